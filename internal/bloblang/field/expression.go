@@ -115,6 +115,28 @@ func (e *Expression) String(index int, msg Message) string {
 	return string(e.Bytes(index, msg))
 }
 
+// TryString returns a string representing the expression resolved for a
+// message of a batch, or an error if any of the interpolation functions of the
+// expression fail or resolve to null.
+func (e *Expression) TryString(index int, msg Message) (string, error) {
+	if len(e.resolvers) == 0 {
+		return e.static, nil
+	}
+	var buf bytes.Buffer
+	for _, r := range e.resolvers {
+		if q, ok := r.(*QueryResolver); ok {
+			b, err := q.tryResolveBytes(index, msg)
+			if err != nil {
+				return "", err
+			}
+			buf.Write(b)
+			continue
+		}
+		buf.Write(r.ResolveBytes(index, msg, false, false))
+	}
+	return buf.String(), nil
+}
+
 // StringLegacy is DEPRECATED - Instructs deprecated functions to disregard
 // index information.
 // TODO V4: Remove this.
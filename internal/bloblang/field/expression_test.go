@@ -467,3 +467,63 @@ func TestLegacyExpressions(t *testing.T) {
 		})
 	}
 }
+
+func TestExpressionTryString(t *testing.T) {
+	metaFn := func(key string) query.Function {
+		fn, err := query.InitFunction("meta", key)
+		require.NoError(t, err)
+		return fn
+	}
+
+	msg := message.New([][]byte{[]byte(`{"foo":"bar"}`)})
+	msg.Get(0).Metadata().Set("host", "example.com")
+
+	tests := map[string]struct {
+		expression *Expression
+		output     string
+		err        string
+	}{
+		"static": {
+			expression: NewExpression(
+				StaticResolver("http://localhost"),
+			),
+			output: "http://localhost",
+		},
+		"resolved": {
+			expression: NewExpression(
+				StaticResolver("http://"),
+				NewQueryResolver(metaFn("host")),
+				StaticResolver("/"),
+				NewQueryResolver(query.NewFieldFunction("foo")),
+			),
+			output: "http://example.com/bar",
+		},
+		"missing metadata": {
+			expression: NewExpression(
+				StaticResolver("http://"),
+				NewQueryResolver(metaFn("nope")),
+			),
+			err: "metadata value 'nope' not found",
+		},
+		"missing field": {
+			expression: NewExpression(
+				StaticResolver("http://localhost/"),
+				NewQueryResolver(query.NewFieldFunction("nope")),
+			),
+			err: "field `this.nope` resolved to null",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			res, err := test.expression.TryString(0, msg)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.output, res)
+		})
+	}
+}
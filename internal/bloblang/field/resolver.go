@@ -1,6 +1,7 @@
 package field
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
@@ -86,6 +87,29 @@ func (q QueryResolver) ResolveBytes(index int, msg Message, escaped, legacy bool
 	return bs
 }
 
+func (q QueryResolver) tryResolveBytes(index int, msg Message) ([]byte, error) {
+	if msg == nil {
+		msg = message.New(nil)
+	}
+	v, err := q.fn.Exec(query.FunctionContext{
+		Index:    index,
+		MsgBatch: msg,
+		NewMsg:   msg.Get(index),
+	}.WithValueFunc(func() *interface{} {
+		if jObj, err := msg.Get(index).JSON(); err == nil {
+			return &jObj
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("%v resolved to null", q.fn.Annotation())
+	}
+	return query.IToBytes(v), nil
+}
+
 func escapeBytes(in []byte) []byte {
 	quoted := strconv.Quote(string(in))
 	if len(quoted) < 3 {
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -306,6 +307,34 @@ var _ = registerSimpleFunction(
 	},
 )
 
+var _ = registerSimpleFunction(
+	NewFunctionSpec(
+		FunctionCategoryMessage, "error_source",
+		"If an error has occurred during the processing of a message this function returns an object describing the processor from which the error originated, with the fields `label`, `path` and `type`, and a field `metadata` containing an object of the metadata that the message carried at the point that it failed. Returns `null` when the message has not failed. For more information about error handling patterns read [here][error_handling].",
+		NewExampleSpec("",
+			`root.doc.failed_stage = error_source().label.or(error_source().path)`,
+		),
+	),
+	func(ctx FunctionContext) (interface{}, error) {
+		meta := ctx.MsgBatch.Get(ctx.Index).Metadata()
+		if len(meta.Get(types.FailFlagKey)) == 0 || len(meta.Get(types.ErrorSourceTypeKey)) == 0 {
+			return nil, nil
+		}
+		metaObj := map[string]interface{}{}
+		if metaStr := meta.Get(types.ErrorSourceMetadataKey); len(metaStr) > 0 {
+			if err := json.Unmarshal([]byte(metaStr), &metaObj); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]interface{}{
+			"label":    meta.Get(types.ErrorSourceLabelKey),
+			"path":     meta.Get(types.ErrorSourcePathKey),
+			"type":     meta.Get(types.ErrorSourceTypeKey),
+			"metadata": metaObj,
+		}, nil
+	},
+)
+
 //------------------------------------------------------------------------------
 
 var _ = RegisterFunction(
@@ -337,7 +366,7 @@ func fileFunction(args ...interface{}) (Function, error) {
 var _ = RegisterFunction(
 	NewFunctionSpec(
 		FunctionCategoryGeneral, "range",
-		"The `range` function creates an array of integers following a range between a start, stop and optional step integer argument. If the step argument is omitted then it defaults to 1. A negative step can be provided as long as stop < start.",
+		"The `range` function creates an array of integers following a range between a start, stop and optional step integer argument. If the step argument is omitted then it defaults to 1. The stop value is exclusive. A negative step can be provided as long as stop < start. Ranges are limited to 1,000,000 elements.",
 		NewExampleSpec("",
 			`root.a = range(0, 10)
 root.b = range(0, this.max, 2)
@@ -353,15 +382,40 @@ root.c = range(0, -this.max, -2)`,
 	ExpectIntArg(2),
 )
 
+// maxGeneratedArrayLen is the largest array that can be created by generator
+// functions such as range and repeat.
+const maxGeneratedArrayLen = 1000000
+
 func rangeFunction(args ...interface{}) (Function, error) {
 	start, stop, step := args[0].(int64), args[1].(int64), int64(1)
 	if len(args) > 2 {
 		step = args[2].(int64)
 	}
+	if step == 0 {
+		return nil, errors.New("step must not be zero")
+	}
 	if step < 0 && stop > start {
 		return nil, fmt.Errorf("with negative step arg stop (%v) must be <= to start (%v)", stop, start)
 	}
-	r := make([]interface{}, (stop-start)/step)
+	if step > 0 && stop < start {
+		return nil, fmt.Errorf("with positive step arg stop (%v) must be >= to start (%v)", stop, start)
+	}
+
+	// The distance between start and stop is calculated unsigned as it can
+	// exceed the bounds of an int64. The stop value is exclusive, and an
+	// uneven step includes the last value before it.
+	dist, absStep := uint64(stop)-uint64(start), uint64(step)
+	if step < 0 {
+		dist, absStep = uint64(start)-uint64(stop), uint64(-step)
+	}
+	n := dist / absStep
+	if dist%absStep != 0 {
+		n++
+	}
+	if n > maxGeneratedArrayLen {
+		return nil, fmt.Errorf("range of %v elements exceeds the limit of %v", n, maxGeneratedArrayLen)
+	}
+	r := make([]interface{}, n)
 	for i := 0; i < len(r); i++ {
 		r[i] = start + step*int64(i)
 	}
@@ -370,6 +424,39 @@ func rangeFunction(args ...interface{}) (Function, error) {
 	}, nil), nil
 }
 
+var _ = RegisterFunction(
+	NewFunctionSpec(
+		FunctionCategoryGeneral, "repeat",
+		"Creates an array containing a value repeated a number of times. Arrays are limited to 1,000,000 elements.",
+		NewExampleSpec("",
+			`root.a = repeat("foo", 3)
+root.b = repeat({"id":0}, this.count).enumerated().map_each(ele -> ele.value.id + ele.index)`,
+			`{"count":3}`,
+			`{"a":["foo","foo","foo"],"b":[0,1,2]}`,
+		),
+	),
+	true, repeatFunction,
+	ExpectNArgs(2),
+	ExpectIntArg(1),
+)
+
+func repeatFunction(args ...interface{}) (Function, error) {
+	value, n := args[0], args[1].(int64)
+	if n < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %v", n)
+	}
+	if n > maxGeneratedArrayLen {
+		return nil, fmt.Errorf("repeat of %v elements exceeds the limit of %v", n, maxGeneratedArrayLen)
+	}
+	return ClosureFunction("function repeat", func(ctx FunctionContext) (interface{}, error) {
+		r := make([]interface{}, n)
+		for i := range r {
+			r[i] = IClone(value)
+		}
+		return r, nil
+	}, nil), nil
+}
+
 //------------------------------------------------------------------------------
 
 var _ = registerSimpleFunction(
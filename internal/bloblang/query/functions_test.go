@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"testing"
 
@@ -111,6 +112,54 @@ func TestFunctions(t *testing.T) {
 				}},
 			},
 		},
+		"check error_source function": {
+			input: mustFunc("error_source"),
+			output: map[string]interface{}{
+				"label": "foo",
+				"path":  "pipeline.processor.0",
+				"type":  "bloblang",
+				"metadata": map[string]interface{}{
+					"kafka_topic": "bar",
+				},
+			},
+			messages: []easyMsg{
+				{content: "", meta: map[string]string{
+					"benthos_processing_failed":     "nope",
+					"benthos_error_source_label":    "foo",
+					"benthos_error_source_path":     "pipeline.processor.0",
+					"benthos_error_source_type":     "bloblang",
+					"benthos_error_source_metadata": `{"kafka_topic":"bar"}`,
+				}},
+			},
+		},
+		"check range function uneven step": {
+			input:  mustFunc("range", int64(0), int64(10), int64(3)),
+			output: []interface{}{int64(0), int64(3), int64(6), int64(9)},
+		},
+		"check range function uneven negative step": {
+			input:  mustFunc("range", int64(0), int64(-10), int64(-3)),
+			output: []interface{}{int64(0), int64(-3), int64(-6), int64(-9)},
+		},
+		"check range function extreme bounds": {
+			input:  mustFunc("range", int64(math.MaxInt64-2), int64(math.MaxInt64), int64(math.MaxInt64)),
+			output: []interface{}{int64(math.MaxInt64 - 2)},
+		},
+		"check repeat function": {
+			input: mustFunc("repeat", map[string]interface{}{"foo": "bar"}, int64(2)),
+			output: []interface{}{
+				map[string]interface{}{"foo": "bar"},
+				map[string]interface{}{"foo": "bar"},
+			},
+		},
+		"check error_source function not failed": {
+			input:  mustFunc("error_source"),
+			output: nil,
+			messages: []easyMsg{
+				{content: "", meta: map[string]string{
+					"benthos_error_source_type": "bloblang",
+				}},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -246,3 +295,55 @@ func TestRandomInt(t *testing.T) {
 		assert.LessOrEqual(t, v, int64(10))
 	}
 }
+
+func TestGeneratorFunctionErrors(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		args []interface{}
+		err  string
+	}{
+		"range zero step": {
+			name: "range",
+			args: []interface{}{int64(0), int64(10), int64(0)},
+			err:  "step must not be zero",
+		},
+		"range positive step backwards": {
+			name: "range",
+			args: []interface{}{int64(10), int64(0)},
+			err:  "with positive step arg stop (0) must be >= to start (10)",
+		},
+		"range too large": {
+			name: "range",
+			args: []interface{}{int64(0), int64(10000000)},
+			err:  "range of 10000000 elements exceeds the limit of 1000000",
+		},
+		"range overflowing distance": {
+			name: "range",
+			args: []interface{}{int64(math.MinInt64), int64(math.MaxInt64)},
+			err:  "range of 18446744073709551615 elements exceeds the limit of 1000000",
+		},
+		"range overflowing negative distance": {
+			name: "range",
+			args: []interface{}{int64(math.MaxInt64), int64(math.MinInt64), int64(-2)},
+			err:  "range of 9223372036854775808 elements exceeds the limit of 1000000",
+		},
+		"repeat negative": {
+			name: "repeat",
+			args: []interface{}{"foo", int64(-1)},
+			err:  "count must not be negative, got -1",
+		},
+		"repeat too large": {
+			name: "repeat",
+			args: []interface{}{"foo", int64(10000000)},
+			err:  "repeat of 10000000 elements exceeds the limit of 1000000",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := InitFunction(test.name, test.args...)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package query
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 
 	"github.com/Jeffail/gabs/v2"
 )
@@ -115,6 +118,188 @@ func boolMethod(target Function, args ...interface{}) (Function, error) {
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"cast", "",
+	).InCategory(
+		MethodCategoryCoercion,
+		`
+Attempts to convert a value into a given type, which can be one of `+"`string`, `bytes`, `number`, `int`, `float` or `bool`"+`. These are the scalar type names returned by `+"[`type`][methods.type]"+`, where `+"`int`"+` and `+"`float`"+` are additionally accepted in order to convert a value into a number that is an integer or a decimal respectively. Values that cannot be converted result in an error, which can be recovered from with `+"[`catch`][methods.catch]"+`. The supported conversions are:
+
+| From | `+"`string`"+` | `+"`bytes`"+` | `+"`number`"+` | `+"`int`"+` | `+"`float`"+` | `+"`bool`"+` |
+|------|------|------|------|------|------|------|
+| string or bytes | As is | As is | Parsed as an integer when possible, otherwise as a decimal number | Parsed as a base 10 integer | Parsed as a decimal number | One of `+"`true`, `false`, `1`, `0`, `t`, `f`, `TRUE`, `FALSE`, `True`, `False`"+` |
+| number | Formatted as a string | Formatted as a string | As is | Must be a whole number | As is | Non-zero is `+"`true`"+` |
+| bool | `+"`true` or `false`"+` | `+"`true` or `false`"+` | `+"`1` or `0`"+` | `+"`1` or `0`"+` | `+"`1` or `0`"+` | As is |
+| array or object | Serialized as JSON | Serialized as JSON | Error | Error | Error | Error |
+| null | Error | Error | Error | Error | Error | Error |
+
+Leading and trailing whitespace is ignored when parsing strings into numbers.`,
+		NewExampleSpec("",
+			`root.a = this.a.cast("int")
+root.b = this.b.cast("bool")
+root.c = this.c.cast("string")`,
+			`{"a":"  42 ","b":"true","c":12.5}`,
+			`{"a":42,"b":true,"c":"12.5"}`,
+		),
+		NewExampleSpec("",
+			`root.a = this.a.cast("int").catch(-1)`,
+			`{"a":"nope"}`,
+			`{"a":-1}`,
+			`{"a":10.5}`,
+			`{"a":-1}`,
+		),
+		NewExampleSpec("The names returned by `type` can be used in order to convert a value into the type of another.",
+			`root.b = this.b.cast(this.a.type())`,
+			`{"a":10,"b":"5.5"}`,
+			`{"b":5.5}`,
+			`{"a":"10","b":5.5}`,
+			`{"b":"5.5"}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		var castFn func(v interface{}) (interface{}, error)
+		switch args[0].(string) {
+		case "string":
+			castFn = castToString
+		case "bytes":
+			castFn = castToBytes
+		case "number":
+			castFn = castToNumber
+		case "int":
+			castFn = castToInt
+		case "float":
+			castFn = castToFloat
+		case "bool":
+			castFn = castToBool
+		default:
+			return nil, fmt.Errorf("unrecognised cast type: %v", args[0])
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			return castFn(v)
+		}, nil
+	},
+	true,
+	ExpectNArgs(1),
+	ExpectStringArg(0),
+)
+
+func castToString(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, errors.New("cannot cast null to string")
+	}
+	return IToString(v), nil
+}
+
+func castToBytes(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, errors.New("cannot cast null to bytes")
+	}
+	return IToBytes(v), nil
+}
+
+func castToNumber(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		t = strings.TrimSpace(t)
+		if i, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot cast string to number: %w", err)
+		}
+		return f, nil
+	case []byte:
+		return castToNumber(string(t))
+	case bool:
+		if t {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case int64, uint64, float64:
+		return t, nil
+	case json.Number:
+		return castToNumber(t.String())
+	}
+	return nil, fmt.Errorf("cannot cast %v to number", ITypeOf(v))
+}
+
+func castToInt(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(t), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot cast string to int: %w", err)
+		}
+		return i, nil
+	case []byte:
+		return castToInt(string(t))
+	case bool:
+		if t {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case int64:
+		return t, nil
+	case uint64:
+		if t > maxInt {
+			return nil, fmt.Errorf("cannot cast number to int: %v is too large", t)
+		}
+		return int64(t), nil
+	case float64:
+		if t != math.Trunc(t) || t >= math.MaxInt64 || t < math.MinInt64 {
+			return nil, fmt.Errorf("cannot cast number to int: %v is not a whole number within range", t)
+		}
+		return int64(t), nil
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("cannot cast number to int: %w", err)
+		}
+		return castToInt(f)
+	}
+	return nil, fmt.Errorf("cannot cast %v to int", ITypeOf(v))
+}
+
+func castToFloat(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot cast string to float: %w", err)
+		}
+		return f, nil
+	case []byte:
+		return castToFloat(string(t))
+	case bool:
+		if t {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	case int64, uint64, float64, json.Number:
+		return IToNumber(t)
+	}
+	return nil, fmt.Errorf("cannot cast %v to float", ITypeOf(v))
+}
+
+func castToBool(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string, []byte, bool, int64, uint64, float64, json.Number:
+		b, err := IToBool(t)
+		if err != nil {
+			return nil, fmt.Errorf("cannot cast %v to bool: %q is not a recognised boolean", ITypeOf(v), IToString(t))
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("cannot cast %v to bool", ITypeOf(v))
+}
+
+//------------------------------------------------------------------------------
+
 var _ = registerMethod(
 	NewMethodSpec(
 		"catch",
@@ -484,7 +669,7 @@ var _ = registerSimpleMethod(
 		"type", "",
 	).InCategory(
 		MethodCategoryCoercion,
-		"Returns the type of a value as a string, providing one of the following values: `string`, `bytes`, `number`, `bool`, `array`, `object` or `null`.",
+		"Returns the type of a value as a string, providing one of the following values: `string`, `bytes`, `number`, `bool`, `array`, `object` or `null`. These are the canonical type names of Bloblang, which are used within error messages and can be given to [`cast`][methods.cast] in order to convert a value into any of the scalar types. Integers and decimals are both of the type `number`.",
 		NewExampleSpec("",
 			`root.bar_type = this.bar.type()
 root.foo_type = this.foo.type()`,
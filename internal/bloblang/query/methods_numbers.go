@@ -1,9 +1,14 @@
 package query
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"net"
+	"strconv"
+	"strings"
 )
 
 //------------------------------------------------------------------------------
@@ -62,6 +67,40 @@ var _ = registerSimpleMethod(
 	ExpectNArgs(0),
 )
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"clamp", "Limits a number to within a range described by a minimum and a maximum bound. If the minimum is greater than the maximum then the bounds are swapped.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.new_value = this.value.clamp(0, 100)`,
+			`{"value":120}`,
+			`{"new_value":100}`,
+			`{"value":-5.5}`,
+			`{"new_value":0}`,
+			`{"value":42.5}`,
+			`{"new_value":42.5}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		lower, upper := args[0].(float64), args[1].(float64)
+		if lower > upper {
+			lower, upper = upper, lower
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			f, err := IGetNumber(v)
+			if err != nil {
+				return nil, err
+			}
+			return math.Max(lower, math.Min(upper, f)), nil
+		}, nil
+	},
+	true,
+	ExpectNArgs(2),
+	ExpectFloatArg(0),
+	ExpectFloatArg(1),
+)
+
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"floor", "Returns the greatest integer value less than or equal to the target number.",
@@ -89,6 +128,124 @@ var _ = registerSimpleMethod(
 	ExpectNArgs(0),
 )
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"format_number", "Formats a number as a string with a fixed number of decimal places and its integer digits grouped in thousands. The separators default to `,` for thousands and `.` for decimals, and can be overridden with the second and third arguments. Integers are formatted without any loss of precision.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.new_value = this.value.format_number(2)`,
+			`{"value":1234567.5}`,
+			`{"new_value":"1,234,567.50"}`,
+			`{"value":-987}`,
+			`{"new_value":"-987.00"}`,
+		),
+		NewExampleSpec("",
+			`root.new_value = this.value.format_number(1, ".", ",")`,
+			`{"value":1234567.89}`,
+			`{"new_value":"1.234.567,9"}`,
+		),
+		NewExampleSpec("",
+			`root.new_value = this.value.format_number(0, " ")`,
+			`{"value":9007199254740993}`,
+			`{"new_value":"9 007 199 254 740 993"}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		decimals := args[0].(int64)
+		if decimals < 0 {
+			return nil, fmt.Errorf("number of decimals must not be negative, got %v", decimals)
+		}
+		thousandsSep, decimalSep := ",", "."
+		if len(args) > 1 {
+			thousandsSep = args[1].(string)
+		}
+		if len(args) > 2 {
+			decimalSep = args[2].(string)
+		}
+		return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
+			var digits string
+			if f != nil {
+				digits = strconv.FormatFloat(*f, 'f', int(decimals), 64)
+			} else {
+				if i != nil {
+					digits = strconv.FormatInt(*i, 10)
+				} else {
+					digits = strconv.FormatUint(*ui, 10)
+				}
+				if decimals > 0 {
+					digits += "." + strings.Repeat("0", int(decimals))
+				}
+			}
+			return formatNumberDigits(digits, thousandsSep, decimalSep), nil
+		}), nil
+	},
+	true,
+	ExpectBetweenNAndMArgs(1, 3),
+	ExpectIntArg(0),
+	ExpectStringArg(1),
+	ExpectStringArg(2),
+)
+
+// formatNumberDigits groups the integer digits of a formatted decimal number in
+// thousands and replaces its decimal point.
+func formatNumberDigits(digits, thousandsSep, decimalSep string) string {
+	var sign, fraction string
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		digits, fraction = digits[:i], decimalSep+digits[i+1:]
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(thousandsSep)
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString(fraction)
+	return b.String()
+}
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"lerp", "Linearly interpolates between two numbers `a` and `b` using the target number as the interpolant, where a target of `0` results in `a` and a target of `1` results in `b`. Targets outside of the range `0` to `1` extrapolate beyond `a` and `b`, and can be limited with the method [`clamp`](#clamp).",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.new_value = this.value.lerp(10, 20)`,
+			`{"value":0.25}`,
+			`{"new_value":12.5}`,
+			`{"value":1.5}`,
+			`{"new_value":25}`,
+		),
+		NewExampleSpec("",
+			`root.new_value = this.value.clamp(0, 1).lerp(20, 10)`,
+			`{"value":0.25}`,
+			`{"new_value":17.5}`,
+			`{"value":-1}`,
+			`{"new_value":20}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		a, b := args[0].(float64), args[1].(float64)
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			t, err := IGetNumber(v)
+			if err != nil {
+				return nil, err
+			}
+			return a + (b-a)*t, nil
+		}, nil
+	},
+	true,
+	ExpectNArgs(2),
+	ExpectFloatArg(0),
+	ExpectFloatArg(1),
+)
+
 var _ = registerSimpleMethod(
 	NewMethodSpec("log", "Returns the natural logarithm of a number.").InCategory(
 		MethodCategoryNumbers, "",
@@ -145,6 +302,48 @@ var _ = registerSimpleMethod(
 	ExpectNArgs(0),
 )
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"map_range", "Maps a number from an input range to an output range, described by the arguments `in_min`, `in_max`, `out_min` and `out_max`. Numbers outside of the input range are extrapolated beyond the output range, and can be limited with the method [`clamp`](#clamp). Either range can be reversed in order to invert the mapping, but the bounds of the input range must not be equal.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.celsius = this.fahrenheit.map_range(32, 212, 0, 100)`,
+			`{"fahrenheit":212}`,
+			`{"celsius":100}`,
+			`{"fahrenheit":-40}`,
+			`{"celsius":-40}`,
+		),
+		NewExampleSpec("",
+			`root.percent = this.reading.clamp(0, 1023).map_range(0, 1023, 100, 0)`,
+			`{"reading":0}`,
+			`{"percent":100}`,
+			`{"reading":2000}`,
+			`{"percent":0}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		inMin, inMax := args[0].(float64), args[1].(float64)
+		outMin, outMax := args[2].(float64), args[3].(float64)
+		if inMin == inMax {
+			return nil, fmt.Errorf("input range bounds must not be equal, got %v", inMin)
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			f, err := IGetNumber(v)
+			if err != nil {
+				return nil, err
+			}
+			return outMin + (f-inMin)*(outMax-outMin)/(inMax-inMin), nil
+		}, nil
+	},
+	true,
+	ExpectNArgs(4),
+	ExpectFloatArg(0),
+	ExpectFloatArg(1),
+	ExpectFloatArg(2),
+	ExpectFloatArg(3),
+)
+
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"max",
@@ -263,3 +462,208 @@ var _ = registerSimpleMethod(
 	false,
 	ExpectNArgs(0),
 )
+
+//------------------------------------------------------------------------------
+
+// iGetWholeInt takes a boxed value and attempts to extract a 64-bit integer
+// from it, returning an error if the value is a number with a fractional part.
+func iGetWholeInt(v interface{}) (int64, error) {
+	switch t := v.(type) {
+	case int64:
+		return t, nil
+	case uint64:
+		return int64(t), nil
+	case float64:
+		if t != math.Trunc(t) || t > math.MaxInt64 || t < math.MinInt64 {
+			return 0, fmt.Errorf("expected integer value, got %v", t)
+		}
+		return int64(t), nil
+	case json.Number:
+		i, err := t.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("expected integer value, got %v", t)
+		}
+		return i, nil
+	}
+	return 0, NewTypeError(v, ValueNumber)
+}
+
+func bitwiseMethod(fn func(v int64) (interface{}, error)) simpleMethod {
+	return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+		i, err := iGetWholeInt(v)
+		if err != nil {
+			return nil, err
+		}
+		return fn(i)
+	}
+}
+
+func bitwiseArgMethod(fn func(v, arg int64) int64) simpleMethodConstructor {
+	return func(args ...interface{}) (simpleMethod, error) {
+		arg := args[0].(int64)
+		return bitwiseMethod(func(v int64) (interface{}, error) {
+			return fn(v, arg), nil
+		}), nil
+	}
+}
+
+func shiftMethod(fn func(v int64, n uint) int64) simpleMethodConstructor {
+	return func(args ...interface{}) (simpleMethod, error) {
+		n := args[0].(int64)
+		if n < 0 {
+			return nil, fmt.Errorf("shift count must not be negative, got %v", n)
+		}
+		return bitwiseMethod(func(v int64) (interface{}, error) {
+			return fn(v, uint(n)), nil
+		}), nil
+	}
+}
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"bit_and", "Returns the bitwise AND of an integer and an integer argument. Both values are treated as 64-bit signed integers and an error is returned if either is not a whole number.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.low_nibble = this.value.bit_and(15)`,
+			`{"value":171}`,
+			`{"low_nibble":11}`,
+		),
+	),
+	bitwiseArgMethod(func(v, arg int64) int64 {
+		return v & arg
+	}),
+	true,
+	ExpectNArgs(1),
+	ExpectIntArg(0),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"bit_or", "Returns the bitwise OR of an integer and an integer argument. Both values are treated as 64-bit signed integers and an error is returned if either is not a whole number.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.flags = this.flags.bit_or(4)`,
+			`{"flags":3}`,
+			`{"flags":7}`,
+		),
+	),
+	bitwiseArgMethod(func(v, arg int64) int64 {
+		return v | arg
+	}),
+	true,
+	ExpectNArgs(1),
+	ExpectIntArg(0),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"bit_xor", "Returns the bitwise exclusive OR of an integer and an integer argument. Both values are treated as 64-bit signed integers and an error is returned if either is not a whole number.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.toggled = this.flags.bit_xor(5)`,
+			`{"flags":6}`,
+			`{"toggled":3}`,
+		),
+	),
+	bitwiseArgMethod(func(v, arg int64) int64 {
+		return v ^ arg
+	}),
+	true,
+	ExpectNArgs(1),
+	ExpectIntArg(0),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"bit_not", "Returns the bitwise complement of an integer, which is treated as a 64-bit signed integer. An error is returned if the value is not a whole number.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.new_value = this.value.bit_not()`,
+			`{"value":0}`,
+			`{"new_value":-1}`,
+			`{"value":5}`,
+			`{"new_value":-6}`,
+		),
+	),
+	func(...interface{}) (simpleMethod, error) {
+		return bitwiseMethod(func(v int64) (interface{}, error) {
+			return ^v, nil
+		}), nil
+	},
+	false,
+	ExpectNArgs(0),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"shift_left", "Shifts the bits of an integer left by a number of positions, filling with zeros. The value is treated as a 64-bit signed integer, bits shifted beyond the 64th position are discarded, and an error is returned if the value is not a whole number or the shift count is negative.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.new_value = this.value.shift_left(4)`,
+			`{"value":3}`,
+			`{"new_value":48}`,
+		),
+	),
+	shiftMethod(func(v int64, n uint) int64 {
+		return v << n
+	}),
+	true,
+	ExpectNArgs(1),
+	ExpectIntArg(0),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"shift_right", "Shifts the bits of an integer right by a number of positions. The value is treated as a 64-bit signed integer and the shift is arithmetic, meaning the sign bit is preserved for negative values. An error is returned if the value is not a whole number or the shift count is negative.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("Extract bit-packed fields by combining shifts with a mask:",
+			`root.version = this.header.shift_right(4)
+root.flags = this.header.bit_and(15)`,
+			`{"header":86}`,
+			`{"flags":6,"version":5}`,
+		),
+		NewExampleSpec("",
+			`root.new_value = this.value.shift_right(1)`,
+			`{"value":-8}`,
+			`{"new_value":-4}`,
+		),
+	),
+	shiftMethod(func(v int64, n uint) int64 {
+		return v >> n
+	}),
+	true,
+	ExpectNArgs(1),
+	ExpectIntArg(0),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"int_to_ip", "",
+	).InCategory(
+		MethodCategoryNumbers,
+		"Converts an integer into an IPv4 address string. This is the inverse of the method `ip_to_int`. An error is returned if the value is not a whole number between 0 and 4294967295.",
+		NewExampleSpec("",
+			`root.ip = this.value.int_to_ip()`,
+			`{"value":3232235777}`,
+			`{"ip":"192.168.1.1"}`,
+		),
+	),
+	func(...interface{}) (simpleMethod, error) {
+		return bitwiseMethod(func(v int64) (interface{}, error) {
+			if v < 0 || v > math.MaxUint32 {
+				return nil, fmt.Errorf("value %v is out of range for an IPv4 address", v)
+			}
+			ip := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(ip, uint32(v))
+			return ip.String(), nil
+		}), nil
+	},
+	true,
+	ExpectNArgs(0),
+)
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/google/go-cmp/cmp"
 	jsonschema "github.com/xeipuuv/gojsonschema"
 )
 
@@ -243,6 +245,135 @@ var _ = registerSimpleMethod(
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"delete_path", "",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		`Returns a copy of a structure with the value at a path removed. The path can either be an array of keys and indexes, which allows keys that contain dots and paths built dynamically, or a [dot path][field_paths] string. Indexes identify elements of arrays, where negative indexes count backwards from the end of the array.
+
+If the path does not exist within the structure then it is returned unchanged.`,
+		NewExampleSpec("",
+			`root = this.delete_path(["foo","bar.baz"])`,
+			`{"foo":{"bar.baz":"first","buz":"second"}}`,
+			`{"foo":{"buz":"second"}}`,
+		),
+		NewExampleSpec("",
+			`root = this.delete_path("things.-1")`,
+			`{"things":["a","b","c"]}`,
+			`{"things":["a","b"]}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		path, err := getStructuredPathArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			return deletePath(v, path), nil
+		}, nil
+	},
+	true,
+	ExpectNArgs(1),
+)
+
+// getStructuredPathArg converts either an array of keys and indexes or a dot
+// path string into a slice of path segments.
+func getStructuredPathArg(arg interface{}) ([]interface{}, error) {
+	switch t := arg.(type) {
+	case string:
+		var path []interface{}
+		for _, seg := range gabs.DotPathToSlice(t) {
+			path = append(path, seg)
+		}
+		return path, nil
+	case []interface{}:
+		path := make([]interface{}, len(t))
+		for i, seg := range t {
+			switch segT := seg.(type) {
+			case string:
+				path[i] = segT
+			default:
+				index, err := IGetInt(seg)
+				if err != nil {
+					return nil, fmt.Errorf("path segment %v: expected string or number, got %v", i, ITypeOf(seg))
+				}
+				path[i] = index
+			}
+		}
+		return path, nil
+	}
+	return nil, fmt.Errorf("expected path argument to be an array or string, got %v", ITypeOf(arg))
+}
+
+// getPathIndex resolves a path segment into an index of an array, where
+// negative indexes count backwards from the end of the array.
+func getPathIndex(seg interface{}, arr []interface{}) (int, bool) {
+	var index int64
+	switch t := seg.(type) {
+	case int64:
+		index = t
+	case string:
+		var err error
+		if index, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return 0, false
+		}
+	}
+	if index < 0 {
+		index = int64(len(arr)) + index
+	}
+	if index < 0 || index >= int64(len(arr)) {
+		return 0, false
+	}
+	return int(index), true
+}
+
+// pathKey returns a path segment as an object key.
+func pathKey(seg interface{}) string {
+	if key, ok := seg.(string); ok {
+		return key
+	}
+	return strconv.FormatInt(seg.(int64), 10)
+}
+
+func deletePath(v interface{}, path []interface{}) interface{} {
+	if len(path) == 0 {
+		return v
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		key := pathKey(path[0])
+		child, exists := t[key]
+		if !exists {
+			return v
+		}
+		newMap := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			newMap[k] = v
+		}
+		if len(path) == 1 {
+			delete(newMap, key)
+		} else {
+			newMap[key] = deletePath(child, path[1:])
+		}
+		return newMap
+	case []interface{}:
+		index, ok := getPathIndex(path[0], t)
+		if !ok {
+			return v
+		}
+		newArray := make([]interface{}, 0, len(t))
+		newArray = append(newArray, t[:index]...)
+		if len(path) > 1 {
+			newArray = append(newArray, deletePath(t[index], path[1:]))
+		}
+		return append(newArray, t[index+1:]...)
+	}
+	return v
+}
+
+//------------------------------------------------------------------------------
+
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"enumerated",
@@ -1004,6 +1135,86 @@ var _ = registerSimpleMethod(
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"set_path", "",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		`Returns a copy of a structure with a value set at a path, creating objects for any intermediate keys that do not exist. The path can either be an array of keys and indexes, which allows keys that contain dots and paths built dynamically, or a [dot path][field_paths] string. Indexes identify existing elements of arrays, where negative indexes count backwards from the end of the array.
+
+An error is returned if the path traverses a value that is neither an object nor an array, or identifies an array element that does not exist.`,
+		NewExampleSpec("",
+			`root = this.set_path(["foo","bar","baz"], "new")`,
+			`{"foo":{"buz":"old"}}`,
+			`{"foo":{"bar":{"baz":"new"},"buz":"old"}}`,
+		),
+		NewExampleSpec("Paths can be built dynamically, and can identify array elements:",
+			`let path = ["things", this.index, "name"]
+root = this.without("index").set_path($path, "updated")`,
+			`{"index":1,"things":[{"name":"a"},{"name":"b"}]}`,
+			`{"things":[{"name":"a"},{"name":"updated"}]}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		path, err := getStructuredPathArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		value := args[1]
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			return setPath(v, path, value)
+		}, nil
+	},
+	true,
+	ExpectNArgs(2),
+)
+
+func setPath(v interface{}, path []interface{}, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	switch t := v.(type) {
+	case nil:
+		key, ok := path[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("index %v does not exist", path[0])
+		}
+		child, err := setPath(nil, path[1:], value)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", key, err)
+		}
+		return map[string]interface{}{key: child}, nil
+	case map[string]interface{}:
+		key := pathKey(path[0])
+		child, err := setPath(t[key], path[1:], value)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", key, err)
+		}
+		newMap := make(map[string]interface{}, len(t)+1)
+		for k, v := range t {
+			newMap[k] = v
+		}
+		newMap[key] = child
+		return newMap, nil
+	case []interface{}:
+		index, ok := getPathIndex(path[0], t)
+		if !ok {
+			return nil, fmt.Errorf("index %v does not exist", path[0])
+		}
+		child, err := setPath(t[index], path[1:], value)
+		if err != nil {
+			return nil, fmt.Errorf("index %v: %w", index, err)
+		}
+		newArray := make([]interface{}, len(t))
+		copy(newArray, t)
+		newArray[index] = child
+		return newArray, nil
+	}
+	return nil, fmt.Errorf("expected object or array, got %v", ITypeOf(v))
+}
+
+//------------------------------------------------------------------------------
+
 var _ = registerMethod(
 	NewMethodSpec(
 		"sort", "",
@@ -1349,12 +1560,17 @@ var _ = registerSimpleMethod(
 		"unique", "",
 	).InCategory(
 		MethodCategoryObjectAndArray,
-		"Attempts to remove duplicate values from an array. The array may contain a combination of different value types, but numbers and strings are checked separately (`\"5\"` is a different element to `5`).",
+		"Attempts to remove duplicate values from an array, preserving the order of first occurrences. The array may contain a combination of different value types, but numbers and strings are checked separately (`\"5\"` is a different element to `5`). Objects and arrays are compared by deep equality. An optional query argument can be provided in order to check for uniqueness by a key derived from each element.",
 		NewExampleSpec("",
 			`root.uniques = this.foo.unique()`,
 			`{"foo":["a","b","a","c"]}`,
 			`{"uniques":["a","b","c"]}`,
 		),
+		NewExampleSpec("",
+			`root.uniques = this.foo.unique()`,
+			`{"foo":[{"id":1},{"id":2},{"id":1}]}`,
+			`{"uniques":[{"id":1},{"id":2}]}`,
+		),
 	),
 	uniqueMethod,
 	false,
@@ -1362,6 +1578,24 @@ var _ = registerSimpleMethod(
 	ExpectFunctionArg(0),
 )
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"unique_by", "",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		"Attempts to remove elements from an array that share a key, preserving the order of first occurrences. The key of each element is derived by executing a query argument with the element as its context, and keys are compared following the same rules as the [`unique`](#unique) method.",
+		NewExampleSpec("",
+			`root.uniques = this.foo.unique_by(i -> i.id)`,
+			`{"foo":[{"id":"a","v":1},{"id":"b","v":2},{"id":"a","v":3}]}`,
+			`{"uniques":[{"id":"a","v":1},{"id":"b","v":2}]}`,
+		),
+	),
+	uniqueMethod,
+	false,
+	ExpectNArgs(1),
+	ExpectFunctionArg(0),
+)
+
 func uniqueMethod(args ...interface{}) (simpleMethod, error) {
 	var emitFn Function
 	if len(args) > 0 {
@@ -1403,6 +1637,18 @@ func uniqueMethod(args ...interface{}) (simpleMethod, error) {
 			return !exists
 		}
 
+		var otherCompares []interface{}
+
+		checkOther := func(other interface{}) bool {
+			for _, o := range otherCompares {
+				if cmp.Equal(o, other) {
+					return false
+				}
+			}
+			otherCompares = append(otherCompares, other)
+			return true
+		}
+
 		uniqueSlice := make([]interface{}, 0, len(slice))
 		for i, v := range slice {
 			check := v
@@ -1437,7 +1683,7 @@ func uniqueMethod(args ...interface{}) (simpleMethod, error) {
 			case float64:
 				unique = checkNum(float64(t))
 			default:
-				return nil, fmt.Errorf("index %v: %w", i, NewTypeError(check, ValueString, ValueNumber))
+				unique = checkOther(t)
 			}
 			if unique {
 				uniqueSlice = append(uniqueSlice, v)
@@ -1542,3 +1788,217 @@ func mapWithout(m map[string]interface{}, paths [][]string) map[string]interface
 }
 
 //------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"zip", "",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		"Combines an array with another array of the same length, returning an array of element pairs where each pair is an array of the form `[left, right]`. By default an error is returned when the arrays are of different lengths, a second boolean argument can be set to `true` in order to truncate the result to the length of the shorter array instead.",
+		NewExampleSpec("",
+			`root.pairs = this.keys.zip(this.values)`,
+			`{"keys":["foo","bar"],"values":[1,2]}`,
+			`{"pairs":[["foo",1],["bar",2]]}`,
+		),
+		NewExampleSpec("",
+			`root.pairs = this.keys.zip(this.values, true)`,
+			`{"keys":["foo","bar","baz"],"values":[1,2]}`,
+			`{"pairs":[["foo",1],["bar",2]]}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			left, right, err := zipArrays(v, args...)
+			if err != nil {
+				return nil, err
+			}
+			pairs := make([]interface{}, len(left))
+			for i := range left {
+				pairs[i] = []interface{}{left[i], right[i]}
+			}
+			return pairs, nil
+		}, nil
+	},
+	true,
+	ExpectBetweenNAndMArgs(1, 2),
+	ExpectBoolArg(1),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"zip_to_object", "",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		"Combines an array of string keys with an array of values of the same length, returning an object where each key is assigned the value at the same index. By default an error is returned when the arrays are of different lengths, a second boolean argument can be set to `true` in order to truncate the result to the length of the shorter array instead.",
+		NewExampleSpec("",
+			`root = this.keys.zip_to_object(this.values)`,
+			`{"keys":["foo","bar"],"values":[1,2]}`,
+			`{"bar":2,"foo":1}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			keys, values, err := zipArrays(v, args...)
+			if err != nil {
+				return nil, err
+			}
+			obj := make(map[string]interface{}, len(keys))
+			for i, k := range keys {
+				kStr, err := IGetString(k)
+				if err != nil {
+					return nil, fmt.Errorf("key at index %v: %w", i, err)
+				}
+				obj[kStr] = values[i]
+			}
+			return obj, nil
+		}, nil
+	},
+	true,
+	ExpectBetweenNAndMArgs(1, 2),
+	ExpectBoolArg(1),
+)
+
+func zipArrays(v interface{}, args ...interface{}) (left, right []interface{}, err error) {
+	var ok bool
+	if left, ok = v.([]interface{}); !ok {
+		return nil, nil, NewTypeError(v, ValueArray)
+	}
+	if right, ok = args[0].([]interface{}); !ok {
+		return nil, nil, NewTypeError(args[0], ValueArray)
+	}
+	truncate := false
+	if len(args) > 1 {
+		truncate = args[1].(bool)
+	}
+	if len(left) != len(right) {
+		if !truncate {
+			return nil, nil, fmt.Errorf("mismatched array lengths: %v != %v", len(left), len(right))
+		}
+		if len(left) > len(right) {
+			left = left[:len(right)]
+		} else {
+			right = right[:len(left)]
+		}
+	}
+	return left, right, nil
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"pivot", "",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		"Converts an array of objects, each containing a key field and a value field, into a single object where each key is assigned its value. The first argument names the key field and the second names the value field, elements with a null or missing value field are assigned null, and an error is returned when an element does not contain a string key field. By default duplicate keys also result in an error, an optional third argument can be set to `first` or `last` in order to keep the first or last value of a duplicated key, or `array` in order to collect the values of each key into an array.",
+		NewExampleSpec("",
+			`root = this.rows.pivot("field", "value")`,
+			`{"rows":[{"field":"name","value":"foo"},{"field":"age","value":10}]}`,
+			`{"age":10,"name":"foo"}`,
+		),
+		NewExampleSpec("",
+			`root = this.rows.pivot("field", "value", "array")`,
+			`{"rows":[{"field":"tag","value":"foo"},{"field":"tag","value":"bar"},{"field":"name"}]}`,
+			`{"name":[null],"tag":["foo","bar"]}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		keyField, valueField := args[0].(string), args[1].(string)
+		duplicates := "error"
+		if len(args) > 2 {
+			duplicates = args[2].(string)
+		}
+		switch duplicates {
+		case "error", "first", "last", "array":
+		default:
+			return nil, fmt.Errorf("unrecognised duplicates strategy: %v", duplicates)
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			rows, ok := v.([]interface{})
+			if !ok {
+				return nil, NewTypeError(v, ValueArray)
+			}
+			obj := make(map[string]interface{}, len(rows))
+			for i, row := range rows {
+				rowObj, ok := row.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("element at index %v: %w", i, NewTypeError(row, ValueObject))
+				}
+				key, err := IGetString(rowObj[keyField])
+				if err != nil {
+					return nil, fmt.Errorf("element at index %v: key field %v: %w", i, keyField, err)
+				}
+				value := rowObj[valueField]
+				if duplicates == "array" {
+					existing, _ := obj[key].([]interface{})
+					obj[key] = append(existing, value)
+					continue
+				}
+				if _, exists := obj[key]; exists {
+					switch duplicates {
+					case "error":
+						return nil, fmt.Errorf("element at index %v: duplicate key: %v", i, key)
+					case "first":
+						continue
+					}
+				}
+				obj[key] = value
+			}
+			return obj, nil
+		}, nil
+	},
+	true,
+	ExpectBetweenNAndMArgs(2, 3),
+	ExpectStringArg(0),
+	ExpectStringArg(1),
+	ExpectStringArg(2),
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"unpivot", "",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		"Converts an object into an array of objects, one for each key of the object sorted by key, where each element contains the key and its value. The names of the key and value fields of each element are `key` and `value` by default, and can be changed with the first and second arguments respectively. This is the inverse of the `pivot` method.",
+		NewExampleSpec("",
+			`root.rows = this.unpivot("field", "value")`,
+			`{"name":"foo","age":10}`,
+			`{"rows":[{"field":"age","value":10},{"field":"name","value":"foo"}]}`,
+		),
+	),
+	func(args ...interface{}) (simpleMethod, error) {
+		keyField, valueField := "key", "value"
+		if len(args) > 0 {
+			keyField = args[0].(string)
+		}
+		if len(args) > 1 {
+			valueField = args[1].(string)
+		}
+		if keyField == valueField {
+			return nil, fmt.Errorf("key and value fields must differ, both are %v", keyField)
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, NewTypeError(v, ValueObject)
+			}
+			keys := make([]string, 0, len(obj))
+			for k := range obj {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			rows := make([]interface{}, len(keys))
+			for i, k := range keys {
+				rows[i] = map[string]interface{}{
+					keyField:   k,
+					valueField: obj[k],
+				}
+			}
+			return rows, nil
+		}, nil
+	},
+	true,
+	ExpectBetweenNAndMArgs(0, 2),
+	ExpectStringArg(0),
+	ExpectStringArg(1),
+)
@@ -45,6 +45,28 @@ func TestMethodImmutability(t *testing.T) {
 				"baz": "buz",
 			},
 		},
+		{
+			name:   "set path",
+			method: "set_path",
+			target: map[string]interface{}{
+				"foo": []interface{}{map[string]interface{}{"bar": "baz"}},
+			},
+			args: []interface{}{"foo.0.bar", "buz"},
+			exp: map[string]interface{}{
+				"foo": []interface{}{map[string]interface{}{"bar": "buz"}},
+			},
+		},
+		{
+			name:   "delete path",
+			method: "delete_path",
+			target: map[string]interface{}{
+				"foo": []interface{}{map[string]interface{}{"bar": "baz"}},
+			},
+			args: []interface{}{"foo.0.bar"},
+			exp: map[string]interface{}{
+				"foo": []interface{}{map[string]interface{}{}},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -164,6 +164,186 @@ func TestMethods(t *testing.T) {
 			),
 			err: "object literal: expected array or object value at path 'foo', found: null",
 		},
+		"check zip": {
+			input: methods(
+				jsonFn(`["a","b"]`),
+				method("zip", []interface{}{"c", "d"}),
+			),
+			output: []interface{}{
+				[]interface{}{"a", "c"},
+				[]interface{}{"b", "d"},
+			},
+		},
+		"check zip mismatched": {
+			input: methods(
+				jsonFn(`["a","b","c"]`),
+				method("zip", []interface{}{"d"}),
+			),
+			err: "array literal: mismatched array lengths: 3 != 1",
+		},
+		"check zip truncated": {
+			input: methods(
+				jsonFn(`["a"]`),
+				method("zip", []interface{}{"c", "d"}, true),
+			),
+			output: []interface{}{
+				[]interface{}{"a", "c"},
+			},
+		},
+		"check zip_to_object": {
+			input: methods(
+				jsonFn(`["a","b","c"]`),
+				method("zip_to_object", []interface{}{"d", int64(5)}, true),
+			),
+			output: map[string]interface{}{
+				"a": "d",
+				"b": int64(5),
+			},
+		},
+		"check zip_to_object bad key": {
+			input: methods(
+				jsonFn(`["a",{}]`),
+				method("zip_to_object", []interface{}{"d", "e"}),
+			),
+			err: "array literal: key at index 1: expected string value, got object",
+		},
+		"check pivot": {
+			input: methods(
+				jsonFn(`[{"f":"a","v":1},{"f":"b"},{"f":"c","v":"3"}]`),
+				method("pivot", "f", "v"),
+			),
+			output: map[string]interface{}{
+				"a": float64(1),
+				"b": nil,
+				"c": "3",
+			},
+		},
+		"check pivot duplicate error": {
+			input: methods(
+				jsonFn(`[{"f":"a","v":1},{"f":"a","v":2}]`),
+				method("pivot", "f", "v"),
+			),
+			err: "array literal: element at index 1: duplicate key: a",
+		},
+		"check pivot duplicate first": {
+			input: methods(
+				jsonFn(`[{"f":"a","v":1},{"f":"a","v":2}]`),
+				method("pivot", "f", "v", "first"),
+			),
+			output: map[string]interface{}{"a": float64(1)},
+		},
+		"check pivot duplicate last": {
+			input: methods(
+				jsonFn(`[{"f":"a","v":1},{"f":"a","v":2}]`),
+				method("pivot", "f", "v", "last"),
+			),
+			output: map[string]interface{}{"a": float64(2)},
+		},
+		"check pivot duplicate array": {
+			input: methods(
+				jsonFn(`[{"f":"a","v":1},{"f":"b","v":3},{"f":"a","v":2}]`),
+				method("pivot", "f", "v", "array"),
+			),
+			output: map[string]interface{}{
+				"a": []interface{}{float64(1), float64(2)},
+				"b": []interface{}{float64(3)},
+			},
+		},
+		"check pivot missing key": {
+			input: methods(
+				jsonFn(`[{"f":"a","v":1},{"v":2}]`),
+				method("pivot", "f", "v"),
+			),
+			err: "array literal: element at index 1: key field f: expected string value, got null",
+		},
+		"check pivot not object": {
+			input: methods(
+				jsonFn(`[{"f":"a","v":1},"nope"]`),
+				method("pivot", "f", "v"),
+			),
+			err: "array literal: element at index 1: expected object value, got string (\"nope\")",
+		},
+		"check unpivot": {
+			input: methods(
+				jsonFn(`{"b":2,"a":1}`),
+				method("unpivot"),
+			),
+			output: []interface{}{
+				map[string]interface{}{"key": "a", "value": float64(1)},
+				map[string]interface{}{"key": "b", "value": float64(2)},
+			},
+		},
+		"check unpivot pivot": {
+			input: methods(
+				jsonFn(`{"b":2,"a":null}`),
+				method("unpivot", "f", "v"),
+				method("pivot", "f", "v"),
+			),
+			output: map[string]interface{}{
+				"a": nil,
+				"b": float64(2),
+			},
+		},
+		"check set path nested array": {
+			input: methods(
+				jsonFn(`{"a":[{"b":1},{"b":2}]}`),
+				method("set_path", []interface{}{"a", int64(-1), "c", "d"}, "new"),
+			),
+			output: map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"b": float64(1)},
+					map[string]interface{}{"b": float64(2), "c": map[string]interface{}{"d": "new"}},
+				},
+			},
+		},
+		"check set path dot path": {
+			input: methods(
+				jsonFn(`{"a":["x","y"]}`),
+				method("set_path", "a.0", "z"),
+			),
+			output: map[string]interface{}{"a": []interface{}{"z", "y"}},
+		},
+		"check set path bad index": {
+			input: methods(
+				jsonFn(`{"a":["x","y"]}`),
+				method("set_path", []interface{}{"a", int64(2)}, "z"),
+			),
+			err: "object literal: field a: index 2 does not exist",
+		},
+		"check set path not structured": {
+			input: methods(
+				jsonFn(`{"a":"x"}`),
+				method("set_path", "a.b", "z"),
+			),
+			err: "object literal: field a: expected object or array, got string",
+		},
+		"check delete path": {
+			input: methods(
+				jsonFn(`{"a":[{"b":1,"c":2},{"b":3}],"d":4}`),
+				method("delete_path", []interface{}{"a", int64(0), "b"}),
+			),
+			output: map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"c": float64(2)},
+					map[string]interface{}{"b": float64(3)},
+				},
+				"d": float64(4),
+			},
+		},
+		"check delete path array element": {
+			input: methods(
+				jsonFn(`{"a":["x","y","z"]}`),
+				method("delete_path", "a.1"),
+			),
+			output: map[string]interface{}{"a": []interface{}{"x", "z"}},
+		},
+		"check delete path missing": {
+			input: methods(
+				jsonFn(`{"a":["x"]}`),
+				method("delete_path", []interface{}{"a", int64(3), "b"}),
+			),
+			output: map[string]interface{}{"a": []interface{}{"x"}},
+		},
 		"check without single": {
 			input: methods(
 				jsonFn(`{"a":"first","b":"second"}`),
@@ -219,12 +399,38 @@ func TestMethods(t *testing.T) {
 				map[string]interface{}{"v": "d"},
 			},
 		},
-		"check unique bad": {
+		"check unique objects": {
 			input: methods(
 				jsonFn(`[{"v":"a"},{"v":"b"},{"v":"c"},{"v":"b"},{"v":"d"},{"v":"a"}]`),
 				method("unique"),
 			),
-			err: "array literal: index 0: expected string or number value, got object",
+			output: []interface{}{
+				map[string]interface{}{"v": "a"},
+				map[string]interface{}{"v": "b"},
+				map[string]interface{}{"v": "c"},
+				map[string]interface{}{"v": "d"},
+			},
+		},
+		"check unique other types": {
+			input: methods(
+				jsonFn(`[true,null,[1,2],false,[1,2],true,null,[2,1],{"a":[1]},{"a":[1]}]`),
+				method("unique"),
+			),
+			output: []interface{}{
+				true, nil, []interface{}{1.0, 2.0}, false, []interface{}{2.0, 1.0},
+				map[string]interface{}{"a": []interface{}{1.0}},
+			},
+		},
+		"check unique by": {
+			input: methods(
+				jsonFn(`[{"id":"a","v":1},{"id":"b","v":2},{"id":"a","v":3},{"id":{"x":1},"v":4},{"id":{"x":1},"v":5}]`),
+				method("unique_by", NewFieldFunction("id")),
+			),
+			output: []interface{}{
+				map[string]interface{}{"id": "a", "v": 1.0},
+				map[string]interface{}{"id": "b", "v": 2.0},
+				map[string]interface{}{"id": map[string]interface{}{"x": 1.0}, "v": 4.0},
+			},
 		},
 		"check unique not array": {
 			input: methods(
@@ -725,6 +931,34 @@ func TestMethods(t *testing.T) {
 			),
 			output: `fd5d5ed60b96e820ebaace4fed962a401adefd3e89c51a374f0bb7f49ed02892af8bc8591628dcbc8b5f065df6bb06588cba95d488c1c8b88faa7cbe08e4558d`,
 		},
+		"check hmac sha1 hex": {
+			input: methods(
+				literalFn("hello world"),
+				method("hmac", "sha1", "static-key"),
+			),
+			output: `d87e5f068fa08fe90bb95bc7c8344cb809179d76`,
+		},
+		"check hmac sha256 base64": {
+			input: methods(
+				literalFn([]byte("hello world")),
+				method("hmac", "sha256", "static-key", "base64"),
+			),
+			output: `sc3OiyrdH5YTWyUG+Kt0iujvFcScAyA1em0WjELiB0Y=`,
+		},
+		"check hmac sha512 base64url": {
+			input: methods(
+				literalFn("hello world"),
+				method("hmac", "sha512", "static-key", "base64url"),
+			),
+			output: `_V1e1guW6CDrqs5P7ZYqQBre_T6JxRo3Twu39J7QKJKvi8hZFijcvItfBl32uwZYjLqV1IjByLiPqny-CORVjQ==`,
+		},
+		"check hmac bad type": {
+			input: methods(
+				literalFn(int64(10)),
+				method("hmac", "sha256", "static-key"),
+			),
+			err: `expected string value, got number from number literal (10)`,
+		},
 		"check xxhash64 hash": {
 			input: methods(
 				literalFn("hello world"),
@@ -741,6 +975,235 @@ func TestMethods(t *testing.T) {
 			),
 			output: `5eb63bbbe01eeed093cb22bb8f5acdc3`,
 		},
+		"check cast string to int": {
+			input:  methods(literalFn(" 10 "), method("cast", "int")),
+			output: int64(10),
+		},
+		"check cast string to int fails": {
+			input: methods(literalFn("1.5"), method("cast", "int")),
+			err:   `string literal: cannot cast string to int: strconv.ParseInt: parsing "1.5": invalid syntax`,
+		},
+		"check cast float to int": {
+			input:  methods(literalFn(float64(3)), method("cast", "int")),
+			output: int64(3),
+		},
+		"check cast fractional float to int fails": {
+			input: methods(literalFn(3.5), method("cast", "int")),
+			err:   `number literal: cannot cast number to int: 3.5 is not a whole number within range`,
+		},
+		"check cast bool to int": {
+			input:  methods(literalFn(true), method("cast", "int")),
+			output: int64(1),
+		},
+		"check cast bytes to float": {
+			input:  methods(literalFn([]byte("2.5")), method("cast", "float")),
+			output: 2.5,
+		},
+		"check cast int to float": {
+			input:  methods(literalFn(int64(2)), method("cast", "float")),
+			output: 2.0,
+		},
+		"check cast string to bool": {
+			input:  methods(literalFn("FALSE"), method("cast", "bool")),
+			output: false,
+		},
+		"check cast string to bool fails": {
+			input: methods(literalFn("nope"), method("cast", "bool")),
+			err:   `string literal: cannot cast string to bool: "nope" is not a recognised boolean`,
+		},
+		"check cast number to bool": {
+			input:  methods(literalFn(int64(5)), method("cast", "bool")),
+			output: true,
+		},
+		"check cast object to string": {
+			input:  methods(literalFn(map[string]interface{}{"a": "b"}), method("cast", "string")),
+			output: `{"a":"b"}`,
+		},
+		"check cast object to int fails": {
+			input: methods(literalFn(map[string]interface{}{}), method("cast", "int")),
+			err:   `object literal: cannot cast object to int`,
+		},
+		"check cast string to number int": {
+			input:  methods(literalFn(" 10 "), method("cast", "number")),
+			output: int64(10),
+		},
+		"check cast string to number float": {
+			input:  methods(literalFn("1.5"), method("cast", "number")),
+			output: 1.5,
+		},
+		"check cast string to number fails": {
+			input: methods(literalFn("nope"), method("cast", "number")),
+			err:   `string literal: cannot cast string to number: strconv.ParseFloat: parsing "nope": invalid syntax`,
+		},
+		"check cast bool to number": {
+			input:  methods(literalFn(true), method("cast", "number")),
+			output: int64(1),
+		},
+		"check cast object to number fails": {
+			input: methods(literalFn(map[string]interface{}{}), method("cast", "number")),
+			err:   `object literal: cannot cast object to number`,
+		},
+		"check cast number to bytes": {
+			input:  methods(literalFn(int64(5)), method("cast", "bytes")),
+			output: []byte("5"),
+		},
+		"check cast object to bytes": {
+			input:  methods(literalFn(map[string]interface{}{"a": "b"}), method("cast", "bytes")),
+			output: []byte(`{"a":"b"}`),
+		},
+		"check cast null to string fails": {
+			input: methods(literalFn(nil), method("cast", "string")),
+			err:   `null literal: cannot cast null to string`,
+		},
+		"check ip_to_int": {
+			input:  methods(literalFn("10.0.0.1"), method("ip_to_int")),
+			output: int64(167772161),
+		},
+		"check ip_to_int mapped ipv6": {
+			input:  methods(literalFn("::ffff:10.0.0.1"), method("ip_to_int")),
+			output: int64(167772161),
+		},
+		"check ip_to_int ipv6 fails": {
+			input: methods(literalFn("2001:db8::1"), method("ip_to_int")),
+			err:   `string literal: IPv6 address 2001:db8::1 cannot be represented as an integer`,
+		},
+		"check ip_to_int invalid fails": {
+			input: methods(literalFn("10.0.0.256"), method("ip_to_int")),
+			err:   `string literal: failed to parse IP address: "10.0.0.256"`,
+		},
+		"check int_to_ip": {
+			input:  methods(literalFn(int64(167772161)), method("int_to_ip")),
+			output: "10.0.0.1",
+		},
+		"check int_to_ip out of range fails": {
+			input: methods(literalFn(int64(4294967296)), method("int_to_ip")),
+			err:   `number literal: value 4294967296 is out of range for an IPv4 address`,
+		},
+		"check ip_info ipv6 private": {
+			input: methods(literalFn("fd00::1"), method("ip_info")),
+			output: map[string]interface{}{
+				"version":      int64(6),
+				"is_private":   true,
+				"is_loopback":  false,
+				"is_multicast": false,
+			},
+		},
+		"check ip_info multicast": {
+			input: methods(literalFn("224.0.0.1"), method("ip_info")),
+			output: map[string]interface{}{
+				"version":      int64(4),
+				"is_private":   false,
+				"is_loopback":  false,
+				"is_multicast": true,
+			},
+		},
+		"check ip_info invalid fails": {
+			input: methods(literalFn("nope"), method("ip_info")),
+			err:   `string literal: failed to parse IP address: "nope"`,
+		},
+		"check crc32 check value": {
+			input: methods(
+				literalFn("123456789"),
+				method("crc32"),
+			),
+			output: int64(3421780262),
+		},
+		"check crc64 check value": {
+			input: methods(
+				literalFn("123456789"),
+				method("crc64"),
+			),
+			output: uint64(0x995dc9bbdf1939fa),
+		},
+		"check crc64 hex": {
+			input: methods(
+				literalFn("123456789"),
+				method("crc64", "hex"),
+			),
+			output: "995dc9bbdf1939fa",
+		},
+		"check xxhash bytes": {
+			input: methods(
+				literalFn([]byte("userC")),
+				method("xxhash"),
+			),
+			output: uint64(11811616936744584835),
+		},
+		"check xxhash empty hex": {
+			input: methods(
+				literalFn(""),
+				method("xxhash", "hex"),
+			),
+			output: "ef46db3751d8e999",
+		},
+		"check crc32 not string": {
+			input: methods(
+				literalFn(int64(10)),
+				method("crc32"),
+			),
+			err: "expected string value, got number from number literal (10)",
+		},
+		"check gzip compress and decompress": {
+			input: methods(
+				literalFn("hello world hello world hello world"),
+				method("compress", "gzip"),
+				method("decompress", "gzip"),
+				method("string"),
+			),
+			output: `hello world hello world hello world`,
+		},
+		"check zlib compress and decompress": {
+			input: methods(
+				literalFn("hello world hello world hello world"),
+				method("compress", "zlib"),
+				method("decompress", "zlib"),
+				method("string"),
+			),
+			output: `hello world hello world hello world`,
+		},
+		"check flate compress and decompress": {
+			input: methods(
+				literalFn("hello world hello world hello world"),
+				method("compress", "flate"),
+				method("decompress", "flate"),
+				method("string"),
+			),
+			output: `hello world hello world hello world`,
+		},
+		"check snappy compress and decompress": {
+			input: methods(
+				literalFn("hello world hello world hello world"),
+				method("compress", "snappy"),
+				method("decompress", "snappy"),
+				method("string"),
+			),
+			output: `hello world hello world hello world`,
+		},
+		"check lz4 compress and decompress": {
+			input: methods(
+				literalFn("hello world hello world hello world"),
+				method("compress", "lz4"),
+				method("decompress", "lz4"),
+				method("string"),
+			),
+			output: `hello world hello world hello world`,
+		},
+		"check zstd compress and decompress": {
+			input: methods(
+				literalFn("hello world hello world hello world"),
+				method("compress", "zstd"),
+				method("decompress", "zstd"),
+				method("string"),
+			),
+			output: `hello world hello world hello world`,
+		},
+		"check decompress error": {
+			input: methods(
+				literalFn("not compressed"),
+				method("decompress", "gzip"),
+			),
+			err: `string literal: gzip: invalid header`,
+		},
 		"check hex encode": {
 			input: methods(
 				literalFn("hello world"),
@@ -786,6 +1249,66 @@ func TestMethods(t *testing.T) {
 			),
 			output: `<<???>>`,
 		},
+		"check base64rawurl encode": {
+			input: methods(
+				literalFn("<<???>>"),
+				method("encode", "base64rawurl"),
+			),
+			output: `PDw_Pz8-Pg`,
+		},
+		"check base64rawurl decode": {
+			input: methods(
+				literalFn("PDw_Pz8-Pg"),
+				method("decode", "base64rawurl"),
+				method("string"),
+			),
+			output: `<<???>>`,
+		},
+		"check base64url_encode": {
+			input: methods(
+				literalFn("<<???>>"),
+				method("base64url_encode"),
+			),
+			output: `PDw_Pz8-Pg`,
+		},
+		"check base64url_encode bytes": {
+			input: methods(
+				literalFn("<<???>>"),
+				method("bytes"),
+				method("base64url_encode"),
+			),
+			output: `PDw_Pz8-Pg`,
+		},
+		"check base64url_encode_padded": {
+			input: methods(
+				literalFn("<<???>>"),
+				method("base64url_encode_padded"),
+			),
+			output: `PDw_Pz8-Pg==`,
+		},
+		"check base64url_decode unpadded": {
+			input: methods(
+				literalFn("PDw_Pz8-Pg"),
+				method("base64url_decode"),
+				method("string"),
+			),
+			output: `<<???>>`,
+		},
+		"check base64url_decode padded": {
+			input: methods(
+				literalFn("PDw_Pz8-Pg=="),
+				method("base64url_decode"),
+				method("string"),
+			),
+			output: `<<???>>`,
+		},
+		"check base64url_decode standard alphabet": {
+			input: methods(
+				literalFn("PDw/Pz8+Pg=="),
+				method("base64url_decode"),
+			),
+			err: "string literal: illegal base64 data at input byte 3",
+		},
 		"check z85 encode": {
 			input: methods(
 				literalFn("hello world!"),
@@ -950,6 +1473,58 @@ func TestMethods(t *testing.T) {
 			),
 			output: "the foo bar",
 		},
+		"check truncate": {
+			input: methods(
+				literalFn("the quick brown fox"),
+				method("truncate", int64(10), "…"),
+			),
+			output: "the quick…",
+		},
+		"check truncate no suffix": {
+			input: methods(
+				literalFn("the quick brown fox"),
+				method("truncate", int64(9)),
+			),
+			output: "the quick",
+		},
+		"check truncate runes": {
+			input: methods(
+				literalFn("héllo wörld"),
+				method("truncate", int64(7), ".."),
+			),
+			output: "héllo..",
+		},
+		"check truncate within limit": {
+			input: methods(
+				literalFn("héllo"),
+				method("truncate", int64(5), "..."),
+			),
+			output: "héllo",
+		},
+		"check truncate suffix exceeds limit": {
+			input: methods(
+				literalFn("hello world"),
+				method("truncate", int64(2), "..."),
+			),
+			output: "..",
+		},
+		"check truncate zero": {
+			input: methods(
+				literalFn("hello world"),
+				method("truncate", int64(0), "..."),
+			),
+			output: "",
+		},
+		"check truncate bytes": {
+			input: methods(
+				function(`content`),
+				method("truncate", int64(3)),
+			),
+			messages: []easyMsg{
+				{content: `hello world`},
+			},
+			output: "hel",
+		},
 		"check trim bytes": {
 			input: methods(
 				function(`content`),
@@ -987,6 +1562,93 @@ func TestMethods(t *testing.T) {
 			},
 			output: []byte("The Foo Bar"),
 		},
+		"check snake_case": {
+			input: methods(
+				literalFn("parseHTTPRequest"),
+				method("snake_case"),
+			),
+			output: "parse_http_request",
+		},
+		"check snake_case acronym suffix": {
+			input: methods(
+				literalFn("userID"),
+				method("snake_case"),
+			),
+			output: "user_id",
+		},
+		"check snake_case separators": {
+			input: methods(
+				literalFn("  Foo-Bar.baz  "),
+				method("snake_case"),
+			),
+			output: "foo_bar_baz",
+		},
+		"check snake_case digits": {
+			input: methods(
+				literalFn("utf8Value2Go"),
+				method("snake_case"),
+			),
+			output: "utf8_value2_go",
+		},
+		"check snake_case unicode": {
+			input: methods(
+				literalFn("ÉcoleNormale"),
+				method("snake_case"),
+			),
+			output: "école_normale",
+		},
+		"check kebab_case": {
+			input: methods(
+				literalFn("FooBar baz"),
+				method("kebab_case"),
+			),
+			output: "foo-bar-baz",
+		},
+		"check camel_case": {
+			input: methods(
+				literalFn("first_name"),
+				method("camel_case"),
+			),
+			output: "firstName",
+		},
+		"check camel_case acronym": {
+			input: methods(
+				literalFn("HTTP_SERVER_url"),
+				method("camel_case"),
+			),
+			output: "httpServerUrl",
+		},
+		"check pascal_case": {
+			input: methods(
+				literalFn("first-name"),
+				method("pascal_case"),
+			),
+			output: "FirstName",
+		},
+		"check title_case": {
+			input: methods(
+				literalFn("first_NAME"),
+				method("title_case"),
+			),
+			output: "First Name",
+		},
+		"check snake_case empty": {
+			input: methods(
+				literalFn(""),
+				method("snake_case"),
+			),
+			output: "",
+		},
+		"check snake_case bytes": {
+			input: methods(
+				function(`content`),
+				method("snake_case"),
+			),
+			messages: []easyMsg{
+				{content: `fooBar`},
+			},
+			output: []byte("foo_bar"),
+		},
 		"check split": {
 			input: methods(
 				literalFn("foo,bar,baz"),
@@ -1088,62 +1750,207 @@ func TestMethods(t *testing.T) {
 			),
 			output: []interface{}{"bar", "baz"},
 		},
-		"check regexp match": {
+		"check regexp match": {
+			input: methods(
+				literalFn(`"there are 10 puppies"`),
+				method("re_match", "[0-9]"),
+			),
+			output: true,
+		},
+		"check regexp match 2": {
+			input: methods(
+				literalFn(`"there are ten puppies"`),
+				method("re_match", "[0-9]"),
+			),
+			output: false,
+		},
+		"check regexp match dynamic": {
+			input: methods(
+				function("json", "input"),
+				method("re_match", function("json", "re")),
+			),
+			messages: []easyMsg{
+				{content: `{"input":"there are 10 puppies","re":"[0-9]"}`},
+			},
+			output: true,
+		},
+		"check regexp replace": {
+			input: methods(
+				literalFn("foo ADD 70"),
+				method("re_replace", "ADD ([0-9]+)", "+($1)"),
+			),
+			output: "foo +(70)",
+		},
+		"check regexp replace dynamic": {
+			input: methods(
+				function("json", "input"),
+				method("re_replace", function("json", "re"), function("json", "replace")),
+			),
+			messages: []easyMsg{
+				{content: `{"input":"foo ADD 70","re":"ADD ([0-9]+)","replace":"+($1)"}`},
+			},
+			output: "foo +(70)",
+		},
+		"check parse json": {
+			input: methods(
+				literalFn("{\"foo\":\"bar\"}"),
+				method("parse_json"),
+			),
+			output: map[string]interface{}{
+				"foo": "bar",
+			},
+		},
+		"check parse json invalid": {
+			input: methods(
+				literalFn("not valid json"),
+				method("parse_json"),
+			),
+			err: `string literal: failed to parse value as JSON: invalid character 'o' in literal null (expecting 'u')`,
+		},
+		"check parse json lines": {
+			input: methods(
+				literalFn("{\"foo\":\"bar\"}\n\n[1,2]\r\n\"baz\"\n"),
+				method("parse_json_lines"),
+			),
+			output: []interface{}{
+				map[string]interface{}{"foo": "bar"},
+				[]interface{}{float64(1), float64(2)},
+				"baz",
+			},
+		},
+		"check parse json lines empty": {
+			input: methods(
+				literalFn(""),
+				method("parse_json_lines"),
+			),
+			output: []interface{}{},
+		},
+		"check parse json lines invalid": {
+			input: methods(
+				literalFn("{\"foo\":\"bar\"}\nnot valid json\n"),
+				method("parse_json_lines"),
+			),
+			err: `string literal: failed to parse line 2 as JSON: invalid character 'o' in literal null (expecting 'u')`,
+		},
+		"check parse duration iso8601 0": {
+			input: methods(
+				literalFn("PT1H30M"),
+				method("parse_duration_iso8601"),
+			),
+			output: int64(5400000000000),
+		},
+		"check parse duration iso8601 1": {
+			input: methods(
+				literalFn("P1W2DT0.5S"),
+				method("parse_duration_iso8601"),
+			),
+			output: int64(777600500000000),
+		},
+		"check parse duration iso8601 2": {
+			input: methods(
+				literalFn("-PT1.25S"),
+				method("parse_duration_iso8601"),
+			),
+			output: int64(-1250000000),
+		},
+		"check parse duration iso8601 3": {
+			input: methods(
+				literalFn("PT0,5M"),
+				method("parse_duration_iso8601"),
+			),
+			output: int64(30000000000),
+		},
+		"check parse duration iso8601 4": {
+			input: methods(
+				literalFn("+PT0.000000001S"),
+				method("parse_duration_iso8601"),
+			),
+			output: int64(1),
+		},
+		"check parse duration iso8601 5": {
+			input: methods(
+				literalFn("P0D"),
+				method("parse_duration_iso8601"),
+			),
+			output: int64(0),
+		},
+		"check parse duration iso8601 bad 0": {
+			input: methods(
+				literalFn("P"),
+				method("parse_duration_iso8601"),
+			),
+			err: "string literal: invalid ISO-8601 duration: P",
+		},
+		"check parse duration iso8601 bad 1": {
+			input: methods(
+				literalFn("PT"),
+				method("parse_duration_iso8601"),
+			),
+			err: "string literal: invalid ISO-8601 duration: PT",
+		},
+		"check parse duration iso8601 bad 2": {
+			input: methods(
+				literalFn("1H"),
+				method("parse_duration_iso8601"),
+			),
+			err: "string literal: invalid ISO-8601 duration: 1H",
+		},
+		"check parse duration iso8601 bad 3": {
+			input: methods(
+				literalFn("P1Y2M"),
+				method("parse_duration_iso8601"),
+			),
+			err: "string literal: invalid ISO-8601 duration: P1Y2M: year and month designators are not supported",
+		},
+		"check parse duration iso8601 bad 4": {
 			input: methods(
-				literalFn(`"there are 10 puppies"`),
-				method("re_match", "[0-9]"),
+				literalFn("PT99999999999999999999H"),
+				method("parse_duration_iso8601"),
 			),
-			output: true,
+			err: "string literal: invalid ISO-8601 duration: PT99999999999999999999H: value 99999999999999999999 out of range",
 		},
-		"check regexp match 2": {
+		"check format duration iso8601 0": {
 			input: methods(
-				literalFn(`"there are ten puppies"`),
-				method("re_match", "[0-9]"),
+				literalFn(int64(5400000000000)),
+				method("format_duration_iso8601"),
 			),
-			output: false,
+			output: "PT1H30M",
 		},
-		"check regexp match dynamic": {
+		"check format duration iso8601 1": {
 			input: methods(
-				function("json", "input"),
-				method("re_match", function("json", "re")),
+				literalFn(int64(-1500000000)),
+				method("format_duration_iso8601"),
 			),
-			messages: []easyMsg{
-				{content: `{"input":"there are 10 puppies","re":"[0-9]"}`},
-			},
-			output: true,
+			output: "-PT1.5S",
 		},
-		"check regexp replace": {
+		"check format duration iso8601 2": {
 			input: methods(
-				literalFn("foo ADD 70"),
-				method("re_replace", "ADD ([0-9]+)", "+($1)"),
+				literalFn(int64(0)),
+				method("format_duration_iso8601"),
 			),
-			output: "foo +(70)",
+			output: "PT0S",
 		},
-		"check regexp replace dynamic": {
+		"check format duration iso8601 3": {
 			input: methods(
-				function("json", "input"),
-				method("re_replace", function("json", "re"), function("json", "replace")),
+				literalFn(int64(90061000000001)),
+				method("format_duration_iso8601"),
 			),
-			messages: []easyMsg{
-				{content: `{"input":"foo ADD 70","re":"ADD ([0-9]+)","replace":"+($1)"}`},
-			},
-			output: "foo +(70)",
+			output: "PT25H1M1.000000001S",
 		},
-		"check parse json": {
+		"check format duration iso8601 4": {
 			input: methods(
-				literalFn("{\"foo\":\"bar\"}"),
-				method("parse_json"),
+				literalFn(float64(60000000000)),
+				method("format_duration_iso8601"),
 			),
-			output: map[string]interface{}{
-				"foo": "bar",
-			},
+			output: "PT1M",
 		},
-		"check parse json invalid": {
+		"check format duration iso8601 roundtrip": {
 			input: methods(
-				literalFn("not valid json"),
-				method("parse_json"),
+				literalFn("-P1DT2H3M4.5S"),
+				method("parse_duration_iso8601"),
+				method("format_duration_iso8601"),
 			),
-			err: `string literal: failed to parse value as JSON: invalid character 'o' in literal null (expecting 'u')`,
+			output: "-PT26H3M4.5S",
 		},
 		"check parse timestamp unix": {
 			input: methods(
@@ -1152,6 +1959,34 @@ func TestMethods(t *testing.T) {
 			),
 			output: int64(1597405526),
 		},
+		"check parse timestamp unix micro string": {
+			input: methods(
+				literalFn("1597405526371000"),
+				method("parse_timestamp_unix_micro"),
+			),
+			output: "2020-08-14T11:45:26.371Z",
+		},
+		"check parse timestamp unix nano": {
+			input: methods(
+				literalFn(int64(1597405526000000001)),
+				method("parse_timestamp_unix_nano"),
+			),
+			output: "2020-08-14T11:45:26.000000001Z",
+		},
+		"check parse timestamp unix nano not number": {
+			input: methods(
+				literalFn(true),
+				method("parse_timestamp_unix_nano"),
+			),
+			err: `expected number value, got bool from bool literal (true)`,
+		},
+		"check format timestamp unix micro": {
+			input: methods(
+				literalFn(float64(1597405526.371)),
+				method("format_timestamp_unix_micro"),
+			),
+			output: int64(1597405526371000),
+		},
 		"check parse timestamp unix with format": {
 			input: methods(
 				literalFn("2020-Aug-14"),
@@ -1702,6 +2537,172 @@ func TestMethods(t *testing.T) {
 			),
 			err: `method decode: ciphertext is not a multiple of the block size`,
 		},
+		"check aes-gcm encryption": {
+			input: methods(
+				literalFn("00000000000000000000000000000000"),
+				method("decode", "hex"),
+				method(
+					"encrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+					methods(
+						literalFn("000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+				method("encode", "hex"),
+			),
+			output: `0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf`,
+		},
+		"check aes-gcm decryption": {
+			input: methods(
+				literalFn("0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf"),
+				method("decode", "hex"),
+				method(
+					"decrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+					methods(
+						literalFn("000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+				method("encode", "hex"),
+			),
+			output: `00000000000000000000000000000000`,
+		},
+		"check aes-gcm prepended nonce decryption": {
+			input: methods(
+				literalFn("0000000000000000000000000388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf"),
+				method("decode", "hex"),
+				method(
+					"decrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+				method("encode", "hex"),
+			),
+			output: `00000000000000000000000000000000`,
+		},
+		"check aes-gcm random nonce round trip": {
+			input: methods(
+				literalFn("hello world!"),
+				method(
+					"encrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+				method(
+					"decrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+				method("string"),
+			),
+			output: `hello world!`,
+		},
+		"check aes-gcm authentication error": {
+			input: methods(
+				literalFn("0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bdde"),
+				method("decode", "hex"),
+				method(
+					"decrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+					methods(
+						literalFn("000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+			),
+			err: `method decode: failed to decrypt: cipher: message authentication failed`,
+		},
+		"check aes-gcm short ciphertext error": {
+			input: methods(
+				literalFn("0000"),
+				method("decode", "hex"),
+				method(
+					"decrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+			),
+			err: `method decode: ciphertext is too short to contain a nonce`,
+		},
+		"check aes-gcm bad nonce error": {
+			input: methods(
+				literalFn("hello world!"),
+				method(
+					"encrypt_aes", "gcm",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+					methods(
+						literalFn("0000"),
+						method("decode", "hex"),
+					),
+				),
+			),
+			err: `nonce must be 12 bytes for scheme gcm, received 2`,
+		},
+		"check aes bad key error": {
+			input: methods(
+				literalFn("hello world!"),
+				method(
+					"encrypt_aes", "gcm",
+					methods(
+						literalFn("0000"),
+						method("decode", "hex"),
+					),
+				),
+			),
+			err: `crypto/aes: invalid key size 2`,
+		},
+		"check aes-ctr missing vector error": {
+			input: methods(
+				literalFn("hello world!"),
+				method(
+					"encrypt_aes", "ctr",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+			),
+			err: `scheme ctr requires an initialization vector`,
+		},
+		"check aes-cbc bad vector error": {
+			input: methods(
+				literalFn("hello world!"),
+				method(
+					"decrypt_aes", "cbc",
+					methods(
+						literalFn("00000000000000000000000000000000"),
+						method("decode", "hex"),
+					),
+					methods(
+						literalFn("000000000000000000000000"),
+						method("decode", "hex"),
+					),
+				),
+			),
+			err: `initialization vector must be 16 bytes for scheme cbc, received 12`,
+		},
 		"check any no array": {
 			input: methods(
 				literalFn("foo"),
@@ -1969,6 +2970,199 @@ func TestMethods(t *testing.T) {
 			})),
 			output: []byte("ITAhello!ITA BOLDworld!BOLD"),
 		},
+		"check replace_all_many string": {
+			input: methods(literalFn("foo foobar bar"), method("replace_all_many", map[string]interface{}{
+				"foo":    "a",
+				"foobar": "b",
+				"bar":    "foo",
+			})),
+			output: "a b foo",
+		},
+		"check replace_all_many bytes": {
+			input: methods(literalFn([]byte("foo foobar bar")), method("replace_all_many", map[string]interface{}{
+				"foo":    "a",
+				"foobar": "b",
+				"bar":    "foo",
+			})),
+			output: []byte("a b foo"),
+		},
+		"check template object": {
+			input: methods(
+				jsonFn(`{"name":"foo","tags":["a","b"]}`),
+				method("template", "{{.name}}: {{range $i, $t := .tags}}{{if $i}},{{end}}{{$t}}{{end}}"),
+			),
+			output: "foo: a,b",
+		},
+		"check template string": {
+			input: methods(
+				literalFn("foo"),
+				method("template", "hello {{.}}"),
+			),
+			output: "hello foo",
+		},
+		"check template missing key": {
+			input: methods(
+				jsonFn(`{"name":"foo"}`),
+				method("template", "{{.nope}}"),
+			),
+			err: `object literal: failed to execute template: template: bloblang:1:2: executing "bloblang" at <.nope>: map has no entry for key "nope"`,
+		},
+		"check format number": {
+			input: methods(
+				literalFn(float64(-1234567.555)),
+				method("format_number", int64(2)),
+			),
+			output: "-1,234,567.55",
+		},
+		"check format number small": {
+			input: methods(
+				literalFn(int64(-123)),
+				method("format_number", int64(0), "_", ","),
+			),
+			output: "-123",
+		},
+		"check format number uint": {
+			input: methods(
+				literalFn(uint64(18446744073709551615)),
+				method("format_number", int64(1), "'"),
+			),
+			output: "18'446'744'073'709'551'615.0",
+		},
+		"check format number string": {
+			input: methods(
+				literalFn("5"),
+				method("format_number", int64(2)),
+			),
+			err: `expected number value, got string from string literal ("5")`,
+		},
+		"check clamp reversed bounds": {
+			input: methods(
+				literalFn(int64(-5)),
+				method("clamp", float64(10), float64(0)),
+			),
+			output: float64(0),
+		},
+		"check clamp string": {
+			input: methods(
+				literalFn("5"),
+				method("clamp", float64(0), float64(10)),
+			),
+			err: `expected number value, got string from string literal ("5")`,
+		},
+		"check lerp": {
+			input: methods(
+				literalFn(float64(0.5)),
+				method("lerp", float64(-10), float64(30)),
+			),
+			output: float64(10),
+		},
+		"check map range reversed": {
+			input: methods(
+				literalFn(int64(25)),
+				method("map_range", float64(100), float64(0), float64(0), float64(1)),
+			),
+			output: float64(0.75),
+		},
+		"check parse query string": {
+			input: methods(
+				literalFn("?a=1&b=x%20y&a=2&c="),
+				method("parse_query_string"),
+			),
+			output: map[string]interface{}{
+				"a": []interface{}{"1", "2"},
+				"b": "x y",
+				"c": "",
+			},
+		},
+		"check parse query string bad escape": {
+			input: methods(
+				literalFn("a=%zz"),
+				method("parse_query_string"),
+			),
+			err: `string literal: invalid URL escape "%zz"`,
+		},
+		"check format query string": {
+			input: methods(
+				literalFn(map[string]interface{}{
+					"b": []interface{}{"x&y", true},
+					"a": int64(1),
+				}),
+				method("format_query_string"),
+			),
+			output: "a=1&b=x%26y&b=true",
+		},
+		"check format query string nested": {
+			input: methods(
+				literalFn(map[string]interface{}{
+					"a": map[string]interface{}{},
+				}),
+				method("format_query_string"),
+			),
+			err: `object literal: field a: expected string, number or bool value, got object`,
+		},
+		"check format query string not object": {
+			input: methods(
+				literalFn("foo"),
+				method("format_query_string"),
+			),
+			err: `expected object value, got string from string literal ("foo")`,
+		},
+		"check bit and": {
+			input: methods(
+				literalFn(float64(171)),
+				method("bit_and", int64(15)),
+			),
+			output: int64(11),
+		},
+		"check bit or uint": {
+			input: methods(
+				literalFn(uint64(1)),
+				method("bit_or", int64(-9223372036854775808)),
+			),
+			output: int64(-9223372036854775807),
+		},
+		"check bit xor": {
+			input: methods(
+				literalFn(int64(6)),
+				method("bit_xor", int64(5)),
+			),
+			output: int64(3),
+		},
+		"check bit not": {
+			input: methods(
+				literalFn(int64(-1)),
+				method("bit_not"),
+			),
+			output: int64(0),
+		},
+		"check shift left overflow": {
+			input: methods(
+				literalFn(int64(3)),
+				method("shift_left", int64(63)),
+			),
+			output: int64(-9223372036854775808),
+		},
+		"check shift right negative": {
+			input: methods(
+				literalFn(int64(-16)),
+				method("shift_right", int64(2)),
+			),
+			output: int64(-4),
+		},
+		"check bit and fractional": {
+			input: methods(
+				literalFn(5.5),
+				method("bit_and", int64(1)),
+			),
+			err: `number literal: expected integer value, got 5.5`,
+		},
+		"check bit not string": {
+			input: methods(
+				literalFn("5"),
+				method("bit_not"),
+			),
+			err: `expected number value, got string from string literal ("5")`,
+		},
 		"check index of": {
 			input: methods(
 				function(`content`),
@@ -2124,3 +3318,22 @@ func TestMethodNoArgsTargets(t *testing.T) {
 		assert.Contains(t, targets, exp, "method: %v", k)
 	}
 }
+
+func TestMethodTruncateNegativeLimit(t *testing.T) {
+	_, err := InitMethod("truncate", NewLiteralFunction("", "foo"), int64(-1))
+	require.EqualError(t, err, "limit must not be negative, received -1")
+}
+
+func TestMethodHMACBadArgs(t *testing.T) {
+	for _, test := range []struct {
+		args []interface{}
+		err  string
+	}{
+		{args: []interface{}{"md5", "static-key"}, err: "unrecognized hmac algorithm: md5"},
+		{args: []interface{}{"sha256", ""}, err: "hmac requires a non-empty key"},
+		{args: []interface{}{"sha256", "static-key", "nope"}, err: "unrecognized encoding type: nope"},
+	} {
+		_, err := InitMethod("hmac", NewLiteralFunction("", "foo"), test.args...)
+		assert.EqualError(t, err, test.err, "args: %v", test.args)
+	}
+}
@@ -25,6 +25,9 @@ func InputConstructorFromSimple(fn func(input.Config, NewManagement) (input.Type
 			return nil, fmt.Errorf("failed to create input '%v': %w", c.Type, err)
 		}
 		pcf = input.AppendProcessorsFromConfig(c, nm, nm.Logger(), nm.Metrics(), pcf...)
+		if pcf, err = input.ApplyRateLimitFromConfig(c, i, nm, nm.Logger(), nm.Metrics(), pcf...); err != nil {
+			return nil, err
+		}
 		return input.WrapWithPipelines(i, pcf...)
 	}
 }
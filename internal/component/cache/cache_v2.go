@@ -36,9 +36,27 @@ type V2 interface {
 	Close(ctx context.Context) error
 }
 
+// V2CompareAndSwap is an optional interface that a V2 cache can implement in
+// order to support atomically swapping the value of a key.
+type V2CompareAndSwap interface {
+	// CompareAndSwap sets the value of a key to newValue only if its current
+	// value matches oldValue, where a nil oldValue requires that the key does
+	// not exist. Returns true if the swap occurred.
+	CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error)
+}
+
+// V2Touch is an optional interface that a V2 cache can implement in order to
+// support resetting the expiry of a key.
+type V2Touch interface {
+	// Touch resets the expiry of an existing key to the provided TTL, or to
+	// the default TTL of the cache when nil. Returns types.ErrKeyNotFound if
+	// the key does not exist.
+	Touch(ctx context.Context, key string, ttl *time.Duration) error
+}
+
 //------------------------------------------------------------------------------
 
-// Implements types.Cache
+// Implements types.CacheWithCAS and types.CacheWithTouch
 type v2ToV1Cache struct {
 	c   V2
 	sig *shutdown.Signaller
@@ -60,6 +78,16 @@ type v2ToV1Cache struct {
 	mDelFailed  metrics.StatCounter
 	mDelSuccess metrics.StatCounter
 	mDelLatency metrics.StatTimer
+
+	mCASMismatch metrics.StatCounter
+	mCASFailed   metrics.StatCounter
+	mCASSuccess  metrics.StatCounter
+	mCASLatency  metrics.StatTimer
+
+	mTouchNotFound metrics.StatCounter
+	mTouchFailed   metrics.StatCounter
+	mTouchSuccess  metrics.StatCounter
+	mTouchLatency  metrics.StatTimer
 }
 
 // NewV2ToV1Cache wraps a cache.V2 with a struct that implements types.Cache.
@@ -84,6 +112,16 @@ func NewV2ToV1Cache(c V2, stats metrics.Type) types.Cache {
 		mDelFailed:  stats.GetCounter("delete.failed"),
 		mDelSuccess: stats.GetCounter("delete.success"),
 		mDelLatency: stats.GetTimer("delete.latency"),
+
+		mCASMismatch: stats.GetCounter("compare_and_swap.mismatch"),
+		mCASFailed:   stats.GetCounter("compare_and_swap.failed"),
+		mCASSuccess:  stats.GetCounter("compare_and_swap.success"),
+		mCASLatency:  stats.GetTimer("compare_and_swap.latency"),
+
+		mTouchNotFound: stats.GetCounter("touch.not_found"),
+		mTouchFailed:   stats.GetCounter("touch.failed"),
+		mTouchSuccess:  stats.GetCounter("touch.success"),
+		mTouchLatency:  stats.GetTimer("touch.latency"),
 	}
 }
 
@@ -189,6 +227,44 @@ func (a *v2ToV1Cache) Delete(key string) error {
 	return err
 }
 
+func (a *v2ToV1Cache) CompareAndSwap(key string, oldValue, newValue []byte) (bool, error) {
+	casCache, ok := a.c.(V2CompareAndSwap)
+	if !ok {
+		return false, types.ErrNotSupported
+	}
+	started := time.Now()
+	swapped, err := casCache.CompareAndSwap(context.Background(), key, oldValue, newValue)
+	a.mCASLatency.Timing(int64(time.Since(started)))
+	if err != nil {
+		a.mCASFailed.Incr(1)
+	} else if !swapped {
+		a.mCASMismatch.Incr(1)
+	} else {
+		a.mCASSuccess.Incr(1)
+	}
+	return swapped, err
+}
+
+func (a *v2ToV1Cache) Touch(key string, ttl *time.Duration) error {
+	touchCache, ok := a.c.(V2Touch)
+	if !ok {
+		return types.ErrNotSupported
+	}
+	started := time.Now()
+	err := touchCache.Touch(context.Background(), key, ttl)
+	a.mTouchLatency.Timing(int64(time.Since(started)))
+	if err != nil {
+		if errors.Is(err, types.ErrKeyNotFound) {
+			a.mTouchNotFound.Incr(1)
+		} else {
+			a.mTouchFailed.Incr(1)
+		}
+	} else {
+		a.mTouchSuccess.Incr(1)
+	}
+	return err
+}
+
 func (a *v2ToV1Cache) CloseAsync() {
 	go func() {
 		if err := a.c.Close(context.Background()); err == nil {
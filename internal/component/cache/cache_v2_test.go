@@ -9,6 +9,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testCacheItem struct {
@@ -264,6 +265,105 @@ func TestCacheAirGapDelete(t *testing.T) {
 	assert.Equal(t, map[string]testCacheItem{}, rl.m)
 }
 
+type casCache struct {
+	closableCache
+}
+
+func (c *casCache) CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	i, ok := c.m[key]
+	if ok != (oldValue != nil) || (ok && string(i.b) != string(oldValue)) {
+		return false, nil
+	}
+	c.m[key] = testCacheItem{b: newValue}
+	return true, nil
+}
+
+func TestCacheAirGapCompareAndSwap(t *testing.T) {
+	rl := &casCache{
+		closableCache: closableCache{
+			m: map[string]testCacheItem{
+				"foo": {
+					b: []byte("bar"),
+				},
+			},
+		},
+	}
+	agrl := NewV2ToV1Cache(rl, metrics.Noop()).(types.CacheWithCAS)
+
+	swapped, err := agrl.CompareAndSwap("foo", []byte("baz"), []byte("buz"))
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = agrl.CompareAndSwap("foo", []byte("bar"), []byte("buz"))
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+	assert.Equal(t, "buz", string(rl.m["foo"].b))
+
+	rl.err = errors.New("test err")
+	_, err = agrl.CompareAndSwap("foo", []byte("buz"), []byte("bev"))
+	assert.EqualError(t, err, "test err")
+}
+
+func TestCacheAirGapCompareAndSwapNotSupported(t *testing.T) {
+	rl := &closableCache{
+		m: map[string]testCacheItem{},
+	}
+	agrl := NewV2ToV1Cache(rl, metrics.Noop()).(types.CacheWithCAS)
+
+	_, err := agrl.CompareAndSwap("foo", nil, []byte("bar"))
+	assert.Equal(t, types.ErrNotSupported, err)
+}
+
+type touchCache struct {
+	closableCache
+	touched map[string]*time.Duration
+}
+
+func (c *touchCache) Touch(ctx context.Context, key string, ttl *time.Duration) error {
+	if c.err != nil {
+		return c.err
+	}
+	if _, ok := c.m[key]; !ok {
+		return types.ErrKeyNotFound
+	}
+	c.touched[key] = ttl
+	return nil
+}
+
+func TestCacheAirGapTouch(t *testing.T) {
+	rl := &touchCache{
+		closableCache: closableCache{
+			m: map[string]testCacheItem{
+				"foo": {
+					b: []byte("bar"),
+				},
+			},
+		},
+		touched: map[string]*time.Duration{},
+	}
+	agrl := NewV2ToV1Cache(rl, metrics.Noop()).(types.CacheWithTouch)
+
+	ttl := time.Minute
+	require.NoError(t, agrl.Touch("foo", &ttl))
+	assert.Equal(t, map[string]*time.Duration{"foo": &ttl}, rl.touched)
+
+	assert.Equal(t, types.ErrKeyNotFound, agrl.Touch("bar", nil))
+
+	rl.err = errors.New("test err")
+	assert.EqualError(t, agrl.Touch("foo", nil), "test err")
+}
+
+func TestCacheAirGapTouchNotSupported(t *testing.T) {
+	rl := &closableCache{
+		m: map[string]testCacheItem{},
+	}
+	agrl := NewV2ToV1Cache(rl, metrics.Noop()).(types.CacheWithTouch)
+	assert.Equal(t, types.ErrNotSupported, agrl.Touch("foo", nil))
+}
+
 type closableCacheType struct {
 	m      map[string]testCacheItem
 	err    error
@@ -71,7 +71,7 @@ func applyOverrides(specs docs.FieldSpecs, root *yaml.Node, overrides ...string)
 	return nil
 }
 
-func (r *Reader) readMain(conf *config.Type) (lints []string, err error) {
+func (r *Reader) readMain(conf *config.Type, lintCtx docs.LintContext) (lints []string, err error) {
 	defer func() {
 		if err != nil && r.mainPath != "" {
 			err = fmt.Errorf("%v: %w", r.mainPath, err)
@@ -103,7 +103,7 @@ func (r *Reader) readMain(conf *config.Type) (lints []string, err error) {
 		if r.mainPath != "" {
 			lintFilePrefix = fmt.Sprintf("%v: ", r.mainPath)
 		}
-		for _, lint := range confSpec.LintYAML(docs.NewLintContext(), &rawNode) {
+		for _, lint := range confSpec.LintYAML(lintCtx.WithFile(r.mainPath), &rawNode) {
 			lints = append(lints, fmt.Sprintf("%vline %v: %v", lintFilePrefix, lint.Line, lint.What))
 		}
 	}
@@ -112,11 +112,11 @@ func (r *Reader) readMain(conf *config.Type) (lints []string, err error) {
 	return
 }
 
-func (r *Reader) readResources(conf *config.Type) (lints []string, err error) {
+func (r *Reader) readResources(conf *config.Type, lintCtx docs.LintContext) (lints []string, err error) {
 	for _, path := range r.resourcePaths {
 		rconf := manager.NewResourceConfig()
 		var rLints []string
-		if rLints, err = readResource(path, &rconf); err != nil {
+		if rLints, err = readResource(path, &rconf, lintCtx); err != nil {
 			return
 		}
 		lints = append(lints, rLints...)
@@ -128,7 +128,7 @@ func (r *Reader) readResources(conf *config.Type) (lints []string, err error) {
 	return
 }
 
-func readResource(path string, conf *manager.ResourceConfig) (lints []string, err error) {
+func readResource(path string, conf *manager.ResourceConfig, lintCtx docs.LintContext) (lints []string, err error) {
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("%v: %w", path, err)
@@ -145,7 +145,7 @@ func readResource(path string, conf *manager.ResourceConfig) (lints []string, er
 		return
 	}
 	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		for _, lint := range manager.Spec().LintYAML(docs.NewLintContext(), &rawNode) {
+		for _, lint := range manager.Spec().LintYAML(lintCtx.WithFile(path), &rawNode) {
 			lints = append(lints, fmt.Sprintf("resource file %v: line %v: %v", path, lint.Line, lint.What))
 		}
 	}
@@ -156,11 +156,14 @@ func readResource(path string, conf *manager.ResourceConfig) (lints []string, er
 
 // Read a Benthos config from the files and options specified.
 func (r *Reader) Read(conf *config.Type) (lints []string, err error) {
-	if lints, err = r.readMain(conf); err != nil {
+	// Labels are linted with a shared context so that collisions between the
+	// main config and resource files are detected.
+	lintCtx := docs.NewLintContext()
+	if lints, err = r.readMain(conf, lintCtx); err != nil {
 		return
 	}
 	var rLints []string
-	if rLints, err = r.readResources(conf); err != nil {
+	if rLints, err = r.readResources(conf, lintCtx); err != nil {
 		return
 	}
 	lints = append(lints, rLints...)
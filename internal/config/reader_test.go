@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -219,3 +220,46 @@ cache_resources:
 	assert.Equal(t, "memory", conf.ResourceCaches[1].Type)
 	assert.Equal(t, 13, conf.ResourceCaches[1].Memory.TTL)
 }
+
+func TestLintsLabelCollisionsAcrossFiles(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test_resources")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	fullPath := filepath.Join(dir, "main.yaml")
+	require.NoError(t, os.WriteFile(fullPath, []byte(`
+input:
+  label: foo
+  kafka:
+    addresses: [ foobar.com ]
+    topics: [ meow1 ]
+`), 0644))
+
+	resourceOnePath := filepath.Join(dir, "res1.yaml")
+	require.NoError(t, os.WriteFile(resourceOnePath, []byte(`
+cache_resources:
+  - label: bar
+    memory: {}
+`), 0644))
+
+	resourceTwoPath := filepath.Join(dir, "res2.yaml")
+	require.NoError(t, os.WriteFile(resourceTwoPath, []byte(`
+rate_limit_resources:
+  - label: foo
+    local: {}
+  - label: bar
+    local: {}
+`), 0644))
+
+	conf := config.New()
+	rdr := iconfig.NewReader(fullPath, []string{resourceOnePath, resourceTwoPath})
+
+	lints, err := rdr.Read(&conf)
+	require.NoError(t, err)
+	require.Len(t, lints, 2)
+	assert.Equal(t, fmt.Sprintf("resource file %v: line 3: Label 'foo' collides with a previously defined label at %v: line 3", resourceTwoPath, fullPath), lints[0])
+	assert.Equal(t, fmt.Sprintf("resource file %v: line 5: Label 'bar' collides with a previously defined label at %v: line 3", resourceTwoPath, resourceOnePath), lints[1])
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/internal/filepath"
+	"github.com/Jeffail/benthos/v3/lib/cache"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/ratelimit"
+)
+
+// ResourceReloader is implemented by resource managers that are able to
+// atomically replace cache and rate limit resources at runtime.
+type ResourceReloader interface {
+	ReplaceResources(ctx context.Context, caches map[string]cache.Config, rateLimits map[string]ratelimit.Config) error
+}
+
+// resourceReloadTimeout is the maximum period to wait for replaced resources
+// to close during a reload.
+const resourceReloadTimeout = time.Second * 30
+
+// ResourceWatcher polls a set of resource files for changes and hot swaps the
+// cache and rate limit resources that they define within a resource manager.
+// Changes to other resource types are not reloaded.
+type ResourceWatcher struct {
+	patterns []string
+	period   time.Duration
+
+	contents   map[string][]byte
+	caches     map[string]cache.Config
+	rateLimits map[string]ratelimit.Config
+	others     manager.Config
+}
+
+// NewResourceWatcher creates a watcher for resource files matching a list of
+// paths, which may include glob patterns. The files are read immediately in
+// order to determine the resources that are currently active, and therefore
+// it should be created alongside the config that the manager is built from.
+func NewResourceWatcher(patterns []string) (*ResourceWatcher, error) {
+	w := &ResourceWatcher{
+		patterns: patterns,
+		period:   time.Second,
+	}
+
+	contents, err := w.readFiles()
+	if err != nil {
+		return nil, err
+	}
+	conf, _, err := w.readConfig(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	w.contents = contents
+	w.caches = conf.Manager.Caches
+	w.rateLimits = conf.Manager.RateLimits
+	w.others = otherResources(conf)
+	return w, nil
+}
+
+// Watch checks the resource files for changes periodically until the context
+// is cancelled, and each time they change the modified cache and rate limit
+// resources are replaced within the provided manager.
+func (w *ResourceWatcher) Watch(ctx context.Context, mgr ResourceReloader, log log.Modular) {
+	for {
+		select {
+		case <-time.After(w.period):
+		case <-ctx.Done():
+			return
+		}
+		w.checkForChanges(ctx, mgr, log)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func (w *ResourceWatcher) readFiles() (map[string][]byte, error) {
+	paths, err := filepath.Globs(w.patterns)
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		if contents[path], err = ioutil.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return contents, nil
+}
+
+func (w *ResourceWatcher) readConfig(contents map[string][]byte) (conf manager.ResourceConfig, lints []string, err error) {
+	paths := make([]string, 0, len(contents))
+	for path := range contents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	conf = manager.NewResourceConfig()
+	lintCtx := docs.NewLintContext()
+	for _, path := range paths {
+		rconf := manager.NewResourceConfig()
+		var rLints []string
+		if rLints, err = readResource(path, &rconf, lintCtx); err != nil {
+			return
+		}
+		lints = append(lints, rLints...)
+		if err = conf.AddFrom(&rconf); err != nil {
+			return
+		}
+	}
+	conf, err = conf.Collapsed()
+	return
+}
+
+func otherResources(conf manager.ResourceConfig) manager.Config {
+	others := conf.Manager
+	others.Caches = nil
+	others.RateLimits = nil
+	return others
+}
+
+func contentsEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, exists := b[k]; !exists || !bytes.Equal(v, bv) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *ResourceWatcher) checkForChanges(ctx context.Context, mgr ResourceReloader, log log.Modular) {
+	contents, err := w.readFiles()
+	if err != nil {
+		log.Debugf("Failed to read resource files: %v\n", err)
+		return
+	}
+	if contentsEqual(contents, w.contents) {
+		return
+	}
+	w.contents = contents
+
+	conf, lints, err := w.readConfig(contents)
+	if err != nil {
+		log.Errorf("Failed to reload resource files, continuing with the previous resources: %v\n", err)
+		return
+	}
+	if len(lints) > 0 {
+		for _, lint := range lints {
+			log.Errorln(lint)
+		}
+		log.Errorln("Failed to reload resource files due to linter errors, continuing with the previous resources")
+		return
+	}
+
+	changedCaches := map[string]cache.Config{}
+	for name, c := range conf.Manager.Caches {
+		if prev, exists := w.caches[name]; !exists || !reflect.DeepEqual(prev, c) {
+			changedCaches[name] = c
+		}
+	}
+	changedRateLimits := map[string]ratelimit.Config{}
+	for name, r := range conf.Manager.RateLimits {
+		if prev, exists := w.rateLimits[name]; !exists || !reflect.DeepEqual(prev, r) {
+			changedRateLimits[name] = r
+		}
+	}
+
+	for name := range w.caches {
+		if _, exists := conf.Manager.Caches[name]; !exists {
+			log.Warnf("Cache resource '%v' was removed from the resource files but remains active until restarted\n", name)
+		}
+	}
+	for name := range w.rateLimits {
+		if _, exists := conf.Manager.RateLimits[name]; !exists {
+			log.Warnf("Rate limit resource '%v' was removed from the resource files but remains active until restarted\n", name)
+		}
+	}
+	if others := otherResources(conf); !reflect.DeepEqual(others, w.others) {
+		w.others = others
+		log.Warnln("Changes to resources other than caches and rate limits are not reloaded and require a restart")
+	}
+
+	if len(changedCaches) == 0 && len(changedRateLimits) == 0 {
+		return
+	}
+
+	rctx, done := context.WithTimeout(ctx, resourceReloadTimeout)
+	defer done()
+	if err := mgr.ReplaceResources(rctx, changedCaches, changedRateLimits); err != nil {
+		log.Errorf("Failed to reload resource files, continuing with the previous resources: %v\n", err)
+		return
+	}
+
+	var names []string
+	for name, c := range changedCaches {
+		w.caches[name] = c
+		names = append(names, "cache '"+name+"'")
+	}
+	for name, r := range changedRateLimits {
+		w.rateLimits[name] = r
+		names = append(names, "rate limit '"+name+"'")
+	}
+	sort.Strings(names)
+	log.Infof("Reloaded resources: %v\n", strings.Join(names, ", "))
+}
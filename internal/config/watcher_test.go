@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/cache"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReloader struct {
+	err        error
+	caches     []map[string]cache.Config
+	rateLimits []map[string]ratelimit.Config
+}
+
+func (f *fakeReloader) ReplaceResources(ctx context.Context, caches map[string]cache.Config, rateLimits map[string]ratelimit.Config) error {
+	f.caches = append(f.caches, caches)
+	f.rateLimits = append(f.rateLimits, rateLimits)
+	return f.err
+}
+
+func TestResourceWatcher(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test_resource_watcher")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	resOnePath := filepath.Join(dir, "res1.yaml")
+	require.NoError(t, os.WriteFile(resOnePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      ttl: 12
+rate_limit_resources:
+  - label: bar
+    local:
+      count: 10
+`), 0644))
+
+	w, err := NewResourceWatcher([]string{filepath.Join(dir, "*.yaml")})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	reloader := &fakeReloader{}
+
+	// No changes
+	w.checkForChanges(ctx, reloader, log.Noop())
+	assert.Empty(t, reloader.caches)
+
+	// Only the rate limit changes
+	require.NoError(t, os.WriteFile(resOnePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      ttl: 12
+rate_limit_resources:
+  - label: bar
+    local:
+      count: 20
+`), 0644))
+
+	w.checkForChanges(ctx, reloader, log.Noop())
+	require.Len(t, reloader.caches, 1)
+	assert.Empty(t, reloader.caches[0])
+	require.Contains(t, reloader.rateLimits[0], "bar")
+	assert.Equal(t, 20, reloader.rateLimits[0]["bar"].Local.Count)
+
+	// Linter errors are rejected
+	require.NoError(t, os.WriteFile(resOnePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      ttl: 30
+      nope: true
+`), 0644))
+
+	w.checkForChanges(ctx, reloader, log.Noop())
+	assert.Len(t, reloader.caches, 1)
+
+	// A new file within the directory adds a cache
+	require.NoError(t, os.WriteFile(resOnePath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      ttl: 12
+rate_limit_resources:
+  - label: bar
+    local:
+      count: 20
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "res2.yaml"), []byte(`
+cache_resources:
+  - label: baz
+    memory:
+      ttl: 5
+`), 0644))
+
+	w.checkForChanges(ctx, reloader, log.Noop())
+	require.Len(t, reloader.caches, 2)
+	assert.Equal(t, []string{"baz"}, keys(reloader.caches[1]))
+	assert.Empty(t, reloader.rateLimits[1])
+}
+
+func TestResourceWatcherFailedReload(t *testing.T) {
+	dir, err := os.MkdirTemp("", "test_resource_watcher_failed")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	resPath := filepath.Join(dir, "res.yaml")
+	require.NoError(t, os.WriteFile(resPath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      ttl: 12
+`), 0644))
+
+	w, err := NewResourceWatcher([]string{resPath})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	reloader := &fakeReloader{err: errors.New("nope")}
+
+	require.NoError(t, os.WriteFile(resPath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      ttl: 30
+`), 0644))
+
+	w.checkForChanges(ctx, reloader, log.Noop())
+	require.Len(t, reloader.caches, 1)
+	assert.Equal(t, 12, w.caches["foo"].Memory.TTL)
+
+	// The failed change is attempted again once the file is modified
+	reloader.err = nil
+	require.NoError(t, os.WriteFile(resPath, []byte(`
+cache_resources:
+  - label: foo
+    memory:
+      ttl: 40
+`), 0644))
+
+	w.checkForChanges(ctx, reloader, log.Noop())
+	require.Len(t, reloader.caches, 2)
+	assert.Equal(t, 40, reloader.caches[1]["foo"].Memory.TTL)
+	assert.Equal(t, 40, w.caches["foo"].Memory.TTL)
+}
+
+func keys(m map[string]cache.Config) []string {
+	var k []string
+	for name := range m {
+		k = append(k, name)
+	}
+	return k
+}
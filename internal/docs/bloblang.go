@@ -16,7 +16,7 @@ import (
 func LintBloblangMapping(ctx LintContext, line, col int, v interface{}) []Lint {
 	str, ok := v.(string)
 	if !ok {
-		return []Lint{NewLintError(line, fmt.Sprintf("expected string value, got %T", v))}
+		return []Lint{NewTypedLintError(line, LintExpectedScalar, fmt.Sprintf("expected string value, got %T", v))}
 	}
 	if str == "" {
 		return nil
@@ -27,11 +27,11 @@ func LintBloblangMapping(ctx LintContext, line, col int, v interface{}) []Lint {
 	}
 	if mErr, ok := err.(*parser.Error); ok {
 		bline, bcol := parser.LineAndColOf([]rune(str), mErr.Input)
-		lint := NewLintError(line+bline, mErr.ErrorAtPositionStructured("", []rune(str)))
+		lint := NewTypedLintError(line+bline, LintBadBloblang, mErr.ErrorAtPositionStructured("", []rune(str)))
 		lint.Column = col + bcol
 		return []Lint{lint}
 	}
-	return []Lint{NewLintError(line, err.Error())}
+	return []Lint{NewTypedLintError(line, LintBadBloblang, err.Error())}
 }
 
 // LintBloblangField is function for linting a config field expected to be an
@@ -39,7 +39,7 @@ func LintBloblangMapping(ctx LintContext, line, col int, v interface{}) []Lint {
 func LintBloblangField(ctx LintContext, line, col int, v interface{}) []Lint {
 	str, ok := v.(string)
 	if !ok {
-		return []Lint{NewLintWarning(line, fmt.Sprintf("expected string value, got %T", v))}
+		return []Lint{NewTypedLintWarning(line, LintExpectedScalar, fmt.Sprintf("expected string value, got %T", v))}
 	}
 	if str == "" {
 		return nil
@@ -50,11 +50,11 @@ func LintBloblangField(ctx LintContext, line, col int, v interface{}) []Lint {
 	}
 	if mErr, ok := err.(*parser.Error); ok {
 		bline, bcol := parser.LineAndColOf([]rune(str), mErr.Input)
-		lint := NewLintError(line+bline, mErr.ErrorAtPositionStructured("", []rune(str)))
+		lint := NewTypedLintError(line+bline, LintBadBloblang, mErr.ErrorAtPositionStructured("", []rune(str)))
 		lint.Column = col + bcol
 		return []Lint{lint}
 	}
-	return []Lint{NewLintError(line, err.Error())}
+	return []Lint{NewTypedLintError(line, LintBadBloblang, err.Error())}
 }
 
 type functionCategory struct {
@@ -255,8 +255,11 @@ root.has_good_taste = ["pikachu","mewtwo","magmar"].contains(this.user.fav_pokem
 {{end -}}
 
 [field_paths]: /docs/configuration/field_paths
+[methods.cast]: #cast
+[methods.catch]: #catch
 [methods.encode]: #encode
 [methods.string]: #string
+[methods.type]: #type
 `
 
 func methodForCat(s query.MethodSpec, cat query.MethodCategory) (query.MethodSpec, bool) {
@@ -108,6 +108,7 @@ type componentContext struct {
 	Categories         string
 	Examples           []AnnotatedExample
 	Fields             []FieldSpecCtx
+	FieldGroups        []FieldGroupCtx
 	Footnotes          string
 	CommonConfig       string
 	AdvancedConfig     string
@@ -193,8 +194,12 @@ Introduced in version {{.Version}}.
 {{if and (le (len .Fields) 4) (gt (len .Fields) 0) -}}
 ## Fields
 
+{{if gt (len .FieldGroups) 0 -}}
+{{template "field_group_docs" . -}}
+{{else -}}
 {{template "field_docs" . -}}
 {{end -}}
+{{end -}}
 
 {{if gt (len .Examples) 0 -}}
 ## Examples
@@ -223,8 +228,12 @@ Introduced in version {{.Version}}.
 {{if gt (len .Fields) 4 -}}
 ## Fields
 
+{{if gt (len .FieldGroups) 0 -}}
+{{template "field_group_docs" . -}}
+{{else -}}
 {{template "field_docs" . -}}
 {{end -}}
+{{end -}}
 
 {{if gt (len .Footnotes) 0 -}}
 {{.Footnotes}}
@@ -328,6 +337,7 @@ func (c *ComponentSpec) AsMarkdown(nest bool, fullConfigExample interface{}) ([]
 		v.Spec.Kind = KindScalar
 		ctx.Fields = append(ctx.Fields, v)
 	}
+	ctx.FieldGroups = GroupFieldsForDocs(ctx.Fields)
 
 	var buf bytes.Buffer
 	err = template.Must(template.New("component").Parse(componentTemplate)).Execute(&buf, ctx)
@@ -19,13 +19,64 @@ var (
 	ErrBadLabel = fmt.Errorf("should match the regular expression /%v/ and must not start with an underscore", labelExpression)
 )
 
+// LabelPolicy describes the characters that are permitted within component
+// labels in addition to lowercase letters, numbers and underscores. The zero
+// value is the default policy used by ValidateLabel.
+type LabelPolicy struct {
+	// AllowUppercase permits uppercase letters.
+	AllowUppercase bool
+
+	// AllowHyphens permits hyphens.
+	AllowHyphens bool
+
+	// AllowLeadingUnderscore permits labels that start with an underscore.
+	AllowLeadingUnderscore bool
+}
+
+func (p LabelPolicy) expression() string {
+	chars := "a-z"
+	if p.AllowUppercase {
+		chars += "A-Z"
+	}
+	chars += "0-9_"
+	if p.AllowHyphens {
+		chars += "-"
+	}
+	return "^[" + chars + "]+$"
+}
+
 // ValidateLabel attempts to validate the contents of a component label.
 func ValidateLabel(label string) error {
-	if strings.HasPrefix(label, "_") {
-		return ErrBadLabel
+	return ValidateLabelWithPolicy(label, LabelPolicy{})
+}
+
+// ValidateLabelWithPolicy attempts to validate the contents of a component
+// label according to a policy of permitted characters.
+func ValidateLabelWithPolicy(label string, policy LabelPolicy) error {
+	if policy == (LabelPolicy{}) {
+		if strings.HasPrefix(label, "_") || !labelRe.MatchString(label) {
+			return ErrBadLabel
+		}
+		return nil
+	}
+
+	badLabel := func() error {
+		if policy.AllowLeadingUnderscore {
+			return fmt.Errorf("should match the regular expression /%v/", policy.expression())
+		}
+		return fmt.Errorf("should match the regular expression /%v/ and must not start with an underscore", policy.expression())
 	}
-	if !labelRe.MatchString(label) {
-		return ErrBadLabel
+	if label == "" || (!policy.AllowLeadingUnderscore && strings.HasPrefix(label, "_")) {
+		return badLabel()
+	}
+	for _, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+		case r >= 'A' && r <= 'Z' && policy.AllowUppercase:
+		case r == '-' && policy.AllowHyphens:
+		default:
+			return badLabel()
+		}
 	}
 	return nil
 }
@@ -46,18 +97,26 @@ var labelField = FieldString(
 	if l == "" {
 		return nil
 	}
-	if err := ValidateLabel(l); err != nil {
+	if err := ValidateLabelWithPolicy(l, ctx.LabelPolicy); err != nil {
 		return []Lint{
-			NewLintError(line, fmt.Sprintf("Invalid label '%v': %v", l, err)),
+			NewTypedLintError(line, LintBadLabel, fmt.Sprintf("Invalid label '%v': %v", l, err)),
 		}
 	}
 	prevLine, exists := ctx.LabelsToLine[l]
 	if exists {
+		if prevFile := ctx.LabelsToFile[l]; prevFile != "" && prevFile != ctx.File {
+			return []Lint{
+				NewTypedLintError(line, LintDuplicateLabel, fmt.Sprintf("Label '%v' collides with a previously defined label at %v: line %v", l, prevFile, prevLine)),
+			}
+		}
 		return []Lint{
-			NewLintError(line, fmt.Sprintf("Label '%v' collides with a previously defined label at line %v", l, prevLine)),
+			NewTypedLintError(line, LintDuplicateLabel, fmt.Sprintf("Label '%v' collides with a previously defined label at line %v", l, prevLine)),
 		}
 	}
 	ctx.LabelsToLine[l] = line
+	if ctx.File != "" && ctx.LabelsToFile != nil {
+		ctx.LabelsToFile[l] = ctx.File
+	}
 	return nil
 })
 
@@ -73,6 +132,67 @@ func reservedFieldsByType(t Type) map[string]FieldSpec {
 			}
 			return "", false
 		})
+		m["max_message_bytes"] = FieldInt("max_message_bytes", "").OmitWhen(func(field, _ interface{}) (string, bool) {
+			if i, ok := field.(int); ok && i <= 0 {
+				return "field max_message_bytes is disabled and can be removed", true
+			}
+			return "", false
+		})
+	}
+	if t == TypeInput {
+		m["rate_limit"] = FieldString("rate_limit", "").OmitWhen(func(field, _ interface{}) (string, bool) {
+			if str, ok := field.(string); ok && str == "" {
+				return "field rate_limit is empty and can be removed", true
+			}
+			return "", false
+		})
+		m["redelivery_cache"] = FieldString("redelivery_cache", "").OmitWhen(func(field, _ interface{}) (string, bool) {
+			if str, ok := field.(string); ok && str == "" {
+				return "field redelivery_cache is empty and can be removed", true
+			}
+			return "", false
+		})
+		m["redelivery_ttl"] = FieldString("redelivery_ttl", "").OmitWhen(func(_, parent interface{}) (string, bool) {
+			if pMap, ok := parent.(map[string]interface{}); ok {
+				if str, _ := pMap["redelivery_cache"].(string); str != "" {
+					return "", false
+				}
+			}
+			return "field redelivery_ttl has no effect without a redelivery_cache and can be removed", true
+		})
+		m["lineage"] = FieldBool("lineage", "").OmitWhen(func(field, _ interface{}) (string, bool) {
+			if b, ok := field.(bool); ok && !b {
+				return "field lineage is disabled and can be removed", true
+			}
+			return "", false
+		})
+		m["message_id"] = FieldAdvanced("message_id", "").WithChildren(
+			FieldString("strategy", "").HasDefault(""),
+			FieldString("value", "").HasDefault(""),
+			FieldString("key", "").HasDefault("id"),
+			FieldBool("overwrite", "").HasDefault(false),
+		).OmitWhen(func(field, _ interface{}) (string, bool) {
+			if mMap, ok := field.(map[string]interface{}); ok {
+				if strategy, _ := mMap["strategy"].(string); strategy == "" {
+					return "field message_id has no strategy and can be removed", true
+				}
+			}
+			return "", false
+		})
+		m["validation"] = FieldAdvanced("validation", "").WithChildren(
+			FieldString("schema", "").HasDefault(""),
+			FieldString("schema_path", "").HasDefault(""),
+			FieldString("quarantine_output", "").HasDefault(""),
+		).OmitWhen(func(field, _ interface{}) (string, bool) {
+			if vMap, ok := field.(map[string]interface{}); ok {
+				schema, _ := vMap["schema"].(string)
+				schemaPath, _ := vMap["schema_path"].(string)
+				if schema == "" && schemaPath == "" {
+					return "field validation has no schema and can be removed", true
+				}
+			}
+			return "", false
+		})
 	}
 	if _, isLabelType := map[Type]struct{}{
 		TypeInput:     {},
@@ -157,11 +277,68 @@ func getInferenceCandidateFromList(docProvider Provider, t Type, defaultType str
 
 	if inferred == "" {
 		sort.Strings(candidates)
-		return "", ComponentSpec{}, fmt.Errorf("unable to infer %v type, candidates were: %v", string(t), candidates)
+		err := fmt.Errorf("unable to infer %v type, candidates were: %v", string(t), candidates)
+		if len(candidates) == 1 {
+			if suggestion := suggestComponentName(docProvider, t, candidates[0]); suggestion != "" {
+				err = fmt.Errorf("%w, did you mean '%v'?", err, suggestion)
+			}
+		}
+		return "", ComponentSpec{}, err
 	}
 	return inferred, inferredSpec, nil
 }
 
+// suggestComponentName attempts to find a registered component of a type with
+// a name similar enough to an unrecognised name that it's likely a typo.
+func suggestComponentName(docProvider Provider, t Type, name string) string {
+	lister, ok := docProvider.(docsLister)
+	if !ok {
+		return ""
+	}
+
+	maxDist := len(name) / 3
+	if maxDist < 2 {
+		maxDist = 2
+	}
+
+	var suggestion string
+	bestDist := maxDist + 1
+	for _, spec := range lister.ListDocs(t) {
+		if dist := levenshtein(name, spec.Name); dist < bestDist {
+			suggestion = spec.Name
+			bestDist = dist
+		}
+	}
+	return suggestion
+}
+
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if v := prev[j] + 1; v < curr[j] {
+				curr[j] = v
+			}
+			if v := curr[j-1] + 1; v < curr[j] {
+				curr[j] = v
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
+
 // TODO: V4 Remove this.
 func sanitiseConditionConfig(raw interface{}, removeDeprecated bool) error {
 	// This is a nasty hack until Benthos v4.
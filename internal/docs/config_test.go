@@ -10,6 +10,33 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+func TestValidateLabelWithPolicy(t *testing.T) {
+	tests := []struct {
+		label  string
+		policy docs.LabelPolicy
+		err    string
+	}{
+		{label: "foo_bar", policy: docs.LabelPolicy{}},
+		{label: "fooBar", policy: docs.LabelPolicy{}, err: docs.ErrBadLabel.Error()},
+		{label: "_foo", policy: docs.LabelPolicy{}, err: docs.ErrBadLabel.Error()},
+		{label: "fooBar", policy: docs.LabelPolicy{AllowUppercase: true}},
+		{label: "foo-bar", policy: docs.LabelPolicy{AllowUppercase: true}, err: "should match the regular expression /^[a-zA-Z0-9_]+$/ and must not start with an underscore"},
+		{label: "foo-bar", policy: docs.LabelPolicy{AllowHyphens: true}},
+		{label: "_foo", policy: docs.LabelPolicy{AllowLeadingUnderscore: true}},
+		{label: "foo.bar", policy: docs.LabelPolicy{AllowLeadingUnderscore: true}, err: "should match the regular expression /^[a-z0-9_]+$/"},
+		{label: "", policy: docs.LabelPolicy{AllowHyphens: true}, err: "should match the regular expression /^[a-z0-9_-]+$/ and must not start with an underscore"},
+	}
+
+	for _, test := range tests {
+		err := docs.ValidateLabelWithPolicy(test.label, test.policy)
+		if test.err == "" {
+			assert.NoError(t, err, test.label)
+		} else {
+			assert.EqualError(t, err, test.err, test.label)
+		}
+	}
+}
+
 func TestInference(t *testing.T) {
 	docsProv := docs.NewMappedDocsProvider()
 	for _, t := range docs.Types() {
@@ -59,6 +86,29 @@ func TestInference(t *testing.T) {
 			},
 			err: "unable to infer input type, candidates were: [bar foo]",
 		},
+		{
+			inputType: docs.TypeInput,
+			inputConf: map[string]interface{}{
+				"label":        "meow",
+				"testfooinput": "baz",
+			},
+			res: "testfooinput",
+		},
+		{
+			inputType: docs.TypeInput,
+			inputConf: map[string]interface{}{
+				"label":       "meow",
+				"testfoinput": "baz",
+			},
+			err: "unable to infer input type, candidates were: [testfoinput], did you mean 'testfooinput'?",
+		},
+		{
+			inputType: docs.TypeProcessor,
+			inputConf: map[string]interface{}{
+				"nothinglikeit": "baz",
+			},
+			err: "unable to infer processor type, candidates were: [nothinglikeit]",
+		},
 		{
 			inputType: docs.TypeTracer,
 			inputConf: map[string]interface{}{
@@ -177,6 +227,68 @@ func TestInference(t *testing.T) {
 	}
 }
 
+func TestInferenceWithLabel(t *testing.T) {
+	docsProv := docs.NewMappedDocsProvider()
+	for _, t := range docs.Types() {
+		docsProv.RegisterDocs(docs.ComponentSpec{
+			Name: fmt.Sprintf("testfoo%v", string(t)),
+			Type: t,
+		})
+	}
+
+	for _, cType := range []docs.Type{
+		docs.TypeInput,
+		docs.TypeProcessor,
+		docs.TypeOutput,
+		docs.TypeCache,
+		docs.TypeRateLimit,
+	} {
+		name := fmt.Sprintf("testfoo%v", string(cType))
+
+		confs := []map[string]interface{}{
+			{"label": "meow", name: "baz"},
+			{name: "baz", "label": "meow"},
+			{"label": "", name: "baz"},
+			{"label": "meow", name: map[string]interface{}{}, "plugin": map[string]interface{}{}},
+		}
+		if cType == docs.TypeInput || cType == docs.TypeOutput {
+			confs = append(confs, map[string]interface{}{
+				"label": "meow", name: "baz", "processors": []interface{}{}, "max_message_bytes": 10,
+			})
+		}
+		if cType == docs.TypeInput {
+			confs = append(confs, map[string]interface{}{
+				"label": "meow", name: "baz", "rate_limit": "foo", "message_id": map[string]interface{}{},
+			})
+		}
+
+		for i, conf := range confs {
+			res, spec, err := docs.GetInferenceCandidate(docsProv, cType, "", conf)
+			require.NoError(t, err, "%v: %v", cType, i)
+			assert.Equal(t, name, res, "%v: %v", cType, i)
+			assert.Equal(t, name, spec.Name, "%v: %v", cType, i)
+
+			var node yaml.Node
+			require.NoError(t, node.Encode(conf))
+			res, spec, err = docs.GetInferenceCandidateFromYAML(docsProv, cType, "", &node)
+			require.NoError(t, err, "%v: %v", cType, i)
+			assert.Equal(t, name, res, "%v: %v", cType, i)
+			assert.Equal(t, name, spec.Name, "%v: %v", cType, i)
+		}
+
+		typoConf := map[string]interface{}{"label": "meow", name + "s": "baz"}
+		expErr := fmt.Sprintf("unable to infer %v type, candidates were: [%vs], did you mean '%v'?", cType, name, name)
+
+		_, _, err := docs.GetInferenceCandidate(docsProv, cType, "", typoConf)
+		assert.EqualError(t, err, expErr)
+
+		var node yaml.Node
+		require.NoError(t, node.Encode(typoConf))
+		_, _, err = docs.GetInferenceCandidateFromYAML(docsProv, cType, "", &node)
+		assert.EqualError(t, err, expErr)
+	}
+}
+
 func TestSanitation(t *testing.T) {
 	for _, t := range docs.Types() {
 		docs.RegisterDocs(docs.ComponentSpec{
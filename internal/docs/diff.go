@@ -0,0 +1,252 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ConfigDiffKind describes the kind of difference found between two configs.
+type ConfigDiffKind string
+
+// ConfigDiffKind variants.
+const (
+	ConfigDiffAdded   ConfigDiffKind = "added"
+	ConfigDiffRemoved ConfigDiffKind = "removed"
+	ConfigDiffChanged ConfigDiffKind = "changed"
+)
+
+// ConfigDiff describes a field level difference between two configs.
+type ConfigDiff struct {
+	Kind ConfigDiffKind `json:"kind"`
+
+	// Path is a dot separated path to the field, where array elements are
+	// identified by their index.
+	Path string `json:"path"`
+
+	// From is the value of the field in the first config, which is nil for
+	// added fields.
+	From interface{} `json:"from,omitempty"`
+
+	// To is the value of the field in the second config, which is nil for
+	// removed fields.
+	To interface{} `json:"to,omitempty"`
+}
+
+// DiffConfigs compares two raw component configurations semantically and
+// returns the differences between them ordered by path. Both configs are
+// sanitised and have any missing fields filled with their default values
+// before being compared, and therefore differences in field ordering, omitted
+// defaults or fields that are not relevant to the component are not reported.
+// The provided configs are not modified.
+func DiffConfigs(a, b interface{}, ctype Type) ([]ConfigDiff, error) {
+	normA, err := normaliseComponentConfig(ctype, a)
+	if err != nil {
+		return nil, fmt.Errorf("first config: %w", err)
+	}
+	normB, err := normaliseComponentConfig(ctype, b)
+	if err != nil {
+		return nil, fmt.Errorf("second config: %w", err)
+	}
+	var diffs []ConfigDiff
+	diffValues("", normA, normB, &diffs)
+	return diffs, nil
+}
+
+func normaliseComponentConfig(ctype Type, raw interface{}) (interface{}, error) {
+	conf, err := jsonRoundTrip(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := SanitiseComponentConfig(ctype, conf, nil); err != nil {
+		return nil, err
+	}
+	if err := fillComponentDefaults(ctype, conf); err != nil {
+		return nil, err
+	}
+	// Default values are added with their Go types and are therefore also
+	// normalised into generic JSON types.
+	return jsonRoundTrip(conf)
+}
+
+// jsonRoundTrip deep copies a generic value whilst normalising it into the
+// types produced when parsing JSON documents.
+func jsonRoundTrip(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalise config: %w", err)
+	}
+	var res interface{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("failed to normalise config: %w", err)
+	}
+	return res, nil
+}
+
+//------------------------------------------------------------------------------
+
+func fillComponentDefaults(componentType Type, raw interface{}) error {
+	name, spec, err := GetInferenceCandidate(globalProvider, componentType, "", raw)
+	if err != nil {
+		return err
+	}
+
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected object configuration type, found: %T", raw)
+	}
+
+	// The type is implied by the component field.
+	delete(m, "type")
+
+	if _, exists := m[name]; !exists {
+		if v, ok := spec.Config.emptyValue(); ok {
+			m[name] = v
+		}
+	}
+	spec.Config.fillDefaults(m[name])
+
+	for k, fieldSpec := range reservedFieldsByType(componentType) {
+		if v, exists := m[k]; exists {
+			fieldSpec.fillDefaults(v)
+		}
+	}
+	if label, _ := m["label"].(string); label == "" {
+		delete(m, "label")
+	}
+	return nil
+}
+
+// emptyValue returns the value a field should be given when it is missing from
+// a config, which is its default when one exists.
+func (f FieldSpec) emptyValue() (interface{}, bool) {
+	if f.Default != nil {
+		return *f.Default, true
+	}
+	if len(f.Children) == 0 {
+		return nil, false
+	}
+	switch f.Kind {
+	case KindArray, Kind2DArray:
+		return []interface{}{}, true
+	}
+	return map[string]interface{}{}, true
+}
+
+func (f FieldSpec) fillDefaults(s interface{}) {
+	if coreType, isCore := f.Type.IsCoreComponent(); isCore {
+		switch f.Kind {
+		case KindArray:
+			if arr, ok := s.([]interface{}); ok {
+				for _, ele := range arr {
+					_ = fillComponentDefaults(coreType, ele)
+				}
+			}
+		case KindMap:
+			if obj, ok := s.(map[string]interface{}); ok {
+				for _, v := range obj {
+					_ = fillComponentDefaults(coreType, v)
+				}
+			}
+		default:
+			_ = fillComponentDefaults(coreType, s)
+		}
+	} else if len(f.Children) > 0 {
+		switch f.Kind {
+		case KindArray:
+			if arr, ok := s.([]interface{}); ok {
+				for _, ele := range arr {
+					f.Children.fillDefaults(ele)
+				}
+			}
+		case KindMap:
+			if obj, ok := s.(map[string]interface{}); ok {
+				for _, v := range obj {
+					f.Children.fillDefaults(v)
+				}
+			}
+		default:
+			f.Children.fillDefaults(s)
+		}
+	}
+}
+
+func (f FieldSpecs) fillDefaults(s interface{}) {
+	m, ok := s.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, spec := range f {
+		v, exists := m[spec.Name]
+		if !exists {
+			if v, exists = spec.emptyValue(); !exists {
+				continue
+			}
+			m[spec.Name] = v
+			if spec.Default != nil {
+				continue
+			}
+		}
+		spec.fillDefaults(v)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func diffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func diffValues(path string, a, b interface{}, diffs *[]ConfigDiff) {
+	switch aT := a.(type) {
+	case map[string]interface{}:
+		if bT, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(aT)+len(bT))
+			for k := range aT {
+				keys = append(keys, k)
+			}
+			for k := range bT {
+				if _, exists := aT[k]; !exists {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				aV, aExists := aT[k]
+				bV, bExists := bT[k]
+				switch {
+				case !aExists:
+					*diffs = append(*diffs, ConfigDiff{Kind: ConfigDiffAdded, Path: diffPath(path, k), To: bV})
+				case !bExists:
+					*diffs = append(*diffs, ConfigDiff{Kind: ConfigDiffRemoved, Path: diffPath(path, k), From: aV})
+				default:
+					diffValues(diffPath(path, k), aV, bV, diffs)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bT, ok := b.([]interface{}); ok {
+			for i := 0; i < len(aT) || i < len(bT); i++ {
+				p := diffPath(path, strconv.Itoa(i))
+				switch {
+				case i >= len(aT):
+					*diffs = append(*diffs, ConfigDiff{Kind: ConfigDiffAdded, Path: p, To: bT[i]})
+				case i >= len(bT):
+					*diffs = append(*diffs, ConfigDiff{Kind: ConfigDiffRemoved, Path: p, From: aT[i]})
+				default:
+					diffValues(p, aT[i], bT[i], diffs)
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, ConfigDiff{Kind: ConfigDiffChanged, Path: path, From: a, To: b})
+	}
+}
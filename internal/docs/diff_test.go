@@ -0,0 +1,190 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testdifffooinput",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("foo1", "").HasDefault("foo1default"),
+			docs.FieldInt("foo2", "").HasDefault(10),
+			docs.FieldCommon("foo3", "").WithChildren(
+				docs.FieldBool("enabled", "").HasDefault(false),
+				docs.FieldString("value", "").HasDefault(""),
+			),
+		),
+	})
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testdiffbarinput",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("bar1", "").HasDefault("bar1default"),
+		),
+	})
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testdifffooprocessor",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("baz1", "").HasDefault("baz1default"),
+			docs.FieldInt("baz2", "").HasDefault(5),
+		),
+	})
+
+	tests := []struct {
+		name string
+		a, b interface{}
+		res  []docs.ConfigDiff
+	}{
+		{
+			name: "defaults and type are normalised",
+			a: map[string]interface{}{
+				"type": "testdifffooinput",
+				"testdifffooinput": map[string]interface{}{
+					"foo2": 10,
+				},
+				"processors": []interface{}{
+					map[string]interface{}{
+						"testdifffooprocessor": map[string]interface{}{},
+					},
+				},
+			},
+			b: map[string]interface{}{
+				"type":  "testdifffooinput",
+				"label": "",
+				"testdifffooinput": map[string]interface{}{
+					"foo1": "foo1default",
+					"foo3": map[string]interface{}{
+						"enabled": false,
+					},
+				},
+				"testdiffbarinput": map[string]interface{}{
+					"bar1": "ignored",
+				},
+				"processors": []interface{}{
+					map[string]interface{}{
+						"testdifffooprocessor": map[string]interface{}{
+							"baz2": 5.0,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "field changes",
+			a: map[string]interface{}{
+				"testdifffooinput": map[string]interface{}{
+					"foo1": "first",
+				},
+				"processors": []interface{}{
+					map[string]interface{}{
+						"testdifffooprocessor": map[string]interface{}{},
+					},
+				},
+			},
+			b: map[string]interface{}{
+				"label": "foo",
+				"testdifffooinput": map[string]interface{}{
+					"foo1": "second",
+					"foo3": map[string]interface{}{
+						"enabled": true,
+					},
+				},
+				"processors": []interface{}{
+					map[string]interface{}{
+						"testdifffooprocessor": map[string]interface{}{
+							"baz2": 6,
+						},
+					},
+					map[string]interface{}{
+						"testdifffooprocessor": map[string]interface{}{},
+					},
+				},
+			},
+			res: []docs.ConfigDiff{
+				{Kind: docs.ConfigDiffAdded, Path: "label", To: "foo"},
+				{Kind: docs.ConfigDiffChanged, Path: "processors.0.testdifffooprocessor.baz2", From: 5.0, To: 6.0},
+				{
+					Kind: docs.ConfigDiffAdded, Path: "processors.1",
+					To: map[string]interface{}{
+						"testdifffooprocessor": map[string]interface{}{
+							"baz1": "baz1default",
+							"baz2": 5.0,
+						},
+					},
+				},
+				{Kind: docs.ConfigDiffChanged, Path: "testdifffooinput.foo1", From: "first", To: "second"},
+				{Kind: docs.ConfigDiffChanged, Path: "testdifffooinput.foo3.enabled", From: false, To: true},
+			},
+		},
+		{
+			name: "component changes",
+			a: map[string]interface{}{
+				"testdifffooinput": map[string]interface{}{},
+			},
+			b: map[string]interface{}{
+				"type": "testdiffbarinput",
+			},
+			res: []docs.ConfigDiff{
+				{
+					Kind: docs.ConfigDiffAdded, Path: "testdiffbarinput",
+					To: map[string]interface{}{
+						"bar1": "bar1default",
+					},
+				},
+				{
+					Kind: docs.ConfigDiffRemoved, Path: "testdifffooinput",
+					From: map[string]interface{}{
+						"foo1": "foo1default",
+						"foo2": 10.0,
+						"foo3": map[string]interface{}{
+							"enabled": false,
+							"value":   "",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			diffs, err := docs.DiffConfigs(test.a, test.b, docs.TypeInput)
+			require.NoError(t, err)
+			assert.Equal(t, test.res, diffs)
+		})
+	}
+}
+
+func TestDiffConfigsDoesNotMutate(t *testing.T) {
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testdiffmutinput",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("foo1", "").HasDefault("foo1default"),
+		),
+	})
+
+	a := map[string]interface{}{
+		"type":             "testdiffmutinput",
+		"testdiffmutinput": map[string]interface{}{},
+		"unrelated":        "field",
+	}
+	_, err := docs.DiffConfigs(a, map[string]interface{}{}, docs.TypeInput)
+	require.Error(t, err)
+
+	_, err = docs.DiffConfigs(a, a, docs.TypeInput)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"type":             "testdiffmutinput",
+		"testdiffmutinput": map[string]interface{}{},
+		"unrelated":        "field",
+	}, a)
+}
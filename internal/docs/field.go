@@ -116,6 +116,12 @@ type FieldSpec struct {
 	// for backwards compatibility reasons.
 	IsDeprecated bool `json:"is_deprecated"`
 
+	// Replacement is the dot separated path, relative to the parent object,
+	// of a field that replaces this deprecated field. When set the value of
+	// this field is moved to the replacement during migration and
+	// sanitisation rather than being dropped.
+	Replacement string `json:"replacement,omitempty"`
+
 	// IsOptional is a boolean flag indicating that a field is optional, even
 	// if there is no default. This prevents linting errors when the field
 	// is missing.
@@ -143,15 +149,20 @@ type FieldSpec struct {
 	// Version is an explicit version when this field was introduced.
 	Version string `json:"version,omitempty"`
 
-	omitWhenFn   func(field, parent interface{}) (why string, shouldOmit bool)
-	customLintFn LintFunc
-	skipLint     bool
+	// Group is an optional name of a group of related fields, which is used
+	// for organising fields within documentation.
+	Group string `json:"group,omitempty"`
+
+	omitWhenFn    func(field, parent interface{}) (why string, shouldOmit bool)
+	customLintFn  LintWithParentFunc
+	skipLint      bool
+	exclusiveWith []string
 }
 
 // IsInterpolated indicates that the field supports interpolation functions.
 func (f FieldSpec) IsInterpolated() FieldSpec {
 	f.Interpolated = true
-	f.customLintFn = LintBloblangField
+	f.customLintFn = lintFuncIgnoreParent(LintBloblangField)
 	return f
 }
 
@@ -211,6 +222,20 @@ func (f FieldSpec) AtVersion(v string) FieldSpec {
 	return f
 }
 
+// ReplacedBy specifies the dot separated path, relative to the parent object,
+// of a field that replaces this deprecated field.
+func (f FieldSpec) ReplacedBy(path string) FieldSpec {
+	f.Replacement = path
+	return f
+}
+
+// InGroup specifies the name of a group of related fields that this field
+// belongs to, fields are listed under their groups within documentation.
+func (f FieldSpec) InGroup(name string) FieldSpec {
+	f.Group = name
+	return f
+}
+
 // HasAnnotatedOptions returns a new FieldSpec that specifies a specific list of
 // annotated options. Either
 func (f FieldSpec) HasAnnotatedOptions(options ...string) FieldSpec {
@@ -254,10 +279,28 @@ func (f FieldSpec) OmitWhen(fn func(field, parent interface{}) (why string, shou
 	return f
 }
 
+// ExclusiveWith specifies sibling fields that must not be set alongside this
+// field. When linting is performed on a config a linting error is returned for
+// each object where more than one of the fields are populated.
+func (f FieldSpec) ExclusiveWith(names ...string) FieldSpec {
+	f.exclusiveWith = append(append([]string{}, f.exclusiveWith...), names...)
+	return f
+}
+
 // Linter adds a linting function to a field. When linting is performed on a
 // config the provided function will be called with a boxed variant of the field
 // value, allowing it to perform linting on that value.
 func (f FieldSpec) Linter(fn LintFunc) FieldSpec {
+	f.customLintFn = lintFuncIgnoreParent(fn)
+	return f
+}
+
+// LinterWithParent adds a linting function to a field that, in addition to a
+// boxed variant of the field value, is provided a boxed variant of the object
+// containing the field, in the same form as the parent provided to OmitWhen.
+// This allows the linting function to check the value against the values of
+// sibling fields. The parent is nil when it could not be parsed.
+func (f FieldSpec) LinterWithParent(fn LintWithParentFunc) FieldSpec {
 	f.customLintFn = fn
 	return f
 }
@@ -269,10 +312,10 @@ func (f FieldSpec) Linter(fn LintFunc) FieldSpec {
 //
 // TODO: V4 Switch this to opt-out.
 func (f FieldSpec) LintOptions() FieldSpec {
-	f.customLintFn = func(ctx LintContext, line, col int, value interface{}) []Lint {
+	f.customLintFn = func(ctx LintContext, line, col int, value, _ interface{}) []Lint {
 		str, ok := value.(string)
 		if !ok {
-			return []Lint{NewLintWarning(line, fmt.Sprintf("expected string value, got %T", value))}
+			return []Lint{NewTypedLintWarning(line, LintExpectedScalar, fmt.Sprintf("expected string value, got %T", value))}
 		}
 		if len(f.Options) > 0 {
 			for _, optStr := range f.Options {
@@ -287,7 +330,7 @@ func (f FieldSpec) LintOptions() FieldSpec {
 				}
 			}
 		}
-		return []Lint{NewLintError(line, fmt.Sprintf("value %v is not a valid option for this field", str))}
+		return []Lint{NewTypedLintError(line, LintInvalidOption, fmt.Sprintf("value %v is not a valid option for this field", str))}
 	}
 	return f
 }
@@ -421,6 +464,19 @@ func (f FieldSpecs) Add(specs ...FieldSpec) FieldSpecs {
 	return append(f, specs...)
 }
 
+// InGroup returns a copy of the field specs where each field without a group
+// is placed within the named group.
+func (f FieldSpecs) InGroup(name string) FieldSpecs {
+	grouped := make(FieldSpecs, len(f))
+	for i, spec := range f {
+		if spec.Group == "" {
+			spec.Group = name
+		}
+		grouped[i] = spec
+	}
+	return grouped
+}
+
 // FieldFilter defines a filter closure that returns a boolean for a component
 // field indicating whether the field should be kept within a generated config.
 type FieldFilter func(spec FieldSpec) bool
@@ -448,6 +504,11 @@ func (f FieldSpecs) sanitise(s interface{}, filter FieldFilter) {
 	if !ok {
 		return
 	}
+	for _, spec := range f {
+		if filter.shouldDrop(spec) {
+			spec.moveToReplacement(m)
+		}
+	}
 	for _, spec := range f {
 		if filter.shouldDrop(spec) {
 			delete(m, spec.Name)
@@ -470,21 +531,79 @@ type LintContext struct {
 	// A map of label names to the line they were defined at.
 	LabelsToLine map[string]int
 
+	// A map of label names to the file they were defined within, labels
+	// defined without a file are absent.
+	LabelsToFile map[string]string
+
+	// File is the path of the config file currently being linted, which is
+	// empty when the config does not originate from a file.
+	File string
+
+	// LabelPolicy determines the characters permitted within labels.
+	LabelPolicy LabelPolicy
+
 	// DocsProvider provides documentation for component implementations.
 	DocsProvider Provider
+
+	// WarnDeprecated enables warnings for deprecated fields and components
+	// that are set within a config.
+	WarnDeprecated bool
 }
 
 // NewLintContext creates a new linting context.
 func NewLintContext() LintContext {
 	return LintContext{
 		LabelsToLine: map[string]int{},
+		LabelsToFile: map[string]string{},
 		DocsProvider: globalProvider,
 	}
 }
 
+// NewLintContextWithLabels creates a new linting context pre-seeded with a map
+// of label names to the lines they were defined at within a given file, in
+// order to detect collisions with labels defined in other files.
+func NewLintContextWithLabels(file string, labels map[string]int) LintContext {
+	ctx := NewLintContext()
+	ctx.MergeLabels(file, labels)
+	return ctx
+}
+
+// WithFile returns a copy of the linting context for linting the config of a
+// given file. The copy shares labels with the original context, and therefore a
+// single context can be used across multiple files.
+func (ctx LintContext) WithFile(path string) LintContext {
+	ctx.File = path
+	return ctx
+}
+
+// MergeLabels adds a map of label names to the lines they were defined at
+// within a given file to the context. Labels that are already known to the
+// context are not overridden.
+func (ctx LintContext) MergeLabels(file string, labels map[string]int) {
+	for k, v := range labels {
+		if _, exists := ctx.LabelsToLine[k]; exists {
+			continue
+		}
+		ctx.LabelsToLine[k] = v
+		if file != "" && ctx.LabelsToFile != nil {
+			ctx.LabelsToFile[k] = file
+		}
+	}
+}
+
 // LintFunc is a common linting function for field values.
 type LintFunc func(ctx LintContext, line, col int, value interface{}) []Lint
 
+// LintWithParentFunc is a linting function for field values that also receives
+// the object containing the field, allowing it to check sibling fields.
+type LintWithParentFunc func(ctx LintContext, line, col int, value, parent interface{}) []Lint
+
+func lintFuncIgnoreParent(fn LintFunc) LintWithParentFunc {
+	return func(ctx LintContext, line, col int, value, _ interface{}) []Lint {
+		return fn(ctx, line, col, value)
+	}
+}
+
 // LintLevel describes the severity level of a linting error.
 type LintLevel int
 
@@ -494,22 +613,131 @@ const (
 	LintWarning LintLevel = iota
 )
 
+// MarshalText returns the name of a lint level.
+func (l LintLevel) MarshalText() ([]byte, error) {
+	if l == LintWarning {
+		return []byte("warning"), nil
+	}
+	return []byte("error"), nil
+}
+
+// LintType is a machine readable code describing the category of a linting
+// error, allowing lints to be filtered without matching their description.
+type LintType int
+
+// Lint types
+const (
+	// LintUnknown means the type of a lint was not specified.
+	LintUnknown LintType = iota
+
+	// LintCustom means a custom linting rule failed.
+	LintCustom
+
+	// LintFailedRead means a config could not be read.
+	LintFailedRead
+
+	// LintInvalidOption means a field value was not one of its options.
+	LintInvalidOption
+
+	// LintBadLabel means a label is invalid.
+	LintBadLabel
+
+	// LintDuplicateLabel means a label collides with another label.
+	LintDuplicateLabel
+
+	// LintBadBloblang means a field contains invalid Bloblang.
+	LintBadBloblang
+
+	// LintShouldOmit means a field should be omitted.
+	LintShouldOmit
+
+	// LintComponentNotFound means the type of a component could not be
+	// determined or has no documentation.
+	LintComponentNotFound
+
+	// LintUnknownField means a field is not recognised.
+	LintUnknownField
+
+	// LintMissingField means a required field is missing.
+	LintMissingField
+
+	// LintExpectedArray means an array value was expected but not found.
+	LintExpectedArray
+
+	// LintExpectedObject means an object value was expected but not found.
+	LintExpectedObject
+
+	// LintExpectedScalar means a scalar value was expected but not found.
+	LintExpectedScalar
+
+	// LintMutuallyExclusive means fields that cannot be set together were
+	// set.
+	LintMutuallyExclusive
+
+	// LintDeprecated means a deprecated field or component was used.
+	LintDeprecated
+)
+
+var lintTypeNames = map[LintType]string{
+	LintUnknown:           "unknown",
+	LintCustom:            "custom",
+	LintFailedRead:        "failed_read",
+	LintInvalidOption:     "invalid_option",
+	LintBadLabel:          "bad_label",
+	LintDuplicateLabel:    "duplicate_label",
+	LintBadBloblang:       "bad_bloblang",
+	LintShouldOmit:        "should_omit",
+	LintComponentNotFound: "component_not_found",
+	LintUnknownField:      "unknown_field",
+	LintMissingField:      "missing_field",
+	LintExpectedArray:     "expected_array",
+	LintExpectedObject:    "expected_object",
+	LintExpectedScalar:    "expected_scalar",
+	LintMutuallyExclusive: "mutually_exclusive",
+	LintDeprecated:        "deprecated",
+}
+
+// String returns the code of a lint type, which is the form used when lints
+// are serialised.
+func (t LintType) String() string {
+	if name, exists := lintTypeNames[t]; exists {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalText returns the code of a lint type.
+func (t LintType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
 // Lint describes a single linting issue found with a Benthos config.
 type Lint struct {
-	Line   int
-	Column int // Optional, omitted from lint report unless >= 1
-	Level  LintLevel
-	What   string
+	Line   int       `json:"line"`
+	Column int       `json:"column,omitempty"` // Optional, omitted from lint report unless >= 1
+	Level  LintLevel `json:"level"`
+	Type   LintType  `json:"type"`
+	What   string    `json:"what"`
 }
 
-// NewLintError returns an error lint.
+// NewLintError returns an error lint of the custom type.
 func NewLintError(line int, msg string) Lint {
-	return Lint{Line: line, Level: LintError, What: msg}
+	return NewTypedLintError(line, LintCustom, msg)
 }
 
-// NewLintWarning returns a warning lint.
+// NewLintWarning returns a warning lint of the custom type.
 func NewLintWarning(line int, msg string) Lint {
-	return Lint{Line: line, Level: LintWarning, What: msg}
+	return NewTypedLintWarning(line, LintCustom, msg)
+}
+
+// NewTypedLintError returns an error lint of a given type.
+func NewTypedLintError(line int, t LintType, msg string) Lint {
+	return Lint{Line: line, Level: LintError, Type: t, What: msg}
+}
+
+// NewTypedLintWarning returns a warning lint of a given type.
+func NewTypedLintWarning(line int, t LintType, msg string) Lint {
+	return Lint{Line: line, Level: LintWarning, Type: t, What: msg}
 }
 
 //------------------------------------------------------------------------------
@@ -23,45 +23,106 @@ type FieldSpecCtx struct {
 	DefaultMarshalled string
 }
 
+// FieldGroupCtx provides a named group of fields for documentation templates
+// to use.
+type FieldGroupCtx struct {
+	Name   string
+	Fields []FieldSpecCtx
+}
+
+// defaultFieldGroup is the name of the group in which fields without an
+// explicit group are documented when other fields of a component are grouped.
+const defaultFieldGroup = "General"
+
+// GroupFieldsForDocs arranges a flat list of fields into groups following the
+// order in which each group first appears, where fields without a group are
+// placed within the group "General". If none of the fields have a group then
+// nil is returned.
+func GroupFieldsForDocs(fields []FieldSpecCtx) []FieldGroupCtx {
+	grouped := false
+	for _, f := range fields {
+		if f.Spec.Group != "" {
+			grouped = true
+			break
+		}
+	}
+	if !grouped {
+		return nil
+	}
+
+	var groups []FieldGroupCtx
+	groupIndexes := map[string]int{}
+	for _, f := range fields {
+		name := f.Spec.Group
+		if name == "" {
+			name = defaultFieldGroup
+		}
+		i, exists := groupIndexes[name]
+		if !exists {
+			i = len(groups)
+			groupIndexes[name] = i
+			groups = append(groups, FieldGroupCtx{Name: name})
+		}
+		groups[i].Fields = append(groups[i].Fields, f)
+	}
+	return groups
+}
+
 // FieldsTemplate returns a Go template for rendering markdown field
 // documentation. The context should have a field `.Fields` of the type
-// `[]FieldSpecCtx`.
+// `[]FieldSpecCtx`, and in order to render fields under group headings with
+// the template "field_group_docs" a field `.FieldGroups` of the type
+// `[]FieldGroupCtx`.
 func FieldsTemplate(lintableExamples bool) string {
 	exampleHint := "yml"
 	if lintableExamples {
 		exampleHint = "yaml"
 	}
-	return `{{define "field_docs" -}}
-{{range $i, $field := .Fields -}}
-### ` + "`{{$field.FullName}}`" + `
-
-{{$field.Spec.Description}}
-{{if $field.Spec.Interpolated -}}
+	return `{{define "field_doc" -}}
+{{$.Spec.Description}}
+{{if $.Spec.Interpolated -}}
 This field supports [interpolation functions](/docs/configuration/interpolation#bloblang-queries).
 {{end}}
 
-Type: {{if eq $field.Spec.Kind "array"}}list of {{end}}{{if eq $field.Spec.Kind "map"}}map of {{end}}` + "`{{$field.Spec.Type}}`" + `  
-{{if gt (len $field.DefaultMarshalled) 0}}Default: ` + "`{{$field.DefaultMarshalled}}`" + `  
+Type: {{if eq $.Spec.Kind "array"}}list of {{end}}{{if eq $.Spec.Kind "map"}}map of {{end}}` + "`{{$.Spec.Type}}`" + `  
+{{if gt (len $.DefaultMarshalled) 0}}Default: ` + "`{{$.DefaultMarshalled}}`" + `  
 {{end -}}
-{{if gt (len $field.Spec.Version) 0}}Requires version {{$field.Spec.Version}} or newer  
+{{if gt (len $.Spec.Version) 0}}Requires version {{$.Spec.Version}} or newer  
 {{end -}}
-{{if gt (len $field.Spec.AnnotatedOptions) 0}}
+{{if gt (len $.Spec.AnnotatedOptions) 0}}
 | Option | Summary |
 |---|---|
-{{range $j, $option := $field.Spec.AnnotatedOptions -}}` + "| `" + `{{index $option 0}}` + "` |" + ` {{index $option 1}} |
+{{range $j, $option := $.Spec.AnnotatedOptions -}}` + "| `" + `{{index $option 0}}` + "` |" + ` {{index $option 1}} |
 {{end}}
-{{else if gt (len $field.Spec.Options) 0}}Options: {{range $j, $option := $field.Spec.Options -}}
+{{else if gt (len $.Spec.Options) 0}}Options: {{range $j, $option := $.Spec.Options -}}
 {{if ne $j 0}}, {{end}}` + "`" + `{{$option}}` + "`" + `{{end}}.
 {{end}}
-{{if gt (len $field.Spec.Examples) 0 -}}
+{{if gt (len $.Spec.Examples) 0 -}}
 ` + "```" + exampleHint + `
 # Examples
 
-{{range $j, $example := $field.ExamplesMarshalled -}}
+{{range $j, $example := $.ExamplesMarshalled -}}
 {{if ne $j 0}}
 {{end}}{{$example}}{{end -}}
 ` + "```" + `
 
+{{end -}}
+{{end -}}
+{{define "field_docs" -}}
+{{range $i, $field := .Fields -}}
+### ` + "`{{$field.FullName}}`" + `
+
+{{template "field_doc" $field -}}
+{{end -}}
+{{end -}}
+{{define "field_group_docs" -}}
+{{range $i, $group := .FieldGroups -}}
+### {{$group.Name}}
+
+{{range $j, $field := $group.Fields -}}
+#### ` + "`{{$field.FullName}}`" + `
+
+{{template "field_doc" $field -}}
 {{end -}}
 {{end -}}
 {{end -}}`
@@ -72,12 +133,16 @@ Type: {{if eq $field.Spec.Kind "array"}}list of {{end}}{{if eq $field.Spec.Kind
 // This makes it easier to list the fields in documentation.
 func (f FieldSpec) FlattenChildrenForDocs() []FieldSpecCtx {
 	flattenedFields := []FieldSpecCtx{}
-	var walkFields func(path string, f FieldSpecs)
-	walkFields = func(path string, f FieldSpecs) {
+	var walkFields func(path, group string, f FieldSpecs)
+	walkFields = func(path, group string, f FieldSpecs) {
 		for _, v := range f {
 			if v.IsDeprecated {
 				continue
 			}
+			if v.Group == "" {
+				// Children are documented within the group of their parent.
+				v.Group = group
+			}
 			newV := FieldSpecCtx{
 				Spec: v,
 			}
@@ -115,7 +180,7 @@ func (f FieldSpec) FlattenChildrenForDocs() []FieldSpecCtx {
 				case KindMap:
 					newPath += ".<name>"
 				}
-				walkFields(newPath+".", v.Children)
+				walkFields(newPath+".", v.Group, v.Children)
 			}
 		}
 	}
@@ -126,6 +191,6 @@ func (f FieldSpec) FlattenChildrenForDocs() []FieldSpecCtx {
 	case KindMap:
 		rootPath = "<name>."
 	}
-	walkFields(rootPath, f.Children)
+	walkFields(rootPath, "", f.Children)
 	return flattenedFields
 }
@@ -0,0 +1,46 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupFieldsForDocs(t *testing.T) {
+	spec := FieldComponent().WithChildren(
+		FieldCommon("a", "").InGroup("Connection"),
+		FieldCommon("b", ""),
+		FieldCommon("c", "").InGroup("Messages"),
+		FieldCommon("d", "").InGroup("Connection").WithChildren(
+			FieldCommon("e", ""),
+			FieldCommon("f", "").InGroup("Messages"),
+		),
+		FieldDeprecated("g").InGroup("Deprecated"),
+	).WithChildren(FieldSpecs{
+		FieldCommon("h", ""),
+		FieldCommon("i", "").InGroup("Other"),
+	}.InGroup("Messages")...)
+
+	var groups []string
+	names := map[string][]string{}
+	for _, g := range GroupFieldsForDocs(spec.FlattenChildrenForDocs()) {
+		groups = append(groups, g.Name)
+		for _, f := range g.Fields {
+			names[g.Name] = append(names[g.Name], f.FullName)
+		}
+	}
+
+	assert.Equal(t, []string{"Connection", "General", "Messages", "Other"}, groups)
+	assert.Equal(t, map[string][]string{
+		"Connection": {"a", "d", "d.e"},
+		"General":    {"b"},
+		"Messages":   {"c", "d.f", "h"},
+		"Other":      {"i"},
+	}, names)
+
+	ungrouped := FieldComponent().WithChildren(
+		FieldCommon("a", ""),
+		FieldCommon("b", ""),
+	)
+	assert.Nil(t, GroupFieldsForDocs(ungrouped.FlattenChildrenForDocs()))
+}
@@ -1,25 +1,55 @@
 package docs
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
 // JSONSchema serializes a field spec into a JSON schema structure.
 func (f FieldSpec) JSONSchema() interface{} {
+	spec, ok := f.jsonSchemaType().(map[string]interface{})
+	if !ok {
+		return true
+	}
+	if f.Description != "" {
+		spec["description"] = f.Description
+	}
+	if f.Default != nil {
+		spec["default"] = *f.Default
+	}
+	if len(f.Examples) > 0 {
+		spec["examples"] = f.Examples
+	}
+	if f.IsDeprecated {
+		spec["deprecated"] = true
+	}
+	return spec
+}
+
+func (f FieldSpec) jsonSchemaType() interface{} {
 	spec := map[string]interface{}{}
 	switch f.Kind {
 	case Kind2DArray:
 		innerField := f
 		innerField.Kind = KindArray
 		spec["type"] = "array"
-		spec["items"] = innerField.JSONSchema()
+		spec["items"] = innerField.jsonSchemaType()
 	case KindArray:
 		innerField := f
 		innerField.Kind = KindScalar
 		spec["type"] = "array"
-		spec["items"] = innerField.JSONSchema()
+		spec["items"] = innerField.jsonSchemaType()
 	case KindMap:
 		innerField := f
 		innerField.Kind = KindScalar
 		spec["type"] = "object"
 		spec["patternProperties"] = map[string]interface{}{
-			".": innerField.JSONSchema(),
+			".": innerField.jsonSchemaType(),
 		}
 	default:
 		switch f.Type {
@@ -33,40 +63,76 @@ func (f FieldSpec) JSONSchema() interface{} {
 			spec["type"] = "number"
 		case FieldTypeObject:
 			spec["type"] = "object"
-			spec["properties"] = f.Children.JSONSchema()
-			var required []string
-			for _, child := range f.Children {
-				if !child.IsOptional && child.Default == nil {
-					required = append(required, child.Name)
-				}
-			}
-			if len(required) > 0 {
-				spec["required"] = required
+			if len(f.Children) == 0 {
+				break
 			}
+			spec["properties"] = f.Children.JSONSchema()
 			spec["additionalProperties"] = false
 		case FieldTypeInput:
-			spec["$ref"] = "#/$defs/input"
+			spec["$ref"] = "#/definitions/input"
 		case FieldTypeBuffer:
-			spec["$ref"] = "#/$defs/buffer"
+			spec["$ref"] = "#/definitions/buffer"
 		case FieldTypeCache:
-			spec["$ref"] = "#/$defs/cache"
+			spec["$ref"] = "#/definitions/cache"
 		case FieldTypeCondition:
 			return true
 		case FieldTypeProcessor:
-			spec["$ref"] = "#/$defs/processor"
+			spec["$ref"] = "#/definitions/processor"
 		case FieldTypeRateLimit:
-			spec["$ref"] = "#/$defs/rate_limit"
+			spec["$ref"] = "#/definitions/rate_limit"
 		case FieldTypeOutput:
-			spec["$ref"] = "#/$defs/output"
+			spec["$ref"] = "#/definitions/output"
 		case FieldTypeMetrics:
-			spec["$ref"] = "#/$defs/metrics"
+			spec["$ref"] = "#/definitions/metrics"
 		case FieldTypeTracer:
-			spec["$ref"] = "#/$defs/tracer"
+			spec["$ref"] = "#/definitions/tracer"
+		}
+		if enum := f.jsonSchemaEnum(); len(enum) > 0 {
+			spec["enum"] = enum
 		}
 	}
 	return spec
 }
 
+// jsonSchemaEnum returns the options of a scalar field converted to the type
+// of the field. The default value of the field is always included as it isn't
+// guaranteed to be one of the listed options.
+func (f FieldSpec) jsonSchemaEnum() []interface{} {
+	options := f.Options
+	for _, o := range f.AnnotatedOptions {
+		options = append(options, o[0])
+	}
+	if len(options) == 0 {
+		return nil
+	}
+
+	var enum []interface{}
+	seen := map[string]struct{}{}
+	add := func(v interface{}) {
+		k := fmt.Sprintf("%v", v)
+		if _, exists := seen[k]; !exists {
+			seen[k] = struct{}{}
+			enum = append(enum, v)
+		}
+	}
+	for _, o := range options {
+		switch f.Type {
+		case FieldTypeString:
+			add(o)
+		case FieldTypeInt, FieldTypeFloat:
+			if n, err := strconv.ParseFloat(o, 64); err == nil {
+				add(n)
+			}
+		default:
+			return nil
+		}
+	}
+	if f.Default != nil {
+		add(*f.Default)
+	}
+	return enum
+}
+
 // JSONSchema serializes a field spec into a JSON schema structure.
 func (f FieldSpecs) JSONSchema() map[string]interface{} {
 	spec := map[string]interface{}{}
@@ -75,3 +141,121 @@ func (f FieldSpecs) JSONSchema() map[string]interface{} {
 	}
 	return spec
 }
+
+//------------------------------------------------------------------------------
+
+// jsonSchemaBranch returns a JSON schema structure matching a config of this
+// component, where the fields of the component are nested under its name. The
+// component may also be identified explicitly with a type field matching its
+// name.
+func (c ComponentSpec) jsonSchemaBranch() map[string]interface{} {
+	var config interface{} = true
+	if !c.Plugin || len(c.Config.Children) > 0 {
+		config = c.Config.JSONSchema()
+	}
+
+	props := map[string]interface{}{
+		c.Name: config,
+	}
+	for k, f := range reservedFieldsByType(c.Type) {
+		switch k {
+		case "type":
+			props[k] = map[string]interface{}{
+				"type":  "string",
+				"const": c.Name,
+			}
+		case "plugin":
+			if c.Plugin {
+				props[k] = true
+			}
+		default:
+			props[k] = f.JSONSchema()
+		}
+	}
+
+	branch := map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+		"anyOf": []interface{}{
+			map[string]interface{}{"required": []string{c.Name}},
+			map[string]interface{}{"required": []string{"type"}},
+		},
+		"additionalProperties": false,
+	}
+	if summary := strings.TrimSpace(c.Summary); summary != "" {
+		branch["description"] = summary
+	}
+	if c.Status == StatusDeprecated {
+		branch["deprecated"] = true
+	}
+	return branch
+}
+
+// JSONSchema serializes the spec of a component into a JSON schema (draft-07)
+// document describing its configuration. Fields of the component that contain
+// nested components are not validated.
+func (c ComponentSpec) JSONSchema() ([]byte, error) {
+	definitions := map[string]interface{}{}
+	for _, t := range Types() {
+		definitions[string(t)] = true
+	}
+
+	doc := c.jsonSchemaBranch()
+	doc["$schema"] = jsonSchemaDraft
+	doc["definitions"] = definitions
+	return json.Marshal(doc)
+}
+
+//------------------------------------------------------------------------------
+
+type docsLister interface {
+	ListDocs(ctype Type) []ComponentSpec
+}
+
+// ProviderToJSONSchema walks all components registered with a provider and
+// serializes them into a JSON schema (draft-07) document, where each component
+// type is described by a definition with a branch for each implementation. The
+// provider must support listing its registered components.
+func ProviderToJSONSchema(p Provider) ([]byte, error) {
+	return ConfigToJSONSchema(p, nil)
+}
+
+// ConfigToJSONSchema serializes a set of root config fields along with all
+// components registered with a provider into a JSON schema (draft-07)
+// document. The provider must support listing its registered components.
+func ConfigToJSONSchema(p Provider, root FieldSpecs) ([]byte, error) {
+	lister, ok := p.(docsLister)
+	if !ok {
+		return nil, errors.New("provider does not support listing components")
+	}
+
+	definitions := map[string]interface{}{}
+	for _, t := range Types() {
+		specs := lister.ListDocs(t)
+		branches := make([]interface{}, 0, len(specs)+1)
+
+		// An empty object is a valid config for any component type, where
+		// the default implementation is used.
+		branches = append(branches, map[string]interface{}{
+			"type":          "object",
+			"maxProperties": 0,
+		})
+		for _, spec := range specs {
+			branches = append(branches, spec.jsonSchemaBranch())
+		}
+		definitions[string(t)] = map[string]interface{}{
+			"oneOf": branches,
+		}
+	}
+
+	doc := map[string]interface{}{
+		"$schema":     jsonSchemaDraft,
+		"type":        "object",
+		"definitions": definitions,
+	}
+	if len(root) > 0 {
+		doc["properties"] = root.JSONSchema()
+		doc["additionalProperties"] = false
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,261 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func jsonSchemaTestProvider() *docs.MappedDocsProvider {
+	docsProv := docs.NewMappedDocsProvider()
+	docsProv.RegisterDocs(docs.ComponentSpec{
+		Name: "foo",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("address", "").HasDefault(""),
+			docs.FieldString("mode", "").HasOptions("a", "b").HasDefault("a"),
+			docs.FieldInt("tries", "").HasDefault(3),
+			docs.FieldString("urls", "").Array().HasDefault([]string{}),
+			docs.FieldDeprecated("old"),
+		),
+	})
+	docsProv.RegisterDocs(docs.ComponentSpec{
+		Name: "bar",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldCommon("inputs", "").Array().HasType(docs.FieldTypeInput),
+		),
+	})
+	docsProv.RegisterDocs(docs.ComponentSpec{
+		Name: "baz",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldCommon("processors", "").Array().HasType(docs.FieldTypeProcessor),
+		),
+	})
+	return docsProv
+}
+
+func TestProviderToJSONSchema(t *testing.T) {
+	schemaBytes, err := docs.ConfigToJSONSchema(jsonSchemaTestProvider(), docs.FieldSpecs{
+		docs.FieldCommon("input", "").HasType(docs.FieldTypeInput),
+		docs.FieldCommon("processors", "").Array().HasType(docs.FieldTypeProcessor),
+	})
+	require.NoError(t, err)
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schemaBytes))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		config interface{}
+		valid  bool
+	}{
+		"empty config": {
+			config: map[string]interface{}{},
+			valid:  true,
+		},
+		"empty component": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{},
+			},
+			valid: true,
+		},
+		"simple component": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"foo": map[string]interface{}{
+						"address": "localhost",
+						"mode":    "b",
+						"tries":   5,
+						"urls":    []interface{}{"a", "b"},
+					},
+				},
+			},
+			valid: true,
+		},
+		"explicit type": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":  "foo",
+					"label": "meow",
+					"foo":   map[string]interface{}{},
+				},
+			},
+			valid: true,
+		},
+		"mismatched type": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type": "bar",
+					"foo":  map[string]interface{}{},
+				},
+			},
+			valid: false,
+		},
+		"unknown component": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"nope": map[string]interface{}{},
+				},
+			},
+			valid: false,
+		},
+		"unknown field": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"foo": map[string]interface{}{
+						"nope": "nah",
+					},
+				},
+			},
+			valid: false,
+		},
+		"bad option": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"foo": map[string]interface{}{
+						"mode": "c",
+					},
+				},
+			},
+			valid: false,
+		},
+		"bad array element": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"foo": map[string]interface{}{
+						"urls": []interface{}{"a", 10},
+					},
+				},
+			},
+			valid: false,
+		},
+		"nested components": {
+			config: map[string]interface{}{
+				"input": map[string]interface{}{
+					"bar": map[string]interface{}{
+						"inputs": []interface{}{
+							map[string]interface{}{
+								"bar": map[string]interface{}{
+									"inputs": []interface{}{
+										map[string]interface{}{
+											"foo": map[string]interface{}{},
+										},
+									},
+								},
+							},
+						},
+					},
+					"processors": []interface{}{
+						map[string]interface{}{
+							"baz": map[string]interface{}{
+								"processors": []interface{}{
+									map[string]interface{}{"baz": map[string]interface{}{}},
+								},
+							},
+						},
+					},
+				},
+			},
+			valid: true,
+		},
+		"bad nested component": {
+			config: map[string]interface{}{
+				"processors": []interface{}{
+					map[string]interface{}{
+						"baz": map[string]interface{}{
+							"processors": []interface{}{
+								map[string]interface{}{"nope": map[string]interface{}{}},
+							},
+						},
+					},
+				},
+			},
+			valid: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			res, err := schema.Validate(gojsonschema.NewGoLoader(test.config))
+			require.NoError(t, err)
+			assert.Equal(t, test.valid, res.Valid(), res.Errors())
+		})
+	}
+}
+
+func TestProviderToJSONSchemaDefinitions(t *testing.T) {
+	schemaBytes, err := docs.ProviderToJSONSchema(jsonSchemaTestProvider())
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(schemaBytes, &schema))
+
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+
+	definitions, _ := schema["definitions"].(map[string]interface{})
+	for _, ctype := range docs.Types() {
+		assert.Contains(t, definitions, string(ctype))
+	}
+
+	inputBranches := definitions["input"].(map[string]interface{})["oneOf"].([]interface{})
+	require.Len(t, inputBranches, 3)
+
+	fooProps := inputBranches[2].(map[string]interface{})["properties"].(map[string]interface{})
+	fooFields := fooProps["foo"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{
+		"type":    "string",
+		"enum":    []interface{}{"a", "b"},
+		"default": "a",
+	}, fooFields["mode"])
+	assert.Equal(t, map[string]interface{}{
+		"type":    "number",
+		"default": float64(3),
+	}, fooFields["tries"])
+	assert.Equal(t, map[string]interface{}{
+		"type":    "array",
+		"items":   map[string]interface{}{"type": "string"},
+		"default": []interface{}{},
+	}, fooFields["urls"])
+	assert.Equal(t, true, fooFields["old"].(map[string]interface{})["deprecated"])
+}
+
+func TestComponentSpecJSONSchema(t *testing.T) {
+	spec, ok := jsonSchemaTestProvider().GetDocs("bar", docs.TypeInput)
+	require.True(t, ok)
+
+	schemaBytes, err := spec.JSONSchema()
+	require.NoError(t, err)
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schemaBytes))
+	require.NoError(t, err)
+
+	res, err := schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{
+		"bar": map[string]interface{}{
+			"inputs": []interface{}{
+				map[string]interface{}{"anything": "goes"},
+			},
+		},
+	}))
+	require.NoError(t, err)
+	assert.True(t, res.Valid(), res.Errors())
+
+	res, err = schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{
+		"bar": map[string]interface{}{
+			"nope": "nah",
+		},
+	}))
+	require.NoError(t, err)
+	assert.False(t, res.Valid())
+}
+
+func TestProviderToJSONSchemaUnsupported(t *testing.T) {
+	_, err := docs.ProviderToJSONSchema(nil)
+	require.Error(t, err)
+}
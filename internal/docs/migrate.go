@@ -0,0 +1,224 @@
+package docs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration describes a single change applied to a config in order to remove
+// usage of deprecated functionality.
+type Migration struct {
+	// Path is the dot separated path of the field that was migrated, relative
+	// to the root of the component config.
+	Path string `json:"path"`
+
+	// NewPath is the dot separated path of the field that the value was moved
+	// to, which is empty when the field was removed without a replacement.
+	NewPath string `json:"new_path,omitempty"`
+
+	// OldValue is the value of the field before it was migrated.
+	OldValue interface{} `json:"old_value"`
+
+	// Reason is a human readable description of why the migration was
+	// applied.
+	Reason string `json:"reason"`
+}
+
+// MigrateComponentConfig walks a raw component configuration and removes any
+// deprecated fields that are set, including within nested components. When a
+// deprecated field documents a replacement its value is moved to that field
+// instead. Returns a list of the migrations that were applied, which describe
+// each field that was removed along with its value and the reason given by its
+// docs.
+func MigrateComponentConfig(componentType Type, raw interface{}) ([]Migration, error) {
+	// Conditions are not documented with field specs and can therefore not be
+	// migrated.
+	if componentType == "condition" {
+		return nil, nil
+	}
+
+	var migrations []Migration
+	if err := migrateComponentConfig(componentType, "", raw, &migrations); err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
+
+func migrateComponentConfig(componentType Type, path string, raw interface{}, migrations *[]Migration) error {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected object configuration type, found: %T", raw)
+	}
+
+	// An empty component config results in the default type, which has no
+	// fields set that could require migration.
+	if len(m) == 0 {
+		return nil
+	}
+
+	name, spec, err := GetInferenceCandidate(globalProvider, componentType, "", raw)
+	if err != nil {
+		return err
+	}
+
+	if componentConfRaw, exists := m[name]; exists {
+		if err := spec.Config.migrate(joinMigratePath(path, name), componentConfRaw, migrations); err != nil {
+			return err
+		}
+	}
+
+	reservedFields := reservedFieldsByType(componentType)
+	for _, k := range sortedKeys(m) {
+		fieldSpec, exists := reservedFields[k]
+		if !exists {
+			continue
+		}
+		if err := fieldSpec.migrate(joinMigratePath(path, k), m[k], migrations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f FieldSpec) migrate(path string, s interface{}, migrations *[]Migration) error {
+	coreType, isCore := f.Type.IsCoreComponent()
+	if !isCore && len(f.Children) == 0 {
+		return nil
+	}
+
+	walk := func(path string, v interface{}) error {
+		if isCore {
+			return migrateComponentConfig(coreType, path, v, migrations)
+		}
+		return f.Children.migrate(path, v, migrations)
+	}
+
+	switch f.Kind {
+	case KindArray:
+		if arr, ok := s.([]interface{}); ok {
+			for i, ele := range arr {
+				if err := walk(joinMigratePath(path, strconv.Itoa(i)), ele); err != nil {
+					return err
+				}
+			}
+		}
+	case KindMap:
+		if obj, ok := s.(map[string]interface{}); ok {
+			for _, k := range sortedKeys(obj) {
+				if err := walk(joinMigratePath(path, k), obj[k]); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		return walk(path, s)
+	}
+	return nil
+}
+
+func (f FieldSpecs) migrate(path string, s interface{}, migrations *[]Migration) error {
+	m, ok := s.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, spec := range f {
+		v, exists := m[spec.Name]
+		if !exists {
+			continue
+		}
+		fieldPath := joinMigratePath(path, spec.Name)
+		if spec.IsDeprecated {
+			migration := Migration{
+				Path:     fieldPath,
+				OldValue: v,
+			}
+			if spec.moveToReplacement(m) {
+				migration.NewPath = joinMigratePath(path, spec.Replacement)
+				migration.Reason = fmt.Sprintf("field %v is deprecated and was renamed to %v", spec.Name, spec.Replacement)
+			} else {
+				reason := strings.TrimSpace(strings.TrimPrefix(spec.Description, "DEPRECATED:"))
+				if reason == "" {
+					reason = "Do not use."
+				}
+				migration.Reason = fmt.Sprintf("field %v is deprecated and was removed: %v", spec.Name, reason)
+			}
+			*migrations = append(*migrations, migration)
+			delete(m, spec.Name)
+			continue
+		}
+		if err := spec.migrate(fieldPath, v, migrations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveToReplacement copies the value of a deprecated field within an object to
+// its replacement, creating any missing parent objects of the replacement.
+// Values that are empty or match the default of the deprecated field are not
+// moved, and neither are values that would overwrite a populated replacement.
+// Returns true if the value was moved.
+func (f FieldSpec) moveToReplacement(m map[string]interface{}) bool {
+	v, exists := m[f.Name]
+	if !exists || f.Replacement == "" || isEmptyValue(v) {
+		return false
+	}
+	if f.Default != nil && fmt.Sprintf("%v", *f.Default) == fmt.Sprintf("%v", v) {
+		return false
+	}
+
+	segments := strings.Split(f.Replacement, ".")
+	target := m
+	for _, seg := range segments[:len(segments)-1] {
+		child, exists := target[seg]
+		if !exists {
+			childMap := map[string]interface{}{}
+			target[seg] = childMap
+			target = childMap
+			continue
+		}
+		childMap, ok := child.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		target = childMap
+	}
+
+	last := segments[len(segments)-1]
+	if existing, exists := target[last]; exists && !isEmptyValue(existing) {
+		return false
+	}
+	target[last] = v
+	return true
+}
+
+func isEmptyValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+func joinMigratePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
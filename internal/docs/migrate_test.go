@@ -0,0 +1,231 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateComponentConfig(t *testing.T) {
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testmigrateinput",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("address", "").HasDefault(""),
+			docs.FieldDeprecated("max_batch_count"),
+			docs.FieldCommon("batching", "").WithChildren(
+				docs.FieldInt("count", "").HasDefault(0),
+				docs.FieldDeprecated("condition", "Use `check` instead."),
+			),
+		),
+	})
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testmigrateprocessor",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldDeprecated("parts"),
+			docs.FieldCommon("processors", "").Array().HasType(docs.FieldTypeProcessor),
+		),
+	})
+
+	conf := map[string]interface{}{
+		"testmigrateinput": map[string]interface{}{
+			"address":         "foo",
+			"max_batch_count": 10,
+			"batching": map[string]interface{}{
+				"count":     5,
+				"condition": map[string]interface{}{"type": "static"},
+			},
+		},
+		"processors": []interface{}{
+			map[string]interface{}{
+				"testmigrateprocessor": map[string]interface{}{
+					"parts": []interface{}{0},
+					"processors": []interface{}{
+						map[string]interface{}{
+							"type":                 "testmigrateprocessor",
+							"testmigrateprocessor": map[string]interface{}{"parts": []interface{}{1}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	migrations, err := docs.MigrateComponentConfig(docs.TypeInput, conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, []docs.Migration{
+		{
+			Path:     "testmigrateinput.max_batch_count",
+			OldValue: 10,
+			Reason:   "field max_batch_count is deprecated and was removed: Do not use.",
+		},
+		{
+			Path:     "testmigrateinput.batching.condition",
+			OldValue: map[string]interface{}{"type": "static"},
+			Reason:   "field condition is deprecated and was removed: Use `check` instead.",
+		},
+		{
+			Path:     "processors.0.testmigrateprocessor.parts",
+			OldValue: []interface{}{0},
+			Reason:   "field parts is deprecated and was removed: Do not use.",
+		},
+		{
+			Path:     "processors.0.testmigrateprocessor.processors.0.testmigrateprocessor.parts",
+			OldValue: []interface{}{1},
+			Reason:   "field parts is deprecated and was removed: Do not use.",
+		},
+	}, migrations)
+
+	assert.Equal(t, map[string]interface{}{
+		"testmigrateinput": map[string]interface{}{
+			"address": "foo",
+			"batching": map[string]interface{}{
+				"count": 5,
+			},
+		},
+		"processors": []interface{}{
+			map[string]interface{}{
+				"testmigrateprocessor": map[string]interface{}{
+					"processors": []interface{}{
+						map[string]interface{}{
+							"type":                 "testmigrateprocessor",
+							"testmigrateprocessor": map[string]interface{}{},
+						},
+					},
+				},
+			},
+		},
+	}, conf)
+}
+
+func TestMigrateComponentConfigErrors(t *testing.T) {
+	_, err := docs.MigrateComponentConfig(docs.TypeInput, "nope")
+	require.Error(t, err)
+
+	_, err = docs.MigrateComponentConfig(docs.TypeInput, map[string]interface{}{
+		"type": "testmigratedoesnotexist",
+	})
+	require.Error(t, err)
+
+	migrations, err := docs.MigrateComponentConfig(docs.TypeInput, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, migrations)
+}
+
+func TestMigrateComponentConfigReplacements(t *testing.T) {
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testmigratereplacedprocessor",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("resource", "").HasDefault(""),
+			docs.FieldDeprecated("cache").ReplacedBy("resource"),
+			docs.FieldCommon("batching", "").WithChildren(
+				docs.FieldInt("count", "").HasDefault(0),
+			),
+			docs.FieldDeprecated("max_batch_count").HasDefault(1).ReplacedBy("batching.count"),
+			docs.FieldDeprecated("old_count").ReplacedBy("batching.count"),
+		),
+	})
+
+	conf := map[string]interface{}{
+		"testmigratereplacedprocessor": map[string]interface{}{
+			"resource":        "",
+			"cache":           "foo",
+			"max_batch_count": 10,
+			"old_count":       20,
+		},
+	}
+
+	migrations, err := docs.MigrateComponentConfig(docs.TypeProcessor, conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, []docs.Migration{
+		{
+			Path:     "testmigratereplacedprocessor.cache",
+			NewPath:  "testmigratereplacedprocessor.resource",
+			OldValue: "foo",
+			Reason:   "field cache is deprecated and was renamed to resource",
+		},
+		{
+			Path:     "testmigratereplacedprocessor.max_batch_count",
+			NewPath:  "testmigratereplacedprocessor.batching.count",
+			OldValue: 10,
+			Reason:   "field max_batch_count is deprecated and was renamed to batching.count",
+		},
+		{
+			Path:     "testmigratereplacedprocessor.old_count",
+			OldValue: 20,
+			Reason:   "field old_count is deprecated and was removed: Do not use.",
+		},
+	}, migrations)
+
+	assert.Equal(t, map[string]interface{}{
+		"testmigratereplacedprocessor": map[string]interface{}{
+			"resource": "foo",
+			"batching": map[string]interface{}{
+				"count": 10,
+			},
+		},
+	}, conf)
+
+	// Values matching the default of the deprecated field are not moved.
+	conf = map[string]interface{}{
+		"testmigratereplacedprocessor": map[string]interface{}{
+			"max_batch_count": 1,
+		},
+	}
+	migrations, err = docs.MigrateComponentConfig(docs.TypeProcessor, conf)
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	assert.Empty(t, migrations[0].NewPath)
+	assert.Equal(t, map[string]interface{}{
+		"testmigratereplacedprocessor": map[string]interface{}{},
+	}, conf)
+}
+
+func TestSanitiseReplacements(t *testing.T) {
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testsanitisereplacedprocessor",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("resource", "").HasDefault(""),
+			docs.FieldDeprecated("cache").ReplacedBy("resource"),
+		),
+	})
+
+	conf := map[string]interface{}{
+		"type": "testsanitisereplacedprocessor",
+		"testsanitisereplacedprocessor": map[string]interface{}{
+			"resource": "",
+			"cache":    "foo",
+		},
+	}
+	require.NoError(t, docs.SanitiseComponentConfig(docs.TypeProcessor, conf, docs.ShouldDropDeprecated(true)))
+	assert.Equal(t, map[string]interface{}{
+		"type": "testsanitisereplacedprocessor",
+		"testsanitisereplacedprocessor": map[string]interface{}{
+			"resource": "foo",
+		},
+	}, conf)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+testsanitisereplacedprocessor:
+  cache: foo
+`), &node))
+	require.NoError(t, docs.SanitiseYAML(docs.TypeProcessor, &node, docs.SanitiseConfig{
+		RemoveTypeField:  true,
+		RemoveDeprecated: true,
+	}))
+
+	resBytes, err := yaml.Marshal(&node)
+	require.NoError(t, err)
+	assert.Equal(t, `testsanitisereplacedprocessor:
+    resource: foo
+`, string(resBytes))
+}
@@ -1,6 +1,7 @@
 package docs
 
 import (
+	"sort"
 	"sync"
 )
 
@@ -126,3 +127,39 @@ func (m *MappedDocsProvider) GetDocs(name string, ctype Type) (ComponentSpec, bo
 
 	return spec, ok
 }
+
+// ListDocs returns the documentation of all component implementations of a
+// given type, sorted by name.
+func (m *MappedDocsProvider) ListDocs(ctype Type) []ComponentSpec {
+	m.componentLock.Lock()
+	defer m.componentLock.Unlock()
+
+	var specMap map[string]ComponentSpec
+	switch ctype {
+	case TypeBuffer:
+		specMap = m.bufferMap
+	case TypeCache:
+		specMap = m.cacheMap
+	case TypeInput:
+		specMap = m.inputMap
+	case TypeMetrics:
+		specMap = m.metricsMap
+	case TypeOutput:
+		specMap = m.outputMap
+	case TypeProcessor:
+		specMap = m.processorMap
+	case TypeRateLimit:
+		specMap = m.rateLimitMap
+	case TypeTracer:
+		specMap = m.tracerMap
+	}
+
+	specs := make([]ComponentSpec, 0, len(specMap))
+	for _, spec := range specMap {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+	return specs
+}
@@ -2,6 +2,8 @@ package docs
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -383,12 +385,73 @@ func (f FieldSpec) SanitiseYAML(node *yaml.Node, conf SanitiseConfig) error {
 	return nil
 }
 
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// moveToReplacementYAML is the equivalent of moveToReplacement for a parsed
+// config object.
+func (f FieldSpec) moveToReplacementYAML(node *yaml.Node) bool {
+	if f.Replacement == "" || node.Kind != yaml.MappingNode {
+		return false
+	}
+	valueNode := yamlMappingValue(node, f.Name)
+	if valueNode == nil {
+		return false
+	}
+	var v interface{}
+	if err := valueNode.Decode(&v); err != nil || isEmptyValue(v) {
+		return false
+	}
+	if f.Default != nil && fmt.Sprintf("%v", *f.Default) == fmt.Sprintf("%v", v) {
+		return false
+	}
+
+	segments := strings.Split(f.Replacement, ".")
+	target := node
+	for _, seg := range segments[:len(segments)-1] {
+		child := yamlMappingValue(target, seg)
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			target.Content = append(target.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: seg}, child)
+		} else if child.Kind != yaml.MappingNode {
+			return false
+		}
+		target = child
+	}
+
+	last := segments[len(segments)-1]
+	if existing := yamlMappingValue(target, last); existing != nil {
+		var ev interface{}
+		if err := existing.Decode(&ev); err == nil && !isEmptyValue(ev) {
+			return false
+		}
+		*existing = *valueNode
+		return true
+	}
+	target.Content = append(target.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: last}, valueNode)
+	return true
+}
+
 // SanitiseYAML attempts to reduce a parsed config (as a *yaml.Node) down into a
 // minimal representation without changing the behaviour of the config. The
 // fields of the result will also be sorted according to the field spec.
 func (f FieldSpecs) SanitiseYAML(node *yaml.Node, conf SanitiseConfig) error {
 	node = unwrapDocumentNode(node)
 
+	// Deprecated fields that are dropped are moved to their replacement
+	// first, as the replacement may precede them in our field specs.
+	for _, field := range f {
+		if (field.IsDeprecated && conf.RemoveDeprecated) || conf.Filter.shouldDrop(field) {
+			field.moveToReplacementYAML(node)
+		}
+	}
+
 	// Following the order of our field specs, extract each field.
 	newNodes := []*yaml.Node{}
 	for _, field := range f {
@@ -423,12 +486,31 @@ func (f FieldSpecs) SanitiseYAML(node *yaml.Node, conf SanitiseConfig) error {
 func lintYAMLFromOmit(parentSpec FieldSpecs, lintTargetSpec FieldSpec, parent, node *yaml.Node) []Lint {
 	why, shouldOmit := lintTargetSpec.shouldOmitYAML(parentSpec, node, parent)
 	if shouldOmit {
-		return []Lint{NewLintError(node.Line, why)}
+		return []Lint{NewTypedLintWarning(node.Line, LintShouldOmit, why)}
 	}
 	return nil
 }
 
-func customLintFromYAML(ctx LintContext, spec FieldSpec, node *yaml.Node) []Lint {
+// yamlParentFn returns a func that lazily extracts a boxed variant of an object
+// node according to its field specs, or nil if it cannot be extracted.
+func yamlParentFn(parentFields FieldSpecs, parentNode *yaml.Node) func() interface{} {
+	var parent interface{}
+	var extracted bool
+	return func() interface{} {
+		if !extracted {
+			extracted = true
+			if m, err := parentFields.YAMLToMap(parentNode, ToValueConfig{
+				Passive:             true,
+				FallbackToInterface: true,
+			}); err == nil {
+				parent = m
+			}
+		}
+		return parent
+	}
+}
+
+func customLintFromYAML(ctx LintContext, spec FieldSpec, node *yaml.Node, parentFn func() interface{}) []Lint {
 	if spec.customLintFn == nil {
 		return nil
 	}
@@ -441,7 +523,11 @@ func customLintFromYAML(ctx LintContext, spec FieldSpec, node *yaml.Node) []Lint
 		// that we'll capture this type error elsewhere.
 		return []Lint{}
 	}
-	lints := spec.customLintFn(ctx, node.Line, node.Column, fieldValue)
+	var parent interface{}
+	if parentFn != nil {
+		parent = parentFn()
+	}
+	lints := spec.customLintFn(ctx, node.Line, node.Column, fieldValue, parent)
 	return lints
 }
 
@@ -472,16 +558,19 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 		}
 		var err error
 		if name, _, err = getInferenceCandidateFromList(ctx.DocsProvider, cType, "", keys); err != nil {
-			lints = append(lints, NewLintWarning(node.Line, "unable to infer component type"))
+			lints = append(lints, NewTypedLintWarning(node.Line, LintComponentNotFound, "unable to infer component type"))
 			return lints
 		}
 	}
 
 	cSpec, exists := ctx.DocsProvider.GetDocs(name, cType)
 	if !exists {
-		lints = append(lints, NewLintWarning(node.Line, fmt.Sprintf("failed to obtain docs for %v type %v", cType, name)))
+		lints = append(lints, NewTypedLintWarning(node.Line, LintComponentNotFound, fmt.Sprintf("failed to obtain docs for %v type %v", cType, name)))
 		return lints
 	}
+	if ctx.WarnDeprecated && cSpec.Status == StatusDeprecated {
+		lints = append(lints, NewTypedLintWarning(node.Line, LintDeprecated, fmt.Sprintf("%v type %v is deprecated", cType, name)))
+	}
 
 	nameFound := false
 	for i := 0; i < len(node.Content)-1; i += 2 {
@@ -499,7 +588,7 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 		}
 		if node.Content[i].Value == "plugin" {
 			if nameFound || !cSpec.Plugin {
-				lints = append(lints, NewLintError(node.Content[i].Line, "plugin object is ineffective"))
+				lints = append(lints, NewTypedLintError(node.Content[i].Line, LintShouldOmit, "plugin object is ineffective"))
 			} else {
 				lints = append(lints, cSpec.Config.LintYAML(ctx, node.Content[i+1])...)
 			}
@@ -507,10 +596,10 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 		spec, exists := reservedFields[node.Content[i].Value]
 		if exists {
 			lints = append(lints, lintYAMLFromOmit(cSpec.Config.Children, spec, node, node.Content[i+1])...)
-			lints = append(lints, spec.LintYAML(ctx, node.Content[i+1])...)
+			lints = append(lints, spec.lintYAML(ctx, node.Content[i+1], yamlParentFn(cSpec.Config.Children, node))...)
 		} else {
-			lints = append(lints, NewLintError(
-				node.Content[i].Line,
+			lints = append(lints, NewTypedLintError(
+				node.Content[i].Line, LintUnknownField,
 				fmt.Sprintf("field %v is invalid when the component type is %v (%v)", node.Content[i].Value, name, cType),
 			))
 		}
@@ -522,6 +611,10 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 // LintYAML returns a list of linting errors found by checking a field
 // definition against a yaml node.
 func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
+	return f.lintYAML(ctx, node, nil)
+}
+
+func (f FieldSpec) lintYAML(ctx LintContext, node *yaml.Node, parentFn func() interface{}) []Lint {
 	if f.skipLint {
 		return nil
 	}
@@ -534,35 +627,35 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 	switch f.Kind {
 	case Kind2DArray:
 		if node.Kind != yaml.SequenceNode {
-			lints = append(lints, NewLintError(node.Line, "expected array value"))
+			lints = append(lints, NewTypedLintError(node.Line, LintExpectedArray, "expected array value"))
 			return lints
 		}
 		for i := 0; i < len(node.Content); i++ {
-			lints = append(lints, f.Array().LintYAML(ctx, node.Content[i])...)
+			lints = append(lints, f.Array().lintYAML(ctx, node.Content[i], parentFn)...)
 		}
 		return lints
 	case KindArray:
 		if node.Kind != yaml.SequenceNode {
-			lints = append(lints, NewLintError(node.Line, "expected array value"))
+			lints = append(lints, NewTypedLintError(node.Line, LintExpectedArray, "expected array value"))
 			return lints
 		}
 		for i := 0; i < len(node.Content); i++ {
-			lints = append(lints, f.Scalar().LintYAML(ctx, node.Content[i])...)
+			lints = append(lints, f.Scalar().lintYAML(ctx, node.Content[i], parentFn)...)
 		}
 		return lints
 	case KindMap:
 		if node.Kind != yaml.MappingNode {
-			lints = append(lints, NewLintError(node.Line, "expected object value"))
+			lints = append(lints, NewTypedLintError(node.Line, LintExpectedObject, "expected object value"))
 			return lints
 		}
 		for i := 0; i < len(node.Content)-1; i += 2 {
-			lints = append(lints, f.Scalar().LintYAML(ctx, node.Content[i+1])...)
+			lints = append(lints, f.Scalar().lintYAML(ctx, node.Content[i+1], parentFn)...)
 		}
 		return lints
 	}
 
 	// Execute custom linters
-	lints = append(lints, customLintFromYAML(ctx, f, node)...)
+	lints = append(lints, customLintFromYAML(ctx, f, node, parentFn)...)
 
 	// If we're a core type then execute component specific linting
 	if coreType, isCore := f.Type.IsCoreComponent(); isCore {
@@ -579,11 +672,11 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 	// TODO: Do proper checking for bool and number types.
 	case FieldTypeBool, FieldTypeString, FieldTypeInt, FieldTypeFloat:
 		if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
-			lints = append(lints, NewLintError(node.Line, fmt.Sprintf("expected %v value", f.Type)))
+			lints = append(lints, NewTypedLintError(node.Line, LintExpectedScalar, fmt.Sprintf("expected %v value", f.Type)))
 		}
 	case FieldTypeObject:
 		if node.Kind != yaml.MappingNode && node.Kind != yaml.AliasNode {
-			lints = append(lints, NewLintError(node.Line, "expected object value"))
+			lints = append(lints, NewTypedLintError(node.Line, LintExpectedObject, "expected object value"))
 		}
 	}
 	return lints
@@ -599,7 +692,7 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			// TODO: Actually lint through aliases
 			return nil
 		}
-		lints = append(lints, NewLintError(node.Line, "expected object value"))
+		lints = append(lints, NewTypedLintError(node.Line, LintExpectedObject, "expected object value"))
 		return lints
 	}
 
@@ -608,16 +701,26 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 		specNames[field.Name] = field
 	}
 
+	parentFn := yamlParentFn(f, node)
+
+	populatedLines := map[string]int{}
 	for i := 0; i < len(node.Content)-1; i += 2 {
+		if populatedYAML(node.Content[i+1]) {
+			populatedLines[node.Content[i].Value] = node.Content[i].Line
+		}
+
 		spec, exists := specNames[node.Content[i].Value]
 		if !exists {
 			if node.Content[i+1].Kind != yaml.AliasNode {
-				lints = append(lints, NewLintError(node.Content[i].Line, fmt.Sprintf("field %v not recognised", node.Content[i].Value)))
+				lints = append(lints, NewTypedLintError(node.Content[i].Line, LintUnknownField, fmt.Sprintf("field %v not recognised", node.Content[i].Value)))
 			}
 			continue
 		}
+		if ctx.WarnDeprecated && spec.IsDeprecated && populatedYAML(node.Content[i+1]) {
+			lints = append(lints, NewTypedLintWarning(node.Content[i].Line, LintDeprecated, fmt.Sprintf("field %v is deprecated", node.Content[i].Value)))
+		}
 		lints = append(lints, lintYAMLFromOmit(f, spec, node, node.Content[i+1])...)
-		lints = append(lints, spec.LintYAML(ctx, node.Content[i+1])...)
+		lints = append(lints, spec.lintYAML(ctx, node.Content[i+1], parentFn)...)
 		delete(specNames, node.Content[i].Value)
 	}
 
@@ -629,8 +732,58 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			remaining.Kind == KindScalar &&
 			!remaining.IsDeprecated &&
 			len(remaining.Children) == 0 {
-			lints = append(lints, NewLintError(node.Line, fmt.Sprintf("field %v is required", name)))
+			lints = append(lints, NewTypedLintError(node.Line, LintMissingField, fmt.Sprintf("field %v is required", name)))
+		}
+	}
+
+	return append(lints, f.lintExclusiveYAML(populatedLines)...)
+}
+
+// populatedYAML returns true if a field value is set to something other than
+// null or an empty string, array or object.
+func populatedYAML(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value != "" && node.Tag != "!!null"
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) > 0
+	}
+	return true
+}
+
+func (f FieldSpecs) lintExclusiveYAML(populatedLines map[string]int) []Lint {
+	var lints []Lint
+	seen := map[string]struct{}{}
+	for _, spec := range f {
+		if len(spec.exclusiveWith) == 0 {
+			continue
+		}
+		if _, exists := populatedLines[spec.Name]; !exists {
+			continue
 		}
+		conflicts := []string{spec.Name}
+		for _, name := range spec.exclusiveWith {
+			if _, exists := populatedLines[name]; exists && name != spec.Name {
+				conflicts = append(conflicts, name)
+			}
+		}
+		if len(conflicts) < 2 {
+			continue
+		}
+		sort.Slice(conflicts, func(i, j int) bool {
+			return populatedLines[conflicts[i]] < populatedLines[conflicts[j]]
+		})
+		key := strings.Join(conflicts, ",")
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		line := populatedLines[conflicts[len(conflicts)-1]]
+		lints = append(lints, NewTypedLintError(line, LintMutuallyExclusive, fmt.Sprintf(
+			"fields %v and %v are mutually exclusive and cannot be set together",
+			strings.Join(conflicts[:len(conflicts)-1], ", "), conflicts[len(conflicts)-1],
+		)))
 	}
 	return lints
 }
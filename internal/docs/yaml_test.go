@@ -1,6 +1,7 @@
 package docs_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -612,6 +613,9 @@ func TestYAMLComponentLinting(t *testing.T) {
 				docs.FieldAdvanced("foo8", "").Map().WithChildren(
 					docs.FieldInt("foochild1", "").Optional(),
 				).Optional(),
+				docs.FieldString("fooex1", "").ExclusiveWith("fooex2", "fooex3").Optional(),
+				docs.FieldString("fooex2", "").Array().Optional(),
+				docs.FieldString("fooex3", "").Optional(),
 			),
 		})
 	}
@@ -710,7 +714,7 @@ testlintfooinput:
   foo1: hello world
 processors: []`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "field processors is empty and can be removed"),
+				docs.NewLintWarning(4, "field processors is empty and can be removed"),
 			},
 		},
 		{
@@ -721,7 +725,7 @@ testlintfooinput:
   foo1: hello world
   foo2: drop me`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "because foo"),
+				docs.NewLintWarning(4, "because foo"),
 			},
 		},
 		{
@@ -864,6 +868,38 @@ testlintfooinput:
 				docs.NewLintError(3, "this is a custom lint"),
 			},
 		},
+		{
+			name:      "exclusive fields",
+			inputType: docs.TypeInput,
+			inputConf: `
+testlintfooinput:
+  fooex1: foo
+  fooex2: [ bar ]`,
+			res: []docs.Lint{
+				docs.NewLintError(4, "fields fooex1 and fooex2 are mutually exclusive and cannot be set together"),
+			},
+		},
+		{
+			name:      "exclusive fields all set",
+			inputType: docs.TypeInput,
+			inputConf: `
+testlintfooinput:
+  fooex3: baz
+  fooex2: [ bar ]
+  fooex1: foo`,
+			res: []docs.Lint{
+				docs.NewLintError(5, "fields fooex3, fooex2 and fooex1 are mutually exclusive and cannot be set together"),
+			},
+		},
+		{
+			name:      "exclusive fields empty",
+			inputType: docs.TypeInput,
+			inputConf: `
+testlintfooinput:
+  fooex1: foo
+  fooex2: []
+  fooex3: null`,
+		},
 	}
 
 	for _, test := range tests {
@@ -872,7 +908,7 @@ testlintfooinput:
 			var node yaml.Node
 			require.NoError(t, yaml.Unmarshal([]byte(test.inputConf), &node))
 			lints := docs.LintYAML(docs.NewLintContext(), test.inputType, &node)
-			assert.Equal(t, test.res, lints)
+			assert.Equal(t, untypedLints(test.res), untypedLints(lints))
 		})
 	}
 }
@@ -949,6 +985,43 @@ func TestYAMLLinting(t *testing.T) {
 				docs.NewLintError(1, "field baz is required"),
 			},
 		},
+		{
+			name:      "sibling lint passes",
+			inputSpec: docs.FieldCommon("foo", "").WithChildren(siblingLintFields()...),
+			inputConf: `batch_size: 5
+max_in_flight: 10`,
+		},
+		{
+			name:      "sibling lint fails",
+			inputSpec: docs.FieldCommon("foo", "").WithChildren(siblingLintFields()...),
+			inputConf: `batch_size: 20
+max_in_flight: 10`,
+			res: []docs.Lint{
+				docs.NewLintError(1, "batch_size (20) must not exceed max_in_flight (10)"),
+			},
+		},
+		{
+			name:      "sibling lint uses default",
+			inputSpec: docs.FieldCommon("foo", "").WithChildren(siblingLintFields()...),
+			inputConf: `batch_size: 70`,
+			res: []docs.Lint{
+				docs.NewLintError(1, "batch_size (70) must not exceed max_in_flight (64)"),
+			},
+		},
+		{
+			name: "sibling lint within array",
+			inputSpec: docs.FieldCommon("foo", "").WithChildren(
+				docs.FieldCommon("bar", "").Array().WithChildren(siblingLintFields()...),
+			),
+			inputConf: `bar:
+  - batch_size: 1
+    max_in_flight: 2
+  - batch_size: 3
+    max_in_flight: 2`,
+			res: []docs.Lint{
+				docs.NewLintError(4, "batch_size (3) must not exceed max_in_flight (2)"),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -958,11 +1031,85 @@ func TestYAMLLinting(t *testing.T) {
 			require.NoError(t, yaml.Unmarshal([]byte(test.inputConf), &node))
 
 			lints := test.inputSpec.LintYAML(docs.NewLintContext(), &node)
-			assert.Equal(t, test.res, lints)
+			assert.Equal(t, untypedLints(test.res), untypedLints(lints))
 		})
 	}
 }
 
+// untypedLints removes the types of lints so that tests that aren't concerned
+// with them can compare lints created with NewLintError and NewLintWarning.
+func untypedLints(lints []docs.Lint) []docs.Lint {
+	for i := range lints {
+		lints[i].Type = docs.LintUnknown
+	}
+	return lints
+}
+
+func TestYAMLLintTypes(t *testing.T) {
+	spec := docs.FieldCommon("", "").WithChildren(
+		docs.FieldString("foo", "").HasOptions("a", "b").LintOptions(),
+		docs.FieldString("bar", "").Array(),
+		docs.FieldString("baz", "").Map(),
+		docs.FieldString("buz", "").Linter(func(ctx docs.LintContext, line, col int, v interface{}) []docs.Lint {
+			return []docs.Lint{docs.NewLintError(line, "custom")}
+		}),
+		docs.FieldString("qux", ""),
+	)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`foo: c
+bar: nope
+baz: nope
+buz: nope
+nope: nope`), &node))
+
+	assert.Equal(t, []docs.Lint{
+		docs.NewTypedLintError(1, docs.LintInvalidOption, "value c is not a valid option for this field"),
+		docs.NewTypedLintError(2, docs.LintExpectedArray, "expected array value"),
+		docs.NewTypedLintError(3, docs.LintExpectedObject, "expected object value"),
+		docs.NewTypedLintError(4, docs.LintCustom, "custom"),
+		docs.NewTypedLintError(5, docs.LintUnknownField, "field nope not recognised"),
+		docs.NewTypedLintError(1, docs.LintMissingField, "field qux is required"),
+	}, spec.LintYAML(docs.NewLintContext(), &node))
+
+	var zero docs.Lint
+	assert.Equal(t, docs.LintUnknown, zero.Type)
+}
+
+func TestYAMLLintingDeprecated(t *testing.T) {
+	spec := docs.FieldCommon("foo", "").WithChildren(
+		docs.FieldString("bar", ""),
+		docs.FieldDeprecated("baz"),
+		docs.FieldDeprecated("buz"),
+	)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`bar: a
+baz: b
+buz: ""`), &node))
+
+	assert.Empty(t, spec.LintYAML(docs.NewLintContext(), &node))
+
+	ctx := docs.NewLintContext()
+	ctx.WarnDeprecated = true
+	assert.Equal(t, []docs.Lint{
+		docs.NewTypedLintWarning(2, docs.LintDeprecated, "field baz is deprecated"),
+	}, spec.LintYAML(ctx, &node))
+}
+
+func TestLintJSON(t *testing.T) {
+	lint := docs.NewTypedLintWarning(3, docs.LintUnknownField, "field foo not recognised")
+	b, err := json.Marshal(lint)
+	require.NoError(t, err)
+	assert.Equal(t, `{"line":3,"level":"warning","type":"unknown_field","what":"field foo not recognised"}`, string(b))
+
+	lint = docs.NewTypedLintError(1, docs.LintBadLabel, "bad label")
+	lint.Column = 4
+	b, err = json.Marshal(lint)
+	require.NoError(t, err)
+	assert.Equal(t, `{"line":1,"column":4,"level":"error","type":"bad_label","what":"bad label"}`, string(b))
+}
+
 func TestYAMLSanitation(t *testing.T) {
 	for _, t := range docs.Types() {
 		docs.RegisterDocs(docs.ComponentSpec{
@@ -1186,3 +1333,25 @@ processors:
 		})
 	}
 }
+
+func siblingLintFields() []docs.FieldSpec {
+	return []docs.FieldSpec{
+		docs.FieldInt("batch_size", "").HasDefault(1).LinterWithParent(
+			func(ctx docs.LintContext, line, col int, value, parent interface{}) []docs.Lint {
+				maxInFlight := 64
+				if pMap, ok := parent.(map[string]interface{}); ok {
+					if v, ok := pMap["max_in_flight"].(int); ok {
+						maxInFlight = v
+					}
+				}
+				if v, ok := value.(int); ok && v > maxInFlight {
+					return []docs.Lint{
+						docs.NewLintError(line, fmt.Sprintf("batch_size (%v) must not exceed max_in_flight (%v)", v, maxInFlight)),
+					}
+				}
+				return nil
+			},
+		),
+		docs.FieldInt("max_in_flight", "").HasDefault(64),
+	}
+}
@@ -35,6 +35,7 @@ type Client struct {
 
 	backoffOn map[int]struct{}
 	dropOn    map[int]struct{}
+	permOn    map[int]struct{}
 	successOn map[int]struct{}
 
 	url     *field.Expression
@@ -81,6 +82,7 @@ func NewClient(conf client.Config, opts ...func(*Client)) (*Client, error) {
 		mgr:       types.NoopMgr(),
 		backoffOn: map[int]struct{}{},
 		dropOn:    map[int]struct{}{},
+		permOn:    map[int]struct{}{},
 		successOn: map[int]struct{}{},
 		headers:   map[string]*field.Expression{},
 		host:      nil,
@@ -137,6 +139,9 @@ func NewClient(conf client.Config, opts ...func(*Client)) (*Client, error) {
 	for _, c := range conf.DropOn {
 		h.dropOn[c] = struct{}{}
 	}
+	for _, c := range conf.PermanentOn {
+		h.permOn[c] = struct{}{}
+	}
 	for _, c := range conf.SuccessfulOn {
 		h.successOn[c] = struct{}{}
 	}
@@ -446,6 +451,9 @@ func (h *Client) checkStatus(code int) (succeeded bool, retStrat retryStrategy)
 	if _, exists := h.successOn[code]; exists {
 		return true, noRetry
 	}
+	if _, exists := h.permOn[code]; exists {
+		return false, noRetry
+	}
 	if code < 200 || code > 299 {
 		return false, retryLinear
 	}
@@ -512,10 +520,11 @@ func (h *Client) SendToResponse(ctx context.Context, sendMsg, refMsg types.Messa
 		h.incrCode(res.StatusCode)
 		if resolved, retryStrat := h.checkStatus(res.StatusCode); !resolved {
 			rateLimited = retryStrat == retryBackoff
+			err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 			if retryStrat == noRetry {
 				numRetries = 0
+				err = types.ErrPermanent{Err: err}
 			}
-			err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 			if res.Body != nil {
 				res.Body.Close()
 			}
@@ -545,10 +554,11 @@ func (h *Client) SendToResponse(ctx context.Context, sendMsg, refMsg types.Messa
 			h.incrCode(res.StatusCode)
 			if resolved, retryStrat := h.checkStatus(res.StatusCode); !resolved {
 				rateLimited = retryStrat == retryBackoff
+				err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 				if retryStrat == noRetry {
 					j = 0
+					err = types.ErrPermanent{Err: err}
 				}
-				err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 				if res.Body != nil {
 					res.Body.Close()
 				}
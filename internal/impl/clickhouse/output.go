@@ -0,0 +1,475 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	ioutput "github.com/Jeffail/benthos/v3/internal/component/output"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message/batch"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/types"
+
+	// ClickHouse native protocol driver
+	_ "github.com/ClickHouse/clickhouse-go"
+)
+
+func init() {
+	bundle.AllOutputs.Add(bundle.OutputConstructorFromSimple(func(c output.Config, nm bundle.NewManagement) (output.Type, error) {
+		s, err := newClickHouseOutput(c.ClickHouse, nm.Logger(), nm.Metrics())
+		if err != nil {
+			return nil, err
+		}
+		w, err := output.NewAsyncWriter(output.TypeClickHouse, c.ClickHouse.MaxInFlight, s, nm.Logger(), nm.Metrics())
+		if err != nil {
+			return nil, err
+		}
+		return output.NewBatcherFromConfig(c.ClickHouse.Batching, w, nm, nm.Logger(), nm.Metrics())
+	}), docs.ComponentSpec{
+		Name:    output.TypeClickHouse,
+		Type:    docs.TypeOutput,
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Categories: []string{
+			string(output.CategoryServices),
+		},
+		Summary: `
+Inserts rows into a ClickHouse table using the native protocol.`,
+		Description: ioutput.Description(true, true, `
+Each message of a batch is converted into a row with the [Bloblang mapping](/docs/guides/bloblang/about)
+`+"`args_mapping`"+`, which must return an array containing a value for each
+column listed in the field `+"`columns`"+`, in the same order. Rows of a batch
+are sent to ClickHouse as a single block of the native format, and therefore
+the [batching policy](/docs/configuration/batching) of this output determines
+the size of each insert. Larger batches are generally far more efficient.
+
+The types of the target columns are read from the table when connecting, and
+values produced by the mapping are converted into those types where possible.
+For example, numbers are converted into the integer or float type of their
+column and strings are parsed as RFC 3339 timestamps for `+"`Date`"+` and
+`+"`DateTime`"+` columns.
+
+### Compression
+
+Blocks can be compressed on the wire by setting the field `+"`compression`"+`
+to `+"`lz4`"+`, which is the only compression algorithm supported by the
+native protocol driver currently in use. This is equivalent to adding the
+parameter `+"`compress=true`"+` to the data source name.`),
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Analytics Events",
+				Summary: "Rows are extracted from JSON events and inserted in compressed blocks of up to ten thousand rows.",
+				Config: `
+output:
+  clickhouse:
+    data_source_name: tcp://localhost:9000?database=analytics&username=benthos&password=foo
+    table: events
+    columns: [ id, user_id, kind, created_at ]
+    args_mapping: '[ this.id, this.user.id, this.kind, this.timestamp ]'
+    compression: lz4
+    max_in_flight: 4
+    batching:
+      count: 10000
+      period: 5s
+`,
+			},
+		},
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldCommon(
+				"data_source_name", "A [Data Source Name](https://github.com/ClickHouse/clickhouse-go#dsn) identifying the target ClickHouse servers.",
+				"tcp://localhost:9000?database=default",
+				"tcp://host1:9000?username=user&password=qwerty&database=clicks&alt_hosts=host2:9000,host3:9000",
+			),
+			docs.FieldCommon("table", "The table to insert rows into.", "events", "analytics.events"),
+			docs.FieldString("columns", "A list of columns to insert values into.", []string{"id", "kind", "created_at"}).Array(),
+			docs.FieldCommon(
+				"args_mapping",
+				"A [Bloblang mapping](/docs/guides/bloblang/about) that produces the values of a row. The mapping must return an array containing a value for each of the `columns`.",
+				`[ this.id, this.kind, meta("kafka_timestamp_unix") ]`,
+			).Linter(docs.LintBloblangMapping),
+			docs.FieldAdvanced("compression", "The compression to apply to blocks sent over the wire.").HasOptions("none", "lz4"),
+			docs.FieldCommon("max_in_flight", "The maximum number of batches to have in flight at a given time. Increase this to improve throughput."),
+			batch.FieldSpec(),
+		).ChildDefaultAndTypesFromStruct(output.NewClickHouseConfig()),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+type clickHouseOutput struct {
+	conf        output.ClickHouseConfig
+	dsn         string
+	insert      string
+	argsMapping *mapping.Executor
+
+	dbMut      sync.RWMutex
+	db         *sql.DB
+	converters []valueConverter
+
+	log   log.Modular
+	stats metrics.Type
+}
+
+func newClickHouseOutput(conf output.ClickHouseConfig, log log.Modular, stats metrics.Type) (*clickHouseOutput, error) {
+	if conf.Table == "" {
+		return nil, errors.New("a table must be specified")
+	}
+	if len(conf.Columns) == 0 {
+		return nil, errors.New("at least one column must be specified")
+	}
+	if conf.ArgsMapping == "" {
+		return nil, errors.New("an args_mapping must be specified")
+	}
+
+	s := &clickHouseOutput{
+		conf:  conf,
+		log:   log,
+		stats: stats,
+	}
+
+	var err error
+	if s.argsMapping, err = bloblang.NewMapping("", conf.ArgsMapping); err != nil {
+		return nil, fmt.Errorf("failed to parse `args_mapping`: %w", err)
+	}
+
+	dsnURL, err := url.Parse(conf.DataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse data source name: %w", err)
+	}
+	switch strings.ToLower(conf.Compression) {
+	case "", "none":
+	case "lz4":
+		query := dsnURL.Query()
+		query.Set("compress", "true")
+		dsnURL.RawQuery = query.Encode()
+	default:
+		return nil, fmt.Errorf("compression not recognised: %v", conf.Compression)
+	}
+	s.dsn = dsnURL.String()
+
+	placeholders := make([]string, len(conf.Columns))
+	for i := range placeholders {
+		placeholders[i] = "?"
+	}
+	s.insert = fmt.Sprintf(
+		"INSERT INTO %v (%v) VALUES (%v)",
+		conf.Table, strings.Join(conf.Columns, ", "), strings.Join(placeholders, ", "),
+	)
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s *clickHouseOutput) columnTypes(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, "DESCRIBE TABLE "+s.conf.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) < 2 {
+		return nil, fmt.Errorf("unexpected table description with %v columns", len(cols))
+	}
+
+	colTypes := map[string]string{}
+	for rows.Next() {
+		fields := make([]interface{}, len(cols))
+		var name, chType sql.NullString
+		fields[0], fields[1] = &name, &chType
+		for i := 2; i < len(fields); i++ {
+			fields[i] = &sql.NullString{}
+		}
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		colTypes[name.String] = chType.String
+	}
+	return colTypes, rows.Err()
+}
+
+// ConnectWithContext establishes a connection to ClickHouse and reads the types
+// of the target columns.
+func (s *clickHouseOutput) ConnectWithContext(ctx context.Context) error {
+	s.dbMut.Lock()
+	defer s.dbMut.Unlock()
+
+	if s.db != nil {
+		return nil
+	}
+
+	db, err := sql.Open("clickhouse", s.dsn)
+	if err != nil {
+		return err
+	}
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return err
+	}
+
+	colTypes, err := s.columnTypes(ctx, db)
+	if err != nil {
+		db.Close()
+		return fmt.Errorf("failed to describe table: %w", err)
+	}
+
+	converters := make([]valueConverter, len(s.conf.Columns))
+	for i, col := range s.conf.Columns {
+		chType, exists := colTypes[col]
+		if !exists {
+			db.Close()
+			return fmt.Errorf("column %v was not found in table %v", col, s.conf.Table)
+		}
+		converters[i] = converterFor(chType)
+	}
+
+	s.db = db
+	s.converters = converters
+	s.log.Infof("Inserting rows into ClickHouse table %v\n", s.conf.Table)
+	return nil
+}
+
+func (s *clickHouseOutput) getRow(index int, msg types.Message, converters []valueConverter) ([]interface{}, error) {
+	p, err := s.argsMapping.MapPart(index, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	iargs, err := p.JSON()
+	if err != nil {
+		return nil, fmt.Errorf("mapping returned non-structured result: %w", err)
+	}
+
+	args, ok := iargs.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("mapping returned non-array result: %T", iargs)
+	}
+	if len(args) != len(converters) {
+		return nil, fmt.Errorf("mapping returned %v values but %v columns are configured", len(args), len(converters))
+	}
+
+	for i, v := range args {
+		if args[i], err = converters[i](v); err != nil {
+			return nil, fmt.Errorf("column %v: %w", s.conf.Columns[i], err)
+		}
+	}
+	return args, nil
+}
+
+// WriteWithContext inserts a batch of messages as a single block.
+func (s *clickHouseOutput) WriteWithContext(ctx context.Context, msg types.Message) error {
+	s.dbMut.RLock()
+	db := s.db
+	converters := s.converters
+	s.dbMut.RUnlock()
+
+	if db == nil {
+		return types.ErrNotConnected
+	}
+
+	rows := make([][]interface{}, msg.Len())
+	if err := msg.Iter(func(i int, _ types.Part) error {
+		row, err := s.getRow(i, msg, converters)
+		if err != nil {
+			return fmt.Errorf("failed to map row %v: %w", i, err)
+		}
+		rows[i] = row
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, s.insert)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err = stmt.ExecContext(ctx, row...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// HealthCheck verifies that ClickHouse is still reachable.
+func (s *clickHouseOutput) HealthCheck(ctx context.Context) error {
+	s.dbMut.RLock()
+	db := s.db
+	s.dbMut.RUnlock()
+
+	if db == nil {
+		return types.ErrNotConnected
+	}
+	return db.PingContext(ctx)
+}
+
+// CloseAsync begins cleaning up resources used by this output asynchronously.
+func (s *clickHouseOutput) CloseAsync() {
+	go func() {
+		s.dbMut.Lock()
+		if s.db != nil {
+			s.db.Close()
+			s.db = nil
+		}
+		s.dbMut.Unlock()
+	}()
+}
+
+// WaitForClose will block until either the output is closed or a specified
+// timeout occurs.
+func (s *clickHouseOutput) WaitForClose(time.Duration) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type valueConverter func(v interface{}) (interface{}, error)
+
+func unwrapType(chType string) (string, bool) {
+	nullable := false
+	for {
+		if strings.HasPrefix(chType, "Nullable(") && strings.HasSuffix(chType, ")") {
+			chType = strings.TrimSuffix(strings.TrimPrefix(chType, "Nullable("), ")")
+			nullable = true
+		} else if strings.HasPrefix(chType, "LowCardinality(") && strings.HasSuffix(chType, ")") {
+			chType = strings.TrimSuffix(strings.TrimPrefix(chType, "LowCardinality("), ")")
+		} else {
+			return chType, nullable
+		}
+	}
+}
+
+// converterFor returns a function that converts values produced by a mapping
+// into a type accepted by a column of the provided ClickHouse type.
+func converterFor(chType string) valueConverter {
+	baseType, nullable := unwrapType(chType)
+
+	var conv valueConverter
+	switch {
+	case strings.HasPrefix(baseType, "Int"), strings.HasPrefix(baseType, "UInt"):
+		conv = toInt64
+	case strings.HasPrefix(baseType, "Float"):
+		conv = toFloat64
+	case baseType == "String", strings.HasPrefix(baseType, "FixedString"):
+		conv = toString
+	case strings.HasPrefix(baseType, "DateTime"), baseType == "Date":
+		conv = toTime
+	default:
+		conv = func(v interface{}) (interface{}, error) {
+			return v, nil
+		}
+	}
+
+	return func(v interface{}) (interface{}, error) {
+		if v == nil {
+			if nullable {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("null value for column of type %v", chType)
+		}
+		return conv(v)
+	}
+}
+
+func toInt64(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case float64:
+		if t != float64(int64(t)) {
+			return nil, fmt.Errorf("expected integer value, got %v", t)
+		}
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case uint64:
+		return int64(t), nil
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return toInt64(f)
+	case bool:
+		if t {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}
+	return nil, fmt.Errorf("expected number value, got %T", v)
+}
+
+func toFloat64(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case int64:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	case json.Number:
+		return t.Float64()
+	}
+	return nil, fmt.Errorf("expected number value, got %T", v)
+}
+
+func toString(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case []byte:
+		return string(t), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func toTime(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		ts, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return nil, err
+		}
+		return ts, nil
+	case float64:
+		return time.Unix(int64(t), 0), nil
+	case int64:
+		return time.Unix(t, 0), nil
+	case json.Number:
+		i, err := t.Int64()
+		if err != nil {
+			return nil, err
+		}
+		return time.Unix(i, 0), nil
+	}
+	return nil, fmt.Errorf("expected timestamp string or unix value, got %T", v)
+}
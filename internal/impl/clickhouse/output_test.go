@@ -0,0 +1,78 @@
+package clickhouse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouseConfig(t *testing.T) {
+	conf := output.NewClickHouseConfig()
+	conf.DataSourceName = "tcp://localhost:9000?database=foo"
+	conf.Table = "events"
+	conf.Columns = []string{"id", "kind"}
+	conf.ArgsMapping = "[ this.id, this.kind ]"
+
+	s, err := newClickHouseOutput(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://localhost:9000?database=foo", s.dsn)
+	assert.Equal(t, "INSERT INTO events (id, kind) VALUES (?, ?)", s.insert)
+
+	conf.Compression = "lz4"
+	s, err = newClickHouseOutput(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://localhost:9000?compress=true&database=foo", s.dsn)
+
+	conf.Compression = "zstd"
+	_, err = newClickHouseOutput(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "compression not recognised: zstd")
+
+	conf.Compression = "none"
+	conf.Columns = nil
+	_, err = newClickHouseOutput(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "at least one column must be specified")
+}
+
+func TestClickHouseRows(t *testing.T) {
+	conf := output.NewClickHouseConfig()
+	conf.Table = "events"
+	conf.Columns = []string{"id", "score", "kind", "created_at", "label"}
+	conf.ArgsMapping = `[ this.id, this.score, this.kind, this.ts, this.label ]`
+
+	s, err := newClickHouseOutput(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	converters := []valueConverter{
+		converterFor("UInt64"),
+		converterFor("Float32"),
+		converterFor("LowCardinality(String)"),
+		converterFor("DateTime('UTC')"),
+		converterFor("Nullable(String)"),
+	}
+
+	msg := message.New([][]byte{
+		[]byte(`{"id":5,"score":3,"kind":{"a":"b"},"ts":"2021-07-20T10:00:00Z","label":null}`),
+		[]byte(`{"id":5.5,"score":3,"kind":"foo","ts":0,"label":"bar"}`),
+	})
+
+	row, err := s.getRow(0, msg, converters)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		int64(5), float64(3), `{"a":"b"}`, time.Date(2021, 7, 20, 10, 0, 0, 0, time.UTC), nil,
+	}, row)
+
+	_, err = s.getRow(1, msg, converters)
+	require.EqualError(t, err, "column id: expected integer value, got 5.5")
+
+	_, err = s.getRow(0, msg, converters[:2])
+	require.EqualError(t, err, "mapping returned 5 values but 2 columns are configured")
+
+	_, err = converterFor("Int32")(nil)
+	require.EqualError(t, err, "null value for column of type Int32")
+}
@@ -0,0 +1,500 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/internal/shutdown"
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/input/reader"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	btls "github.com/Jeffail/benthos/v3/lib/util/tls"
+	"github.com/gorilla/websocket"
+)
+
+func init() {
+	bundle.AllInputs.Add(bundle.InputConstructorFromSimple(func(c input.Config, nm bundle.NewManagement) (input.Type, error) {
+		r, err := newGraphQLReader(c.GraphQL, nm.Logger(), nm.Metrics())
+		if err != nil {
+			return nil, err
+		}
+		return input.NewAsyncReader(input.TypeGraphQL, false, r, nm.Logger(), nm.Metrics())
+	}), docs.ComponentSpec{
+		Name:    input.TypeGraphQL,
+		Type:    docs.TypeInput,
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Categories: []string{
+			string(input.CategoryNetwork),
+		},
+		Summary: `Executes a GraphQL query, or subscribes to a GraphQL subscription, and creates a message from the data of each result.`,
+		Description: `
+Each message consists of the ` + "`data`" + ` object of a GraphQL response. When a response contains only errors the read fails and is attempted again, and when a response contains both data and errors the errors are added to the message as the metadata field ` + "`graphql_errors`" + `, which is a JSON array.
+
+### Polling
+
+By default the query is executed once, and after all pages of results have been read the input closes. When the field ` + "`interval`" + ` is set the query is instead executed repeatedly, waiting for the interval between the start of each execution.
+
+### Pagination
+
+The field ` + "`cursor_mapping`" + ` is an optional [Bloblang mapping](/docs/guides/bloblang/about) that is executed on the ` + "`data`" + ` of each response, and must result in an object of variables used for requesting the next page of results. These variables are added to those of the field ` + "`variables`" + `. When the mapping results in ` + "`deleted()`" + ` there are no more pages and the execution ends.
+
+### Subscriptions
+
+When ` + "`subscription`" + ` is set to ` + "`true`" + ` the query must be a subscription, which is executed over a WebSocket connection to the URL and produces a message for each event. Both the ` + "`graphql-transport-ws`" + ` protocol and the legacy ` + "`graphql-ws`" + ` protocol are supported, and the connection is established again if it is lost. When the subscription is completed by the server the input closes.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+` + "```text" + `
+- graphql_errors
+` + "```" + `
+
+You can access these metadata fields using
+[function interpolation](/docs/configuration/interpolation#metadata).`,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldCommon("url", "The URL of the GraphQL API. When consuming a subscription this should be a WebSocket URL.", "https://example.com/graphql", "wss://example.com/graphql"),
+			docs.FieldCommon("query", "The GraphQL query document to execute.", `query ($cursor: String) {
+  issues(first: 100, after: $cursor) {
+    nodes { id title }
+    pageInfo { hasNextPage endCursor }
+  }
+}`, `subscription { orderCreated { id total } }`),
+			docs.FieldCommon("variables", "An object of variables to execute the query with.", map[string]interface{}{"owner": "foo"}).HasType(docs.FieldTypeObject),
+			docs.FieldCommon("cursor_mapping", "An optional [Bloblang mapping](/docs/guides/bloblang/about) executed on the data of each response that results in the variables for requesting the next page of results, or `deleted()` when there are no more pages.", `root = if this.issues.pageInfo.hasNextPage {
+  { "cursor": this.issues.pageInfo.endCursor }
+} else {
+  deleted()
+}`).Linter(docs.LintBloblangMapping),
+			docs.FieldCommon("interval", "An optional period after which the query is executed again. When empty the query is executed only once.", "60s", "1h"),
+			docs.FieldCommon("subscription", "Whether the query is a subscription to be consumed over a WebSocket connection."),
+			docs.FieldAdvanced("subscription_protocol", "The WebSocket protocol used for consuming subscriptions.").HasOptions("graphql-transport-ws", "graphql-ws"),
+			docs.FieldString("headers", "A map of headers to add to requests.", map[string]string{"Authorization": "Bearer ${GRAPHQL_TOKEN}"}).Map(),
+			docs.FieldAdvanced("timeout", "The maximum period to wait for a query response."),
+			btls.FieldSpec(),
+		).ChildDefaultAndTypesFromStruct(input.NewGraphQLConfig()),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage   `json:"data"`
+	Errors []json.RawMessage `json:"errors"`
+}
+
+func errorsToErr(errs []json.RawMessage) error {
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		var gErr graphQLError
+		if err := json.Unmarshal(e, &gErr); err == nil && gErr.Message != "" {
+			msgs = append(msgs, gErr.Message)
+		} else {
+			msgs = append(msgs, string(e))
+		}
+	}
+	return fmt.Errorf("graphql errors: %v", strings.Join(msgs, ", "))
+}
+
+// toMessage creates a message from a GraphQL response, returning an error if
+// the response contains no data.
+func (r graphQLResponse) toMessage() (types.Message, error) {
+	if len(r.Data) == 0 || string(r.Data) == "null" {
+		if len(r.Errors) > 0 {
+			return nil, errorsToErr(r.Errors)
+		}
+		return nil, errors.New("response contained no data")
+	}
+	part := message.NewPart(r.Data)
+	if len(r.Errors) > 0 {
+		errBytes, _ := json.Marshal(r.Errors)
+		part.Metadata().Set("graphql_errors", string(errBytes))
+	}
+	msg := message.New(nil)
+	msg.Append(part)
+	return msg, nil
+}
+
+//------------------------------------------------------------------------------
+
+type graphQLReader struct {
+	conf          input.GraphQLConfig
+	cursorMapping *mapping.Executor
+	interval      time.Duration
+	headers       http.Header
+	client        *http.Client
+	dialer        *websocket.Dialer
+
+	log log.Modular
+
+	// Polling state
+	nextVars   map[string]interface{}
+	lastExecAt time.Time
+	executed   bool
+
+	// Subscription state
+	connMut sync.Mutex
+	conn    *websocket.Conn
+
+	shutSig *shutdown.Signaller
+}
+
+func newGraphQLReader(conf input.GraphQLConfig, log log.Modular, stats metrics.Type) (*graphQLReader, error) {
+	if conf.URL == "" {
+		return nil, errors.New("field url must not be empty")
+	}
+	if conf.Query == "" {
+		return nil, errors.New("field query must not be empty")
+	}
+	if conf.SubscriptionProtocol != "graphql-transport-ws" && conf.SubscriptionProtocol != "graphql-ws" {
+		return nil, fmt.Errorf("unrecognised subscription protocol: %v", conf.SubscriptionProtocol)
+	}
+
+	g := &graphQLReader{
+		conf:    conf,
+		headers: http.Header{},
+		log:     log,
+		shutSig: shutdown.NewSignaller(),
+	}
+	for k, v := range conf.Headers {
+		g.headers.Set(k, v)
+	}
+
+	var err error
+	if conf.CursorMapping != "" {
+		if conf.Subscription {
+			return nil, errors.New("field cursor_mapping cannot be used with subscriptions")
+		}
+		if g.cursorMapping, err = bloblang.NewMapping("", conf.CursorMapping); err != nil {
+			return nil, fmt.Errorf("failed to parse cursor mapping: %w", err)
+		}
+	}
+	if conf.Interval != "" {
+		if conf.Subscription {
+			return nil, errors.New("field interval cannot be used with subscriptions")
+		}
+		if g.interval, err = time.ParseDuration(conf.Interval); err != nil {
+			return nil, fmt.Errorf("failed to parse interval: %w", err)
+		}
+	}
+
+	timeout, err := time.ParseDuration(conf.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timeout: %w", err)
+	}
+
+	g.client = &http.Client{Timeout: timeout}
+	g.dialer = &websocket.Dialer{
+		HandshakeTimeout: timeout,
+		Subprotocols:     []string{conf.SubscriptionProtocol},
+	}
+	if conf.TLS.Enabled {
+		tlsConf, err := conf.TLS.Get()
+		if err != nil {
+			return nil, err
+		}
+		g.client.Transport = &http.Transport{TLSClientConfig: tlsConf}
+		g.dialer.TLSClientConfig = tlsConf
+	}
+	return g, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (g *graphQLReader) variables() map[string]interface{} {
+	vars := make(map[string]interface{}, len(g.conf.Variables)+len(g.nextVars))
+	for k, v := range g.conf.Variables {
+		vars[k] = v
+	}
+	for k, v := range g.nextVars {
+		vars[k] = v
+	}
+	return vars
+}
+
+func (g *graphQLReader) query(ctx context.Context) (*graphQLResponse, error) {
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"query":     g.conf.Query,
+		"variables": g.variables(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.conf.URL, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range g.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var gRes graphQLResponse
+	if jerr := json.Unmarshal(resBytes, &gRes); jerr != nil {
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return nil, fmt.Errorf("request failed with status %v", res.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to parse response: %w", jerr)
+	}
+	return &gRes, nil
+}
+
+// nextCursor executes the cursor mapping on the data of a response, and
+// returns the variables for the next page or nil if there are no more pages.
+func (g *graphQLReader) nextCursor(msg types.Message) (map[string]interface{}, error) {
+	if g.cursorMapping == nil {
+		return nil, nil
+	}
+	part, err := g.cursorMapping.MapPart(0, msg)
+	if err != nil {
+		return nil, fmt.Errorf("cursor mapping failed: %w", err)
+	}
+	if part == nil {
+		return nil, nil
+	}
+	v, err := part.JSON()
+	if err != nil {
+		return nil, fmt.Errorf("cursor mapping result: %w", err)
+	}
+	vars, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cursor mapping resulted in a non-object: %T", v)
+	}
+	return vars, nil
+}
+
+func (g *graphQLReader) readPoll(ctx context.Context) (types.Message, reader.AsyncAckFn, error) {
+	if g.nextVars == nil && g.executed {
+		if g.interval <= 0 {
+			return nil, nil, types.ErrTypeClosed
+		}
+		select {
+		case <-time.After(time.Until(g.lastExecAt.Add(g.interval))):
+		case <-ctx.Done():
+			return nil, nil, types.ErrTimeout
+		case <-g.shutSig.CloseAtLeisureChan():
+			return nil, nil, types.ErrTypeClosed
+		}
+	}
+	if g.nextVars == nil {
+		g.lastExecAt = time.Now()
+	}
+
+	res, err := g.query(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, err := res.toMessage()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if g.nextVars, err = g.nextCursor(msg); err != nil {
+		return nil, nil, err
+	}
+	g.executed = true
+
+	return msg, func(context.Context, types.Response) error {
+		return nil
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func (g *graphQLReader) subscribeMsgType() string {
+	if g.conf.SubscriptionProtocol == "graphql-ws" {
+		return "start"
+	}
+	return "subscribe"
+}
+
+func (g *graphQLReader) connectSubscription(ctx context.Context) error {
+	g.connMut.Lock()
+	defer g.connMut.Unlock()
+
+	if g.conn != nil {
+		return nil
+	}
+
+	conn, _, err := g.dialer.DialContext(ctx, g.conf.URL, g.headers)
+	if err != nil {
+		return err
+	}
+
+	if err := conn.WriteJSON(wsMessage{Type: "connection_init", Payload: json.RawMessage(`{}`)}); err != nil {
+		conn.Close()
+		return err
+	}
+
+	for {
+		var ack wsMessage
+		if err := conn.ReadJSON(&ack); err != nil {
+			conn.Close()
+			return err
+		}
+		if ack.Type == "connection_ack" {
+			break
+		}
+		if ack.Type == "connection_error" {
+			conn.Close()
+			return fmt.Errorf("connection rejected: %s", ack.Payload)
+		}
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     g.conf.Query,
+		"variables": g.variables(),
+	})
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if err := conn.WriteJSON(wsMessage{ID: "1", Type: g.subscribeMsgType(), Payload: payload}); err != nil {
+		conn.Close()
+		return err
+	}
+
+	g.conn = conn
+	return nil
+}
+
+func (g *graphQLReader) resetConn() {
+	g.connMut.Lock()
+	if g.conn != nil {
+		g.conn.Close()
+		g.conn = nil
+	}
+	g.connMut.Unlock()
+}
+
+func (g *graphQLReader) readSubscription(ctx context.Context) (types.Message, reader.AsyncAckFn, error) {
+	g.connMut.Lock()
+	conn := g.conn
+	g.connMut.Unlock()
+
+	if conn == nil {
+		return nil, nil, types.ErrNotConnected
+	}
+
+	for {
+		var wsMsg wsMessage
+		if err := conn.ReadJSON(&wsMsg); err != nil {
+			g.resetConn()
+			if g.shutSig.ShouldCloseAtLeisure() {
+				return nil, nil, types.ErrTypeClosed
+			}
+			g.log.Errorf("Failed to read subscription event: %v\n", err)
+			return nil, nil, types.ErrNotConnected
+		}
+
+		switch wsMsg.Type {
+		case "next", "data":
+			var res graphQLResponse
+			if err := json.Unmarshal(wsMsg.Payload, &res); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse subscription event: %w", err)
+			}
+			msg, err := res.toMessage()
+			if err != nil {
+				return nil, nil, err
+			}
+			return msg, func(context.Context, types.Response) error {
+				return nil
+			}, nil
+		case "error":
+			errs := []json.RawMessage{wsMsg.Payload}
+			_ = json.Unmarshal(wsMsg.Payload, &errs)
+			g.resetConn()
+			g.log.Errorf("Subscription failed: %v\n", errorsToErr(errs))
+			return nil, nil, types.ErrNotConnected
+		case "complete":
+			g.resetConn()
+			return nil, nil, types.ErrTypeClosed
+		case "ping":
+			g.connMut.Lock()
+			err := conn.WriteJSON(wsMessage{Type: "pong"})
+			g.connMut.Unlock()
+			if err != nil {
+				g.resetConn()
+				return nil, nil, types.ErrNotConnected
+			}
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ConnectWithContext establishes a subscription, and for queries does nothing.
+func (g *graphQLReader) ConnectWithContext(ctx context.Context) error {
+	if g.conf.Subscription {
+		return g.connectSubscription(ctx)
+	}
+	return nil
+}
+
+// ReadWithContext attempts to read the next result.
+func (g *graphQLReader) ReadWithContext(ctx context.Context) (types.Message, reader.AsyncAckFn, error) {
+	if g.conf.Subscription {
+		return g.readSubscription(ctx)
+	}
+	return g.readPoll(ctx)
+}
+
+// CloseAsync shuts down the input and stops reading results.
+func (g *graphQLReader) CloseAsync() {
+	g.shutSig.CloseAtLeisure()
+	g.connMut.Lock()
+	if g.conn != nil {
+		stopType := "complete"
+		if g.conf.SubscriptionProtocol == "graphql-ws" {
+			stopType = "stop"
+		}
+		_ = g.conn.WriteJSON(wsMessage{ID: "1", Type: stopType})
+		g.conn.Close()
+		g.conn = nil
+	}
+	g.connMut.Unlock()
+}
+
+// WaitForClose blocks until the input has closed down.
+func (g *graphQLReader) WaitForClose(timeout time.Duration) error {
+	return nil
+}
@@ -0,0 +1,230 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type gqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func TestGraphQLPagination(t *testing.T) {
+	pages := map[string]string{
+		"":  `{"data":{"items":{"nodes":[1,2],"pageInfo":{"hasNextPage":true,"endCursor":"a"}}}}`,
+		"a": `{"data":{"items":{"nodes":[3],"pageInfo":{"hasNextPage":true,"endCursor":"b"}}},"errors":[{"message":"partial"}]}`,
+		"b": `{"data":{"items":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`,
+	}
+
+	var reqs []gqlRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer foo", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var req gqlRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+
+		cursor, _ := req.Variables["cursor"].(string)
+		w.Write([]byte(pages[cursor]))
+	}))
+	defer ts.Close()
+
+	conf := input.NewGraphQLConfig()
+	conf.URL = ts.URL
+	conf.Query = `query ($owner: String, $cursor: String) { items(owner: $owner, after: $cursor) { nodes } }`
+	conf.Variables = map[string]interface{}{"owner": "bar"}
+	conf.Headers = map[string]string{"Authorization": "Bearer foo"}
+	conf.CursorMapping = `root = if this.items.pageInfo.hasNextPage {
+  { "cursor": this.items.pageInfo.endCursor }
+} else {
+  deleted()
+}`
+
+	r, err := newGraphQLReader(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+	require.NoError(t, r.ConnectWithContext(ctx))
+
+	var results []string
+	var errMeta []string
+	for {
+		msg, _, err := r.ReadWithContext(ctx)
+		if err == types.ErrTypeClosed {
+			break
+		}
+		require.NoError(t, err)
+		results = append(results, string(msg.Get(0).Get()))
+		errMeta = append(errMeta, msg.Get(0).Metadata().Get("graphql_errors"))
+	}
+
+	assert.Equal(t, []string{
+		`{"items":{"nodes":[1,2],"pageInfo":{"hasNextPage":true,"endCursor":"a"}}}`,
+		`{"items":{"nodes":[3],"pageInfo":{"hasNextPage":true,"endCursor":"b"}}}`,
+		`{"items":{"nodes":[],"pageInfo":{"hasNextPage":false}}}`,
+	}, results)
+	assert.Equal(t, []string{"", `[{"message":"partial"}]`, ""}, errMeta)
+
+	require.Len(t, reqs, 3)
+	assert.Equal(t, conf.Query, reqs[0].Query)
+	assert.Equal(t, map[string]interface{}{"owner": "bar"}, reqs[0].Variables)
+	assert.Equal(t, map[string]interface{}{"owner": "bar", "cursor": "a"}, reqs[1].Variables)
+	assert.Equal(t, map[string]interface{}{"owner": "bar", "cursor": "b"}, reqs[2].Variables)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":null,"errors":[{"message":"foo is bad"},{"message":"bar is bad"}]}`))
+	}))
+	defer ts.Close()
+
+	conf := input.NewGraphQLConfig()
+	conf.URL = ts.URL
+	conf.Query = `{ foo }`
+
+	r, err := newGraphQLReader(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	_, _, err = r.ReadWithContext(context.Background())
+	require.EqualError(t, err, "graphql errors: foo is bad, bar is bad")
+}
+
+func TestGraphQLInterval(t *testing.T) {
+	var count int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.Write([]byte(`{"data":{"foo":"bar"}}`))
+	}))
+	defer ts.Close()
+
+	conf := input.NewGraphQLConfig()
+	conf.URL = ts.URL
+	conf.Query = `{ foo }`
+	conf.Interval = "10ms"
+
+	r, err := newGraphQLReader(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		msg, _, err := r.ReadWithContext(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, `{"foo":"bar"}`, string(msg.Get(0).Get()))
+	}
+	assert.Equal(t, 3, count)
+
+	r.CloseAsync()
+	_, _, err = r.ReadWithContext(context.Background())
+	assert.Equal(t, types.ErrTypeClosed, err)
+}
+
+func TestGraphQLSubscription(t *testing.T) {
+	for _, protocol := range []string{"graphql-transport-ws", "graphql-ws"} {
+		protocol := protocol
+		t.Run(protocol, func(t *testing.T) {
+			dataType, subType := "next", "subscribe"
+			if protocol == "graphql-ws" {
+				dataType, subType = "data", "start"
+			}
+
+			upgrader := websocket.Upgrader{Subprotocols: []string{protocol}}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "Bearer foo", r.Header.Get("Authorization"))
+
+				ws, err := upgrader.Upgrade(w, r, nil)
+				require.NoError(t, err)
+				defer ws.Close()
+
+				assert.Equal(t, protocol, ws.Subprotocol())
+
+				var msg wsMessage
+				require.NoError(t, ws.ReadJSON(&msg))
+				assert.Equal(t, "connection_init", msg.Type)
+				require.NoError(t, ws.WriteJSON(wsMessage{Type: "connection_ack"}))
+
+				require.NoError(t, ws.ReadJSON(&msg))
+				assert.Equal(t, subType, msg.Type)
+				assert.Equal(t, "1", msg.ID)
+
+				var req gqlRequest
+				require.NoError(t, json.Unmarshal(msg.Payload, &req))
+				assert.Equal(t, "subscription { foo }", req.Query)
+
+				if protocol == "graphql-transport-ws" {
+					require.NoError(t, ws.WriteJSON(wsMessage{Type: "ping"}))
+					require.NoError(t, ws.ReadJSON(&msg))
+					assert.Equal(t, "pong", msg.Type)
+				} else {
+					require.NoError(t, ws.WriteJSON(wsMessage{Type: "ka"}))
+				}
+
+				for _, v := range []string{"first", "second"} {
+					require.NoError(t, ws.WriteJSON(wsMessage{
+						ID:      "1",
+						Type:    dataType,
+						Payload: json.RawMessage(`{"data":{"foo":"` + v + `"}}`),
+					}))
+				}
+				require.NoError(t, ws.WriteJSON(wsMessage{ID: "1", Type: "complete"}))
+			}))
+			defer ts.Close()
+
+			conf := input.NewGraphQLConfig()
+			conf.URL = "ws" + strings.TrimPrefix(ts.URL, "http")
+			conf.Query = `subscription { foo }`
+			conf.Subscription = true
+			conf.SubscriptionProtocol = protocol
+			conf.Headers = map[string]string{"Authorization": "Bearer foo"}
+
+			r, err := newGraphQLReader(conf, log.Noop(), metrics.Noop())
+			require.NoError(t, err)
+
+			ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+			defer done()
+			require.NoError(t, r.ConnectWithContext(ctx))
+
+			for _, exp := range []string{`{"foo":"first"}`, `{"foo":"second"}`} {
+				msg, _, err := r.ReadWithContext(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, exp, string(msg.Get(0).Get()))
+			}
+
+			_, _, err = r.ReadWithContext(ctx)
+			assert.Equal(t, types.ErrTypeClosed, err)
+
+			r.CloseAsync()
+			require.NoError(t, r.WaitForClose(time.Second))
+		})
+	}
+}
+
+func TestGraphQLBadConfig(t *testing.T) {
+	conf := input.NewGraphQLConfig()
+	conf.URL = "ws://localhost:1234"
+	conf.Query = `subscription { foo }`
+	conf.Subscription = true
+	conf.Interval = "1s"
+
+	_, err := newGraphQLReader(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "field interval cannot be used with subscriptions")
+
+	conf.Interval = ""
+	conf.CursorMapping = `root = this`
+	_, err = newGraphQLReader(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "field cursor_mapping cannot be used with subscriptions")
+}
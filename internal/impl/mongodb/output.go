@@ -360,6 +360,18 @@ func (m *Writer) WriteWithContext(ctx context.Context, msg types.Message) error
 	return nil
 }
 
+// HealthCheck verifies that the MongoDB server is still reachable.
+func (m *Writer) HealthCheck(ctx context.Context) error {
+	m.mu.Lock()
+	client := m.client
+	m.mu.Unlock()
+
+	if client == nil {
+		return types.ErrNotConnected
+	}
+	return client.Ping(ctx, nil)
+}
+
 // CloseAsync begins cleaning up resources used by this writer asynchronously.
 func (m *Writer) CloseAsync() {
 	go func() {
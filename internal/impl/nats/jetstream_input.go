@@ -42,6 +42,7 @@ This input adds the following metadata fields to each message:
 
 ` + "```text" + `
 - nats_subject
+- All message headers (when supported by the connection)
 ` + "```" + `
 
 You can access these metadata fields using
@@ -211,7 +212,11 @@ func (j *jetStreamReader) ReadWithContext(ctx context.Context) (types.Message, r
 	}
 
 	msg := message.New([][]byte{nmsg.Data})
-	msg.Get(0).Metadata().Set("nats_subject", nmsg.Subject)
+	part := msg.Get(0)
+	part.Metadata().Set("nats_subject", nmsg.Subject)
+	for k := range nmsg.Header {
+		part.Metadata().Set(k, nmsg.Header.Get(k))
+	}
 
 	return msg, func(ctx context.Context, res types.Response) error {
 		if res.Error() == nil {
@@ -0,0 +1,143 @@
+package nats
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/cache"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKVEntry struct {
+	nats.KeyValueEntry
+
+	value    []byte
+	revision uint64
+}
+
+func (e *fakeKVEntry) Value() []byte {
+	return e.value
+}
+
+func (e *fakeKVEntry) Revision() uint64 {
+	return e.revision
+}
+
+// fakeKV implements the subset of a key value bucket used by the cache, where
+// writes conditional on a revision fail in the same way as a real bucket.
+type fakeKV struct {
+	nats.KeyValue
+
+	seq       uint64
+	entries   map[string]*fakeKVEntry
+	createErr error
+	afterGet  func()
+}
+
+func newFakeKV() *fakeKV {
+	return &fakeKV{entries: map[string]*fakeKVEntry{}}
+}
+
+func (f *fakeKV) Get(key string) (nats.KeyValueEntry, error) {
+	e, exists := f.entries[key]
+	if f.afterGet != nil {
+		f.afterGet()
+	}
+	if !exists {
+		return nil, nats.ErrKeyNotFound
+	}
+	return e, nil
+}
+
+func (f *fakeKV) Put(key string, value []byte) (uint64, error) {
+	f.seq++
+	f.entries[key] = &fakeKVEntry{value: value, revision: f.seq}
+	return f.seq, nil
+}
+
+func (f *fakeKV) Create(key string, value []byte) (uint64, error) {
+	if f.createErr != nil {
+		return 0, f.createErr
+	}
+	return f.Update(key, value, 0)
+}
+
+func (f *fakeKV) Update(key string, value []byte, last uint64) (uint64, error) {
+	var current uint64
+	if e, exists := f.entries[key]; exists {
+		current = e.revision
+	}
+	if current != last {
+		return 0, fmt.Errorf("nats: wrong last sequence: %v", current)
+	}
+	return f.Put(key, value)
+}
+
+func TestKVCacheAdd(t *testing.T) {
+	kv := newFakeKV()
+	c := &kvCache{kv: kv}
+
+	require.NoError(t, c.Add("foo", []byte("first")))
+	assert.Equal(t, types.ErrKeyAlreadyExists, c.Add("foo", []byte("second")))
+
+	v, err := c.Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(v))
+
+	// Other errors are returned even when the key exists.
+	kv.createErr = errors.New("nats: timeout")
+	assert.EqualError(t, c.Add("foo", []byte("third")), "nats: timeout")
+}
+
+func TestKVCacheCompareAndSwap(t *testing.T) {
+	kv := newFakeKV()
+	c := &kvCache{kv: kv}
+
+	swapped, err := c.CompareAndSwap("foo", []byte("nope"), []byte("first"))
+	require.NoError(t, err)
+	assert.False(t, swapped, "missing key with non-nil old")
+
+	swapped, err = c.CompareAndSwap("foo", nil, []byte("first"))
+	require.NoError(t, err)
+	assert.True(t, swapped, "missing key with nil old")
+
+	swapped, err = c.CompareAndSwap("foo", nil, []byte("second"))
+	require.NoError(t, err)
+	assert.False(t, swapped, "existing key with nil old")
+
+	swapped, err = c.CompareAndSwap("foo", []byte("nope"), []byte("second"))
+	require.NoError(t, err)
+	assert.False(t, swapped, "mismatched old")
+
+	swapped, err = c.CompareAndSwap("foo", []byte("first"), []byte("second"))
+	require.NoError(t, err)
+	assert.True(t, swapped, "matching old")
+
+	// A write between reading the key and updating it results in no swap.
+	kv.afterGet = func() {
+		kv.afterGet = nil
+		_, _ = kv.Put("foo", []byte("second"))
+	}
+	swapped, err = c.CompareAndSwap("foo", []byte("second"), []byte("third"))
+	require.NoError(t, err)
+	assert.False(t, swapped, "concurrent write")
+
+	v, err := c.Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(v))
+}
+
+func TestKVCacheTTLWithoutCreateBucket(t *testing.T) {
+	conf := cache.NewNATSKVConfig()
+	conf.Bucket = "foo"
+	conf.TTL = "60s"
+
+	_, err := newKVCache(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "a ttl can only be set when create_bucket is true, as the max age of an existing bucket cannot be changed")
+}
@@ -0,0 +1,352 @@
+package snowflake
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	ioutput "github.com/Jeffail/benthos/v3/internal/component/output"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message/batch"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/gofrs/uuid"
+	"github.com/snowflakedb/gosnowflake"
+	"github.com/youmark/pkcs8"
+)
+
+func init() {
+	bundle.AllOutputs.Add(bundle.OutputConstructorFromSimple(func(c output.Config, nm bundle.NewManagement) (output.Type, error) {
+		s, err := newSnowflakePutOutput(c.SnowflakePut, nm.Logger(), nm.Metrics())
+		if err != nil {
+			return nil, err
+		}
+		w, err := output.NewAsyncWriter(output.TypeSnowflakePut, c.SnowflakePut.MaxInFlight, s, nm.Logger(), nm.Metrics())
+		if err != nil {
+			return nil, err
+		}
+		return output.NewBatcherFromConfig(c.SnowflakePut.Batching, w, nm, nm.Logger(), nm.Metrics())
+	}), docs.ComponentSpec{
+		Name:    output.TypeSnowflakePut,
+		Type:    docs.TypeOutput,
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Categories: []string{
+			string(output.CategoryServices),
+		},
+		Summary: `
+Writes batches of messages as files to a Snowflake internal stage and loads them
+into a table.`,
+		Description: ioutput.Description(true, true, `
+Each batch of messages is written as a single file, with each message on its own
+line, which is uploaded to an internal stage with a `+"`PUT`"+` command. Once
+uploaded the file is loaded into the target table with a `+"`COPY INTO`"+`
+command using the file format described by the field `+"`file_format`"+`.
+
+The size of each file is therefore determined by the
+[batching policy](/docs/configuration/batching) of this output, and larger
+batches are generally more efficient to load.
+
+### Authentication
+
+Only key pair authentication is supported, where the field
+`+"`private_key_file`"+` must point to a PEM encoded PKCS8 private key, which
+may optionally be encrypted with the passphrase `+"`private_key_pass`"+`. More
+information on configuring key pair authentication can be found
+[in the Snowflake documentation](https://docs.snowflake.com/en/user-guide/key-pair-auth.html).`),
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "JSON Documents",
+				Summary: "Batches of JSON documents are uploaded as gzip compressed files to the stage of a table with a single `VARIANT` column and then loaded into it.",
+				Config: `
+output:
+  snowflake_put:
+    account: xy12345
+    region: eu-west-1
+    user: benthos
+    private_key_file: ./rsa_key.p8
+    role: BENTHOS_ROLE
+    database: BENTHOS_DB
+    warehouse: COMPUTE_WH
+    schema: PUBLIC
+    table: EVENTS
+    path: benthos
+    file_format: TYPE = JSON
+    compression: GZIP
+    batching:
+      count: 10000
+      period: 30s
+`,
+			},
+		},
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldCommon("account", "The Snowflake account identifier, which is the first part of your account URL.", "xy12345"),
+			docs.FieldCommon("region", "The region of your Snowflake account, which can be left empty for accounts in `us-west-2`.", "eu-west-1", "east-us-2"),
+			docs.FieldAdvanced("cloud", "The cloud platform of your Snowflake account, which is required for accounts hosted outside of AWS.", "aws", "gcp", "azure"),
+			docs.FieldCommon("user", "The user to authenticate as."),
+			docs.FieldCommon("private_key_file", "The path to a PEM encoded PKCS8 private key used for key pair authentication."),
+			docs.FieldAdvanced("private_key_pass", "An optional passphrase for decrypting the private key."),
+			docs.FieldCommon("role", "The role to use for the session."),
+			docs.FieldCommon("database", "The database containing the target table."),
+			docs.FieldCommon("warehouse", "The warehouse to use for loading files."),
+			docs.FieldCommon("schema", "The schema containing the target table."),
+			docs.FieldCommon("table", "The table to load files into."),
+			docs.FieldAdvanced("stage", "The internal stage to upload files to. When left empty files are uploaded to the stage of the target table.", "@my_stage"),
+			docs.FieldAdvanced("path", "An optional path within the stage to upload files to."),
+			docs.FieldCommon("file_format", "The format options used by `COPY INTO` when loading files.", "TYPE = JSON", "TYPE = CSV FIELD_DELIMITER = ','"),
+			docs.FieldCommon("compression", "The compression to apply to files before they are uploaded.").HasOptions("NONE", "GZIP"),
+			docs.FieldCommon("max_in_flight", "The maximum number of files to have in flight at a given time. Increase this to improve throughput."),
+			batch.FieldSpec(),
+		).ChildDefaultAndTypesFromStruct(output.NewSnowflakePutConfig()),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// getPrivateKey reads and parses a PEM encoded PKCS8 private key, decrypting it
+// with a passphrase when one is provided.
+func getPrivateKey(path, pass string) (*rsa.PrivateKey, error) {
+	keyBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		return nil, errors.New("failed to decode private key file: no PEM data found")
+	}
+
+	if pass != "" {
+		return pkcs8.ParsePKCS8PrivateKeyRSA(block.Bytes, []byte(pass))
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an RSA private key, found %T", key)
+	}
+	return rsaKey, nil
+}
+
+//------------------------------------------------------------------------------
+
+type snowflakePutOutput struct {
+	conf          output.SnowflakePutConfig
+	dsn           string
+	stageLocation string
+	fileExt       string
+
+	dbMut sync.RWMutex
+	db    *sql.DB
+
+	log   log.Modular
+	stats metrics.Type
+}
+
+func newSnowflakePutOutput(conf output.SnowflakePutConfig, log log.Modular, stats metrics.Type) (*snowflakePutOutput, error) {
+	if conf.Account == "" {
+		return nil, errors.New("an account must be specified")
+	}
+	if conf.Table == "" {
+		return nil, errors.New("a table must be specified")
+	}
+	if conf.PrivateKeyFile == "" {
+		return nil, errors.New("a private key file must be specified")
+	}
+
+	s := &snowflakePutOutput{
+		conf:  conf,
+		log:   log,
+		stats: stats,
+	}
+
+	switch strings.ToUpper(conf.Compression) {
+	case "NONE":
+	case "GZIP":
+		s.fileExt = ".gz"
+	default:
+		return nil, fmt.Errorf("compression not recognised: %v", conf.Compression)
+	}
+
+	s.stageLocation = conf.Stage
+	if s.stageLocation == "" {
+		s.stageLocation = "@%" + conf.Table
+	}
+	if path := strings.Trim(conf.Path, "/"); path != "" {
+		s.stageLocation += "/" + path
+	}
+
+	privateKey, err := getPrivateKey(conf.PrivateKeyFile, conf.PrivateKeyPass)
+	if err != nil {
+		return nil, err
+	}
+
+	region := conf.Region
+	if conf.Cloud != "" {
+		region += "." + conf.Cloud
+	}
+	if s.dsn, err = gosnowflake.DSN(&gosnowflake.Config{
+		Account:       conf.Account,
+		Region:        region,
+		User:          conf.User,
+		Role:          conf.Role,
+		Database:      conf.Database,
+		Warehouse:     conf.Warehouse,
+		Schema:        conf.Schema,
+		Authenticator: gosnowflake.AuthTypeJwt,
+		PrivateKey:    privateKey,
+		Application:   "benthos",
+	}); err != nil {
+		return nil, fmt.Errorf("failed to construct connection string: %w", err)
+	}
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s *snowflakePutOutput) putStatement(fileName string) string {
+	return fmt.Sprintf(
+		"PUT file://%v %v AUTO_COMPRESS = FALSE SOURCE_COMPRESSION = %v",
+		fileName, s.stageLocation, strings.ToUpper(s.conf.Compression),
+	)
+}
+
+func (s *snowflakePutOutput) copyStatement(fileName string) string {
+	return fmt.Sprintf(
+		"COPY INTO %v FROM %v FILES = ('%v') FILE_FORMAT = (%v)",
+		s.conf.Table, s.stageLocation, fileName, s.conf.FileFormat,
+	)
+}
+
+func (s *snowflakePutOutput) fileContents(msg types.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if s.fileExt == "" {
+		_ = msg.Iter(func(i int, p types.Part) error {
+			buf.Write(p.Get())
+			buf.WriteByte('\n')
+			return nil
+		})
+		return buf.Bytes(), nil
+	}
+
+	zw := gzip.NewWriter(&buf)
+	if err := msg.Iter(func(i int, p types.Part) error {
+		if _, err := zw.Write(p.Get()); err != nil {
+			return err
+		}
+		_, err := zw.Write([]byte("\n"))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//------------------------------------------------------------------------------
+
+// ConnectWithContext establishes a connection to Snowflake.
+func (s *snowflakePutOutput) ConnectWithContext(ctx context.Context) error {
+	s.dbMut.Lock()
+	defer s.dbMut.Unlock()
+
+	if s.db != nil {
+		return nil
+	}
+
+	db, err := sql.Open("snowflake", s.dsn)
+	if err != nil {
+		return err
+	}
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return err
+	}
+
+	s.db = db
+	s.log.Infof("Loading files into Snowflake table %v via stage %v\n", s.conf.Table, s.stageLocation)
+	return nil
+}
+
+// WriteWithContext uploads a batch of messages as a single file to the stage
+// and then loads it into the target table.
+func (s *snowflakePutOutput) WriteWithContext(ctx context.Context, msg types.Message) error {
+	s.dbMut.RLock()
+	db := s.db
+	s.dbMut.RUnlock()
+
+	if db == nil {
+		return types.ErrNotConnected
+	}
+
+	contents, err := s.fileContents(msg)
+	if err != nil {
+		return fmt.Errorf("failed to compress file: %w", err)
+	}
+
+	fileUUID, err := uuid.NewV4()
+	if err != nil {
+		return fmt.Errorf("failed to generate file name: %w", err)
+	}
+	fileName := fileUUID.String() + s.fileExt
+
+	if _, err = db.ExecContext(
+		gosnowflake.WithFileStream(ctx, bytes.NewReader(contents)),
+		s.putStatement(fileName),
+	); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	if _, err = db.ExecContext(ctx, s.copyStatement(fileName)); err != nil {
+		return fmt.Errorf("failed to load file: %w", err)
+	}
+	return nil
+}
+
+// HealthCheck verifies that Snowflake is still reachable.
+func (s *snowflakePutOutput) HealthCheck(ctx context.Context) error {
+	s.dbMut.RLock()
+	db := s.db
+	s.dbMut.RUnlock()
+
+	if db == nil {
+		return types.ErrNotConnected
+	}
+	return db.PingContext(ctx)
+}
+
+// CloseAsync begins cleaning up resources used by this output asynchronously.
+func (s *snowflakePutOutput) CloseAsync() {
+	go func() {
+		s.dbMut.Lock()
+		if s.db != nil {
+			s.db.Close()
+			s.db = nil
+		}
+		s.dbMut.Unlock()
+	}()
+}
+
+// WaitForClose will block until either the output is closed or a specified
+// timeout occurs.
+func (s *snowflakePutOutput) WaitForClose(time.Duration) error {
+	return nil
+}
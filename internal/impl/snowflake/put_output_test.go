@@ -0,0 +1,143 @@
+package snowflake
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youmark/pkcs8"
+)
+
+func writeTestKey(t *testing.T, pass string) (string, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var der []byte
+	blockType := "PRIVATE KEY"
+	if pass != "" {
+		der, err = pkcs8.ConvertPrivateKeyToPKCS8(key, []byte(pass))
+		blockType = "ENCRYPTED PRIVATE KEY"
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+	}
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "rsa_key.p8")
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{
+		Type:  blockType,
+		Bytes: der,
+	}), 0600))
+	return path, key
+}
+
+func TestSnowflakePrivateKey(t *testing.T) {
+	path, key := writeTestKey(t, "")
+	parsed, err := getPrivateKey(path, "")
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	path, key = writeTestKey(t, "foobar")
+	parsed, err = getPrivateKey(path, "foobar")
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = getPrivateKey(path, "nope")
+	require.Error(t, err)
+}
+
+func TestSnowflakeStatements(t *testing.T) {
+	keyPath, _ := writeTestKey(t, "")
+
+	conf := output.NewSnowflakePutConfig()
+	conf.Account = "xy12345"
+	conf.User = "foo"
+	conf.PrivateKeyFile = keyPath
+	conf.Table = "EVENTS"
+	conf.Path = "/benthos/"
+
+	s, err := newSnowflakePutOutput(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	assert.Equal(t, "PUT file://foo.gz @%EVENTS/benthos AUTO_COMPRESS = FALSE SOURCE_COMPRESSION = GZIP", s.putStatement("foo.gz"))
+	assert.Equal(t, "COPY INTO EVENTS FROM @%EVENTS/benthos FILES = ('foo.gz') FILE_FORMAT = (TYPE = JSON)", s.copyStatement("foo.gz"))
+
+	conf.Stage = "@my_stage"
+	conf.Path = ""
+	conf.Compression = "NONE"
+
+	s, err = newSnowflakePutOutput(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	assert.Equal(t, "PUT file://foo @my_stage AUTO_COMPRESS = FALSE SOURCE_COMPRESSION = NONE", s.putStatement("foo"))
+	assert.Equal(t, "COPY INTO EVENTS FROM @my_stage FILES = ('foo') FILE_FORMAT = (TYPE = JSON)", s.copyStatement("foo"))
+}
+
+func TestSnowflakeFileContents(t *testing.T) {
+	keyPath, _ := writeTestKey(t, "")
+
+	conf := output.NewSnowflakePutConfig()
+	conf.Account = "xy12345"
+	conf.User = "foo"
+	conf.PrivateKeyFile = keyPath
+	conf.Table = "EVENTS"
+
+	msg := message.New([][]byte{
+		[]byte(`{"id":1}`),
+		[]byte(`{"id":2}`),
+	})
+
+	s, err := newSnowflakePutOutput(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	contents, err := s.fileContents(msg)
+	require.NoError(t, err)
+
+	zr, err := gzip.NewReader(bytes.NewReader(contents))
+	require.NoError(t, err)
+
+	uncompressed, err := ioutil.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", string(uncompressed))
+
+	conf.Compression = "NONE"
+	s, err = newSnowflakePutOutput(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	contents, err = s.fileContents(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", string(contents))
+}
+
+func TestSnowflakeBadConfig(t *testing.T) {
+	keyPath, _ := writeTestKey(t, "")
+
+	conf := output.NewSnowflakePutConfig()
+	conf.Account = "xy12345"
+	conf.User = "foo"
+	conf.PrivateKeyFile = keyPath
+	conf.Table = "EVENTS"
+	conf.Compression = "LZ4"
+
+	_, err := newSnowflakePutOutput(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "compression not recognised: LZ4")
+
+	conf.Compression = "GZIP"
+	conf.Table = ""
+
+	_, err = newSnowflakePutOutput(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "a table must be specified")
+}
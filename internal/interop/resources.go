@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Jeffail/benthos/v3/lib/credentials"
 	"github.com/Jeffail/benthos/v3/lib/types"
 )
 
@@ -187,3 +188,60 @@ func AccessRateLimit(ctx context.Context, mgr types.Manager, name string, fn fun
 	fn(c)
 	return nil
 }
+
+// ProbeCredentials checks whether a credentials resource has been configured,
+// and returns an error if not.
+func ProbeCredentials(ctx context.Context, mgr types.Manager, name string) error {
+	if gc, ok := mgr.(interface {
+		GetCredentials(name string) (credentials.Resolver, error)
+	}); ok {
+		if _, err := gc.GetCredentials(name); err != nil {
+			return fmt.Errorf("credentials resource '%v' was not found", name)
+		}
+	} else {
+		return errors.New("manager does not support credentials resources")
+	}
+	return nil
+}
+
+// AccessCredentials attempts to access a credentials resource by a unique
+// identifier and executes a closure function with the resolver as an argument.
+// Returns an error if the resource does not exist (or is otherwise
+// inaccessible).
+func AccessCredentials(ctx context.Context, mgr types.Manager, name string, fn func(credentials.Resolver)) error {
+	if nm, ok := mgr.(interface {
+		AccessCredentials(ctx context.Context, name string, fn func(credentials.Resolver)) error
+	}); ok {
+		return nm.AccessCredentials(ctx, name, fn)
+	}
+	if gc, ok := mgr.(interface {
+		GetCredentials(name string) (credentials.Resolver, error)
+	}); ok {
+		r, err := gc.GetCredentials(name)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return types.ErrCredentialsNotFound
+		}
+		fn(r)
+		return nil
+	}
+	return errors.New("manager does not support credentials resources")
+}
+
+// ResolveCredentials resolves the secret referenced by a component from a
+// credentials resource.
+func ResolveCredentials(ctx context.Context, mgr types.Manager, ref credentials.ReferenceConfig) (*credentials.Secret, error) {
+	var secret *credentials.Secret
+	var err error
+	if rerr := AccessCredentials(ctx, mgr, ref.Resource, func(r credentials.Resolver) {
+		secret, err = r.Resolve(ctx, ref.Path)
+	}); rerr != nil {
+		return nil, rerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+	return secret, nil
+}
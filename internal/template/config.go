@@ -210,8 +210,12 @@ func FieldConfigSpec() docs.FieldSpecs {
 	return docs.FieldSpecs{
 		docs.FieldString("name", "The name of the field."),
 		docs.FieldString("description", "A description of the field.").HasDefault(""),
-		docs.FieldString("type", "The scalar type of the field.").HasOptions(
-			"string", "int", "float", "bool",
+		docs.FieldString("type", "The scalar type of the field.").HasAnnotatedOptions(
+			"string", "standard string type",
+			"int", "standard integer type",
+			"float", "standard float type",
+			"bool", "a boolean true/false",
+			"processor", "a processor config, which allows templates to compose processors provided by the user",
 		).LintOptions(),
 		docs.FieldString("kind", "The kind of the field.").HasOptions(
 			"scalar", "map", "list",
@@ -0,0 +1,240 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/component/output"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// Quorum is a broker that implements types.Consumer and broadcasts each message
+// out to an array of outputs, acknowledging the message once a required number
+// of those outputs have confirmed receipt of it.
+type Quorum struct {
+	logger log.Modular
+	stats  metrics.Type
+
+	maxInFlight  int
+	requiredAcks int
+	transactions <-chan types.Transaction
+
+	outputTSChans []chan types.Transaction
+	outputs       []types.Output
+
+	ctx        context.Context
+	close      func()
+	closedChan chan struct{}
+}
+
+// NewQuorum creates a new Quorum type by providing outputs and the number of
+// them that must acknowledge a message before it is acknowledged upstream.
+func NewQuorum(
+	outputs []types.Output, requiredAcks int, logger log.Modular, stats metrics.Type,
+) (*Quorum, error) {
+	if requiredAcks < 1 || requiredAcks > len(outputs) {
+		return nil, fmt.Errorf("required acks must be between 1 and the number of outputs (%v), got %v", len(outputs), requiredAcks)
+	}
+
+	ctx, done := context.WithCancel(context.Background())
+	o := &Quorum{
+		maxInFlight:  1,
+		requiredAcks: requiredAcks,
+		stats:        stats,
+		logger:       logger,
+		transactions: nil,
+		outputs:      outputs,
+		closedChan:   make(chan struct{}),
+		ctx:          ctx,
+		close:        done,
+	}
+
+	o.outputTSChans = make([]chan types.Transaction, len(o.outputs))
+	for i := range o.outputTSChans {
+		o.outputTSChans[i] = make(chan types.Transaction)
+		if err := o.outputs[i].Consume(o.outputTSChans[i]); err != nil {
+			return nil, err
+		}
+		if mif, ok := output.GetMaxInFlight(o.outputs[i]); ok && mif > o.maxInFlight {
+			o.maxInFlight = mif
+		}
+	}
+	return o, nil
+}
+
+// WithMaxInFlight sets the maximum number of in-flight messages this broker
+// supports. This must be set before calling Consume.
+func (o *Quorum) WithMaxInFlight(i int) *Quorum {
+	if i < 1 {
+		i = 1
+	}
+	o.maxInFlight = i
+	return o
+}
+
+//------------------------------------------------------------------------------
+
+// Consume assigns a new transactions channel for the broker to read.
+func (o *Quorum) Consume(transactions <-chan types.Transaction) error {
+	if o.transactions != nil {
+		return types.ErrAlreadyStarted
+	}
+	o.transactions = transactions
+
+	go o.loop()
+	return nil
+}
+
+// Connected returns a boolean indicating whether this output is currently
+// connected to its target, which is true when enough outputs to reach a quorum
+// are connected.
+func (o *Quorum) Connected() bool {
+	connected := 0
+	for _, out := range o.outputs {
+		if out.Connected() {
+			connected++
+		}
+	}
+	return connected >= o.requiredAcks
+}
+
+// MaxInFlight returns the maximum number of in flight messages permitted by the
+// output. This value can be used to determine a sensible value for parent
+// outputs, but should not be relied upon as part of dispatcher logic.
+func (o *Quorum) MaxInFlight() (int, bool) {
+	return o.maxInFlight, true
+}
+
+//------------------------------------------------------------------------------
+
+// loop is an internal loop that brokers incoming messages to many outputs.
+func (o *Quorum) loop() {
+	var (
+		wg         = sync.WaitGroup{}
+		mMsgsRcvd  = o.stats.GetCounter("messages.received")
+		mOutputErr = o.stats.GetCounter("error")
+		mMsgsSnt   = o.stats.GetCounter("messages.sent")
+		mNoQuorum  = o.stats.GetCounter("quorum.failed")
+	)
+
+	defer func() {
+		wg.Wait()
+		for _, c := range o.outputTSChans {
+			close(c)
+		}
+		closeAllOutputs(o.outputs)
+		close(o.closedChan)
+	}()
+
+	sendLoop := func() {
+		defer wg.Done()
+
+		for {
+			var ts types.Transaction
+			var open bool
+			select {
+			case ts, open = <-o.transactions:
+				if !open {
+					return
+				}
+			case <-o.ctx.Done():
+				return
+			}
+			mMsgsRcvd.Incr(1)
+
+			results := make(chan error, len(o.outputTSChans))
+			for target := range o.outputTSChans {
+				msgCopy, i := ts.Payload.Copy(), target
+				go func() {
+					resChan := make(chan types.Response)
+					select {
+					case o.outputTSChans[i] <- types.NewTransaction(msgCopy, resChan):
+					case <-o.ctx.Done():
+						results <- types.ErrTypeClosed
+						return
+					}
+					select {
+					case res := <-resChan:
+						if err := res.Error(); err != nil {
+							o.logger.Errorf("Failed to dispatch quorum message to output '%v': %v\n", i, err)
+							mOutputErr.Incr(1)
+							results <- err
+							return
+						}
+						mMsgsSnt.Incr(1)
+						results <- nil
+					case <-o.ctx.Done():
+						results <- types.ErrTypeClosed
+					}
+				}()
+			}
+
+			// Respond upstream as soon as the result is known, and then wait
+			// for the remaining outputs so that slow outputs still apply back
+			// pressure.
+			acks, fails := 0, 0
+			tolerated := len(o.outputTSChans) - o.requiredAcks
+			var lastErr error
+			responded, closed := false, false
+			for pending := len(o.outputTSChans); pending > 0; pending-- {
+				if err := <-results; err != nil {
+					fails++
+					lastErr = err
+				} else {
+					acks++
+				}
+				if responded || closed {
+					continue
+				}
+				var res types.Response
+				if acks >= o.requiredAcks {
+					res = response.NewAck()
+				} else if fails > tolerated {
+					mNoQuorum.Incr(1)
+					res = response.NewError(fmt.Errorf("failed to reach quorum of %v acknowledgements: %w", o.requiredAcks, lastErr))
+				} else {
+					continue
+				}
+				select {
+				case ts.ResponseChan <- res:
+					responded = true
+				case <-o.ctx.Done():
+					closed = true
+				}
+			}
+			if closed || o.ctx.Err() != nil {
+				return
+			}
+		}
+	}
+
+	// Max in flight
+	for i := 0; i < o.maxInFlight; i++ {
+		wg.Add(1)
+		go sendLoop()
+	}
+}
+
+// CloseAsync shuts down the Quorum broker and stops processing requests.
+func (o *Quorum) CloseAsync() {
+	o.close()
+}
+
+// WaitForClose blocks until the Quorum broker has closed down.
+func (o *Quorum) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-o.closedChan:
+	case <-time.After(timeout):
+		return types.ErrTimeout
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,113 @@
+package broker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ types.Consumer = &Quorum{}
+var _ types.Closable = &Quorum{}
+
+func TestQuorumBadRequiredAcks(t *testing.T) {
+	outputs := []types.Output{&MockOutputType{}, &MockOutputType{}}
+
+	_, err := NewQuorum(outputs, 0, log.Noop(), metrics.Noop())
+	assert.EqualError(t, err, "required acks must be between 1 and the number of outputs (2), got 0")
+
+	_, err = NewQuorum(outputs, 3, log.Noop(), metrics.Noop())
+	assert.EqualError(t, err, "required acks must be between 1 and the number of outputs (2), got 3")
+}
+
+func TestQuorum(t *testing.T) {
+	mockOutputs := []*MockOutputType{{}, {}, {}}
+	outputs := []types.Output{mockOutputs[0], mockOutputs[1], mockOutputs[2]}
+
+	readChan := make(chan types.Transaction)
+	resChan := make(chan types.Response)
+
+	oTM, err := NewQuorum(outputs, 2, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, oTM.Consume(readChan))
+	assert.True(t, oTM.Connected())
+
+	send := func(content string) []chan<- types.Response {
+		t.Helper()
+
+		select {
+		case readChan <- types.NewTransaction(message.New([][]byte{[]byte(content)}), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for broker send")
+		}
+
+		resChans := make([]chan<- types.Response, len(mockOutputs))
+		for i, mo := range mockOutputs {
+			select {
+			case ts := <-mo.TChan:
+				assert.Equal(t, content, string(ts.Payload.Get(0).Get()))
+				resChans[i] = ts.ResponseChan
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for broker propagate")
+			}
+		}
+		return resChans
+	}
+
+	respond := func(resChan chan<- types.Response, err error) {
+		t.Helper()
+		var res types.Response = response.NewAck()
+		if err != nil {
+			res = response.NewError(err)
+		}
+		select {
+		case resChan <- res:
+		case <-time.After(time.Second):
+			t.Fatal("timed out responding to broker")
+		}
+	}
+
+	awaitRes := func() error {
+		t.Helper()
+		select {
+		case res := <-resChan:
+			return res.Error()
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for broker response")
+		}
+		return nil
+	}
+
+	// Two acks reach a quorum before the third output responds.
+	resChans := send("first")
+	respond(resChans[0], nil)
+	respond(resChans[1], nil)
+	require.NoError(t, awaitRes())
+	respond(resChans[2], errors.New("slow and failed"))
+
+	// A single failure is tolerated.
+	resChans = send("second")
+	respond(resChans[0], errors.New("nope"))
+	respond(resChans[1], nil)
+	respond(resChans[2], nil)
+	require.NoError(t, awaitRes())
+
+	// Two failures cannot reach a quorum.
+	resChans = send("third")
+	respond(resChans[1], errors.New("nope one"))
+	respond(resChans[2], errors.New("nope two"))
+	err = awaitRes()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reach quorum of 2 acknowledgements: nope")
+	respond(resChans[0], nil)
+
+	oTM.CloseAsync()
+	require.NoError(t, oTM.WaitForClose(time.Second*5))
+}
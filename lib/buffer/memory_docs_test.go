@@ -28,6 +28,7 @@ func TestMemorySanit(t *testing.T) {
         count: 0
         byte_size: 0
         period: ""
+        idle_period: ""
         check: ""
         processors: []
 `
@@ -87,6 +87,7 @@ const (
 	TypeMemory      = "memory"
 	TypeMongoDB     = "mongodb"
 	TypeMultilevel  = "multilevel"
+	TypeNATSKV      = "nats_kv"
 	TypeRedis       = "redis"
 	TypeRistretto   = "ristretto"
 	TypeS3          = "s3"
@@ -106,6 +107,7 @@ type Config struct {
 	Memory      MemoryConfig     `json:"memory" yaml:"memory"`
 	MongoDB     MongoDBConfig    `json:"mongodb" yaml:"mongodb"`
 	Multilevel  MultilevelConfig `json:"multilevel" yaml:"multilevel"`
+	NATSKV      NATSKVConfig     `json:"nats_kv" yaml:"nats_kv"`
 	Plugin      interface{}      `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Redis       RedisConfig      `json:"redis" yaml:"redis"`
 	Ristretto   RistrettoConfig  `json:"ristretto" yaml:"ristretto"`
@@ -125,6 +127,7 @@ func NewConfig() Config {
 		Memory:      NewMemoryConfig(),
 		MongoDB:     NewMongoDBConfig(),
 		Multilevel:  NewMultilevelConfig(),
+		NATSKV:      NewNATSKVConfig(),
 		Plugin:      nil,
 		Redis:       NewRedisConfig(),
 		Ristretto:   NewRistrettoConfig(),
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"strings"
@@ -28,7 +29,16 @@ multiple cache types to share a memcached cluster under different namespaces.`,
 			docs.FieldCommon("ttl", "A TTL in seconds to set for items, after this period keys will be removed."),
 			docs.FieldAdvanced("retries", "The maximum number of retry attempts to make before abandoning a request."),
 			docs.FieldAdvanced("retry_period", "The duration to wait between retry attempts."),
+			docs.FieldInt("max_idle_conns", "The maximum number of idle connections to keep pooled per server.").AtVersion("3.51.0").Advanced(),
 		},
+		Description: `
+Keys are distributed across the servers using ketama consistent hashing, and
+therefore adding or removing a server only redistributes a proportional share
+of the keys.
+
+The cache also supports compare-and-swap operations by making use of memcached
+CAS tokens, which guarantees that a value is only replaced when it has not been
+modified by another client since it was read.`,
 	}
 }
 
@@ -36,21 +46,23 @@ multiple cache types to share a memcached cluster under different namespaces.`,
 
 // MemcachedConfig is a config struct for a memcached connection.
 type MemcachedConfig struct {
-	Addresses   []string `json:"addresses" yaml:"addresses"`
-	Prefix      string   `json:"prefix" yaml:"prefix"`
-	TTL         int32    `json:"ttl" yaml:"ttl"`
-	Retries     int      `json:"retries" yaml:"retries"`
-	RetryPeriod string   `json:"retry_period" yaml:"retry_period"`
+	Addresses    []string `json:"addresses" yaml:"addresses"`
+	Prefix       string   `json:"prefix" yaml:"prefix"`
+	TTL          int32    `json:"ttl" yaml:"ttl"`
+	Retries      int      `json:"retries" yaml:"retries"`
+	RetryPeriod  string   `json:"retry_period" yaml:"retry_period"`
+	MaxIdleConns int      `json:"max_idle_conns" yaml:"max_idle_conns"`
 }
 
 // NewMemcachedConfig returns a MemcachedConfig with default values.
 func NewMemcachedConfig() MemcachedConfig {
 	return MemcachedConfig{
-		Addresses:   []string{"localhost:11211"},
-		Prefix:      "",
-		TTL:         300,
-		Retries:     3,
-		RetryPeriod: "500ms",
+		Addresses:    []string{"localhost:11211"},
+		Prefix:       "",
+		TTL:          300,
+		Retries:      3,
+		RetryPeriod:  "500ms",
+		MaxIdleConns: 2,
 	}
 }
 
@@ -85,6 +97,17 @@ type Memcached struct {
 	mDelFailedErr  metrics.StatCounter
 	mDelSuccess    metrics.StatCounter
 	mDelLatency    metrics.StatTimer
+	mCASCount      metrics.StatCounter
+	mCASMismatch   metrics.StatCounter
+	mCASFailedErr  metrics.StatCounter
+	mCASSuccess    metrics.StatCounter
+	mCASLatency    metrics.StatTimer
+	mTouchCount    metrics.StatCounter
+	mTouchRetry    metrics.StatCounter
+	mTouchFailed   metrics.StatCounter
+	mTouchNotFound metrics.StatCounter
+	mTouchSuccess  metrics.StatCounter
+	mTouchLatency  metrics.StatTimer
 
 	mc          *memcache.Client
 	retryPeriod time.Duration
@@ -109,6 +132,12 @@ func NewMemcached(
 			return nil, fmt.Errorf("failed to parse retry period string: %v", err)
 		}
 	}
+	selector, err := newKetamaSelector(addresses...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve memcached servers: %v", err)
+	}
+	mc := memcache.NewFromSelector(selector)
+	mc.MaxIdleConns = conf.Memcached.MaxIdleConns
 	return &Memcached{
 		conf:  conf,
 		log:   log,
@@ -137,9 +166,20 @@ func NewMemcached(
 		mDelFailedErr:  stats.GetCounter("delete.failed.error"),
 		mDelSuccess:    stats.GetCounter("delete.success"),
 		mDelLatency:    stats.GetTimer("delete.latency"),
+		mCASCount:      stats.GetCounter("compare_and_swap.count"),
+		mCASMismatch:   stats.GetCounter("compare_and_swap.failed.mismatch"),
+		mCASFailedErr:  stats.GetCounter("compare_and_swap.failed.error"),
+		mCASSuccess:    stats.GetCounter("compare_and_swap.success"),
+		mCASLatency:    stats.GetTimer("compare_and_swap.latency"),
+		mTouchCount:    stats.GetCounter("touch.count"),
+		mTouchRetry:    stats.GetCounter("touch.retry"),
+		mTouchFailed:   stats.GetCounter("touch.failed.error"),
+		mTouchNotFound: stats.GetCounter("touch.failed.not_found"),
+		mTouchSuccess:  stats.GetCounter("touch.success"),
+		mTouchLatency:  stats.GetTimer("touch.latency"),
 
 		retryPeriod: retryPeriod,
-		mc:          memcache.New(addresses...),
+		mc:          mc,
 	}, nil
 }
 
@@ -293,6 +333,94 @@ func (m *Memcached) Add(key string, value []byte) error {
 	return m.AddWithTTL(key, value, nil)
 }
 
+// CompareAndSwap sets the value of a key only if its current value matches old,
+// where a nil old requires that the key does not exist, and returns whether the
+// swap occurred. The CAS token of the key is used in order to ensure that no
+// other client has modified it in the meantime.
+func (m *Memcached) CompareAndSwap(key string, old, value []byte) (bool, error) {
+	m.mCASCount.Incr(1)
+	tStarted := time.Now()
+	defer func() {
+		latency := int64(time.Since(tStarted))
+		m.mCASLatency.Timing(latency)
+		m.mLatency.Timing(latency)
+	}()
+
+	if old == nil {
+		err := m.mc.Add(m.getItemFor(key, value, nil))
+		if err != nil {
+			if errors.Is(err, memcache.ErrNotStored) {
+				m.mCASMismatch.Incr(1)
+				return false, nil
+			}
+			m.mCASFailedErr.Incr(1)
+			return false, err
+		}
+		m.mCASSuccess.Incr(1)
+		return true, nil
+	}
+
+	item, err := m.mc.Get(m.conf.Memcached.Prefix + key)
+	if err != nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			m.mCASMismatch.Incr(1)
+			return false, nil
+		}
+		m.mCASFailedErr.Incr(1)
+		return false, err
+	}
+	if !bytes.Equal(item.Value, old) {
+		m.mCASMismatch.Incr(1)
+		return false, nil
+	}
+
+	item.Value = value
+	item.Expiration = m.conf.Memcached.TTL
+	if err = m.mc.CompareAndSwap(item); err != nil {
+		if errors.Is(err, memcache.ErrCASConflict) || errors.Is(err, memcache.ErrNotStored) {
+			m.mCASMismatch.Incr(1)
+			return false, nil
+		}
+		m.mCASFailedErr.Incr(1)
+		return false, err
+	}
+
+	m.mCASSuccess.Incr(1)
+	return true, nil
+}
+
+// Touch resets the expiry of an existing key to the provided TTL, or to the
+// configured TTL when nil.
+func (m *Memcached) Touch(key string, ttl *time.Duration) error {
+	m.mTouchCount.Incr(1)
+	tStarted := time.Now()
+
+	item := m.getItemFor(key, nil, ttl)
+	err := m.mc.Touch(item.Key, item.Expiration)
+	for i := 0; i < m.conf.Memcached.Retries && err != nil && !errors.Is(err, memcache.ErrCacheMiss); i++ {
+		m.log.Errorf("Touch command failed: %v\n", err)
+		<-time.After(m.retryPeriod)
+		m.mTouchRetry.Incr(1)
+		err = m.mc.Touch(item.Key, item.Expiration)
+	}
+
+	latency := int64(time.Since(tStarted))
+	m.mTouchLatency.Timing(latency)
+	m.mLatency.Timing(latency)
+
+	if err != nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			m.mTouchNotFound.Incr(1)
+			return types.ErrKeyNotFound
+		}
+		m.mTouchFailed.Incr(1)
+		return err
+	}
+
+	m.mTouchSuccess.Incr(1)
+	return nil
+}
+
 // Delete attempts to remove a key.
 func (m *Memcached) Delete(key string) error {
 	m.mDelCount.Incr(1)
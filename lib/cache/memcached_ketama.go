@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+//------------------------------------------------------------------------------
+
+// ketamaPointsPerServer is the number of points each server is given on the
+// hash ring, matching the ketama implementations of other memcached clients.
+const ketamaPointsPerServer = 160
+
+type ketamaPoint struct {
+	hash uint32
+	addr net.Addr
+}
+
+// ketamaSelector is a memcache.ServerSelector that distributes keys across
+// servers with ketama consistent hashing. Unlike the default selector, which
+// picks a server from the modulo of a key hash, adding or removing a server
+// only remaps the keys of a proportional share of the ring.
+type ketamaSelector struct {
+	addrs  []net.Addr
+	points []ketamaPoint
+}
+
+func newKetamaSelector(servers ...string) (*ketamaSelector, error) {
+	s := &ketamaSelector{}
+	for _, server := range servers {
+		addr, err := resolveMemcachedAddr(server)
+		if err != nil {
+			return nil, err
+		}
+		s.addrs = append(s.addrs, addr)
+
+		// Each md5 digest provides four points, and points are derived from the
+		// configured address so that the ring does not change when the
+		// resolved address of a server does.
+		for i := 0; i < ketamaPointsPerServer/4; i++ {
+			digest := md5.Sum([]byte(server + "-" + strconv.Itoa(i)))
+			for j := 0; j < 4; j++ {
+				s.points = append(s.points, ketamaPoint{
+					hash: binary.LittleEndian.Uint32(digest[j*4:]),
+					addr: addr,
+				})
+			}
+		}
+	}
+	sort.Slice(s.points, func(i, j int) bool {
+		return s.points[i].hash < s.points[j].hash
+	})
+	return s, nil
+}
+
+func resolveMemcachedAddr(server string) (net.Addr, error) {
+	if strings.Contains(server, "/") {
+		return net.ResolveUnixAddr("unix", server)
+	}
+	return net.ResolveTCPAddr("tcp", server)
+}
+
+// PickServer returns the server that owns the first point of the ring at or
+// after the hash of a key, wrapping around to the first point of the ring.
+func (s *ketamaSelector) PickServer(key string) (net.Addr, error) {
+	if len(s.points) == 0 {
+		return nil, memcache.ErrNoServers
+	}
+	digest := md5.Sum([]byte(key))
+	hash := binary.LittleEndian.Uint32(digest[:4])
+	i := sort.Search(len(s.points), func(i int) bool {
+		return s.points[i].hash >= hash
+	})
+	if i == len(s.points) {
+		i = 0
+	}
+	return s.points[i].addr, nil
+}
+
+// Each iterates each server.
+func (s *ketamaSelector) Each(fn func(net.Addr) error) error {
+	for _, addr := range s.addrs {
+		if err := fn(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
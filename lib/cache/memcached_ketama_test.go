@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKetamaSelectorNoServers(t *testing.T) {
+	s, err := newKetamaSelector()
+	require.NoError(t, err)
+
+	_, err = s.PickServer("foo")
+	assert.Equal(t, memcache.ErrNoServers, err)
+}
+
+func TestKetamaSelectorDistribution(t *testing.T) {
+	servers := []string{"127.0.0.1:11211", "127.0.0.2:11211", "127.0.0.3:11211"}
+
+	s, err := newKetamaSelector(servers...)
+	require.NoError(t, err)
+
+	var eachAddrs []string
+	require.NoError(t, s.Each(func(a net.Addr) error {
+		eachAddrs = append(eachAddrs, a.String())
+		return nil
+	}))
+	assert.Equal(t, servers, eachAddrs)
+
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		addr, err := s.PickServer(fmt.Sprintf("key-%v", i))
+		require.NoError(t, err)
+		counts[addr.String()]++
+	}
+	for _, server := range servers {
+		assert.Greater(t, counts[server], 500, server)
+	}
+}
+
+func TestKetamaSelectorAddServer(t *testing.T) {
+	before, err := newKetamaSelector("127.0.0.1:11211", "127.0.0.2:11211", "127.0.0.3:11211")
+	require.NoError(t, err)
+
+	after, err := newKetamaSelector("127.0.0.1:11211", "127.0.0.2:11211", "127.0.0.3:11211", "127.0.0.4:11211")
+	require.NoError(t, err)
+
+	moved := 0
+	for i := 0; i < 4000; i++ {
+		key := fmt.Sprintf("key-%v", i)
+
+		bAddr, err := before.PickServer(key)
+		require.NoError(t, err)
+
+		aAddr, err := after.PickServer(key)
+		require.NoError(t, err)
+
+		if bAddr.String() != aAddr.String() {
+			assert.Equal(t, "127.0.0.4:11211", aAddr.String(), key)
+			moved++
+		}
+	}
+
+	// Roughly a quarter of keys should move to the new server, whereas modulo
+	// hashing would move around three quarters of them.
+	assert.Greater(t, moved, 500)
+	assert.Less(t, moved, 1600)
+}
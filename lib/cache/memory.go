@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"sync"
@@ -22,8 +23,9 @@ func init() {
 		Summary: `
 Stores key/value pairs in a map held in memory. This cache is therefore reset
 every time the service restarts. Each item in the cache has a TTL set from the
-moment it was last edited, after which it will be removed during the next
-compaction.`,
+moment it was last edited, or read with the ` + "`refresh_ttl`" + ` field of the
+` + "[`cache` processor](/docs/components/processors/cache)" + `, after which it
+will be removed during the next compaction.`,
 		Description: `
 The compaction interval determines how often the cache is cleared of expired
 items, and this process is only triggered on writes to the cache. Access to the
@@ -242,6 +244,48 @@ func (m *memoryV2) Delete(_ context.Context, key string) error {
 	return nil
 }
 
+func (m *memoryV2) CompareAndSwap(_ context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	shard := m.getShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+
+	k, exists := shard.items[key]
+	if exists && shard.isExpired(k) {
+		exists = false
+	}
+	if oldValue == nil {
+		if exists {
+			return false, nil
+		}
+	} else if !exists || !bytes.Equal(k.value, oldValue) {
+		return false, nil
+	}
+
+	shard.compaction()
+	shard.items[key] = item{value: newValue, ts: time.Now()}
+	shard.mKeys.Set(int64(len(shard.items)))
+	return true, nil
+}
+
+// Touch resets the time at which an item expires to a full TTL from now. The
+// ttl argument is ignored as items share the TTL of the cache, and items that
+// are exempt from TTLs remain so.
+func (m *memoryV2) Touch(_ context.Context, key string, _ *time.Duration) error {
+	shard := m.getShard(key)
+	shard.Lock()
+	defer shard.Unlock()
+
+	k, exists := shard.items[key]
+	if !exists || shard.isExpired(k) {
+		return types.ErrKeyNotFound
+	}
+	if !k.ts.IsZero() {
+		k.ts = time.Now()
+		shard.items[key] = k
+	}
+	return nil
+}
+
 func (m *memoryV2) Close(context.Context) error {
 	return nil
 }
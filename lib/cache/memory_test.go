@@ -77,6 +77,81 @@ func TestMemoryCache(t *testing.T) {
 	}
 }
 
+func TestMemoryCacheCompareAndSwap(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = "memory"
+
+	c, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	casCache, ok := c.(types.CacheWithCAS)
+	require.True(t, ok)
+
+	swapped, err := casCache.CompareAndSwap("foo", nil, []byte("1"))
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	swapped, err = casCache.CompareAndSwap("foo", nil, []byte("2"))
+	require.NoError(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = casCache.CompareAndSwap("foo", []byte("2"), []byte("3"))
+	require.NoError(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = casCache.CompareAndSwap("bar", []byte("1"), []byte("3"))
+	require.NoError(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = casCache.CompareAndSwap("foo", []byte("1"), []byte("3"))
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	act, err := c.Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "3", string(act))
+
+	_, err = c.Get("bar")
+	assert.Equal(t, types.ErrKeyNotFound, err)
+}
+
+func TestMemoryCacheTouch(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = "memory"
+	conf.Memory.TTL = 1
+	conf.Memory.InitValues = map[string]string{
+		"static": "foo",
+	}
+
+	c, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	touchCache, ok := c.(types.CacheWithTouch)
+	require.True(t, ok)
+
+	assert.Equal(t, types.ErrKeyNotFound, touchCache.Touch("foo", nil))
+	require.NoError(t, touchCache.Touch("static", nil))
+
+	require.NoError(t, c.Set("foo", []byte("1")))
+	require.NoError(t, c.Set("bar", []byte("2")))
+
+	<-time.After(time.Millisecond * 600)
+	require.NoError(t, touchCache.Touch("foo", nil))
+	<-time.After(time.Millisecond * 600)
+
+	act, err := c.Get("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(act))
+
+	_, err = c.Get("bar")
+	assert.Equal(t, types.ErrKeyNotFound, err)
+	assert.Equal(t, types.ErrKeyNotFound, touchCache.Touch("bar", nil))
+
+	act, err = c.Get("static")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(act))
+}
+
 func TestMemoryCacheCompaction(t *testing.T) {
 	conf := NewConfig()
 	conf.Type = "memory"
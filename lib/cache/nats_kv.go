@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"github.com/Jeffail/benthos/v3/lib/util/tls"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSKVConfig contains configuration fields for the NATS JetStream KV cache
+// type.
+type NATSKVConfig struct {
+	URLs         []string   `json:"urls" yaml:"urls"`
+	Bucket       string     `json:"bucket" yaml:"bucket"`
+	CreateBucket bool       `json:"create_bucket" yaml:"create_bucket"`
+	TTL          string     `json:"ttl" yaml:"ttl"`
+	Replicas     int        `json:"replicas" yaml:"replicas"`
+	TLS          tls.Config `json:"tls" yaml:"tls"`
+}
+
+// NewNATSKVConfig creates a new NATSKVConfig with default values.
+func NewNATSKVConfig() NATSKVConfig {
+	return NATSKVConfig{
+		URLs:         []string{nats.DefaultURL},
+		Bucket:       "",
+		CreateBucket: false,
+		TTL:          "",
+		Replicas:     1,
+		TLS:          tls.NewConfig(),
+	}
+}
@@ -85,6 +85,18 @@ type Redis struct {
 	mDelNotFound   metrics.StatCounter
 	mDelSuccess    metrics.StatCounter
 	mDelLatency    metrics.StatTimer
+	mCASCount      metrics.StatCounter
+	mCASRetry      metrics.StatCounter
+	mCASFailedErr  metrics.StatCounter
+	mCASMismatch   metrics.StatCounter
+	mCASSuccess    metrics.StatCounter
+	mCASLatency    metrics.StatTimer
+	mTouchCount    metrics.StatCounter
+	mTouchRetry    metrics.StatCounter
+	mTouchFailed   metrics.StatCounter
+	mTouchNotFound metrics.StatCounter
+	mTouchSuccess  metrics.StatCounter
+	mTouchLatency  metrics.StatTimer
 
 	client      redis.UniversalClient
 	ttl         time.Duration
@@ -147,6 +159,18 @@ func NewRedis(
 		mDelNotFound:   stats.GetCounter("delete.failed.not_found"),
 		mDelSuccess:    stats.GetCounter("delete.success"),
 		mDelLatency:    stats.GetTimer("delete.latency"),
+		mCASCount:      stats.GetCounter("compare_and_swap.count"),
+		mCASRetry:      stats.GetCounter("compare_and_swap.retry"),
+		mCASFailedErr:  stats.GetCounter("compare_and_swap.failed.error"),
+		mCASMismatch:   stats.GetCounter("compare_and_swap.failed.mismatch"),
+		mCASSuccess:    stats.GetCounter("compare_and_swap.success"),
+		mCASLatency:    stats.GetTimer("compare_and_swap.latency"),
+		mTouchCount:    stats.GetCounter("touch.count"),
+		mTouchRetry:    stats.GetCounter("touch.retry"),
+		mTouchFailed:   stats.GetCounter("touch.failed.error"),
+		mTouchNotFound: stats.GetCounter("touch.failed.not_found"),
+		mTouchSuccess:  stats.GetCounter("touch.success"),
+		mTouchLatency:  stats.GetTimer("touch.latency"),
 
 		retryPeriod: retryPeriod,
 		ttl:         ttl,
@@ -349,6 +373,112 @@ func (r *Redis) Delete(key string) error {
 	return err
 }
 
+// redisCASScript atomically compares the current value of a key before setting
+// it, ARGV[1] is set to 1 when the key is expected to not exist.
+var redisCASScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if ARGV[1] == "1" then
+  if current then
+    return 0
+  end
+elseif current ~= ARGV[2] then
+  return 0
+end
+if tonumber(ARGV[4]) > 0 then
+  redis.call("SET", KEYS[1], ARGV[3], "PX", ARGV[4])
+else
+  redis.call("SET", KEYS[1], ARGV[3])
+end
+return 1
+`)
+
+// CompareAndSwap attempts to set the value of a key only if its current value
+// matches oldValue, where a nil oldValue requires that the key does not exist.
+// Returns true if the swap occurred.
+func (r *Redis) CompareAndSwap(key string, oldValue, newValue []byte) (bool, error) {
+	r.mCASCount.Incr(1)
+	tStarted := time.Now()
+
+	key = r.prefix + key
+
+	mustNotExist := "0"
+	if oldValue == nil {
+		mustNotExist = "1"
+	}
+	args := []interface{}{mustNotExist, oldValue, newValue, int64(r.ttl / time.Millisecond)}
+
+	res, err := redisCASScript.Run(r.client, []string{key}, args...).Int()
+	for i := 0; i < r.conf.Redis.Retries && err != nil; i++ {
+		r.log.Errorf("Compare and swap command failed: %v\n", err)
+		<-time.After(r.retryPeriod)
+		r.mCASRetry.Incr(1)
+		res, err = redisCASScript.Run(r.client, []string{key}, args...).Int()
+	}
+
+	latency := int64(time.Since(tStarted))
+	r.mCASLatency.Timing(latency)
+	r.mLatency.Timing(latency)
+
+	if err != nil {
+		r.mCASFailedErr.Incr(1)
+		return false, err
+	}
+	if res == 0 {
+		r.mCASMismatch.Incr(1)
+		return false, nil
+	}
+	r.mCASSuccess.Incr(1)
+	return true, nil
+}
+
+// Touch resets the expiry of an existing key to the provided TTL, or to the
+// configured expiration when nil. When the resulting TTL is zero the expiry of
+// the key is removed.
+func (r *Redis) Touch(key string, ttl *time.Duration) error {
+	r.mTouchCount.Incr(1)
+	tStarted := time.Now()
+
+	key = r.prefix + key
+
+	t := r.ttl
+	if ttl != nil {
+		t = *ttl
+	}
+	touch := func() (bool, error) {
+		if t <= 0 {
+			n, err := r.client.Exists(key).Result()
+			if err != nil || n == 0 {
+				return false, err
+			}
+			return true, r.client.Persist(key).Err()
+		}
+		return r.client.Expire(key, t).Result()
+	}
+
+	exists, err := touch()
+	for i := 0; i < r.conf.Redis.Retries && err != nil; i++ {
+		r.log.Errorf("Touch command failed: %v\n", err)
+		<-time.After(r.retryPeriod)
+		r.mTouchRetry.Incr(1)
+		exists, err = touch()
+	}
+
+	latency := int64(time.Since(tStarted))
+	r.mTouchLatency.Timing(latency)
+	r.mLatency.Timing(latency)
+
+	if err != nil {
+		r.mTouchFailed.Incr(1)
+		return err
+	}
+	if !exists {
+		r.mTouchNotFound.Incr(1)
+		return types.ErrKeyNotFound
+	}
+	r.mTouchSuccess.Incr(1)
+	return nil
+}
+
 // CloseAsync shuts down the cache.
 func (r *Redis) CloseAsync() {
 }
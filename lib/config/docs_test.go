@@ -21,6 +21,7 @@ import (
 	_ "github.com/Jeffail/benthos/v3/public/components/all"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func walkSpecWithConfig(t *testing.T, prefix string, spec docs.FieldSpec, conf interface{}) {
@@ -208,7 +209,10 @@ func walkTypeWithConfig(t *testing.T, prefix string, spec docs.FieldSpec, v refl
 				v.Kind() == reflect.Uint16 ||
 				v.Kind() == reflect.Uint8
 		case docs.FieldTypeUnknown:
-			isCorrect = v.Kind() == reflect.Interface
+			// Types that parse more than one format of config are documented
+			// as unknown.
+			isCorrect = v.Kind() == reflect.Interface ||
+				reflect.PtrTo(v).Implements(reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem())
 		default:
 			isCorrect = false
 		}
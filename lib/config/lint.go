@@ -9,22 +9,36 @@ import (
 )
 
 // Lint attempts to report errors within a user config. Returns a slice of lint
-// results.
-func Lint(rawBytes []byte, _ Type) ([]string, error) {
-	if bytes.HasPrefix(rawBytes, []byte("# BENTHOS LINT DISABLE")) {
-		return nil, nil
-	}
-
-	var rawNode yaml.Node
-	if err := yaml.Unmarshal(rawBytes, &rawNode); err != nil {
+// results. Lints of a warning level are not included, use LintWithWarnings in
+// order to obtain them.
+func Lint(rawBytes []byte, conf Type) ([]string, error) {
+	lints, err := LintWithWarnings(rawBytes, conf)
+	if err != nil {
 		return nil, err
 	}
 
 	var lintStrs []string
-	for _, lint := range Spec().LintYAML(docs.NewLintContext(), &rawNode) {
+	for _, lint := range lints {
 		if lint.Level == docs.LintError {
 			lintStrs = append(lintStrs, fmt.Sprintf("line %v: %v", lint.Line, lint.What))
 		}
 	}
 	return lintStrs, nil
 }
+
+// LintWithWarnings attempts to report errors and advisory warnings, including
+// the use of deprecated fields and components, within a user config. Returns a
+// slice of lints, where the severity of each is described by its level.
+func LintWithWarnings(rawBytes []byte, _ Type) ([]docs.Lint, error) {
+	if bytes.HasPrefix(rawBytes, []byte("# BENTHOS LINT DISABLE")) {
+		return nil, nil
+	}
+
+	var rawNode yaml.Node
+	if err := yaml.Unmarshal(rawBytes, &rawNode); err != nil {
+		return nil, err
+	}
+	lintCtx := docs.NewLintContext()
+	lintCtx.WarnDeprecated = true
+	return Spec().LintYAML(lintCtx, &rawNode), nil
+}
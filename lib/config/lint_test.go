@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/config"
 	_ "github.com/Jeffail/benthos/v3/public/components/all"
 )
@@ -149,6 +150,29 @@ tests:
       - check: errored()
         output:
           drop: {}
+`,
+			lints: nil,
+		},
+		{
+			name: "nats_kv cache with ttl and without create_bucket",
+			conf: `cache_resources:
+  - label: foo
+    nats_kv:
+      bucket: foo
+      ttl: 60s
+`,
+			lints: []string{
+				"line 4: field ttl is only used when the bucket is created and requires create_bucket to be set to true",
+			},
+		},
+		{
+			name: "nats_kv cache with ttl and create_bucket",
+			conf: `cache_resources:
+  - label: foo
+    nats_kv:
+      bucket: foo
+      create_bucket: true
+      ttl: 60s
 `,
 			lints: nil,
 		},
@@ -168,3 +192,34 @@ tests:
 }
 
 //------------------------------------------------------------------------------
+
+func TestConfigLintWarnings(t *testing.T) {
+	conf := []byte(`input:
+  stdin:
+    nope: true
+  processors: []
+`)
+
+	lints, err := config.Lint(conf, config.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := []string{"line 3: field nope not recognised"}, lints; !reflect.DeepEqual(exp, act) {
+		t.Errorf("Wrong lint results: %v != %v", act, exp)
+	}
+
+	dLints, err := config.LintWithWarnings(conf, config.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []docs.Lint{
+		docs.NewLintError(3, "field nope not recognised"),
+		docs.NewLintWarning(4, "field processors is empty and can be removed"),
+	}
+	for i := range dLints {
+		dLints[i].Type = docs.LintCustom
+	}
+	if act := dLints; !reflect.DeepEqual(exp, act) {
+		t.Errorf("Wrong lint results: %v != %v", act, exp)
+	}
+}
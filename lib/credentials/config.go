@@ -0,0 +1,64 @@
+package credentials
+
+import (
+	"github.com/Jeffail/benthos/v3/lib/util/tls"
+)
+
+//------------------------------------------------------------------------------
+
+// Config is the all encompassing configuration struct for credentials
+// resources.
+type Config struct {
+	Label string      `json:"label" yaml:"label"`
+	Vault VaultConfig `json:"vault" yaml:"vault"`
+}
+
+// NewConfig returns a configuration struct fully populated with default values.
+func NewConfig() Config {
+	return Config{
+		Label: "",
+		Vault: NewVaultConfig(),
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// VaultConfig contains configuration fields for resolving credentials from
+// HashiCorp Vault.
+type VaultConfig struct {
+	URL             string     `json:"url" yaml:"url"`
+	Token           string     `json:"token" yaml:"token"`
+	Namespace       string     `json:"namespace" yaml:"namespace"`
+	Paths           []string   `json:"paths" yaml:"paths"`
+	RefreshInterval string     `json:"refresh_interval" yaml:"refresh_interval"`
+	TLS             tls.Config `json:"tls" yaml:"tls"`
+}
+
+// NewVaultConfig creates a new VaultConfig with default values.
+func NewVaultConfig() VaultConfig {
+	return VaultConfig{
+		URL:             "http://127.0.0.1:8200",
+		Token:           "",
+		Namespace:       "",
+		Paths:           []string{},
+		RefreshInterval: "5m",
+		TLS:             tls.NewConfig(),
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ReferenceConfig contains configuration fields for components that reference
+// the credentials of a secret from a credentials resource.
+type ReferenceConfig struct {
+	Resource string `json:"resource" yaml:"resource"`
+	Path     string `json:"path" yaml:"path"`
+}
+
+// NewReferenceConfig creates a new ReferenceConfig with default values.
+func NewReferenceConfig() ReferenceConfig {
+	return ReferenceConfig{
+		Resource: "",
+		Path:     "",
+	}
+}
@@ -0,0 +1,30 @@
+package credentials
+
+import (
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	btls "github.com/Jeffail/benthos/v3/lib/util/tls"
+)
+
+// Spec returns the field specs of a credentials resource.
+func Spec() docs.FieldSpecs {
+	return docs.FieldSpecs{
+		docs.FieldString("label", "A unique label that components use to reference the credentials resource.").HasDefault(""),
+		docs.FieldCommon("vault", "Resolves credentials from [HashiCorp Vault](https://www.vaultproject.io/) and manages their leases.").WithChildren(
+			docs.FieldCommon("url", "The URL of the Vault server."),
+			docs.FieldCommon("token", "The token used to authenticate with Vault. It is recommended to set this with an environment variable.", "${VAULT_TOKEN}"),
+			docs.FieldAdvanced("namespace", "An optional Vault Enterprise namespace to access secrets within."),
+			docs.FieldString("paths", "A list of secret paths to read when the resource is created. The resource fails to start if any of them cannot be read.", []string{"secret/data/foo", "database/creds/readonly"}).Array(),
+			docs.FieldAdvanced("refresh_interval", "The period after which secrets without a lease, such as KV version 2 secrets, are read again."),
+			btls.FieldSpec(),
+		).ChildDefaultAndTypesFromStruct(NewVaultConfig()),
+	}
+}
+
+// ReferenceFieldSpec returns a field spec for a component that connects to a
+// service with the credentials of a secret from a credentials resource.
+func ReferenceFieldSpec(desc string) docs.FieldSpec {
+	return docs.FieldAdvanced("credentials", desc).WithChildren(
+		docs.FieldString("resource", "The label of a [credentials resource](/docs/configuration/resources#credentials) to resolve credentials from, when empty the credentials of the component are used as configured."),
+		docs.FieldString("path", "The path of the secret within the credentials resource.", "database/creds/readonly", "secret/data/foo"),
+	).ChildDefaultAndTypesFromStruct(NewReferenceConfig()).AtVersion("3.51.0")
+}
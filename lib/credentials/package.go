@@ -0,0 +1,4 @@
+// Package credentials implements resolvers of credentials managed by external
+// secret stores, which are shared service wide as resources and referenced by
+// components that connect to services with those credentials.
+package credentials
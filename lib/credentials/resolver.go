@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// Secret is the data of a secret resolved from a credentials resource.
+type Secret struct {
+	Data map[string]interface{}
+
+	changed chan struct{}
+}
+
+// NewSecret creates a secret with data that never changes.
+func NewSecret(data map[string]interface{}) *Secret {
+	return &Secret{Data: data, changed: make(chan struct{})}
+}
+
+// Changed returns a channel that is closed once the data of the secret has been
+// replaced, for example when a lease expired and new credentials were issued.
+// Components should resolve the secret again and reconnect when this happens.
+func (s *Secret) Changed() <-chan struct{} {
+	return s.changed
+}
+
+// String returns the value of a field of the secret as a string.
+func (s *Secret) String(key string) (string, error) {
+	v, exists := s.Data[key]
+	if !exists {
+		return "", fmt.Errorf("secret does not contain the field '%v'", key)
+	}
+	str, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected field '%v' of secret to be a string, got %T", key, v)
+	}
+	return str, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Resolver is a credentials resource that resolves secrets by their path.
+type Resolver interface {
+	// Resolve returns the current data of the secret at a path.
+	Resolve(ctx context.Context, path string) (*Secret, error)
+
+	types.Closable
+}
+
+// New creates a credentials resolver from a config.
+func New(conf Config, log log.Modular, stats metrics.Type) (Resolver, error) {
+	return newVaultResolver(conf.Vault, log, stats)
+}
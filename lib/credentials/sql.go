@@ -0,0 +1,53 @@
+package credentials
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// SQLDataSourceName returns a copy of a data source name of an SQL driver with
+// the username and password replaced by the fields `username` and `password` of
+// a secret, which are the fields of dynamic database credentials issued by
+// Vault.
+func SQLDataSourceName(driver, dsn string, secret *Secret) (string, error) {
+	username, err := secret.String("username")
+	if err != nil {
+		return "", err
+	}
+	password, err := secret.String("password")
+	if err != nil {
+		return "", err
+	}
+
+	switch driver {
+	case "mysql":
+		conf, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse data source name: %w", err)
+		}
+		conf.User = username
+		conf.Passwd = password
+		return conf.FormatDSN(), nil
+	case "postgres", "mssql", "clickhouse":
+		if !strings.Contains(dsn, "://") {
+			return "", fmt.Errorf("data source name of driver %v must be a URL in order to set credentials", driver)
+		}
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse data source name: %w", err)
+		}
+		if driver == "clickhouse" {
+			query := u.Query()
+			query.Set("username", username)
+			query.Set("password", password)
+			u.RawQuery = query.Encode()
+		} else {
+			u.User = url.UserPassword(username, password)
+		}
+		return u.String(), nil
+	}
+	return "", fmt.Errorf("driver %v does not support credentials", driver)
+}
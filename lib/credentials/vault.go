@@ -0,0 +1,339 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/shutdown"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// The period at which secrets are checked for whether they're due a renewal or
+// refresh.
+var vaultCheckInterval = time.Second
+
+type vaultSecret struct {
+	secret    *Secret
+	leaseID   string
+	renewable bool
+	expiresAt time.Time
+	refreshAt time.Time
+}
+
+// vaultResolver resolves secrets from the Vault HTTP API and holds them in
+// memory. Secrets with a renewable lease are renewed in the background before
+// the lease expires, and when a lease can no longer be renewed the secret is
+// read again, which for dynamic secrets results in new credentials.
+type vaultResolver struct {
+	conf            VaultConfig
+	client          *http.Client
+	refreshInterval time.Duration
+
+	log log.Modular
+
+	mRead      metrics.StatCounter
+	mReadErr   metrics.StatCounter
+	mRenew     metrics.StatCounter
+	mRenewErr  metrics.StatCounter
+	mRotations metrics.StatCounter
+
+	secretsMut sync.Mutex
+	secrets    map[string]*vaultSecret
+
+	shutSig *shutdown.Signaller
+}
+
+func newVaultResolver(conf VaultConfig, log log.Modular, stats metrics.Type) (*vaultResolver, error) {
+	if conf.URL == "" {
+		return nil, errors.New("a vault url must be specified")
+	}
+
+	refreshInterval, err := time.ParseDuration(conf.RefreshInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refresh interval: %w", err)
+	}
+
+	client := &http.Client{Timeout: time.Second * 30}
+	if conf.TLS.Enabled {
+		tlsConf, err := conf.TLS.Get()
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = &http.Transport{TLSClientConfig: tlsConf}
+	}
+
+	v := &vaultResolver{
+		conf:            conf,
+		client:          client,
+		refreshInterval: refreshInterval,
+		log:             log,
+		mRead:           stats.GetCounter("read.success"),
+		mReadErr:        stats.GetCounter("read.error"),
+		mRenew:          stats.GetCounter("renew.success"),
+		mRenewErr:       stats.GetCounter("renew.error"),
+		mRotations:      stats.GetCounter("rotated"),
+		secrets:         map[string]*vaultSecret{},
+		shutSig:         shutdown.NewSignaller(),
+	}
+
+	for _, p := range conf.Paths {
+		if _, err := v.read(context.Background(), p); err != nil {
+			return nil, fmt.Errorf("failed to read secret '%v': %w", p, err)
+		}
+	}
+
+	go v.loop()
+	return v, nil
+}
+
+//------------------------------------------------------------------------------
+
+type vaultResponse struct {
+	LeaseID       string          `json:"lease_id"`
+	Renewable     bool            `json:"renewable"`
+	LeaseDuration int64           `json:"lease_duration"`
+	Data          json.RawMessage `json:"data"`
+	Errors        []string        `json:"errors"`
+}
+
+var errVaultSecretNotFound = errors.New("secret not found")
+
+func (v *vaultResolver) request(ctx context.Context, method, path string, body interface{}) (*vaultResponse, error) {
+	var reqBody *bytes.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(bodyBytes)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	ctx, done := v.shutSig.CloseNowCtx(ctx)
+	defer done()
+
+	url := strings.TrimSuffix(v.conf.URL, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.conf.Token)
+	if v.conf.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.conf.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errVaultSecretNotFound
+	}
+
+	var vRes vaultResponse
+	if len(resBytes) > 0 {
+		if err := json.Unmarshal(resBytes, &vRes); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		if len(vRes.Errors) > 0 {
+			return nil, fmt.Errorf("request failed with status %v: %v", res.StatusCode, strings.Join(vRes.Errors, ", "))
+		}
+		return nil, fmt.Errorf("request failed with status %v", res.StatusCode)
+	}
+	return &vRes, nil
+}
+
+// vaultSecretData extracts the data of a secret, unwrapping the inner data of
+// KV version 2 secrets.
+func vaultSecretData(data json.RawMessage) (map[string]interface{}, error) {
+	var kvV2 struct {
+		Data     json.RawMessage `json:"data"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &kvV2); err == nil && len(kvV2.Data) > 0 && len(kvV2.Metadata) > 0 {
+		data = kvV2.Data
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse secret data: %w", err)
+	}
+	if values == nil {
+		return nil, errVaultSecretNotFound
+	}
+	return values, nil
+}
+
+// read reads a secret from Vault and stores it. When the data of a previously
+// read secret has changed the previous secret is flagged as changed.
+func (v *vaultResolver) read(ctx context.Context, path string) (*Secret, error) {
+	res, err := v.request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		v.mReadErr.Incr(1)
+		return nil, err
+	}
+	data, err := vaultSecretData(res.Data)
+	if err != nil {
+		v.mReadErr.Incr(1)
+		return nil, err
+	}
+	v.mRead.Incr(1)
+
+	now := time.Now()
+	vSecret := &vaultSecret{
+		leaseID:   res.LeaseID,
+		renewable: res.Renewable,
+		refreshAt: now.Add(v.refreshInterval),
+	}
+	if res.LeaseDuration > 0 {
+		lease := time.Duration(res.LeaseDuration) * time.Second
+		vSecret.expiresAt = now.Add(lease)
+		vSecret.refreshAt = now.Add(lease * 2 / 3)
+	}
+
+	v.secretsMut.Lock()
+	defer v.secretsMut.Unlock()
+
+	prev, exists := v.secrets[path]
+	if exists && reflect.DeepEqual(prev.secret.Data, data) {
+		vSecret.secret = prev.secret
+	} else {
+		vSecret.secret = NewSecret(data)
+		if exists {
+			v.mRotations.Incr(1)
+			close(prev.secret.changed)
+		}
+	}
+	v.secrets[path] = vSecret
+	return vSecret.secret, nil
+}
+
+// renew attempts to renew the lease of a secret, returns false if the lease
+// could not be renewed.
+func (v *vaultResolver) renew(path string, vSecret *vaultSecret) bool {
+	res, err := v.request(context.Background(), http.MethodPut, "sys/leases/renew", map[string]interface{}{
+		"lease_id": vSecret.leaseID,
+	})
+	if err != nil {
+		v.mRenewErr.Incr(1)
+		v.log.Warnf("Failed to renew lease of secret '%v': %v\n", path, err)
+		return false
+	}
+	if res.LeaseDuration <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	lease := time.Duration(res.LeaseDuration) * time.Second
+
+	// Once a lease reaches its max TTL renewals no longer extend it, at which
+	// point we read the secret again rather than waiting for it to expire.
+	expiresAt := now.Add(lease)
+	if !expiresAt.After(vSecret.expiresAt) {
+		return false
+	}
+	v.mRenew.Incr(1)
+
+	v.secretsMut.Lock()
+	if current, exists := v.secrets[path]; exists && current == vSecret {
+		current.expiresAt = expiresAt
+		current.refreshAt = now.Add(lease * 2 / 3)
+	}
+	v.secretsMut.Unlock()
+	return true
+}
+
+func (v *vaultResolver) refreshDue() {
+	now := time.Now()
+
+	due := map[string]*vaultSecret{}
+	v.secretsMut.Lock()
+	for k, s := range v.secrets {
+		if !now.Before(s.refreshAt) {
+			due[k] = s
+		}
+	}
+	v.secretsMut.Unlock()
+
+	for path, vSecret := range due {
+		if vSecret.leaseID != "" && vSecret.renewable && v.renew(path, vSecret) {
+			continue
+		}
+		if _, err := v.read(context.Background(), path); err != nil {
+			v.log.Errorf("Failed to read secret '%v': %v\n", path, err)
+		} else {
+			v.log.Debugf("Read new version of secret '%v'\n", path)
+		}
+	}
+}
+
+func (v *vaultResolver) loop() {
+	defer v.shutSig.ShutdownComplete()
+
+	ticker := time.NewTicker(vaultCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			v.refreshDue()
+		case <-v.shutSig.CloseAtLeisureChan():
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Resolve returns the current data of a secret, reading it from Vault if it
+// has not yet been read or its lease has expired.
+func (v *vaultResolver) Resolve(ctx context.Context, path string) (*Secret, error) {
+	v.secretsMut.Lock()
+	vSecret, exists := v.secrets[path]
+	v.secretsMut.Unlock()
+
+	if exists && (vSecret.expiresAt.IsZero() || time.Now().Before(vSecret.expiresAt)) {
+		return vSecret.secret, nil
+	}
+	return v.read(ctx, path)
+}
+
+// CloseAsync shuts down the resolver.
+func (v *vaultResolver) CloseAsync() {
+	v.shutSig.CloseNow()
+}
+
+// WaitForClose blocks until the resolver has closed down.
+func (v *vaultResolver) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-time.After(timeout):
+		return types.ErrTimeout
+	case <-v.shutSig.HasClosedChan():
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVault struct {
+	t *testing.T
+
+	mut       sync.Mutex
+	reads     map[string]int
+	renewals  int
+	renewable bool
+}
+
+func (f *fakeVault) handler(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != "footoken" {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+	assert.Equal(f.t, "foons", r.Header.Get("X-Vault-Namespace"))
+
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	var res interface{}
+	switch r.URL.Path {
+	case "/v1/secret/data/foo":
+		f.reads[r.URL.Path]++
+		res = map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"password": fmt.Sprintf("foo%v", f.reads[r.URL.Path])},
+				"metadata": map[string]interface{}{"version": f.reads[r.URL.Path]},
+			},
+		}
+	case "/v1/database/creds/ro":
+		f.reads[r.URL.Path]++
+		res = map[string]interface{}{
+			"lease_id":       fmt.Sprintf("database/creds/ro/%v", f.reads[r.URL.Path]),
+			"renewable":      true,
+			"lease_duration": 1,
+			"data": map[string]interface{}{
+				"username": fmt.Sprintf("user%v", f.reads[r.URL.Path]),
+			},
+		}
+	case "/v1/sys/leases/renew":
+		assert.Equal(f.t, http.MethodPut, r.Method)
+		var body struct {
+			LeaseID string `json:"lease_id"`
+		}
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+		assert.NotEmpty(f.t, body.LeaseID)
+		if !f.renewable {
+			http.Error(w, `{"errors":["lease not found"]}`, http.StatusBadRequest)
+			return
+		}
+		f.renewals++
+		res = map[string]interface{}{
+			"lease_id":       body.LeaseID,
+			"renewable":      true,
+			"lease_duration": 1,
+		}
+	default:
+		http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+		return
+	}
+	require.NoError(f.t, json.NewEncoder(w).Encode(res))
+}
+
+func (f *fakeVault) getReads(path string) int {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.reads[path]
+}
+
+func (f *fakeVault) getRenewals() int {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.renewals
+}
+
+func (f *fakeVault) setRenewable(renewable bool) {
+	f.mut.Lock()
+	f.renewable = renewable
+	f.mut.Unlock()
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, VaultConfig) {
+	t.Helper()
+
+	f := &fakeVault{t: t, reads: map[string]int{}, renewable: true}
+	ts := httptest.NewServer(http.HandlerFunc(f.handler))
+	t.Cleanup(ts.Close)
+
+	conf := NewVaultConfig()
+	conf.URL = ts.URL
+	conf.Token = "footoken"
+	conf.Namespace = "foons"
+	return f, conf
+}
+
+func TestVaultResolve(t *testing.T) {
+	f, conf := newFakeVault(t)
+	conf.Paths = []string{"secret/data/foo"}
+
+	v, err := newVaultResolver(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		v.CloseAsync()
+		require.NoError(t, v.WaitForClose(time.Second))
+	})
+
+	assert.Equal(t, 1, f.getReads("/v1/secret/data/foo"))
+
+	s, err := v.Resolve(context.Background(), "secret/data/foo")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"password": "foo1"}, s.Data)
+	assert.Equal(t, 1, f.getReads("/v1/secret/data/foo"))
+
+	s, err = v.Resolve(context.Background(), "database/creds/ro")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"username": "user1"}, s.Data)
+
+	_, err = v.Resolve(context.Background(), "secret/data/nope")
+	assert.Equal(t, errVaultSecretNotFound, err)
+}
+
+func TestVaultBadPaths(t *testing.T) {
+	_, conf := newFakeVault(t)
+	conf.Paths = []string{"secret/data/nope"}
+
+	_, err := newVaultResolver(conf, log.Noop(), metrics.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secret/data/nope")
+
+	_, conf = newFakeVault(t)
+	conf.Token = "bartoken"
+	conf.Paths = []string{"secret/data/foo"}
+
+	_, err = newVaultResolver(conf, log.Noop(), metrics.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestVaultLeases(t *testing.T) {
+	defer func(i time.Duration) {
+		vaultCheckInterval = i
+	}(vaultCheckInterval)
+	vaultCheckInterval = time.Millisecond * 10
+
+	f, conf := newFakeVault(t)
+	conf.Paths = []string{"database/creds/ro"}
+
+	v, err := newVaultResolver(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		v.CloseAsync()
+		require.NoError(t, v.WaitForClose(time.Second))
+	})
+
+	assert.Eventually(t, func() bool {
+		return f.getRenewals() > 0
+	}, time.Second*5, time.Millisecond*10)
+
+	s, err := v.Resolve(context.Background(), "database/creds/ro")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"username": "user1"}, s.Data)
+	assert.Equal(t, 1, f.getReads("/v1/database/creds/ro"))
+
+	select {
+	case <-s.Changed():
+		t.Fatal("secret changed after a renewal")
+	default:
+	}
+
+	f.setRenewable(false)
+	select {
+	case <-s.Changed():
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for secret to change")
+	}
+	assert.Greater(t, f.getReads("/v1/database/creds/ro"), 1)
+
+	s, err = v.Resolve(context.Background(), "database/creds/ro")
+	require.NoError(t, err)
+	assert.NotEqual(t, map[string]interface{}{"username": "user1"}, s.Data)
+}
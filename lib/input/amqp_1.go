@@ -42,6 +42,16 @@ You can access these metadata fields using
 			),
 			docs.FieldCommon("source_address", "The source address to consume from.", "/foo", "queue:/bar", "topic:/baz"),
 			docs.FieldAdvanced("azure_renew_lock", "Experimental: Azure service bus specific option to renew lock if processing takes more then configured lock time").AtVersion("3.45.0"),
+			docs.FieldAdvanced(
+				"selector",
+				"An optional selector expression used to filter messages on the broker before they are consumed, which is sent as an `apache.org:selector-filter:string` filter when attaching to the source. The expression syntax is broker specific, brokers such as ActiveMQ Artemis and Apache Qpid support JMS style SQL expressions on message properties, whereas Azure Service Bus filtering is configured with subscription rules on the broker instead.",
+				`color = 'red'`, `JMSPriority > 4 AND region IN ('eu', 'us')`,
+			).AtVersion("3.51.0"),
+			docs.FieldString(
+				"source_filters",
+				"An optional map of additional filters to apply to the source when attaching, where each key is the name of the filter, which is also used as its descriptor, and each value is the filter expression. Support for filters and their syntax varies between brokers and any filters not recognised by the broker are ignored or rejected on attach.",
+				map[string]string{"jms-selector": "color = 'red'"},
+			).Map().Advanced().AtVersion("3.51.0"),
 			tls.FieldSpec(),
 			sasl.FieldSpec(),
 		},
@@ -29,6 +29,9 @@ type AsyncReader struct {
 	typeStr string
 	reader  reader.Async
 
+	// Holds a *rateLimitGate when reads are gated by a rate limit.
+	rateLimit atomic.Value
+
 	stats metrics.Type
 	log   log.Modular
 
@@ -66,6 +69,19 @@ func NewAsyncReader(
 
 //------------------------------------------------------------------------------
 
+func (r *AsyncReader) setRateLimit(gate *rateLimitGate) {
+	r.rateLimit.Store(gate)
+}
+
+// recordMessageSizes adds the size in bytes of each message of a batch to a
+// size distribution metric.
+func recordMessageSizes(mSize metrics.StatTimer, msg types.Message) {
+	msg.Iter(func(i int, p types.Part) error {
+		mSize.Timing(int64(len(p.Get())))
+		return nil
+	})
+}
+
 func (r *AsyncReader) loop() {
 	// Metrics paths
 	var (
@@ -77,6 +93,7 @@ func (r *AsyncReader) loop() {
 		mFailedConn = r.stats.GetCounter("connection.failed")
 		mLostConn   = r.stats.GetCounter("connection.lost")
 		mLatency    = r.stats.GetTimer("latency")
+		mSize       = r.stats.GetTimer("message_size")
 	)
 
 	defer func() {
@@ -129,6 +146,10 @@ func (r *AsyncReader) loop() {
 
 	for {
 		readCtx, readDone := r.shutSig.CloseAtLeisureCtx(context.Background())
+		if gate, _ := r.rateLimit.Load().(*rateLimitGate); gate != nil && !gate.access(readCtx) {
+			readDone()
+			return
+		}
 		msg, ackFn, err := r.reader.ReadWithContext(readCtx)
 		readDone()
 
@@ -165,6 +186,7 @@ func (r *AsyncReader) loop() {
 			mCount.Incr(1)
 			mPartsRcvd.Incr(int64(msg.Len()))
 			mRcvd.Incr(1)
+			recordMessageSizes(mSize, msg)
 			r.log.Tracef("Consumed %v messages from '%v'.\n", msg.Len(), r.typeStr)
 		}
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -45,6 +46,21 @@ Consumes messages from one or more Kinesis streams either by automatically balan
 
 Benthos will not store a consumed sequence unless it is acknowledged at the output level, which ensures at-least-once delivery guarantees. However, this also means that by default messages of a given shard cannot be processed concurrently. In order to increase the number of shard messages that can be processed concurrently increase the field ` + "`checkpoint_limit`" + `.
 
+## Static Sharding
+
+Deployments that consist of a fixed set of Benthos instances can divide the
+shards of streams between them without balancing by setting ` + "`shard_count`" + `
+to the number of instances and giving each instance a unique
+` + "`shard_index`" + ` from zero. When connecting the open shards of each stream
+are listed and sorted by their identifier, and each instance consumes the
+shards whose position modulo the shard count matches its index, therefore every
+shard is consumed by exactly one instance. An error is reported if any instance
+would be left without shards. Shards are only assigned when connecting, and
+therefore instances should be restarted after a stream is resharded.
+
+Shards can also be listed explicitly with the ` + "`streams`" + ` syntax
+` + "`foo:0`" + `, in which case each shard must not be listed more than once.
+
 ## Table Schema
 
 It's possible to configure Benthos to create the DynamoDB table required for coordination if it does not already exist. However, if you wish to create this yourself (recommended) then create a table with a string HASH key ` + "`StreamID`" + ` and a string RANGE key ` + "`ShardID`" + `. 
@@ -65,6 +81,8 @@ Use the ` + "`batching`" + ` fields to configure an optional [batching policy](/
 				docs.FieldAdvanced("rebalance_period", "The period of time between each attempt to rebalance shards across clients."),
 				docs.FieldAdvanced("lease_period", "The period of time after which a client that has failed to update a shard checkpoint is assumed to be inactive."),
 				docs.FieldCommon("start_from_oldest", "Whether to consume from the oldest message when a sequence does not yet exist for the stream."),
+				docs.FieldAdvanced("shard_index", "When `shard_count` is set, the index of this input amongst the inputs that statically divide shards, counted from zero.").AtVersion("3.51.0"),
+				docs.FieldAdvanced("shard_count", "An optional number of inputs to [statically divide](#static-sharding) the shards of each stream across. When set to zero shards are balanced across inputs through the DynamoDB table instead.").AtVersion("3.51.0"),
 			}, session.FieldSpecs()...),
 			batch.FieldSpec(),
 		),
@@ -87,6 +105,8 @@ type AWSKinesisConfig struct {
 	LeasePeriod     string                   `json:"lease_period" yaml:"lease_period"`
 	RebalancePeriod string                   `json:"rebalance_period" yaml:"rebalance_period"`
 	StartFromOldest bool                     `json:"start_from_oldest" yaml:"start_from_oldest"`
+	ShardIndex      int                      `json:"shard_index" yaml:"shard_index"`
+	ShardCount      int                      `json:"shard_count" yaml:"shard_count"`
 	Batching        batch.PolicyConfig       `json:"batching" yaml:"batching"`
 }
 
@@ -101,6 +121,8 @@ func NewAWSKinesisConfig() AWSKinesisConfig {
 		LeasePeriod:     "30s",
 		RebalancePeriod: "30s",
 		StartFromOldest: true,
+		ShardIndex:      0,
+		ShardCount:      0,
 		Batching:        batch.NewPolicyConfig(),
 	}
 }
@@ -127,6 +149,7 @@ type kinesisReader struct {
 
 	streamShards    map[string][]string
 	balancedStreams []string
+	shardedStreams  []string
 
 	commitPeriod    time.Duration
 	leasePeriod     time.Duration
@@ -194,6 +217,11 @@ func newKinesisReader(
 					}
 					stream := strings.TrimSpace(withShards[0])
 					shard := strings.TrimSpace(withShards[1])
+					for _, existing := range k.streamShards[stream] {
+						if shard == existing {
+							return nil, fmt.Errorf("shard '%v' of stream '%v' is listed more than once", shard, stream)
+						}
+					}
 					k.streamShards[stream] = append(k.streamShards[stream], shard)
 				} else {
 					if len(k.streamShards) > 0 {
@@ -204,6 +232,17 @@ func newKinesisReader(
 			}
 		}
 	}
+	if conf.ShardCount > 0 {
+		if len(k.streamShards) > 0 {
+			return nil, errors.New("explicit stream shards cannot be combined with a shard_count")
+		}
+		if conf.ShardIndex < 0 || conf.ShardIndex >= conf.ShardCount {
+			return nil, fmt.Errorf("shard_index must be at least 0 and less than the shard_count of %v, got %v", conf.ShardCount, conf.ShardIndex)
+		}
+		k.shardedStreams, k.balancedStreams = k.balancedStreams, nil
+	} else if conf.ShardIndex != 0 {
+		return nil, errors.New("a shard_index can only be used in combination with a shard_count")
+	}
 	if k.commitPeriod, err = time.ParseDuration(k.conf.CommitPeriod); err != nil {
 		return nil, fmt.Errorf("failed to parse commit period string: %v", err)
 	}
@@ -643,6 +682,45 @@ func (k *kinesisReader) runBalancedShards() {
 	}
 }
 
+// assignStreamShards lists the open shards of each sharded stream and assigns
+// those owned by this input, where shards are sorted by their identifier and
+// each is owned by the input that matches its position modulo the count of
+// inputs.
+func (k *kinesisReader) assignStreamShards(ctx context.Context) (map[string][]string, error) {
+	assigned := map[string][]string{}
+	for _, streamID := range k.shardedStreams {
+		var shardIDs []string
+		input := &kinesis.ListShardsInput{
+			StreamName: aws.String(streamID),
+		}
+		for {
+			shardsRes, err := k.svc.ListShardsWithContext(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to obtain stream '%v' shards: %w", streamID, err)
+			}
+			for _, s := range shardsRes.Shards {
+				if !isShardFinished(s) {
+					shardIDs = append(shardIDs, *s.ShardId)
+				}
+			}
+			if shardsRes.NextToken == nil {
+				break
+			}
+			input = &kinesis.ListShardsInput{
+				NextToken: shardsRes.NextToken,
+			}
+		}
+		if len(shardIDs) < k.conf.ShardCount {
+			return nil, fmt.Errorf("shard_count of %v exceeds the %v open shards of stream '%v', which would leave inputs without shards", k.conf.ShardCount, len(shardIDs), streamID)
+		}
+		sort.Strings(shardIDs)
+		for i := k.conf.ShardIndex; i < len(shardIDs); i += k.conf.ShardCount {
+			assigned[streamID] = append(assigned[streamID], shardIDs[i])
+		}
+	}
+	return assigned, nil
+}
+
 func (k *kinesisReader) runExplicitShards() {
 	var wg sync.WaitGroup
 	defer func() {
@@ -707,8 +785,17 @@ func (k *kinesisReader) ConnectWithContext(ctx context.Context) error {
 
 	k.svc = svc
 	k.checkpointer = checkpointer
-	k.msgChan = make(chan asyncMessage)
 
+	if len(k.shardedStreams) > 0 {
+		if k.streamShards, err = k.assignStreamShards(ctx); err != nil {
+			return err
+		}
+		for streamID, shards := range k.streamShards {
+			k.log.Infof("Consuming Kinesis stream '%v' shards %v as shard %v of %v\n", streamID, shards, k.conf.ShardIndex, k.conf.ShardCount)
+		}
+	}
+
+	k.msgChan = make(chan asyncMessage)
 	if len(k.streamShards) > 0 {
 		go k.runExplicitShards()
 	} else {
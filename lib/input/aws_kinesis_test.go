@@ -0,0 +1,150 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockKinesisShards struct {
+	kinesisiface.KinesisAPI
+	pages [][]*kinesis.Shard
+}
+
+func (m *mockKinesisShards) ListShardsWithContext(ctx context.Context, input *kinesis.ListShardsInput, opts ...request.Option) (*kinesis.ListShardsOutput, error) {
+	page := 0
+	if input.NextToken != nil {
+		fmt.Sscanf(*input.NextToken, "%d", &page)
+	} else if input.StreamName == nil {
+		return nil, fmt.Errorf("missing stream name")
+	}
+	out := &kinesis.ListShardsOutput{Shards: m.pages[page]}
+	if page+1 < len(m.pages) {
+		out.NextToken = aws.String(fmt.Sprintf("%d", page+1))
+	}
+	return out, nil
+}
+
+func kinesisTestShard(id string, finished bool) *kinesis.Shard {
+	s := &kinesis.Shard{
+		ShardId:             aws.String(id),
+		SequenceNumberRange: &kinesis.SequenceNumberRange{},
+	}
+	if finished {
+		s.SequenceNumberRange.EndingSequenceNumber = aws.String("100")
+	}
+	return s
+}
+
+func TestKinesisAssignStreamShards(t *testing.T) {
+	svc := &mockKinesisShards{
+		pages: [][]*kinesis.Shard{
+			{
+				kinesisTestShard("shardId-000000000003", false),
+				kinesisTestShard("shardId-000000000000", true),
+				kinesisTestShard("shardId-000000000001", false),
+			},
+			{
+				kinesisTestShard("shardId-000000000004", false),
+				kinesisTestShard("shardId-000000000002", false),
+			},
+		},
+	}
+
+	seen := map[string]int{}
+	for index := 0; index < 2; index++ {
+		conf := NewAWSKinesisConfig()
+		conf.Streams = []string{"foo"}
+		conf.ShardIndex = index
+		conf.ShardCount = 2
+
+		k, err := newKinesisReader(conf, nil, log.Noop(), metrics.Noop())
+		require.NoError(t, err)
+		k.svc = svc
+
+		assigned, err := k.assignStreamShards(context.Background())
+		require.NoError(t, err)
+		for _, s := range assigned["foo"] {
+			seen[s]++
+		}
+		if index == 0 {
+			assert.Equal(t, []string{"shardId-000000000001", "shardId-000000000003"}, assigned["foo"])
+		} else {
+			assert.Equal(t, []string{"shardId-000000000002", "shardId-000000000004"}, assigned["foo"])
+		}
+	}
+	assert.Equal(t, map[string]int{
+		"shardId-000000000001": 1,
+		"shardId-000000000002": 1,
+		"shardId-000000000003": 1,
+		"shardId-000000000004": 1,
+	}, seen)
+
+	conf := NewAWSKinesisConfig()
+	conf.Streams = []string{"foo"}
+	conf.ShardCount = 5
+
+	k, err := newKinesisReader(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	k.svc = svc
+
+	_, err = k.assignStreamShards(context.Background())
+	require.EqualError(t, err, "shard_count of 5 exceeds the 4 open shards of stream 'foo', which would leave inputs without shards")
+}
+
+func TestKinesisBadShardParams(t *testing.T) {
+	testCases := []struct {
+		name       string
+		streams    []string
+		shardIndex int
+		shardCount int
+		errStr     string
+	}{
+		{
+			name:    "duplicate shards",
+			streams: []string{"foo:0,foo:1", "foo:0"},
+			errStr:  "shard '0' of stream 'foo' is listed more than once",
+		},
+		{
+			name:       "shards with explicit shards",
+			streams:    []string{"foo:0"},
+			shardCount: 2,
+			errStr:     "explicit stream shards cannot be combined with a shard_count",
+		},
+		{
+			name:       "shard index out of range",
+			streams:    []string{"foo"},
+			shardIndex: -1,
+			shardCount: 2,
+			errStr:     "shard_index must be at least 0 and less than the shard_count of 2, got -1",
+		},
+		{
+			name:       "shard index without count",
+			streams:    []string{"foo"},
+			shardIndex: 1,
+			errStr:     "a shard_index can only be used in combination with a shard_count",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := NewAWSKinesisConfig()
+			conf.Streams = test.streams
+			conf.ShardIndex = test.shardIndex
+			conf.ShardCount = test.shardCount
+
+			_, err := newKinesisReader(conf, nil, log.Noop(), metrics.Noop())
+			require.EqualError(t, err, test.errStr)
+		})
+	}
+}
@@ -57,6 +57,10 @@ If your notification events are being routed to SQS via an SNS topic then the ev
 
 When using SQS please make sure you have sensible values for ` + "`sqs.max_messages`" + ` and also the visibility timeout of the queue itself. When Benthos consumes an S3 object the SQS message that triggered it is not deleted until the S3 object has been sent onwards. This ensures at-least-once crash resiliency, but also means that if the S3 object takes longer to process than the visibility timeout of your queue then the same objects might be processed multiple times.
 
+## Polling a Bucket
+
+By default the objects of a bucket are walked once, after which the input shuts down. When a ` + "`poll_interval`" + ` is set the bucket is instead listed again each interval once the objects of the previous listing have been consumed, and only objects modified since the previous listing began are downloaded. The field ` + "`poll_jitter`" + ` randomises each interval by a percentage so that many instances polling the same bucket do not list it in lockstep.
+
 ## Downloading Large Files
 
 When downloading large files it's often necessary to process it in streamed parts in order to avoid loading the entire file in memory at a given time. In order to do this a ` + "[`codec`](#codec)" + ` can be specified that determines how to break the input into smaller individual messages.
@@ -81,7 +85,7 @@ This input adds the following metadata fields to each message:
 
 You can access these metadata fields using [function interpolation](/docs/configuration/interpolation#metadata). Note that user defined metadata is case insensitive within AWS, and it is likely that the keys will be received in a capitalized form, if you wish to make them consistent you can map all metadata keys to lower or uppercase using a Bloblang mapping such as ` + "`meta = meta().map_each_key(key -> key.lowercase())`" + `.`,
 
-		FieldSpecs: append(
+		FieldSpecs: append(append(
 			append(docs.FieldSpecs{
 				docs.FieldCommon("bucket", "The bucket to consume from. If the field `sqs.url` is specified this field is optional."),
 				docs.FieldCommon("prefix", "An optional path prefix, if set only objects with the prefix are consumed when walking a bucket."),
@@ -102,7 +106,7 @@ You can access these metadata fields using [function interpolation](/docs/config
 				),
 				docs.FieldAdvanced("max_messages", "The maximum number of SQS messages to consume from each request."),
 			),
-		),
+		), pollScheduleFieldSpecs("An optional period between listings of the bucket, where the bucket is [listed again](#polling-a-bucket) each period in order to consume new objects. When empty the bucket is walked once. This field cannot be used with `sqs.url`.")...),
 		Categories: []Category{
 			CategoryServices,
 			CategoryAWS,
@@ -145,6 +149,8 @@ type AWSS3Config struct {
 	ForcePathStyleURLs bool           `json:"force_path_style_urls" yaml:"force_path_style_urls"`
 	DeleteObjects      bool           `json:"delete_objects" yaml:"delete_objects"`
 	SQS                AWSS3SQSConfig `json:"sqs" yaml:"sqs"`
+	PollInterval       string         `json:"poll_interval" yaml:"poll_interval"`
+	PollJitter         float64        `json:"poll_jitter" yaml:"poll_jitter"`
 }
 
 // NewAWSS3Config creates a new AWSS3Config with default values.
@@ -157,6 +163,8 @@ func NewAWSS3Config() AWSS3Config {
 		ForcePathStyleURLs: false,
 		DeleteObjects:      false,
 		SQS:                NewAWSS3SQSConfig(),
+		PollInterval:       "",
+		PollJitter:         0,
 	}
 }
 
@@ -216,6 +224,17 @@ type staticTargetReader struct {
 	s3         *s3.S3
 	conf       AWSS3Config
 	startAfter *string
+	listed     bool
+
+	// When polling the bucket is listed again each poll, and only objects
+	// modified since the previous listing began are consumed. Objects modified
+	// since the current listing began might also be found by the next one,
+	// and are therefore remembered in order to avoid consuming them twice.
+	poll         *pollSchedule
+	since        time.Time
+	listStarted  time.Time
+	consumed     map[string]struct{}
+	prevConsumed map[string]struct{}
 }
 
 func newStaticTargetReader(
@@ -223,58 +242,97 @@ func newStaticTargetReader(
 	conf AWSS3Config,
 	log log.Modular,
 	s3Client *s3.S3,
+	poll *pollSchedule,
 ) (*staticTargetReader, error) {
-	listInput := &s3.ListObjectsV2Input{
-		Bucket:  aws.String(conf.Bucket),
-		MaxKeys: aws.Int64(100),
-	}
-	if len(conf.Prefix) > 0 {
-		listInput.Prefix = aws.String(conf.Prefix)
-	}
-	output, err := s3Client.ListObjectsV2WithContext(ctx, listInput)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list objects: %v", err)
-	}
 	staticKeys := staticTargetReader{
 		s3:   s3Client,
 		conf: conf,
+		poll: poll,
 	}
-	for _, obj := range output.Contents {
-		ackFn := deleteS3ObjectAckFn(s3Client, conf.Bucket, *obj.Key, conf.DeleteObjects, nil)
-		staticKeys.pending = append(staticKeys.pending, newS3ObjectTarget(*obj.Key, conf.Bucket, time.Time{}, ackFn))
+	if poll != nil {
+		if err := poll.wait(ctx); err != nil {
+			return nil, err
+		}
+		staticKeys.startListing()
 	}
-	if len(output.Contents) > 0 {
-		staticKeys.startAfter = output.Contents[len(output.Contents)-1].Key
+	if err := staticKeys.listPage(ctx); err != nil {
+		return nil, err
 	}
 	return &staticKeys, nil
 }
 
-func (s *staticTargetReader) Pop(ctx context.Context) (*s3ObjectTarget, error) {
-	if len(s.pending) == 0 && s.startAfter != nil {
-		s.pending = nil
-		listInput := &s3.ListObjectsV2Input{
-			Bucket:     aws.String(s.conf.Bucket),
-			MaxKeys:    aws.Int64(100),
-			StartAfter: s.startAfter,
+func (s *staticTargetReader) startListing() {
+	s.startAfter = nil
+	s.listed = false
+	s.since, s.listStarted = s.listStarted, time.Now()
+	s.prevConsumed, s.consumed = s.consumed, map[string]struct{}{}
+}
+
+// isNew returns whether an object listed whilst polling has not already been
+// consumed by a previous listing.
+func (s *staticTargetReader) isNew(obj *s3.Object) bool {
+	var modified time.Time
+	if obj.LastModified != nil {
+		modified = *obj.LastModified
+	}
+	id := *obj.Key + "@" + modified.String()
+	if !s.since.IsZero() {
+		if modified.Before(s.since) {
+			return false
 		}
-		if len(s.conf.Prefix) > 0 {
-			listInput.Prefix = aws.String(s.conf.Prefix)
+		if _, exists := s.prevConsumed[id]; exists {
+			return false
 		}
-		output, err := s.s3.ListObjectsV2WithContext(ctx, listInput)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list objects: %v", err)
+	}
+	if !modified.Before(s.listStarted) {
+		s.consumed[id] = struct{}{}
+	}
+	return true
+}
+
+func (s *staticTargetReader) listPage(ctx context.Context) error {
+	listInput := &s3.ListObjectsV2Input{
+		Bucket:     aws.String(s.conf.Bucket),
+		MaxKeys:    aws.Int64(100),
+		StartAfter: s.startAfter,
+	}
+	if len(s.conf.Prefix) > 0 {
+		listInput.Prefix = aws.String(s.conf.Prefix)
+	}
+	output, err := s.s3.ListObjectsV2WithContext(ctx, listInput)
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %v", err)
+	}
+	for _, obj := range output.Contents {
+		if s.poll != nil && !s.isNew(obj) {
+			continue
 		}
-		for _, obj := range output.Contents {
-			ackFn := deleteS3ObjectAckFn(s.s3, s.conf.Bucket, *obj.Key, s.conf.DeleteObjects, nil)
-			s.pending = append(s.pending, newS3ObjectTarget(*obj.Key, s.conf.Bucket, time.Time{}, ackFn))
+		ackFn := deleteS3ObjectAckFn(s.s3, s.conf.Bucket, *obj.Key, s.conf.DeleteObjects, nil)
+		s.pending = append(s.pending, newS3ObjectTarget(*obj.Key, s.conf.Bucket, time.Time{}, ackFn))
+	}
+	if len(output.Contents) > 0 {
+		s.startAfter = output.Contents[len(output.Contents)-1].Key
+	} else {
+		s.listed = true
+	}
+	return nil
+}
+
+func (s *staticTargetReader) Pop(ctx context.Context) (*s3ObjectTarget, error) {
+	for len(s.pending) == 0 {
+		if s.listed {
+			if s.poll == nil {
+				return nil, io.EOF
+			}
+			if err := s.poll.wait(ctx); err != nil {
+				return nil, err
+			}
+			s.startListing()
 		}
-		if len(output.Contents) > 0 {
-			s.startAfter = output.Contents[len(output.Contents)-1].Key
+		if err := s.listPage(ctx); err != nil {
+			return nil, err
 		}
 	}
-	if len(s.pending) == 0 {
-		return nil, io.EOF
-	}
 	obj := s.pending[0]
 	s.pending = s.pending[1:]
 	return obj, nil
@@ -555,6 +613,7 @@ type awsS3 struct {
 	sqs     *sqs.SQS
 
 	gracePeriod time.Duration
+	poll        *pollSchedule
 
 	objectMut sync.Mutex
 	object    *s3PendingObject
@@ -582,6 +641,9 @@ func newAmazonS3(
 	if conf.Prefix != "" && conf.SQS.URL != "" {
 		return nil, errors.New("cannot specify both a prefix and sqs.url")
 	}
+	if conf.PollInterval != "" && conf.SQS.URL != "" {
+		return nil, errors.New("cannot specify both a poll_interval and sqs.url")
+	}
 	s := &awsS3{
 		conf:  conf,
 		log:   log,
@@ -596,6 +658,9 @@ func newAmazonS3(
 			return nil, fmt.Errorf("failed to parse grace period: %w", err)
 		}
 	}
+	if s.poll, err = newPollSchedule(conf.PollInterval, conf.PollJitter); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
@@ -603,7 +668,7 @@ func (a *awsS3) getTargetReader(ctx context.Context) (s3ObjectTargetReader, erro
 	if a.sqs != nil {
 		return newSQSTargetReader(a.conf, a.log, a.s3, a.sqs), nil
 	}
-	return newStaticTargetReader(ctx, a.conf, a.log, a.s3)
+	return newStaticTargetReader(ctx, a.conf, a.log, a.s3, a.poll)
 }
 
 // ConnectWithContext attempts to establish a connection to the target S3 bucket
@@ -0,0 +1,51 @@
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSS3PollConfigErrors(t *testing.T) {
+	conf := NewAWSS3Config()
+	conf.Bucket = "foo"
+	conf.PollInterval = "1m"
+	conf.SQS.URL = "http://localhost:4566/queue"
+	_, err := newAmazonS3(conf, nil, nil)
+	require.EqualError(t, err, "cannot specify both a poll_interval and sqs.url")
+
+	conf.SQS.URL = ""
+	conf.PollJitter = 101
+	_, err = newAmazonS3(conf, nil, nil)
+	require.EqualError(t, err, "poll jitter must be between 0 and 100, received 101")
+}
+
+func TestAWSS3StaticTargetPollFilter(t *testing.T) {
+	s := &staticTargetReader{}
+	obj := func(key string, modified time.Time) *s3.Object {
+		return &s3.Object{
+			Key:          aws.String(key),
+			LastModified: aws.Time(modified),
+		}
+	}
+
+	// The first listing consumes every object.
+	s.startListing()
+	firstStarted := s.listStarted
+	assert.True(t, s.isNew(obj("old", firstStarted.Add(-time.Hour))))
+	assert.True(t, s.isNew(obj("during", firstStarted.Add(time.Millisecond))))
+
+	// The next listing only consumes objects modified since the first began,
+	// excluding those already consumed by it.
+	s.startListing()
+	assert.False(t, s.isNew(obj("old", firstStarted.Add(-time.Hour))))
+	assert.False(t, s.isNew(obj("during", firstStarted.Add(time.Millisecond))))
+	assert.True(t, s.isNew(obj("new", firstStarted.Add(time.Second))))
+
+	// Objects that are modified again are consumed again.
+	assert.True(t, s.isNew(obj("during", firstStarted.Add(time.Second))))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/cenkalti/backoff/v4"
 )
 
@@ -55,7 +56,16 @@ This input adds the following metadata fields to each message:
 ` + "```" + `
 
 You can access these metadata fields using
-[function interpolation](/docs/configuration/interpolation#metadata).`,
+[function interpolation](/docs/configuration/interpolation#metadata).
+
+### Prefetching
+
+Messages are only requested from SQS once the pipeline is ready to consume
+them, and the number of messages requested at a time adapts to the rate at
+which they are consumed, up to a maximum of 10. While the pipeline is applying
+back pressure fewer messages are fetched ahead of time, which reduces the
+number of messages that sit in flight and risk exceeding their visibility
+timeout.`,
 		FieldSpecs: append(docs.FieldSpecs{
 			docs.FieldCommon("url", "The SQS URL to consume from."),
 			docs.FieldAdvanced("delete_message", "Whether to delete the consumed message once it is acked. Disabling allows you to handle the deletion using a different mechanism."),
@@ -91,9 +101,10 @@ type awsSQS struct {
 	conf AWSSQSConfig
 
 	session *session.Session
-	sqs     *sqs.SQS
+	sqs     sqsiface.SQSAPI
 
 	messagesChan     chan *sqs.Message
+	readRequestChan  chan struct{}
 	ackMessagesChan  chan sqsMessageHandle
 	nackMessagesChan chan sqsMessageHandle
 	closeSignal      *shutdown.Signaller
@@ -108,6 +119,7 @@ func newAWSSQS(conf AWSSQSConfig, log log.Modular, stats metrics.Type) (*awsSQS,
 		log:              log,
 		stats:            stats,
 		messagesChan:     make(chan *sqs.Message),
+		readRequestChan:  make(chan struct{}, 1),
 		ackMessagesChan:  make(chan sqsMessageHandle),
 		nackMessagesChan: make(chan sqsMessageHandle),
 		closeSignal:      shutdown.NewSignaller(),
@@ -128,7 +140,13 @@ func (a *awsSQS) ConnectWithContext(ctx context.Context) error {
 
 	a.sqs = sqs.New(sess)
 	a.session = sess
+	a.start()
+
+	a.log.Infof("Receiving Amazon SQS messages from URL: %v\n", a.conf.URL)
+	return nil
+}
 
+func (a *awsSQS) start() {
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go a.readLoop(&wg)
@@ -137,9 +155,6 @@ func (a *awsSQS) ConnectWithContext(ctx context.Context) error {
 		wg.Wait()
 		a.closeSignal.ShutdownComplete()
 	}()
-
-	a.log.Infof("Receiving Amazon SQS messages from URL: %v\n", a.conf.URL)
-	return nil
 }
 
 func (a *awsSQS) ackLoop(wg *sync.WaitGroup) {
@@ -232,12 +247,16 @@ func (a *awsSQS) readLoop(wg *sync.WaitGroup) {
 	backoff.InitialInterval = time.Millisecond
 	backoff.MaxInterval = time.Second
 
+	var fetchSize sqsFetchSize
+	var fetched int
+	var backPressured bool
+
 	getMsgs := func() {
 		ctx, done := a.closeSignal.CloseAtLeisureCtx(context.Background())
 		defer done()
 		res, err := a.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
 			QueueUrl:              aws.String(a.conf.URL),
-			MaxNumberOfMessages:   aws.Int64(10),
+			MaxNumberOfMessages:   aws.Int64(fetchSize.get()),
 			AttributeNames:        []*string{aws.String("All")},
 			MessageAttributeNames: []*string{aws.String("All")},
 		})
@@ -249,12 +268,19 @@ func (a *awsSQS) readLoop(wg *sync.WaitGroup) {
 		}
 		if len(res.Messages) > 0 {
 			pendingMsgs = append(pendingMsgs, res.Messages...)
+			fetched, backPressured = len(res.Messages), false
 			backoff.Reset()
 		}
 	}
 
 	for {
 		if len(pendingMsgs) == 0 {
+			// Only fetch messages once a reader is waiting for them.
+			select {
+			case <-a.readRequestChan:
+			case <-a.closeSignal.CloseAtLeisureChan():
+				return
+			}
 			getMsgs()
 			if len(pendingMsgs) == 0 {
 				select {
@@ -265,15 +291,56 @@ func (a *awsSQS) readLoop(wg *sync.WaitGroup) {
 				continue
 			}
 		}
+		waitStarted := time.Now()
 		select {
 		case a.messagesChan <- pendingMsgs[0]:
 			pendingMsgs = pendingMsgs[1:]
 		case <-a.closeSignal.CloseAtLeisureChan():
 			return
 		}
+		if time.Since(waitStarted) > sqsBackPressureThreshold {
+			backPressured = true
+		}
+		if len(pendingMsgs) == 0 {
+			fetchSize.update(fetched, backPressured)
+		}
 	}
 }
 
+// sqsBackPressureThreshold is how long a fetched message can wait to be
+// consumed before the pipeline is considered to be applying back pressure.
+const sqsBackPressureThreshold = time.Millisecond * 10
+
+const sqsMaxFetchSize = 10
+
+// sqsFetchSize tracks the number of messages to request from SQS at a time,
+// which grows while fetched messages are consumed as soon as they are
+// available and shrinks while they are left waiting for the pipeline.
+type sqsFetchSize struct {
+	size int64
+}
+
+func (f *sqsFetchSize) get() int64 {
+	if f.size < 1 {
+		f.size = 1
+	}
+	return f.size
+}
+
+func (f *sqsFetchSize) update(fetched int, backPressured bool) {
+	size := f.get()
+	if backPressured {
+		if size /= 2; size < 1 {
+			size = 1
+		}
+	} else if int64(fetched) >= size {
+		if size *= 2; size > sqsMaxFetchSize {
+			size = sqsMaxFetchSize
+		}
+	}
+	f.size = size
+}
+
 type sqsMessageHandle struct {
 	id, receiptHandle string
 }
@@ -357,6 +424,12 @@ func (a *awsSQS) ReadWithContext(ctx context.Context) (types.Message, reader.Asy
 		return nil, nil, types.ErrNotConnected
 	}
 
+	// Signal to the read loop that we're ready for a message.
+	select {
+	case a.readRequestChan <- struct{}{}:
+	default:
+	}
+
 	var next *sqs.Message
 	var open bool
 	select {
@@ -0,0 +1,115 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSQSInput struct {
+	sqsiface.SQSAPI
+
+	mut       sync.Mutex
+	nextID    int
+	requested []int64
+}
+
+func (m *mockSQSInput) ReceiveMessageWithContext(ctx aws.Context, input *sqs.ReceiveMessageInput, opts ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	m.requested = append(m.requested, *input.MaxNumberOfMessages)
+
+	var msgs []*sqs.Message
+	for i := int64(0); i < *input.MaxNumberOfMessages; i++ {
+		m.nextID++
+		msgs = append(msgs, &sqs.Message{
+			MessageId:     aws.String(fmt.Sprintf("id%v", m.nextID)),
+			ReceiptHandle: aws.String(fmt.Sprintf("handle%v", m.nextID)),
+			Body:          aws.String(fmt.Sprintf("msg%v", m.nextID)),
+		})
+	}
+	return &sqs.ReceiveMessageOutput{Messages: msgs}, nil
+}
+
+func (m *mockSQSInput) ChangeMessageVisibilityBatchWithContext(ctx aws.Context, input *sqs.ChangeMessageVisibilityBatchInput, opts ...request.Option) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	return &sqs.ChangeMessageVisibilityBatchOutput{}, nil
+}
+
+func (m *mockSQSInput) getRequested() []int64 {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	return append([]int64{}, m.requested...)
+}
+
+func TestAWSSQSAdaptiveFetch(t *testing.T) {
+	mockSQS := &mockSQSInput{}
+
+	a, err := newAWSSQS(NewAWSSQSConfig(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	a.session, a.sqs = &session.Session{}, mockSQS
+	a.start()
+
+	read := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			ctx, done := context.WithTimeout(context.Background(), time.Second)
+			_, _, err := a.ReadWithContext(ctx)
+			done()
+			require.NoError(t, err)
+		}
+	}
+
+	// Nothing is fetched until the pipeline is ready for messages.
+	<-time.After(time.Millisecond * 50)
+	assert.Empty(t, mockSQS.getRequested())
+
+	// Fetches grow while messages are consumed immediately.
+	read(16)
+	assert.Equal(t, []int64{1, 2, 4, 8, 10}, mockSQS.getRequested())
+
+	// And shrink while messages are left waiting.
+	<-time.After(time.Millisecond * 50)
+	read(10)
+	assert.Equal(t, []int64{1, 2, 4, 8, 10, 5}, mockSQS.getRequested())
+
+	a.CloseAsync()
+	require.NoError(t, a.WaitForClose(time.Second*5))
+}
+
+func TestSQSFetchSize(t *testing.T) {
+	var f sqsFetchSize
+	assert.Equal(t, int64(1), f.get())
+
+	f.update(1, false)
+	assert.Equal(t, int64(2), f.get())
+
+	// Partial fetches do not grow the size.
+	f.update(1, false)
+	assert.Equal(t, int64(2), f.get())
+
+	for i := 0; i < 5; i++ {
+		f.update(sqsMaxFetchSize, false)
+	}
+	assert.Equal(t, int64(sqsMaxFetchSize), f.get())
+
+	f.update(sqsMaxFetchSize, true)
+	assert.Equal(t, int64(5), f.get())
+
+	for i := 0; i < 5; i++ {
+		f.update(sqsMaxFetchSize, true)
+	}
+	assert.Equal(t, int64(1), f.get())
+}
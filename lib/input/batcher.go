@@ -153,7 +153,9 @@ func (m *Batcher) loop() {
 			})
 			pendingTrans = append(pendingTrans, trackedTran)
 		case <-nextTimedBatchChan:
-			flushBatch = true
+			// An idle period may have been extended by messages added since
+			// the timer was set, in which case the timer is reset instead.
+			flushBatch = m.batcher.UntilNext() <= 0
 			nextTimedBatchChan = nil
 		case <-m.shutSig.CloseAtLeisureChan():
 			return
@@ -93,7 +93,20 @@ func WalkConstructors(fn func(ConstructorFunc, docs.ComponentSpec)) {
 
 // AppendProcessorsFromConfig takes a variant arg of pipeline constructor
 // functions and returns a new slice of them where the processors of the
-// provided input configuration will also be initialized.
+// provided input configuration will also be initialized, along with pipelines
+// for any other enabled fields of the configuration. Messages flow through the
+// resulting pipelines in the following order, where each step is only present
+// when configured:
+//
+// 1. max_message_bytes: Rejects batches containing oversized messages.
+// 2. validation: Validates the original messages against a schema.
+// 3. lineage: Adds lineage metadata to messages.
+// 4. message_id: Adds identifiers to messages.
+// 5. redelivery_cache: Drops previously processed messages.
+// 6. processors: The processors of the input.
+//
+// The provided pipelines follow all of these. Rate limits are applied
+// separately with ApplyRateLimitFromConfig.
 func AppendProcessorsFromConfig(
 	conf Config,
 	mgr types.Manager,
@@ -120,7 +133,11 @@ func AppendProcessorsFromConfig(
 			return pipeline.NewProcessor(log, stats, processors...), nil
 		}}, pipelines...)
 	}
-	return pipelines
+	pipelines = appendRedeliveryDedupeFromConfig(conf, mgr, log, stats, pipelines...)
+	pipelines = appendMessageIDFromConfig(conf, mgr, log, stats, pipelines...)
+	pipelines = appendLineageFromConfig(conf, mgr, log, stats, pipelines...)
+	pipelines = appendValidationFromConfig(conf, mgr, log, stats, pipelines...)
+	return appendSizeLimitFromConfig(conf, mgr, log, stats, pipelines...)
 }
 
 // TODO: V4 Remove this.
@@ -156,7 +173,12 @@ func appendProcessorsFromConfigBatchAware(
 			return pipeline.NewProcessor(log, stats, processors...), nil
 		}}, pipelines...)
 	}
-	return hasBatchProc, pipelines
+	pipelines = appendRateLimitFromConfig(conf, mgr, log, stats, pipelines...)
+	pipelines = appendRedeliveryDedupeFromConfig(conf, mgr, log, stats, pipelines...)
+	pipelines = appendMessageIDFromConfig(conf, mgr, log, stats, pipelines...)
+	pipelines = appendLineageFromConfig(conf, mgr, log, stats, pipelines...)
+	pipelines = appendValidationFromConfig(conf, mgr, log, stats, pipelines...)
+	return hasBatchProc, appendSizeLimitFromConfig(conf, mgr, log, stats, pipelines...)
 }
 
 func fromSimpleConstructor(fn func(Config, types.Manager, log.Modular, metrics.Type) (Type, error)) ConstructorFunc {
@@ -173,6 +195,9 @@ func fromSimpleConstructor(fn func(Config, types.Manager, log.Modular, metrics.T
 			return nil, fmt.Errorf("failed to create input '%v': %w", conf.Type, err)
 		}
 		pipelines = AppendProcessorsFromConfig(conf, mgr, log, stats, pipelines...)
+		if pipelines, err = ApplyRateLimitFromConfig(conf, input, mgr, log, stats, pipelines...); err != nil {
+			return nil, err
+		}
 		return WrapWithPipelines(input, pipelines...)
 	}
 }
@@ -191,6 +216,9 @@ func fromBatchAwareConstructor(fn func(bool, Config, types.Manager, log.Modular,
 			return nil, fmt.Errorf("failed to create input '%v': %w", conf.Type, err)
 		}
 		pipelines = AppendProcessorsFromConfig(conf, mgr, log, stats, pipelines...)
+		if pipelines, err = ApplyRateLimitFromConfig(conf, input, mgr, log, stats, pipelines...); err != nil {
+			return nil, err
+		}
 		return WrapWithPipelines(input, pipelines...)
 	}
 }
@@ -219,6 +247,7 @@ const (
 	TypeGCPCloudStorage   = "gcp_cloud_storage"
 	TypeGCPPubSub         = "gcp_pubsub"
 	TypeGenerate          = "generate"
+	TypeGraphQL           = "graphql"
 	TypeHDFS              = "hdfs"
 	TypeHTTPClient        = "http_client"
 	TypeHTTPServer        = "http_server"
@@ -277,6 +306,7 @@ type Config struct {
 	GCPCloudStorage   GCPCloudStorageConfig        `json:"gcp_cloud_storage" yaml:"gcp_cloud_storage"`
 	GCPPubSub         reader.GCPPubSubConfig       `json:"gcp_pubsub" yaml:"gcp_pubsub"`
 	Generate          BloblangConfig               `json:"generate" yaml:"generate"`
+	GraphQL           GraphQLConfig                `json:"graphql" yaml:"graphql"`
 	HDFS              reader.HDFSConfig            `json:"hdfs" yaml:"hdfs"`
 	HTTPClient        HTTPClientConfig             `json:"http_client" yaml:"http_client"`
 	HTTPServer        HTTPServerConfig             `json:"http_server" yaml:"http_server"`
@@ -311,6 +341,13 @@ type Config struct {
 	UDPServer         UDPServerConfig              `json:"udp_server" yaml:"udp_server"`
 	Websocket         reader.WebsocketConfig       `json:"websocket" yaml:"websocket"`
 	ZMQ4              *reader.ZMQ4Config           `json:"zmq4,omitempty" yaml:"zmq4,omitempty"`
+	RateLimit         string                       `json:"rate_limit" yaml:"rate_limit"`
+	RedeliveryCache   string                       `json:"redelivery_cache" yaml:"redelivery_cache"`
+	RedeliveryTTL     string                       `json:"redelivery_ttl" yaml:"redelivery_ttl"`
+	Lineage           bool                         `json:"lineage" yaml:"lineage"`
+	MessageID         MessageIDConfig              `json:"message_id" yaml:"message_id"`
+	MaxMessageBytes   int                          `json:"max_message_bytes" yaml:"max_message_bytes"`
+	Validation        ValidationConfig             `json:"validation" yaml:"validation"`
 	Processors        []processor.Config           `json:"processors" yaml:"processors"`
 }
 
@@ -336,6 +373,7 @@ func NewConfig() Config {
 		GCPCloudStorage:   NewGCPCloudStorageConfig(),
 		GCPPubSub:         reader.NewGCPPubSubConfig(),
 		Generate:          NewBloblangConfig(),
+		GraphQL:           NewGraphQLConfig(),
 		HDFS:              reader.NewHDFSConfig(),
 		HTTPClient:        NewHTTPClientConfig(),
 		HTTPServer:        NewHTTPServerConfig(),
@@ -370,6 +408,13 @@ func NewConfig() Config {
 		UDPServer:         NewUDPServerConfig(),
 		Websocket:         reader.NewWebsocketConfig(),
 		ZMQ4:              reader.NewZMQ4Config(),
+		RateLimit:         "",
+		RedeliveryCache:   "",
+		RedeliveryTTL:     "24h",
+		Lineage:           false,
+		MessageID:         NewMessageIDConfig(),
+		MaxMessageBytes:   0,
+		Validation:        NewValidationConfig(),
 		Processors:        []processor.Config{},
 	}
 }
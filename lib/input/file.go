@@ -3,8 +3,11 @@ package input
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -34,8 +37,14 @@ Consumes data from files on disk, emitting messages according to a chosen codec.
 			docs.FieldDeprecated("delimiter"),
 			docs.FieldDeprecated("multipart"),
 			docs.FieldAdvanced("delete_on_finish", "Whether to delete consumed files from the disk once they are fully consumed."),
+			docs.FieldAdvanced("whole_file", "Whether to consume each file as a single message, in which case the `codec` field is ignored. A message is emitted for every matched file, including empty files.").AtVersion("3.51.0"),
+			docs.FieldAdvanced("max_file_size", "The maximum size in bytes of a file consumed when `whole_file` is enabled. Files that exceed this size are skipped with an error. Set to zero in order to disable the limit.").AtVersion("3.51.0"),
 		},
 		Description: `
+### Whole Files
+
+By default files are consumed according to a codec, which usually results in multiple messages per file. When ` + "`whole_file`" + ` is set to ` + "`true`" + ` the entire contents of each matched file are instead emitted as a single message, which is useful for documents that must be processed atomically such as JSON documents or images. In this mode the size of files can be capped with the field ` + "`max_file_size`" + `.
+
 ### Metadata
 
 This input adds the following metadata fields to each message:
@@ -44,6 +53,14 @@ This input adds the following metadata fields to each message:
 - path
 ` + "```" + `
 
+When ` + "`whole_file`" + ` is enabled the following metadata fields are also added:
+
+` + "```text" + `
+- file_size
+- mod_time_unix
+- mod_time (RFC3339)
+` + "```" + `
+
 You can access these metadata fields using
 [function interpolation](/docs/configuration/interpolation#metadata).`,
 		Categories: []Category{
@@ -75,6 +92,8 @@ type FileConfig struct {
 	MaxBuffer      int      `json:"max_buffer" yaml:"max_buffer"`
 	Delim          string   `json:"delimiter" yaml:"delimiter"`
 	DeleteOnFinish bool     `json:"delete_on_finish" yaml:"delete_on_finish"`
+	WholeFile      bool     `json:"whole_file" yaml:"whole_file"`
+	MaxFileSize    int64    `json:"max_file_size" yaml:"max_file_size"`
 }
 
 // NewFileConfig creates a new FileConfig with default values.
@@ -88,6 +107,8 @@ func NewFileConfig() FileConfig {
 		MaxBuffer:      1000000,
 		Delim:          "",
 		DeleteOnFinish: false,
+		WholeFile:      false,
+		MaxFileSize:    0,
 	}
 }
 
@@ -124,6 +145,9 @@ type fileConsumer struct {
 	currentPath string
 
 	delete bool
+
+	wholeFile   bool
+	maxFileSize int64
 }
 
 func newFileConsumer(conf FileConfig, log log.Modular) (*fileConsumer, error) {
@@ -144,6 +168,8 @@ func newFileConsumer(conf FileConfig, log log.Modular) (*fileConsumer, error) {
 		scannerCtor: ctor,
 		paths:       expandedPaths,
 		delete:      conf.DeleteOnFinish,
+		wholeFile:   conf.WholeFile,
+		maxFileSize: conf.MaxFileSize,
 	}, nil
 }
 
@@ -161,6 +187,10 @@ func (f *fileConsumer) ConnectWithContext(ctx context.Context) error {
 		return types.ErrTypeClosed
 	}
 
+	if f.wholeFile {
+		return nil
+	}
+
 	nextPath := f.paths[0]
 
 	file, err := os.Open(nextPath)
@@ -190,6 +220,10 @@ func (f *fileConsumer) ReadWithContext(ctx context.Context) (types.Message, read
 	f.scannerMut.Lock()
 	defer f.scannerMut.Unlock()
 
+	if f.wholeFile {
+		return f.readWholeFile()
+	}
+
 	if f.scanner == nil {
 		return nil, nil, types.ErrNotConnected
 	}
@@ -227,6 +261,60 @@ func (f *fileConsumer) ReadWithContext(ctx context.Context) (types.Message, read
 	}, nil
 }
 
+func (f *fileConsumer) readWholeFile() (types.Message, reader.AsyncAckFn, error) {
+	if len(f.paths) == 0 {
+		return nil, nil, types.ErrNotConnected
+	}
+
+	nextPath := f.paths[0]
+	f.paths = f.paths[1:]
+
+	file, err := os.Open(nextPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.maxFileSize > 0 && info.Size() > f.maxFileSize {
+		return nil, nil, fmt.Errorf("file '%v' size of %v bytes exceeds max_file_size of %v bytes", nextPath, info.Size(), f.maxFileSize)
+	}
+
+	var rdr io.Reader = file
+	if f.maxFileSize > 0 {
+		// The file may have grown since it was inspected.
+		rdr = io.LimitReader(file, f.maxFileSize+1)
+	}
+	contents, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.maxFileSize > 0 && int64(len(contents)) > f.maxFileSize {
+		return nil, nil, fmt.Errorf("file '%v' exceeds max_file_size of %v bytes", nextPath, f.maxFileSize)
+	}
+
+	f.log.Infof("Consumed whole file '%v'\n", nextPath)
+
+	part := message.NewPart(contents)
+	meta := part.Metadata()
+	meta.Set("path", nextPath)
+	meta.Set("file_size", strconv.FormatInt(int64(len(contents)), 10))
+	meta.Set("mod_time_unix", strconv.FormatInt(info.ModTime().Unix(), 10))
+	meta.Set("mod_time", info.ModTime().Format(time.RFC3339))
+
+	msg := message.New(nil)
+	msg.Append(part)
+	return msg, func(rctx context.Context, res types.Response) error {
+		if res.Error() == nil && f.delete {
+			return os.Remove(nextPath)
+		}
+		return nil
+	}, nil
+}
+
 // CloseAsync begins cleaning up resources used by this reader asynchronously.
 func (f *fileConsumer) CloseAsync() {
 	go func() {
@@ -234,8 +322,8 @@ func (f *fileConsumer) CloseAsync() {
 		if f.scanner != nil {
 			f.scanner.Close(context.Background())
 			f.scanner = nil
-			f.paths = nil
 		}
+		f.paths = nil
 		f.scannerMut.Unlock()
 	}()
 }
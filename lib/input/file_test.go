@@ -3,6 +3,7 @@ package input
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -144,3 +145,65 @@ func TestFileMultiPartDeprecated(t *testing.T) {
 		t.Error("Timed out waiting for channel close")
 	}
 }
+
+func TestFileWholeFile(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.json": `{"id":"a"}`,
+		"b.json": "{\n  \"id\": \"b\"\n}\n",
+		"c.json": "",
+		"d.json": `{"id":"this file is too large"}`,
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	conf := NewConfig()
+	conf.File.Paths = []string{filepath.Join(dir, "*.json")}
+	conf.File.WholeFile = true
+	conf.File.MaxFileSize = 20
+	conf.File.DeleteOnFinish = true
+
+	f, err := NewFile(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	defer func() {
+		f.CloseAsync()
+		assert.NoError(t, f.WaitForClose(time.Second))
+	}()
+
+	for _, name := range []string{"a.json", "b.json", "c.json"} {
+		var ts types.Transaction
+		var open bool
+		select {
+		case ts, open = <-f.TransactionChan():
+			require.True(t, open)
+			require.Equal(t, 1, ts.Payload.Len())
+			part := ts.Payload.Get(0)
+			assert.Equal(t, files[name], string(part.Get()))
+			assert.Equal(t, filepath.Join(dir, name), part.Metadata().Get("path"))
+			assert.Equal(t, strconv.Itoa(len(files[name])), part.Metadata().Get("file_size"))
+			assert.NotEmpty(t, part.Metadata().Get("mod_time_unix"))
+			assert.NotEmpty(t, part.Metadata().Get("mod_time"))
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for message")
+		}
+		select {
+		case ts.ResponseChan <- response.NewAck():
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for response")
+		}
+	}
+
+	select {
+	case _, open := <-f.TransactionChan():
+		require.False(t, open)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for channel close")
+	}
+
+	remaining, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "d.json")}, remaining)
+}
@@ -30,7 +30,20 @@ This input adds the following metadata fields to each message:
 ` + "```" + `
 
 You can access these metadata fields using
-[function interpolation](/docs/configuration/interpolation#metadata).`,
+[function interpolation](/docs/configuration/interpolation#metadata).
+
+### Back Pressure
+
+The number of messages received ahead of the pipeline adapts to the rate at
+which they are consumed, up to a maximum of ` + "`max_outstanding_messages`" + `.
+While received messages are left waiting for the pipeline the number of
+outstanding messages permitted is reduced, and messages that have been received
+but not yet consumed are returned to the subscription so that other consumers
+of the subscription are able to process them instead. While the pipeline is
+waiting for messages the number permitted grows back towards the maximum.
+
+Messages that are in transit when the number permitted changes might only be
+redelivered once their ack deadline expires.`,
 		Categories: []Category{
 			CategoryServices,
 			CategoryGCP,
@@ -49,10 +49,16 @@ testing your pipeline configs.`,
 				"@every 1s", "0,30 */2 * * * *", "TZ=Europe/London 30 3-6,20-23 * * *",
 			),
 			docs.FieldCommon("count", "An optional number of messages to generate, if set above 0 the specified number of messages is generated and then the input will shut down."),
+			docs.FieldAdvanced("batch_size", "The number of messages to generate each interval, which are sent as a single batch. When a `count` is set the final batch may be smaller.").AtVersion("3.51.0"),
 		},
 		Categories: []Category{
 			CategoryUtility,
 		},
+		Description: `
+The mapping is executed once for each generated message and can use any
+[Bloblang function](/docs/guides/bloblang/functions), such as ` + "`uuid_v4`" + `
+and ` + "`timestamp_unix`" + ` for producing unique and timestamped data, or
+` + "`count`" + ` for numbering the generated messages.`,
 		Examples: []docs.AnnotatedExample{
 			{
 				Title:   "Cron Scheduled Processing",
@@ -90,6 +96,26 @@ input:
           "bar": "is gross"
         }
       }
+`,
+			},
+			{
+				Title:   "Load Testing",
+				Summary: "Batches of 100 synthetic events are generated each second, which is a convenient way to load test an output without an external source of data.",
+				Config: `
+input:
+  generate:
+    interval: 1s
+    batch_size: 100
+    mapping: |
+      root.id = uuid_v4()
+      root.seq = count("events")
+      root.created_at = timestamp_unix()
+      root.value = random_int() % 1000
+
+output:
+  http_client:
+    url: http://localhost:4195/post
+    verb: POST
 `,
 			},
 		},
@@ -126,6 +152,7 @@ This input has been ` + "[renamed to `generate`](/docs/components/inputs/generat
 				"@every 1s", "0,30 */2 * * * *", "30 3-6,20-23 * * *",
 			),
 			docs.FieldCommon("count", "An optional number of messages to generate, if set above 0 the specified number of messages is generated and then the input will shut down."),
+			docs.FieldAdvanced("batch_size", "The number of messages to generate each interval, which are sent as a single batch. When a `count` is set the final batch may be smaller.").AtVersion("3.51.0"),
 		},
 		Categories: []Category{
 			CategoryUtility,
@@ -139,16 +166,18 @@ This input has been ` + "[renamed to `generate`](/docs/components/inputs/generat
 type BloblangConfig struct {
 	Mapping string `json:"mapping" yaml:"mapping"`
 	// internal can be both duration string or cron expression
-	Interval string `json:"interval" yaml:"interval"`
-	Count    int    `json:"count" yaml:"count"`
+	Interval  string `json:"interval" yaml:"interval"`
+	Count     int    `json:"count" yaml:"count"`
+	BatchSize int    `json:"batch_size" yaml:"batch_size"`
 }
 
 // NewBloblangConfig creates a new BloblangConfig with default values.
 func NewBloblangConfig() BloblangConfig {
 	return BloblangConfig{
-		Mapping:  "",
-		Interval: "1s",
-		Count:    0,
+		Mapping:   "",
+		Interval:  "1s",
+		Count:     0,
+		BatchSize: 1,
 	}
 }
 
@@ -158,6 +187,7 @@ func NewBloblangConfig() BloblangConfig {
 type Bloblang struct {
 	remaining   int64
 	limited     bool
+	batchSize   int64
 	firstIsFree bool
 	exec        *mapping.Executor
 	timer       *time.Ticker
@@ -176,6 +206,9 @@ func newBloblang(conf BloblangConfig) (*Bloblang, error) {
 		firstIsFree = true
 	)
 
+	if conf.BatchSize < 1 {
+		return nil, fmt.Errorf("batch_size must be greater than zero, got %v", conf.BatchSize)
+	}
 	if len(conf.Interval) > 0 {
 		if duration, err = time.ParseDuration(conf.Interval); err != nil {
 			// interval is not a duration so try to parse as a cron expression
@@ -200,6 +233,7 @@ func newBloblang(conf BloblangConfig) (*Bloblang, error) {
 		exec:        exec,
 		remaining:   remaining,
 		limited:     remaining > 0,
+		batchSize:   int64(conf.BatchSize),
 		timer:       timer,
 		schedule:    schedule,
 		location:    location,
@@ -243,10 +277,15 @@ func (b *Bloblang) ConnectWithContext(ctx context.Context) error {
 
 // ReadWithContext a new bloblang generated message.
 func (b *Bloblang) ReadWithContext(ctx context.Context) (types.Message, reader.AsyncAckFn, error) {
+	batchSize := b.batchSize
 	if b.limited {
-		if remaining := atomic.AddInt64(&b.remaining, -1); remaining < 0 {
+		remaining := atomic.AddInt64(&b.remaining, -batchSize) + batchSize
+		if remaining <= 0 {
 			return nil, nil, types.ErrTypeClosed
 		}
+		if remaining < batchSize {
+			batchSize = remaining
+		}
 	}
 
 	if !b.firstIsFree && b.timer != nil {
@@ -264,17 +303,20 @@ func (b *Bloblang) ReadWithContext(ctx context.Context) (types.Message, reader.A
 	}
 
 	b.firstIsFree = false
-	p, err := b.exec.MapPart(0, message.New(nil))
-	if err != nil {
-		return nil, nil, err
+	msg := message.New(nil)
+	for i := int64(0); i < batchSize; i++ {
+		p, err := b.exec.MapPart(0, message.New(nil))
+		if err != nil {
+			return nil, nil, err
+		}
+		if p != nil {
+			msg.Append(p)
+		}
 	}
-	if p == nil {
+	if msg.Len() == 0 {
 		return nil, nil, types.ErrTimeout
 	}
 
-	msg := message.New(nil)
-	msg.Append(p)
-
 	return msg, func(context.Context, types.Response) error { return nil }, nil
 }
 
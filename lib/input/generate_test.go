@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -129,3 +130,37 @@ func TestBloblangRemaining(t *testing.T) {
 	_, _, err = b.ReadWithContext(ctx)
 	assert.EqualError(t, err, "type was closed")
 }
+
+func TestBloblangBatchSize(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer done()
+
+	conf := NewBloblangConfig()
+	conf.Mapping = `root = count("bloblang_batch_size_test")`
+	conf.Interval = "1ms"
+	conf.Count = 7
+	conf.BatchSize = 3
+
+	b, err := newBloblang(conf)
+	require.NoError(t, err)
+	require.NoError(t, b.ConnectWithContext(ctx))
+
+	var results []string
+	for _, expLen := range []int{3, 3, 1} {
+		m, _, err := b.ReadWithContext(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expLen, m.Len())
+		_ = m.Iter(func(i int, p types.Part) error {
+			results = append(results, string(p.Get()))
+			return nil
+		})
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7"}, results)
+
+	_, _, err = b.ReadWithContext(ctx)
+	assert.EqualError(t, err, "type was closed")
+
+	conf.BatchSize = 0
+	_, err = newBloblang(conf)
+	assert.EqualError(t, err, "batch_size must be greater than zero, got 0")
+}
@@ -0,0 +1,35 @@
+package input
+
+import (
+	"github.com/Jeffail/benthos/v3/lib/util/tls"
+)
+
+// GraphQLConfig contains configuration for the GraphQL input type.
+type GraphQLConfig struct {
+	URL                  string                 `json:"url" yaml:"url"`
+	Query                string                 `json:"query" yaml:"query"`
+	Variables            map[string]interface{} `json:"variables" yaml:"variables"`
+	CursorMapping        string                 `json:"cursor_mapping" yaml:"cursor_mapping"`
+	Interval             string                 `json:"interval" yaml:"interval"`
+	Subscription         bool                   `json:"subscription" yaml:"subscription"`
+	SubscriptionProtocol string                 `json:"subscription_protocol" yaml:"subscription_protocol"`
+	Headers              map[string]string      `json:"headers" yaml:"headers"`
+	Timeout              string                 `json:"timeout" yaml:"timeout"`
+	TLS                  tls.Config             `json:"tls" yaml:"tls"`
+}
+
+// NewGraphQLConfig creates a new GraphQLConfig with default values.
+func NewGraphQLConfig() GraphQLConfig {
+	return GraphQLConfig{
+		URL:                  "",
+		Query:                "",
+		Variables:            map[string]interface{}{},
+		CursorMapping:        "",
+		Interval:             "",
+		Subscription:         false,
+		SubscriptionProtocol: "graphql-transport-ws",
+		Headers:              map[string]string{},
+		Timeout:              "5s",
+		TLS:                  tls.NewConfig(),
+	}
+}
@@ -37,6 +37,9 @@ func httpClientSpecs() docs.FieldSpecs {
 	specs := append(client.FieldSpecs(),
 		docs.FieldCommon("payload", "An optional payload to deliver for each request."),
 		docs.FieldAdvanced("drop_empty_bodies", "Whether empty payloads received from the target server should be dropped."),
+	)
+	specs = append(specs, pollScheduleFieldSpecs("An optional minimum period between the start of each request. When empty requests are made as soon as the previous message has been read. This field has no effect in streaming mode.")...)
+	specs = append(specs,
 		docs.FieldCommon(
 			"stream", "Allows you to set streaming mode, where requests are kept open and messages are processed line-by-line.",
 		).WithChildren(streamSpecs...),
@@ -57,6 +60,22 @@ interpolations described [here](/docs/configuration/interpolation#bloblang-queri
 
 If you enable streaming then Benthos will consume the body of the response as a continuous stream of data, breaking messages out following a chosen codec. This allows you to consume APIs that provide long lived streamed data feeds (such as Twitter).
 
+### Polling
+
+By default a request is made as soon as the previous message has been read. A
+minimum period between requests can be set with ` + "`poll_interval`" + `, and
+` + "`poll_jitter`" + ` randomises each period by a percentage so that many instances
+polling the same server do not send their requests in lockstep:
+
+` + "```yaml" + `
+input:
+  http_client:
+    url: https://api.example.com/status
+    verb: GET
+    poll_interval: 60s
+    poll_jitter: 10
+` + "```" + `
+
 ### Pagination
 
 This input supports interpolation functions in the ` + "`url` and `headers`" + ` fields where data from the previous successfully consumed message (if there was one) can be referenced. This can be used in order to support basic levels of pagination. However, in cases where pagination depends on logic it is recommended that you use an ` + "[`http` processor](/docs/components/processors/http) instead, often combined with a [`generate` input](/docs/components/inputs/generate)" + ` in order to schedule the processor.`,
@@ -112,6 +131,8 @@ type HTTPClientConfig struct {
 	client.Config   `json:",inline" yaml:",inline"`
 	Payload         string       `json:"payload" yaml:"payload"`
 	DropEmptyBodies bool         `json:"drop_empty_bodies" yaml:"drop_empty_bodies"`
+	PollInterval    string       `json:"poll_interval" yaml:"poll_interval"`
+	PollJitter      float64      `json:"poll_jitter" yaml:"poll_jitter"`
 	Stream          StreamConfig `json:"stream" yaml:"stream"`
 }
 
@@ -124,6 +145,8 @@ func NewHTTPClientConfig() HTTPClientConfig {
 		Config:          cConf,
 		Payload:         "",
 		DropEmptyBodies: true,
+		PollInterval:    "",
+		PollJitter:      0,
 		Stream: StreamConfig{
 			Enabled:   false,
 			Reconnect: true,
@@ -146,6 +169,8 @@ type HTTPClient struct {
 	payload      types.Message
 	prevResponse types.Message
 
+	poll *pollSchedule
+
 	codecCtor codec.ReaderConstructor
 
 	codecMut sync.Mutex
@@ -182,6 +207,11 @@ func newHTTPClient(conf HTTPClientConfig, mgr types.Manager, log log.Modular, st
 		}
 	}
 
+	poll, err := newPollSchedule(conf.PollInterval, conf.PollJitter)
+	if err != nil {
+		return nil, err
+	}
+
 	var payload types.Message = message.New(nil)
 	if len(conf.Payload) > 0 {
 		payload = message.New([][]byte{[]byte(conf.Payload)})
@@ -203,6 +233,7 @@ func newHTTPClient(conf HTTPClientConfig, mgr types.Manager, log log.Modular, st
 		payload:      payload,
 		prevResponse: message.New(nil),
 		client:       client,
+		poll:         poll,
 
 		codecCtor: codecCtor,
 	}, nil
@@ -305,6 +336,12 @@ func (h *HTTPClient) readStreamed(ctx context.Context) (types.Message, reader.As
 }
 
 func (h *HTTPClient) readNotStreamed(ctx context.Context) (types.Message, reader.AsyncAckFn, error) {
+	if h.poll != nil {
+		if err := h.poll.wait(ctx); err != nil {
+			return nil, nil, types.ErrTimeout
+		}
+	}
+
 	msg, err := h.client.Send(ctx, h.payload, h.prevResponse)
 	if err != nil {
 		if strings.Contains(err.Error(), "(Client.Timeout exceeded while awaiting headers)") {
@@ -142,6 +142,70 @@ func TestHTTPClientPagination(t *testing.T) {
 	}
 }
 
+func TestHTTPClientPollInterval(t *testing.T) {
+	var reqTimes []time.Time
+	var reqTimesLock sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqTimesLock.Lock()
+		reqTimes = append(reqTimes, time.Now())
+		reqTimesLock.Unlock()
+		w.Write([]byte("foo"))
+	}))
+	defer ts.Close()
+
+	conf := NewConfig()
+	conf.HTTPClient.URL = ts.URL + "/testget"
+	conf.HTTPClient.PollInterval = "50ms"
+	conf.HTTPClient.PollJitter = 20
+
+	h, err := NewHTTPClient(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		var tr types.Transaction
+		select {
+		case tr = <-h.TransactionChan():
+			assert.Equal(t, "foo", string(tr.Payload.Get(0).Get()))
+		case <-time.After(time.Second):
+			t.Fatal("Action timed out")
+		}
+		select {
+		case tr.ResponseChan <- response.NewAck():
+		case <-time.After(time.Second):
+			t.Fatal("Action timed out")
+		}
+	}
+
+	h.CloseAsync()
+	assert.NoError(t, h.WaitForClose(time.Second))
+
+	reqTimesLock.Lock()
+	defer reqTimesLock.Unlock()
+	require.GreaterOrEqual(t, len(reqTimes), 4)
+	for i := 1; i < 4; i++ {
+		assert.GreaterOrEqual(t, int64(reqTimes[i].Sub(reqTimes[i-1])), int64(40*time.Millisecond))
+	}
+}
+
+func TestHTTPClientPollJitter(t *testing.T) {
+	conf := NewHTTPClientConfig()
+	conf.PollInterval = "1s"
+	conf.PollJitter = 10
+
+	h, err := newHTTPClient(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		period := h.poll.period()
+		assert.GreaterOrEqual(t, int64(period), int64(900*time.Millisecond))
+		assert.LessOrEqual(t, int64(period), int64(1100*time.Millisecond))
+	}
+
+	conf.PollJitter = 150
+	_, err = newHTTPClient(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "poll jitter must be between 0 and 100, received 150")
+}
+
 func TestHTTPClientGETError(t *testing.T) {
 	t.Parallel()
 
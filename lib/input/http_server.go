@@ -82,6 +82,18 @@ message of the batch.
 Creates a websocket connection, where payloads received on the socket are passed
 through the pipeline as a batch of one message.
 
+Each payload is fully processed before the next is read from the socket, and
+therefore a busy pipeline applies backpressure to the client rather than
+buffering payloads in memory. Payloads that are rejected by the pipeline are
+retried until they succeed or the input is closed, and no further payloads are
+read from the socket in the meantime. A client closing the connection does not
+stop these retries, as the closure is only detected once the next payload is
+read.
+
+[Synchronous responses](/docs/guides/sync_responses) are written back over the
+same socket as text messages before the next payload is read, and are therefore
+always delivered in the order that their payloads were received.
+
 You may specify an optional ` + "`ws_welcome_message`" + `, which is a static
 payload to be sent to all clients once a websocket connection is first
 established.
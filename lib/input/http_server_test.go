@@ -577,6 +577,61 @@ func TestHTTPServerWebsockets(t *testing.T) {
 	}
 }
 
+func TestHTTPServerWebsocketsSyncResponse(t *testing.T) {
+	t.Parallel()
+
+	reg := apiRegMutWrapper{mut: &http.ServeMux{}}
+
+	mgr, err := manager.New(manager.NewConfig(), reg, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := input.NewConfig()
+	conf.HTTPServer.WSPath = "/testws"
+
+	h, err := input.NewHTTPServer(conf, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	server := httptest.NewServer(reg.mut)
+	defer server.Close()
+
+	purl, err := url.Parse(server.URL + "/testws")
+	require.NoError(t, err)
+	purl.Scheme = "ws"
+
+	client, _, err := websocket.DefaultDialer.Dial(purl.String(), http.Header{})
+	require.NoError(t, err)
+
+	for _, payload := range []string{"hello world 1", "hello world 2"} {
+		require.NoError(t, client.WriteMessage(websocket.BinaryMessage, []byte(payload)))
+
+		var ts types.Transaction
+		select {
+		case ts = <-h.TransactionChan():
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for message")
+		}
+		assert.Equal(t, payload, string(ts.Payload.Get(0).Get()))
+
+		ts.Payload.Get(0).Set([]byte("echo: " + payload))
+		roundtrip.SetAsResponse(ts.Payload)
+
+		select {
+		case ts.ResponseChan <- response.NewAck():
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for response")
+		}
+
+		msgType, resBytes, err := client.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.TextMessage, msgType)
+		assert.Equal(t, "echo: "+payload, string(resBytes))
+	}
+	require.NoError(t, client.Close())
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second*5))
+}
+
 func TestHTTPServerWSRateLimit(t *testing.T) {
 	t.Parallel()
 
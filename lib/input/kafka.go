@@ -39,6 +39,26 @@ The Kafka input allows parallel processing of messages from different topic part
 
 Alternatively, if you perform batching at the input level using the ` + "[`batching`](#batching)" + ` field it is done per-partition and therefore avoids stalling.
 
+### Static Partitioning
+
+Deployments that consist of a fixed set of Benthos instances can divide the
+partitions of topics between them without a consumer group by setting
+` + "`shard_count`" + ` to the number of instances and giving each instance a
+unique ` + "`shard_index`" + ` from zero. Each instance consumes the partitions
+whose number modulo the shard count matches its index, and therefore every
+partition is consumed by exactly one instance. When connecting the shard count
+is validated against the number of partitions of each topic, and an error is
+reported if any shard would be left without partitions.
+
+Partitions can also be assigned explicitly with the ` + "`topics`" + ` syntax
+` + "`foo:0-3`" + `, in which case each partition listed must exist and must not
+be listed more than once, but it is not possible to detect partitions that are
+missing from the lists of all instances.
+
+Offsets of statically assigned partitions are committed under the
+` + "`consumer_group`" + `, or stored within an ` + "`offsets_file`" + `, without
+joining the group.
+
 ### Metadata
 
 This input adds the following metadata fields to each message:
@@ -73,12 +93,23 @@ You can access these metadata fields using [function interpolation](/docs/config
 			btls.FieldSpec(),
 			sasl.FieldSpec(),
 			docs.FieldCommon("consumer_group", "An identifier for the consumer group of the connection. This field can be explicitly made empty in order to disable stored offsets for the consumed topic partitions."),
+			docs.FieldAdvanced("group_instance_id", "An optional static identifier for this consumer within the consumer group. When set the consumer becomes a static member of the group, and restarting it within the `group.session_timeout` does not trigger a rebalance. Each consumer of the group must use a unique identifier. Requires a `target_version` of at least `2.3.0`.").AtVersion("3.51.0"),
 			docs.FieldCommon("client_id", "An identifier for the client connection."),
 			docs.FieldAdvanced("start_from_oldest", "If an offset is not found for a topic partition, determines whether to consume from the oldest available offset, otherwise messages are consumed from the latest offset."),
+			docs.FieldAdvanced(
+				"start_from_timestamp", "An optional timestamp, either in RFC3339 format or as a unix timestamp in milliseconds, to begin consuming from when an offset is not found for a topic partition. This takes precedence over `start_from_oldest`, and each partition without a stored offset begins consuming from the first message at or after the timestamp. If a partition has no messages at or after the timestamp then it begins at the latest offset and a warning is logged. Requires a `target_version` of at least `0.10.1`.",
+				"2021-09-29T14:00:00Z", "1632924000000",
+			).AtVersion("3.51.0"),
 			docs.FieldCommon(
 				"checkpoint_limit", "EXPERIMENTAL: The maximum number of messages of the same topic and partition that can be processed at a given time. Increasing this limit enables parallel processing and batching at the output level to work on individual partitions. Any given offset will not be committed unless all messages under that offset are delivered in order to preserve at least once delivery guarantees.",
 			).AtVersion("3.33.0"),
 			docs.FieldAdvanced("commit_period", "The period of time between each commit of the current partition offsets. Offsets are always committed during shutdown."),
+			docs.FieldAdvanced(
+				"offsets_file", "EXPERIMENTAL: A path to a file used for storing the offsets of explicit topic partitions instead of a consumer group. Offsets are read from this file when connecting and are written to it each `commit_period`, which allows you to deterministically replay partitions without a consumer group. The file is a JSON object of topics to objects of partitions and the next offset to consume, e.g. `{\"foo\":{\"0\":1205}}`, and can be edited by hand in order to seek to a specific offset.",
+				"./offsets.json",
+			).AtVersion("3.51.0"),
+			docs.FieldAdvanced("shard_index", "When `shard_count` is set, the index of this input amongst the shards from zero. Each topic partition is consumed by the shard that matches its number modulo the `shard_count`.").AtVersion("3.51.0"),
+			docs.FieldAdvanced("shard_count", "An optional number of shards to [statically divide](#static-partitioning) the partitions of each topic across. When set to zero topics are balanced across the consumer group instead.").AtVersion("3.51.0"),
 			docs.FieldAdvanced("max_processing_period", "A maximum estimate for the time taken to process a message, this is used for tuning consumer group synchronization."),
 			input.ExtractTracingSpanMappingDocs,
 			docs.FieldAdvanced("group", "Tuning parameters for consumer group synchronization.").WithChildren(
@@ -166,12 +197,14 @@ type kafkaReader struct {
 
 	topicPartitions map[string][]int32
 	balancedTopics  []string
+	shardedTopics   []string
 
 	commitPeriod      time.Duration
 	sessionTimeout    time.Duration
 	heartbeatInterval time.Duration
 	rebalanceTimeout  time.Duration
 	maxProcPeriod     time.Duration
+	startFromTime     time.Time
 
 	// Connection resources
 	cMut            sync.Mutex
@@ -179,6 +212,7 @@ type kafkaReader struct {
 	consumerDoneCtx context.Context
 	msgChan         chan asyncMessage
 	session         offsetMarker
+	groupClient     sarama.Client
 
 	mRebalanced metrics.StatCounter
 
@@ -193,6 +227,15 @@ type kafkaReader struct {
 
 var errCannotMixBalanced = errors.New("it is not currently possible to include balanced and explicit partition topics in the same kafka input")
 
+// parseKafkaTimestamp parses a timestamp either in RFC3339 format or as a unix
+// timestamp in milliseconds.
+func parseKafkaTimestamp(ts string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(ts, 10, 64); err == nil {
+		return time.Unix(0, millis*int64(time.Millisecond)), nil
+	}
+	return time.Parse(time.RFC3339, ts)
+}
+
 func parsePartitions(expr string) ([]int32, error) {
 	rangeExpr := strings.Split(expr, "-")
 	if len(rangeExpr) > 2 {
@@ -271,6 +314,13 @@ func newKafkaReader(
 					if err != nil {
 						return nil, err
 					}
+					for _, p := range parts {
+						for _, existing := range k.topicPartitions[topic] {
+							if p == existing {
+								return nil, fmt.Errorf("partition %v of topic '%v' is listed more than once", p, topic)
+							}
+						}
+					}
 					k.topicPartitions[topic] = append(k.topicPartitions[topic], parts...)
 				} else {
 					if len(k.topicPartitions) > 0 {
@@ -281,6 +331,17 @@ func newKafkaReader(
 			}
 		}
 	}
+	if conf.ShardCount > 0 {
+		if len(k.topicPartitions) > 0 {
+			return nil, errors.New("explicit topic partitions cannot be combined with a shard_count")
+		}
+		if conf.ShardIndex < 0 || conf.ShardIndex >= conf.ShardCount {
+			return nil, fmt.Errorf("shard_index must be at least 0 and less than the shard_count of %v, got %v", conf.ShardCount, conf.ShardIndex)
+		}
+		k.shardedTopics, k.balancedTopics = k.balancedTopics, nil
+	} else if conf.ShardIndex != 0 {
+		return nil, errors.New("a shard_index can only be used in combination with a shard_count")
+	}
 	if tout := conf.CommitPeriod; len(tout) > 0 {
 		var err error
 		if k.commitPeriod, err = time.ParseDuration(tout); err != nil {
@@ -314,11 +375,25 @@ func newKafkaReader(
 	if conf.ConsumerGroup == "" && len(k.balancedTopics) > 0 {
 		return nil, errors.New("a consumer group must be specified when consuming balanced topics")
 	}
+	if conf.OffsetsFile != "" && len(k.balancedTopics) > 0 {
+		return nil, errors.New("an offsets file can only be used when consuming explicit topic partitions")
+	}
 
 	var err error
 	if k.version, err = sarama.ParseKafkaVersion(conf.TargetVersion); err != nil {
 		return nil, err
 	}
+	if conf.GroupInstanceID != "" && !k.version.IsAtLeast(sarama.V2_3_0_0) {
+		return nil, fmt.Errorf("a group_instance_id requires a target_version of at least %v, got %v", sarama.V2_3_0_0, k.version)
+	}
+	if conf.StartFromTimestamp != "" {
+		if k.startFromTime, err = parseKafkaTimestamp(conf.StartFromTimestamp); err != nil {
+			return nil, fmt.Errorf("failed to parse start_from_timestamp: %w", err)
+		}
+		if !k.version.IsAtLeast(sarama.V0_10_1_0) {
+			return nil, fmt.Errorf("a start_from_timestamp requires a target_version of at least %v, got %v", sarama.V0_10_1_0, k.version)
+		}
+	}
 	return &k, nil
 }
 
@@ -430,6 +505,25 @@ func dataToPart(highestOffset int64, data *sarama.ConsumerMessage) types.Part {
 	return part
 }
 
+// timestampOffset returns the offset of the first message of a topic partition
+// at or after the configured start_from_timestamp. If no such message exists
+// the latest offset of the partition is returned instead.
+func (k *kafkaReader) timestampOffset(client sarama.Client, topic string, partition int32) (int64, error) {
+	millis := k.startFromTime.UnixNano() / int64(time.Millisecond)
+	offset, err := client.GetOffset(topic, partition, millis)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire offset for timestamp of topic %v partition %v: %w", topic, partition, err)
+	}
+	if offset >= 0 {
+		return offset, nil
+	}
+	k.log.Warnf("No messages found at or after start_from_timestamp for topic %v partition %v, consuming from the newest offset\n", topic, partition)
+	if offset, err = client.GetOffset(topic, partition, sarama.OffsetNewest); err != nil {
+		return 0, fmt.Errorf("failed to acquire newest offset of topic %v partition %v: %w", topic, partition, err)
+	}
+	return offset, nil
+}
+
 //------------------------------------------------------------------------------
 
 func (k *kafkaReader) closeGroupAndConsumers() {
@@ -471,6 +565,7 @@ func (k *kafkaReader) ConnectWithContext(ctx context.Context) error {
 	config.Consumer.Group.Session.Timeout = k.sessionTimeout
 	config.Consumer.Group.Heartbeat.Interval = k.heartbeatInterval
 	config.Consumer.Group.Rebalance.Timeout = k.rebalanceTimeout
+	config.Consumer.Group.InstanceId = k.conf.GroupInstanceID
 	config.ChannelBufferSize = k.conf.FetchBufferCap
 
 	if config.Net.ReadTimeout <= k.sessionTimeout {
@@ -492,7 +587,7 @@ func (k *kafkaReader) ConnectWithContext(ctx context.Context) error {
 		return err
 	}
 
-	if len(k.topicPartitions) > 0 {
+	if len(k.topicPartitions) > 0 || len(k.shardedTopics) > 0 {
 		return k.connectExplicitTopics(ctx, config)
 	}
 	return k.connectBalancedTopics(ctx, config)
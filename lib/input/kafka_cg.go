@@ -2,6 +2,7 @@ package input
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
@@ -14,8 +15,52 @@ import (
 func (k *kafkaReader) Setup(sesh sarama.ConsumerGroupSession) error {
 	k.cMut.Lock()
 	k.session = sesh
+	client := k.groupClient
 	k.cMut.Unlock()
 	k.mRebalanced.Incr(1)
+	if !k.startFromTime.IsZero() && client != nil {
+		return k.seekClaimsToTimestamp(client, sesh)
+	}
+	return nil
+}
+
+// seekClaimsToTimestamp marks the offset of each claimed partition that has no
+// committed offset for the group to the first message at or after the
+// configured start_from_timestamp.
+func (k *kafkaReader) seekClaimsToTimestamp(client sarama.Client, sesh sarama.ConsumerGroupSession) error {
+	coordinator, err := client.Coordinator(k.conf.ConsumerGroup)
+	if err != nil {
+		return fmt.Errorf("failed to acquire group coordinator: %w", err)
+	}
+
+	offsetGetReq := sarama.OffsetFetchRequest{
+		Version:       k.offsetVersion(),
+		ConsumerGroup: k.conf.ConsumerGroup,
+	}
+	for topic, partitions := range sesh.Claims() {
+		for _, partition := range partitions {
+			offsetGetReq.AddPartition(topic, partition)
+		}
+	}
+
+	offsetRes, err := coordinator.FetchOffset(&offsetGetReq)
+	if err != nil {
+		return fmt.Errorf("failed to acquire offsets from broker: %w", err)
+	}
+
+	for topic, partitions := range sesh.Claims() {
+		for _, partition := range partitions {
+			if block := offsetRes.GetBlock(topic, partition); block != nil && block.Err == sarama.ErrNoError && block.Offset >= 0 {
+				continue
+			}
+			offset, err := k.timestampOffset(client, topic, partition)
+			if err != nil {
+				return err
+			}
+			k.log.Debugf("Consuming topic %v partition %v from offset %v of start_from_timestamp\n", topic, partition, offset)
+			sesh.MarkOffset(topic, partition, offset, "")
+		}
+	}
 	return nil
 }
 
@@ -90,9 +135,15 @@ func (k *kafkaReader) ConsumeClaim(sess sarama.ConsumerGroupSession, claim saram
 //------------------------------------------------------------------------------
 
 func (k *kafkaReader) connectBalancedTopics(ctx context.Context, config *sarama.Config) error {
+	client, err := sarama.NewClient(k.addresses, config)
+	if err != nil {
+		return err
+	}
+
 	// Start a new consumer group
-	group, err := sarama.NewConsumerGroup(k.addresses, k.conf.ConsumerGroup, config)
+	group, err := sarama.NewConsumerGroupFromClient(k.conf.ConsumerGroup, client)
 	if err != nil {
+		client.Close()
 		return err
 	}
 
@@ -144,8 +195,10 @@ func (k *kafkaReader) connectBalancedTopics(ctx context.Context, config *sarama.
 		k.log.Debugln("Closing consumer group")
 
 		group.Close()
+		client.Close()
 
 		k.cMut.Lock()
+		k.groupClient = nil
 		if k.msgChan != nil {
 			close(k.msgChan)
 			k.msgChan = nil
@@ -154,6 +207,7 @@ func (k *kafkaReader) connectBalancedTopics(ctx context.Context, config *sarama.
 	}()
 
 	k.msgChan = make(chan asyncMessage)
+	k.groupClient = client
 	k.consumerDoneCtx = consumerDoneCtx
 	k.log.Infof("Consuming kafka topics %v from brokers %s as group '%v'\n", k.balancedTopics, k.addresses, k.conf.ConsumerGroup)
 	return nil
@@ -2,9 +2,12 @@ package input
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +25,33 @@ func (c *closureOffsetTracker) MarkOffset(topic string, partition int32, offset
 	c.fn(topic, partition, offset, metadata)
 }
 
+// readKafkaOffsetsFile parses a JSON file of topics to partition offsets, a
+// file that does not yet exist results in an empty set of offsets.
+func readKafkaOffsetsFile(path string) (map[string]map[int32]int64, error) {
+	offsets := map[string]map[int32]int64{}
+	offsetsJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return offsets, nil
+		}
+		return nil, fmt.Errorf("failed to read offsets file: %w", err)
+	}
+	if err = json.Unmarshal(offsetsJSON, &offsets); err != nil {
+		return nil, fmt.Errorf("failed to parse offsets file: %w", err)
+	}
+	return offsets, nil
+}
+
+// writeKafkaOffsetsFile writes offsets to a temporary file before moving it
+// over the target path, so that a crash mid-write never corrupts the offsets.
+func writeKafkaOffsetsFile(path string, offsetsJSON []byte) error {
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, offsetsJSON, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
 func (k *kafkaReader) runPartitionConsumer(
 	ctx context.Context,
 	wg *sync.WaitGroup,
@@ -123,6 +153,47 @@ func (k *kafkaReader) offsetPartitionPutRequest(consumerGroup string) *sarama.Of
 	return req
 }
 
+// assignedPartitions returns the partitions of each topic to consume, which are
+// either listed explicitly or statically divided across shards, and verifies
+// them against the partitions that exist within the cluster.
+func (k *kafkaReader) assignedPartitions(client sarama.Client) (map[string][]int32, error) {
+	assigned := map[string][]int32{}
+	for topic, parts := range k.topicPartitions {
+		available, err := client.Partitions(topic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain partitions of topic %v: %w", topic, err)
+		}
+		for _, p := range parts {
+			if p < 0 || int(p) >= len(available) {
+				return nil, fmt.Errorf("partition %v of topic %v does not exist, the topic has %v partitions", p, topic, len(available))
+			}
+		}
+		assigned[topic] = parts
+	}
+	for _, topic := range k.shardedTopics {
+		available, err := client.Partitions(topic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain partitions of topic %v: %w", topic, err)
+		}
+		if len(available) < k.conf.ShardCount {
+			return nil, fmt.Errorf("shard_count of %v exceeds the %v partitions of topic %v, which would leave shards without partitions", k.conf.ShardCount, len(available), topic)
+		}
+		assigned[topic] = kafkaShardPartitions(int32(len(available)), k.conf.ShardIndex, k.conf.ShardCount)
+	}
+	return assigned, nil
+}
+
+// kafkaShardPartitions returns the partitions owned by a shard, where each
+// partition is assigned to the shard that matches its number modulo the
+// count of shards, ensuring that shards never overlap and leave no gaps.
+func kafkaShardPartitions(numPartitions int32, index, count int) []int32 {
+	var parts []int32
+	for p := int32(index); p < numPartitions; p += int32(count) {
+		parts = append(parts, p)
+	}
+	return parts
+}
+
 func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.Config) error {
 	var coordinator *sarama.Broker
 	var consumer sarama.Consumer
@@ -143,10 +214,22 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 		}
 	}()
 
+	var fileOffsets map[string]map[int32]int64
+	if k.conf.OffsetsFile != "" {
+		if fileOffsets, err = readKafkaOffsetsFile(k.conf.OffsetsFile); err != nil {
+			return err
+		}
+	}
+
 	if client, err = sarama.NewClient(k.addresses, config); err != nil {
 		return err
 	}
-	if len(k.conf.ConsumerGroup) > 0 {
+
+	var topicPartitions map[string][]int32
+	if topicPartitions, err = k.assignedPartitions(client); err != nil {
+		return err
+	}
+	if len(k.conf.ConsumerGroup) > 0 && fileOffsets == nil {
 		if coordinator, err = client.Coordinator(k.conf.ConsumerGroup); err != nil {
 			return err
 		}
@@ -159,7 +242,7 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 		Version:       k.offsetVersion(),
 		ConsumerGroup: k.conf.ConsumerGroup,
 	}
-	for topic, parts := range k.topicPartitions {
+	for topic, parts := range topicPartitions {
 		for _, part := range parts {
 			offsetGetReq.AddPartition(topic, part)
 		}
@@ -184,7 +267,13 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 		// checkpointer that uses it already does this, but it's not
 		// particularly clear, hence this comment.
 		fn: func(topic string, partition int32, offset int64, metadata string) {
-			offsetPutReq.AddBlock(topic, partition, offset, time.Now().Unix(), metadata)
+			offsetPutReq.AddBlock(topic, partition, offset, 0, time.Now().Unix(), metadata)
+			if fileOffsets != nil {
+				if _, exists := fileOffsets[topic]; !exists {
+					fileOffsets[topic] = map[int32]int64{}
+				}
+				fileOffsets[topic][partition] = offset
+			}
 		},
 	}
 
@@ -193,16 +282,19 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 	msgChan := make(chan asyncMessage)
 	ctx, doneFn := context.WithCancel(context.Background())
 
-	for topic, partitions := range k.topicPartitions {
+	for topic, partitions := range topicPartitions {
 		for _, partition := range partitions {
 			offset := sarama.OffsetNewest
 			if k.conf.StartFromOldest {
 				offset = sarama.OffsetOldest
 			}
-			if block := offsetRes.GetBlock(topic, partition); block != nil {
+			stored := false
+			if fileOffset, exists := fileOffsets[topic][partition]; exists {
+				offset, stored = fileOffset, true
+			} else if block := offsetRes.GetBlock(topic, partition); block != nil {
 				if block.Err == sarama.ErrNoError {
 					if block.Offset > 0 {
-						offset = block.Offset
+						offset, stored = block.Offset, true
 					}
 				} else {
 					k.log.Debugf("Failed to acquire offset for topic %v partition %v: %v\n", topic, partition, block.Err)
@@ -210,6 +302,12 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 			} else {
 				k.log.Debugf("Failed to acquire offset for topic %v partition %v\n", topic, partition)
 			}
+			if !stored && !k.startFromTime.IsZero() {
+				if offset, err = k.timestampOffset(client, topic, partition); err != nil {
+					doneFn()
+					return err
+				}
+			}
 
 			var partConsumer sarama.PartitionConsumer
 			if partConsumer, err = consumer.ConsumePartition(topic, partition, offset); err != nil {
@@ -254,6 +352,17 @@ func (k *kafkaReader) connectExplicitTopics(ctx context.Context, config *sarama.
 					k.log.Errorf("Failed to commit offsets: %v\n", err)
 				}
 			}
+			if fileOffsets != nil {
+				k.cMut.Lock()
+				offsetsJSON, err := json.Marshal(fileOffsets)
+				k.cMut.Unlock()
+				if err == nil {
+					err = writeKafkaOffsetsFile(k.conf.OffsetsFile, offsetsJSON)
+				}
+				if err != nil {
+					k.log.Errorf("Failed to write offsets file: %v\n", err)
+				}
+			}
 		}
 		for _, consumer := range partConsumers {
 			consumer.AsyncClose()
@@ -1,18 +1,29 @@
 package input
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKafkaBadParams(t *testing.T) {
 	testCases := []struct {
-		name   string
-		topics []string
-		errStr string
+		name        string
+		topics      []string
+		shardIndex  int
+		shardCount  int
+		offsetsFile string
+		instanceID  string
+		startFromTS string
+		version     string
+		errStr      string
 	}{
 		{
 			name:   "mixing consumer types",
@@ -29,6 +40,55 @@ func TestKafkaBadParams(t *testing.T) {
 			topics: []string{"foo:1-2-3"},
 			errStr: "failed to create input 'kafka': partition '1-2-3' is invalid, only one range can be specified",
 		},
+		{
+			name:   "duplicate partitions",
+			topics: []string{"foo:0-2", "bar:0", "foo:2"},
+			errStr: "failed to create input 'kafka': partition 2 of topic 'foo' is listed more than once",
+		},
+		{
+			name:       "shards with explicit partitions",
+			topics:     []string{"foo:0"},
+			shardCount: 2,
+			errStr:     "failed to create input 'kafka': explicit topic partitions cannot be combined with a shard_count",
+		},
+		{
+			name:       "shard index out of range",
+			topics:     []string{"foo"},
+			shardIndex: 2,
+			shardCount: 2,
+			errStr:     "failed to create input 'kafka': shard_index must be at least 0 and less than the shard_count of 2, got 2",
+		},
+		{
+			name:       "shard index without count",
+			topics:     []string{"foo"},
+			shardIndex: 1,
+			errStr:     "failed to create input 'kafka': a shard_index can only be used in combination with a shard_count",
+		},
+		{
+			name:        "offsets file with balanced topics",
+			topics:      []string{"foo"},
+			offsetsFile: "./offsets.json",
+			errStr:      "failed to create input 'kafka': an offsets file can only be used when consuming explicit topic partitions",
+		},
+		{
+			name:       "instance id with old target version",
+			topics:     []string{"foo"},
+			instanceID: "foo-1",
+			errStr:     "failed to create input 'kafka': a group_instance_id requires a target_version of at least 2.3.0, got 1.0.0",
+		},
+		{
+			name:        "bad start timestamp",
+			topics:      []string{"foo"},
+			startFromTS: "yesterday",
+			errStr:      "failed to create input 'kafka': failed to parse start_from_timestamp: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\"",
+		},
+		{
+			name:        "start timestamp with old target version",
+			topics:      []string{"foo"},
+			startFromTS: "1632924000000",
+			version:     "0.10.0.0",
+			errStr:      "failed to create input 'kafka': a start_from_timestamp requires a target_version of at least 0.10.1.0, got 0.10.0.0",
+		},
 	}
 
 	for _, test := range testCases {
@@ -38,9 +98,94 @@ func TestKafkaBadParams(t *testing.T) {
 			conf.Type = TypeKafka
 			conf.Kafka.Addresses = []string{"example.com:1234"}
 			conf.Kafka.Topics = test.topics
+			conf.Kafka.OffsetsFile = test.offsetsFile
+			conf.Kafka.ShardIndex = test.shardIndex
+			conf.Kafka.ShardCount = test.shardCount
+			conf.Kafka.GroupInstanceID = test.instanceID
+			conf.Kafka.StartFromTimestamp = test.startFromTS
+			if test.version != "" {
+				conf.Kafka.TargetVersion = test.version
+			}
 
 			_, err := New(conf, nil, log.Noop(), metrics.Noop())
 			assert.EqualError(t, err, test.errStr)
 		})
 	}
 }
+
+func TestKafkaShardPartitions(t *testing.T) {
+	assert.Equal(t, []int32{0, 3, 6, 9}, kafkaShardPartitions(10, 0, 3))
+	assert.Equal(t, []int32{2, 5, 8}, kafkaShardPartitions(10, 2, 3))
+	assert.Equal(t, []int32{1}, kafkaShardPartitions(2, 1, 2))
+
+	for _, count := range []int{1, 2, 3, 7} {
+		seen := map[int32]int{}
+		for index := 0; index < count; index++ {
+			for _, p := range kafkaShardPartitions(20, index, count) {
+				seen[p]++
+			}
+		}
+		require.Len(t, seen, 20, "count: %v", count)
+		for p, n := range seen {
+			assert.Equal(t, 1, n, "count: %v, partition: %v", count, p)
+		}
+	}
+}
+
+func TestKafkaShardedTopicsNoGroup(t *testing.T) {
+	conf := NewConfig()
+	conf.Kafka.Topics = []string{"foo,bar"}
+	conf.Kafka.ConsumerGroup = ""
+	conf.Kafka.ShardIndex = 1
+	conf.Kafka.ShardCount = 3
+
+	k, err := newKafkaReader(conf.Kafka, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, k.shardedTopics)
+	assert.Empty(t, k.balancedTopics)
+}
+
+func TestParseKafkaTimestamp(t *testing.T) {
+	exp := time.Date(2021, 9, 29, 14, 0, 0, 0, time.UTC)
+
+	ts, err := parseKafkaTimestamp("2021-09-29T14:00:00Z")
+	require.NoError(t, err)
+	assert.True(t, exp.Equal(ts), ts.String())
+
+	ts, err = parseKafkaTimestamp("1632924000000")
+	require.NoError(t, err)
+	assert.True(t, exp.Equal(ts), ts.String())
+
+	_, err = parseKafkaTimestamp("nope")
+	require.Error(t, err)
+}
+
+func TestKafkaOffsetsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offsets.json")
+
+	offsets, err := readKafkaOffsetsFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, offsets)
+
+	offsetsJSON, err := json.Marshal(map[string]map[int32]int64{
+		"foo": {0: 10, 3: 25},
+		"bar": {1: 5},
+	})
+	require.NoError(t, err)
+	require.NoError(t, writeKafkaOffsetsFile(path, offsetsJSON))
+
+	fileBytes, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bar":{"1":5},"foo":{"0":10,"3":25}}`, string(fileBytes))
+
+	offsets, err = readKafkaOffsetsFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[int32]int64{
+		"foo": {0: 10, 3: 25},
+		"bar": {1: 5},
+	}, offsets)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`not json`), 0644))
+	_, err = readKafkaOffsetsFile(path)
+	require.Error(t, err)
+}
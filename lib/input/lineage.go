@@ -0,0 +1,113 @@
+package input
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/pipeline"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// sourceLocatorFormats lists the metadata keys that inputs use to describe the
+// location of a message within its source, along with a format for each. The
+// first format where all keys are present on a message is used as its locator.
+var sourceLocatorFormats = []struct {
+	keys   []string
+	format string
+}{
+	{[]string{"kafka_topic", "kafka_partition", "kafka_offset"}, "kafka://%v/%v/%v"},
+	{[]string{"s3_bucket", "s3_key"}, "s3://%v/%v"},
+	{[]string{"blob_storage_container", "blob_storage_key"}, "azblob://%v/%v"},
+	{[]string{"kinesis_shard", "kinesis_sequence_number"}, "kinesis://%v/%v"},
+	{[]string{"nats_stream_subject", "nats_stream_sequence"}, "nats_stream://%v/%v"},
+	{[]string{"sqs_message_id"}, "sqs://%v"},
+	{[]string{"redis_stream"}, "redis_stream://%v"},
+	{[]string{"sftp_path"}, "sftp://%v"},
+	{[]string{"path"}, "file://%v"},
+}
+
+// sourceLocator returns a string that describes the location of a message
+// within its source, or an empty string if the location is not known.
+func sourceLocator(p types.Part) string {
+	meta := p.Metadata()
+	for _, f := range sourceLocatorFormats {
+		values := make([]interface{}, 0, len(f.keys))
+		for _, k := range f.keys {
+			v := meta.Get(k)
+			if v == "" {
+				break
+			}
+			values = append(values, v)
+		}
+		if len(values) == len(f.keys) {
+			return fmt.Sprintf(f.format, values...)
+		}
+	}
+	return ""
+}
+
+//------------------------------------------------------------------------------
+
+// appendLineageFromConfig takes a variant arg of pipeline constructor functions
+// and, when the provided input configuration enables lineage metadata, returns
+// a new slice where a pipeline adding lineage metadata to messages precedes all
+// others.
+func appendLineageFromConfig(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	pipelines ...types.PipelineConstructorFunc,
+) []types.PipelineConstructorFunc {
+	if !conf.Lineage {
+		return pipelines
+	}
+	label := conf.Label
+	if label == "" {
+		label = conf.Type
+	}
+	return append([]types.PipelineConstructorFunc{func(i *int) (types.Pipeline, error) {
+		return pipeline.NewProcessor(log, stats, &lineageProcessor{
+			label: label,
+			now:   time.Now,
+		}), nil
+	}}, pipelines...)
+}
+
+//------------------------------------------------------------------------------
+
+// lineageProcessor adds metadata to messages describing where and when they
+// were consumed.
+type lineageProcessor struct {
+	label string
+	now   func() time.Time
+}
+
+func (l *lineageProcessor) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	if msg.Len() == 0 {
+		return nil, response.NewAck()
+	}
+	ingested := l.now().Format(time.RFC3339Nano)
+	msg.Iter(func(i int, p types.Part) error {
+		meta := p.Metadata()
+		meta.Set("ingest_timestamp", ingested)
+		meta.Set("input_label", l.label)
+		if locator := sourceLocator(p); locator != "" {
+			meta.Set("source_locator", locator)
+		}
+		return nil
+	})
+	return []types.Message{msg}, nil
+}
+
+func (l *lineageProcessor) CloseAsync() {
+}
+
+func (l *lineageProcessor) WaitForClose(timeout time.Duration) error {
+	return nil
+}
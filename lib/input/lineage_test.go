@@ -0,0 +1,118 @@
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceLocator(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     map[string]string
+		expected string
+	}{
+		{
+			name: "kafka",
+			meta: map[string]string{
+				"kafka_topic":     "foo",
+				"kafka_partition": "1",
+				"kafka_offset":    "23",
+			},
+			expected: "kafka://foo/1/23",
+		},
+		{
+			name: "s3",
+			meta: map[string]string{
+				"s3_bucket": "foo",
+				"s3_key":    "bar/baz.json",
+			},
+			expected: "s3://foo/bar/baz.json",
+		},
+		{
+			name:     "file",
+			meta:     map[string]string{"path": "/tmp/foo.txt"},
+			expected: "file:///tmp/foo.txt",
+		},
+		{
+			name:     "partial kafka",
+			meta:     map[string]string{"kafka_topic": "foo"},
+			expected: "",
+		},
+		{
+			name:     "unknown",
+			meta:     map[string]string{"foo": "bar"},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			part := message.NewPart(nil)
+			for k, v := range test.meta {
+				part.Metadata().Set(k, v)
+			}
+			assert.Equal(t, test.expected, sourceLocator(part))
+		})
+	}
+}
+
+func TestLineageMetadata(t *testing.T) {
+	in := &fakeInput{ts: make(chan types.Transaction)}
+
+	conf := NewConfig()
+	conf.Label = "foo"
+	conf.Lineage = true
+
+	lIn, err := WrapWithPipelines(in, AppendProcessorsFromConfig(conf, nil, log.Noop(), metrics.Noop())...)
+	require.NoError(t, err)
+
+	msg := message.New([][]byte{[]byte("hello"), []byte("world")})
+	msg.Get(0).Metadata().Set("path", "/tmp/foo.txt")
+
+	resChan := make(chan types.Response)
+	select {
+	case in.ts <- types.NewTransaction(msg, resChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	var tran types.Transaction
+	select {
+	case tran = <-lIn.TransactionChan():
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	require.Equal(t, 2, tran.Payload.Len())
+	for i := 0; i < 2; i++ {
+		meta := tran.Payload.Get(i).Metadata()
+		assert.Equal(t, "foo", meta.Get("input_label"))
+		_, err := time.Parse(time.RFC3339Nano, meta.Get("ingest_timestamp"))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "file:///tmp/foo.txt", tran.Payload.Get(0).Metadata().Get("source_locator"))
+	assert.Equal(t, "", tran.Payload.Get(1).Metadata().Get("source_locator"))
+
+	go func() {
+		tran.ResponseChan <- response.NewAck()
+	}()
+	select {
+	case res := <-resChan:
+		assert.NoError(t, res.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	lIn.CloseAsync()
+	close(in.ts)
+	require.NoError(t, lIn.WaitForClose(time.Second))
+}
@@ -0,0 +1,162 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/pipeline"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/OneOfOne/xxhash"
+)
+
+//------------------------------------------------------------------------------
+
+// MessageIDConfig contains configuration fields for generating identifiers for
+// messages consumed by an input.
+type MessageIDConfig struct {
+	Strategy  string `json:"strategy" yaml:"strategy"`
+	Value     string `json:"value" yaml:"value"`
+	Key       string `json:"key" yaml:"key"`
+	Overwrite bool   `json:"overwrite" yaml:"overwrite"`
+}
+
+// NewMessageIDConfig returns a MessageIDConfig with default values.
+func NewMessageIDConfig() MessageIDConfig {
+	return MessageIDConfig{
+		Strategy:  "",
+		Value:     "",
+		Key:       "id",
+		Overwrite: false,
+	}
+}
+
+// appendMessageIDFromConfig takes a variant arg of pipeline constructor
+// functions and, when the provided input configuration sets a strategy for
+// generating message identifiers, returns a new slice where a pipeline adding
+// identifiers to messages precedes all others.
+func appendMessageIDFromConfig(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	pipelines ...types.PipelineConstructorFunc,
+) []types.PipelineConstructorFunc {
+	if conf.MessageID.Strategy == "" {
+		return pipelines
+	}
+	return append([]types.PipelineConstructorFunc{func(i *int) (types.Pipeline, error) {
+		m, err := newMessageIDProcessor(conf.MessageID, log, stats)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create message_id: %w", err)
+		}
+		return pipeline.NewProcessor(log, stats, m), nil
+	}}, pipelines...)
+}
+
+//------------------------------------------------------------------------------
+
+type messageIDFunc func(index int, msg types.Message) (string, error)
+
+func messageIDContentHash(index int, msg types.Message) (string, error) {
+	h := xxhash.New64()
+	h.Write(msg.Get(index).Get())
+	return strconv.FormatUint(h.Sum64(), 16), nil
+}
+
+func messageIDKafkaOffset(index int, msg types.Message) (string, error) {
+	meta := msg.Get(index).Metadata()
+	topic, partition, offset := meta.Get("kafka_topic"), meta.Get("kafka_partition"), meta.Get("kafka_offset")
+	if topic == "" || partition == "" || offset == "" {
+		return "", errors.New("message is missing kafka topic, partition or offset metadata")
+	}
+	return topic + "-" + partition + "-" + offset, nil
+}
+
+func messageIDInterpolated(value *field.Expression) messageIDFunc {
+	return func(index int, msg types.Message) (string, error) {
+		id := value.String(index, msg)
+		if id == "" {
+			return "", errors.New("interpolated identifier resulted in an empty string")
+		}
+		return id, nil
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// messageIDProcessor generates an identifier for each message and stores it
+// within a metadata field.
+type messageIDProcessor struct {
+	key       string
+	overwrite bool
+	fn        messageIDFunc
+	log       log.Modular
+
+	mErr metrics.StatCounter
+}
+
+func newMessageIDProcessor(conf MessageIDConfig, log log.Modular, stats metrics.Type) (*messageIDProcessor, error) {
+	if conf.Key == "" {
+		return nil, errors.New("a metadata key must be specified")
+	}
+
+	var fn messageIDFunc
+	switch conf.Strategy {
+	case "content_hash":
+		fn = messageIDContentHash
+	case "kafka_offset":
+		fn = messageIDKafkaOffset
+	case "interpolated":
+		value, err := bloblang.NewField(conf.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value expression: %v", err)
+		}
+		fn = messageIDInterpolated(value)
+	default:
+		return nil, fmt.Errorf("strategy not recognised: %v", conf.Strategy)
+	}
+
+	return &messageIDProcessor{
+		key:       conf.Key,
+		overwrite: conf.Overwrite,
+		fn:        fn,
+		log:       log,
+		mErr:      stats.GetCounter("message_id.error"),
+	}, nil
+}
+
+func (m *messageIDProcessor) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	if msg.Len() == 0 {
+		return nil, response.NewAck()
+	}
+	msg.Iter(func(i int, p types.Part) error {
+		if !m.overwrite && p.Metadata().Get(m.key) != "" {
+			return nil
+		}
+		id, err := m.fn(i, msg)
+		if err != nil {
+			m.log.Debugf("Failed to generate message identifier: %v\n", err)
+			m.mErr.Incr(1)
+			processor.FlagErr(p, err)
+			return nil
+		}
+		p.Metadata().Set(m.key, id)
+		return nil
+	})
+	return []types.Message{msg}, nil
+}
+
+func (m *messageIDProcessor) CloseAsync() {
+}
+
+func (m *messageIDProcessor) WaitForClose(timeout time.Duration) error {
+	return nil
+}
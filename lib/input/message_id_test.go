@@ -0,0 +1,133 @@
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageIDContentHash(t *testing.T) {
+	conf := NewMessageIDConfig()
+	conf.Strategy = "content_hash"
+
+	proc, err := newMessageIDProcessor(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	input := message.New([][]byte{[]byte("foo"), []byte("bar"), []byte("foo")})
+	input.Get(1).Metadata().Set("id", "existing")
+
+	msgs, res := proc.ProcessMessage(input)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	assert.Equal(t, "33bf00a859c4ba3f", msgs[0].Get(0).Metadata().Get("id"))
+	assert.Equal(t, "existing", msgs[0].Get(1).Metadata().Get("id"))
+	assert.Equal(t, "33bf00a859c4ba3f", msgs[0].Get(2).Metadata().Get("id"))
+}
+
+func TestMessageIDKafkaOffset(t *testing.T) {
+	conf := NewMessageIDConfig()
+	conf.Strategy = "kafka_offset"
+	conf.Key = "dedupe_key"
+	conf.Overwrite = true
+
+	proc, err := newMessageIDProcessor(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	input := message.New([][]byte{[]byte("foo"), []byte("bar")})
+	input.Get(0).Metadata().
+		Set("kafka_topic", "baz").
+		Set("kafka_partition", "3").
+		Set("kafka_offset", "105").
+		Set("dedupe_key", "existing")
+
+	msgs, res := proc.ProcessMessage(input)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	assert.Equal(t, "baz-3-105", msgs[0].Get(0).Metadata().Get("dedupe_key"))
+	assert.False(t, processor.HasFailed(msgs[0].Get(0)))
+
+	assert.Equal(t, "", msgs[0].Get(1).Metadata().Get("dedupe_key"))
+	assert.True(t, processor.HasFailed(msgs[0].Get(1)))
+}
+
+func TestMessageIDInterpolated(t *testing.T) {
+	conf := NewMessageIDConfig()
+	conf.Strategy = "interpolated"
+	conf.Value = `${! meta("source") }:${! json("id") }`
+
+	proc, err := newMessageIDProcessor(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	input := message.New([][]byte{[]byte(`{"id":"foo"}`)})
+	input.Get(0).Metadata().Set("source", "bar")
+
+	msgs, res := proc.ProcessMessage(input)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	assert.Equal(t, "bar:foo", msgs[0].Get(0).Metadata().Get("id"))
+}
+
+func TestMessageIDBadStrategy(t *testing.T) {
+	conf := NewConfig()
+	conf.MessageID.Strategy = "nope"
+
+	_, err := WrapWithPipelines(&fakeInput{ts: make(chan types.Transaction)}, AppendProcessorsFromConfig(conf, nil, log.Noop(), metrics.Noop())...)
+	require.EqualError(t, err, "failed to create message_id: strategy not recognised: nope")
+}
+
+func TestMessageIDPrecedesProcessors(t *testing.T) {
+	in := &fakeInput{ts: make(chan types.Transaction)}
+
+	conf := NewConfig()
+	conf.MessageID.Strategy = "content_hash"
+
+	procConf := processor.NewConfig()
+	procConf.Type = processor.TypeBloblang
+	procConf.Bloblang.Mapping = `root = meta("id")`
+	conf.Processors = append(conf.Processors, procConf)
+
+	mIn, err := WrapWithPipelines(in, AppendProcessorsFromConfig(conf, nil, log.Noop(), metrics.Noop())...)
+	require.NoError(t, err)
+
+	resChan := make(chan types.Response)
+	select {
+	case in.ts <- types.NewTransaction(message.New([][]byte{[]byte("foo")}), resChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	var tran types.Transaction
+	select {
+	case tran = <-mIn.TransactionChan():
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	require.Equal(t, 1, tran.Payload.Len())
+	assert.Equal(t, "33bf00a859c4ba3f", string(tran.Payload.Get(0).Get()))
+
+	go func() {
+		tran.ResponseChan <- response.NewAck()
+	}()
+	select {
+	case res := <-resChan:
+		assert.NoError(t, res.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	mIn.CloseAsync()
+	close(in.ts)
+	require.NoError(t, mIn.WaitForClose(time.Second))
+}
@@ -23,6 +23,7 @@ This input adds the following metadata fields to each message:
 
 ` + "``` text" + `
 - nats_subject
+- All message headers (when supported by the connection)
 ` + "```" + `
 
 You can access these metadata fields using
@@ -0,0 +1,74 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+)
+
+//------------------------------------------------------------------------------
+
+// pollScheduleFieldSpecs returns the field specs of a poll interval and jitter
+// for an input that polls a source, where the interval field is documented with
+// a description specific to the input.
+func pollScheduleFieldSpecs(intervalDesc string) docs.FieldSpecs {
+	return docs.FieldSpecs{
+		docs.FieldAdvanced("poll_interval", intervalDesc, "30s", "5m").AtVersion("3.51.0"),
+		docs.FieldFloat("poll_jitter", "A percentage by which each `poll_interval` is randomly increased or decreased, which prevents instances polling the same source from doing so at the same time. For example, a `poll_interval` of `60s` with a `poll_jitter` of `10` results in periods between `54s` and `66s`.").Advanced().AtVersion("3.51.0"),
+	}
+}
+
+// pollSchedule paces the polls of a source by a minimum period between the
+// start of each poll, where each period is randomly adjusted by a jitter
+// percentage.
+type pollSchedule struct {
+	interval time.Duration
+	jitter   float64
+	next     time.Time
+}
+
+// newPollSchedule parses a poll interval and jitter percentage, returning nil
+// when the interval is empty, meaning polls are not paced.
+func newPollSchedule(interval string, jitter float64) (*pollSchedule, error) {
+	if jitter < 0 || jitter > 100 {
+		return nil, fmt.Errorf("poll jitter must be between 0 and 100, received %v", jitter)
+	}
+	if interval == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse poll interval: %w", err)
+	}
+	if d <= 0 {
+		return nil, nil
+	}
+	return &pollSchedule{interval: d, jitter: jitter}, nil
+}
+
+// period returns the poll interval randomly adjusted by the jitter percentage.
+func (p *pollSchedule) period() time.Duration {
+	if p.jitter <= 0 {
+		return p.interval
+	}
+	spread := float64(p.interval) * p.jitter / 100
+	return p.interval + time.Duration(spread*(2*rand.Float64()-1))
+}
+
+// wait blocks until the next poll is due and schedules the poll after it,
+// returning an error if the context is cancelled whilst waiting. The first
+// poll is due immediately.
+func (p *pollSchedule) wait(ctx context.Context) error {
+	if wait := time.Until(p.next); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	p.next = time.Now().Add(p.period())
+	return nil
+}
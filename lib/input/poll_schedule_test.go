@@ -0,0 +1,46 @@
+package input
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollScheduleParse(t *testing.T) {
+	p, err := newPollSchedule("", 10)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = newPollSchedule("nope", 10)
+	require.EqualError(t, err, "failed to parse poll interval: time: invalid duration \"nope\"")
+
+	_, err = newPollSchedule("1s", -1)
+	require.EqualError(t, err, "poll jitter must be between 0 and 100, received -1")
+
+	p, err = newPollSchedule("1s", 0)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, p.period())
+}
+
+func TestPollScheduleWait(t *testing.T) {
+	p, err := newPollSchedule("50ms", 20)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// The first poll is due immediately.
+	started := time.Now()
+	require.NoError(t, p.wait(ctx))
+	assert.Less(t, int64(time.Since(started)), int64(40*time.Millisecond))
+
+	started = time.Now()
+	require.NoError(t, p.wait(ctx))
+	assert.GreaterOrEqual(t, int64(time.Since(started)), int64(40*time.Millisecond))
+
+	cancelledCtx, done := context.WithCancel(ctx)
+	done()
+	require.Error(t, p.wait(cancelledCtx))
+}
@@ -0,0 +1,218 @@
+package input
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// rateLimitedReader is implemented by inputs that read data within a loop and
+// are able to take access from a rate limit before each read.
+type rateLimitedReader interface {
+	setRateLimit(gate *rateLimitGate)
+}
+
+// ApplyRateLimitFromConfig gates the reads of an input by the rate limit
+// resource referenced by its config, if any. Inputs that read data within a
+// loop, such as those created with NewAsyncReader, access the rate limit
+// before each read and therefore never read data ahead of it. Any other input
+// instead has its transactions gated by a pipeline that precedes all of the
+// provided pipelines, which is returned as a new slice.
+func ApplyRateLimitFromConfig(
+	conf Config,
+	in Type,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	pipelines ...types.PipelineConstructorFunc,
+) ([]types.PipelineConstructorFunc, error) {
+	rl, ok := in.(rateLimitedReader)
+	if conf.RateLimit == "" || !ok {
+		return appendRateLimitFromConfig(conf, mgr, log, stats, pipelines...), nil
+	}
+	gate, err := newRateLimitGate(conf.RateLimit, mgr, log, stats)
+	if err != nil {
+		return nil, err
+	}
+	rl.setRateLimit(gate)
+	return pipelines, nil
+}
+
+// appendRateLimitFromConfig takes a variant arg of pipeline constructor
+// functions and, when the provided input configuration references a rate limit
+// resource, returns a new slice where a pipeline gating the flow of
+// transactions by that rate limit precedes all others.
+func appendRateLimitFromConfig(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	pipelines ...types.PipelineConstructorFunc,
+) []types.PipelineConstructorFunc {
+	if conf.RateLimit == "" {
+		return pipelines
+	}
+	return append([]types.PipelineConstructorFunc{func(i *int) (types.Pipeline, error) {
+		gate, err := newRateLimitGate(conf.RateLimit, mgr, log, stats)
+		if err != nil {
+			return nil, err
+		}
+		return newRateLimitPipeline(gate), nil
+	}}, pipelines...)
+}
+
+//------------------------------------------------------------------------------
+
+// rateLimitGate blocks until access to a rate limit resource is granted.
+type rateLimitGate struct {
+	rlName string
+	mgr    types.Manager
+	log    log.Modular
+
+	mRateLimited metrics.StatCounter
+	mErr         metrics.StatCounter
+}
+
+func newRateLimitGate(
+	rlName string, mgr types.Manager, log log.Modular, stats metrics.Type,
+) (*rateLimitGate, error) {
+	if err := interop.ProbeRateLimit(context.Background(), mgr, rlName); err != nil {
+		return nil, err
+	}
+	return &rateLimitGate{
+		rlName:       rlName,
+		mgr:          mgr,
+		log:          log,
+		mRateLimited: stats.GetCounter("rate_limit.limited"),
+		mErr:         stats.GetCounter("rate_limit.error"),
+	}, nil
+}
+
+// access blocks until the rate limit grants access, returning false if the
+// context was cancelled or the rate limit closed whilst waiting.
+func (r *rateLimitGate) access(ctx context.Context) bool {
+	for {
+		var waitFor time.Duration
+		var err error
+		if rerr := interop.AccessRateLimit(ctx, r.mgr, r.rlName, func(rl types.RateLimit) {
+			waitFor, err = rl.Access()
+		}); rerr != nil {
+			err = rerr
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		if err == nil && waitFor <= 0 {
+			return true
+		}
+		if err == types.ErrTypeClosed {
+			return false
+		}
+		if err != nil {
+			r.mErr.Incr(1)
+			r.log.Errorf("Failed to access rate limit: %v\n", err)
+			waitFor = time.Second
+		} else {
+			r.mRateLimited.Incr(1)
+		}
+		select {
+		case <-time.After(waitFor):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// rateLimitPipeline is a types.Pipeline that consumes transactions from an
+// input and only forwards each one once access to a rate limit resource has
+// been granted. Since inputs block until their transactions are consumed this
+// also blocks the input from reading any further data.
+type rateLimitPipeline struct {
+	gate *rateLimitGate
+
+	transactionsOut chan types.Transaction
+
+	closeCtx   context.Context
+	close      func()
+	closedChan chan struct{}
+	closeOnce  sync.Once
+}
+
+func newRateLimitPipeline(gate *rateLimitGate) *rateLimitPipeline {
+	closeCtx, closeFn := context.WithCancel(context.Background())
+	return &rateLimitPipeline{
+		gate:            gate,
+		transactionsOut: make(chan types.Transaction),
+		closeCtx:        closeCtx,
+		close:           closeFn,
+		closedChan:      make(chan struct{}),
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func (r *rateLimitPipeline) loop(tChan <-chan types.Transaction) {
+	defer func() {
+		close(r.transactionsOut)
+		close(r.closedChan)
+	}()
+
+	for {
+		var tran types.Transaction
+		var open bool
+		select {
+		case tran, open = <-tChan:
+			if !open {
+				return
+			}
+		case <-r.closeCtx.Done():
+			return
+		}
+
+		if !r.gate.access(r.closeCtx) {
+			return
+		}
+
+		select {
+		case r.transactionsOut <- tran:
+		case <-r.closeCtx.Done():
+			return
+		}
+	}
+}
+
+// Consume assigns a messages channel for the pipeline to read.
+func (r *rateLimitPipeline) Consume(msgs <-chan types.Transaction) error {
+	go r.loop(msgs)
+	return nil
+}
+
+// TransactionChan returns the channel used for consuming messages from this
+// pipeline.
+func (r *rateLimitPipeline) TransactionChan() <-chan types.Transaction {
+	return r.transactionsOut
+}
+
+// CloseAsync shuts down the pipeline and stops processing messages.
+func (r *rateLimitPipeline) CloseAsync() {
+	r.closeOnce.Do(r.close)
+}
+
+// WaitForClose blocks until the pipeline has closed down.
+func (r *rateLimitPipeline) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-r.closedChan:
+	case <-time.After(timeout):
+		return types.ErrTimeout
+	}
+	return nil
+}
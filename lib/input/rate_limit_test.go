@@ -0,0 +1,178 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/input/reader"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRateLimit struct {
+	resFn func() (time.Duration, error)
+}
+
+func (f fakeRateLimit) Access() (time.Duration, error) {
+	return f.resFn()
+}
+func (f fakeRateLimit) CloseAsync()                      {}
+func (f fakeRateLimit) WaitForClose(time.Duration) error { return nil }
+
+func TestInputRateLimit(t *testing.T) {
+	var hits int32
+	mgr := &fakeProcMgr{
+		rls: map[string]types.RateLimit{
+			"foo": fakeRateLimit{resFn: func() (time.Duration, error) {
+				switch atomic.AddInt32(&hits, 1) {
+				case 1:
+					return time.Millisecond * 10, nil
+				case 2:
+					return 0, errors.New("nope")
+				}
+				return 0, nil
+			}},
+		},
+	}
+
+	in := &fakeInput{ts: make(chan types.Transaction)}
+
+	conf := NewConfig()
+	conf.RateLimit = "foo"
+
+	pipelines, err := ApplyRateLimitFromConfig(conf, in, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	rlIn, err := WrapWithPipelines(in, pipelines...)
+	require.NoError(t, err)
+
+	resChan := make(chan types.Response)
+	select {
+	case in.ts <- types.NewTransaction(message.New([][]byte{[]byte("hello world")}), resChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	var tran types.Transaction
+	select {
+	case tran = <-rlIn.TransactionChan():
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+	assert.Equal(t, "hello world", string(tran.Payload.Get(0).Get()))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	go func() {
+		tran.ResponseChan <- response.NewAck()
+	}()
+	select {
+	case res := <-resChan:
+		assert.NoError(t, res.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	close(in.ts)
+	rlIn.CloseAsync()
+	require.NoError(t, rlIn.WaitForClose(time.Second))
+}
+
+type countingAsyncReader struct {
+	connChan chan struct{}
+	reads    int32
+}
+
+func (c *countingAsyncReader) ConnectWithContext(ctx context.Context) error {
+	select {
+	case <-c.connChan:
+	case <-ctx.Done():
+		return types.ErrTypeClosed
+	}
+	return nil
+}
+
+func (c *countingAsyncReader) ReadWithContext(ctx context.Context) (types.Message, reader.AsyncAckFn, error) {
+	atomic.AddInt32(&c.reads, 1)
+	return message.New([][]byte{[]byte("hello world")}), func(context.Context, types.Response) error {
+		return nil
+	}, nil
+}
+
+func (c *countingAsyncReader) CloseAsync() {}
+
+func (c *countingAsyncReader) WaitForClose(time.Duration) error {
+	return nil
+}
+
+func TestAsyncReaderRateLimit(t *testing.T) {
+	tokens := make(chan struct{}, 1)
+	mgr := &fakeProcMgr{
+		rls: map[string]types.RateLimit{
+			"foo": fakeRateLimit{resFn: func() (time.Duration, error) {
+				select {
+				case <-tokens:
+					return 0, nil
+				default:
+				}
+				return time.Millisecond, nil
+			}},
+		},
+	}
+
+	rdr := &countingAsyncReader{connChan: make(chan struct{})}
+	in, err := NewAsyncReader("foo", false, rdr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := NewConfig()
+	conf.RateLimit = "foo"
+
+	pipelines, err := ApplyRateLimitFromConfig(conf, in, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	assert.Empty(t, pipelines)
+	close(rdr.connChan)
+
+	// No data is read until the rate limit grants access
+	<-time.After(time.Millisecond * 50)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&rdr.reads))
+
+	tokens <- struct{}{}
+	select {
+	case tran := <-in.TransactionChan():
+		assert.Equal(t, "hello world", string(tran.Payload.Get(0).Get()))
+		go func() {
+			tran.ResponseChan <- response.NewAck()
+		}()
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+
+	<-time.After(time.Millisecond * 50)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&rdr.reads))
+
+	in.CloseAsync()
+	require.NoError(t, in.WaitForClose(time.Second*5))
+}
+
+func TestInputRateLimitNotFound(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = TypeResource
+	conf.Resource = "foo"
+	conf.RateLimit = "bar"
+
+	mgr := &fakeProcMgr{
+		ins: map[string]types.Input{
+			"foo": &fakeInput{ts: make(chan types.Transaction)},
+		},
+	}
+
+	_, err := New(conf, mgr, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "rate limit resource 'bar' was not found")
+}
@@ -1,6 +1,7 @@
 package input
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,6 +24,9 @@ type Reader struct {
 	typeStr string
 	reader  reader.Type
 
+	// Holds a *rateLimitGate when reads are gated by a rate limit.
+	rateLimit atomic.Value
+
 	stats metrics.Type
 	log   log.Modular
 
@@ -66,6 +70,29 @@ func NewReader(
 
 //------------------------------------------------------------------------------
 
+func (r *Reader) setRateLimit(gate *rateLimitGate) {
+	r.rateLimit.Store(gate)
+}
+
+// waitForRateLimit blocks until the rate limit of the reader, if any, grants
+// access, returning false if the reader was closed whilst waiting.
+func (r *Reader) waitForRateLimit() bool {
+	gate, _ := r.rateLimit.Load().(*rateLimitGate)
+	if gate == nil {
+		return true
+	}
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+	go func() {
+		select {
+		case <-r.closeChan:
+			done()
+		case <-ctx.Done():
+		}
+	}()
+	return gate.access(ctx)
+}
+
 func (r *Reader) loop() {
 	// Metrics paths
 	var (
@@ -77,6 +104,7 @@ func (r *Reader) loop() {
 		mFailedConn = r.stats.GetCounter("connection.failed")
 		mLostConn   = r.stats.GetCounter("connection.lost")
 		mLatency    = r.stats.GetTimer("latency")
+		mSize       = r.stats.GetTimer("message_size")
 	)
 
 	defer func() {
@@ -110,6 +138,9 @@ func (r *Reader) loop() {
 	atomic.StoreInt32(&r.connected, 1)
 
 	for atomic.LoadInt32(&r.running) == 1 {
+		if !r.waitForRateLimit() {
+			return
+		}
 		msg, err := r.reader.Read()
 
 		// If our reader says it is not connected.
@@ -157,6 +188,7 @@ func (r *Reader) loop() {
 			mCount.Incr(1)
 			mPartsRcvd.Incr(int64(msg.Len()))
 			mRcvd.Incr(1)
+			recordMessageSizes(mSize, msg)
 			r.log.Tracef("Consumed %v messages from '%v'.\n", msg.Len(), r.typeStr)
 		}
 
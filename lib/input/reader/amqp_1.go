@@ -20,11 +20,13 @@ import (
 
 // AMQP1Config contains configuration for the AMQP1 input type.
 type AMQP1Config struct {
-	URL            string      `json:"url" yaml:"url"`
-	SourceAddress  string      `json:"source_address" yaml:"source_address"`
-	AzureRenewLock bool        `json:"azure_renew_lock" yaml:"azure_renew_lock"`
-	TLS            btls.Config `json:"tls" yaml:"tls"`
-	SASL           sasl.Config `json:"sasl" yaml:"sasl"`
+	URL            string            `json:"url" yaml:"url"`
+	SourceAddress  string            `json:"source_address" yaml:"source_address"`
+	AzureRenewLock bool              `json:"azure_renew_lock" yaml:"azure_renew_lock"`
+	Selector       string            `json:"selector" yaml:"selector"`
+	SourceFilters  map[string]string `json:"source_filters" yaml:"source_filters"`
+	TLS            btls.Config       `json:"tls" yaml:"tls"`
+	SASL           sasl.Config       `json:"sasl" yaml:"sasl"`
 }
 
 // NewAMQP1Config creates a new AMQP1Config with default values.
@@ -32,6 +34,8 @@ func NewAMQP1Config() AMQP1Config {
 	return AMQP1Config{
 		URL:           "",
 		SourceAddress: "",
+		Selector:      "",
+		SourceFilters: map[string]string{},
 		TLS:           btls.NewConfig(),
 		SASL:          sasl.NewConfig(),
 	}
@@ -110,6 +114,20 @@ func NewAMQP1(conf AMQP1Config, log log.Modular, stats metrics.Type) (*AMQP1, er
 
 //------------------------------------------------------------------------------
 
+func (a *AMQP1) receiverOpts() []amqp.LinkOption {
+	opts := []amqp.LinkOption{
+		amqp.LinkSourceAddress(a.conf.SourceAddress),
+		amqp.LinkCredit(10),
+	}
+	if a.conf.Selector != "" {
+		opts = append(opts, amqp.LinkSelectorFilter(a.conf.Selector))
+	}
+	for k, v := range a.conf.SourceFilters {
+		opts = append(opts, amqp.LinkSourceFilter(k, 0, v))
+	}
+	return opts
+}
+
 // ConnectWithContext establishes a connection to an AMQP1 server.
 func (a *AMQP1) ConnectWithContext(ctx context.Context) error {
 	a.m.Lock()
@@ -144,10 +162,7 @@ func (a *AMQP1) ConnectWithContext(ctx context.Context) error {
 	}
 
 	// Create a receiver
-	if conn.receiver, err = conn.session.NewReceiver(
-		amqp.LinkSourceAddress(a.conf.SourceAddress),
-		amqp.LinkCredit(10),
-	); err != nil {
+	if conn.receiver, err = conn.session.NewReceiver(a.receiverOpts()...); err != nil {
 		conn.Close(ctx)
 		return err
 	}
@@ -20,6 +20,25 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestAMQP1ReceiverOpts(t *testing.T) {
+	conf := NewAMQP1Config()
+	conf.SourceAddress = "queue:/foo"
+
+	m, err := NewAMQP1(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	assert.Len(t, m.receiverOpts(), 2)
+
+	conf.Selector = "color = 'red'"
+	conf.SourceFilters = map[string]string{
+		"foo": "bar",
+		"baz": "buz",
+	}
+
+	m, err = NewAMQP1(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	assert.Len(t, m.receiverOpts(), 5)
+}
+
 func TestAMQP1Integration(t *testing.T) {
 	if m := flag.Lookup("test.run").Value.String(); m == "" || regexp.MustCompile(strings.Split(m, "/")[0]).FindString(t.Name()) == "" {
 		t.Skip("Skipping as execution was not requested explicitly using go test -run ^TestIntegration$")
@@ -82,17 +82,18 @@ func (p *AsyncBatcher) wrapAckFns() AsyncAckFn {
 
 // ReadWithContext attempts to read a new message from the source.
 func (p *AsyncBatcher) ReadWithContext(ctx context.Context) (types.Message, AsyncAckFn, error) {
-	var forcedBatchDeadline time.Time
-	if tout := p.batcher.UntilNext(); tout >= 0 {
-		forcedBatchDeadline = time.Now().Add(tout)
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithDeadline(ctx, forcedBatchDeadline)
-		defer cancel()
-	}
-
 	flushBatch := false
 	for !flushBatch {
-		msg, ackFn, err := p.r.ReadWithContext(ctx)
+		// The deadline is recalculated for each read as an idle period is
+		// extended each time a message is added to the batch.
+		readCtx, cancel := ctx, func() {}
+		var forcedBatchDeadline time.Time
+		if tout := p.batcher.UntilNext(); tout >= 0 {
+			forcedBatchDeadline = time.Now().Add(tout)
+			readCtx, cancel = context.WithDeadline(ctx, forcedBatchDeadline)
+		}
+		msg, ackFn, err := p.r.ReadWithContext(readCtx)
+		cancel()
 		if err != nil {
 			if !forcedBatchDeadline.IsZero() && !time.Now().Before(forcedBatchDeadline) {
 				if batch := p.batcher.Flush(); batch != nil && batch.Len() > 0 {
@@ -104,13 +105,11 @@ func (p *AsyncBatcher) ReadWithContext(ctx context.Context) (types.Message, Asyn
 				// was cancelled, in which case we want to return right now, or
 				// that the underlying mechanism timed out, in which case we
 				// simply want to try again.
-				select {
-				case <-ctx.Done():
+				if ctx.Err() != nil || (!forcedBatchDeadline.IsZero() && !time.Now().Before(forcedBatchDeadline)) {
 					if batch := p.batcher.Flush(); batch != nil && batch.Len() > 0 {
 						return batch, p.wrapAckFns(), nil
 					}
 					return nil, nil, types.ErrTimeout
-				default:
 				}
 				continue
 			}
@@ -55,6 +55,9 @@ type GCPPubSub struct {
 	client      *pubsub.Client
 	pendingMsgs []*pubsub.Message
 
+	prefetch      *gcpPubSubPrefetch
+	adaptInterval time.Duration
+
 	log   log.Modular
 	stats metrics.Type
 }
@@ -70,10 +73,12 @@ func NewGCPPubSub(
 		return nil, err
 	}
 	return &GCPPubSub{
-		conf:   conf,
-		log:    log,
-		stats:  stats,
-		client: client,
+		conf:          conf,
+		log:           log,
+		stats:         stats,
+		client:        client,
+		prefetch:      newGCPPubSubPrefetch(conf.MaxOutstandingMessages),
+		adaptInterval: gcpPubSubAdaptInterval,
 	}, nil
 }
 
@@ -91,40 +96,98 @@ func (c *GCPPubSub) ConnectWithContext(ignored context.Context) error {
 		return nil
 	}
 
-	sub := c.client.Subscription(c.conf.SubscriptionID)
-	sub.ReceiveSettings.MaxOutstandingMessages = c.conf.MaxOutstandingMessages
-	sub.ReceiveSettings.MaxOutstandingBytes = c.conf.MaxOutstandingBytes
-
 	subCtx, cancel := context.WithCancel(context.Background())
 	msgsChan := make(chan *pubsub.Message, c.conf.MaxBatchCount)
 
-	c.subscription = sub
+	c.subscription = c.client.Subscription(c.conf.SubscriptionID)
 	c.msgsChan = msgsChan
 	c.closeFunc = cancel
 
-	go func() {
-		rerr := sub.Receive(subCtx, func(ctx context.Context, m *pubsub.Message) {
-			select {
-			case msgsChan <- m:
-			case <-ctx.Done():
-				if m != nil {
+	go c.receiveLoop(subCtx, msgsChan)
+
+	c.log.Infof("Receiving GCP Cloud Pub/Sub messages from project '%v' and subscription '%v'\n", c.conf.ProjectID, c.conf.SubscriptionID)
+	return nil
+}
+
+// receiveLoop receives messages from the subscription until the context is
+// cancelled or the subscription fails. The number of outstanding messages
+// permitted is periodically adapted to the rate at which messages are
+// consumed, and when it changes the streaming pull is restarted with the new
+// limit. Messages held by the previous stream that have not yet been consumed
+// are nacked, whereas those already consumed remain acknowledgeable.
+func (c *GCPPubSub) receiveLoop(ctx context.Context, msgsChan chan *pubsub.Message) {
+	var wg sync.WaitGroup
+	errChan := make(chan error, 1)
+
+	receive := func(maxOutstanding int) context.CancelFunc {
+		sub := c.client.Subscription(c.conf.SubscriptionID)
+		sub.ReceiveSettings.MaxOutstandingMessages = maxOutstanding
+		sub.ReceiveSettings.MaxOutstandingBytes = c.conf.MaxOutstandingBytes
+
+		recvCtx, cancel := context.WithCancel(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rerr := sub.Receive(recvCtx, func(ctx context.Context, m *pubsub.Message) {
+				backPressureTimer := time.NewTimer(gcpPubSubBackPressureThreshold)
+				defer backPressureTimer.Stop()
+				select {
+				case msgsChan <- m:
+					return
+				case <-backPressureTimer.C:
+					c.prefetch.backPressured()
+				case <-ctx.Done():
 					m.Nack()
+					return
+				}
+				select {
+				case msgsChan <- m:
+				case <-ctx.Done():
+					m.Nack()
+				}
+			})
+			if rerr != nil && rerr != context.Canceled {
+				select {
+				case errChan <- rerr:
+				default:
 				}
 			}
-		})
-		if rerr != nil && rerr != context.Canceled {
+		}()
+		return cancel
+	}
+
+	limit := c.prefetch.get()
+	cancel := receive(limit)
+
+	adaptTicker := time.NewTicker(c.adaptInterval)
+	defer adaptTicker.Stop()
+
+adaptLoop:
+	for {
+		select {
+		case <-adaptTicker.C:
+			if newLimit := c.prefetch.adapt(); newLimit != limit {
+				c.log.Debugf("Adapting max outstanding messages from %v to %v\n", limit, newLimit)
+				cancel()
+				limit = newLimit
+				cancel = receive(limit)
+			}
+		case rerr := <-errChan:
 			c.log.Errorf("Subscription error: %v\n", rerr)
+			break adaptLoop
+		case <-ctx.Done():
+			break adaptLoop
 		}
-		c.subMut.Lock()
-		c.subscription = nil
-		close(c.msgsChan)
-		c.msgsChan = nil
-		c.closeFunc = nil
-		c.subMut.Unlock()
-	}()
+	}
+	cancel()
+	wg.Wait()
 
-	c.log.Infof("Receiving GCP Cloud Pub/Sub messages from project '%v' and subscription '%v'\n", c.conf.ProjectID, c.conf.SubscriptionID)
-	return nil
+	c.subMut.Lock()
+	c.subscription = nil
+	close(c.msgsChan)
+	c.msgsChan = nil
+	c.closeFunc = nil
+	c.subMut.Unlock()
 }
 
 // ReadWithContext attempts to read a new message from the target subscription.
@@ -142,8 +205,14 @@ func (c *GCPPubSub) ReadWithContext(ctx context.Context) (types.Message, AsyncAc
 	var open bool
 	select {
 	case gmsg, open = <-msgsChan:
-	case <-ctx.Done():
-		return nil, nil, types.ErrTimeout
+	default:
+		// No messages are waiting to be consumed.
+		c.prefetch.starved()
+		select {
+		case gmsg, open = <-msgsChan:
+		case <-ctx.Done():
+			return nil, nil, types.ErrTimeout
+		}
 	}
 	if !open {
 		return nil, nil, types.ErrNotConnected
@@ -175,7 +244,15 @@ func (c *GCPPubSub) Read() (types.Message, error) {
 
 	msg := message.New(nil)
 
-	gmsg, open := <-msgsChan
+	var gmsg *pubsub.Message
+	var open bool
+	select {
+	case gmsg, open = <-msgsChan:
+	default:
+		// No messages are waiting to be consumed.
+		c.prefetch.starved()
+		gmsg, open = <-msgsChan
+	}
 	if !open {
 		return nil, types.ErrNotConnected
 	}
@@ -220,6 +297,77 @@ func (c *GCPPubSub) Acknowledge(err error) error {
 	return nil
 }
 
+//------------------------------------------------------------------------------
+
+// gcpPubSubBackPressureThreshold is how long a received message can wait to be
+// consumed before the pipeline is considered to be applying back pressure.
+const gcpPubSubBackPressureThreshold = time.Millisecond * 10
+
+// gcpPubSubAdaptInterval is how often the number of outstanding messages is
+// adapted to the rate at which messages are consumed.
+const gcpPubSubAdaptInterval = time.Second
+
+// gcpPubSubPrefetch tracks the number of outstanding messages to permit, which
+// shrinks while received messages are left waiting for the pipeline and grows
+// back up to the configured maximum while the pipeline is waiting for
+// messages.
+type gcpPubSubPrefetch struct {
+	mut             sync.Mutex
+	size            int
+	max             int
+	isBackPressured bool
+	isStarved       bool
+}
+
+func newGCPPubSubPrefetch(max int) *gcpPubSubPrefetch {
+	return &gcpPubSubPrefetch{size: max, max: max}
+}
+
+func (p *gcpPubSubPrefetch) get() int {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	return p.size
+}
+
+// backPressured records that a received message was left waiting for the
+// pipeline.
+func (p *gcpPubSubPrefetch) backPressured() {
+	p.mut.Lock()
+	p.isBackPressured = true
+	p.mut.Unlock()
+}
+
+// starved records that the pipeline was left waiting for a message.
+func (p *gcpPubSubPrefetch) starved() {
+	p.mut.Lock()
+	p.isStarved = true
+	p.mut.Unlock()
+}
+
+// adapt returns the number of outstanding messages to permit from the signals
+// recorded since the last call. A maximum below one means that the number of
+// outstanding messages is unlimited, in which case it is never adapted.
+func (p *gcpPubSubPrefetch) adapt() int {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	if p.max < 1 {
+		return p.size
+	}
+	if p.isBackPressured && !p.isStarved {
+		if p.size /= 2; p.size < 1 {
+			p.size = 1
+		}
+	} else if p.isStarved && !p.isBackPressured {
+		if p.size *= 2; p.size > p.max {
+			p.size = p.max
+		}
+	}
+	p.isBackPressured, p.isStarved = false, false
+	return p.size
+}
+
+//------------------------------------------------------------------------------
+
 // CloseAsync begins cleaning up resources used by this reader asynchronously.
 func (c *GCPPubSub) CloseAsync() {
 	c.subMut.Lock()
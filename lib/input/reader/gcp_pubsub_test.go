@@ -0,0 +1,126 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+func TestGCPPubSubPrefetch(t *testing.T) {
+	p := newGCPPubSubPrefetch(8)
+	assert.Equal(t, 8, p.get())
+
+	// No signals do not change the size.
+	assert.Equal(t, 8, p.adapt())
+
+	p.backPressured()
+	assert.Equal(t, 4, p.adapt())
+
+	for i := 0; i < 5; i++ {
+		p.backPressured()
+		p.adapt()
+	}
+	assert.Equal(t, 1, p.get())
+
+	// Mixed signals do not change the size.
+	p.backPressured()
+	p.starved()
+	assert.Equal(t, 1, p.adapt())
+
+	p.starved()
+	assert.Equal(t, 2, p.adapt())
+
+	for i := 0; i < 5; i++ {
+		p.starved()
+		p.adapt()
+	}
+	assert.Equal(t, 8, p.get())
+
+	// An unlimited number of outstanding messages is never adapted.
+	p = newGCPPubSubPrefetch(-1)
+	p.backPressured()
+	assert.Equal(t, -1, p.adapt())
+}
+
+func TestGCPPubSubAdaptiveReceive(t *testing.T) {
+	// Messages in transit when a stream is restarted are only redelivered once
+	// their ack deadline of at least ten seconds expires.
+	if testing.Short() {
+		t.Skip("Skipping slow test in short mode")
+	}
+
+	srv := pstest.NewServer()
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	client, err := pubsub.NewClient(ctx, "foo", option.WithGRPCConn(conn))
+	require.NoError(t, err)
+
+	topic, err := client.CreateTopic(ctx, "bar")
+	require.NoError(t, err)
+
+	_, err = client.CreateSubscription(ctx, "baz", pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		_, err = topic.Publish(ctx, &pubsub.Message{
+			Data: []byte(fmt.Sprintf("msg%v", i)),
+		}).Get(ctx)
+		require.NoError(t, err)
+	}
+
+	conf := NewGCPPubSubConfig()
+	conf.ProjectID = "foo"
+	conf.SubscriptionID = "baz"
+	conf.MaxOutstandingMessages = 8
+
+	r := &GCPPubSub{
+		conf:          conf,
+		log:           log.Noop(),
+		stats:         metrics.Noop(),
+		client:        client,
+		prefetch:      newGCPPubSubPrefetch(conf.MaxOutstandingMessages),
+		adaptInterval: time.Millisecond * 50,
+	}
+	require.NoError(t, r.ConnectWithContext(ctx))
+
+	// Leaving messages unconsumed shrinks the number of outstanding messages.
+	assert.Eventually(t, func() bool {
+		return r.prefetch.get() == 1
+	}, time.Second*10, time.Millisecond*10)
+
+	// Messages nacked by restarts are redelivered.
+	seen := map[string]struct{}{}
+	for len(seen) < 20 {
+		msg, ackFn, err := r.ReadWithContext(ctx)
+		require.NoError(t, err)
+		seen[string(msg.Get(0).Get())] = struct{}{}
+		require.NoError(t, ackFn(ctx, response.NewAck()))
+	}
+
+	r.CloseAsync()
+	require.NoError(t, r.WaitForClose(time.Second))
+}
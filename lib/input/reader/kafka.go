@@ -28,13 +28,18 @@ type KafkaConfig struct {
 	Topics              []string                 `json:"topics" yaml:"topics"`
 	ClientID            string                   `json:"client_id" yaml:"client_id"`
 	ConsumerGroup       string                   `json:"consumer_group" yaml:"consumer_group"`
+	GroupInstanceID     string                   `json:"group_instance_id" yaml:"group_instance_id"`
 	Group               KafkaBalancedGroupConfig `json:"group" yaml:"group"`
 	CommitPeriod        string                   `json:"commit_period" yaml:"commit_period"`
+	OffsetsFile         string                   `json:"offsets_file" yaml:"offsets_file"`
+	ShardIndex          int                      `json:"shard_index" yaml:"shard_index"`
+	ShardCount          int                      `json:"shard_count" yaml:"shard_count"`
 	CheckpointLimit     int                      `json:"checkpoint_limit" yaml:"checkpoint_limit"`
 	ExtractTracingMap   string                   `json:"extract_tracing_map" yaml:"extract_tracing_map"`
 	MaxProcessingPeriod string                   `json:"max_processing_period" yaml:"max_processing_period"`
 	FetchBufferCap      int                      `json:"fetch_buffer_cap" yaml:"fetch_buffer_cap"`
 	StartFromOldest     bool                     `json:"start_from_oldest" yaml:"start_from_oldest"`
+	StartFromTimestamp  string                   `json:"start_from_timestamp" yaml:"start_from_timestamp"`
 	TargetVersion       string                   `json:"target_version" yaml:"target_version"`
 	TLS                 btls.Config              `json:"tls" yaml:"tls"`
 	SASL                sasl.Config              `json:"sasl" yaml:"sasl"`
@@ -61,14 +66,19 @@ func NewKafkaConfig() KafkaConfig {
 		Topics:              []string{},
 		ClientID:            "benthos_kafka_input",
 		ConsumerGroup:       "benthos_consumer_group",
+		GroupInstanceID:     "",
 		Group:               NewKafkaBalancedGroupConfig(),
 		CommitPeriod:        "1s",
+		OffsetsFile:         "",
+		ShardIndex:          0,
+		ShardCount:          0,
 		CheckpointLimit:     1,
 		MaxProcessingPeriod: "100ms",
 		FetchBufferCap:      256,
 		Topic:               "benthos_stream",
 		Partition:           0,
 		StartFromOldest:     true,
+		StartFromTimestamp:  "",
 		TargetVersion:       sarama.V1_0_0_0.String(),
 		MaxBatchCount:       1,
 		TLS:                 btls.NewConfig(),
@@ -438,7 +448,7 @@ func (k *Kafka) commit() error {
 
 	commitReq := sarama.OffsetCommitRequest{}
 	commitReq.ConsumerGroup = k.conf.ConsumerGroup
-	commitReq.AddBlock(k.conf.Topic, k.conf.Partition, k.offset, 0, "")
+	commitReq.AddBlock(k.conf.Topic, k.conf.Partition, k.offset, 0, 0, "")
 
 	commitRes, err := coordinator.CommitOffset(&commitReq)
 	if err == nil {
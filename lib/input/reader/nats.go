@@ -162,7 +162,11 @@ func (n *NATS) ReadWithContext(ctx context.Context) (types.Message, AsyncAckFn,
 	}
 
 	bmsg := message.New([][]byte{msg.Data})
-	bmsg.Get(0).Metadata().Set("nats_subject", msg.Subject)
+	part := bmsg.Get(0)
+	part.Metadata().Set("nats_subject", msg.Subject)
+	for k := range msg.Header {
+		part.Metadata().Set(k, msg.Header.Get(k))
+	}
 
 	return bmsg, func(ctx context.Context, res types.Response) error {
 		if res.Error() != nil {
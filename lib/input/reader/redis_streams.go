@@ -308,6 +308,7 @@ func (r *RedisStreams) read() (pendingRedisStreamMsg, error) {
 
 			part := message.NewPart(bodyBytes)
 			part.Metadata().Set("redis_stream", xmsg.ID)
+			part.Metadata().Set("redis_stream_name", strRes.Stream)
 			for k, v := range xmsg.Values {
 				part.Metadata().Set(k, fmt.Sprintf("%v", v))
 			}
@@ -69,17 +69,18 @@ func (p *SyncBatcher) Read() (types.Message, error) {
 
 // ReadNextWithContext attempts to read a new message from the source.
 func (p *SyncBatcher) ReadNextWithContext(ctx context.Context) (types.Message, error) {
-	var forcedBatchDeadline time.Time
-	if tout := p.batcher.UntilNext(); tout >= 0 {
-		forcedBatchDeadline = time.Now().Add(tout)
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithDeadline(ctx, forcedBatchDeadline)
-		defer cancel()
-	}
-
 	flushBatch := false
 	for !flushBatch {
-		msg, err := p.r.ReadNextWithContext(ctx)
+		// The deadline is recalculated for each read as an idle period is
+		// extended each time a message is added to the batch.
+		readCtx, cancel := ctx, func() {}
+		var forcedBatchDeadline time.Time
+		if tout := p.batcher.UntilNext(); tout >= 0 {
+			forcedBatchDeadline = time.Now().Add(tout)
+			readCtx, cancel = context.WithDeadline(ctx, forcedBatchDeadline)
+		}
+		msg, err := p.r.ReadNextWithContext(readCtx)
+		cancel()
 		if err != nil {
 			if !forcedBatchDeadline.IsZero() && !time.Now().Before(forcedBatchDeadline) {
 				if batch := p.batcher.Flush(); batch != nil && batch.Len() > 0 {
@@ -91,13 +92,11 @@ func (p *SyncBatcher) ReadNextWithContext(ctx context.Context) (types.Message, e
 				// was cancelled, in which case we want to return right now, or
 				// that the underlying mechanism timed out, in which case we
 				// simply want to try again.
-				select {
-				case <-ctx.Done():
+				if ctx.Err() != nil || (!forcedBatchDeadline.IsZero() && !time.Now().Before(forcedBatchDeadline)) {
 					if batch := p.batcher.Flush(); batch != nil && batch.Len() > 0 {
 						return batch, nil
 					}
 					return nil, types.ErrTimeout
-				default:
 				}
 				continue
 			}
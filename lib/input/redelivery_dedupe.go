@@ -0,0 +1,329 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/batch"
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// sourceIdentityKeys lists the sets of metadata keys that inputs with
+// at-least-once delivery guarantees use to identify messages within their
+// source. The first set where all keys are present on a message is used as its
+// identity.
+var sourceIdentityKeys = [][]string{
+	{"kafka_topic", "kafka_partition", "kafka_offset"},
+	{"sqs_message_id"},
+	{"kinesis_shard", "kinesis_sequence_number"},
+	{"nats_stream_subject", "nats_stream_sequence"},
+	{"redis_stream_name", "redis_stream"},
+}
+
+// sourceIdentity returns a string that identifies a message within its source,
+// or an empty string if the message has no known identity.
+func sourceIdentity(p types.Part) string {
+	meta := p.Metadata()
+	for _, keys := range sourceIdentityKeys {
+		ids := make([]string, 0, len(keys))
+		for _, k := range keys {
+			v := meta.Get(k)
+			if v == "" {
+				break
+			}
+			ids = append(ids, k+"="+v)
+		}
+		if len(ids) == len(keys) {
+			return strings.Join(ids, ",")
+		}
+	}
+	return ""
+}
+
+//------------------------------------------------------------------------------
+
+// appendRedeliveryDedupeFromConfig takes a variant arg of pipeline constructor
+// functions and, when the provided input configuration references a cache
+// resource for deduplicating redeliveries, returns a new slice where a pipeline
+// dropping previously processed messages precedes all others.
+func appendRedeliveryDedupeFromConfig(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	pipelines ...types.PipelineConstructorFunc,
+) []types.PipelineConstructorFunc {
+	if conf.RedeliveryCache == "" {
+		return pipelines
+	}
+	return append([]types.PipelineConstructorFunc{func(i *int) (types.Pipeline, error) {
+		ttl, err := time.ParseDuration(conf.RedeliveryTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse redelivery_ttl: %v", err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("redelivery_ttl must be greater than zero, got %v", conf.RedeliveryTTL)
+		}
+		return newRedeliveryDedupePipeline(conf.RedeliveryCache, ttl, mgr, log, stats)
+	}}, pipelines...)
+}
+
+//------------------------------------------------------------------------------
+
+// redeliveryDedupePipeline is a types.Pipeline that drops messages that have
+// already been processed according to their identity within the source, and
+// records the identity of messages once their transaction is acknowledged.
+// Messages without a known source identity are always forwarded, and the
+// identities of messages are only remembered for a TTL.
+type redeliveryDedupePipeline struct {
+	cacheName string
+	ttl       time.Duration
+	mgr       types.Manager
+	log       log.Modular
+
+	mDropped metrics.StatCounter
+	mErr     metrics.StatCounter
+
+	transactionsOut chan types.Transaction
+
+	pendingAcks sync.WaitGroup
+	closeChan   chan struct{}
+	closedChan  chan struct{}
+	closeOnce   sync.Once
+}
+
+func newRedeliveryDedupePipeline(
+	cacheName string, ttl time.Duration, mgr types.Manager, log log.Modular, stats metrics.Type,
+) (*redeliveryDedupePipeline, error) {
+	if err := interop.ProbeCache(context.Background(), mgr, cacheName); err != nil {
+		return nil, err
+	}
+	return &redeliveryDedupePipeline{
+		cacheName:       cacheName,
+		ttl:             ttl,
+		mgr:             mgr,
+		log:             log,
+		mDropped:        stats.GetCounter("redelivery.dropped"),
+		mErr:            stats.GetCounter("redelivery.error"),
+		transactionsOut: make(chan types.Transaction),
+		closeChan:       make(chan struct{}),
+		closedChan:      make(chan struct{}),
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// filter returns a message containing only the parts of a batch that haven't
+// been processed before, along with the index of each of those parts within
+// the original batch and their identities, which are empty for parts without
+// a known identity.
+func (r *redeliveryDedupePipeline) filter(msg types.Message) (types.Message, []int, []string) {
+	ids := make([]string, msg.Len())
+	for i := range ids {
+		ids[i] = sourceIdentity(msg.Get(i))
+	}
+
+	seen := make([]bool, len(ids))
+	if err := interop.AccessCache(context.Background(), r.mgr, r.cacheName, func(c types.Cache) {
+		for i, id := range ids {
+			if id == "" {
+				continue
+			}
+			_, cerr := c.Get(id)
+			if cerr == nil {
+				seen[i] = true
+			} else if cerr != types.ErrKeyNotFound {
+				r.mErr.Incr(1)
+				r.log.Errorf("Failed to check message identity '%v': %v\n", id, cerr)
+			}
+		}
+	}); err != nil {
+		r.mErr.Incr(1)
+		r.log.Errorf("Failed to access cache: %v\n", err)
+	}
+
+	filtered := message.New(nil)
+	var indexes []int
+	var filteredIDs []string
+	msg.Iter(func(i int, p types.Part) error {
+		if seen[i] {
+			r.mDropped.Incr(1)
+			return nil
+		}
+		filtered.Append(p)
+		indexes = append(indexes, i)
+		filteredIDs = append(filteredIDs, ids[i])
+		return nil
+	})
+	if filtered.Len() == msg.Len() {
+		return msg, indexes, filteredIDs
+	}
+	return filtered, indexes, filteredIDs
+}
+
+// record stores the identities of processed messages for the TTL of the
+// pipeline. Caches that do not support per key TTLs fall back to their own TTL.
+func (r *redeliveryDedupePipeline) record(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	if err := interop.AccessCache(context.Background(), r.mgr, r.cacheName, func(c types.Cache) {
+		ttlc, supportsTTL := c.(types.CacheWithTTL)
+		for _, id := range ids {
+			var cerr error
+			if supportsTTL {
+				ttl := r.ttl
+				cerr = ttlc.SetWithTTL(id, []byte("t"), &ttl)
+			} else {
+				cerr = c.Set(id, []byte("t"))
+			}
+			if cerr != nil {
+				r.mErr.Incr(1)
+				r.log.Errorf("Failed to record message identity '%v': %v\n", id, cerr)
+			}
+		}
+	}); err != nil {
+		r.mErr.Incr(1)
+		r.log.Errorf("Failed to access cache: %v\n", err)
+	}
+}
+
+// resolve returns the identities of the filtered parts of a batch that were
+// delivered successfully according to a response, along with a response for
+// the original batch. Batch errors that are indexed against the filtered parts
+// are mapped back to the indexes of the original batch, where parts that were
+// dropped as redeliveries are successful.
+func (r *redeliveryDedupePipeline) resolve(original types.Message, indexes []int, ids []string, res types.Response) ([]string, types.Response) {
+	err := res.Error()
+	if err == nil {
+		return nonEmpty(ids), res
+	}
+
+	walkable, ok := err.(batch.WalkableError)
+	if !ok || walkable.IndexedErrors() == 0 {
+		return nil, res
+	}
+
+	var delivered []string
+	bErr := batch.NewError(original, err)
+	walkable.WalkParts(func(i int, _ types.Part, pErr error) bool {
+		if i >= len(indexes) {
+			return false
+		}
+		if pErr != nil {
+			bErr.Failed(indexes[i], pErr)
+		} else if ids[i] != "" {
+			delivered = append(delivered, ids[i])
+		}
+		return true
+	})
+	return delivered, response.NewError(bErr)
+}
+
+func nonEmpty(strs []string) []string {
+	var res []string
+	for _, s := range strs {
+		if s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func (r *redeliveryDedupePipeline) loop(tChan <-chan types.Transaction) {
+	defer func() {
+		r.pendingAcks.Wait()
+		close(r.transactionsOut)
+		close(r.closedChan)
+	}()
+
+	for {
+		var tran types.Transaction
+		var open bool
+		select {
+		case tran, open = <-tChan:
+			if !open {
+				return
+			}
+		case <-r.closeChan:
+			return
+		}
+
+		msg, indexes, ids := r.filter(tran.Payload)
+		if msg.Len() == 0 {
+			select {
+			case tran.ResponseChan <- response.NewAck():
+			case <-r.closeChan:
+				return
+			}
+			continue
+		}
+
+		resChan := make(chan types.Response)
+		select {
+		case r.transactionsOut <- types.NewTransaction(msg, resChan):
+		case <-r.closeChan:
+			return
+		}
+
+		r.pendingAcks.Add(1)
+		go func(original types.Message, indexes []int, ids []string, resChanUpstream chan<- types.Response) {
+			defer r.pendingAcks.Done()
+
+			var res types.Response
+			select {
+			case res = <-resChan:
+			case <-r.closeChan:
+				return
+			}
+
+			var delivered []string
+			delivered, res = r.resolve(original, indexes, ids, res)
+			r.record(delivered)
+
+			select {
+			case resChanUpstream <- res:
+			case <-r.closeChan:
+			}
+		}(tran.Payload, indexes, ids, tran.ResponseChan)
+	}
+}
+
+// Consume assigns a messages channel for the pipeline to read.
+func (r *redeliveryDedupePipeline) Consume(msgs <-chan types.Transaction) error {
+	go r.loop(msgs)
+	return nil
+}
+
+// TransactionChan returns the channel used for consuming messages from this
+// pipeline.
+func (r *redeliveryDedupePipeline) TransactionChan() <-chan types.Transaction {
+	return r.transactionsOut
+}
+
+// CloseAsync shuts down the pipeline and stops processing messages.
+func (r *redeliveryDedupePipeline) CloseAsync() {
+	r.closeOnce.Do(func() {
+		close(r.closeChan)
+	})
+}
+
+// WaitForClose blocks until the pipeline has closed down.
+func (r *redeliveryDedupePipeline) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-r.closedChan:
+	case <-time.After(timeout):
+		return types.ErrTimeout
+	}
+	return nil
+}
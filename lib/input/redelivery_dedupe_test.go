@@ -0,0 +1,270 @@
+package input
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/batch"
+	"github.com/Jeffail/benthos/v3/lib/cache"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     map[string]string
+		expected string
+	}{
+		{
+			name: "kafka",
+			meta: map[string]string{
+				"kafka_topic":     "foo",
+				"kafka_partition": "1",
+				"kafka_offset":    "23",
+				"kafka_key":       "bar",
+			},
+			expected: "kafka_topic=foo,kafka_partition=1,kafka_offset=23",
+		},
+		{
+			name:     "sqs",
+			meta:     map[string]string{"sqs_message_id": "abc"},
+			expected: "sqs_message_id=abc",
+		},
+		{
+			name: "redis streams",
+			meta: map[string]string{
+				"redis_stream":      "1-0",
+				"redis_stream_name": "foo",
+			},
+			expected: "redis_stream_name=foo,redis_stream=1-0",
+		},
+		{
+			name:     "amqp is not unique",
+			meta:     map[string]string{"amqp_message_id": "abc"},
+			expected: "",
+		},
+		{
+			name: "partial kafka",
+			meta: map[string]string{
+				"kafka_topic":  "foo",
+				"kafka_offset": "23",
+			},
+			expected: "",
+		},
+		{
+			name:     "unknown",
+			meta:     map[string]string{"foo": "bar"},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			p := message.NewPart(nil)
+			for k, v := range test.meta {
+				p.Metadata().Set(k, v)
+			}
+			assert.Equal(t, test.expected, sourceIdentity(p))
+		})
+	}
+}
+
+func kafkaTestMsg(offsets ...string) types.Message {
+	msg := message.New(nil)
+	for _, o := range offsets {
+		p := message.NewPart([]byte("offset " + o))
+		p.Metadata().Set("kafka_topic", "foo")
+		p.Metadata().Set("kafka_partition", "0")
+		p.Metadata().Set("kafka_offset", o)
+		msg.Append(p)
+	}
+	return msg
+}
+
+func TestInputRedeliveryDedupe(t *testing.T) {
+	cConf := cache.NewConfig()
+	c, err := cache.NewMemory(cConf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := &fakeProcMgr{
+		cs: map[string]types.Cache{"foo": c},
+	}
+
+	in := &fakeInput{ts: make(chan types.Transaction)}
+
+	conf := NewConfig()
+	conf.RedeliveryCache = "foo"
+
+	dIn, err := WrapWithPipelines(in, AppendProcessorsFromConfig(conf, mgr, log.Noop(), metrics.Noop())...)
+	require.NoError(t, err)
+
+	sendAndAck := func(msg types.Message, resErr error) ([]string, error) {
+		t.Helper()
+
+		resChan := make(chan types.Response)
+		select {
+		case in.ts <- types.NewTransaction(msg, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+
+		var contents []string
+		for {
+			select {
+			case tran := <-dIn.TransactionChan():
+				_ = tran.Payload.Iter(func(i int, p types.Part) error {
+					contents = append(contents, string(p.Get()))
+					return nil
+				})
+				go func() {
+					if resErr != nil {
+						tran.ResponseChan <- response.NewError(resErr)
+					} else {
+						tran.ResponseChan <- response.NewAck()
+					}
+				}()
+			case res := <-resChan:
+				return contents, res.Error()
+			case <-time.After(time.Second):
+				t.Fatal("timed out")
+			}
+		}
+	}
+
+	contents, err := sendAndAck(kafkaTestMsg("1", "2"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"offset 1", "offset 2"}, contents)
+
+	// A failed transaction must not be recorded
+	contents, err = sendAndAck(kafkaTestMsg("3"), errors.New("nope"))
+	require.EqualError(t, err, "nope")
+	assert.Equal(t, []string{"offset 3"}, contents)
+
+	contents, err = sendAndAck(kafkaTestMsg("2", "3"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"offset 3"}, contents)
+
+	contents, err = sendAndAck(kafkaTestMsg("1", "2", "3"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, contents)
+
+	contents, err = sendAndAck(message.New([][]byte{[]byte("no identity")}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no identity"}, contents)
+
+	contents, err = sendAndAck(message.New([][]byte{[]byte("no identity")}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no identity"}, contents)
+
+	close(in.ts)
+	dIn.CloseAsync()
+	require.NoError(t, dIn.WaitForClose(time.Second))
+}
+
+func TestInputRedeliveryDedupeNotFound(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = TypeResource
+	conf.Resource = "foo"
+	conf.RedeliveryCache = "bar"
+
+	mgr := &fakeProcMgr{
+		ins: map[string]types.Input{
+			"foo": &fakeInput{ts: make(chan types.Transaction)},
+		},
+	}
+
+	_, err := New(conf, mgr, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "cache resource 'bar' was not found")
+}
+
+func TestInputRedeliveryDedupePartialFailure(t *testing.T) {
+	cConf := cache.NewConfig()
+	c, err := cache.NewMemory(cConf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, c.Set("kafka_topic=foo,kafka_partition=0,kafka_offset=1", []byte("t")))
+
+	mgr := &fakeProcMgr{
+		cs: map[string]types.Cache{"foo": c},
+	}
+
+	in := &fakeInput{ts: make(chan types.Transaction)}
+
+	conf := NewConfig()
+	conf.RedeliveryCache = "foo"
+
+	dIn, err := WrapWithPipelines(in, AppendProcessorsFromConfig(conf, mgr, log.Noop(), metrics.Noop())...)
+	require.NoError(t, err)
+
+	resChan := make(chan types.Response)
+	select {
+	case in.ts <- types.NewTransaction(kafkaTestMsg("1", "2", "3"), resChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	var tran types.Transaction
+	select {
+	case tran = <-dIn.TransactionChan():
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	require.Equal(t, 2, tran.Payload.Len())
+
+	// The second part of the filtered batch is the third part of the original
+	go func() {
+		tran.ResponseChan <- response.NewError(batch.NewError(tran.Payload, errors.New("nope")).Failed(1, errors.New("nope")))
+	}()
+
+	var res types.Response
+	select {
+	case res = <-resChan:
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	walkable, ok := res.Error().(batch.WalkableError)
+	require.True(t, ok)
+
+	var failed []string
+	walkable.WalkParts(func(i int, p types.Part, err error) bool {
+		if err != nil {
+			failed = append(failed, string(p.Get()))
+		}
+		return true
+	})
+	assert.Equal(t, []string{"offset 3"}, failed)
+
+	_, err = c.Get("kafka_topic=foo,kafka_partition=0,kafka_offset=2")
+	assert.NoError(t, err)
+	_, err = c.Get("kafka_topic=foo,kafka_partition=0,kafka_offset=3")
+	assert.Equal(t, types.ErrKeyNotFound, err)
+
+	close(in.ts)
+	dIn.CloseAsync()
+	require.NoError(t, dIn.WaitForClose(time.Second))
+}
+
+func TestInputRedeliveryDedupeBadTTL(t *testing.T) {
+	cConf := cache.NewConfig()
+	c, err := cache.NewMemory(cConf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := &fakeProcMgr{
+		cs: map[string]types.Cache{"foo": c},
+	}
+
+	conf := NewConfig()
+	conf.RedeliveryCache = "foo"
+	conf.RedeliveryTTL = "nope"
+
+	_, err = WrapWithPipelines(&fakeInput{ts: make(chan types.Transaction)}, AppendProcessorsFromConfig(conf, mgr, log.Noop(), metrics.Noop())...)
+	require.EqualError(t, err, "failed to parse redelivery_ttl: time: invalid duration \"nope\"")
+}
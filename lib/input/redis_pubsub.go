@@ -27,7 +27,34 @@ patterns in your channel names. For example:
 - ` + "`h[ae]llo`" + ` subscribes to hello and hallo, but not hillo
 
 Use ` + "`\\`" + ` to escape special characters if you want to match them
-verbatim.`,
+verbatim.
+
+Subscriptions to all channels and patterns are restored automatically when the
+connection to Redis is lost and then re-established.
+
+### Delivery Guarantees
+
+Redis publish/subscribe is fire-and-forget, messages are only delivered to
+subscribers connected at the time they are published and are never
+acknowledged. Therefore this input offers at-most-once delivery, messages
+published while Benthos is disconnected or shutting down, and messages still in
+flight within Benthos when it crashes, are lost.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+` + "``` text" + `
+- redis_pubsub_channel
+- redis_pubsub_pattern (only when use_patterns is true)
+` + "```" + `
+
+The field ` + "`redis_pubsub_channel`" + ` contains the channel the message
+was published to, and ` + "`redis_pubsub_pattern`" + ` contains the pattern that
+the channel matched.
+
+You can access these metadata fields using
+[function interpolation](/docs/configuration/interpolation#metadata).`,
 		FieldSpecs: redis.ConfigDocs().Add(
 			docs.FieldCommon("channels", "A list of channels to consume from.").Array(),
 			docs.FieldCommon("use_patterns", "Whether to use the PSUBSCRIBE command."),
@@ -21,7 +21,23 @@ Pulls messages from Redis (v5.0+) streams with the XREADGROUP command. The
 		Description: `
 Redis stream entries are key/value pairs, as such it is necessary to specify the
 key that contains the body of the message. All other keys/value pairs are saved
-as metadata fields.`,
+as metadata fields.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+` + "``` text" + `
+- redis_stream
+- redis_stream_name
+- All other key/value pairs of the entry
+` + "```" + `
+
+The field ` + "`redis_stream`" + ` contains the ID of the entry and
+` + "`redis_stream_name`" + ` contains the name of the stream it was read from.
+
+You can access these metadata fields using
+[function interpolation](/docs/configuration/interpolation#metadata).`,
 		FieldSpecs: redis.ConfigDocs().Add(
 			func() docs.FieldSpec {
 				b := batch.FieldSpec()
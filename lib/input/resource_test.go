@@ -36,11 +36,16 @@ func (f *fakeInput) WaitForClose(time.Duration) error {
 
 type fakeProcMgr struct {
 	ins map[string]types.Input
+	rls map[string]types.RateLimit
+	cs  map[string]types.Cache
 }
 
 func (f *fakeProcMgr) RegisterEndpoint(path, desc string, h http.HandlerFunc) {
 }
 func (f *fakeProcMgr) GetCache(name string) (types.Cache, error) {
+	if c, exists := f.cs[name]; exists {
+		return c, nil
+	}
 	return nil, types.ErrCacheNotFound
 }
 func (f *fakeProcMgr) GetCondition(name string) (types.Condition, error) {
@@ -56,6 +61,9 @@ func (f *fakeProcMgr) GetInput(name string) (types.Input, error) {
 	return nil, types.ErrInputNotFound
 }
 func (f *fakeProcMgr) GetRateLimit(name string) (types.RateLimit, error) {
+	if r, exists := f.rls[name]; exists {
+		return r, nil
+	}
 	return nil, types.ErrRateLimitNotFound
 }
 func (f *fakeProcMgr) GetPlugin(name string) (interface{}, error) {
@@ -0,0 +1,31 @@
+package input
+
+import (
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/pipeline"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// appendSizeLimitFromConfig takes a variant arg of pipeline constructor
+// functions and, when the provided input configuration sets a maximum message
+// size, returns a new slice where a pipeline rejecting batches containing
+// oversized messages precedes all others.
+func appendSizeLimitFromConfig(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	pipelines ...types.PipelineConstructorFunc,
+) []types.PipelineConstructorFunc {
+	if conf.MaxMessageBytes <= 0 {
+		return pipelines
+	}
+	return append([]types.PipelineConstructorFunc{func(i *int) (types.Pipeline, error) {
+		return pipeline.NewSizeLimit(conf.MaxMessageBytes, log, stats), nil
+	}}, pipelines...)
+}
+
+//------------------------------------------------------------------------------
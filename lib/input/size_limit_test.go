@@ -0,0 +1,73 @@
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeLimit(t *testing.T) {
+	in := &fakeInput{ts: make(chan types.Transaction)}
+
+	conf := NewConfig()
+	conf.MaxMessageBytes = 5
+
+	stats := metrics.NewLocal()
+	sIn, err := WrapWithPipelines(in, AppendProcessorsFromConfig(conf, nil, log.Noop(), stats)...)
+	require.NoError(t, err)
+
+	resChan := make(chan types.Response)
+
+	// Batches within the limit are propagated
+	select {
+	case in.ts <- types.NewTransaction(message.New([][]byte{[]byte("hello"), []byte("foo")}), resChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	var tran types.Transaction
+	select {
+	case tran = <-sIn.TransactionChan():
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	assert.Equal(t, [][]byte{[]byte("hello"), []byte("foo")}, message.GetAllBytes(tran.Payload))
+
+	go func() {
+		tran.ResponseChan <- response.NewAck()
+	}()
+	select {
+	case res := <-resChan:
+		assert.NoError(t, res.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	// Batches containing an oversized message are rejected
+	select {
+	case in.ts <- types.NewTransaction(message.New([][]byte{[]byte("foo"), []byte("hello world")}), resChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	select {
+	case res := <-resChan:
+		assert.EqualError(t, res.Error(), "message size 11 exceeds the limit of 5 bytes")
+	case tran = <-sIn.TransactionChan():
+		t.Fatalf("unexpected transaction: %s", message.GetAllBytes(tran.Payload))
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	assert.Equal(t, int64(1), stats.GetCounters()["message_size.rejected"])
+
+	sIn.CloseAsync()
+	close(in.ts)
+	require.NoError(t, sIn.WaitForClose(time.Second))
+}
@@ -0,0 +1,169 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/pipeline"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// ValidationConfig contains configuration fields for validating messages
+// consumed by an input before they are processed.
+type ValidationConfig struct {
+	Schema           string `json:"schema" yaml:"schema"`
+	SchemaPath       string `json:"schema_path" yaml:"schema_path"`
+	QuarantineOutput string `json:"quarantine_output" yaml:"quarantine_output"`
+}
+
+// NewValidationConfig returns a ValidationConfig with default values.
+func NewValidationConfig() ValidationConfig {
+	return ValidationConfig{
+		Schema:           "",
+		SchemaPath:       "",
+		QuarantineOutput: "",
+	}
+}
+
+// appendValidationFromConfig takes a variant arg of pipeline constructor
+// functions and, when the provided input configuration sets a schema to
+// validate messages against, returns a new slice where a pipeline validating
+// messages precedes all others.
+func appendValidationFromConfig(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	pipelines ...types.PipelineConstructorFunc,
+) []types.PipelineConstructorFunc {
+	if conf.Validation.Schema == "" && conf.Validation.SchemaPath == "" {
+		return pipelines
+	}
+	return append([]types.PipelineConstructorFunc{func(i *int) (types.Pipeline, error) {
+		v, err := newValidationProcessor(conf.Validation, mgr, log, stats)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create validation: %w", err)
+		}
+		return pipeline.NewProcessor(log, stats, v), nil
+	}}, pipelines...)
+}
+
+//------------------------------------------------------------------------------
+
+// validationProcessor checks messages against a JSON schema and, when a
+// quarantine output is configured, removes invalid messages from their batch
+// and writes them to that output.
+type validationProcessor struct {
+	quarantineOutput string
+	schema           types.Processor
+	mgr              types.Manager
+	log              log.Modular
+
+	mQuarantined metrics.StatCounter
+	mErr         metrics.StatCounter
+}
+
+func newValidationProcessor(
+	conf ValidationConfig, mgr types.Manager, log log.Modular, stats metrics.Type,
+) (*validationProcessor, error) {
+	schemaConf := processor.NewConfig()
+	schemaConf.Type = processor.TypeJSONSchema
+	schemaConf.JSONSchema.Schema = conf.Schema
+	schemaConf.JSONSchema.SchemaPath = conf.SchemaPath
+
+	schema, err := processor.New(schemaConf, mgr, log, metrics.Noop())
+	if err != nil {
+		return nil, err
+	}
+	if conf.QuarantineOutput != "" {
+		if err := interop.ProbeOutput(context.Background(), mgr, conf.QuarantineOutput); err != nil {
+			return nil, err
+		}
+	}
+	return &validationProcessor{
+		quarantineOutput: conf.QuarantineOutput,
+		schema:           schema,
+		mgr:              mgr,
+		log:              log,
+		mQuarantined:     stats.GetCounter("validation.quarantined"),
+		mErr:             stats.GetCounter("validation.error"),
+	}, nil
+}
+
+func (v *validationProcessor) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	if msg.Len() == 0 {
+		return nil, response.NewAck()
+	}
+
+	// The schema processor does not modify payloads, but messages are flagged
+	// on a copy in order to keep the original messages untouched.
+	results, _ := v.schema.ProcessMessage(msg.Copy())
+	if len(results) != 1 {
+		return []types.Message{msg}, nil
+	}
+	checked := results[0]
+
+	if v.quarantineOutput == "" {
+		return []types.Message{checked}, nil
+	}
+
+	valid, invalid := message.New(nil), message.New(nil)
+	_ = checked.Iter(func(i int, p types.Part) error {
+		if !processor.HasFailed(p) {
+			valid.Append(msg.Get(i))
+			return nil
+		}
+		qPart := msg.Get(i).Copy()
+		qPart.Metadata().Set("validation_errors", processor.GetFail(p))
+		invalid.Append(qPart)
+		return nil
+	})
+	if invalid.Len() == 0 {
+		return []types.Message{msg}, nil
+	}
+
+	if err := v.quarantine(invalid); err != nil {
+		v.mErr.Incr(1)
+		v.log.Errorf("Failed to write invalid messages to quarantine output: %v\n", err)
+		return nil, response.NewError(err)
+	}
+	v.mQuarantined.Incr(int64(invalid.Len()))
+
+	if valid.Len() == 0 {
+		return nil, response.NewAck()
+	}
+	return []types.Message{valid}, nil
+}
+
+// quarantine writes invalid messages to the quarantine output and blocks until
+// they are acknowledged.
+func (v *validationProcessor) quarantine(msg types.Message) error {
+	resChan := make(chan types.Response)
+	var err error
+	if oerr := interop.AccessOutput(context.Background(), v.mgr, v.quarantineOutput, func(o types.OutputWriter) {
+		err = o.WriteTransaction(context.Background(), types.NewTransaction(msg, resChan))
+	}); oerr != nil {
+		return oerr
+	}
+	if err != nil {
+		return err
+	}
+	return (<-resChan).Error()
+}
+
+func (v *validationProcessor) CloseAsync() {
+	v.schema.CloseAsync()
+}
+
+func (v *validationProcessor) WaitForClose(timeout time.Duration) error {
+	return v.schema.WaitForClose(timeout)
+}
@@ -0,0 +1,165 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuarantineWriter struct {
+	msgs []types.Message
+	err  error
+}
+
+func (f *fakeQuarantineWriter) Connected() bool {
+	return true
+}
+
+func (f *fakeQuarantineWriter) WriteTransaction(ctx context.Context, ts types.Transaction) error {
+	f.msgs = append(f.msgs, ts.Payload)
+	go func() {
+		ts.ResponseChan <- response.NewError(f.err)
+	}()
+	return nil
+}
+
+func (f *fakeQuarantineWriter) CloseAsync() {
+}
+
+func (f *fakeQuarantineWriter) WaitForClose(time.Duration) error {
+	return nil
+}
+
+type fakeQuarantineMgr struct {
+	fakeProcMgr
+	outs map[string]types.OutputWriter
+}
+
+func (f *fakeQuarantineMgr) GetOutput(name string) (types.OutputWriter, error) {
+	if o, exists := f.outs[name]; exists {
+		return o, nil
+	}
+	return nil, types.ErrOutputNotFound
+}
+
+func TestInputValidationQuarantine(t *testing.T) {
+	out := &fakeQuarantineWriter{}
+	mgr := &fakeQuarantineMgr{
+		outs: map[string]types.OutputWriter{"foo": out},
+	}
+
+	in := &fakeInput{ts: make(chan types.Transaction)}
+
+	conf := NewConfig()
+	conf.Validation.Schema = `{"type":"object","properties":{"age":{"type":"integer","minimum":0}}}`
+	conf.Validation.QuarantineOutput = "foo"
+
+	procConf := processor.NewConfig()
+	procConf.Type = processor.TypeBloblang
+	procConf.Bloblang.Mapping = `root = "mutated"`
+	conf.Processors = append(conf.Processors, procConf)
+
+	vIn, err := WrapWithPipelines(in, AppendProcessorsFromConfig(conf, mgr, log.Noop(), metrics.Noop())...)
+	require.NoError(t, err)
+
+	send := func(msg types.Message) ([]string, error) {
+		t.Helper()
+
+		resChan := make(chan types.Response)
+		select {
+		case in.ts <- types.NewTransaction(msg, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+
+		var contents []string
+		for {
+			select {
+			case tran := <-vIn.TransactionChan():
+				_ = tran.Payload.Iter(func(i int, p types.Part) error {
+					contents = append(contents, string(p.Get()))
+					return nil
+				})
+				go func() {
+					tran.ResponseChan <- response.NewAck()
+				}()
+			case res := <-resChan:
+				return contents, res.Error()
+			case <-time.After(time.Second):
+				t.Fatal("timed out")
+			}
+		}
+	}
+
+	input := message.New([][]byte{
+		[]byte(`{"age":21}`),
+		[]byte(`{"age":-20}`),
+		[]byte(`not json`),
+	})
+	input.Get(1).Metadata().Set("foo", "bar")
+
+	contents, err := send(input)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mutated"}, contents)
+
+	require.Len(t, out.msgs, 1)
+	qMsg := out.msgs[0]
+	require.Equal(t, 2, qMsg.Len())
+	assert.Equal(t, `{"age":-20}`, string(qMsg.Get(0).Get()))
+	assert.Equal(t, "bar", qMsg.Get(0).Metadata().Get("foo"))
+	assert.Equal(t, "age must be greater than or equal to 0", qMsg.Get(0).Metadata().Get("validation_errors"))
+	assert.False(t, processor.HasFailed(qMsg.Get(0)))
+	assert.Equal(t, `not json`, string(qMsg.Get(1).Get()))
+	assert.NotEmpty(t, qMsg.Get(1).Metadata().Get("validation_errors"))
+
+	contents, err = send(message.New([][]byte{[]byte(`{"age":-1}`)}))
+	require.NoError(t, err)
+	assert.Empty(t, contents)
+	require.Len(t, out.msgs, 2)
+
+	out.err = errors.New("nope")
+	contents, err = send(message.New([][]byte{[]byte(`{"age":1}`), []byte(`{"age":-1}`)}))
+	require.EqualError(t, err, "nope")
+	assert.Empty(t, contents)
+
+	close(in.ts)
+	vIn.CloseAsync()
+	require.NoError(t, vIn.WaitForClose(time.Second))
+}
+
+func TestInputValidationFlagsWithoutQuarantine(t *testing.T) {
+	conf := NewValidationConfig()
+	conf.Schema = `{"type":"object","properties":{"age":{"type":"integer"}}}`
+
+	v, err := newValidationProcessor(conf, &fakeProcMgr{}, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msgs, res := v.ProcessMessage(message.New([][]byte{
+		[]byte(`{"age":21}`),
+		[]byte(`{"age":"old"}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	require.Equal(t, 2, msgs[0].Len())
+	assert.False(t, processor.HasFailed(msgs[0].Get(0)))
+	assert.True(t, processor.HasFailed(msgs[0].Get(1)))
+}
+
+func TestInputValidationOutputNotFound(t *testing.T) {
+	conf := NewValidationConfig()
+	conf.Schema = `{"type":"object"}`
+	conf.QuarantineOutput = "foo"
+
+	_, err := newValidationProcessor(conf, &fakeQuarantineMgr{}, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "output resource 'foo' was not found")
+}
@@ -15,6 +15,7 @@ func Spec() docs.FieldSpecs {
 		docs.FieldString("static_fields", "A map of key/value pairs to add to each structured log.").Map().HasDefault(map[string]string{
 			"@service": "benthos",
 		}),
+		docs.FieldFloat("debug_sample_rate", "The fraction of `DEBUG` and `TRACE` level logs to emit, between 0 and 1. In high throughput pipelines logging at these levels for each message can be expensive, and a lower rate emits a random sample of them instead. A rate of 1 emits all logs and a rate of 0 emits none of them.").HasDefault(1.0).Advanced().AtVersion("3.51.0"),
 		docs.FieldDeprecated("prefix"),
 		docs.FieldDeprecated("json_format"),
 	}
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -80,12 +82,13 @@ func logLevelToInt(level string) int {
 
 // Config holds configuration options for a logger object.
 type Config struct {
-	Prefix       string            `json:"prefix" yaml:"prefix"`
-	LogLevel     string            `json:"level" yaml:"level"`
-	Format       string            `json:"format" yaml:"format"`
-	AddTimeStamp bool              `json:"add_timestamp" yaml:"add_timestamp"`
-	JSONFormat   bool              `json:"json_format" yaml:"json_format"`
-	StaticFields map[string]string `json:"static_fields" yaml:"static_fields"`
+	Prefix          string            `json:"prefix" yaml:"prefix"`
+	LogLevel        string            `json:"level" yaml:"level"`
+	Format          string            `json:"format" yaml:"format"`
+	AddTimeStamp    bool              `json:"add_timestamp" yaml:"add_timestamp"`
+	JSONFormat      bool              `json:"json_format" yaml:"json_format"`
+	StaticFields    map[string]string `json:"static_fields" yaml:"static_fields"`
+	DebugSampleRate float64           `json:"debug_sample_rate" yaml:"debug_sample_rate"`
 }
 
 // NewConfig returns a config struct with the default values for each field.
@@ -99,6 +102,7 @@ func NewConfig() Config {
 		StaticFields: map[string]string{
 			"@service": "benthos",
 		},
+		DebugSampleRate: 1,
 	}
 }
 
@@ -179,6 +183,9 @@ type Logger struct {
 	addTimestamp bool
 	level        int
 	formatter    logFormatter
+
+	// Samples DEBUG and TRACE logs, nil means all of them are emitted.
+	debugSampler *debugSampler
 }
 
 // New creates and returns a new logger object.
@@ -203,6 +210,8 @@ func New(stream io.Writer, config Config) Modular {
 		format:       config.Format,
 		addTimestamp: config.AddTimeStamp,
 		level:        logLevelToInt(config.LogLevel),
+
+		debugSampler: newDebugSampler(config.DebugSampleRate),
 	}
 
 	logger.formatter, _ = getFormatter(config.Format, config.Prefix, config.AddTimeStamp, fields)
@@ -215,6 +224,9 @@ func New(stream io.Writer, config Config) Modular {
 // NewV2 returns a new logger from a config, or returns an error if the config
 // is invalid.
 func NewV2(stream io.Writer, config Config) (Modular, error) {
+	if config.DebugSampleRate < 0 || config.DebugSampleRate > 1 {
+		return nil, fmt.Errorf("debug sample rate must be between 0 and 1, got %v", config.DebugSampleRate)
+	}
 	if !config.JSONFormat {
 		config.Format = "deprecated"
 	}
@@ -234,6 +246,8 @@ func NewV2(stream io.Writer, config Config) (Modular, error) {
 		format:       config.Format,
 		addTimestamp: config.AddTimeStamp,
 		level:        logLevelToInt(config.LogLevel),
+
+		debugSampler: newDebugSampler(config.DebugSampleRate),
 	}
 
 	var err error
@@ -245,6 +259,46 @@ func NewV2(stream io.Writer, config Config) (Modular, error) {
 
 //------------------------------------------------------------------------------
 
+// debugSampler determines whether DEBUG and TRACE logs are emitted according
+// to a sample rate. Each sampler has its own source of randomness, which is
+// shared by the loggers derived from the same root logger.
+type debugSampler struct {
+	rate float64
+
+	mut  sync.Mutex
+	rand *rand.Rand
+}
+
+// newDebugSampler returns a sampler for a configured rate between 0 and 1,
+// where 0 emits no logs, or nil if the rate emits all logs.
+func newDebugSampler(rate float64) *debugSampler {
+	if rate >= 1 {
+		return nil
+	}
+	return &debugSampler{
+		rate: rate,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (d *debugSampler) sample() bool {
+	if d.rate <= 0 {
+		return false
+	}
+	d.mut.Lock()
+	v := d.rand.Float64()
+	d.mut.Unlock()
+	return v < d.rate
+}
+
+// debugSampled returns whether a DEBUG or TRACE log should be emitted according
+// to the configured sample rate.
+func (l *Logger) debugSampled() bool {
+	return l.debugSampler == nil || l.debugSampler.sample()
+}
+
+//------------------------------------------------------------------------------
+
 // Noop creates and returns a new logger object that writes nothing.
 func Noop() Modular {
 	return &Logger{
@@ -288,6 +342,8 @@ func (l *Logger) NewModule(name string) Modular {
 		format:       l.format,
 		addTimestamp: l.addTimestamp,
 		formatter:    formatter,
+
+		debugSampler: l.debugSampler,
 	}
 }
 
@@ -315,6 +371,8 @@ func (l *Logger) WithFields(inboundFields map[string]string) Modular {
 		format:       l.format,
 		addTimestamp: l.addTimestamp,
 		formatter:    formatter,
+
+		debugSampler: l.debugSampler,
 	}
 }
 
@@ -351,6 +409,8 @@ func (l *Logger) With(args ...interface{}) Modular {
 		format:       l.format,
 		addTimestamp: l.addTimestamp,
 		formatter:    formatter,
+
+		debugSampler: l.debugSampler,
 	}
 }
 
@@ -497,14 +557,14 @@ func (l *Logger) Infof(format string, v ...interface{}) {
 
 // Debugf prints a debug message to the console.
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if LogDebug <= l.level {
+	if LogDebug <= l.level && l.debugSampled() {
 		l.write(format, "DEBUG", v...)
 	}
 }
 
 // Tracef prints a trace message to the console.
 func (l *Logger) Tracef(format string, v ...interface{}) {
-	if LogTrace <= l.level {
+	if LogTrace <= l.level && l.debugSampled() {
 		l.write(format, "TRACE", v...)
 	}
 }
@@ -541,14 +601,14 @@ func (l *Logger) Infoln(message string) {
 
 // Debugln prints a debug message to the console.
 func (l *Logger) Debugln(message string) {
-	if LogDebug <= l.level {
+	if LogDebug <= l.level && l.debugSampled() {
 		l.write(message, "DEBUG")
 	}
 }
 
 // Traceln prints a trace message to the console.
 func (l *Logger) Traceln(message string) {
-	if LogTrace <= l.level {
+	if LogTrace <= l.level && l.debugSampled() {
 		l.write(message, "TRACE")
 	}
 }
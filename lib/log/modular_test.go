@@ -276,3 +276,57 @@ func TestLogLevels(t *testing.T) {
 		}
 	}
 }
+
+func TestDebugSampleRate(t *testing.T) {
+	loggerConfig := NewConfig()
+	loggerConfig.AddTimeStamp = false
+	loggerConfig.Format = "logfmt"
+	loggerConfig.LogLevel = "TRACE"
+	loggerConfig.DebugSampleRate = 0.5
+
+	var buf bytes.Buffer
+
+	logger, err := NewV2(&buf, loggerConfig)
+	require.NoError(t, err)
+	logger = logger.NewModule(".foo")
+
+	for i := 0; i < 1000; i++ {
+		logger.Debugln("debug message")
+		logger.Traceln("trace message")
+		logger.Infoln("info message")
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	var debugLines, traceLines, infoLines int
+	for _, l := range lines {
+		switch {
+		case bytes.Contains(l, []byte("debug message")):
+			debugLines++
+		case bytes.Contains(l, []byte("trace message")):
+			traceLines++
+		case bytes.Contains(l, []byte("info message")):
+			infoLines++
+		}
+	}
+	assert.Equal(t, 1000, infoLines)
+	assert.Greater(t, debugLines, 300)
+	assert.Less(t, debugLines, 700)
+	assert.Greater(t, traceLines, 300)
+	assert.Less(t, traceLines, 700)
+
+	buf.Reset()
+	loggerConfig.DebugSampleRate = 0
+	logger, err = NewV2(&buf, loggerConfig)
+	require.NoError(t, err)
+
+	logger.Debugln("debug message")
+	logger.Traceln("trace message")
+	logger.Infoln("info message")
+	assert.NotContains(t, buf.String(), "debug message")
+	assert.NotContains(t, buf.String(), "trace message")
+	assert.Contains(t, buf.String(), "info message")
+
+	loggerConfig.DebugSampleRate = 2
+	_, err = NewV2(&buf, loggerConfig)
+	require.EqualError(t, err, "debug sample rate must be between 0 and 1, got 2")
+}
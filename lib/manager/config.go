@@ -6,6 +6,7 @@ import (
 
 	"github.com/Jeffail/benthos/v3/lib/cache"
 	"github.com/Jeffail/benthos/v3/lib/condition"
+	"github.com/Jeffail/benthos/v3/lib/credentials"
 	"github.com/Jeffail/benthos/v3/lib/input"
 	"github.com/Jeffail/benthos/v3/lib/output"
 	"github.com/Jeffail/benthos/v3/lib/processor"
@@ -17,29 +18,33 @@ import (
 // of a Benthos config.
 type ResourceConfig struct {
 	// Called manager for backwards compatibility.
-	Manager            Config             `json:"resources,omitempty" yaml:"resources,omitempty"`
-	ResourceInputs     []input.Config     `json:"input_resources,omitempty" yaml:"input_resources,omitempty"`
-	ResourceProcessors []processor.Config `json:"processor_resources,omitempty" yaml:"processor_resources,omitempty"`
-	ResourceOutputs    []output.Config    `json:"output_resources,omitempty" yaml:"output_resources,omitempty"`
-	ResourceCaches     []cache.Config     `json:"cache_resources,omitempty" yaml:"cache_resources,omitempty"`
-	ResourceRateLimits []ratelimit.Config `json:"rate_limit_resources,omitempty" yaml:"rate_limit_resources,omitempty"`
+	Manager             Config               `json:"resources,omitempty" yaml:"resources,omitempty"`
+	ResourceInputs      []input.Config       `json:"input_resources,omitempty" yaml:"input_resources,omitempty"`
+	ResourceProcessors  []processor.Config   `json:"processor_resources,omitempty" yaml:"processor_resources,omitempty"`
+	ResourceOutputs     []output.Config      `json:"output_resources,omitempty" yaml:"output_resources,omitempty"`
+	ResourceCaches      []cache.Config       `json:"cache_resources,omitempty" yaml:"cache_resources,omitempty"`
+	ResourceRateLimits  []ratelimit.Config   `json:"rate_limit_resources,omitempty" yaml:"rate_limit_resources,omitempty"`
+	ResourceCredentials []credentials.Config `json:"credential_resources,omitempty" yaml:"credential_resources,omitempty"`
 }
 
 // NewResourceConfig creates a ResourceConfig with default values.
 func NewResourceConfig() ResourceConfig {
 	return ResourceConfig{
-		Manager:            NewConfig(),
-		ResourceInputs:     []input.Config{},
-		ResourceProcessors: []processor.Config{},
-		ResourceOutputs:    []output.Config{},
-		ResourceCaches:     []cache.Config{},
-		ResourceRateLimits: []ratelimit.Config{},
+		Manager:             NewConfig(),
+		ResourceInputs:      []input.Config{},
+		ResourceProcessors:  []processor.Config{},
+		ResourceOutputs:     []output.Config{},
+		ResourceCaches:      []cache.Config{},
+		ResourceRateLimits:  []ratelimit.Config{},
+		ResourceCredentials: []credentials.Config{},
 	}
 }
 
-// Collapses all the slice based resources into maps, returning an error if any
-// labels are duplicated or empty.
-func (r *ResourceConfig) collapsed() (ResourceConfig, error) {
+// Collapsed returns a copy of the config where all the slice based resources
+// are collapsed into maps, returning an error if any labels are duplicated or
+// empty. Credentials resources have no map equivalent and are therefore kept as
+// a slice.
+func (r *ResourceConfig) Collapsed() (ResourceConfig, error) {
 	newMaps := NewConfig()
 
 	for k, v := range r.Manager.Caches {
@@ -115,8 +120,20 @@ func (r *ResourceConfig) collapsed() (ResourceConfig, error) {
 		newMaps.RateLimits[c.Label] = c
 	}
 
+	credLabels := map[string]struct{}{}
+	for _, c := range r.ResourceCredentials {
+		if c.Label == "" {
+			return *r, errors.New("credentials resource has an empty label")
+		}
+		if _, exists := credLabels[c.Label]; exists {
+			return *r, fmt.Errorf("credentials resource label '%v' collides with a previously defined resource", c.Label)
+		}
+		credLabels[c.Label] = struct{}{}
+	}
+
 	return ResourceConfig{
-		Manager: newMaps,
+		Manager:             newMaps,
+		ResourceCredentials: r.ResourceCredentials,
 	}, nil
 }
 
@@ -132,6 +149,7 @@ func (r *ResourceConfig) AddFrom(extra *ResourceConfig) error {
 	r.ResourceOutputs = append(r.ResourceOutputs, extra.ResourceOutputs...)
 	r.ResourceCaches = append(r.ResourceCaches, extra.ResourceCaches...)
 	r.ResourceRateLimits = append(r.ResourceRateLimits, extra.ResourceRateLimits...)
+	r.ResourceCredentials = append(r.ResourceCredentials, extra.ResourceCredentials...)
 	return nil
 }
 
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/credentials"
 	"github.com/Jeffail/gabs/v2"
 )
 
@@ -10,7 +11,7 @@ func lintResource(ctx docs.LintContext, line, col int, v interface{}) []docs.Lin
 	label, _ := gObj.S("label").Data().(string)
 	if label == "" {
 		return []docs.Lint{
-			docs.NewLintError(line, "The label field for resources must be unique and not empty"),
+			docs.NewTypedLintError(line, docs.LintBadLabel, "The label field for resources must be unique and not empty"),
 		}
 	}
 	return nil
@@ -58,5 +59,9 @@ func Spec() docs.FieldSpecs {
 		docs.FieldCommon(
 			"rate_limit_resources", "A list of rate limit resources, each must have a unique label.",
 		).Array().HasType(docs.FieldTypeRateLimit).Linter(lintResource),
+
+		docs.FieldAdvanced(
+			"credential_resources", "A list of credentials resources, each must have a unique label.",
+		).Array().WithChildren(credentials.Spec()...).Linter(lintResource).AtVersion("3.51.0"),
 	}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/buffer"
 	"github.com/Jeffail/benthos/v3/lib/cache"
 	"github.com/Jeffail/benthos/v3/lib/condition"
+	"github.com/Jeffail/benthos/v3/lib/credentials"
 	"github.com/Jeffail/benthos/v3/lib/input"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
@@ -55,6 +56,9 @@ type Type struct {
 	// if specified should be added as a label to logs and metrics.
 	component string
 
+	// Determines the characters permitted within component labels.
+	labelPolicy docs.LabelPolicy
+
 	apiReg APIReg
 
 	inputs       map[string]types.Input
@@ -62,6 +66,7 @@ type Type struct {
 	processors   map[string]types.Processor
 	outputs      map[string]types.OutputWriter
 	rateLimits   map[string]types.RateLimit
+	credentials  map[string]credentials.Resolver
 	plugins      map[string]interface{}
 	resourceLock *sync.RWMutex
 
@@ -100,6 +105,7 @@ func NewV2(conf ResourceConfig, apiReg APIReg, log log.Modular, stats metrics.Ty
 		processors:   map[string]types.Processor{},
 		outputs:      map[string]types.OutputWriter{},
 		rateLimits:   map[string]types.RateLimit{},
+		credentials:  map[string]credentials.Resolver{},
 		plugins:      map[string]interface{}{},
 		resourceLock: &sync.RWMutex{},
 
@@ -120,11 +126,22 @@ func NewV2(conf ResourceConfig, apiReg APIReg, log log.Modular, stats metrics.Ty
 		conditions: map[string]types.Condition{},
 	}
 
-	conf, err := conf.collapsed()
+	conf, err := conf.Collapsed()
 	if err != nil {
 		return nil, err
 	}
 
+	// Credentials are resolved by other resources when they connect, and are
+	// therefore created first.
+	for _, c := range conf.ResourceCredentials {
+		cMgr := t.forChildComponent("resource.credentials." + c.Label)
+		r, err := credentials.New(c, cMgr.Logger(), cMgr.Metrics())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create credentials resource '%v': %v", c.Label, err)
+		}
+		t.credentials[c.Label] = r
+	}
+
 	// Sometimes resources of a type might refer to other resources of the same
 	// type. When they are constructed they will check with the manager to
 	// ensure the resource they point to is valid, but not keep the reference.
@@ -340,6 +357,14 @@ func (t *Type) UnsetPipe(name string, tran <-chan types.Transaction) {
 
 //------------------------------------------------------------------------------
 
+// WithLabelPolicy returns a manager that validates component labels according
+// to a policy of permitted characters.
+func (t *Type) WithLabelPolicy(p docs.LabelPolicy) *Type {
+	newT := *t
+	newT.labelPolicy = p
+	return &newT
+}
+
 // WithMetricsMapping returns a manager with the stored metrics exporter wrapped
 // with a mapping.
 func (t *Type) WithMetricsMapping(m *imetrics.Mapping) *Type {
@@ -435,7 +460,7 @@ func (t *Type) NewCache(conf cache.Config) (types.Cache, error) {
 	mgr := t
 	// A configured label overrides any previously set component label.
 	if len(conf.Label) > 0 && t.component != conf.Label {
-		if err := docs.ValidateLabel(conf.Label); err != nil {
+		if err := docs.ValidateLabelWithPolicy(conf.Label, t.labelPolicy); err != nil {
 			return nil, err
 		}
 		mgr = t.forComponent(conf.Label)
@@ -501,7 +526,7 @@ func (t *Type) NewInput(conf input.Config, hasBatchProc bool, pipelines ...types
 	mgr := t
 	// A configured label overrides any previously set component label.
 	if len(conf.Label) > 0 && t.component != conf.Label {
-		if err := docs.ValidateLabel(conf.Label); err != nil {
+		if err := docs.ValidateLabelWithPolicy(conf.Label, t.labelPolicy); err != nil {
 			return nil, err
 		}
 		mgr = t.forComponent(conf.Label)
@@ -570,12 +595,21 @@ func (t *Type) NewProcessor(conf processor.Config) (types.Processor, error) {
 	mgr := t
 	// A configured label overrides any previously set component label.
 	if len(conf.Label) > 0 && t.component != conf.Label {
-		if err := docs.ValidateLabel(conf.Label); err != nil {
+		if err := docs.ValidateLabelWithPolicy(conf.Label, t.labelPolicy); err != nil {
 			return nil, err
 		}
 		mgr = t.forComponent(conf.Label)
 	}
-	return t.processorBundle.Init(conf, mgr)
+	p, err := t.processorBundle.Init(conf, mgr)
+	if err != nil {
+		return nil, err
+	}
+	p = processor.WithPanicRecovery(p, mgr.logger, mgr.stats)
+	return processor.WithErrorSource(processor.ErrorSource{
+		Label: conf.Label,
+		Path:  t.component,
+		Type:  conf.Type,
+	}, p), nil
 }
 
 // StoreProcessor attempts to store a new processor resource. If an existing
@@ -638,7 +672,7 @@ func (t *Type) NewOutput(conf output.Config, pipelines ...types.PipelineConstruc
 	mgr := t
 	// A configured label overrides any previously set component label.
 	if len(conf.Label) > 0 && t.component != conf.Label {
-		if err := docs.ValidateLabel(conf.Label); err != nil {
+		if err := docs.ValidateLabelWithPolicy(conf.Label, t.labelPolicy); err != nil {
 			return nil, err
 		}
 		mgr = t.forComponent(conf.Label)
@@ -705,12 +739,27 @@ func (t *Type) AccessRateLimit(ctx context.Context, name string, fn func(types.R
 	return nil
 }
 
+// AccessCredentials attempts to access a credentials resource by a unique
+// identifier and executes a closure function with the resolver as an argument.
+// Returns an error if the resource does not exist (or is otherwise
+// inaccessible).
+func (t *Type) AccessCredentials(ctx context.Context, name string, fn func(credentials.Resolver)) error {
+	t.resourceLock.RLock()
+	defer t.resourceLock.RUnlock()
+	r, ok := t.credentials[name]
+	if !ok {
+		return ErrResourceNotFound(name)
+	}
+	fn(r)
+	return nil
+}
+
 // NewRateLimit attempts to create a new rate limit component from a config.
 func (t *Type) NewRateLimit(conf ratelimit.Config) (types.RateLimit, error) {
 	mgr := t
 	// A configured label overrides any previously set component label.
 	if len(conf.Label) > 0 && t.component != conf.Label {
-		if err := docs.ValidateLabel(conf.Label); err != nil {
+		if err := docs.ValidateLabelWithPolicy(conf.Label, t.labelPolicy); err != nil {
 			return nil, err
 		}
 		mgr = t.forComponent(conf.Label)
@@ -749,6 +798,83 @@ func (t *Type) StoreRateLimit(ctx context.Context, name string, conf ratelimit.C
 
 //------------------------------------------------------------------------------
 
+// ReplaceResources attempts to initialize a collection of cache and rate limit
+// resources and swap them in place of any existing resources of the same
+// names, which are then closed.
+//
+// Unlike StoreCache and StoreRateLimit the swap is atomic: existing resources
+// are only replaced once every new resource has been initialized
+// successfully. If any of them fail then those already created are closed
+// and the existing resources are left untouched.
+func (t *Type) ReplaceResources(
+	ctx context.Context,
+	caches map[string]cache.Config,
+	rateLimits map[string]ratelimit.Config,
+) error {
+	newCaches := make(map[string]types.Cache, len(caches))
+	newRateLimits := make(map[string]types.RateLimit, len(rateLimits))
+
+	closeNew := func() {
+		for _, c := range newCaches {
+			c.CloseAsync()
+		}
+		for _, r := range newRateLimits {
+			r.CloseAsync()
+		}
+	}
+
+	for name, conf := range caches {
+		c, err := t.forComponent("resource.cache." + name).NewCache(conf)
+		if err != nil {
+			closeNew()
+			return fmt.Errorf(
+				"failed to create cache resource '%v' of type '%v': %w",
+				name, conf.Type, err,
+			)
+		}
+		newCaches[name] = c
+	}
+	for name, conf := range rateLimits {
+		r, err := t.forComponent("resource.rate_limit." + name).NewRateLimit(conf)
+		if err != nil {
+			closeNew()
+			return fmt.Errorf(
+				"failed to create rate limit resource '%v' of type '%v': %w",
+				name, conf.Type, err,
+			)
+		}
+		newRateLimits[name] = r
+	}
+
+	var replaced []types.Closable
+
+	t.resourceLock.Lock()
+	for name, c := range newCaches {
+		if prev := t.caches[name]; prev != nil {
+			replaced = append(replaced, prev)
+		}
+		t.caches[name] = c
+	}
+	for name, r := range newRateLimits {
+		if prev := t.rateLimits[name]; prev != nil {
+			replaced = append(replaced, prev)
+		}
+		t.rateLimits[name] = r
+	}
+	t.resourceLock.Unlock()
+
+	// Components only use resources whilst holding the read lock, and
+	// therefore the replaced resources are no longer in use at this point.
+	for _, c := range replaced {
+		if err := closeWithContext(ctx, c); err != nil {
+			t.logger.Warnf("Failed to cleanly close a replaced resource: %v\n", err)
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
 // CloseAsync triggers the shut down of all resource types that implement the
 // lifetime interface types.Closable.
 func (t *Type) CloseAsync() {
@@ -780,6 +906,9 @@ func (t *Type) CloseAsync() {
 	for _, c := range t.outputs {
 		c.CloseAsync()
 	}
+	for _, c := range t.credentials {
+		c.CloseAsync()
+	}
 }
 
 // WaitForClose blocks until either all closable resource types are shut down or
@@ -819,6 +948,12 @@ func (t *Type) WaitForClose(timeout time.Duration) error {
 		}
 		delete(t.outputs, k)
 	}
+	for k, c := range t.credentials {
+		if err := c.WaitForClose(time.Until(timesOut)); err != nil {
+			return fmt.Errorf("resource '%s' failed to cleanly shutdown: %v", k, err)
+		}
+		delete(t.credentials, k)
+	}
 	for k, c := range t.plugins {
 		if closer, ok := c.(types.Closable); ok {
 			if err := closer.WaitForClose(time.Until(timesOut)); err != nil {
@@ -856,6 +991,8 @@ func (t *Type) GetInput(name string) (types.Input, error) {
 
 // GetCache attempts to find a service wide cache by its name.
 func (t *Type) GetCache(name string) (types.Cache, error) {
+	t.resourceLock.RLock()
+	defer t.resourceLock.RUnlock()
 	if c, exists := t.caches[name]; exists {
 		return c, nil
 	}
@@ -880,6 +1017,8 @@ func (t *Type) GetProcessor(name string) (types.Processor, error) {
 
 // GetRateLimit attempts to find a service wide rate limit by its name.
 func (t *Type) GetRateLimit(name string) (types.RateLimit, error) {
+	t.resourceLock.RLock()
+	defer t.resourceLock.RUnlock()
 	if rl, exists := t.rateLimits[name]; exists {
 		return rl, nil
 	}
@@ -894,6 +1033,15 @@ func (t *Type) GetOutput(name string) (types.OutputWriter, error) {
 	return nil, types.ErrOutputNotFound
 }
 
+// GetCredentials attempts to find a service wide credentials resource by its
+// name.
+func (t *Type) GetCredentials(name string) (credentials.Resolver, error) {
+	if r, exists := t.credentials[name]; exists {
+		return r, nil
+	}
+	return nil, types.ErrCredentialsNotFound
+}
+
 // GetPlugin attempts to find a service wide resource plugin by its name.
 func (t *Type) GetPlugin(name string) (interface{}, error) {
 	if pl, exists := t.plugins[name]; exists {
@@ -2,14 +2,18 @@ package manager_test
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/cache"
 	"github.com/Jeffail/benthos/v3/lib/condition"
+	"github.com/Jeffail/benthos/v3/lib/credentials"
 	"github.com/Jeffail/benthos/v3/lib/input"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/output"
 	"github.com/Jeffail/benthos/v3/lib/processor"
@@ -34,7 +38,7 @@ func TestManagerProcessorLabels(t *testing.T) {
 	for _, l := range goodLabels {
 		conf := processor.NewConfig()
 		conf.Type = processor.TypeBloblang
-		conf.Bloblang = "root = this"
+		conf.Bloblang.Mapping = "root = this"
 		conf.Label = l
 
 		mgr, err := manager.New(manager.NewConfig(), nil, log.Noop(), metrics.Noop())
@@ -53,7 +57,7 @@ func TestManagerProcessorLabels(t *testing.T) {
 	for _, l := range badLabels {
 		conf := processor.NewConfig()
 		conf.Type = processor.TypeBloblang
-		conf.Bloblang = "root = this"
+		conf.Bloblang.Mapping = "root = this"
 		conf.Label = l
 
 		mgr, err := manager.New(manager.NewConfig(), nil, log.Noop(), metrics.Noop())
@@ -64,6 +68,34 @@ func TestManagerProcessorLabels(t *testing.T) {
 	}
 }
 
+func TestManagerLabelPolicy(t *testing.T) {
+	mgr, err := manager.New(manager.NewConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr = mgr.WithLabelPolicy(docs.LabelPolicy{
+		AllowUppercase: true,
+		AllowHyphens:   true,
+	})
+
+	for _, l := range []string{"fooBar", "foo-bar", "FOO"} {
+		conf := processor.NewConfig()
+		conf.Type = processor.TypeBloblang
+		conf.Bloblang.Mapping = "root = this"
+		conf.Label = l
+
+		_, err = mgr.NewProcessor(conf)
+		assert.NoError(t, err, "label: %v", l)
+	}
+
+	conf := processor.NewConfig()
+	conf.Type = processor.TypeBloblang
+	conf.Bloblang.Mapping = "root = this"
+	conf.Label = "_fooBar"
+
+	_, err = mgr.NewProcessor(conf)
+	assert.EqualError(t, err, "should match the regular expression /^[a-zA-Z0-9_-]+$/ and must not start with an underscore")
+}
+
 func TestManagerCache(t *testing.T) {
 	testLog := log.Noop()
 
@@ -209,6 +241,47 @@ func TestManagerRateLimitListErrors(t *testing.T) {
 	require.EqualError(t, err, "rate limit resource has an empty label")
 }
 
+func TestManagerCredentialsListErrors(t *testing.T) {
+	cFoo := credentials.NewConfig()
+	cFoo.Label = "foo"
+
+	cBar := credentials.NewConfig()
+	cBar.Label = "foo"
+
+	conf := manager.NewResourceConfig()
+	conf.ResourceCredentials = append(conf.ResourceCredentials, cFoo, cBar)
+
+	_, err := manager.NewV2(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "credentials resource label 'foo' collides with a previously defined resource")
+
+	cEmpty := credentials.NewConfig()
+	conf = manager.NewResourceConfig()
+	conf.ResourceCredentials = append(conf.ResourceCredentials, cEmpty)
+
+	_, err = manager.NewV2(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "credentials resource has an empty label")
+}
+
+func TestManagerCredentialsList(t *testing.T) {
+	cFoo := credentials.NewConfig()
+	cFoo.Label = "foo"
+
+	conf := manager.NewResourceConfig()
+	conf.ResourceCredentials = append(conf.ResourceCredentials, cFoo)
+
+	mgr, err := manager.NewV2(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	err = mgr.AccessCredentials(context.Background(), "foo", func(credentials.Resolver) {})
+	require.NoError(t, err)
+
+	err = mgr.AccessCredentials(context.Background(), "bar", func(credentials.Resolver) {})
+	assert.EqualError(t, err, "unable to locate resource: bar")
+
+	mgr.CloseAsync()
+	require.NoError(t, mgr.WaitForClose(time.Second))
+}
+
 func TestManagerBadRateLimit(t *testing.T) {
 	conf := manager.NewConfig()
 	badConf := ratelimit.NewConfig()
@@ -220,6 +293,97 @@ func TestManagerBadRateLimit(t *testing.T) {
 	}
 }
 
+func TestManagerReplaceResources(t *testing.T) {
+	ctx := context.Background()
+
+	conf := manager.NewConfig()
+	conf.Caches["foo"] = cache.NewConfig()
+	conf.RateLimits["bar"] = ratelimit.NewConfig()
+
+	mgr, err := manager.New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.AccessCache(ctx, "foo", func(c types.Cache) {
+		require.NoError(t, c.Set("key", []byte("old")))
+	}))
+
+	var oldRateLimit types.RateLimit
+	require.NoError(t, mgr.AccessRateLimit(ctx, "bar", func(r types.RateLimit) {
+		oldRateLimit = r
+	}))
+
+	badCache := cache.NewConfig()
+	badCache.Type = "notexist"
+
+	newRateLimit := ratelimit.NewConfig()
+	newRateLimit.Local.Count = 5
+
+	err = mgr.ReplaceResources(ctx, map[string]cache.Config{
+		"foo": badCache,
+	}, map[string]ratelimit.Config{
+		"bar": newRateLimit,
+	})
+	require.Error(t, err)
+
+	require.NoError(t, mgr.AccessCache(ctx, "foo", func(c types.Cache) {
+		v, err := c.Get("key")
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(v))
+	}))
+	require.NoError(t, mgr.AccessRateLimit(ctx, "bar", func(r types.RateLimit) {
+		assert.Same(t, oldRateLimit, r)
+	}))
+
+	err = mgr.ReplaceResources(ctx, map[string]cache.Config{
+		"foo": cache.NewConfig(),
+		"baz": cache.NewConfig(),
+	}, map[string]ratelimit.Config{
+		"bar": newRateLimit,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.AccessCache(ctx, "foo", func(c types.Cache) {
+		_, err := c.Get("key")
+		assert.Equal(t, types.ErrKeyNotFound, err)
+	}))
+	require.NoError(t, mgr.AccessCache(ctx, "baz", func(types.Cache) {}))
+	require.NoError(t, mgr.AccessRateLimit(ctx, "bar", func(r types.RateLimit) {
+		assert.NotSame(t, oldRateLimit, r)
+	}))
+}
+
+func TestManagerReplaceResourcesConcurrentGet(t *testing.T) {
+	ctx := context.Background()
+
+	conf := manager.NewConfig()
+	conf.Caches["foo"] = cache.NewConfig()
+	conf.RateLimits["bar"] = ratelimit.NewConfig()
+
+	mgr, err := manager.New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_, cErr := mgr.GetCache("foo")
+			assert.NoError(t, cErr)
+			_, rErr := mgr.GetRateLimit("bar")
+			assert.NoError(t, rErr)
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, mgr.ReplaceResources(ctx, map[string]cache.Config{
+			"foo": cache.NewConfig(),
+		}, map[string]ratelimit.Config{
+			"bar": ratelimit.NewConfig(),
+		}))
+	}
+	wg.Wait()
+}
+
 func TestManagerCondition(t *testing.T) {
 	testLog := log.Noop()
 
@@ -264,6 +428,36 @@ func TestManagerProcessor(t *testing.T) {
 	}
 }
 
+func TestManagerProcessorWorkflowBranch(t *testing.T) {
+	branchConf := processor.NewConfig()
+	branchConf.Type = processor.TypeBranch
+	branchConf.Branch.RequestMap = "root = this.foo"
+	branchConf.Branch.ResultMap = "root.bar = content().string()"
+
+	upperConf := processor.NewConfig()
+	upperConf.Type = processor.TypeBloblang
+	upperConf.Bloblang.Mapping = "root = content().uppercase()"
+	branchConf.Branch.Processors = append(branchConf.Branch.Processors, upperConf)
+
+	conf := manager.NewConfig()
+	conf.Processors["foo"] = branchConf
+
+	mgr, err := manager.New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	workflowConf := processor.NewConfig()
+	workflowConf.Type = processor.TypeWorkflow
+	workflowConf.Workflow.BranchResources = []string{"foo"}
+
+	proc, err := mgr.NewProcessor(workflowConf)
+	require.NoError(t, err)
+
+	msgs, res := proc.ProcessMessage(message.New([][]byte{[]byte(`{"foo":"hello"}`)}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, `{"bar":"HELLO","foo":"hello","meta":{"workflow":{"succeeded":["foo"]}}}`, string(msgs[0].Get(0).Get()))
+}
+
 func TestManagerProcessorList(t *testing.T) {
 	cFoo := processor.NewConfig()
 	cFoo.Label = "foo"
@@ -39,6 +39,11 @@ Allows you to configure a [batching policy](/docs/configuration/batching).`,
 				"A period in which an incomplete batch should be flushed regardless of its size.",
 				"1s", "1m", "500ms",
 			).HasDefault(""),
+			docs.FieldString(
+				"idle_period",
+				"A duration after which an incomplete batch should be flushed when no new messages have been added to it. Unlike `period` this is measured from the most recently added message, which bounds latency during lulls in traffic without flushing batches early during steady traffic.",
+				"100ms", "1s",
+			).HasDefault("").AtVersion("3.51.0").Advanced(),
 			docs.FieldString(
 				"check",
 				"A [Bloblang query](/docs/guides/bloblang/about/) that should return a boolean value indicating whether a message should end a batch.",
@@ -27,11 +27,12 @@ func SanitisePolicyConfig(policy PolicyConfig) (interface{}, error) {
 		}
 	}
 	bSanit := map[string]interface{}{
-		"byte_size":  policy.ByteSize,
-		"count":      policy.Count,
-		"check":      policy.Check,
-		"period":     policy.Period,
-		"processors": procConfs,
+		"byte_size":   policy.ByteSize,
+		"count":       policy.Count,
+		"check":       policy.Check,
+		"period":      policy.Period,
+		"idle_period": policy.IdlePeriod,
+		"processors":  procConfs,
 	}
 	if !isNoopCondition(policy.Condition) {
 		condSanit, err := condition.SanitiseConfig(policy.Condition)
@@ -56,6 +57,7 @@ type PolicyConfig struct {
 	Condition  condition.Config   `json:"condition" yaml:"condition"`
 	Check      string             `json:"check" yaml:"check"`
 	Period     string             `json:"period" yaml:"period"`
+	IdlePeriod string             `json:"idle_period" yaml:"idle_period"`
 	Processors []processor.Config `json:"processors" yaml:"processors"`
 }
 
@@ -70,6 +72,7 @@ func NewPolicyConfig() PolicyConfig {
 		Condition:  cond,
 		Check:      "",
 		Period:     "",
+		IdlePeriod: "",
 		Processors: []processor.Config{},
 	}
 }
@@ -91,6 +94,9 @@ func (p PolicyConfig) IsNoop() bool {
 	if len(p.Period) > 0 {
 		return false
 	}
+	if len(p.IdlePeriod) > 0 {
+		return false
+	}
 	if len(p.Processors) > 0 {
 		return false
 	}
@@ -107,6 +113,9 @@ func (p PolicyConfig) isLimited() bool {
 	if len(p.Period) > 0 {
 		return true
 	}
+	if len(p.IdlePeriod) > 0 {
+		return true
+	}
 	if !isNoopCondition(p.Condition) {
 		return true
 	}
@@ -136,21 +145,24 @@ func (p PolicyConfig) isHardLimited() bool {
 type Policy struct {
 	log log.Modular
 
-	byteSize  int
-	count     int
-	period    time.Duration
-	cond      condition.Type
-	check     *mapping.Executor
-	procs     []types.Processor
-	sizeTally int
-	parts     []types.Part
+	byteSize   int
+	count      int
+	period     time.Duration
+	idlePeriod time.Duration
+	cond       condition.Type
+	check      *mapping.Executor
+	procs      []types.Processor
+	sizeTally  int
+	parts      []types.Part
 
 	triggered bool
 	lastBatch time.Time
+	lastAdd   time.Time
 
 	mSizeBatch   metrics.StatCounter
 	mCountBatch  metrics.StatCounter
 	mPeriodBatch metrics.StatCounter
+	mIdleBatch   metrics.StatCounter
 	mCheckBatch  metrics.StatCounter
 	mCondBatch   metrics.StatCounter
 }
@@ -188,6 +200,12 @@ func NewPolicy(
 			return nil, fmt.Errorf("failed to parse duration string: %v", err)
 		}
 	}
+	var idlePeriod time.Duration
+	if len(conf.IdlePeriod) > 0 {
+		if idlePeriod, err = time.ParseDuration(conf.IdlePeriod); err != nil {
+			return nil, fmt.Errorf("failed to parse idle period duration string: %v", err)
+		}
+	}
 	var procs []types.Processor
 	for i, pconf := range conf.Processors {
 		pMgr, pLog, pStats := interop.LabelChild(fmt.Sprintf("%v", i), mgr, log, stats)
@@ -200,18 +218,20 @@ func NewPolicy(
 	return &Policy{
 		log: log,
 
-		byteSize: conf.ByteSize,
-		count:    conf.Count,
-		period:   period,
-		cond:     cond,
-		check:    check,
-		procs:    procs,
+		byteSize:   conf.ByteSize,
+		count:      conf.Count,
+		period:     period,
+		idlePeriod: idlePeriod,
+		cond:       cond,
+		check:      check,
+		procs:      procs,
 
 		lastBatch: time.Now(),
 
 		mSizeBatch:   stats.GetCounter("on_size"),
 		mCountBatch:  stats.GetCounter("on_count"),
 		mPeriodBatch: stats.GetCounter("on_period"),
+		mIdleBatch:   stats.GetCounter("on_idle"),
 		mCheckBatch:  stats.GetCounter("on_check"),
 		mCondBatch:   stats.GetCounter("on_condition"),
 	}, nil
@@ -224,6 +244,7 @@ func NewPolicy(
 func (p *Policy) Add(part types.Part) bool {
 	p.sizeTally += len(part.Get())
 	p.parts = append(p.parts, part)
+	p.lastAdd = time.Now()
 
 	if !p.triggered && p.count > 0 && len(p.parts) >= p.count {
 		p.triggered = true
@@ -289,6 +310,9 @@ func (p *Policy) FlushAny() []types.Message {
 		if !p.triggered && p.period > 0 && time.Since(p.lastBatch) > p.period {
 			p.mPeriodBatch.Incr(1)
 			p.log.Traceln("Batching based on period")
+		} else if !p.triggered && p.idlePeriod > 0 && time.Since(p.lastAdd) >= p.idlePeriod {
+			p.mIdleBatch.Incr(1)
+			p.log.Traceln("Batching based on idle period")
 		}
 		newMsg = message.New(nil)
 		newMsg.Append(p.parts...)
@@ -323,13 +347,27 @@ func (p *Policy) Count() int {
 }
 
 // UntilNext returns a duration indicating how long until the current batch
-// should be flushed due to a configured period. A negative duration indicates
-// a period has not been set.
+// should be flushed due to a configured period or idle period. A negative
+// duration indicates a period has not been set.
+//
+// Since the idle period of a batch is extended each time a message is added,
+// callers waiting on the returned duration should call UntilNext again once it
+// has elapsed in order to determine whether the batch is due.
 func (p *Policy) UntilNext() time.Duration {
-	if p.period <= 0 {
-		return -1
+	next := time.Duration(-1)
+	if p.period > 0 {
+		next = time.Until(p.lastBatch.Add(p.period))
+	}
+	if p.idlePeriod > 0 && len(p.parts) > 0 {
+		untilIdle := time.Until(p.lastAdd.Add(p.idlePeriod))
+		if untilIdle < 0 {
+			untilIdle = 0
+		}
+		if next < 0 || untilIdle < next {
+			next = untilIdle
+		}
 	}
-	return time.Until(p.lastBatch.Add(p.period))
+	return next
 }
 
 //------------------------------------------------------------------------------
@@ -124,6 +124,45 @@ func TestPolicyPeriod(t *testing.T) {
 	}
 }
 
+func TestPolicyIdlePeriod(t *testing.T) {
+	conf := NewPolicyConfig()
+	conf.Period = "1s"
+	conf.IdlePeriod = "200ms"
+
+	pol, err := NewPolicy(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		pol.CloseAsync()
+		require.NoError(t, pol.WaitForClose(time.Second))
+	})
+
+	// Without pending messages only the period applies.
+	v := pol.UntilNext()
+	assert.Greater(t, int64(v), int64(time.Millisecond*500))
+
+	assert.False(t, pol.Add(message.NewPart(nil)))
+	v = pol.UntilNext()
+	assert.LessOrEqual(t, int64(v), int64(time.Millisecond*200))
+	assert.Greater(t, int64(v), int64(time.Millisecond*100))
+
+	// Adding a message extends the idle period.
+	<-time.After(time.Millisecond * 150)
+	assert.False(t, pol.Add(message.NewPart(nil)))
+	v = pol.UntilNext()
+	assert.Greater(t, int64(v), int64(time.Millisecond*100))
+
+	<-time.After(time.Millisecond * 250)
+	assert.Equal(t, time.Duration(0), pol.UntilNext())
+
+	msg := pol.Flush()
+	require.NotNil(t, msg)
+	assert.Equal(t, 2, msg.Len())
+
+	v = pol.UntilNext()
+	assert.Greater(t, int64(v), int64(time.Millisecond*500))
+}
+
 func TestPolicySize(t *testing.T) {
 	conf := NewPolicyConfig()
 	conf.ByteSize = 10
@@ -202,7 +202,10 @@ func (c *cloudWatchStat) Decr(count int64) error {
 func (c *cloudWatchStat) Timing(delta int64) error {
 	// Most granular value for timing metrics in cloudwatch is microseconds
 	// versus nanoseconds.
-	c.appendValue(delta / 1000)
+	if c.unit == cloudwatch.StandardUnitMicroseconds {
+		delta /= 1000
+	}
+	c.appendValue(delta)
 	return nil
 }
 
@@ -281,6 +284,7 @@ type CloudWatch struct {
 
 	ctx    context.Context
 	cancel func()
+	loopWG sync.WaitGroup
 
 	pathMapping *pathMapping
 	config      CloudWatchConfig
@@ -325,6 +329,7 @@ func newCloudWatch(config CloudWatchConfig, opts ...func(Type)) (Type, error) {
 	}
 
 	c.client = cloudwatch.New(sess)
+	c.loopWG.Add(1)
 	go c.loop()
 	return c, nil
 }
@@ -361,56 +366,63 @@ func (c *CloudWatch) GetCounter(path string) StatCounter {
 
 // GetCounterVec returns a stat counter object for a path with the labels
 func (c *CloudWatch) GetCounterVec(path string, n []string) StatCounterVec {
-	name, labels, values := c.toCMName(path)
-	if name == "" {
+	mapped := c.pathMapping.mapPathWithVecLabels(path, n)
+	if mapped.path == "" {
 		return fakeCounterVec(func([]string) StatCounter {
 			return DudStat{}
 		})
 	}
-	if len(labels) > 0 {
-		labels = append(labels, n...)
+	if !mapped.passthrough() {
 		return fakeCounterVec(func(vs []string) StatCounter {
-			fvs := append([]string{}, values...)
-			fvs = append(fvs, vs...)
 			return (&cloudWatchCounterVec{
 				cloudWatchStatVec: cloudWatchStatVec{
 					root:       c,
-					name:       name,
+					name:       mapped.path,
 					unit:       cloudwatch.StandardUnitCount,
-					labelNames: labels,
+					labelNames: mapped.labelNames,
 				},
-			}).With(fvs...)
+			}).With(mapped.labelValues(vs)...)
 		})
 	}
 	return &cloudWatchCounterVec{
 		cloudWatchStatVec: cloudWatchStatVec{
 			root:       c,
-			name:       name,
+			name:       mapped.path,
 			unit:       cloudwatch.StandardUnitCount,
-			labelNames: n,
+			labelNames: mapped.labelNames,
 		},
 	}
 }
 
+// cloudWatchTimerUnit returns the unit of a timer metric, which is bytes for
+// size timers and microseconds for all others.
+func cloudWatchTimerUnit(path string) string {
+	if IsSizeTimer(path) {
+		return cloudwatch.StandardUnitBytes
+	}
+	return cloudwatch.StandardUnitMicroseconds
+}
+
 // GetTimer returns a stat timer object for a path.
 func (c *CloudWatch) GetTimer(path string) StatTimer {
 	name, labels, values := c.toCMName(path)
 	if name == "" {
 		return DudStat{}
 	}
+	unit := cloudWatchTimerUnit(path)
 	if len(labels) == 0 {
 		return &cloudWatchStat{
 			root: c,
 			id:   name,
 			name: name,
-			unit: cloudwatch.StandardUnitMicroseconds,
+			unit: unit,
 		}
 	}
 	return (&cloudWatchTimerVec{
 		cloudWatchStatVec: cloudWatchStatVec{
 			root:       c,
 			name:       name,
-			unit:       cloudwatch.StandardUnitMicroseconds,
+			unit:       unit,
 			labelNames: labels,
 		},
 	}).With(values...)
@@ -418,33 +430,31 @@ func (c *CloudWatch) GetTimer(path string) StatTimer {
 
 // GetTimerVec returns a stat timer object for a path with the labels
 func (c *CloudWatch) GetTimerVec(path string, n []string) StatTimerVec {
-	name, labels, values := c.toCMName(path)
-	if name == "" {
+	mapped := c.pathMapping.mapPathWithVecLabels(path, n)
+	if mapped.path == "" {
 		return fakeTimerVec(func([]string) StatTimer {
 			return DudStat{}
 		})
 	}
-	if len(labels) > 0 {
-		labels = append(labels, n...)
+	unit := cloudWatchTimerUnit(path)
+	if !mapped.passthrough() {
 		return fakeTimerVec(func(vs []string) StatTimer {
-			fvs := append([]string{}, values...)
-			fvs = append(fvs, vs...)
 			return (&cloudWatchTimerVec{
 				cloudWatchStatVec: cloudWatchStatVec{
 					root:       c,
-					name:       name,
-					unit:       cloudwatch.StandardUnitMicroseconds,
-					labelNames: labels,
+					name:       mapped.path,
+					unit:       unit,
+					labelNames: mapped.labelNames,
 				},
-			}).With(fvs...)
+			}).With(mapped.labelValues(vs)...)
 		})
 	}
 	return &cloudWatchTimerVec{
 		cloudWatchStatVec: cloudWatchStatVec{
 			root:       c,
-			name:       name,
-			unit:       cloudwatch.StandardUnitMicroseconds,
-			labelNames: n,
+			name:       mapped.path,
+			unit:       unit,
+			labelNames: mapped.labelNames,
 		},
 	}
 }
@@ -475,33 +485,30 @@ func (c *CloudWatch) GetGauge(path string) StatGauge {
 
 // GetGaugeVec returns a stat timer object for a path with the labels
 func (c *CloudWatch) GetGaugeVec(path string, n []string) StatGaugeVec {
-	name, labels, values := c.toCMName(path)
-	if name == "" {
+	mapped := c.pathMapping.mapPathWithVecLabels(path, n)
+	if mapped.path == "" {
 		return fakeGaugeVec(func([]string) StatGauge {
 			return DudStat{}
 		})
 	}
-	if len(labels) > 0 {
-		labels = append(labels, n...)
+	if !mapped.passthrough() {
 		return fakeGaugeVec(func(vs []string) StatGauge {
-			fvs := append([]string{}, values...)
-			fvs = append(fvs, vs...)
 			return (&cloudWatchGaugeVec{
 				cloudWatchStatVec: cloudWatchStatVec{
 					root:       c,
-					name:       name,
+					name:       mapped.path,
 					unit:       cloudwatch.StandardUnitNone,
-					labelNames: labels,
+					labelNames: mapped.labelNames,
 				},
-			}).With(fvs...)
+			}).With(mapped.labelValues(vs)...)
 		})
 	}
 	return &cloudWatchGaugeVec{
 		cloudWatchStatVec: cloudWatchStatVec{
 			root:       c,
-			name:       name,
+			name:       mapped.path,
 			unit:       cloudwatch.StandardUnitNone,
-			labelNames: n,
+			labelNames: mapped.labelNames,
 		},
 	}
 }
@@ -509,6 +516,7 @@ func (c *CloudWatch) GetGaugeVec(path string, n []string) StatGaugeVec {
 //------------------------------------------------------------------------------
 
 func (c *CloudWatch) loop() {
+	defer c.loopWG.Done()
 	ticker := time.NewTicker(c.flushPeriod)
 	defer ticker.Stop()
 	for {
@@ -644,12 +652,15 @@ func (c *CloudWatch) SetLogger(log log.Modular) {
 	c.log = log
 }
 
-// Close stops the CloudWatch object from aggregating metrics and cleans up
-// resources.
+// Close stops the CloudWatch object from aggregating metrics, flushes any
+// remaining metrics and cleans up resources.
 func (c *CloudWatch) Close() error {
 	c.cancel()
-	c.flush()
-	return nil
+
+	// Wait for any flush in progress to finish so that the final flush
+	// includes everything aggregated up until now.
+	c.loopWG.Wait()
+	return c.flush()
 }
 
 //------------------------------------------------------------------------------
@@ -203,6 +203,36 @@ func TestCloudWatchBasic(t *testing.T) {
 	}, checkInput(mockSvc.inputs[1]))
 }
 
+func TestCloudWatchSizeTimers(t *testing.T) {
+	mockSvc := &mockCloudWatchClient{}
+
+	cw := &CloudWatch{
+		config:    NewCloudWatchConfig(),
+		datumses:  map[string]*cloudWatchDatum{},
+		datumLock: &sync.Mutex{},
+		log:       log.Noop(),
+		client:    mockSvc,
+	}
+	cw.ctx, cw.cancel = context.WithCancel(context.Background())
+
+	sizeFoo := cw.GetTimer("input.message_size")
+	sizeFoo.Timing(23000)
+	sizeFoo.Timing(512)
+
+	cw.flush()
+
+	assert.Equal(t, 1, len(mockSvc.inputs))
+	assert.Equal(t, map[string]checkedDatum{
+		"input.message_size": {
+			unit: "Bytes",
+			values: map[float64]float64{
+				23000: 1,
+				512:   1,
+			},
+		},
+	}, checkInput(mockSvc.inputs[0]))
+}
+
 func TestCloudWatchMoreThan20Items(t *testing.T) {
 	mockSvc := &mockCloudWatchClient{}
 
@@ -535,3 +565,30 @@ func TestCloudWatchTagsMoreThan20(t *testing.T) {
 		},
 	}, checkInput(mockSvc.inputs[0]))
 }
+
+func TestCloudWatchCloseFlushes(t *testing.T) {
+	mockSvc := &mockCloudWatchClient{}
+
+	cw := &CloudWatch{
+		config:      NewCloudWatchConfig(),
+		datumses:    map[string]*cloudWatchDatum{},
+		datumLock:   &sync.Mutex{},
+		log:         log.Noop(),
+		client:      mockSvc,
+		flushPeriod: time.Hour,
+	}
+	cw.ctx, cw.cancel = context.WithCancel(context.Background())
+	cw.loopWG.Add(1)
+	go cw.loop()
+
+	cw.GetCounter("counter.foo").Incr(5)
+	require.NoError(t, cw.Close())
+
+	require.Len(t, mockSvc.inputs, 1)
+	assert.Equal(t, map[string]checkedDatum{
+		"counter.foo": {
+			unit:  "Count",
+			value: 5,
+		},
+	}, checkInput(mockSvc.inputs[0]))
+}
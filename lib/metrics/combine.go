@@ -183,9 +183,11 @@ func (c *combinedWrapper) SetLogger(log log.Modular) {
 }
 
 func (c *combinedWrapper) Close() error {
-	c.t1.Close()
-	c.t2.Close()
-	return nil
+	err1 := c.t1.Close()
+	if err2 := c.t2.Close(); err1 == nil {
+		err1 = err2
+	}
+	return err1
 }
 
 //------------------------------------------------------------------------------
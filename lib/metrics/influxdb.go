@@ -352,19 +352,15 @@ func (i *InfluxDB) GetCounter(path string) StatCounter {
 
 // GetCounterVec returns a stat counter object for a path with the labels
 func (i *InfluxDB) GetCounterVec(path string, n []string) StatCounterVec {
-	name, labels, values := i.toCMName(path)
-	if name == "" {
+	mapped := i.pathMapping.mapPathWithVecLabels(path, n)
+	if mapped.path == "" {
 		return fakeCounterVec(func([]string) StatCounter {
 			return DudStat{}
 		})
 	}
-	labels = append(labels, n...)
 	return &fCounterVec{
 		f: func(l []string) StatCounter {
-			v := make([]string, 0, len(values)+len(l))
-			v = append(v, values...)
-			v = append(v, l...)
-			encodedName := encodeInfluxDBName(path, labels, v)
+			encodedName := encodeInfluxDBName(path, mapped.labelNames, mapped.labelValues(l))
 			return i.registry.GetOrRegister(encodedName, func() metrics.Counter {
 				return influxDBCounter{
 					metrics.NewCounter(),
@@ -390,19 +386,15 @@ func (i *InfluxDB) GetTimer(path string) StatTimer {
 
 // GetTimerVec returns a stat timer object for a path with the labels
 func (i *InfluxDB) GetTimerVec(path string, n []string) StatTimerVec {
-	name, labels, values := i.toCMName(path)
-	if name == "" {
+	mapped := i.pathMapping.mapPathWithVecLabels(path, n)
+	if mapped.path == "" {
 		return fakeTimerVec(func([]string) StatTimer {
 			return DudStat{}
 		})
 	}
-	labels = append(labels, n...)
 	return &fTimerVec{
 		f: func(l []string) StatTimer {
-			v := make([]string, 0, len(values)+len(l))
-			v = append(v, values...)
-			v = append(v, l...)
-			encodedName := encodeInfluxDBName(name, labels, v)
+			encodedName := encodeInfluxDBName(mapped.path, mapped.labelNames, mapped.labelValues(l))
 			return i.registry.GetOrRegister(encodedName, func() metrics.Timer {
 				return influxDBTimer{
 					metrics.NewTimer(),
@@ -429,19 +421,15 @@ func (i *InfluxDB) GetGauge(path string) StatGauge {
 
 // GetGaugeVec returns a stat timer object for a path with the labels
 func (i *InfluxDB) GetGaugeVec(path string, n []string) StatGaugeVec {
-	name, labels, values := i.toCMName(path)
-	if name == "" {
+	mapped := i.pathMapping.mapPathWithVecLabels(path, n)
+	if mapped.path == "" {
 		return fakeGaugeVec(func([]string) StatGauge {
 			return DudStat{}
 		})
 	}
-	labels = append(labels, n...)
 	return &fGaugeVec{
 		f: func(l []string) StatGauge {
-			v := make([]string, 0, len(values)+len(l))
-			v = append(v, values...)
-			v = append(v, l...)
-			encodedName := encodeInfluxDBName(name, labels, v)
+			encodedName := encodeInfluxDBName(mapped.path, mapped.labelNames, mapped.labelValues(l))
 			return i.registry.GetOrRegister(encodedName, func() metrics.Gauge {
 				return influxDBGauge{
 					metrics.NewGauge(),
@@ -3,6 +3,9 @@ package metrics
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
@@ -17,6 +20,45 @@ import (
 type pathMapping struct {
 	m      *mapping.Executor
 	logger log.Modular
+
+	cacheMut sync.RWMutex
+	cache    map[string]mappedPath
+}
+
+// mappedPath is the cached result of executing a path mapping on a given
+// metric path and set of dynamic label names.
+type mappedPath struct {
+	path string
+
+	// labelNames contains the names of static labels created by the mapping,
+	// followed by the names of any dynamic labels that were retained.
+	labelNames   []string
+	staticValues []string
+
+	// keepIndexes contains the indexes of dynamic label values to retain, or
+	// is nil when all dynamic labels are retained.
+	keepIndexes []int
+}
+
+// passthrough returns true when dynamic label values can be used as they are.
+func (p mappedPath) passthrough() bool {
+	return len(p.staticValues) == 0 && p.keepIndexes == nil
+}
+
+// labelValues returns the full set of label values for a metric given the
+// values of its dynamic labels.
+func (p mappedPath) labelValues(dynamicValues []string) []string {
+	values := make([]string, 0, len(p.labelNames))
+	values = append(values, p.staticValues...)
+	if p.keepIndexes == nil {
+		return append(values, dynamicValues...)
+	}
+	for _, i := range p.keepIndexes {
+		if i < len(dynamicValues) {
+			values = append(values, dynamicValues[i])
+		}
+	}
+	return values
 }
 
 func pathMappingDocs(allowLabels, forPrometheus bool) docs.FieldSpec {
@@ -41,15 +83,15 @@ func pathMappingDocs(allowLabels, forPrometheus bool) docs.FieldSpec {
 	if allowLabels {
 		examples = append(examples, `let matches = this.re_find_all_submatch("resource_processor_([a-zA-Z]+)_(.*)")
 meta processor = $matches.0.1 | deleted()
-root = $matches.0.2 | deleted()`)
-		summary += " BETA FEATURE: Labels can also be created for the metric path by mapping meta fields."
+root = $matches.0.2 | deleted()`, `meta message_id = deleted()`)
+		summary += " BETA FEATURE: Labels can also be created for the metric path by mapping meta fields. Labels that are populated dynamically by a component, such as those of the `metric` processor, are exposed to the mapping as meta fields with empty values and can be removed with `meta foo = deleted()`, which is useful for reducing the cardinality of metrics."
 	}
 	return docs.FieldCommon("path_mapping", summary, examples...).Linter(docs.LintBloblangMapping)
 }
 
 func newPathMapping(mapping string, logger log.Modular) (*pathMapping, error) {
 	if mapping == "" {
-		return &pathMapping{m: nil, logger: logger}, nil
+		return &pathMapping{m: nil, logger: logger, cache: map[string]mappedPath{}}, nil
 	}
 	m, err := bloblang.NewMapping("", mapping)
 	if err != nil {
@@ -58,7 +100,7 @@ func newPathMapping(mapping string, logger log.Modular) (*pathMapping, error) {
 		}
 		return nil, err
 	}
-	return &pathMapping{m, logger}, nil
+	return &pathMapping{m: m, logger: logger, cache: map[string]mappedPath{}}, nil
 }
 
 func (m *pathMapping) mapPathNoTags(path string) string {
@@ -74,9 +116,54 @@ func (m *pathMapping) mapPath(path string, allowLabels bool) (outPath string, la
 	if m == nil || m.m == nil {
 		return path, nil, nil
 	}
+	mapped := m.mapPathCached(path, allowLabels, nil)
+	return mapped.path, mapped.labelNames, mapped.staticValues
+}
+
+// mapPathWithVecLabels maps a path along with the names of labels that are
+// populated dynamically, where the mapping is able to remove dynamic labels by
+// deleting them from the metadata of the mapped message.
+func (m *pathMapping) mapPathWithVecLabels(path string, vecLabelNames []string) mappedPath {
+	if m == nil || m.m == nil {
+		return mappedPath{path: path, labelNames: vecLabelNames}
+	}
+	return m.mapPathCached(path, true, vecLabelNames)
+}
+
+// mapPathCached executes the mapping once for each unique combination of path
+// and dynamic label names, components obtain metrics at creation time and
+// therefore results can be safely reused.
+func (m *pathMapping) mapPathCached(path string, allowLabels bool, vecLabelNames []string) mappedPath {
+	key := strconv.FormatBool(allowLabels) + "\x00" + path + "\x00" + strings.Join(vecLabelNames, "\x00")
+
+	m.cacheMut.RLock()
+	mapped, exists := m.cache[key]
+	m.cacheMut.RUnlock()
+	if exists {
+		return mapped
+	}
+
+	mapped = m.execMapping(path, allowLabels, vecLabelNames)
+
+	// Cap the cached slices so that callers appending to them cannot modify
+	// the cache.
+	mapped.labelNames = mapped.labelNames[:len(mapped.labelNames):len(mapped.labelNames)]
+	mapped.staticValues = mapped.staticValues[:len(mapped.staticValues):len(mapped.staticValues)]
+
+	m.cacheMut.Lock()
+	m.cache[key] = mapped
+	m.cacheMut.Unlock()
+	return mapped
+}
+
+func (m *pathMapping) execMapping(path string, allowLabels bool, vecLabelNames []string) mappedPath {
+	unchanged := mappedPath{path: path, labelNames: vecLabelNames}
 
 	var input interface{} = path
 	meta := metadata.New(nil)
+	for _, k := range vecLabelNames {
+		meta.Set(k, "")
+	}
 	vars := map[string]interface{}{}
 
 	var v interface{} = query.Nothing(nil)
@@ -91,13 +178,41 @@ func (m *pathMapping) mapPath(path string, allowLabels bool) (outPath string, la
 		Value: &v,
 	}); err != nil {
 		m.logger.Errorf("Failed to apply path mapping on '%v': %v\n", path, err)
-		return path, nil, nil
+		return unchanged
 	}
 
-	meta.Iter(func(k, v string) error {
-		labelNames = append(labelNames, k)
+	labels := map[string]string{}
+	_ = meta.Iter(func(k, v string) error {
+		labels[k] = v
 		return nil
 	})
+
+	var mapped mappedPath
+
+	// Dynamic labels that remain with an empty value are retained, those that
+	// were given a value become static labels.
+	var keepNames []string
+	keepIndexes := []int{}
+	for i, k := range vecLabelNames {
+		v, exists := labels[k]
+		if !exists {
+			m.logger.Tracef("Metrics label '%v' was removed from path '%v'.\n", k, path)
+			continue
+		}
+		if v == "" {
+			keepIndexes = append(keepIndexes, i)
+			keepNames = append(keepNames, k)
+			delete(labels, k)
+		}
+	}
+	if len(keepIndexes) != len(vecLabelNames) {
+		mapped.keepIndexes = keepIndexes
+	}
+
+	var labelNames []string
+	for k := range labels {
+		labelNames = append(labelNames, k)
+	}
 	if len(labelNames) > 0 && !allowLabels {
 		for _, k := range labelNames {
 			m.logger.Tracef("Metrics label '%v' was not created as this metrics target does not support them.\n", k)
@@ -107,23 +222,27 @@ func (m *pathMapping) mapPath(path string, allowLabels bool) (outPath string, la
 	if len(labelNames) > 0 {
 		sort.Strings(labelNames)
 		for _, k := range labelNames {
-			v := meta.Get(k)
+			v := labels[k]
 			m.logger.Tracef("Metrics label '%v' created with static value '%v'.\n", k, v)
-			labelValues = append(labelValues, v)
+			mapped.staticValues = append(mapped.staticValues, v)
 		}
 	}
+	mapped.labelNames = append(labelNames, keepNames...)
 
 	switch t := v.(type) {
 	case query.Delete:
 		m.logger.Tracef("Deleting metrics path: %v\n", path)
-		return "", nil, nil
+		return mappedPath{}
 	case query.Nothing:
 		m.logger.Tracef("Metrics path '%v' registered unchanged.\n", path)
-		return path, labelNames, labelValues
+		mapped.path = path
+		return mapped
 	case string:
 		m.logger.Tracef("Updated metrics path '%v' to: %v\n", path, t)
-		return t, labelNames, labelValues
+		mapped.path = t
+		return mapped
 	}
 	m.logger.Errorf("Path mapping returned invalid result, expected string, found %T\n", v)
-	return path, labelNames, labelValues
+	mapped.path = path
+	return mapped
 }
@@ -92,3 +92,36 @@ func TestPathMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestPathMappingVecLabels(t *testing.T) {
+	m, err := newPathMapping(`root = this.uppercase()
+meta b = deleted()
+meta c = "static"
+meta d = "also static"`, log.Noop())
+	require.NoError(t, err)
+
+	mapped := m.mapPathWithVecLabels("foo", []string{"a", "b", "c"})
+	assert.Equal(t, "FOO", mapped.path)
+	assert.Equal(t, []string{"c", "d", "a"}, mapped.labelNames)
+	assert.False(t, mapped.passthrough())
+	assert.Equal(t, []string{"static", "also static", "first"}, mapped.labelValues([]string{"first", "second", "third"}))
+
+	// Results are cached and must not be modified by callers appending to
+	// them.
+	_ = append(mapped.labelNames, "e")
+	assert.Equal(t, mapped, m.mapPathWithVecLabels("foo", []string{"a", "b", "c"}))
+	assert.Len(t, m.cache, 1)
+
+	mapped = m.mapPathWithVecLabels("foo", []string{"a"})
+	assert.Equal(t, []string{"c", "d", "a"}, mapped.labelNames)
+	assert.Len(t, m.cache, 2)
+
+	m, err = newPathMapping(`root = this`, log.Noop())
+	require.NoError(t, err)
+
+	mapped = m.mapPathWithVecLabels("foo", []string{"a", "b"})
+	assert.Equal(t, "foo", mapped.path)
+	assert.Equal(t, []string{"a", "b"}, mapped.labelNames)
+	assert.True(t, mapped.passthrough())
+	assert.Equal(t, []string{"first", "second"}, mapped.labelValues([]string{"first", "second"}))
+}
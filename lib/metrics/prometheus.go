@@ -3,6 +3,7 @@
 package metrics
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,9 +12,11 @@ import (
 	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/uber/jaeger-client-go"
 )
 
 //------------------------------------------------------------------------------
@@ -66,6 +69,44 @@ func (p *PromTiming) Timing(val int64) error {
 	return nil
 }
 
+// PromHistogramTiming is a representation of a single metric stat that is
+// observed as a histogram in seconds. Interactions with this stat are thread
+// safe.
+type PromHistogramTiming struct {
+	hist      prometheus.Observer
+	exemplars bool
+}
+
+// Timing sets a timing metric.
+func (p *PromHistogramTiming) Timing(val int64) error {
+	p.hist.Observe(float64(val) / float64(time.Second))
+	return nil
+}
+
+// TimingWithSpan sets a timing metric and, when exemplars are enabled and the
+// span has been sampled, attaches the trace ID of the span as an exemplar.
+func (p *PromHistogramTiming) TimingWithSpan(val int64, span opentracing.Span) error {
+	if p.exemplars {
+		if eObs, ok := p.hist.(prometheus.ExemplarObserver); ok {
+			if traceID := sampledTraceID(span); traceID != "" {
+				eObs.ObserveWithExemplar(float64(val)/float64(time.Second), prometheus.Labels{
+					"trace_id": traceID,
+				})
+				return nil
+			}
+		}
+	}
+	return p.Timing(val)
+}
+
+func sampledTraceID(span opentracing.Span) string {
+	spanCtx, ok := span.Context().(jaeger.SpanContext)
+	if !ok || !spanCtx.IsSampled() {
+		return ""
+	}
+	return spanCtx.TraceID().String()
+}
+
 //------------------------------------------------------------------------------
 
 // PromCounterVec creates StatCounters with dynamic labels.
@@ -92,6 +133,21 @@ func (p *PromTimingVec) With(labelValues ...string) StatTimer {
 	}
 }
 
+// PromHistogramTimingVec creates StatTimers observed as histograms with dynamic
+// labels.
+type PromHistogramTimingVec struct {
+	hist      *prometheus.HistogramVec
+	exemplars bool
+}
+
+// With returns a StatTimer with a set of label values.
+func (p *PromHistogramTimingVec) With(labelValues ...string) StatTimer {
+	return &PromHistogramTiming{
+		hist:      p.hist.WithLabelValues(labelValues...),
+		exemplars: p.exemplars,
+	}
+}
+
 // PromGaugeVec creates StatGauges with dynamic labels.
 type PromGaugeVec struct {
 	ctr *prometheus.GaugeVec
@@ -118,11 +174,13 @@ type Prometheus struct {
 	prefix      string
 
 	pusher *push.Pusher
+	pushWG sync.WaitGroup
 	reg    *prometheus.Registry
 
 	counters map[string]*prometheus.CounterVec
 	gauges   map[string]*prometheus.GaugeVec
 	timers   map[string]*prometheus.SummaryVec
+	histos   map[string]*prometheus.HistogramVec
 
 	sync.Mutex
 }
@@ -139,12 +197,20 @@ func NewPrometheus(config Config, opts ...func(Type)) (Type, error) {
 		counters:   map[string]*prometheus.CounterVec{},
 		gauges:     map[string]*prometheus.GaugeVec{},
 		timers:     map[string]*prometheus.SummaryVec{},
+		histos:     map[string]*prometheus.HistogramVec{},
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
 
+	if p.config.AddExemplars && !p.config.UseHistogramTiming {
+		return nil, errors.New("exemplars can only be added when use_histogram_timing is enabled")
+	}
+	if len(p.config.HistogramBuckets) == 0 {
+		p.config.HistogramBuckets = prometheus.DefBuckets
+	}
+
 	// TODO: Maybe disable this with a config flag.
 	if err := p.reg.Register(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{})); err != nil {
 		return nil, err
@@ -170,13 +236,15 @@ func NewPrometheus(config Config, opts ...func(Type)) (Type, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse push interval: %v", err)
 			}
+			p.pushWG.Add(1)
 			go func() {
+				defer p.pushWG.Done()
 				for {
 					select {
 					case <-p.closedChan:
 						return
 					case <-time.After(interval):
-						if err = p.pusher.Push(); err != nil {
+						if err := p.pusher.Push(); err != nil {
 							p.log.Errorf("Failed to push metrics: %v\n", err)
 						}
 					}
@@ -193,16 +261,26 @@ func NewPrometheus(config Config, opts ...func(Type)) (Type, error) {
 // HandlerFunc returns an http.HandlerFunc for scraping metrics.
 func (p *Prometheus) HandlerFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		promhttp.HandlerFor(p.reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+		promhttp.HandlerFor(p.reg, promhttp.HandlerOpts{
+			EnableOpenMetrics: p.config.AddExemplars,
+		}).ServeHTTP(w, r)
 	}
 }
 
 //------------------------------------------------------------------------------
 
-func (p *Prometheus) toPromName(dotSepName string) (outPath string, labelNames, labelValues []string) {
+func toPromPath(dotSepName string) string {
 	dotSepName = strings.ReplaceAll(dotSepName, "_", "__")
 	dotSepName = strings.ReplaceAll(dotSepName, "-", "__")
-	return p.pathMapping.mapPathWithTags(strings.ReplaceAll(dotSepName, ".", "_"))
+	return strings.ReplaceAll(dotSepName, ".", "_")
+}
+
+func (p *Prometheus) toPromName(dotSepName string) (outPath string, labelNames, labelValues []string) {
+	return p.pathMapping.mapPathWithTags(toPromPath(dotSepName))
+}
+
+func (p *Prometheus) toPromVecName(dotSepName string, labelNames []string) mappedPath {
+	return p.pathMapping.mapPathWithVecLabels(toPromPath(dotSepName), labelNames)
 }
 
 // GetCounter returns a stat counter object for a path.
@@ -239,6 +317,18 @@ func (p *Prometheus) GetTimer(path string) StatTimer {
 		return DudStat{}
 	}
 
+	if p.config.UseHistogramTiming {
+		if IsSizeTimer(path) {
+			return &PromTiming{
+				sum: p.getHistogramVec(stat, labels, promSizeBuckets).WithLabelValues(values...),
+			}
+		}
+		return &PromHistogramTiming{
+			hist:      p.getHistogramVec(stat, labels, p.config.HistogramBuckets).WithLabelValues(values...),
+			exemplars: p.config.AddExemplars,
+		}
+	}
+
 	var tmr *prometheus.SummaryVec
 
 	p.Lock()
@@ -260,6 +350,28 @@ func (p *Prometheus) GetTimer(path string) StatTimer {
 	}
 }
 
+// promSizeBuckets are the histogram buckets of size timers, which range from
+// 64 bytes to 16 megabytes.
+var promSizeBuckets = prometheus.ExponentialBuckets(64, 4, 10)
+
+func (p *Prometheus) getHistogramVec(stat string, labelNames []string, buckets []float64) *prometheus.HistogramVec {
+	p.Lock()
+	defer p.Unlock()
+
+	hist, exists := p.histos[stat]
+	if !exists {
+		hist = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: p.prefix,
+			Name:      stat,
+			Help:      "Benthos Timing metric",
+			Buckets:   buckets,
+		}, labelNames)
+		p.reg.MustRegister(hist)
+		p.histos[stat] = hist
+	}
+	return hist
+}
+
 // GetGauge returns a stat gauge object for a path.
 func (p *Prometheus) GetGauge(path string) StatGauge {
 	stat, labels, values := p.toPromName(path)
@@ -291,38 +403,33 @@ func (p *Prometheus) GetGauge(path string) StatGauge {
 // these labels must be consistent with any other metrics registered on the same
 // path.
 func (p *Prometheus) GetCounterVec(path string, labelNames []string) StatCounterVec {
-	stat, labels, values := p.toPromName(path)
-	if stat == "" {
+	mapped := p.toPromVecName(path, labelNames)
+	if mapped.path == "" {
 		return fakeCounterVec(func([]string) StatCounter {
 			return DudStat{}
 		})
 	}
-	if len(labels) > 0 {
-		labelNames = append(labels, labelNames...)
-	}
 
 	var ctr *prometheus.CounterVec
 
 	p.Lock()
 	var exists bool
-	if ctr, exists = p.counters[stat]; !exists {
+	if ctr, exists = p.counters[mapped.path]; !exists {
 		ctr = prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: p.prefix,
-			Name:      stat,
+			Name:      mapped.path,
 			Help:      "Benthos Counter metric",
-		}, labelNames)
+		}, mapped.labelNames)
 		p.reg.MustRegister(ctr)
-		p.counters[stat] = ctr
+		p.counters[mapped.path] = ctr
 	}
 	p.Unlock()
 
-	if len(labels) > 0 {
+	if !mapped.passthrough() {
 		return fakeCounterVec(func(vs []string) StatCounter {
-			fvs := append([]string{}, values...)
-			fvs = append(fvs, vs...)
 			return (&PromCounterVec{
 				ctr: ctr,
-			}).With(fvs...)
+			}).With(mapped.labelValues(vs)...)
 		})
 	}
 	return &PromCounterVec{
@@ -334,39 +441,58 @@ func (p *Prometheus) GetCounterVec(path string, labelNames []string) StatCounter
 // these labels must be consistent with any other metrics registered on the same
 // path.
 func (p *Prometheus) GetTimerVec(path string, labelNames []string) StatTimerVec {
-	stat, labels, values := p.toPromName(path)
-	if stat == "" {
+	mapped := p.toPromVecName(path, labelNames)
+	if mapped.path == "" {
 		return fakeTimerVec(func([]string) StatTimer {
 			return DudStat{}
 		})
 	}
-	if len(labels) > 0 {
-		labelNames = append(labels, labelNames...)
+
+	if p.config.UseHistogramTiming && IsSizeTimer(path) {
+		hist := p.getHistogramVec(mapped.path, mapped.labelNames, promSizeBuckets)
+		return fakeTimerVec(func(vs []string) StatTimer {
+			return &PromTiming{
+				sum: hist.WithLabelValues(mapped.labelValues(vs)...),
+			}
+		})
+	}
+	if p.config.UseHistogramTiming {
+		hist := p.getHistogramVec(mapped.path, mapped.labelNames, p.config.HistogramBuckets)
+		if !mapped.passthrough() {
+			return fakeTimerVec(func(vs []string) StatTimer {
+				return (&PromHistogramTimingVec{
+					hist:      hist,
+					exemplars: p.config.AddExemplars,
+				}).With(mapped.labelValues(vs)...)
+			})
+		}
+		return &PromHistogramTimingVec{
+			hist:      hist,
+			exemplars: p.config.AddExemplars,
+		}
 	}
 
 	var tmr *prometheus.SummaryVec
 
 	p.Lock()
 	var exists bool
-	if tmr, exists = p.timers[stat]; !exists {
+	if tmr, exists = p.timers[mapped.path]; !exists {
 		tmr = prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Namespace:  p.prefix,
-			Name:       stat,
+			Name:       mapped.path,
 			Help:       "Benthos Timing metric",
 			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
-		}, labelNames)
+		}, mapped.labelNames)
 		p.reg.MustRegister(tmr)
-		p.timers[stat] = tmr
+		p.timers[mapped.path] = tmr
 	}
 	p.Unlock()
 
-	if len(labels) > 0 {
+	if !mapped.passthrough() {
 		return fakeTimerVec(func(vs []string) StatTimer {
-			fvs := append([]string{}, values...)
-			fvs = append(fvs, vs...)
 			return (&PromTimingVec{
 				sum: tmr,
-			}).With(fvs...)
+			}).With(mapped.labelValues(vs)...)
 		})
 	}
 	return &PromTimingVec{
@@ -378,38 +504,33 @@ func (p *Prometheus) GetTimerVec(path string, labelNames []string) StatTimerVec
 // these labels must be consistent with any other metrics registered on the same
 // path.
 func (p *Prometheus) GetGaugeVec(path string, labelNames []string) StatGaugeVec {
-	stat, labels, values := p.toPromName(path)
-	if stat == "" {
+	mapped := p.toPromVecName(path, labelNames)
+	if mapped.path == "" {
 		return fakeGaugeVec(func([]string) StatGauge {
 			return DudStat{}
 		})
 	}
-	if len(labels) > 0 {
-		labelNames = append(labels, labelNames...)
-	}
 
 	var ctr *prometheus.GaugeVec
 
 	p.Lock()
 	var exists bool
-	if ctr, exists = p.gauges[stat]; !exists {
+	if ctr, exists = p.gauges[mapped.path]; !exists {
 		ctr = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: p.prefix,
-			Name:      stat,
+			Name:      mapped.path,
 			Help:      "Benthos Gauge metric",
-		}, labelNames)
+		}, mapped.labelNames)
 		p.reg.MustRegister(ctr)
-		p.gauges[stat] = ctr
+		p.gauges[mapped.path] = ctr
 	}
 	p.Unlock()
 
-	if len(labels) > 0 {
+	if !mapped.passthrough() {
 		return fakeGaugeVec(func(vs []string) StatGauge {
-			fvs := append([]string{}, values...)
-			fvs = append(fvs, vs...)
 			return (&PromGaugeVec{
 				ctr: ctr,
-			}).With(fvs...)
+			}).With(mapped.labelValues(vs)...)
 		})
 	}
 	return &PromGaugeVec{
@@ -422,13 +543,17 @@ func (p *Prometheus) SetLogger(log log.Modular) {
 	p.log = log
 }
 
-// Close stops the Prometheus object from aggregating metrics and cleans up
-// resources.
+// Close stops the Prometheus object from aggregating metrics, pushes the final
+// values to the push gateway when configured and cleans up resources.
 func (p *Prometheus) Close() error {
-	if atomic.CompareAndSwapInt32(&p.running, 1, 0) {
-		close(p.closedChan)
+	if !atomic.CompareAndSwapInt32(&p.running, 1, 0) {
+		return nil
 	}
+	close(p.closedChan)
 	if p.pusher != nil {
+		// Wait for any push in progress to finish before pushing the final
+		// values.
+		p.pushWG.Wait()
 		return p.pusher.Push()
 	}
 	return nil
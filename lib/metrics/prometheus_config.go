@@ -14,6 +14,9 @@ Metrics paths will differ from [the standard list](/docs/components/metrics/abou
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon("prefix", "A string prefix to add to all metrics."),
 			pathMappingDocs(true, true),
+			docs.FieldAdvanced("use_histogram_timing", "Whether to export timing metrics as a histogram, if `false` a summary is used instead. When exporting histogram timings the delta values are converted from nanoseconds into seconds in order to better fit within bucket definitions. The exception is `message_size` metrics, which are exported as histograms of sizes in bytes with exponential buckets from 64 bytes to 16 megabytes. For more information on histograms and summaries refer to: https://prometheus.io/docs/practices/histograms/.").AtVersion("3.51.0"),
+			docs.FieldAdvanced("histogram_buckets", "Timing metrics histogram buckets (in seconds). If left empty defaults to DefBuckets (https://pkg.go.dev/github.com/prometheus/client_golang/prometheus#pkg-variables).").Array().HasType(docs.FieldTypeFloat).AtVersion("3.51.0"),
+			docs.FieldAdvanced("add_exemplars", "Whether to attach the trace ID of the current span as an [exemplar](#exemplars) to histogram timing observations. Requires `use_histogram_timing` to be `true`.").AtVersion("3.51.0"),
 			docs.FieldAdvanced("push_url", "An optional [Push Gateway URL](#push-gateway) to push metrics to."),
 			docs.FieldAdvanced("push_interval", "The period of time between each push when sending metrics to a Push Gateway."),
 			docs.FieldAdvanced("push_job_name", "An identifier for push jobs."),
@@ -34,7 +37,21 @@ The Push Gateway is useful for when Benthos instances are short lived. Do not
 include the "/metrics/jobs/..." path in the push URL.
 
 If the Push Gateway requires HTTP Basic Authentication it can be configured with
-` + "`push_basic_auth`.",
+` + "`push_basic_auth`" + `.
+
+## Exemplars
+
+When both ` + "`use_histogram_timing`" + ` and ` + "`add_exemplars`" + ` are set
+to ` + "`true`" + ` the trace ID of sampled spans is attached as an
+[OpenMetrics exemplar](https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md#exemplars)
+with the label ` + "`trace_id`" + ` to timing observations where a span is
+available, such as the ` + "`output_batch_latency`" + ` metric. This allows you
+to jump from a metric straight to the trace of a request that contributed to it.
+
+Exemplars are only exposed when the metrics endpoint is scraped using the
+OpenMetrics format, which needs to be enabled on your Prometheus server with the
+feature flag ` + "`--enable-feature=exemplar-storage`" + `. A
+[tracer](/docs/components/tracers/about) must also be configured.`,
 	}
 }
 
@@ -42,12 +59,15 @@ If the Push Gateway requires HTTP Basic Authentication it can be configured with
 
 // PrometheusConfig is config for the Prometheus metrics type.
 type PrometheusConfig struct {
-	Prefix        string                        `json:"prefix" yaml:"prefix"`
-	PathMapping   string                        `json:"path_mapping" yaml:"path_mapping"`
-	PushURL       string                        `json:"push_url" yaml:"push_url"`
-	PushBasicAuth PrometheusPushBasicAuthConfig `json:"push_basic_auth" yaml:"push_basic_auth"`
-	PushInterval  string                        `json:"push_interval" yaml:"push_interval"`
-	PushJobName   string                        `json:"push_job_name" yaml:"push_job_name"`
+	Prefix             string                        `json:"prefix" yaml:"prefix"`
+	PathMapping        string                        `json:"path_mapping" yaml:"path_mapping"`
+	UseHistogramTiming bool                          `json:"use_histogram_timing" yaml:"use_histogram_timing"`
+	HistogramBuckets   []float64                     `json:"histogram_buckets" yaml:"histogram_buckets"`
+	AddExemplars       bool                          `json:"add_exemplars" yaml:"add_exemplars"`
+	PushURL            string                        `json:"push_url" yaml:"push_url"`
+	PushBasicAuth      PrometheusPushBasicAuthConfig `json:"push_basic_auth" yaml:"push_basic_auth"`
+	PushInterval       string                        `json:"push_interval" yaml:"push_interval"`
+	PushJobName        string                        `json:"push_job_name" yaml:"push_job_name"`
 }
 
 // PrometheusPushBasicAuthConfig contains parameters for establishing basic
@@ -68,12 +88,15 @@ func NewPrometheusPushBasicAuthConfig() PrometheusPushBasicAuthConfig {
 // NewPrometheusConfig creates an PrometheusConfig struct with default values.
 func NewPrometheusConfig() PrometheusConfig {
 	return PrometheusConfig{
-		Prefix:        "benthos",
-		PathMapping:   "",
-		PushURL:       "",
-		PushBasicAuth: NewPrometheusPushBasicAuthConfig(),
-		PushInterval:  "",
-		PushJobName:   "benthos_push",
+		Prefix:             "benthos",
+		PathMapping:        "",
+		UseHistogramTiming: false,
+		HistogramBuckets:   []float64{},
+		AddExemplars:       false,
+		PushURL:            "",
+		PushBasicAuth:      NewPrometheusPushBasicAuthConfig(),
+		PushInterval:       "",
+		PushJobName:        "benthos_push",
 	}
 }
 
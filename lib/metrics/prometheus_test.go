@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
 )
 
 func TestPrometheusNoPushGateway(t *testing.T) {
@@ -147,3 +148,138 @@ func TestPrometheusMetrics(t *testing.T) {
 	assert.Contains(t, body, "\ngaugetwo{label2=\"value3\"} 12")
 	assert.Contains(t, body, "\ntimertwo_sum{label3=\"value4\",label4=\"value5\"} 13")
 }
+
+func TestPrometheusPathMappingVecLabels(t *testing.T) {
+	conf := NewConfig()
+	conf.Prometheus.Prefix = ""
+	conf.Prometheus.PathMapping = `meta message_id = deleted()
+meta source = if this == "countertwo" { "static" }`
+	conf.Type = TypePrometheus
+
+	prom, err := New(conf)
+	require.NoError(t, err)
+
+	wHandler, ok := prom.(WithHandlerFunc)
+	require.True(t, ok)
+
+	ctr := prom.GetCounterVec("counterone", []string{"topic", "message_id"})
+	ctr.With("foo", "1").Incr(1)
+	ctr.With("foo", "2").Incr(2)
+	ctr.With("bar", "3").Incr(3)
+
+	ctrTwo := prom.GetCounterVec("countertwo", []string{"message_id", "source"})
+	ctrTwo.With("1", "a").Incr(4)
+	ctrTwo.With("2", "b").Incr(5)
+
+	ggeTwo := prom.GetGaugeVec("gaugeone", []string{"message_id"})
+	ggeTwo.With("1").Set(6)
+
+	tmr := prom.GetTimerVec("timerone", []string{"message_id", "topic"})
+	tmr.With("1", "baz").Timing(7)
+
+	body := getPage(t, wHandler.HandlerFunc())
+
+	assert.Contains(t, body, "\ncounterone{topic=\"foo\"} 3")
+	assert.Contains(t, body, "\ncounterone{topic=\"bar\"} 3")
+	assert.Contains(t, body, "\ncountertwo{source=\"static\"} 9")
+	assert.Contains(t, body, "\ngaugeone 6")
+	assert.Contains(t, body, "\ntimerone_sum{topic=\"baz\"} 7")
+	assert.NotContains(t, body, "message_id")
+}
+
+func TestPrometheusHistogramTimings(t *testing.T) {
+	conf := NewConfig()
+	conf.Prometheus.Prefix = ""
+	conf.Prometheus.UseHistogramTiming = true
+	conf.Prometheus.HistogramBuckets = []float64{0.5, 1}
+	conf.Type = TypePrometheus
+
+	prom, err := New(conf)
+	require.NoError(t, err)
+
+	handler := prom.(WithHandlerFunc).HandlerFunc()
+
+	tmr := prom.GetTimer("timerone")
+	tmr.Timing(int64(time.Second * 2))
+
+	tmrTwo := prom.GetTimerVec("timertwo", []string{"label1"})
+	tmrTwo.With("value1").Timing(int64(time.Millisecond * 100))
+
+	body := getPage(t, handler)
+
+	assert.Contains(t, body, "\ntimerone_bucket{le=\"0.5\"} 0")
+	assert.Contains(t, body, "\ntimerone_bucket{le=\"+Inf\"} 1")
+	assert.Contains(t, body, "\ntimerone_sum 2")
+	assert.Contains(t, body, "\ntimertwo_bucket{label1=\"value1\",le=\"0.5\"} 1")
+	assert.Contains(t, body, "\ntimertwo_sum{label1=\"value1\"} 0.1")
+}
+
+func TestPrometheusHistogramSizes(t *testing.T) {
+	conf := NewConfig()
+	conf.Prometheus.Prefix = ""
+	conf.Prometheus.UseHistogramTiming = true
+	conf.Prometheus.HistogramBuckets = []float64{0.5, 1}
+	conf.Type = TypePrometheus
+
+	prom, err := New(conf)
+	require.NoError(t, err)
+
+	handler := prom.(WithHandlerFunc).HandlerFunc()
+
+	tmr := prom.GetTimer("input.message_size")
+	tmr.Timing(1000000)
+
+	tmrTwo := prom.GetTimerVec("output.message_size", []string{"label1"})
+	tmrTwo.With("value1").Timing(100)
+
+	body := getPage(t, handler)
+
+	assert.Contains(t, body, "\ninput_message__size_bucket{le=\"262144\"} 0")
+	assert.Contains(t, body, "\ninput_message__size_bucket{le=\"1.048576e+06\"} 1")
+	assert.Contains(t, body, "\ninput_message__size_sum 1e+06")
+	assert.Contains(t, body, "\noutput_message__size_bucket{label1=\"value1\",le=\"256\"} 1")
+	assert.Contains(t, body, "\noutput_message__size_sum{label1=\"value1\"} 100")
+}
+
+func TestPrometheusExemplarsNoHistogram(t *testing.T) {
+	conf := NewConfig()
+	conf.Prometheus.AddExemplars = true
+
+	_, err := NewPrometheus(conf)
+	require.EqualError(t, err, "exemplars can only be added when use_histogram_timing is enabled")
+}
+
+func TestPrometheusExemplars(t *testing.T) {
+	conf := NewConfig()
+	conf.Prometheus.Prefix = ""
+	conf.Prometheus.UseHistogramTiming = true
+	conf.Prometheus.HistogramBuckets = []float64{1}
+	conf.Prometheus.AddExemplars = true
+	conf.Type = TypePrometheus
+
+	prom, err := New(conf)
+	require.NoError(t, err)
+
+	handler := prom.(WithHandlerFunc).HandlerFunc()
+
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer closer.Close()
+
+	span := tracer.StartSpan("foo")
+	defer span.Finish()
+
+	traceID := span.Context().(jaeger.SpanContext).TraceID().String()
+
+	tmr := prom.GetTimer("timerone")
+	require.NoError(t, TimingWithSpan(tmr, int64(time.Millisecond*100), span))
+
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	body, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "\ntimerone_bucket{le=\"1.0\"} 1 # {trace_id=\""+traceID+"\"} 0.1")
+}
@@ -272,11 +272,10 @@ func (h *Statsd) SetLogger(log log.Modular) {
 	h.log = log
 }
 
-// Close stops the Statsd object from aggregating metrics and cleans up
-// resources.
+// Close stops the Statsd object from aggregating metrics, flushes any buffered
+// metrics and cleans up resources.
 func (h *Statsd) Close() error {
-	h.s.Close()
-	return nil
+	return h.s.Close()
 }
 
 // tags merges tag labels with their interpolated values
@@ -209,11 +209,10 @@ func (h *StatsdLegacy) SetLogger(log log.Modular) {
 	h.log = log
 }
 
-// Close stops the StatsdLegacy object from aggregating metrics and cleans up
-// resources.
+// Close stops the StatsdLegacy object from aggregating metrics, flushes any
+// buffered metrics and cleans up resources.
 func (h *StatsdLegacy) Close() error {
-	h.s.Close()
-	return nil
+	return h.s.Close()
 }
 
 //------------------------------------------------------------------------------
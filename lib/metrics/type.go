@@ -2,8 +2,10 @@ package metrics
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/opentracing/opentracing-go"
 )
 
 //------------------------------------------------------------------------------
@@ -22,6 +24,30 @@ type StatTimer interface {
 	Timing(delta int64) error
 }
 
+// IsSizeTimer returns true when the path of a timer metric ends with
+// `message_size`, which are timers where each observation is a size in bytes
+// rather than a duration in nanoseconds. Metrics targets that convert timing
+// observations into another unit of time record these observations unchanged.
+func IsSizeTimer(path string) bool {
+	return path == "message_size" || strings.HasSuffix(path, ".message_size")
+}
+
+// StatTimerWithSpan is an optional interface implemented by StatTimers that are
+// able to link a timing observation to the trace of a span.
+type StatTimerWithSpan interface {
+	// TimingWithSpan sets a timing metric and links it to the trace of a span.
+	TimingWithSpan(delta int64, span opentracing.Span) error
+}
+
+// TimingWithSpan sets a timing metric and, if the timer supports it, links the
+// observation to the trace of the provided span.
+func TimingWithSpan(t StatTimer, delta int64, span opentracing.Span) error {
+	if ts, ok := t.(StatTimerWithSpan); ok && span != nil {
+		return ts.TimingWithSpan(delta, span)
+	}
+	return t.Timing(delta)
+}
+
 // StatGauge is a representation of a single gauge metric stat. Interactions
 // with this stat are thread safe.
 type StatGauge interface {
@@ -86,7 +112,8 @@ type Type interface {
 	// SetLogger sets the logging mechanism of the metrics type.
 	SetLogger(log log.Modular)
 
-	// Close stops aggregating stats and cleans up resources.
+	// Close stops aggregating stats, flushes any pending stats to the
+	// exporter, blocking until they are sent, and cleans up resources.
 	Close() error
 }
 
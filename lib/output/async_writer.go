@@ -37,20 +37,37 @@ type AsyncSink interface {
 	types.Closable
 }
 
+// AsyncHealthChecker is an optional interface that an AsyncSink can implement
+// in order to actively verify that its destination is reachable, rather than
+// only discovering a lost connection when a write fails. While the most recent
+// check has failed the output reports that it is not connected.
+type AsyncHealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// asyncHealthCheckPeriod is the interval between health checks of sinks that
+// implement AsyncHealthChecker.
+var asyncHealthCheckPeriod = time.Second * 5
+
 // AsyncWriter is an output type that writes messages to a writer.Type.
 type AsyncWriter struct {
 	isConnected int32
+	isHealthy   int32
 
-	typeStr     string
-	maxInflight int
-	noCancel    bool
-	writer      AsyncSink
+	typeStr                  string
+	maxInflight              int
+	noCancel                 bool
+	preserveOrder            bool
+	preserveOrderBackoffCtor func() backoff.BackOff
+	writer                   AsyncSink
 
 	injectTracingMap *mapping.Executor
 
 	log   log.Modular
 	stats metrics.Type
 
+	mInFlight metrics.StatGauge
+
 	transactions <-chan types.Transaction
 
 	shutSig *shutdown.Signaller
@@ -70,7 +87,9 @@ func NewAsyncWriter(
 		writer:       w,
 		log:          log,
 		stats:        stats,
+		mInFlight:    stats.GetGauge("batch.in_flight"),
 		transactions: nil,
+		isHealthy:    1,
 		shutSig:      shutdown.NewSignaller(),
 	}
 	return aWriter, nil
@@ -93,9 +112,25 @@ func (w *AsyncWriter) SetNoCancel() {
 	w.noCancel = true
 }
 
+// SetPreserveOrder configures the async writer so that messages are delivered
+// in the order that they were received. Only one message is written at a time
+// regardless of the max in flight, and failed writes are retried until they
+// succeed rather than propagating the error upstream, as a nack would allow
+// subsequent messages to be delivered before the failed message is resent. The
+// period between retries is determined by backoffs created with backoffCtor,
+// and retries continue after a backoff stops, at its final interval.
+func (w *AsyncWriter) SetPreserveOrder(backoffCtor func() backoff.BackOff) {
+	w.preserveOrder = true
+	w.preserveOrderBackoffCtor = backoffCtor
+	w.maxInflight = 1
+}
+
 //------------------------------------------------------------------------------
 
 func (w *AsyncWriter) latencyMeasuringWrite(msg types.Message) (latencyNs int64, err error) {
+	w.mInFlight.Incr(1)
+	defer w.mInFlight.Decr(1)
+
 	t0 := time.Now()
 	var ctx context.Context
 	if w.noCancel {
@@ -110,6 +145,25 @@ func (w *AsyncWriter) latencyMeasuringWrite(msg types.Message) (latencyNs int64,
 	return latencyNs, err
 }
 
+// recordLatency sets a latency timing metric, linking the observation to the
+// trace of the first message of a batch where supported.
+func recordLatency(mLatency metrics.StatTimer, latency int64, spans []opentracing.Span) {
+	if len(spans) > 0 {
+		metrics.TimingWithSpan(mLatency, latency, spans[0])
+		return
+	}
+	mLatency.Timing(latency)
+}
+
+// recordMessageSizes adds the size in bytes of each message of a batch to a
+// size distribution metric.
+func recordMessageSizes(mSize metrics.StatTimer, msg types.Message) {
+	msg.Iter(func(i int, p types.Part) error {
+		mSize.Timing(int64(len(p.Get())))
+		return nil
+	})
+}
+
 func (w *AsyncWriter) injectSpans(msg types.Message, spans []opentracing.Span) types.Message {
 	if w.injectTracingMap == nil || msg.Len() > len(spans) {
 		return msg
@@ -153,6 +207,42 @@ func (w *AsyncWriter) injectSpans(msg types.Message, spans []opentracing.Span) t
 	return newMsg
 }
 
+// healthCheckLoop periodically checks the health of the destination of a sink
+// until the writer is closed.
+func (w *AsyncWriter) healthCheckLoop(checker AsyncHealthChecker) {
+	mHealthy := w.stats.GetGauge("connection.healthy")
+	mHealthy.Set(1)
+
+	for {
+		select {
+		case <-time.After(asyncHealthCheckPeriod):
+		case <-w.shutSig.CloseAtLeisureChan():
+			return
+		}
+
+		ctx, done := w.shutSig.CloseAtLeisureCtx(context.Background())
+		ctx, cancel := context.WithTimeout(ctx, asyncHealthCheckPeriod)
+		err := checker.HealthCheck(ctx)
+		cancel()
+		done()
+
+		if err != nil {
+			if w.shutSig.ShouldCloseAtLeisure() {
+				return
+			}
+			if atomic.SwapInt32(&w.isHealthy, 0) == 1 {
+				w.log.Warnf("Health check of %v failed: %v\n", w.typeStr, err)
+			}
+			mHealthy.Set(0)
+		} else {
+			if atomic.SwapInt32(&w.isHealthy, 1) == 0 {
+				w.log.Infof("Health check of %v recovered\n", w.typeStr)
+			}
+			mHealthy.Set(1)
+		}
+	}
+}
+
 // loop is an internal loop that brokers incoming messages to output pipe.
 func (w *AsyncWriter) loop() {
 	// Metrics paths
@@ -160,11 +250,16 @@ func (w *AsyncWriter) loop() {
 		mCount      = w.stats.GetCounter("count")
 		mPartsSent  = w.stats.GetCounter("sent")
 		mSent       = w.stats.GetCounter("batch.sent")
+		mPartsAcked = w.stats.GetCounter("acked")
+		mAcked      = w.stats.GetCounter("batch.acked")
 		mBytesSent  = w.stats.GetCounter("batch.bytes")
 		mLatency    = w.stats.GetTimer("batch.latency")
+		mPartsError = w.stats.GetCounter("error")
+		mError      = w.stats.GetCounter("batch.error")
 		mConn       = w.stats.GetCounter("connection.up")
 		mFailedConn = w.stats.GetCounter("connection.failed")
 		mLostConn   = w.stats.GetCounter("connection.lost")
+		mSize       = w.stats.GetTimer("message_size")
 	)
 
 	defer func() {
@@ -212,6 +307,14 @@ func (w *AsyncWriter) loop() {
 	wg := sync.WaitGroup{}
 	wg.Add(w.maxInflight)
 
+	if checker, ok := w.writer.(AsyncHealthChecker); ok {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.healthCheckLoop(checker)
+		}()
+	}
+
 	connectMut := sync.Mutex{}
 	connectLoop := func(msg types.Message) (latency int64, err error) {
 		atomic.StoreInt32(&w.isConnected, 0)
@@ -254,12 +357,15 @@ func (w *AsyncWriter) loop() {
 					return
 				}
 				mCount.Incr(1)
+				recordMessageSizes(mSize, ts.Payload)
 			case <-w.shutSig.CloseAtLeisureChan():
 				return
 			}
 
 			w.log.Tracef("Attempting to write %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
 			spans := tracing.CreateChildSpans("output_"+w.typeStr, ts.Payload)
+			mSent.Incr(1)
+			mPartsSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 			ts.Payload = w.injectSpans(ts.Payload, spans)
 
 			latency, err := w.latencyMeasuringWrite(ts.Payload)
@@ -269,6 +375,39 @@ func (w *AsyncWriter) loop() {
 				latency, err = connectLoop(ts.Payload)
 			}
 
+			// When preserving order we retry until success, as propagating
+			// the error would allow later messages to overtake this one.
+			if w.preserveOrder && err != nil && err != types.ErrTypeClosed {
+				mError.Incr(1)
+				mPartsError.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
+
+				retryBackoff := w.preserveOrderBackoffCtor()
+				var lastBackoff time.Duration
+				for err != nil && err != types.ErrTypeClosed {
+					w.log.Errorf("Failed to send message to %v, retrying in order to preserve ordering: %v\n", w.typeStr, err)
+					if nextBackoff := retryBackoff.NextBackOff(); nextBackoff != backoff.Stop {
+						lastBackoff = nextBackoff
+					}
+					select {
+					case <-time.After(lastBackoff):
+					case <-w.shutSig.CloseAtLeisureChan():
+						for _, s := range spans {
+							s.Finish()
+						}
+						// Propagate the failure so that the pending message
+						// isn't lost with the shutdown.
+						select {
+						case ts.ResponseChan <- response.NewError(err):
+						case <-w.shutSig.CloseNowChan():
+						}
+						return
+					}
+					if latency, err = w.latencyMeasuringWrite(ts.Payload); err == types.ErrNotConnected {
+						latency, err = connectLoop(ts.Payload)
+					}
+				}
+			}
+
 			// Close immediately if our writer is closed.
 			if err == types.ErrTypeClosed {
 				return
@@ -282,11 +421,13 @@ func (w *AsyncWriter) loop() {
 				} else {
 					w.log.Debugf("Rejecting message: %v\n", err)
 				}
+				mError.Incr(1)
+				mPartsError.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 			} else {
-				mSent.Incr(1)
-				mPartsSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
+				mAcked.Incr(1)
+				mPartsAcked.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 				mBytesSent.Incr(int64(message.GetAllBytesLen(ts.Payload)))
-				mLatency.Timing(latency)
+				recordLatency(mLatency, latency, spans)
 				w.log.Tracef("Successfully wrote %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
 			}
 
@@ -294,6 +435,14 @@ func (w *AsyncWriter) loop() {
 				s.Finish()
 			}
 
+			// Prefer delivering the response when the receiver is ready, as
+			// during shutdown both cases are otherwise chosen at random and
+			// rejected messages would not be reported to the input.
+			select {
+			case ts.ResponseChan <- response.NewError(err):
+				continue
+			default:
+			}
 			select {
 			case ts.ResponseChan <- response.NewError(err):
 			case <-w.shutSig.CloseAtLeisureChan():
@@ -319,9 +468,10 @@ func (w *AsyncWriter) Consume(ts <-chan types.Transaction) error {
 }
 
 // Connected returns a boolean indicating whether this output is currently
-// connected to its target.
+// connected to its target, and that its most recent health check (if
+// supported) succeeded.
 func (w *AsyncWriter) Connected() bool {
-	return atomic.LoadInt32(&w.isConnected) == 1
+	return atomic.LoadInt32(&w.isConnected) == 1 && atomic.LoadInt32(&w.isHealthy) == 1
 }
 
 // MaxInFlight returns the maximum number of in flight messages permitted by the
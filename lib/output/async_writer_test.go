@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //------------------------------------------------------------------------------
@@ -635,3 +640,239 @@ func TestAsyncWriterSadPath(t *testing.T) {
 }
 
 //------------------------------------------------------------------------------
+
+func TestAsyncWriterMetrics(t *testing.T) {
+	t.Parallel()
+
+	writerImpl := newMockWriter()
+	stats := metrics.NewLocal()
+
+	w, err := NewAsyncWriter("foo", 1, writerImpl, log.Noop(), stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgChan := make(chan types.Transaction)
+	resChan := make(chan types.Response)
+
+	if err = w.Consume(msgChan); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case writerImpl.connChan <- nil:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out")
+	}
+
+	for _, expErr := range []error{nil, errors.New("nope")} {
+		select {
+		case msgChan <- types.NewTransaction(message.New([][]byte{[]byte("foo"), []byte("bar")}), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out")
+		}
+		select {
+		case writerImpl.writeChan <- expErr:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out")
+		}
+		select {
+		case res := <-resChan:
+			if actErr := res.Error(); expErr != actErr {
+				t.Errorf("Wrong response: %v != %v", actErr, expErr)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out")
+		}
+	}
+
+	w.CloseAsync()
+	if err = w.WaitForClose(time.Second); err != nil {
+		t.Error(err)
+	}
+
+	exp := map[string]int64{
+		"count":             2,
+		"sent":              4,
+		"batch.sent":        2,
+		"acked":             2,
+		"batch.acked":       1,
+		"batch.bytes":       6,
+		"error":             2,
+		"batch.error":       1,
+		"batch.in_flight":   0,
+		"connection.up":     1,
+		"connection.failed": 0,
+		"connection.lost":   0,
+	}
+	if act := stats.GetCounters(); !reflect.DeepEqual(exp, act) {
+		t.Errorf("Wrong metrics: %v != %v", act, exp)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type mockHealthCheckWriter struct {
+	healthErr atomic.Value
+}
+
+func (w *mockHealthCheckWriter) ConnectWithContext(ctx context.Context) error {
+	return nil
+}
+func (w *mockHealthCheckWriter) WriteWithContext(ctx context.Context, msg types.Message) error {
+	return nil
+}
+func (w *mockHealthCheckWriter) HealthCheck(ctx context.Context) error {
+	return w.healthErr.Load().(healthErr).error
+}
+func (w *mockHealthCheckWriter) CloseAsync() {}
+func (w *mockHealthCheckWriter) WaitForClose(time.Duration) error {
+	return nil
+}
+
+type healthErr struct{ error }
+
+func TestAsyncWriterHealthCheck(t *testing.T) {
+	defer func(p time.Duration) {
+		asyncHealthCheckPeriod = p
+	}(asyncHealthCheckPeriod)
+	asyncHealthCheckPeriod = time.Millisecond * 10
+
+	writerImpl := &mockHealthCheckWriter{}
+	writerImpl.healthErr.Store(healthErr{})
+
+	w, err := NewAsyncWriter("foo", 1, writerImpl, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, w.Consume(make(chan types.Transaction)))
+
+	assert.Eventually(t, w.Connected, time.Second, time.Millisecond*5)
+
+	writerImpl.healthErr.Store(healthErr{errors.New("unreachable")})
+	assert.Eventually(t, func() bool {
+		return !w.Connected()
+	}, time.Second, time.Millisecond*5)
+
+	writerImpl.healthErr.Store(healthErr{})
+	assert.Eventually(t, w.Connected, time.Second, time.Millisecond*5)
+
+	w.CloseAsync()
+	require.NoError(t, w.WaitForClose(time.Second))
+}
+
+//------------------------------------------------------------------------------
+
+type failingOrderedWriter struct {
+	mut     sync.Mutex
+	fails   int
+	written []string
+}
+
+func (w *failingOrderedWriter) ConnectWithContext(ctx context.Context) error {
+	return nil
+}
+
+func (w *failingOrderedWriter) WriteWithContext(ctx context.Context, msg types.Message) error {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	if w.fails != 0 {
+		if w.fails > 0 {
+			w.fails--
+		}
+		return errors.New("nope")
+	}
+	w.written = append(w.written, string(msg.Get(0).Get()))
+	return nil
+}
+
+func (w *failingOrderedWriter) CloseAsync() {}
+
+func (w *failingOrderedWriter) WaitForClose(time.Duration) error {
+	return nil
+}
+
+func TestAsyncWriterPreserveOrder(t *testing.T) {
+	t.Parallel()
+
+	writerImpl := &failingOrderedWriter{fails: 2}
+	stats := metrics.NewLocal()
+
+	w, err := NewAsyncWriter("foo", 5, writerImpl, log.Noop(), stats)
+	require.NoError(t, err)
+
+	aw := w.(*AsyncWriter)
+	aw.SetPreserveOrder(func() backoff.BackOff {
+		return backoff.NewConstantBackOff(time.Millisecond)
+	})
+
+	mif, ok := aw.MaxInFlight()
+	require.True(t, ok)
+	assert.Equal(t, 1, mif)
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, w.Consume(tChan))
+
+	var resChans []chan types.Response
+	for _, content := range []string{"a", "b", "c"} {
+		resChan := make(chan types.Response, 1)
+		resChans = append(resChans, resChan)
+		select {
+		case tChan <- types.NewTransaction(message.New([][]byte{[]byte(content)}), resChan):
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+	}
+
+	for _, resChan := range resChans {
+		select {
+		case res := <-resChan:
+			assert.NoError(t, res.Error())
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+	}
+
+	writerImpl.mut.Lock()
+	assert.Equal(t, []string{"a", "b", "c"}, writerImpl.written)
+	writerImpl.mut.Unlock()
+
+	// The failed batch is counted as a single error regardless of retries.
+	assert.Equal(t, int64(1), stats.GetCounters()["batch.error"])
+	assert.Equal(t, int64(3), stats.GetCounters()["batch.sent"])
+	assert.Equal(t, int64(3), stats.GetCounters()["batch.acked"])
+
+	w.CloseAsync()
+	require.NoError(t, w.WaitForClose(time.Second))
+}
+
+func TestAsyncWriterPreserveOrderClose(t *testing.T) {
+	t.Parallel()
+
+	writerImpl := &failingOrderedWriter{fails: -1}
+
+	w, err := NewAsyncWriter("foo", 1, writerImpl, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	w.(*AsyncWriter).SetPreserveOrder(func() backoff.BackOff {
+		return backoff.NewConstantBackOff(time.Millisecond)
+	})
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, w.Consume(tChan))
+
+	resChan := make(chan types.Response)
+	select {
+	case tChan <- types.NewTransaction(message.New([][]byte{[]byte("a")}), resChan):
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+
+	// The pending batch is nacked when the output closes.
+	w.CloseAsync()
+	select {
+	case res := <-resChan:
+		assert.EqualError(t, res.Error(), "nope")
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+	require.NoError(t, w.WaitForClose(time.Second))
+}
@@ -38,6 +38,7 @@ allowing you to transfer data across accounts. You can find out more
 			docs.FieldCommon("partition_key", "A required key for partitioning messages.").IsInterpolated(),
 			docs.FieldAdvanced("hash_key", "A optional hash key for partitioning messages.").IsInterpolated(),
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldAdvanced("preserve_order", "Whether to write records to the stream in the order that they were received, which keeps the records of each partition key ordered within their shard. When enabled `max_in_flight` is ignored and batches are written one at a time, and a batch that fails is retried with the intervals of the `backoff` fields until it succeeds instead of being nacked, regardless of `max_retries`.").AtVersion("3.51.0"),
 			batch.FieldSpec(),
 		}.Merge(session.FieldSpecs()).Merge(retries.FieldSpecs()),
 		Categories: []Category{
@@ -74,6 +75,7 @@ allowing you to transfer data across accounts. You can find out more
 			docs.FieldCommon("partition_key", "A required key for partitioning messages.").IsInterpolated(),
 			docs.FieldAdvanced("hash_key", "A optional hash key for partitioning messages.").IsInterpolated(),
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldAdvanced("preserve_order", "Whether to write records to the stream in the order that they were received, which keeps the records of each partition key ordered within their shard. When enabled `max_in_flight` is ignored and batches are written one at a time, and a batch that fails is retried with the intervals of the `backoff` fields until it succeeds instead of being nacked, regardless of `max_retries`.").AtVersion("3.51.0"),
 			batch.FieldSpec(),
 		}.Merge(session.FieldSpecs()).Merge(retries.FieldSpecs()),
 		Categories: []Category{
@@ -101,7 +103,7 @@ func newKinesis(name string, conf writer.KinesisConfig, mgr types.Manager, log l
 		return nil, err
 	}
 	var w Type
-	if conf.MaxInFlight == 1 {
+	if conf.MaxInFlight == 1 && !conf.PreserveOrder {
 		w, err = NewWriter(name, kin, log, stats)
 	} else {
 		w, err = NewAsyncWriter(name, conf.MaxInFlight, kin, log, stats)
@@ -109,6 +111,13 @@ func newKinesis(name string, conf writer.KinesisConfig, mgr types.Manager, log l
 	if err != nil {
 		return w, err
 	}
+	if conf.PreserveOrder {
+		boffCtor, err := conf.Config.GetCtor()
+		if err != nil {
+			return nil, err
+		}
+		w.(*AsyncWriter).SetPreserveOrder(boffCtor)
+	}
 	return NewBatcherFromConfig(conf.Batching, w, mgr, log, stats)
 }
 
@@ -90,7 +90,38 @@ output:
       processors:
         - archive:
             format: json_array
-` + "```" + ``,
+` + "```" + `
+
+### Deduplication
+
+When ` + "`deduplicate.enabled`" + ` is set to ` + "`true`" + ` a SHA-256 hash of the
+contents of each message is calculated and used in order to avoid uploading the
+same content more than once. The hash of each uploaded object is stored as the
+metadata field ` + "`benthos_content_sha256`" + `.
+
+If a ` + "[`cache`](/docs/components/caches/about)" + ` resource is specified
+with ` + "`deduplicate.cache`" + ` it is used as an index of content hashes to the
+path of the object first written with that content. When the content of a
+message has already been written to a different path an empty object is written
+instead, with the metadata field ` + "`benthos_content_ref`" + ` set to the path of
+the object containing the content.
+
+Without a cache a HEAD request is made for the path of each message and the
+upload is skipped when an object with the same content hash already exists,
+which is best suited to paths derived from the content itself:
+
+` + "```yaml" + `
+output:
+  aws_s3:
+    bucket: TODO
+    path: ${! content().hash("sha256").encode("hex") }.json
+    deduplicate:
+      enabled: true
+` + "```" + `
+
+Setting ` + "`deduplicate.paranoid`" + ` to ` + "`true`" + ` downloads the existing object
+on a match and compares it with the message in full, uploading the message as
+normal when the contents differ.`,
 		Async: true,
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon("bucket", "The bucket to upload messages to."),
@@ -118,6 +149,7 @@ output:
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
 			docs.FieldAdvanced("timeout", "The maximum period to wait on an upload before abandoning it and reattempting."),
 			batch.FieldSpec(),
+			s3DeduplicateFieldSpec(),
 		}.Merge(session.FieldSpecs()),
 		Categories: []Category{
 			CategoryServices,
@@ -233,6 +265,7 @@ output:
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
 			docs.FieldAdvanced("timeout", "The maximum period to wait on an upload before abandoning it and reattempting."),
 			batch.FieldSpec(),
+			s3DeduplicateFieldSpec(),
 		}.Merge(session.FieldSpecs()),
 		Categories: []Category{
 			CategoryServices,
@@ -253,8 +286,18 @@ func NewAmazonS3(conf Config, mgr types.Manager, log log.Modular, stats metrics.
 	return newAmazonS3(TypeS3, conf.S3, mgr, log, stats)
 }
 
+func s3DeduplicateFieldSpec() docs.FieldSpec {
+	return docs.FieldAdvanced(
+		"deduplicate", "Skip uploading the contents of messages that have already been written to the bucket, identified by a hash of their contents.",
+	).WithChildren(
+		docs.FieldAdvanced("enabled", "Whether deduplication is enabled."),
+		docs.FieldAdvanced("cache", "An optional [cache resource](/docs/components/caches/about) used as an index of content hashes to object paths. When empty a HEAD request is made for the path of each message instead."),
+		docs.FieldAdvanced("paranoid", "Whether to download existing objects with a matching hash and compare them with the message in full in order to rule out hash collisions."),
+	).AtVersion("3.51.0")
+}
+
 func newAmazonS3(name string, conf writer.AmazonS3Config, mgr types.Manager, log log.Modular, stats metrics.Type) (Type, error) {
-	sthree, err := writer.NewAmazonS3(conf, log, stats)
+	sthree, err := writer.NewAmazonS3V2(conf, mgr, log, stats)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +44,7 @@ allowing you to transfer data across accounts. You can find out more
 			docs.FieldCommon("message_group_id", "An optional group ID to set for messages.").IsInterpolated(),
 			docs.FieldCommon("message_deduplication_id", "An optional deduplication ID to set for messages.").IsInterpolated(),
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldAdvanced("preserve_order", "Whether to send messages to the queue in the order that they were received, which is useful for FIFO queues where the messages of a group must remain ordered. When enabled batches are sent one at a time, ignoring `max_in_flight`, and a batch that fails to send is retried indefinitely with the intervals of the `backoff` fields instead of being nacked.").AtVersion("3.51.0"),
 			docs.FieldCommon("metadata", "Specify criteria for which metadata values are sent as headers.").WithChildren(output.MetadataFields()...),
 			batch.FieldSpec(),
 		}.Merge(session.FieldSpecs()).Merge(retries.FieldSpecs()),
@@ -86,6 +87,7 @@ allowing you to transfer data across accounts. You can find out more
 			docs.FieldCommon("message_group_id", "An optional group ID to set for messages.").IsInterpolated(),
 			docs.FieldCommon("message_deduplication_id", "An optional deduplication ID to set for messages.").IsInterpolated(),
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldAdvanced("preserve_order", "Whether to send messages to the queue in the order that they were received, which is useful for FIFO queues where the messages of a group must remain ordered. When enabled batches are sent one at a time, ignoring `max_in_flight`, and a batch that fails to send is retried indefinitely with the intervals of the `backoff` fields instead of being nacked.").AtVersion("3.51.0"),
 			docs.FieldCommon("metadata", "Specify criteria for which metadata values are sent as headers.").WithChildren(output.MetadataFields()...),
 			batch.FieldSpec(),
 		}.Merge(session.FieldSpecs()).Merge(retries.FieldSpecs()),
@@ -114,7 +116,7 @@ func newAmazonSQS(name string, conf writer.AmazonSQSConfig, mgr types.Manager, l
 		return nil, err
 	}
 	var w Type
-	if conf.MaxInFlight == 1 {
+	if conf.MaxInFlight == 1 && !conf.PreserveOrder {
 		w, err = NewWriter(name, s, log, stats)
 	} else {
 		w, err = NewAsyncWriter(name, conf.MaxInFlight, s, log, stats)
@@ -122,6 +124,13 @@ func newAmazonSQS(name string, conf writer.AmazonSQSConfig, mgr types.Manager, l
 	if err != nil {
 		return w, err
 	}
+	if conf.PreserveOrder {
+		boffCtor, err := conf.Config.GetCtor()
+		if err != nil {
+			return nil, err
+		}
+		w.(*AsyncWriter).SetPreserveOrder(boffCtor)
+	}
 	return NewBatcherFromConfig(conf.Batching, w, mgr, log, stats)
 }
 
@@ -355,4 +355,48 @@ func TestBatcherTimed(t *testing.T) {
 	close(tInChan)
 }
 
+func TestBatcherIdle(t *testing.T) {
+	tInChan := make(chan types.Transaction)
+
+	policyConf := batch.NewPolicyConfig()
+	policyConf.IdlePeriod = "200ms"
+	batcher, err := batch.NewPolicy(policyConf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	out := &mockOutput{}
+
+	b := NewBatcher(batcher, out, log.Noop(), metrics.Noop())
+	require.NoError(t, b.Consume(tInChan))
+
+	for _, content := range []string{"foo1", "foo2", "foo3"} {
+		select {
+		case tInChan <- types.NewTransaction(message.New([][]byte{[]byte(content)}), make(chan types.Response)):
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for message send")
+		}
+
+		// Messages arriving within the idle period must not trigger a flush.
+		select {
+		case <-out.ts:
+			t.Fatal("Unexpected batch flushed")
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+
+	var outTr types.Transaction
+	select {
+	case outTr = <-out.ts:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for message read")
+	}
+	assert.Equal(t, [][]byte{
+		[]byte("foo1"),
+		[]byte("foo2"),
+		[]byte("foo3"),
+	}, message.GetAllBytes(outTr.Payload))
+
+	b.CloseAsync()
+	require.NoError(t, b.WaitForClose(time.Second))
+}
+
 //------------------------------------------------------------------------------
@@ -74,6 +74,20 @@ Similar to the fan out pattern except outputs are written to sequentially,
 meaning an output is only written to once the preceding output has confirmed
 receipt of the same message.
 
+### ` + "`quorum`" + `
+
+Similar to the fan out pattern except that each message is acknowledged once a
+number of outputs determined by the field ` + "`required_acks`" + ` have
+confirmed receipt of it, with the remaining outputs written to on a best effort
+basis. When zero ` + "`required_acks`" + ` defaults to a majority of the
+outputs.
+
+Messages are not retried by the broker, and instead when too many outputs fail
+to deliver a message for a quorum to be reached the message is rejected. Since
+rejected messages are delivered again by the input outputs that succeeded will
+receive duplicates. Slow outputs do not delay the acknowledgement of a message,
+but they still apply back pressure.
+
 ### ` + "`round_robin`" + `
 
 With the round robin pattern each message will be assigned a single output
@@ -92,11 +106,15 @@ outputs.`,
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldAdvanced("copies", "The number of copies of each configured output to spawn."),
 			docs.FieldCommon("pattern", "The brokering pattern to use.").HasOptions(
-				"fan_out", "fan_out_sequential", "round_robin", "greedy",
+				"fan_out", "fan_out_sequential", "quorum", "round_robin", "greedy",
 			),
+			docs.FieldAdvanced(
+				"required_acks",
+				"The number of outputs that must acknowledge a message before it is acknowledged, where zero means a majority of the outputs. Only relevant for the `quorum` pattern.",
+			).AtVersion("3.51.0"),
 			docs.FieldAdvanced(
 				"max_in_flight",
-				"The maximum number of parallel message batches to have in flight at any given time. Note that if a child output has a higher `max_in_flight` then the switch output will automatically match it, therefore this value is the minimum `max_in_flight` to set in cases where the child values can't be inferred (such as when using resource outputs as children). Only relevant for `fan_out`, `fan_out_sequential` brokers.",
+				"The maximum number of parallel message batches to have in flight at any given time. Note that if a child output has a higher `max_in_flight` then the switch output will automatically match it, therefore this value is the minimum `max_in_flight` to set in cases where the child values can't be inferred (such as when using resource outputs as children). Only relevant for `fan_out`, `fan_out_sequential` and `quorum` brokers.",
 			),
 			docs.FieldCommon("outputs", "A list of child outputs to broker.").Array().HasType(docs.FieldTypeOutput),
 			batch.FieldSpec(),
@@ -111,21 +129,23 @@ outputs.`,
 
 // BrokerConfig contains configuration fields for the Broker output type.
 type BrokerConfig struct {
-	Copies      int                `json:"copies" yaml:"copies"`
-	Pattern     string             `json:"pattern" yaml:"pattern"`
-	MaxInFlight int                `json:"max_in_flight" yaml:"max_in_flight"`
-	Outputs     brokerOutputList   `json:"outputs" yaml:"outputs"`
-	Batching    batch.PolicyConfig `json:"batching" yaml:"batching"`
+	Copies       int                `json:"copies" yaml:"copies"`
+	Pattern      string             `json:"pattern" yaml:"pattern"`
+	RequiredAcks int                `json:"required_acks" yaml:"required_acks"`
+	MaxInFlight  int                `json:"max_in_flight" yaml:"max_in_flight"`
+	Outputs      brokerOutputList   `json:"outputs" yaml:"outputs"`
+	Batching     batch.PolicyConfig `json:"batching" yaml:"batching"`
 }
 
 // NewBrokerConfig creates a new BrokerConfig with default values.
 func NewBrokerConfig() BrokerConfig {
 	return BrokerConfig{
-		Copies:      1,
-		Pattern:     "fan_out",
-		MaxInFlight: 1,
-		Outputs:     brokerOutputList{},
-		Batching:    batch.NewPolicyConfig(),
+		Copies:       1,
+		Pattern:      "fan_out",
+		RequiredAcks: 0,
+		MaxInFlight:  1,
+		Outputs:      brokerOutputList{},
+		Batching:     batch.NewPolicyConfig(),
 	}
 }
 
@@ -201,6 +221,15 @@ func NewBroker(
 		if bTmp, err = broker.NewFanOutSequential(outputs, log, stats); err == nil {
 			b = bTmp.WithMaxInFlight(maxInFlight)
 		}
+	case "quorum":
+		requiredAcks := conf.Broker.RequiredAcks
+		if requiredAcks == 0 {
+			requiredAcks = len(outputs)/2 + 1
+		}
+		var bTmp *broker.Quorum
+		if bTmp, err = broker.NewQuorum(outputs, requiredAcks, log, stats); err == nil {
+			b = bTmp.WithMaxInFlight(maxInFlight)
+		}
 	case "round_robin":
 		b, err = broker.NewRoundRobin(outputs, stats)
 	case "greedy":
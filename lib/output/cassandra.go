@@ -3,6 +3,7 @@ package output
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message/batch"
@@ -45,9 +47,17 @@ func init() {
 		Summary: `
 Runs a query against a Cassandra database for each message in order to insert data.`,
 		Description: `
-Query arguments are set using [interpolation functions](/docs/configuration/interpolation#bloblang-queries) in the ` + "`args`" + ` field.
+Query arguments are set using either [interpolation functions](/docs/configuration/interpolation#bloblang-queries) in the ` + "`args`" + ` field or a [Bloblang mapping](/docs/guides/bloblang/about) in the ` + "`args_mapping`" + ` field.
 
-When populating timestamp columns the value must either be a string in ISO 8601 format (2006-01-02T15:04:05Z07:00), or an integer representing unix time in seconds.`,
+When populating timestamp columns the value must either be a string in ISO 8601 format (2006-01-02T15:04:05Z07:00), or an integer representing unix time in seconds.
+
+This output is also compatible with [ScyllaDB](https://www.scylladb.com/).
+
+### Batching
+
+Queries are prepared by the driver and the prepared statements are cached for the lifetime of the connection. When a message batch of more than one message is written the queries are executed as a single CQL batch, which is unlogged by default and can be changed to a logged batch with the field ` + "`logged_batch`" + `.
+
+Batches that span multiple partitions are expensive for the coordinator node, and so by enabling ` + "`batch_by_partition`" + ` the messages of a batch are grouped by the partition key of their query and a separate single partition batch is executed for each group. This is most efficient when combined with ` + "`token_aware`" + `, which routes each query or batch directly to a node that owns its partition.`,
 		Examples: []docs.AnnotatedExample{
 			{
 				Title:   "Basic Inserts",
@@ -64,6 +74,23 @@ output:
       - ${! json("timestamp").format_timestamp() }
     batching:
       count: 500
+`,
+			},
+			{
+				Title:   "Time Series Inserts",
+				Summary: "If we were to create a table `CREATE TABLE foo.readings (sensor text, ts timestamp, value double, PRIMARY KEY (sensor, ts));` and were consuming JSON documents of the form `{\"sensor\":\"a\",\"ts\":\"2021-07-05T10:00:00Z\",\"value\":4.5}` we could write large batches where each CQL batch only targets a single partition with the following config:",
+				Config: `
+output:
+  cassandra:
+    addresses:
+      - localhost:9042
+    query: 'INSERT INTO foo.readings (sensor, ts, value) VALUES (?, ?, ?)'
+    args_mapping: 'root = [ this.sensor, this.ts, this.value ]'
+    token_aware: true
+    batch_by_partition: true
+    batching:
+      count: 1000
+      period: 1s
 `,
 			},
 			{
@@ -108,17 +135,26 @@ output:
 				"args",
 				"A list of arguments for the query to be resolved for each message.",
 			).IsInterpolated().Array(),
+			docs.FieldString(
+				"args_mapping",
+				"A [Bloblang mapping](/docs/guides/bloblang/about) that produces the arguments for the query. The mapping must return an array containing the number of arguments in the query. This field cannot be combined with `args`.",
+				`root = [ this.id, this.content, this.timestamp.string() ]`,
+			).Linter(docs.LintBloblangMapping).ExclusiveWith("args").AtVersion("3.51.0"),
 			docs.FieldAdvanced(
 				"consistency",
 				"The consistency level to use.",
 			).HasOptions(
 				"ANY", "ONE", "TWO", "THREE", "QUORUM", "ALL", "LOCAL_QUORUM", "EACH_QUORUM", "LOCAL_ONE",
 			),
+			docs.FieldAdvanced("logged_batch", "Whether message batches should be written as logged CQL batches rather than unlogged batches.").AtVersion("3.51.0"),
+			docs.FieldAdvanced("batch_by_partition", "Whether message batches should be split into a separate CQL batch for each partition targeted by the queries of the batch.").AtVersion("3.51.0"),
+			docs.FieldAdvanced("token_aware", "Whether queries should be routed directly to a node that owns the targeted partition. Token information is not available when `disable_initial_host_lookup` is enabled, in which case queries are routed round robin.").AtVersion("3.51.0"),
 			docs.FieldAdvanced("max_retries", "The maximum number of retries before giving up on a request."),
 			docs.FieldAdvanced("backoff", "Control time intervals between retry attempts.").WithChildren(
 				docs.FieldAdvanced("initial_interval", "The initial period to wait between retry attempts."),
 				docs.FieldAdvanced("max_interval", "The maximum period to wait between retry attempts."),
 				docs.FieldDeprecated("max_elapsed_time"),
+				docs.FieldDeprecated("jitter"),
 			),
 		}.Merge(docs.FieldSpecs{
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
@@ -145,7 +181,11 @@ type CassandraConfig struct {
 	DisableInitialHostLookup bool                  `json:"disable_initial_host_lookup" yaml:"disable_initial_host_lookup"`
 	Query                    string                `json:"query" yaml:"query"`
 	Args                     []string              `json:"args" yaml:"args"`
+	ArgsMapping              string                `json:"args_mapping" yaml:"args_mapping"`
 	Consistency              string                `json:"consistency" yaml:"consistency"`
+	LoggedBatch              bool                  `json:"logged_batch" yaml:"logged_batch"`
+	BatchByPartition         bool                  `json:"batch_by_partition" yaml:"batch_by_partition"`
+	TokenAware               bool                  `json:"token_aware" yaml:"token_aware"`
 	// TODO: V4 Remove this and replace with explicit values.
 	retries.Config `json:",inline" yaml:",inline"`
 	MaxInFlight    int                `json:"max_in_flight" yaml:"max_in_flight"`
@@ -171,7 +211,11 @@ func NewCassandraConfig() CassandraConfig {
 		DisableInitialHostLookup: false,
 		Query:                    "",
 		Args:                     []string{},
+		ArgsMapping:              "",
 		Consistency:              gocql.Quorum.String(),
+		LoggedBatch:              false,
+		BatchByPartition:         false,
+		TokenAware:               false,
 		Config:                   rConf,
 		MaxInFlight:              1,
 		Batching:                 batch.NewPolicyConfig(),
@@ -188,12 +232,18 @@ type cassandraWriter struct {
 	backoffMax time.Duration
 
 	args          []*field.Expression
+	argsMapping   *mapping.Executor
+	batchType     gocql.BatchType
 	session       *gocql.Session
 	mQueryLatency metrics.StatTimer
 	connLock      sync.RWMutex
 }
 
 func newCassandraWriter(conf CassandraConfig, log log.Modular, stats metrics.Type) (*cassandraWriter, error) {
+	if len(conf.Args) > 0 && conf.ArgsMapping != "" {
+		return nil, errors.New("cannot specify both `args` and an `args_mapping` in the same output")
+	}
+
 	var args []*field.Expression
 	for i, v := range conf.Args {
 		expr, err := bloblang.NewField(v)
@@ -203,13 +253,26 @@ func newCassandraWriter(conf CassandraConfig, log log.Modular, stats metrics.Typ
 		args = append(args, expr)
 	}
 
+	var argsMapping *mapping.Executor
+	if conf.ArgsMapping != "" {
+		var err error
+		if argsMapping, err = bloblang.NewMapping("", conf.ArgsMapping); err != nil {
+			return nil, fmt.Errorf("failed to parse `args_mapping`: %w", err)
+		}
+	}
+
 	c := cassandraWriter{
 		log:           log,
 		stats:         stats,
 		conf:          conf,
 		args:          args,
+		argsMapping:   argsMapping,
+		batchType:     gocql.UnloggedBatch,
 		mQueryLatency: stats.GetTimer("query.latency"),
 	}
+	if conf.LoggedBatch {
+		c.batchType = gocql.LoggedBatch
+	}
 	var err error
 	if conf.TLS.Enabled {
 		if c.tlsConf, err = conf.TLS.Get(); err != nil {
@@ -249,6 +312,9 @@ func (c *cassandraWriter) ConnectWithContext(ctx context.Context) error {
 		}
 	}
 	conn.DisableInitialHostLookup = c.conf.DisableInitialHostLookup
+	if c.conf.TokenAware {
+		conn.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy())
+	}
 	if conn.Consistency, err = gocql.ParseConsistencyWrapper(c.conf.Consistency); err != nil {
 		return fmt.Errorf("parsing consistency: %w", err)
 	}
@@ -345,43 +411,106 @@ func (s stringValue) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
 	return gocql.Marshal(info, string(s))
 }
 
+func (c *cassandraWriter) getArgs(index int, msg types.Message) ([]interface{}, error) {
+	if c.argsMapping == nil {
+		values := make([]interface{}, 0, len(c.args))
+		for _, arg := range c.args {
+			values = append(values, stringValue(arg.String(index, msg)))
+		}
+		return values, nil
+	}
+
+	pargs, err := c.argsMapping.MapPart(index, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	iargs, err := pargs.JSON()
+	if err != nil {
+		return nil, fmt.Errorf("mapping returned non-structured result: %w", err)
+	}
+
+	args, ok := iargs.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("mapping returned non-array result: %T", iargs)
+	}
+	for i, v := range args {
+		if str, isStr := v.(string); isStr {
+			args[i] = stringValue(str)
+		}
+	}
+	return args, nil
+}
+
 func (c *cassandraWriter) writeRow(session *gocql.Session, msg types.Message) error {
 	t0 := time.Now()
 
-	values := make([]interface{}, 0, len(c.args))
-	for _, arg := range c.args {
-		values = append(values, stringValue(arg.String(0, msg)))
-	}
-	err := session.Query(c.conf.Query, values...).Exec()
+	values, err := c.getArgs(0, msg)
 	if err != nil {
 		return err
 	}
+	if err = session.Query(c.conf.Query, values...).Exec(); err != nil {
+		return err
+	}
 
 	c.mQueryLatency.Timing(time.Since(t0).Nanoseconds())
 	return nil
 }
 
 func (c *cassandraWriter) writeBatch(session *gocql.Session, msg types.Message) error {
-	batch := session.NewBatch(gocql.UnloggedBatch)
 	t0 := time.Now()
 
-	msg.Iter(func(i int, p types.Part) error {
-		values := make([]interface{}, 0, len(c.args))
-		for _, arg := range c.args {
-			values = append(values, stringValue(arg.String(i, msg)))
+	var batches []*gocql.Batch
+	partitionBatches := map[string]*gocql.Batch{}
+
+	if err := msg.Iter(func(i int, p types.Part) error {
+		values, err := c.getArgs(i, msg)
+		if err != nil {
+			return err
+		}
+
+		var key string
+		if c.conf.BatchByPartition {
+			routingKey, err := session.Query(c.conf.Query, values...).GetRoutingKey()
+			if err != nil {
+				return fmt.Errorf("failed to obtain partition key: %w", err)
+			}
+			key = string(routingKey)
+		}
+
+		batch, exists := partitionBatches[key]
+		if !exists {
+			batch = session.NewBatch(c.batchType)
+			partitionBatches[key] = batch
+			batches = append(batches, batch)
 		}
 		batch.Query(c.conf.Query, values...)
 		return nil
-	})
-
-	err := session.ExecuteBatch(batch)
-	if err != nil {
+	}); err != nil {
 		return err
 	}
+
+	for _, batch := range batches {
+		if err := session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+	}
 	c.mQueryLatency.Timing(time.Since(t0).Nanoseconds())
 	return nil
 }
 
+// HealthCheck verifies that the Cassandra cluster is still reachable.
+func (c *cassandraWriter) HealthCheck(ctx context.Context) error {
+	c.connLock.RLock()
+	session := c.session
+	c.connLock.RUnlock()
+
+	if session == nil {
+		return types.ErrNotConnected
+	}
+	return session.Query("SELECT now() FROM system.local").WithContext(ctx).Exec()
+}
+
 // CloseAsync shuts down the Cassandra output and stops processing messages.
 func (c *cassandraWriter) CloseAsync() {
 	go func() {
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"github.com/Jeffail/benthos/v3/lib/message/batch"
+)
+
+// ClickHouseConfig contains configuration fields for the ClickHouse output
+// type.
+type ClickHouseConfig struct {
+	DataSourceName string             `json:"data_source_name" yaml:"data_source_name"`
+	Table          string             `json:"table" yaml:"table"`
+	Columns        []string           `json:"columns" yaml:"columns"`
+	ArgsMapping    string             `json:"args_mapping" yaml:"args_mapping"`
+	Compression    string             `json:"compression" yaml:"compression"`
+	MaxInFlight    int                `json:"max_in_flight" yaml:"max_in_flight"`
+	Batching       batch.PolicyConfig `json:"batching" yaml:"batching"`
+}
+
+// NewClickHouseConfig creates a new ClickHouseConfig with default values.
+func NewClickHouseConfig() ClickHouseConfig {
+	return ClickHouseConfig{
+		DataSourceName: "",
+		Table:          "",
+		Columns:        []string{},
+		ArgsMapping:    "",
+		Compression:    "none",
+		MaxInFlight:    1,
+		Batching:       batch.NewPolicyConfig(),
+	}
+}
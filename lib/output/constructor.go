@@ -56,7 +56,9 @@ type TypeSpec struct {
 
 // AppendProcessorsFromConfig takes a variant arg of pipeline constructor
 // functions and returns a new slice of them where the processors of the
-// provided output configuration will also be initialized.
+// provided output configuration will also be initialized. If the output
+// configuration sets a maximum message size then a pipeline rejecting oversized
+// messages is placed after the processors.
 func AppendProcessorsFromConfig(
 	conf Config,
 	mgr types.Manager,
@@ -83,6 +85,11 @@ func AppendProcessorsFromConfig(
 			return pipeline.NewProcessor(log, stats, processors...), nil
 		}}...)
 	}
+	if conf.MaxMessageBytes > 0 {
+		pipelines = append(pipelines, func(i *int) (types.Pipeline, error) {
+			return pipeline.NewSizeLimit(conf.MaxMessageBytes, log, stats), nil
+		})
+	}
 	return pipelines
 }
 
@@ -171,12 +178,15 @@ const (
 	TypeBroker             = "broker"
 	TypeCache              = "cache"
 	TypeCassandra          = "cassandra"
+	TypeClickHouse         = "clickhouse"
 	TypeDrop               = "drop"
 	TypeDropOn             = "drop_on"
 	TypeDropOnError        = "drop_on_error"
 	TypeDynamic            = "dynamic"
+	TypeDynamicFanOut      = "dynamic_fan_out"
 	TypeDynamoDB           = "dynamodb"
 	TypeElasticsearch      = "elasticsearch"
+	TypeErrorCatch         = "error_catch"
 	TypeFile               = "file"
 	TypeFiles              = "files"
 	TypeGCPCloudStorage    = "gcp_cloud_storage"
@@ -206,6 +216,7 @@ const (
 	TypeS3                 = "s3"
 	TypeSFTP               = "sftp"
 	TypeSNS                = "sns"
+	TypeSnowflakePut       = "snowflake_put"
 	TypeSQL                = "sql"
 	TypeSQS                = "sqs"
 	TypeSTDOUT             = "stdout"
@@ -217,6 +228,7 @@ const (
 	TypeTry                = "try"
 	TypeUDP                = "udp"
 	TypeSocket             = "socket"
+	TypeSpool              = "spool"
 	TypeWebsocket          = "websocket"
 	TypeZMQ4               = "zmq4"
 )
@@ -243,12 +255,15 @@ type Config struct {
 	Broker             BrokerConfig                   `json:"broker" yaml:"broker"`
 	Cache              writer.CacheConfig             `json:"cache" yaml:"cache"`
 	Cassandra          CassandraConfig                `json:"cassandra" yaml:"cassandra"`
+	ClickHouse         ClickHouseConfig               `json:"clickhouse" yaml:"clickhouse"`
 	Drop               writer.DropConfig              `json:"drop" yaml:"drop"`
 	DropOn             DropOnConfig                   `json:"drop_on" yaml:"drop_on"`
 	DropOnError        DropOnErrorConfig              `json:"drop_on_error" yaml:"drop_on_error"`
 	Dynamic            DynamicConfig                  `json:"dynamic" yaml:"dynamic"`
+	DynamicFanOut      DynamicFanOutConfig            `json:"dynamic_fan_out" yaml:"dynamic_fan_out"`
 	DynamoDB           writer.DynamoDBConfig          `json:"dynamodb" yaml:"dynamodb"`
 	Elasticsearch      writer.ElasticsearchConfig     `json:"elasticsearch" yaml:"elasticsearch"`
+	ErrorCatch         ErrorCatchConfig               `json:"error_catch" yaml:"error_catch"`
 	File               FileConfig                     `json:"file" yaml:"file"`
 	Files              writer.FilesConfig             `json:"files" yaml:"files"`
 	GCPCloudStorage    GCPCloudStorageConfig          `json:"gcp_cloud_storage" yaml:"gcp_cloud_storage"`
@@ -279,6 +294,7 @@ type Config struct {
 	S3                 writer.AmazonS3Config          `json:"s3" yaml:"s3"`
 	SFTP               SFTPConfig                     `json:"sftp" yaml:"sftp"`
 	SNS                writer.SNSConfig               `json:"sns" yaml:"sns"`
+	SnowflakePut       SnowflakePutConfig             `json:"snowflake_put" yaml:"snowflake_put"`
 	SQL                SQLConfig                      `json:"sql" yaml:"sql"`
 	SQS                writer.AmazonSQSConfig         `json:"sqs" yaml:"sqs"`
 	STDOUT             STDOUTConfig                   `json:"stdout" yaml:"stdout"`
@@ -290,8 +306,10 @@ type Config struct {
 	Try                TryConfig                      `json:"try" yaml:"try"`
 	UDP                writer.UDPConfig               `json:"udp" yaml:"udp"`
 	Socket             writer.SocketConfig            `json:"socket" yaml:"socket"`
+	Spool              SpoolConfig                    `json:"spool" yaml:"spool"`
 	Websocket          writer.WebsocketConfig         `json:"websocket" yaml:"websocket"`
 	ZMQ4               *writer.ZMQ4Config             `json:"zmq4,omitempty" yaml:"zmq4,omitempty"`
+	MaxMessageBytes    int                            `json:"max_message_bytes" yaml:"max_message_bytes"`
 	Processors         []processor.Config             `json:"processors" yaml:"processors"`
 }
 
@@ -316,12 +334,15 @@ func NewConfig() Config {
 		Broker:             NewBrokerConfig(),
 		Cache:              writer.NewCacheConfig(),
 		Cassandra:          NewCassandraConfig(),
+		ClickHouse:         NewClickHouseConfig(),
 		Drop:               writer.NewDropConfig(),
 		DropOn:             NewDropOnConfig(),
 		DropOnError:        NewDropOnErrorConfig(),
 		Dynamic:            NewDynamicConfig(),
+		DynamicFanOut:      NewDynamicFanOutConfig(),
 		DynamoDB:           writer.NewDynamoDBConfig(),
 		Elasticsearch:      writer.NewElasticsearchConfig(),
+		ErrorCatch:         NewErrorCatchConfig(),
 		File:               NewFileConfig(),
 		Files:              writer.NewFilesConfig(),
 		GCPCloudStorage:    NewGCPCloudStorageConfig(),
@@ -352,6 +373,7 @@ func NewConfig() Config {
 		S3:                 writer.NewAmazonS3Config(),
 		SFTP:               NewSFTPConfig(),
 		SNS:                writer.NewSNSConfig(),
+		SnowflakePut:       NewSnowflakePutConfig(),
 		SQL:                NewSQLConfig(),
 		SQS:                writer.NewAmazonSQSConfig(),
 		STDOUT:             NewSTDOUTConfig(),
@@ -363,8 +385,10 @@ func NewConfig() Config {
 		Try:                NewTryConfig(),
 		UDP:                writer.NewUDPConfig(),
 		Socket:             writer.NewSocketConfig(),
+		Spool:              NewSpoolConfig(),
 		Websocket:          writer.NewWebsocketConfig(),
 		ZMQ4:               writer.NewZMQ4Config(),
+		MaxMessageBytes:    0,
 		Processors:         []processor.Config{},
 	}
 }
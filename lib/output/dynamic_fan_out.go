@@ -0,0 +1,543 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/batch"
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	imessage "github.com/Jeffail/benthos/v3/internal/message"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	Constructors[TypeDynamicFanOut] = TypeSpec{
+		constructor: fromSimpleConstructor(NewDynamicFanOut),
+		Status:      docs.StatusExperimental,
+		Version:     "3.51.0",
+		Summary: `
+Routes messages to a child output per distinct value of a key, where child outputs are created from a template output config the first time a key is seen and are closed once they become idle.`,
+		Description: `
+The ` + "`key`" + ` field is resolved for each message of a batch, and the batch is split so that each message is written to the child output of its key. Child outputs are all created from the same ` + "`output`" + ` config, and therefore in order to write to a distinct destination per key the child output should use [interpolation functions](/docs/configuration/interpolation#bloblang-queries) that resolve to that destination from the messages themselves.
+
+The number of child outputs that can exist at any given time is limited by ` + "`max_outputs`" + `. When a new key is seen and the limit has been reached the least recently used child output that has no messages in flight is closed in order to make room. If all child outputs have messages in flight then the messages of the new key are rejected and will be reattempted.
+
+Child outputs that have not been written to for the period ` + "`idle_timeout`" + ` are closed, and will be created again if their key is seen once more.
+
+### Metrics
+
+This output emits the counters ` + "`outputs.created` and `outputs.closed`" + ` each time a child output is created or closed, and the gauge ` + "`outputs.active`" + ` reflecting the number of child outputs currently open.`,
+		FieldSpecs: docs.FieldSpecs{
+			docs.FieldCommon(
+				"key", "A key identifying the child output that each message should be written to.",
+				`${! meta("tenant") }`, `${! json("customer.id") }`,
+			).IsInterpolated(),
+			docs.FieldCommon("output", "A template output config from which the child output of each key is created.").HasType(docs.FieldTypeOutput),
+			docs.FieldCommon("max_outputs", "The maximum number of child outputs that can be open at any given time, set to `0` for no limit."),
+			docs.FieldAdvanced("idle_timeout", "The period of time after which a child output that has not been written to is closed, set to an empty string to never close idle outputs."),
+			docs.FieldCommon("max_in_flight", "The maximum number of batches to dispatch across child outputs at any given time."),
+		},
+		Categories: []Category{
+			CategoryUtility,
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Per Tenant Buckets",
+				Summary: "In this example messages are written to an S3 bucket owned by each tenant, where tenants are discovered at runtime from message metadata and a separate output is kept for each of them.",
+				Config: `
+output:
+  dynamic_fan_out:
+    key: ${! meta("tenant") }
+    max_outputs: 50
+    idle_timeout: 10m
+    output:
+      aws_s3:
+        bucket: ${! meta("tenant") }-events
+        path: ${! timestamp_unix_nano() }.json
+        batching:
+          count: 100
+          period: 10s
+`,
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// DynamicFanOutConfig contains configuration fields for the DynamicFanOut
+// output type.
+type DynamicFanOutConfig struct {
+	Key         string  `json:"key" yaml:"key"`
+	Output      *Config `json:"output" yaml:"output"`
+	MaxOutputs  int     `json:"max_outputs" yaml:"max_outputs"`
+	IdleTimeout string  `json:"idle_timeout" yaml:"idle_timeout"`
+	MaxInFlight int     `json:"max_in_flight" yaml:"max_in_flight"`
+}
+
+// NewDynamicFanOutConfig creates a new DynamicFanOutConfig with default values.
+func NewDynamicFanOutConfig() DynamicFanOutConfig {
+	return DynamicFanOutConfig{
+		Key:         "",
+		Output:      nil,
+		MaxOutputs:  100,
+		IdleTimeout: "5m",
+		MaxInFlight: 1,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type dummyDynamicFanOutConfig struct {
+	Key         string      `json:"key" yaml:"key"`
+	Output      interface{} `json:"output" yaml:"output"`
+	MaxOutputs  int         `json:"max_outputs" yaml:"max_outputs"`
+	IdleTimeout string      `json:"idle_timeout" yaml:"idle_timeout"`
+	MaxInFlight int         `json:"max_in_flight" yaml:"max_in_flight"`
+}
+
+func (d DynamicFanOutConfig) dummy() dummyDynamicFanOutConfig {
+	dummy := dummyDynamicFanOutConfig{
+		Key:         d.Key,
+		Output:      d.Output,
+		MaxOutputs:  d.MaxOutputs,
+		IdleTimeout: d.IdleTimeout,
+		MaxInFlight: d.MaxInFlight,
+	}
+	if d.Output == nil {
+		dummy.Output = struct{}{}
+	}
+	return dummy
+}
+
+// MarshalJSON prints an empty object instead of nil.
+func (d DynamicFanOutConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.dummy())
+}
+
+// MarshalYAML prints an empty object instead of nil.
+func (d DynamicFanOutConfig) MarshalYAML() (interface{}, error) {
+	return d.dummy(), nil
+}
+
+//------------------------------------------------------------------------------
+
+// dynamicFanOutChild is a child output of a DynamicFanOut along with the state
+// used for determining when it can be closed.
+type dynamicFanOutChild struct {
+	output   Type
+	tsChan   chan types.Transaction
+	inFlight int
+	lastUsed time.Time
+}
+
+// DynamicFanOut is an output type that lazily creates a child output for each
+// distinct key resolved from messages, and routes each message to the child
+// output of its key.
+type DynamicFanOut struct {
+	logger log.Modular
+	stats  metrics.Type
+
+	childMgr   types.Manager
+	childLog   log.Modular
+	childStats metrics.Type
+
+	key         *field.Expression
+	outputConf  Config
+	maxOutputs  int
+	idleTimeout time.Duration
+	maxInFlight int
+
+	childMut sync.Mutex
+	children map[string]*dynamicFanOutChild
+	closeWG  sync.WaitGroup
+
+	mMsgRcvd   metrics.StatCounter
+	mMsgSnt    metrics.StatCounter
+	mOutputErr metrics.StatCounter
+	mCreated   metrics.StatCounter
+	mClosed    metrics.StatCounter
+	mActive    metrics.StatGauge
+
+	transactions <-chan types.Transaction
+
+	ctx        context.Context
+	close      func()
+	closedChan chan struct{}
+}
+
+// NewDynamicFanOut creates a new DynamicFanOut output type.
+func NewDynamicFanOut(
+	conf Config,
+	mgr types.Manager,
+	logger log.Modular,
+	stats metrics.Type,
+) (Type, error) {
+	if conf.DynamicFanOut.Output == nil {
+		return nil, errors.New("cannot create a dynamic_fan_out output without a template output")
+	}
+	if conf.DynamicFanOut.Key == "" {
+		return nil, errors.New("cannot create a dynamic_fan_out output without a key")
+	}
+
+	key, err := bloblang.NewField(conf.DynamicFanOut.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key expression: %v", err)
+	}
+
+	var idleTimeout time.Duration
+	if tout := conf.DynamicFanOut.IdleTimeout; len(tout) > 0 {
+		if idleTimeout, err = time.ParseDuration(tout); err != nil {
+			return nil, fmt.Errorf("failed to parse idle timeout string: %v", err)
+		}
+	}
+
+	maxInFlight := conf.DynamicFanOut.MaxInFlight
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+
+	childMgr, childLog, childStats := interop.LabelChild("dynamic_fan_out.output", mgr, logger, stats)
+	childStats = metrics.Combine(stats, childStats)
+
+	ctx, done := context.WithCancel(context.Background())
+	return &DynamicFanOut{
+		logger:      logger,
+		stats:       stats,
+		childMgr:    childMgr,
+		childLog:    childLog,
+		childStats:  childStats,
+		key:         key,
+		outputConf:  *conf.DynamicFanOut.Output,
+		maxOutputs:  conf.DynamicFanOut.MaxOutputs,
+		idleTimeout: idleTimeout,
+		maxInFlight: maxInFlight,
+		children:    map[string]*dynamicFanOutChild{},
+		mMsgRcvd:    stats.GetCounter("messages.received"),
+		mMsgSnt:     stats.GetCounter("messages.sent"),
+		mOutputErr:  stats.GetCounter("error"),
+		mCreated:    stats.GetCounter("outputs.created"),
+		mClosed:     stats.GetCounter("outputs.closed"),
+		mActive:     stats.GetGauge("outputs.active"),
+		ctx:         ctx,
+		close:       done,
+		closedChan:  make(chan struct{}),
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Consume assigns a new transactions channel for the output to read.
+func (d *DynamicFanOut) Consume(transactions <-chan types.Transaction) error {
+	if d.transactions != nil {
+		return types.ErrAlreadyStarted
+	}
+	d.transactions = transactions
+
+	go d.loop()
+	return nil
+}
+
+// MaxInFlight returns the maximum number of in flight messages permitted by the
+// output. This value can be used to determine a sensible value for parent
+// outputs, but should not be relied upon as part of dispatcher logic.
+func (d *DynamicFanOut) MaxInFlight() (int, bool) {
+	return d.maxInFlight, true
+}
+
+// Connected returns a boolean indicating whether this output is currently
+// connected to its target, which is true when all open child outputs are
+// connected.
+func (d *DynamicFanOut) Connected() bool {
+	d.childMut.Lock()
+	defer d.childMut.Unlock()
+	for _, c := range d.children {
+		if !c.output.Connected() {
+			return false
+		}
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+// acquireChild returns the child output of a key, creating it if it does not
+// yet exist, and marks it as having messages in flight.
+func (d *DynamicFanOut) acquireChild(key string) (*dynamicFanOutChild, error) {
+	d.childMut.Lock()
+	defer d.childMut.Unlock()
+
+	if c, exists := d.children[key]; exists {
+		c.inFlight++
+		return c, nil
+	}
+
+	if d.maxOutputs > 0 && len(d.children) >= d.maxOutputs {
+		var lruKey string
+		var lru *dynamicFanOutChild
+		for k, c := range d.children {
+			if c.inFlight == 0 && (lru == nil || c.lastUsed.Before(lru.lastUsed)) {
+				lruKey, lru = k, c
+			}
+		}
+		if lru == nil {
+			return nil, fmt.Errorf("reached the limit of %v outputs, all of which have messages in flight", d.maxOutputs)
+		}
+		d.logger.Debugf("Closing output of key '%v' in order to make room for key '%v'\n", lruKey, key)
+		d.removeChild(lruKey, lru)
+	}
+
+	out, err := New(d.outputConf, d.childMgr, d.childLog.WithFields(map[string]string{"key": key}), d.childStats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output for key '%v': %w", key, err)
+	}
+	c := &dynamicFanOutChild{
+		output:   out,
+		tsChan:   make(chan types.Transaction),
+		inFlight: 1,
+	}
+	if err := out.Consume(c.tsChan); err != nil {
+		out.CloseAsync()
+		return nil, fmt.Errorf("failed to start output for key '%v': %w", key, err)
+	}
+
+	d.children[key] = c
+	d.mCreated.Incr(1)
+	d.mActive.Set(int64(len(d.children)))
+	return c, nil
+}
+
+// releaseChild marks a batch as no longer in flight for a child output.
+func (d *DynamicFanOut) releaseChild(c *dynamicFanOutChild) {
+	d.childMut.Lock()
+	c.inFlight--
+	c.lastUsed = time.Now()
+	d.childMut.Unlock()
+}
+
+// removeChild removes a child output that has no messages in flight and closes
+// it in the background. The child mutex must be held by the caller.
+func (d *DynamicFanOut) removeChild(key string, c *dynamicFanOutChild) {
+	delete(d.children, key)
+	d.mClosed.Incr(1)
+	d.mActive.Set(int64(len(d.children)))
+
+	d.closeWG.Add(1)
+	go func() {
+		defer d.closeWG.Done()
+		c.output.CloseAsync()
+		close(c.tsChan)
+		for c.output.WaitForClose(time.Second) != nil {
+		}
+	}()
+}
+
+// closeIdleChildren removes all child outputs that have no messages in flight
+// and have not been written to within the idle timeout.
+func (d *DynamicFanOut) closeIdleChildren() {
+	d.childMut.Lock()
+	defer d.childMut.Unlock()
+	for k, c := range d.children {
+		if c.inFlight == 0 && time.Since(c.lastUsed) >= d.idleTimeout {
+			d.logger.Debugf("Closing idle output of key '%v'\n", k)
+			d.removeChild(k, c)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func (d *DynamicFanOut) dispatch(group *imessage.SortGroup, sourceMessage types.Message, keys []string, targets map[string][]types.Part) error {
+	var wg sync.WaitGroup
+
+	var generalErr error
+	var batchErr *batch.Error
+	var errLock sync.Mutex
+
+	setErrForPart := func(part types.Part, err error) {
+		errLock.Lock()
+		defer errLock.Unlock()
+
+		index := group.GetIndex(part)
+		if index == -1 {
+			generalErr = err
+			return
+		}
+		if batchErr == nil {
+			batchErr = batch.NewError(sourceMessage, err)
+		}
+		batchErr.Failed(index, err)
+	}
+
+	for _, key := range keys {
+		wg.Add(1)
+		msgCopy, key := message.New(nil), key
+		msgCopy.SetAll(targets[key])
+
+		go func() {
+			defer wg.Done()
+
+			setErr := func(err error) {
+				msgCopy.Iter(func(i int, p types.Part) error {
+					setErrForPart(p, err)
+					return nil
+				})
+			}
+
+			c, err := d.acquireChild(key)
+			if err != nil {
+				d.logger.Errorf("Failed to dispatch dynamic_fan_out message: %v\n", err)
+				d.mOutputErr.Incr(1)
+				setErr(err)
+				return
+			}
+			defer d.releaseChild(c)
+
+			resChan := make(chan types.Response)
+			select {
+			case c.tsChan <- types.NewTransaction(msgCopy, resChan):
+			case <-d.ctx.Done():
+				setErr(types.ErrTypeClosed)
+				return
+			}
+			select {
+			case res := <-resChan:
+				if res.Error() != nil {
+					d.mOutputErr.Incr(1)
+					if bErr, ok := res.Error().(*batch.Error); ok {
+						bErr.WalkParts(func(i int, p types.Part, e error) bool {
+							if e != nil {
+								setErrForPart(p, e)
+							}
+							return true
+						})
+					} else {
+						setErr(res.Error())
+					}
+				} else {
+					d.mMsgSnt.Incr(1)
+				}
+			case <-d.ctx.Done():
+				setErr(types.ErrTypeClosed)
+			}
+		}()
+	}
+
+	wg.Wait()
+	if batchErr != nil {
+		return batchErr
+	}
+	return generalErr
+}
+
+// loop is an internal loop that routes incoming messages to child outputs.
+func (d *DynamicFanOut) loop() {
+	var wg sync.WaitGroup
+
+	idleDone := make(chan struct{})
+	if d.idleTimeout > 0 {
+		go func() {
+			ticker := time.NewTicker(d.idleTimeout / 2)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					d.closeIdleChildren()
+				case <-idleDone:
+					return
+				}
+			}
+		}()
+	}
+
+	defer func() {
+		wg.Wait()
+		close(idleDone)
+
+		d.childMut.Lock()
+		for k, c := range d.children {
+			d.removeChild(k, c)
+		}
+		d.childMut.Unlock()
+
+		d.closeWG.Wait()
+		close(d.closedChan)
+	}()
+
+	sendLoop := func() {
+		defer wg.Done()
+		for {
+			var ts types.Transaction
+			var open bool
+
+			select {
+			case ts, open = <-d.transactions:
+				if !open {
+					return
+				}
+			case <-d.ctx.Done():
+				return
+			}
+			d.mMsgRcvd.Incr(1)
+
+			group, trackedMsg := imessage.NewSortGroup(ts.Payload)
+
+			var keys []string
+			targets := map[string][]types.Part{}
+			trackedMsg.Iter(func(i int, p types.Part) error {
+				key := d.key.String(i, trackedMsg)
+				if _, exists := targets[key]; !exists {
+					keys = append(keys, key)
+				}
+				targets[key] = append(targets[key], p.Copy())
+				return nil
+			})
+
+			var oResponse types.Response = response.NewAck()
+			if err := d.dispatch(group, trackedMsg, keys, targets); err != nil {
+				oResponse = response.NewError(err)
+			}
+			select {
+			case ts.ResponseChan <- oResponse:
+			case <-d.ctx.Done():
+				return
+			}
+		}
+	}
+
+	for i := 0; i < d.maxInFlight; i++ {
+		wg.Add(1)
+		go sendLoop()
+	}
+}
+
+// CloseAsync shuts down the DynamicFanOut output and stops processing
+// requests.
+func (d *DynamicFanOut) CloseAsync() {
+	d.close()
+}
+
+// WaitForClose blocks until the DynamicFanOut output has closed down.
+func (d *DynamicFanOut) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-d.closedChan:
+	case <-time.After(timeout):
+		return types.ErrTimeout
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,150 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDynamicFanOutForTest(t *testing.T, dir string, maxOutputs int, idleTimeout string) (*DynamicFanOut, chan types.Transaction) {
+	t.Helper()
+
+	childConf := NewConfig()
+	childConf.Type = TypeFile
+	childConf.File.Path = filepath.Join(dir, `${! meta("tenant") }.txt`)
+	childConf.File.Codec = "lines"
+
+	conf := NewConfig()
+	conf.Type = TypeDynamicFanOut
+	conf.DynamicFanOut.Key = `${! meta("tenant") }`
+	conf.DynamicFanOut.Output = &childConf
+	conf.DynamicFanOut.MaxOutputs = maxOutputs
+	conf.DynamicFanOut.IdleTimeout = idleTimeout
+
+	o, err := NewDynamicFanOut(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		o.CloseAsync()
+		assert.NoError(t, o.WaitForClose(time.Second*5))
+	})
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, o.Consume(tChan))
+	return o.(*DynamicFanOut), tChan
+}
+
+func sendDynamicFanOutBatch(t *testing.T, tChan chan types.Transaction, tenants ...string) error {
+	t.Helper()
+
+	msg := message.New(nil)
+	for _, tenant := range tenants {
+		part := message.NewPart([]byte("hello " + tenant))
+		part.Metadata().Set("tenant", tenant)
+		msg.Append(part)
+	}
+
+	resChan := make(chan types.Response)
+	select {
+	case tChan <- types.NewTransaction(msg, resChan):
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+	select {
+	case res := <-resChan:
+		return res.Error()
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+	return nil
+}
+
+func (d *DynamicFanOut) childCount() int {
+	d.childMut.Lock()
+	defer d.childMut.Unlock()
+	return len(d.children)
+}
+
+func TestDynamicFanOutRouting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benthos_dynamic_fan_out_test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	o, tChan := newDynamicFanOutForTest(t, dir, 0, "")
+
+	require.NoError(t, sendDynamicFanOutBatch(t, tChan, "foo", "bar", "foo"))
+	require.NoError(t, sendDynamicFanOutBatch(t, tChan, "baz"))
+	assert.Equal(t, 3, o.childCount())
+
+	assert.Eventually(t, func() bool {
+		fooBytes, _ := ioutil.ReadFile(filepath.Join(dir, "foo.txt"))
+		barBytes, _ := ioutil.ReadFile(filepath.Join(dir, "bar.txt"))
+		bazBytes, _ := ioutil.ReadFile(filepath.Join(dir, "baz.txt"))
+		return string(fooBytes) == "hello foo\nhello foo\n\n" &&
+			string(barBytes) == "hello bar\n" &&
+			string(bazBytes) == "hello baz\n"
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func TestDynamicFanOutMaxOutputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benthos_dynamic_fan_out_test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	o, tChan := newDynamicFanOutForTest(t, dir, 2, "")
+
+	require.NoError(t, sendDynamicFanOutBatch(t, tChan, "foo"))
+	require.NoError(t, sendDynamicFanOutBatch(t, tChan, "bar"))
+	require.NoError(t, sendDynamicFanOutBatch(t, tChan, "baz"))
+	assert.Equal(t, 2, o.childCount())
+
+	o.childMut.Lock()
+	_, fooExists := o.children["foo"]
+	_, bazExists := o.children["baz"]
+	o.childMut.Unlock()
+	assert.False(t, fooExists, "least recently used output should be closed")
+	assert.True(t, bazExists)
+}
+
+func TestDynamicFanOutIdleTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benthos_dynamic_fan_out_test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	o, tChan := newDynamicFanOutForTest(t, dir, 0, "50ms")
+
+	require.NoError(t, sendDynamicFanOutBatch(t, tChan, "foo", "bar"))
+	assert.Eventually(t, func() bool {
+		return o.childCount() == 0
+	}, time.Second*5, time.Millisecond*10)
+
+	require.NoError(t, sendDynamicFanOutBatch(t, tChan, "foo"))
+	assert.Eventually(t, func() bool {
+		fooBytes, _ := ioutil.ReadFile(filepath.Join(dir, "foo.txt"))
+		return string(fooBytes) == "hello foo\nhello foo\n"
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func TestDynamicFanOutBadConfig(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = TypeDynamicFanOut
+	conf.DynamicFanOut.Key = `${! meta("tenant") }`
+
+	_, err := NewDynamicFanOut(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "cannot create a dynamic_fan_out output without a template output")
+
+	childConf := NewConfig()
+	conf.DynamicFanOut.Output = &childConf
+	conf.DynamicFanOut.Key = ""
+
+	_, err = NewDynamicFanOut(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "cannot create a dynamic_fan_out output without a key")
+}
@@ -26,6 +26,19 @@ Both the ` + "`id` and `index`" + ` fields can be dynamically set using function
 interpolations described [here](/docs/configuration/interpolation#bloblang-queries). When
 sending batched messages these interpolations are performed per message part.
 
+### Rejected Documents
+
+When sending batched messages documents that fail for temporary reasons, such as
+the cluster being overloaded, are retried according to the ` + "`backoff`" + `
+fields. Documents that are rejected for reasons that retrying would not resolve,
+such as a conflict with the mapping of the index, are not retried, and instead
+only their messages are flagged as failed once all other documents are written.
+
+Documents that conflict with the mapping of an index are counted by the metric
+` + "`error.mapping`" + `, and in order to avoid reattempting them indefinitely
+they can instead be logged and dropped by setting ` + "`mapping_errors`" + ` to
+` + "`drop`" + `.
+
 ### AWS
 
 It's possible to enable AWS connectivity with this output using the ` + "`aws`" + `
@@ -44,6 +57,10 @@ false for connections to succeed.`,
 			docs.FieldAdvanced("timeout", "The maximum time to wait before abandoning a request (and trying again)."),
 			tls.FieldSpec(),
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldAdvanced("mapping_errors", "How to handle documents that are rejected because they conflict with the mapping of the index.").HasAnnotatedOptions(
+				"fail", "Flag the messages of the documents as failed.",
+				"drop", "Log and drop the documents.",
+			).AtVersion("3.51.0"),
 		}.Merge(retries.FieldSpecs()).Add(
 			auth.BasicAuthFieldSpec(),
 			batch.FieldSpec(),
@@ -0,0 +1,173 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	Constructors[TypeErrorCatch] = TypeSpec{
+		constructor: fromSimpleConstructor(NewErrorCatch),
+		Status:      docs.StatusExperimental,
+		Version:     "3.51.0",
+		Summary: `
+Routes messages that have failed during processing to a dead letter output, and all other messages to a primary output.`,
+		Description: `
+Messages that have been flagged with a processing error, which can be detected with the Bloblang function ` + "[`errored`](/docs/guides/bloblang/functions#errored)" + `, are routed to the ` + "`dead_letter`" + ` output. Before being sent these messages are enriched with the following metadata fields describing the failure:
+
+` + "```text" + `
+- error_component
+- error_msg
+- error_timestamp
+` + "```" + `
+
+The field ` + "`error_component`" + ` contains the label of the processor that flagged the most recent error, or its path when the processor is not labelled. The field ` + "`error_msg`" + ` contains the error string and ` + "`error_timestamp`" + ` contains the time at which the message was routed in RFC 3339 format.
+
+Messages that have not failed are sent to the primary ` + "`output`" + ` unchanged. This is equivalent to a ` + "[`switch` output](/docs/components/outputs/switch)" + ` with a case checking ` + "`errored()`" + `, and more information about error handling patterns can be found [here](/docs/configuration/error_handling).`,
+		Categories: []Category{
+			CategoryUtility,
+		},
+		FieldSpecs: docs.FieldSpecs{
+			docs.FieldCommon("output", "The primary output to send messages that have not failed to.").HasType(docs.FieldTypeOutput),
+			docs.FieldCommon("dead_letter", "The output to send failed messages to.").HasType(docs.FieldTypeOutput),
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Dead Letter Queue",
+				Summary: "In this example messages are consumed from Kafka and written to Elasticsearch, but any documents that fail processing are written to a separate Kafka topic along with details of the failure.",
+				Config: `
+input:
+  kafka:
+    addresses: [ TODO ]
+    topics: [ foo ]
+    consumer_group: foogroup
+
+pipeline:
+  processors:
+    - label: parse_doc
+      bloblang: root = this.doc.parse_json()
+
+output:
+  error_catch:
+    output:
+      elasticsearch:
+        urls: [ TODO ]
+        index: foo
+    dead_letter:
+      kafka:
+        addresses: [ TODO ]
+        topic: foo_dlq
+`,
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ErrorCatchConfig contains configuration fields for the ErrorCatch output
+// type.
+type ErrorCatchConfig struct {
+	Output     *Config `json:"output" yaml:"output"`
+	DeadLetter *Config `json:"dead_letter" yaml:"dead_letter"`
+}
+
+// NewErrorCatchConfig creates a new ErrorCatchConfig with default values.
+func NewErrorCatchConfig() ErrorCatchConfig {
+	return ErrorCatchConfig{
+		Output:     nil,
+		DeadLetter: nil,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type dummyErrorCatchConfig struct {
+	Output     interface{} `json:"output" yaml:"output"`
+	DeadLetter interface{} `json:"dead_letter" yaml:"dead_letter"`
+}
+
+func (e ErrorCatchConfig) dummy() dummyErrorCatchConfig {
+	dummy := dummyErrorCatchConfig{
+		Output:     e.Output,
+		DeadLetter: e.DeadLetter,
+	}
+	if e.Output == nil {
+		dummy.Output = struct{}{}
+	}
+	if e.DeadLetter == nil {
+		dummy.DeadLetter = struct{}{}
+	}
+	return dummy
+}
+
+// MarshalJSON prints an empty object instead of nil.
+func (e ErrorCatchConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.dummy())
+}
+
+// MarshalYAML prints an empty object instead of nil.
+func (e ErrorCatchConfig) MarshalYAML() (interface{}, error) {
+	return e.dummy(), nil
+}
+
+//------------------------------------------------------------------------------
+
+// errorCatchEnrichMapping adds metadata describing the processing error of a
+// message before it is sent to the dead letter output.
+const errorCatchEnrichMapping = `
+let src = error_source()
+meta error_component = match {
+  $src == null => deleted()
+  $src.label != "" => $src.label
+  _ => $src.path
+}
+meta error_msg = error()
+meta error_timestamp = now()
+`
+
+// NewErrorCatch creates a new ErrorCatch output type, which is a switch output
+// that routes failed messages to a dead letter output.
+func NewErrorCatch(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+) (Type, error) {
+	if conf.ErrorCatch.Output == nil {
+		return nil, errors.New("cannot create an error_catch output without a primary output")
+	}
+	if conf.ErrorCatch.DeadLetter == nil {
+		return nil, errors.New("cannot create an error_catch output without a dead_letter output")
+	}
+
+	enrichConf := processor.NewConfig()
+	enrichConf.Type = processor.TypeBloblang
+	enrichConf.Bloblang.Mapping = errorCatchEnrichMapping
+
+	deadLetterCase := NewSwitchConfigCase()
+	deadLetterCase.Check = "errored()"
+	deadLetterCase.Output = *conf.ErrorCatch.DeadLetter
+	deadLetterCase.Output.Processors = append(
+		[]processor.Config{enrichConf},
+		deadLetterCase.Output.Processors...,
+	)
+
+	primaryCase := NewSwitchConfigCase()
+	primaryCase.Output = *conf.ErrorCatch.Output
+
+	sConf := NewConfig()
+	sConf.Type = TypeSwitch
+	sConf.Switch.Cases = []SwitchConfigCase{deadLetterCase, primaryCase}
+	return NewSwitch(sConf, mgr, log, stats)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,127 @@
+package output_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestErrorCatch(t *testing.T) {
+	mgr, err := manager.New(manager.NewConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := output.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+error_catch:
+  output:
+    inproc: happy
+  dead_letter:
+    inproc: sad
+`), &conf))
+
+	o, err := output.New(conf, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	defer func() {
+		o.CloseAsync()
+		assert.NoError(t, o.WaitForClose(time.Second*5))
+	}()
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, o.Consume(tChan))
+
+	sendAndReceive := func(pipe string, part types.Part) types.Part {
+		t.Helper()
+
+		resChan := make(chan types.Response)
+		msg := message.New(nil)
+		msg.Append(part)
+
+		select {
+		case tChan <- types.NewTransaction(msg, resChan):
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+
+		var outChan <-chan types.Transaction
+		require.Eventually(t, func() bool {
+			outChan, err = mgr.GetPipe(pipe)
+			return err == nil
+		}, time.Second*5, time.Millisecond*10)
+
+		var outTran types.Transaction
+		select {
+		case outTran = <-outChan:
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+		require.Equal(t, 1, outTran.Payload.Len())
+
+		select {
+		case outTran.ResponseChan <- response.NewAck():
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+
+		select {
+		case res := <-resChan:
+			require.NoError(t, res.Error())
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+		return outTran.Payload.Get(0)
+	}
+
+	happyPart := sendAndReceive("happy", message.NewPart([]byte("hello world")))
+	assert.Equal(t, "hello world", string(happyPart.Get()))
+	assert.Equal(t, "", happyPart.Metadata().Get("error_msg"))
+	assert.Equal(t, "", happyPart.Metadata().Get("error_component"))
+
+	failedPart := message.NewPart([]byte("bad world"))
+	failedPart.Metadata().
+		Set(types.FailFlagKey, "it broke").
+		Set(types.ErrorSourceLabelKey, "parse_doc").
+		Set(types.ErrorSourcePathKey, "pipeline.processor.0").
+		Set(types.ErrorSourceTypeKey, "bloblang")
+
+	sadPart := sendAndReceive("sad", failedPart)
+	assert.Equal(t, "bad world", string(sadPart.Get()))
+	assert.Equal(t, "it broke", sadPart.Metadata().Get("error_msg"))
+	assert.Equal(t, "parse_doc", sadPart.Metadata().Get("error_component"))
+
+	ts, err := time.Parse(time.RFC3339Nano, sadPart.Metadata().Get("error_timestamp"))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), ts, time.Minute)
+
+	unlabelledPart := message.NewPart([]byte("bad world"))
+	unlabelledPart.Metadata().
+		Set(types.FailFlagKey, "it broke again").
+		Set(types.ErrorSourcePathKey, "pipeline.processor.1").
+		Set(types.ErrorSourceTypeKey, "bloblang")
+
+	sadPart = sendAndReceive("sad", unlabelledPart)
+	assert.Equal(t, "it broke again", sadPart.Metadata().Get("error_msg"))
+	assert.Equal(t, "pipeline.processor.1", sadPart.Metadata().Get("error_component"))
+}
+
+func TestErrorCatchMissingChildren(t *testing.T) {
+	conf := output.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+error_catch:
+  output:
+    drop: {}
+`), &conf))
+
+	_, err := output.New(conf, nil, log.Noop(), metrics.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dead_letter")
+}
@@ -1,6 +1,9 @@
 package output
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message/batch"
@@ -8,6 +11,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/output/writer"
 	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/Jeffail/benthos/v3/lib/util/http/client"
+	"github.com/cenkalti/backoff/v4"
 )
 
 func init() {
@@ -23,23 +27,97 @@ the send is attempted again until successful whilst applying back pressure.
 The URL and header values of this type can be dynamically set using function
 interpolations described [here](/docs/configuration/interpolation#bloblang-queries).
 
+### Dynamic URLs
+
+The ` + "`url`" + ` field is resolved for each message, which allows a single output
+to send messages to many endpoints. Connections are pooled by the resolved host
+and so keep-alive connections are reused across messages that target the same
+host. If an interpolation of the URL fails or resolves to null, such as when a
+referenced metadata field is missing, the request fails unless
+` + "`url_fallback`" + ` is set, in which case the fallback URL is used instead:
+
+` + "```yaml" + `
+output:
+  http_client:
+    url: ${! meta("target_url") }
+    url_fallback: http://localhost:4195/unrouted
+` + "```" + `
+
 The body of the HTTP request is the raw contents of the message payload. If the
 message has multiple parts (is a batch) the request will be sent according to
 [RFC1341](https://www.w3.org/Protocols/rfc1341/7_2_Multipart.html). This
 behaviour can be disabled by setting the field ` + "[`batch_as_multipart`](#batch_as_multipart) to `false`" + `.
 
+### Multipart Form Data
+
+Requests can instead be sent as ` + "`multipart/form-data`" + ` bodies, as
+expected by most file upload APIs, by listing the fields of the form within
+` + "`multipart`" + `. The name, filename, content type and content of each field
+are resolved per message with [interpolation functions](/docs/configuration/interpolation#bloblang-queries),
+and a field without ` + "`content`" + ` contains the raw message payload. When a
+batch is sent as a single request the fields are added to the body for each
+message of the batch.
+
+` + "```yaml" + `
+output:
+  http_client:
+    url: https://example.com/upload
+    verb: POST
+    multipart:
+      - name: file
+        filename: ${! meta("filename") }
+        content_type: text/csv
+      - name: description
+        content: ${! meta("description") }
+` + "```" + `
+
+The ` + "`Content-Type`" + ` header is set to ` + "`multipart/form-data`" + `
+with a generated boundary, overriding any value set within ` + "`headers`" + `.
+Bodies are streamed to the server as they are written rather than being
+buffered.
+
 ### Propagating Responses
 
 It's possible to propagate the response from each HTTP request back to the input
 source by setting ` + "`propagate_response` to `true`" + `. Only inputs that
 support [synchronous responses](/docs/guides/sync_responses) are able to make use of
-these propagated responses.`,
+these propagated responses.
+
+### Idempotency Keys
+
+When ` + "`idempotency.enabled` is `true`" + ` each request is sent with a
+header, ` + "`Idempotency-Key`" + ` by default, containing a key that remains the
+same for every retry of the message, allowing servers that support idempotency
+keys to safely discard duplicate requests. The key is resolved from the
+interpolated field ` + "`idempotency.key`" + `, or when left empty a random UUID
+is generated, once for each message and is stored in the metadata field
+` + "`http_idempotency_key`" + `. When a batch is sent as a single multipart
+request the whole batch shares one key, which is either a random UUID or a
+SHA-256 hex digest of the interpolated key of each message of the batch.
+
+Retries performed by this output always reuse the same key. However, if a
+message is rejected and redelivered by the input then a generated key will
+differ, and therefore in order to remain idempotent across redeliveries the key
+should be derived from the message itself, e.g.
+` + "`${! meta(\"kafka_topic\") }-${! meta(\"kafka_partition\") }-${! meta(\"kafka_offset\") }`" + `.`,
 		Async:   true,
 		Batches: true,
 		FieldSpecs: client.FieldSpecs().Add(
 			docs.FieldAdvanced("batch_as_multipart", "Send message batches as a single request using [RFC1341](https://www.w3.org/Protocols/rfc1341/7_2_Multipart.html). If disabled messages in batches will be sent as individual requests."),
 			docs.FieldAdvanced("propagate_response", "Whether responses from the server should be [propagated back](/docs/guides/sync_responses) to the input."),
+			docs.FieldAdvanced("idempotency", "Send a [key](#idempotency-keys) with each request that remains the same across retries of a message.").WithChildren(
+				docs.FieldAdvanced("enabled", "Whether to send idempotency keys."),
+				docs.FieldAdvanced("header", "The header to send the key with."),
+				docs.FieldAdvanced("key", "An optional expression for the key, when empty a random UUID is generated for each message.", `${! json("id") }`, `${! meta("kafka_key") }`).IsInterpolated(),
+			).AtVersion("3.51.0"),
+			docs.FieldAdvanced("multipart", "An optional list of fields from which requests are sent as [`multipart/form-data`](#multipart-form-data) bodies. When set the default request body behaviour is overridden and these fields are added to the body for each message of a batch.").Array().WithChildren(
+				docs.FieldString("name", "The name of the form field.", "file", `${! meta("field") }`).IsInterpolated().HasDefault(""),
+				docs.FieldString("filename", "An optional filename of the form field, which marks the field as a file upload.", `${! meta("filename") }`, "report.csv").IsInterpolated().HasDefault(""),
+				docs.FieldString("content_type", "An optional content type of the form field. When empty file uploads default to `application/octet-stream` and other fields have no content type.", "application/json").IsInterpolated().HasDefault(""),
+				docs.FieldString("content", "The content of the form field. When empty the raw contents of the message are used.", `${! json("description") }`).IsInterpolated().HasDefault(""),
+			).AtVersion("3.51.0"),
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			docs.FieldAdvanced("preserve_order", "Whether to send requests in the order that messages were received. When enabled `max_in_flight` is ignored and only one request is made at a time, and a request that still fails after its `retries` are exhausted is attempted again, waiting between `retry_period` and `max_retry_backoff`, until it succeeds rather than the message being rejected.").AtVersion("3.51.0"),
 		).Add(batch.FieldSpec()),
 		Categories: []Category{
 			CategoryNetwork,
@@ -57,8 +135,40 @@ func NewHTTPClient(conf Config, mgr types.Manager, log log.Modular, stats metric
 	if err != nil {
 		return w, err
 	}
+	if conf.HTTPClient.PreserveOrder {
+		boffCtor, err := httpClientBackoffCtor(conf.HTTPClient)
+		if err != nil {
+			return nil, err
+		}
+		w.(*AsyncWriter).SetPreserveOrder(boffCtor)
+	}
 	if !conf.HTTPClient.BatchAsMultipart {
 		w = OnlySinglePayloads(w)
 	}
 	return NewBatcherFromConfig(conf.HTTPClient.Batching, w, mgr, log, stats)
 }
+
+// httpClientBackoffCtor returns a constructor for backoffs between the retries
+// of a batch that mirror the retries of individual requests, where the period
+// starts at the retry period and increases up to the max retry backoff.
+func httpClientBackoffCtor(conf writer.HTTPClientConfig) (func() backoff.BackOff, error) {
+	var retry, maxBackoff time.Duration
+	var err error
+	if conf.Retry != "" {
+		if retry, err = time.ParseDuration(conf.Retry); err != nil {
+			return nil, fmt.Errorf("failed to parse retry duration string: %v", err)
+		}
+	}
+	if conf.MaxBackoff != "" {
+		if maxBackoff, err = time.ParseDuration(conf.MaxBackoff); err != nil {
+			return nil, fmt.Errorf("failed to parse max backoff duration string: %v", err)
+		}
+	}
+	return func() backoff.BackOff {
+		boff := backoff.NewExponentialBackOff()
+		boff.InitialInterval = retry
+		boff.MaxInterval = maxBackoff
+		boff.MaxElapsedTime = 0
+		return boff
+	}, nil
+}
@@ -35,30 +35,48 @@ When strict ordering is required for messages written to topic partitions it is
 
 You must also ensure that failed batches are never rerouted back to the same output. This can be done by setting the field ` + "`max_retries` to `0` and `backoff.max_elapsed_time`" + ` to empty, which will apply back pressure indefinitely until the batch is sent successfully.
 
-However, this also means that manual intervention will eventually be required in cases where the batch cannot be sent due to configuration problems such as an incorrect ` + "`max_msg_bytes`" + ` estimate. A less strict but automated alternative would be to route failed batches to a dead letter queue using a ` + "[`try` broker](/docs/components/outputs/try)" + `, but this would allow subsequent batches to be delivered in the meantime whilst those failed batches are dealt with.`,
+Alternatively, the field ` + "`preserve_order`" + ` can be set to ` + "`true`" + `, which limits the number of batches in flight to one and retries failed batches until they are sent successfully.
+
+However, this also means that manual intervention will eventually be required in cases where the batch cannot be sent due to configuration problems such as an incorrect ` + "`max_msg_bytes`" + ` estimate. A less strict but automated alternative would be to route failed batches to a dead letter queue using a ` + "[`try` broker](/docs/components/outputs/try)" + `, but this would allow subsequent batches to be delivered in the meantime whilst those failed batches are dealt with.
+
+### Graceful Shutdown
+
+When Benthos shuts down the output stops retrying failed sends and waits for
+messages in flight to be acknowledged by the brokers for up to
+` + "`shutdown_timeout`" + `. Messages that are not acknowledged within this
+period, including the remainder of partially sent batches, are rejected so that
+inputs with at-least-once delivery guarantees are able to redeliver them.
+
+The number of messages that failed to flush during shutdown is tracked by the
+metric ` + "`shutdown.flush_failed`" + ` and is logged once the output has
+closed. The ` + "`shutdown_timeout`" + ` should be shorter than the root
+level ` + "`shutdown_timeout`" + ` in order for these messages to be reported
+before Benthos forcefully exits.`,
 		Async:   true,
 		Batches: true,
 		FieldSpecs: append(docs.FieldSpecs{
 			docs.FieldDeprecated("round_robin_partitions"),
-			docs.FieldCommon("addresses", "A list of broker addresses to connect to. If an item of the list contains commas it will be expanded into multiple addresses.", []string{"localhost:9092"}, []string{"localhost:9041,localhost:9042"}, []string{"localhost:9041", "localhost:9042"}).Array(),
-			tls.FieldSpec(),
-			sasl.FieldSpec(),
-			docs.FieldCommon("topic", "The topic to publish messages to.").IsInterpolated(),
-			docs.FieldCommon("client_id", "An identifier for the client connection."),
-			docs.FieldCommon("key", "The key to publish messages with.").IsInterpolated(),
-			docs.FieldCommon("partitioner", "The partitioning algorithm to use.").HasOptions("fnv1a_hash", "murmur2_hash", "random", "round_robin"),
-			docs.FieldCommon("compression", "The compression algorithm to use.").HasOptions("none", "snappy", "lz4", "gzip"),
-			docs.FieldString("static_headers", "An optional map of static headers that should be added to messages in addition to metadata.", map[string]string{"first-static-header": "value-1", "second-static-header": "value-2"}).Map(),
-			docs.FieldCommon("metadata", "Specify criteria for which metadata values are sent with messages as headers.").WithChildren(output.MetadataFields()...),
-			output.InjectTracingSpanMappingDocs,
-			docs.FieldCommon("max_in_flight", "The maximum number of parallel message batches to have in flight at any given time."),
-			docs.FieldAdvanced("ack_replicas", "Ensure that messages have been copied across all replicas before acknowledging receipt."),
-			docs.FieldAdvanced("max_msg_bytes", "The maximum size in bytes of messages sent to the target topic."),
-			docs.FieldAdvanced("timeout", "The maximum period of time to wait for message sends before abandoning the request and retrying."),
-			docs.FieldAdvanced("target_version", "The version of the Kafka protocol to use."),
-			docs.FieldAdvanced("retry_as_batch", "When enabled forces an entire batch of messages to be retried if any individual message fails on a send, otherwise only the individual messages that failed are retried. Disabling this helps to reduce message duplicates during intermittent errors, but also makes it impossible to guarantee strict ordering of messages."),
-			batch.FieldSpec(),
-		}, retries.FieldSpecs()...),
+			docs.FieldCommon("addresses", "A list of broker addresses to connect to. If an item of the list contains commas it will be expanded into multiple addresses.", []string{"localhost:9092"}, []string{"localhost:9041,localhost:9042"}, []string{"localhost:9041", "localhost:9042"}).Array().InGroup("Connection"),
+			tls.FieldSpec().InGroup("Connection"),
+			sasl.FieldSpec().InGroup("Connection"),
+			docs.FieldCommon("topic", "The topic to publish messages to.").IsInterpolated().InGroup("Messages"),
+			docs.FieldCommon("client_id", "An identifier for the client connection.").InGroup("Connection"),
+			docs.FieldCommon("key", "The key to publish messages with.").IsInterpolated().InGroup("Messages"),
+			docs.FieldCommon("partitioner", "The partitioning algorithm to use.").HasOptions("fnv1a_hash", "murmur2_hash", "random", "round_robin").InGroup("Messages"),
+			docs.FieldCommon("compression", "The compression algorithm to use.").HasOptions("none", "snappy", "lz4", "gzip").InGroup("Messages"),
+			docs.FieldString("static_headers", "An optional map of static headers that should be added to messages in addition to metadata.", map[string]string{"first-static-header": "value-1", "second-static-header": "value-2"}).Map().InGroup("Messages"),
+			docs.FieldCommon("metadata", "Specify criteria for which metadata values are sent with messages as headers.").WithChildren(output.MetadataFields()...).InGroup("Messages"),
+			output.InjectTracingSpanMappingDocs.InGroup("Messages"),
+			docs.FieldCommon("max_in_flight", "The maximum number of parallel message batches to have in flight at any given time.").InGroup("Delivery"),
+			docs.FieldAdvanced("preserve_order", "Whether to write messages to each partition in the order that they were received. When enabled only one batch is written at a time regardless of `max_in_flight`, and a batch that fails is retried with the intervals of the `backoff` fields until it succeeds, rather than being nacked and allowing later batches to be written first.").AtVersion("3.51.0").InGroup("Delivery"),
+			docs.FieldAdvanced("ack_replicas", "Ensure that messages have been copied across all replicas before acknowledging receipt.").InGroup("Delivery"),
+			docs.FieldAdvanced("max_msg_bytes", "The maximum size in bytes of messages sent to the target topic.").InGroup("Messages"),
+			docs.FieldAdvanced("timeout", "The maximum period of time to wait for message sends before abandoning the request and retrying.").InGroup("Delivery"),
+			docs.FieldAdvanced("shutdown_timeout", "The maximum period of time to wait for in flight messages to be acknowledged once the output begins [shutting down](#graceful-shutdown), after which they are rejected. Set to an empty string in order to wait indefinitely.").AtVersion("3.51.0").InGroup("Delivery"),
+			docs.FieldAdvanced("target_version", "The version of the Kafka protocol to use.").InGroup("Connection"),
+			docs.FieldAdvanced("retry_as_batch", "When enabled forces an entire batch of messages to be retried if any individual message fails on a send, otherwise only the individual messages that failed are retried. Disabling this helps to reduce message duplicates during intermittent errors, but also makes it impossible to guarantee strict ordering of messages.").InGroup("Delivery"),
+			batch.FieldSpec().InGroup("Delivery"),
+		}, retries.FieldSpecs().InGroup("Delivery")...),
 		Categories: []Category{
 			CategoryServices,
 		},
@@ -79,6 +97,13 @@ func NewKafka(conf Config, mgr types.Manager, log log.Modular, stats metrics.Typ
 	if err != nil {
 		return nil, err
 	}
+	if conf.Kafka.PreserveOrder {
+		boffCtor, err := conf.Kafka.Config.GetCtor()
+		if err != nil {
+			return nil, err
+		}
+		w.(*AsyncWriter).SetPreserveOrder(boffCtor)
+	}
 
 	if conf.Kafka.InjectTracingMap != "" {
 		aw, ok := w.(*AsyncWriter)
@@ -66,11 +66,16 @@ func NewLineWriter(
 func (w *LineWriter) loop() {
 	// Metrics paths
 	var (
-		mCount     = w.stats.GetCounter("count")
-		mPartsSent = w.stats.GetCounter("sent")
-		mSent      = w.stats.GetCounter("batch.sent")
-		mBytesSent = w.stats.GetCounter("batch.bytes")
-		mLatency   = w.stats.GetTimer("batch.latency")
+		mCount      = w.stats.GetCounter("count")
+		mPartsSent  = w.stats.GetCounter("sent")
+		mSent       = w.stats.GetCounter("batch.sent")
+		mPartsAcked = w.stats.GetCounter("acked")
+		mAcked      = w.stats.GetCounter("batch.acked")
+		mBytesSent  = w.stats.GetCounter("batch.bytes")
+		mLatency    = w.stats.GetTimer("batch.latency")
+		mPartsError = w.stats.GetCounter("error")
+		mError      = w.stats.GetCounter("batch.error")
+		mInFlight   = w.stats.GetGauge("batch.in_flight")
 	)
 
 	defer func() {
@@ -100,8 +105,11 @@ func (w *LineWriter) loop() {
 
 		w.log.Tracef("Attempting to write %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
 		spans := tracing.CreateChildSpans("output_"+w.typeStr, ts.Payload)
+		mSent.Incr(1)
+		mPartsSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 
 		var err error
+		mInFlight.Incr(1)
 		t0 := time.Now()
 		if ts.Payload.Len() == 1 {
 			_, err = fmt.Fprintf(w.handle, "%s%s", ts.Payload.Get(0).Get(), delim)
@@ -109,9 +117,14 @@ func (w *LineWriter) loop() {
 			_, err = fmt.Fprintf(w.handle, "%s%s%s", bytes.Join(message.GetAllBytes(ts.Payload), delim), delim, delim)
 		}
 		latency := time.Since(t0).Nanoseconds()
-		if err == nil {
-			mSent.Incr(1)
-			mPartsSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
+		mInFlight.Decr(1)
+		if err != nil {
+			w.log.Errorf("Failed to send message to %v: %v\n", w.typeStr, err)
+			mError.Incr(1)
+			mPartsError.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
+		} else {
+			mAcked.Incr(1)
+			mPartsAcked.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 			mBytesSent.Incr(int64(message.GetAllBytesLen(ts.Payload)))
 			w.log.Tracef("Successfully wrote %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
 			mLatency.Timing(latency)
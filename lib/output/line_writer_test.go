@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 	"time"
 
@@ -9,6 +10,8 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testBuffer struct {
@@ -169,3 +172,60 @@ func TestLineWriterCustomDelim(t *testing.T) {
 		t.Error("Buffer was not closed by writer")
 	}
 }
+
+type failingLineHandle struct {
+	fail bool
+}
+
+func (f *failingLineHandle) Write(p []byte) (int, error) {
+	if f.fail {
+		return 0, errors.New("nope")
+	}
+	return len(p), nil
+}
+
+func (f *failingLineHandle) Close() error {
+	return nil
+}
+
+func TestLineWriterMetrics(t *testing.T) {
+	handle := &failingLineHandle{}
+	stats := metrics.NewLocal()
+
+	msgChan := make(chan types.Transaction)
+	resChan := make(chan types.Response)
+
+	writer, err := NewLineWriter(handle, true, []byte{}, "foo", log.Noop(), stats)
+	require.NoError(t, err)
+	require.NoError(t, writer.Consume(msgChan))
+
+	for _, fail := range []bool{false, true} {
+		handle.fail = fail
+		select {
+		case msgChan <- types.NewTransaction(message.New([][]byte{[]byte("foo"), []byte("bar")}), resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		select {
+		case res := <-resChan:
+			assert.Equal(t, fail, res.Error() != nil)
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+	}
+
+	writer.CloseAsync()
+	require.NoError(t, writer.WaitForClose(time.Second))
+
+	assert.Equal(t, map[string]int64{
+		"count":           2,
+		"sent":            4,
+		"batch.sent":      2,
+		"acked":           2,
+		"batch.acked":     1,
+		"batch.bytes":     6,
+		"error":           2,
+		"batch.error":     1,
+		"batch.in_flight": 0,
+	}, stats.GetCounters())
+}
@@ -8,10 +8,11 @@ import (
 // NATSJetStreamConfig contains configuration fields for the NATS Jetstream
 // input type.
 type NATSJetStreamConfig struct {
-	URLs        []string   `json:"urls" yaml:"urls"`
-	Subject     string     `json:"subject" yaml:"subject"`
-	MaxInFlight int        `json:"max_in_flight" yaml:"max_in_flight"`
-	TLS         tls.Config `json:"tls" yaml:"tls"`
+	URLs        []string          `json:"urls" yaml:"urls"`
+	Subject     string            `json:"subject" yaml:"subject"`
+	Headers     map[string]string `json:"headers" yaml:"headers"`
+	MaxInFlight int               `json:"max_in_flight" yaml:"max_in_flight"`
+	TLS         tls.Config        `json:"tls" yaml:"tls"`
 }
 
 // NewNATSJetStreamConfig creates a new NATSJetstreamConfig with default values.
@@ -19,6 +20,7 @@ func NewNATSJetStreamConfig() NATSJetStreamConfig {
 	return NATSJetStreamConfig{
 		URLs:        []string{nats.DefaultURL},
 		Subject:     "",
+		Headers:     map[string]string{},
 		MaxInFlight: 1024,
 		TLS:         tls.NewConfig(),
 	}
@@ -8,9 +8,11 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/Jeffail/benthos/v3/internal/batch"
 	"github.com/Jeffail/benthos/v3/internal/component/output"
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/response"
 	"github.com/Jeffail/benthos/v3/lib/types"
@@ -38,7 +40,23 @@ we want to avoid reapplying to the same message more than once in the pipeline.
 
 Rather than retrying the same output you may wish to retry the send using a
 different output target (a dead letter queue). In which case you should instead
-use the ` + "[`try`](/docs/components/outputs/try)" + ` output type.`,
+use the ` + "[`try`](/docs/components/outputs/try)" + ` output type.
+
+When the child output is able to report which messages of a batch failed, such
+as the ` + "`kafka`" + ` and ` + "`elasticsearch`" + ` outputs, only the failed
+messages are retried and those that were successfully delivered are not sent
+again.
+
+### Permanent Errors
+
+Some outputs classify errors that will not be resolved by retrying as
+permanent, such as HTTP responses with a status code listed in the
+` + "`permanent_on`" + ` field of the ` + "[`http_client`](/docs/components/outputs/http_client)" + `
+output, or constraint violations reported by the ` + "[`sql`](/docs/components/outputs/sql)" + `
+output. When all failed messages of a batch have a permanent error they are not
+retried, and the failure is instead passed on immediately so that it can be
+handled, for example by routing the messages to a dead letter queue with a
+` + "[`fallback`](/docs/components/outputs/fallback)" + ` output.`,
 		FieldSpecs: retries.FieldSpecs().Add(
 			docs.FieldCommon("output", "A child output.").HasType(docs.FieldTypeOutput),
 		),
@@ -168,6 +186,7 @@ func (r *Retry) loop() {
 		mPartsSuccess = r.stats.GetCounter("retry.parts.send.success")
 		mError        = r.stats.GetCounter("retry.send.error")
 		mEndOfRetries = r.stats.GetCounter("retry.end_of_retries")
+		mPermanent    = r.stats.GetCounter("retry.permanent_error")
 	)
 
 	wg := sync.WaitGroup{}
@@ -221,6 +240,12 @@ func (r *Retry) loop() {
 
 		wg.Add(1)
 		go func(ts types.Transaction, resChan chan types.Response) {
+			pending := ts.Payload
+
+			// The indexes of pending messages within the original batch, or
+			// nil when the entire batch is pending.
+			var pendingIndexes []int
+
 			var backOff backoff.BackOff
 			var resOut types.Response
 			var inErrLoop bool
@@ -255,6 +280,13 @@ func (r *Retry) loop() {
 
 					mError.Incr(1)
 
+					if permanentFailure(pending, res.Error()) {
+						mPermanent.Incr(1)
+						r.log.Errorf("Failed to send message with permanent error: %v\n", res.Error())
+						resOut = response.NewError(originalBatchError(ts.Payload, pendingIndexes, res.Error()))
+						break
+					}
+
 					if backOff == nil {
 						backOff = r.backoffCtor()
 					}
@@ -274,8 +306,9 @@ func (r *Retry) loop() {
 						return
 					}
 
+					pending, pendingIndexes = failedParts(pending, pendingIndexes, res.Error())
 					select {
-					case r.transactionsOut <- types.NewTransaction(ts.Payload, resChan):
+					case r.transactionsOut <- types.NewTransaction(pending, resChan):
 					case <-r.closeChan:
 						return
 					}
@@ -296,6 +329,80 @@ func (r *Retry) loop() {
 	}
 }
 
+// failedParts returns the messages of a batch that failed according to a batch
+// error, or the entire batch if the error does not identify individual failures.
+// The indexes of the messages within the original batch are also returned,
+// where a nil slice of indexes refers to the entire original batch.
+func failedParts(msg types.Message, indexes []int, err error) (types.Message, []int) {
+	walkable, ok := err.(batch.WalkableError)
+	if !ok || walkable.IndexedErrors() == 0 || walkable.IndexedErrors() >= msg.Len() {
+		return msg, indexes
+	}
+
+	var parts []types.Part
+	var newIndexes []int
+	walkable.WalkParts(func(i int, _ types.Part, pErr error) bool {
+		if pErr != nil && i < msg.Len() {
+			parts = append(parts, msg.Get(i))
+			if indexes == nil {
+				newIndexes = append(newIndexes, i)
+			} else {
+				newIndexes = append(newIndexes, indexes[i])
+			}
+		}
+		return true
+	})
+	if len(parts) == 0 {
+		return msg, indexes
+	}
+
+	newMsg := message.New(nil)
+	newMsg.SetAll(parts)
+	return newMsg, newIndexes
+}
+
+// originalBatchError converts an error for a subset of the original batch of a
+// transaction, identified by indexes, into an error for the original batch so
+// that the indexes of failed messages match those of the original batch.
+func originalBatchError(original types.Message, indexes []int, err error) error {
+	if indexes == nil {
+		return err
+	}
+	bErr := batch.NewError(original, err)
+	if walkable, ok := err.(batch.WalkableError); ok && walkable.IndexedErrors() > 0 {
+		walkable.WalkParts(func(i int, _ types.Part, pErr error) bool {
+			if pErr != nil && i < len(indexes) {
+				bErr.Failed(indexes[i], pErr)
+			}
+			return true
+		})
+		return bErr
+	}
+	for _, i := range indexes {
+		bErr.Failed(i, err)
+	}
+	return bErr
+}
+
+// permanentFailure returns true if every failed message of a batch failed with
+// a permanent error.
+func permanentFailure(msg types.Message, err error) bool {
+	walkable, ok := err.(batch.WalkableError)
+	if !ok || walkable.IndexedErrors() == 0 {
+		return types.IsPermanent(err)
+	}
+
+	permanent := true
+	walkable.WalkParts(func(i int, _ types.Part, pErr error) bool {
+		if pErr != nil && i < msg.Len() && !types.IsPermanent(pErr) {
+			permanent = false
+			return false
+		}
+		return true
+	})
+	return permanent
+}
+
 // Consume assigns a messages channel for the output to read.
 func (r *Retry) Consume(ts <-chan types.Transaction) error {
 	if r.transactionsIn != nil {
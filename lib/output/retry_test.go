@@ -1,14 +1,18 @@
 package output
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/Jeffail/benthos/v3/internal/batch"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/response"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRetryConfigErrs(t *testing.T) {
@@ -322,3 +326,231 @@ func TestRetryParallel(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestRetryPartialBatch(t *testing.T) {
+	conf := NewConfig()
+
+	childConf := NewConfig()
+	conf.Retry.Output = &childConf
+	conf.Retry.Backoff.InitialInterval = "10us"
+	conf.Retry.Backoff.MaxInterval = "10us"
+
+	output, err := NewRetry(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	ret, ok := output.(*Retry)
+	require.True(t, ok)
+
+	mOut := &mockOutput{
+		ts: make(chan types.Transaction),
+	}
+	ret.wrapped = mOut
+
+	tChan := make(chan types.Transaction)
+	resChan := make(chan types.Response)
+
+	require.NoError(t, ret.Consume(tChan))
+
+	testMsg := message.New([][]byte{
+		[]byte("foo"), []byte("bar"), []byte("baz"),
+	})
+
+	go func() {
+		select {
+		case tChan <- types.NewTransaction(testMsg, resChan):
+		case <-time.After(time.Second):
+			t.Error("timed out")
+		}
+	}()
+
+	var tran types.Transaction
+	select {
+	case tran = <-mOut.ts:
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	assert.Equal(t, 3, tran.Payload.Len())
+
+	batchErr := batch.NewError(tran.Payload, errors.New("meow")).Failed(1, errors.New("woof"))
+	select {
+	case tran.ResponseChan <- response.NewError(batchErr):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	select {
+	case tran = <-mOut.ts:
+	case <-resChan:
+		t.Fatal("Received response not retry")
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	require.Equal(t, 1, tran.Payload.Len())
+	assert.Equal(t, "bar", string(tran.Payload.Get(0).Get()))
+
+	select {
+	case tran.ResponseChan <- response.NewAck():
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	select {
+	case res := <-resChan:
+		assert.NoError(t, res.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	output.CloseAsync()
+	require.NoError(t, output.WaitForClose(time.Second))
+}
+
+func TestRetryPermanentError(t *testing.T) {
+	conf := NewConfig()
+
+	childConf := NewConfig()
+	conf.Retry.Output = &childConf
+	conf.Retry.Backoff.InitialInterval = "10us"
+	conf.Retry.Backoff.MaxInterval = "10us"
+
+	output, err := NewRetry(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	ret, ok := output.(*Retry)
+	require.True(t, ok)
+
+	mOut := &mockOutput{
+		ts: make(chan types.Transaction),
+	}
+	ret.wrapped = mOut
+
+	tChan := make(chan types.Transaction)
+	resChan := make(chan types.Response)
+
+	require.NoError(t, ret.Consume(tChan))
+
+	sendAndFail := func(resErr func(msg types.Message) error) types.Transaction {
+		t.Helper()
+
+		testMsg := message.New([][]byte{
+			[]byte("foo"), []byte("bar"), []byte("baz"),
+		})
+		go func() {
+			select {
+			case tChan <- types.NewTransaction(testMsg, resChan):
+			case <-time.After(time.Second):
+				t.Error("timed out")
+			}
+		}()
+
+		var tran types.Transaction
+		select {
+		case tran = <-mOut.ts:
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+
+		select {
+		case tran.ResponseChan <- response.NewError(resErr(tran.Payload)):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+		return tran
+	}
+
+	permErr := types.ErrPermanent{Err: errors.New("nope")}
+
+	// A permanent error is not retried.
+	sendAndFail(func(types.Message) error {
+		return permErr
+	})
+	select {
+	case <-mOut.ts:
+		t.Fatal("Received retry for permanent error")
+	case res := <-resChan:
+		assert.True(t, types.IsPermanent(res.Error()))
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	// A batch where all failed messages are permanent is not retried.
+	sendAndFail(func(msg types.Message) error {
+		return batch.NewError(msg, errors.New("meow")).Failed(0, permErr).Failed(2, permErr)
+	})
+	select {
+	case <-mOut.ts:
+		t.Fatal("Received retry for permanent error")
+	case res := <-resChan:
+		assert.Error(t, res.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	// A batch with a mix of permanent and other errors is retried.
+	sendAndFail(func(msg types.Message) error {
+		return batch.NewError(msg, errors.New("meow")).Failed(0, permErr).Failed(2, errors.New("woof"))
+	})
+	var tran types.Transaction
+	select {
+	case tran = <-mOut.ts:
+	case <-resChan:
+		t.Fatal("Received response not retry")
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	assert.Equal(t, 2, tran.Payload.Len())
+
+	select {
+	case tran.ResponseChan <- response.NewAck():
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	select {
+	case res := <-resChan:
+		assert.NoError(t, res.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	// Permanent errors of a retried subset of the batch refer to the indexes
+	// of the original batch.
+	sendAndFail(func(msg types.Message) error {
+		return batch.NewError(msg, errors.New("meow")).Failed(0, errors.New("woof")).Failed(2, errors.New("woof"))
+	})
+	select {
+	case tran = <-mOut.ts:
+	case <-resChan:
+		t.Fatal("Received response not retry")
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	require.Equal(t, 2, tran.Payload.Len())
+
+	select {
+	case tran.ResponseChan <- response.NewError(batch.NewError(tran.Payload, errors.New("meow")).Failed(1, permErr)):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	select {
+	case <-mOut.ts:
+		t.Fatal("Received retry for permanent error")
+	case res := <-resChan:
+		walkable, ok := res.Error().(batch.WalkableError)
+		require.True(t, ok)
+		assert.Equal(t, 1, walkable.IndexedErrors())
+		walkable.WalkParts(func(i int, p types.Part, err error) bool {
+			if i == 2 {
+				assert.Equal(t, "baz", string(p.Get()))
+				assert.True(t, types.IsPermanent(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			return true
+		})
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	output.CloseAsync()
+	require.NoError(t, output.WaitForClose(time.Second))
+}
@@ -0,0 +1,50 @@
+package output
+
+import (
+	"github.com/Jeffail/benthos/v3/lib/message/batch"
+)
+
+// SnowflakePutConfig contains configuration fields for the Snowflake PUT
+// output type.
+type SnowflakePutConfig struct {
+	Account        string             `json:"account" yaml:"account"`
+	Region         string             `json:"region" yaml:"region"`
+	Cloud          string             `json:"cloud" yaml:"cloud"`
+	User           string             `json:"user" yaml:"user"`
+	PrivateKeyFile string             `json:"private_key_file" yaml:"private_key_file"`
+	PrivateKeyPass string             `json:"private_key_pass" yaml:"private_key_pass"`
+	Role           string             `json:"role" yaml:"role"`
+	Database       string             `json:"database" yaml:"database"`
+	Warehouse      string             `json:"warehouse" yaml:"warehouse"`
+	Schema         string             `json:"schema" yaml:"schema"`
+	Table          string             `json:"table" yaml:"table"`
+	Stage          string             `json:"stage" yaml:"stage"`
+	Path           string             `json:"path" yaml:"path"`
+	FileFormat     string             `json:"file_format" yaml:"file_format"`
+	Compression    string             `json:"compression" yaml:"compression"`
+	MaxInFlight    int                `json:"max_in_flight" yaml:"max_in_flight"`
+	Batching       batch.PolicyConfig `json:"batching" yaml:"batching"`
+}
+
+// NewSnowflakePutConfig creates a new SnowflakePutConfig with default values.
+func NewSnowflakePutConfig() SnowflakePutConfig {
+	return SnowflakePutConfig{
+		Account:        "",
+		Region:         "",
+		Cloud:          "",
+		User:           "",
+		PrivateKeyFile: "",
+		PrivateKeyPass: "",
+		Role:           "",
+		Database:       "",
+		Warehouse:      "",
+		Schema:         "",
+		Table:          "",
+		Stage:          "",
+		Path:           "",
+		FileFormat:     "TYPE = JSON",
+		Compression:    "GZIP",
+		MaxInFlight:    1,
+		Batching:       batch.NewPolicyConfig(),
+	}
+}
@@ -0,0 +1,619 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/component/output"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/cenkalti/backoff/v4"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	Constructors[TypeSpool] = TypeSpec{
+		constructor: fromSimpleConstructor(func(conf Config, mgr types.Manager, log log.Modular, stats metrics.Type) (Type, error) {
+			if conf.Spool.Output == nil {
+				return nil, errors.New("cannot create a spool output without a child")
+			}
+			wrapped, err := New(*conf.Spool.Output, mgr, log, stats)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create output '%v': %v", conf.Spool.Output.Type, err)
+			}
+			return newSpool(conf.Spool, wrapped, log, stats)
+		}),
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Summary: `
+Attempts to write messages to a child output and during an outage of the child
+writes them to a spool on local disk instead, replaying them once the child
+recovers.`,
+		Description: `
+Regular Benthos outputs apply back pressure when downstream services aren't
+accessible, which blocks the entire pipeline until the outage is resolved. This
+output instead acknowledges messages once they are safely written to a spool
+directory on local disk, allowing the pipeline to continue consuming.
+
+A batch is written to the spool when the child output returns an error for it,
+or when it is not delivered by the child within the ` + "`back_pressure`" + `
+duration. From that point all subsequent batches are also written to the spool
+in order to preserve ordering, and batches are replayed from the spool to the
+child in the order that they were written until the spool is empty, at which
+point batches are once again sent directly to the child.
+
+Batches that fail during replay are retried until they succeed. Since a batch
+that times out may still eventually be delivered by the child duplicates are
+possible, and therefore this output provides at-least-once delivery.
+
+### Persistence
+
+Each batch is written to its own file within the directory ` + "`path`" + `,
+including the metadata of its messages. Files are written to a temporary
+location and synced before being moved into place, and are only deleted once
+they have been delivered by the child. Therefore a spool survives restarts of
+Benthos, and any batches remaining within it are replayed once the output
+starts again. Files that cannot be decoded are renamed with the suffix
+` + "`.corrupt`" + ` and skipped.
+
+The total size of spooled batches is limited by ` + "`max_size`" + `. When the
+spool is full and ` + "`on_full`" + ` is ` + "`block`" + ` back pressure is
+applied until the spool drains enough to fit the next batch, when ` + "`on_full`" + `
+is ` + "`drop`" + ` batches that do not fit are dropped instead.`,
+		Categories: []Category{
+			CategoryUtility,
+		},
+		FieldSpecs: docs.FieldSpecs{
+			docs.FieldCommon("path", "The directory to write spooled batches to, which is created if it does not exist. Only one spool output should use a given directory."),
+			docs.FieldCommon("max_size", "The maximum total size in bytes of spooled batches."),
+			docs.FieldCommon("on_full", "What to do with batches that do not fit within the spool.").HasOptions("block", "drop"),
+			docs.FieldCommon("back_pressure", "The maximum period to wait for a batch to be delivered by the child output before it is considered to be experiencing an outage and the batch is written to the spool instead.", "5s", "1m"),
+			docs.FieldCommon("output", "A child output.").HasType(docs.FieldTypeOutput),
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Surviving HTTP Outages",
+				Summary: "Messages are sent to an HTTP endpoint, and during outages of the endpoint up to 10GB of messages are spooled to disk rather than blocking the Kafka consumer.",
+				Config: `
+input:
+  kafka:
+    addresses: [ TODO ]
+    topics: [ foo ]
+    consumer_group: benthos_group
+
+output:
+  spool:
+    path: /var/lib/benthos/spool
+    max_size: 10737418240
+    on_full: block
+    back_pressure: 10s
+    output:
+      http_client:
+        url: http://example.com/foo/messages
+        verb: POST
+`,
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// SpoolConfig contains configuration values for the Spool output type.
+type SpoolConfig struct {
+	Path         string  `json:"path" yaml:"path"`
+	MaxSize      int64   `json:"max_size" yaml:"max_size"`
+	OnFull       string  `json:"on_full" yaml:"on_full"`
+	BackPressure string  `json:"back_pressure" yaml:"back_pressure"`
+	Output       *Config `json:"output" yaml:"output"`
+}
+
+// NewSpoolConfig creates a new SpoolConfig with default values.
+func NewSpoolConfig() SpoolConfig {
+	return SpoolConfig{
+		Path:         "",
+		MaxSize:      1073741824,
+		OnFull:       "block",
+		BackPressure: "5s",
+		Output:       nil,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type dummySpoolConfig struct {
+	Path         string      `json:"path" yaml:"path"`
+	MaxSize      int64       `json:"max_size" yaml:"max_size"`
+	OnFull       string      `json:"on_full" yaml:"on_full"`
+	BackPressure string      `json:"back_pressure" yaml:"back_pressure"`
+	Output       interface{} `json:"output" yaml:"output"`
+}
+
+func (s SpoolConfig) dummy() dummySpoolConfig {
+	dummy := dummySpoolConfig{
+		Path:         s.Path,
+		MaxSize:      s.MaxSize,
+		OnFull:       s.OnFull,
+		BackPressure: s.BackPressure,
+		Output:       s.Output,
+	}
+	if s.Output == nil {
+		dummy.Output = struct{}{}
+	}
+	return dummy
+}
+
+// MarshalJSON prints an empty object instead of nil.
+func (s SpoolConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.dummy())
+}
+
+// MarshalYAML prints an empty object instead of nil.
+func (s SpoolConfig) MarshalYAML() (interface{}, error) {
+	return s.dummy(), nil
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	spoolFileExt    = ".spool"
+	spoolTmpExt     = ".tmp"
+	spoolCorruptExt = ".corrupt"
+)
+
+type spoolPart struct {
+	Content  []byte            `json:"content"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func encodeSpoolBatch(msg types.Message) ([]byte, error) {
+	parts := make([]spoolPart, msg.Len())
+	_ = msg.Iter(func(i int, p types.Part) error {
+		parts[i].Content = p.Get()
+		_ = p.Metadata().Iter(func(k, v string) error {
+			if parts[i].Metadata == nil {
+				parts[i].Metadata = map[string]string{}
+			}
+			parts[i].Metadata[k] = v
+			return nil
+		})
+		return nil
+	})
+	return json.Marshal(parts)
+}
+
+func decodeSpoolBatch(b []byte) (types.Message, error) {
+	var parts []spoolPart
+	if err := json.Unmarshal(b, &parts); err != nil {
+		return nil, err
+	}
+	msg := message.New(nil)
+	for _, p := range parts {
+		part := message.NewPart(p.Content)
+		for k, v := range p.Metadata {
+			part.Metadata().Set(k, v)
+		}
+		msg.Append(part)
+	}
+	return msg, nil
+}
+
+type spoolFile struct {
+	seq  uint64
+	size int64
+}
+
+// spool attempts to forward messages to a child output, but writes them to
+// disk during outages of the child and replays them once it recovers.
+type spool struct {
+	stats metrics.Type
+	log   log.Modular
+
+	path         string
+	maxSize      int64
+	dropOnFull   bool
+	backPressure time.Duration
+	wrapped      Type
+
+	// Guards the files pending replay, the first of which is held until it has
+	// been delivered so that direct writes are only made when it is empty.
+	filesMut  sync.Mutex
+	filesCond *sync.Cond
+	files     []spoolFile
+	size      int64
+	nextSeq   uint64
+
+	mWritten  metrics.StatCounter
+	mDropped  metrics.StatCounter
+	mReplayed metrics.StatCounter
+	mBytes    metrics.StatGauge
+
+	transactionsIn  <-chan types.Transaction
+	transactionsOut chan types.Transaction
+
+	ctx        context.Context
+	done       func()
+	closedChan chan struct{}
+}
+
+func newSpool(conf SpoolConfig, wrapped Type, log log.Modular, stats metrics.Type) (*spool, error) {
+	if conf.Path == "" {
+		return nil, errors.New("a spool path must be specified")
+	}
+	if conf.MaxSize <= 0 {
+		return nil, errors.New("max_size must be greater than zero")
+	}
+
+	s := &spool{
+		log:             log,
+		stats:           stats,
+		path:            conf.Path,
+		maxSize:         conf.MaxSize,
+		wrapped:         wrapped,
+		transactionsOut: make(chan types.Transaction),
+
+		mWritten:  stats.GetCounter("spool.batch.written"),
+		mDropped:  stats.GetCounter("spool.batch.dropped"),
+		mReplayed: stats.GetCounter("spool.batch.replayed"),
+		mBytes:    stats.GetGauge("spool.bytes"),
+
+		closedChan: make(chan struct{}),
+	}
+	s.filesCond = sync.NewCond(&s.filesMut)
+
+	switch conf.OnFull {
+	case "block":
+	case "drop":
+		s.dropOnFull = true
+	default:
+		return nil, fmt.Errorf("on_full option not recognised: %v", conf.OnFull)
+	}
+
+	var err error
+	if s.backPressure, err = time.ParseDuration(conf.BackPressure); err != nil {
+		return nil, fmt.Errorf("failed to parse back_pressure duration: %w", err)
+	}
+	if err = s.loadFiles(); err != nil {
+		return nil, err
+	}
+
+	s.ctx, s.done = context.WithCancel(context.Background())
+	go func() {
+		// Wake up anything waiting on the spool once we're closing.
+		<-s.ctx.Done()
+		s.filesMut.Lock()
+		s.filesCond.Broadcast()
+		s.filesMut.Unlock()
+	}()
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s *spool) filePath(seq uint64) string {
+	return filepath.Join(s.path, fmt.Sprintf("%020d%v", seq, spoolFileExt))
+}
+
+// loadFiles scans the spool directory for batches left over from a previous
+// run, and removes temporary files from writes that never completed.
+func (s *spool) loadFiles() error {
+	if err := os.MkdirAll(s.path, 0755); err != nil {
+		return fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	infos, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory: %w", err)
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if strings.HasSuffix(name, spoolTmpExt) {
+			_ = os.Remove(filepath.Join(s.path, name))
+			continue
+		}
+		if !strings.HasSuffix(name, spoolFileExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		s.files = append(s.files, spoolFile{seq: seq, size: info.Size()})
+		s.size += info.Size()
+	}
+	sort.Slice(s.files, func(i, j int) bool {
+		return s.files[i].seq < s.files[j].seq
+	})
+	if len(s.files) > 0 {
+		s.nextSeq = s.files[len(s.files)-1].seq + 1
+		s.log.Infof("Replaying %v spooled batches from previous run\n", len(s.files))
+	}
+	s.mBytes.Set(s.size)
+	return nil
+}
+
+// write adds a batch to the end of the spool, blocking while the spool is full
+// unless batches should be dropped.
+func (s *spool) write(msg types.Message) error {
+	b, err := encodeSpoolBatch(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode batch: %w", err)
+	}
+	size := int64(len(b))
+	if size > s.maxSize {
+		return fmt.Errorf("batch of size %v exceeds the maximum spool size of %v", size, s.maxSize)
+	}
+
+	s.filesMut.Lock()
+	defer s.filesMut.Unlock()
+
+	for s.size+size > s.maxSize {
+		if s.dropOnFull {
+			s.mDropped.Incr(1)
+			s.log.Warnln("Batch dropped due to the spool being full.")
+			return nil
+		}
+		if s.ctx.Err() != nil {
+			return types.ErrTypeClosed
+		}
+		s.filesCond.Wait()
+	}
+
+	seq := s.nextSeq
+	if err = writeSpoolFile(s.filePath(seq), b); err != nil {
+		return fmt.Errorf("failed to write spool file: %w", err)
+	}
+	s.nextSeq++
+	s.files = append(s.files, spoolFile{seq: seq, size: size})
+	s.size += size
+	s.mBytes.Set(s.size)
+	s.mWritten.Incr(1)
+	s.filesCond.Broadcast()
+	return nil
+}
+
+// writeSpoolFile writes a file to a temporary path and syncs it before moving
+// it into place, so that a crash mid-write never results in a partial batch.
+func writeSpoolFile(path string, b []byte) error {
+	tmpPath := path + spoolTmpExt
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(b); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// isEmpty returns whether there are no batches pending replay.
+func (s *spool) isEmpty() bool {
+	s.filesMut.Lock()
+	defer s.filesMut.Unlock()
+	return len(s.files) == 0
+}
+
+// send writes a batch to the child output and returns its response, or false
+// if the child did not respond within the timeout or the output is closing.
+func (s *spool) send(msg types.Message, timeout <-chan time.Time) (types.Response, bool) {
+	resChan := make(chan types.Response, 1)
+	select {
+	case s.transactionsOut <- types.NewTransaction(msg, resChan):
+	case <-timeout:
+		return nil, false
+	case <-s.ctx.Done():
+		return nil, false
+	}
+	select {
+	case res := <-resChan:
+		return res, true
+	case <-timeout:
+	case <-s.ctx.Done():
+	}
+	return nil, false
+}
+
+//------------------------------------------------------------------------------
+
+func (s *spool) inputLoop() {
+	for {
+		var ts types.Transaction
+		var open bool
+		select {
+		case ts, open = <-s.transactionsIn:
+			if !open {
+				return
+			}
+		case <-s.ctx.Done():
+			return
+		}
+
+		if s.isEmpty() {
+			timer := time.NewTimer(s.backPressure)
+			res, ok := s.send(ts.Payload, timer.C)
+			timer.Stop()
+			if ok && res.Error() == nil {
+				select {
+				case ts.ResponseChan <- res:
+				case <-s.ctx.Done():
+					return
+				}
+				continue
+			}
+			if s.ctx.Err() != nil {
+				return
+			}
+			if ok {
+				s.log.Warnf("Spooling batches due to child output error: %v\n", res.Error())
+			} else {
+				s.log.Warnf("Spooling batches due to child output back pressure beyond: %v\n", s.backPressure)
+			}
+		}
+
+		err := s.write(ts.Payload)
+		if err == types.ErrTypeClosed {
+			return
+		}
+		if err != nil {
+			s.log.Errorf("Failed to spool batch: %v\n", err)
+		}
+		select {
+		case ts.ResponseChan <- response.NewError(err):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *spool) replayLoop() {
+	boff := backoff.NewExponentialBackOff()
+	boff.InitialInterval = time.Millisecond * 100
+	boff.MaxInterval = time.Second * 5
+	boff.MaxElapsedTime = 0
+
+	for {
+		s.filesMut.Lock()
+		for len(s.files) == 0 && s.ctx.Err() == nil {
+			s.filesCond.Wait()
+		}
+		if s.ctx.Err() != nil {
+			s.filesMut.Unlock()
+			return
+		}
+		file := s.files[0]
+		s.filesMut.Unlock()
+
+		path := s.filePath(file.seq)
+		msg, err := s.readFile(path)
+		if err == nil {
+			for {
+				res, ok := s.send(msg, nil)
+				if !ok {
+					return
+				}
+				if err = res.Error(); err == nil {
+					break
+				}
+				s.log.Errorf("Failed to replay spooled batch: %v\n", err)
+				select {
+				case <-time.After(boff.NextBackOff()):
+				case <-s.ctx.Done():
+					return
+				}
+			}
+			boff.Reset()
+			s.mReplayed.Incr(1)
+			if err = os.Remove(path); err != nil {
+				s.log.Errorf("Failed to remove replayed spool file: %v\n", err)
+			}
+		} else {
+			s.log.Errorf("Skipping spool file '%v' that could not be read: %v\n", path, err)
+			_ = os.Rename(path, path+spoolCorruptExt)
+		}
+
+		s.filesMut.Lock()
+		s.files = s.files[1:]
+		s.size -= file.size
+		s.mBytes.Set(s.size)
+		if len(s.files) == 0 {
+			s.log.Infoln("Spool drained, resuming direct writes to child output.")
+		}
+		s.filesCond.Broadcast()
+		s.filesMut.Unlock()
+	}
+}
+
+func (s *spool) readFile(path string) (types.Message, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSpoolBatch(b)
+}
+
+func (s *spool) loop() {
+	defer func() {
+		close(s.transactionsOut)
+		s.wrapped.CloseAsync()
+		err := s.wrapped.WaitForClose(time.Second)
+		for ; err != nil; err = s.wrapped.WaitForClose(time.Second) {
+		}
+		close(s.closedChan)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.inputLoop()
+		// Once the input closes there's nothing left to write and remaining
+		// spooled batches are replayed on the next run.
+		s.done()
+	}()
+	go func() {
+		defer wg.Done()
+		s.replayLoop()
+	}()
+	wg.Wait()
+}
+
+// Consume assigns a messages channel for the output to read.
+func (s *spool) Consume(ts <-chan types.Transaction) error {
+	if s.transactionsIn != nil {
+		return types.ErrAlreadyStarted
+	}
+	if err := s.wrapped.Consume(s.transactionsOut); err != nil {
+		return err
+	}
+	s.transactionsIn = ts
+	go s.loop()
+	return nil
+}
+
+// Connected returns a boolean indicating whether this output is currently
+// connected to its target.
+func (s *spool) Connected() bool {
+	return s.wrapped.Connected()
+}
+
+func (s *spool) MaxInFlight() (int, bool) {
+	return output.GetMaxInFlight(s.wrapped)
+}
+
+// CloseAsync shuts down the Spool output and stops processing requests.
+func (s *spool) CloseAsync() {
+	s.done()
+}
+
+// WaitForClose blocks until the Spool output has closed down.
+func (s *spool) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-s.closedChan:
+	case <-time.After(timeout):
+		return types.ErrTimeout
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,192 @@
+package output
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spoolTestConf(t *testing.T) SpoolConfig {
+	t.Helper()
+	conf := NewSpoolConfig()
+	conf.Path = t.TempDir()
+	conf.BackPressure = "50ms"
+	return conf
+}
+
+func spoolTestSend(t *testing.T, tChan chan<- types.Transaction, msg types.Message) error {
+	t.Helper()
+	rChan := make(chan types.Response)
+	select {
+	case tChan <- types.NewTransaction(msg, rChan):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	select {
+	case res := <-rChan:
+		return res.Error()
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	return nil
+}
+
+func spoolTestReceive(t *testing.T, child *mockOutput) types.Transaction {
+	t.Helper()
+	select {
+	case ts, open := <-child.ts:
+		require.True(t, open)
+		return ts
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	return types.Transaction{}
+}
+
+func spoolTestRespond(t *testing.T, ts types.Transaction, err error) {
+	t.Helper()
+	select {
+	case ts.ResponseChan <- response.NewError(err):
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+func TestSpoolOutage(t *testing.T) {
+	child := &mockOutput{}
+	s, err := newSpool(spoolTestConf(t), child, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, s.Consume(tChan))
+
+	msgA := message.New([][]byte{[]byte("a")})
+	msgA.Get(0).Metadata().Set("foo", "bar")
+
+	done := make(chan error)
+	go func() {
+		done <- spoolTestSend(t, tChan, msgA)
+	}()
+
+	ts := spoolTestReceive(t, child)
+	assert.Equal(t, "a", string(ts.Payload.Get(0).Get()))
+	spoolTestRespond(t, ts, errors.New("nope"))
+	require.NoError(t, <-done)
+
+	// The failed batch is now replayed from the spool, and new batches are
+	// spooled behind it.
+	ts = spoolTestReceive(t, child)
+	assert.Equal(t, "a", string(ts.Payload.Get(0).Get()))
+	assert.Equal(t, "bar", ts.Payload.Get(0).Metadata().Get("foo"))
+
+	require.NoError(t, spoolTestSend(t, tChan, message.New([][]byte{[]byte("b")})))
+
+	spoolTestRespond(t, ts, errors.New("still nope"))
+	ts = spoolTestReceive(t, child)
+	assert.Equal(t, "a", string(ts.Payload.Get(0).Get()))
+	spoolTestRespond(t, ts, nil)
+
+	ts = spoolTestReceive(t, child)
+	assert.Equal(t, "b", string(ts.Payload.Get(0).Get()))
+	spoolTestRespond(t, ts, nil)
+
+	assert.Eventually(t, s.isEmpty, time.Second, time.Millisecond*10)
+
+	// Once drained batches are written directly again.
+	go func() {
+		done <- spoolTestSend(t, tChan, message.New([][]byte{[]byte("c")}))
+	}()
+	ts = spoolTestReceive(t, child)
+	assert.Equal(t, "c", string(ts.Payload.Get(0).Get()))
+	spoolTestRespond(t, ts, errors.New("direct error"))
+	require.NoError(t, <-done)
+
+	s.CloseAsync()
+	require.NoError(t, s.WaitForClose(time.Second))
+
+	// The last batch was spooled and remains on disk for the next run.
+	files, err := filepath.Glob(filepath.Join(s.path, "*"+spoolFileExt))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestSpoolBackPressure(t *testing.T) {
+	child := &mockOutput{}
+	s, err := newSpool(spoolTestConf(t), child, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, s.Consume(tChan))
+
+	// Nothing reads from the child, so the batch is spooled after the back
+	// pressure period.
+	require.NoError(t, spoolTestSend(t, tChan, message.New([][]byte{[]byte("a")})))
+	assert.False(t, s.isEmpty())
+
+	s.CloseAsync()
+	require.NoError(t, s.WaitForClose(time.Second))
+}
+
+func TestSpoolRestart(t *testing.T) {
+	conf := spoolTestConf(t)
+
+	first, err := newSpool(conf, &mockOutput{}, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, first.write(message.New([][]byte{[]byte("a")})))
+	require.NoError(t, first.write(message.New([][]byte{[]byte("b")})))
+	first.done()
+
+	require.NoError(t, ioutil.WriteFile(first.filePath(5), []byte("not a batch"), 0644))
+	require.NoError(t, ioutil.WriteFile(first.filePath(6)+spoolTmpExt, []byte("partial"), 0644))
+
+	child := &mockOutput{}
+	s, err := newSpool(conf, child, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), s.nextSeq)
+
+	_, err = os.Stat(first.filePath(6) + spoolTmpExt)
+	assert.True(t, os.IsNotExist(err))
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, s.Consume(tChan))
+
+	for _, exp := range []string{"a", "b"} {
+		ts := spoolTestReceive(t, child)
+		assert.Equal(t, exp, string(ts.Payload.Get(0).Get()))
+		spoolTestRespond(t, ts, nil)
+	}
+
+	assert.Eventually(t, s.isEmpty, time.Second, time.Millisecond*10)
+	_, err = os.Stat(first.filePath(5) + spoolCorruptExt)
+	assert.NoError(t, err)
+
+	s.CloseAsync()
+	require.NoError(t, s.WaitForClose(time.Second))
+}
+
+func TestSpoolFull(t *testing.T) {
+	conf := spoolTestConf(t)
+	conf.MaxSize = 30
+	conf.OnFull = "drop"
+
+	s, err := newSpool(conf, &mockOutput{}, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	defer s.done()
+
+	require.NoError(t, s.write(message.New([][]byte{[]byte("a")})))
+	require.NoError(t, s.write(message.New([][]byte{[]byte("b")})))
+	assert.Len(t, s.files, 1)
+
+	require.EqualError(t, s.write(message.New([][]byte{make([]byte, 100)})), "batch of size 152 exceeds the maximum spool size of 30")
+}
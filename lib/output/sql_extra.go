@@ -4,6 +4,23 @@ package output
 
 // Import extra drivers that aren't supported by WASM builds.
 import (
+	"errors"
+
 	// SQL Drivers
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
+
+func init() {
+	sqlPermanentErrFns = append(sqlPermanentErrFns, func(err error) bool {
+		var pErr *pq.Error
+		if !errors.As(err, &pErr) {
+			return false
+		}
+		// Data exceptions and integrity constraint violations.
+		switch pErr.Code.Class() {
+		case "22", "23":
+			return true
+		}
+		return false
+	})
+}
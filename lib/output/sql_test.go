@@ -0,0 +1,260 @@
+package output
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/batch"
+	"github.com/Jeffail/benthos/v3/lib/credentials"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSQLDriver is a database/sql driver that records the first argument of
+// each committed query, and fails any query where the first argument is
+// "fail".
+type fakeSQLDriver struct {
+	mut       sync.Mutex
+	committed []string
+}
+
+func (f *fakeSQLDriver) Open(string) (driver.Conn, error) {
+	return &fakeSQLConn{d: f}, nil
+}
+
+type fakeSQLConn struct {
+	d       *fakeSQLDriver
+	pending []string
+}
+
+func (c *fakeSQLConn) Prepare(string) (driver.Stmt, error) {
+	return &fakeSQLStmt{c: c}, nil
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	c.pending = nil
+	return c, nil
+}
+
+func (c *fakeSQLConn) Commit() error {
+	c.d.mut.Lock()
+	c.d.committed = append(c.d.committed, c.pending...)
+	c.d.mut.Unlock()
+	c.pending = nil
+	return nil
+}
+
+func (c *fakeSQLConn) Rollback() error {
+	c.pending = nil
+	return nil
+}
+
+type fakeSQLStmt struct {
+	c *fakeSQLConn
+}
+
+func (s *fakeSQLStmt) Close() error {
+	return nil
+}
+
+func (s *fakeSQLStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	v, _ := args[0].(string)
+	if v == "fail" {
+		return nil, errors.New("query failed")
+	}
+	s.c.pending = append(s.c.pending, v)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+var fakeSQL = &fakeSQLDriver{}
+
+func init() {
+	sql.Register("benthos_fake_sql", fakeSQL)
+}
+
+func TestSQLTransactionKey(t *testing.T) {
+	conf := NewSQLConfig()
+	conf.Driver = "benthos_fake_sql"
+	conf.Query = "INSERT INTO foo (id) VALUES (?)"
+	conf.ArgsMapping = `[ this.id ]`
+	conf.TransactionKey = `${! json("group") }`
+
+	w, err := newSQLWriter(conf, types.NoopMgr(), log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, w.ConnectWithContext(context.Background()))
+	t.Cleanup(w.CloseAsync)
+
+	fakeSQL.mut.Lock()
+	fakeSQL.committed = nil
+	fakeSQL.mut.Unlock()
+
+	msg := message.New([][]byte{
+		[]byte(`{"group":"a","id":"a1"}`),
+		[]byte(`{"group":"b","id":"b1"}`),
+		[]byte(`{"group":"a","id":"fail"}`),
+		[]byte(`{"group":"b","id":"b2"}`),
+		[]byte(`{"group":"c","id":"c1"}`),
+	})
+
+	err = w.WriteWithContext(context.Background(), msg)
+	require.Error(t, err)
+
+	var bErr *batch.Error
+	require.True(t, errors.As(err, &bErr))
+
+	var failed []int
+	bErr.WalkParts(func(i int, _ types.Part, err error) bool {
+		if err != nil {
+			failed = append(failed, i)
+		}
+		return true
+	})
+	assert.Equal(t, []int{0, 2}, failed)
+
+	fakeSQL.mut.Lock()
+	assert.Equal(t, []string{"b1", "b2", "c1"}, fakeSQL.committed)
+	fakeSQL.mut.Unlock()
+}
+
+func TestSQLTransactionKeyDisabled(t *testing.T) {
+	conf := NewSQLConfig()
+	conf.Driver = "benthos_fake_sql"
+	conf.Query = "INSERT INTO foo (id) VALUES (?)"
+	conf.ArgsMapping = `[ this.id ]`
+
+	w, err := newSQLWriter(conf, types.NoopMgr(), log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, w.ConnectWithContext(context.Background()))
+	t.Cleanup(w.CloseAsync)
+
+	fakeSQL.mut.Lock()
+	fakeSQL.committed = nil
+	fakeSQL.mut.Unlock()
+
+	msg := message.New([][]byte{
+		[]byte(`{"id":"a1"}`),
+		[]byte(`{"id":"fail"}`),
+		[]byte(`{"id":"a2"}`),
+	})
+	require.Error(t, w.WriteWithContext(context.Background(), msg))
+
+	// Without a transaction key the successful queries of the batch are still
+	// committed.
+	fakeSQL.mut.Lock()
+	assert.Equal(t, []string{"a1", "a2"}, fakeSQL.committed)
+	fakeSQL.mut.Unlock()
+}
+
+type fakeCredentialsResolver struct {
+	secret *credentials.Secret
+}
+
+func (f *fakeCredentialsResolver) Resolve(ctx context.Context, path string) (*credentials.Secret, error) {
+	if path != "database/creds/foo" {
+		return nil, errors.New("secret not found")
+	}
+	return f.secret, nil
+}
+
+func (f *fakeCredentialsResolver) CloseAsync() {
+}
+
+func (f *fakeCredentialsResolver) WaitForClose(time.Duration) error {
+	return nil
+}
+
+type fakeCredentialsMgr struct {
+	types.Manager
+	resolvers map[string]credentials.Resolver
+}
+
+func (f *fakeCredentialsMgr) GetCredentials(name string) (credentials.Resolver, error) {
+	if r, exists := f.resolvers[name]; exists {
+		return r, nil
+	}
+	return nil, types.ErrCredentialsNotFound
+}
+
+func TestSQLCredentials(t *testing.T) {
+	secret := credentials.NewSecret(map[string]interface{}{
+		"username": "foo",
+		"password": "bar",
+	})
+	mgr := &fakeCredentialsMgr{
+		Manager: types.NoopMgr(),
+		resolvers: map[string]credentials.Resolver{
+			"vault": &fakeCredentialsResolver{secret: secret},
+		},
+	}
+
+	conf := NewSQLConfig()
+	conf.Driver = "postgres"
+	conf.Query = "INSERT INTO foo (id) VALUES ($1)"
+	conf.Credentials.Resource = "nope"
+	conf.Credentials.Path = "database/creds/foo"
+
+	_, err := newSQLWriter(conf, mgr, log.Noop())
+	require.EqualError(t, err, "credentials resource 'nope' was not found")
+
+	conf.Credentials.Resource = "vault"
+	conf.Credentials.Path = ""
+	_, err = newSQLWriter(conf, mgr, log.Noop())
+	require.Error(t, err)
+
+	conf.Credentials.Path = "database/creds/nope"
+	w, err := newSQLWriter(conf, mgr, log.Noop())
+	require.NoError(t, err)
+	require.EqualError(t, w.ConnectWithContext(context.Background()), "failed to resolve credentials: secret not found")
+
+	conf.Driver = "benthos_fake_sql"
+	conf.Credentials.Path = "database/creds/foo"
+	w, err = newSQLWriter(conf, mgr, log.Noop())
+	require.NoError(t, err)
+	require.EqualError(t, w.ConnectWithContext(context.Background()), "driver benthos_fake_sql does not support credentials")
+}
+
+func TestSQLReconnectOnChangedCredentials(t *testing.T) {
+	conf := NewSQLConfig()
+	conf.Driver = "benthos_fake_sql"
+	conf.Query = "INSERT INTO foo (id) VALUES (?)"
+	conf.ArgsMapping = `[ this.id ]`
+
+	w, err := newSQLWriter(conf, types.NoopMgr(), log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, w.ConnectWithContext(context.Background()))
+	t.Cleanup(w.CloseAsync)
+
+	changed := make(chan struct{})
+	w.dbMut.Lock()
+	w.credsChanged = changed
+	w.dbMut.Unlock()
+
+	msg := message.New([][]byte{[]byte(`{"id":"a1"}`)})
+	require.NoError(t, w.WriteWithContext(context.Background(), msg))
+
+	close(changed)
+	assert.Equal(t, types.ErrNotConnected, w.WriteWithContext(context.Background(), msg))
+
+	require.NoError(t, w.ConnectWithContext(context.Background()))
+	require.NoError(t, w.WriteWithContext(context.Background(), msg))
+}
@@ -0,0 +1,249 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+// SQLUpsertConfig contains configuration fields for generating batched upsert
+// queries with the SQL output.
+type SQLUpsertConfig struct {
+	Table           string   `json:"table" yaml:"table"`
+	Columns         []string `json:"columns" yaml:"columns"`
+	ConflictColumns []string `json:"conflict_columns" yaml:"conflict_columns"`
+	UpdateColumns   []string `json:"update_columns" yaml:"update_columns"`
+}
+
+// NewSQLUpsertConfig returns a SQLUpsertConfig with default values.
+func NewSQLUpsertConfig() SQLUpsertConfig {
+	return SQLUpsertConfig{
+		Table:           "",
+		Columns:         []string{},
+		ConflictColumns: []string{},
+		UpdateColumns:   []string{},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// sqlMaxPlaceholders is the maximum number of placeholders supported within a
+// single statement by both postgres and mysql.
+const sqlMaxPlaceholders = 65535
+
+var sqlIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_$]*(\.[a-zA-Z_][a-zA-Z0-9_$]*)?$`)
+
+type sqlUpsert struct {
+	driver          string
+	conf            SQLUpsertConfig
+	conflictIndexes []int
+	updateIndexes   []int
+}
+
+func newSQLUpsert(driver string, conf SQLUpsertConfig) (*sqlUpsert, error) {
+	if driver != "postgres" && driver != "mysql" {
+		return nil, fmt.Errorf("upserts are not supported by the driver %v", driver)
+	}
+	if len(conf.Columns) == 0 {
+		return nil, errors.New("upserts require at least one column")
+	}
+	if len(conf.ConflictColumns) == 0 {
+		return nil, errors.New("upserts require at least one conflict column")
+	}
+
+	for _, ident := range append([]string{conf.Table}, conf.Columns...) {
+		if !sqlIdentifierRegexp.MatchString(ident) {
+			return nil, fmt.Errorf("invalid identifier: %q", ident)
+		}
+	}
+
+	columnIndexes := make(map[string]int, len(conf.Columns))
+	for i, c := range conf.Columns {
+		columnIndexes[c] = i
+	}
+
+	u := &sqlUpsert{driver: driver, conf: conf}
+	for _, c := range conf.ConflictColumns {
+		i, exists := columnIndexes[c]
+		if !exists {
+			return nil, fmt.Errorf("conflict column %v is not within the list of columns", c)
+		}
+		u.conflictIndexes = append(u.conflictIndexes, i)
+	}
+	for _, c := range conf.UpdateColumns {
+		i, exists := columnIndexes[c]
+		if !exists {
+			return nil, fmt.Errorf("update column %v is not within the list of columns", c)
+		}
+		u.updateIndexes = append(u.updateIndexes, i)
+	}
+	return u, nil
+}
+
+// maxRows returns the maximum number of rows that can be written with a single
+// statement.
+func (u *sqlUpsert) maxRows() int {
+	return sqlMaxPlaceholders / len(u.conf.Columns)
+}
+
+// query returns an upsert statement for a number of rows.
+func (u *sqlUpsert) query(rows int) string {
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	b.WriteString(u.conf.Table)
+	b.WriteString(" (")
+	b.WriteString(strings.Join(u.conf.Columns, ", "))
+	b.WriteString(") VALUES ")
+
+	n := 0
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := range u.conf.Columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			n++
+			if u.driver == "postgres" {
+				b.WriteString("$" + strconv.Itoa(n))
+			} else {
+				b.WriteByte('?')
+			}
+		}
+		b.WriteByte(')')
+	}
+
+	if u.driver == "postgres" {
+		b.WriteString(" ON CONFLICT (")
+		b.WriteString(strings.Join(u.conf.ConflictColumns, ", "))
+		b.WriteString(")")
+		if len(u.conf.UpdateColumns) == 0 {
+			b.WriteString(" DO NOTHING")
+			return b.String()
+		}
+		b.WriteString(" DO UPDATE SET ")
+		for i, c := range u.conf.UpdateColumns {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c + " = EXCLUDED." + c)
+		}
+		return b.String()
+	}
+
+	b.WriteString(" ON DUPLICATE KEY UPDATE ")
+	if len(u.conf.UpdateColumns) == 0 {
+		// MySQL has no equivalent of DO NOTHING that ignores only duplicate
+		// key errors, and therefore we assign a conflict column to itself.
+		c := u.conf.ConflictColumns[0]
+		b.WriteString(c + " = " + c)
+		return b.String()
+	}
+	for i, c := range u.conf.UpdateColumns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(c + " = VALUES(" + c + ")")
+	}
+	return b.String()
+}
+
+// dedupe merges rows that share conflict column values within a batch, as
+// postgres rejects statements that affect the same row more than once. The
+// result is equivalent to executing each row in order: the first row of each
+// conflict key provides the inserted values, and the last row provides the
+// values of the update columns, which are applied when the key already exists
+// or is written again within the batch.
+func (u *sqlUpsert) dedupe(argSets [][]interface{}) ([][]interface{}, error) {
+	rowIndexes := make(map[string]int, len(argSets))
+	rows := make([][]interface{}, 0, len(argSets))
+	for _, args := range argSets {
+		if len(args) != len(u.conf.Columns) {
+			return nil, fmt.Errorf("expected %v arguments for upsert, received %v", len(u.conf.Columns), len(args))
+		}
+		var key strings.Builder
+		for _, ci := range u.conflictIndexes {
+			key.WriteString(sqlKeyValue(args[ci]))
+			key.WriteByte(0)
+		}
+		i, exists := rowIndexes[key.String()]
+		if !exists {
+			rowIndexes[key.String()] = len(rows)
+			rows = append(rows, append([]interface{}(nil), args...))
+			continue
+		}
+		for _, ui := range u.updateIndexes {
+			rows[i][ui] = args[ui]
+		}
+	}
+	return rows, nil
+}
+
+// sqlKeyValue returns a string representation of a column value for comparing
+// conflict keys, where numeric values are normalised so that, for example,
+// int64(1) and float64(1) are considered equal.
+func sqlKeyValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "s" + t
+	case []byte:
+		return "s" + string(t)
+	case bool:
+		return "b" + strconv.FormatBool(t)
+	case int:
+		return "n" + strconv.FormatInt(int64(t), 10)
+	case int8:
+		return "n" + strconv.FormatInt(int64(t), 10)
+	case int16:
+		return "n" + strconv.FormatInt(int64(t), 10)
+	case int32:
+		return "n" + strconv.FormatInt(int64(t), 10)
+	case int64:
+		return "n" + strconv.FormatInt(t, 10)
+	case uint:
+		return "n" + strconv.FormatUint(uint64(t), 10)
+	case uint8:
+		return "n" + strconv.FormatUint(uint64(t), 10)
+	case uint16:
+		return "n" + strconv.FormatUint(uint64(t), 10)
+	case uint32:
+		return "n" + strconv.FormatUint(uint64(t), 10)
+	case uint64:
+		return "n" + strconv.FormatUint(t, 10)
+	case float32:
+		return sqlKeyFloat(float64(t))
+	case float64:
+		return sqlKeyFloat(t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return "n" + strconv.FormatInt(i, 10)
+		}
+		if f, err := t.Float64(); err == nil {
+			return sqlKeyFloat(f)
+		}
+		return "s" + t.String()
+	case time.Time:
+		return "t" + t.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("v%#v", v)
+}
+
+func sqlKeyFloat(f float64) string {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return "n" + strconv.FormatInt(int64(f), 10)
+	}
+	return "n" + strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+//------------------------------------------------------------------------------
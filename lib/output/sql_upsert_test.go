@@ -0,0 +1,186 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLUpsertQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		conf     SQLUpsertConfig
+		rows     int
+		expected string
+	}{
+		{
+			name:   "postgres update",
+			driver: "postgres",
+			conf: SQLUpsertConfig{
+				Table:           "foo",
+				Columns:         []string{"id", "bar", "baz"},
+				ConflictColumns: []string{"id"},
+				UpdateColumns:   []string{"bar", "baz"},
+			},
+			rows:     2,
+			expected: "INSERT INTO foo (id, bar, baz) VALUES ($1, $2, $3), ($4, $5, $6) ON CONFLICT (id) DO UPDATE SET bar = EXCLUDED.bar, baz = EXCLUDED.baz",
+		},
+		{
+			name:   "postgres do nothing",
+			driver: "postgres",
+			conf: SQLUpsertConfig{
+				Table:           "public.foo",
+				Columns:         []string{"id", "bar"},
+				ConflictColumns: []string{"id", "bar"},
+			},
+			rows:     1,
+			expected: "INSERT INTO public.foo (id, bar) VALUES ($1, $2) ON CONFLICT (id, bar) DO NOTHING",
+		},
+		{
+			name:   "mysql update",
+			driver: "mysql",
+			conf: SQLUpsertConfig{
+				Table:           "foo",
+				Columns:         []string{"id", "bar"},
+				ConflictColumns: []string{"id"},
+				UpdateColumns:   []string{"bar"},
+			},
+			rows:     2,
+			expected: "INSERT INTO foo (id, bar) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE bar = VALUES(bar)",
+		},
+		{
+			name:   "mysql ignore",
+			driver: "mysql",
+			conf: SQLUpsertConfig{
+				Table:           "foo",
+				Columns:         []string{"id", "bar"},
+				ConflictColumns: []string{"id"},
+			},
+			rows:     1,
+			expected: "INSERT INTO foo (id, bar) VALUES (?, ?) ON DUPLICATE KEY UPDATE id = id",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			u, err := newSQLUpsert(test.driver, test.conf)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, u.query(test.rows))
+		})
+	}
+}
+
+func TestSQLUpsertErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		conf   SQLUpsertConfig
+		err    string
+	}{
+		{
+			name:   "unsupported driver",
+			driver: "clickhouse",
+			conf: SQLUpsertConfig{
+				Table: "foo", Columns: []string{"id"}, ConflictColumns: []string{"id"},
+			},
+			err: "upserts are not supported by the driver clickhouse",
+		},
+		{
+			name:   "no conflict columns",
+			driver: "postgres",
+			conf: SQLUpsertConfig{
+				Table: "foo", Columns: []string{"id"},
+			},
+			err: "upserts require at least one conflict column",
+		},
+		{
+			name:   "bad identifier",
+			driver: "postgres",
+			conf: SQLUpsertConfig{
+				Table: "foo; DROP TABLE foo", Columns: []string{"id"}, ConflictColumns: []string{"id"},
+			},
+			err: `invalid identifier: "foo; DROP TABLE foo"`,
+		},
+		{
+			name:   "unknown conflict column",
+			driver: "postgres",
+			conf: SQLUpsertConfig{
+				Table: "foo", Columns: []string{"id"}, ConflictColumns: []string{"nope"},
+			},
+			err: "conflict column nope is not within the list of columns",
+		},
+		{
+			name:   "unknown update column",
+			driver: "mysql",
+			conf: SQLUpsertConfig{
+				Table: "foo", Columns: []string{"id"}, ConflictColumns: []string{"id"}, UpdateColumns: []string{"nope"},
+			},
+			err: "update column nope is not within the list of columns",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newSQLUpsert(test.driver, test.conf)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestSQLUpsertDedupe(t *testing.T) {
+	u, err := newSQLUpsert("postgres", SQLUpsertConfig{
+		Table:           "foo",
+		Columns:         []string{"id", "region", "value", "created"},
+		ConflictColumns: []string{"id", "region"},
+		UpdateColumns:   []string{"value"},
+	})
+	require.NoError(t, err)
+
+	rows, err := u.dedupe([][]interface{}{
+		{"a", "eu", 1.0, "first"},
+		{"b", "eu", 2.0, "first"},
+		{"a", "us", 3.0, "first"},
+		{"a", "eu", 4.0, "second"},
+		{"c", "eu", 5.0, "first"},
+		{"b", "eu", 6.0, "second"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{
+		{"a", "eu", 4.0, "first"},
+		{"b", "eu", 6.0, "first"},
+		{"a", "us", 3.0, "first"},
+		{"c", "eu", 5.0, "first"},
+	}, rows)
+
+	rows, err = u.dedupe([][]interface{}{
+		{int64(1), "eu", 1.0, "first"},
+		{float64(1), "eu", 2.0, "second"},
+		{json.Number("1"), []byte("eu"), 3.0, "third"},
+		{int64(2), "eu", 4.0, "first"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{
+		{int64(1), "eu", 3.0, "first"},
+		{int64(2), "eu", 4.0, "first"},
+	}, rows)
+
+	_, err = u.dedupe([][]interface{}{{"a", "eu"}})
+	require.EqualError(t, err, "expected 4 arguments for upsert, received 2")
+}
+
+func TestSQLUpsertWithQuery(t *testing.T) {
+	conf := NewSQLConfig()
+	conf.Driver = "postgres"
+	conf.Query = "INSERT INTO foo (id) VALUES ($1)"
+	conf.Upsert.Table = "foo"
+	conf.Upsert.Columns = []string{"id"}
+	conf.Upsert.ConflictColumns = []string{"id"}
+
+	_, err := newSQLWriter(conf, nil, nil)
+	require.Error(t, err)
+}
@@ -30,6 +30,8 @@ type Writer struct {
 	log   log.Modular
 	stats metrics.Type
 
+	mInFlight metrics.StatGauge
+
 	transactions <-chan types.Transaction
 
 	closeChan      chan struct{}
@@ -51,6 +53,7 @@ func NewWriter(
 		writer:         w,
 		log:            log,
 		stats:          stats,
+		mInFlight:      stats.GetGauge("batch.in_flight"),
 		transactions:   nil,
 		closeChan:      make(chan struct{}),
 		fullyCloseChan: make(chan struct{}),
@@ -61,6 +64,9 @@ func NewWriter(
 //------------------------------------------------------------------------------
 
 func (w *Writer) latencyMeasuringWrite(msg types.Message) (latencyNs int64, err error) {
+	w.mInFlight.Incr(1)
+	defer w.mInFlight.Decr(1)
+
 	t0 := time.Now()
 	err = w.writer.Write(msg)
 	latencyNs = time.Since(t0).Nanoseconds()
@@ -74,11 +80,16 @@ func (w *Writer) loop() {
 		mCount      = w.stats.GetCounter("count")
 		mPartsSent  = w.stats.GetCounter("sent")
 		mSent       = w.stats.GetCounter("batch.sent")
+		mPartsAcked = w.stats.GetCounter("acked")
+		mAcked      = w.stats.GetCounter("batch.acked")
 		mBytesSent  = w.stats.GetCounter("batch.bytes")
 		mLatency    = w.stats.GetTimer("batch.latency")
+		mPartsError = w.stats.GetCounter("error")
+		mError      = w.stats.GetCounter("batch.error")
 		mConn       = w.stats.GetCounter("connection.up")
 		mFailedConn = w.stats.GetCounter("connection.failed")
 		mLostConn   = w.stats.GetCounter("connection.lost")
+		mSize       = w.stats.GetTimer("message_size")
 	)
 
 	defer func() {
@@ -120,12 +131,15 @@ func (w *Writer) loop() {
 				return
 			}
 			mCount.Incr(1)
+			recordMessageSizes(mSize, ts.Payload)
 		case <-w.closeChan:
 			return
 		}
 
 		w.log.Tracef("Attempting to write %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
 		spans := tracing.CreateChildSpans("output_"+w.typeStr, ts.Payload)
+		mSent.Incr(1)
+		mPartsSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 		latency, err := w.latencyMeasuringWrite(ts.Payload)
 
 		// If our writer says it is not connected.
@@ -163,14 +177,16 @@ func (w *Writer) loop() {
 
 		if err != nil {
 			w.log.Errorf("Failed to send message to %v: %v\n", w.typeStr, err)
+			mError.Incr(1)
+			mPartsError.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 			if !throt.Retry() {
 				return
 			}
 		} else {
-			mSent.Incr(1)
-			mPartsSent.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
+			mAcked.Incr(1)
+			mPartsAcked.Incr(int64(batch.MessageCollapsedCount(ts.Payload)))
 			mBytesSent.Incr(int64(message.GetAllBytesLen(ts.Payload)))
-			mLatency.Timing(latency)
+			recordLatency(mLatency, latency, spans)
 			w.log.Tracef("Successfully wrote %v messages to '%v'.\n", ts.Payload.Len(), w.typeStr)
 			throt.Reset()
 		}
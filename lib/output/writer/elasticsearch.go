@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	batchInternal "github.com/Jeffail/benthos/v3/internal/batch"
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
 	"github.com/Jeffail/benthos/v3/lib/log"
@@ -49,6 +50,7 @@ type ElasticsearchConfig struct {
 	Auth           auth.BasicAuthConfig `json:"basic_auth" yaml:"basic_auth"`
 	AWS            OptionalAWSConfig    `json:"aws" yaml:"aws"`
 	MaxInFlight    int                  `json:"max_in_flight" yaml:"max_in_flight"`
+	MappingErrors  string               `json:"mapping_errors" yaml:"mapping_errors"`
 	retries.Config `json:",inline" yaml:",inline"`
 	Batching       batch.PolicyConfig `json:"batching" yaml:"batching"`
 }
@@ -75,9 +77,10 @@ func NewElasticsearchConfig() ElasticsearchConfig {
 			Enabled: false,
 			Config:  sess.NewConfig(),
 		},
-		MaxInFlight: 1,
-		Config:      rConf,
-		Batching:    batch.NewPolicyConfig(),
+		MaxInFlight:   1,
+		MappingErrors: "fail",
+		Config:        rConf,
+		Batching:      batch.NewPolicyConfig(),
 	}
 }
 
@@ -101,7 +104,8 @@ type Elasticsearch struct {
 	indexStr    *field.Expression
 	pipelineStr *field.Expression
 
-	eJSONErr metrics.StatCounter
+	eJSONErr    metrics.StatCounter
+	eMappingErr metrics.StatCounter
 
 	client *elastic.Client
 }
@@ -115,6 +119,13 @@ func NewElasticsearch(conf ElasticsearchConfig, log log.Modular, stats metrics.T
 		sniff:       conf.Sniff,
 		healthcheck: conf.Healthcheck,
 		eJSONErr:    stats.GetCounter("error.json"),
+		eMappingErr: stats.GetCounter("error.mapping"),
+	}
+
+	switch conf.MappingErrors {
+	case "fail", "drop":
+	default:
+		return nil, fmt.Errorf("unrecognised mapping_errors option: %v", conf.MappingErrors)
 	}
 
 	var err error
@@ -219,9 +230,37 @@ func shouldRetry(s int) bool {
 	if s >= 500 && s <= 599 {
 		return true
 	}
+	return s == http.StatusTooManyRequests
+}
+
+// isMappingError returns true if an error type returned by Elasticsearch
+// indicates that a document conflicts with the mapping of an index, in which
+// case retrying the document would never succeed.
+func isMappingError(errType string) bool {
+	switch errType {
+	case "mapper_parsing_exception",
+		"strict_dynamic_mapping_exception",
+		"document_parsing_exception",
+		"illegal_argument_exception":
+		return true
+	}
 	return false
 }
 
+// dropMappingError returns true if a mapping error should be dropped rather
+// than failing the message.
+func (e *Elasticsearch) dropMappingError(id, errType, reason string) bool {
+	if !isMappingError(errType) {
+		return false
+	}
+	e.eMappingErr.Incr(1)
+	if e.conf.MappingErrors != "drop" {
+		return false
+	}
+	e.log.Warnf("Dropping Elasticsearch message '%v' due to mapping error [%v]: %v\n", id, errType, reason)
+	return true
+}
+
 type pendingBulkIndex struct {
 	Index    string
 	Pipeline string
@@ -246,22 +285,28 @@ func (e *Elasticsearch) Write(msg types.Message) error {
 
 	if msg.Len() == 1 {
 		index := e.indexStr.String(0, msg)
+		id := e.idStr.String(0, msg)
 		// nolint:staticcheck // Ignore SA1019 Type is deprecated warning for .Index()
 		_, err := e.client.Index().
 			Index(index).
 			Pipeline(e.pipelineStr.String(0, msg)).
 			Type(e.conf.Type).
-			Id(e.idStr.String(0, msg)).
+			Id(id).
 			BodyString(string(msg.Get(0).Get())).
 			Do(context.Background())
 		if err == nil {
 			// Flush to make sure the document got written.
 			_, err = e.client.Flush().Index(index).Do(context.Background())
+		} else if eErr, ok := err.(*elastic.Error); ok && eErr.Details != nil {
+			if e.dropMappingError(id, eErr.Details.Type, eErr.Details.Reason) {
+				return nil
+			}
 		}
 		return err
 	}
 
 	requests := map[string]*pendingBulkIndex{}
+	partIndexes := map[string][]int{}
 	msg.Iter(func(i int, part types.Part) error {
 		jObj, ierr := part.JSON()
 		if ierr != nil {
@@ -269,12 +314,14 @@ func (e *Elasticsearch) Write(msg types.Message) error {
 			e.log.Errorf("Failed to marshal message into JSON document: %v\n", ierr)
 			return nil
 		}
-		requests[e.idStr.String(i, msg)] = &pendingBulkIndex{
+		id := e.idStr.String(i, msg)
+		requests[id] = &pendingBulkIndex{
 			Index:    e.indexStr.String(i, msg),
 			Pipeline: e.pipelineStr.String(i, msg),
 			Type:     e.conf.Type,
 			Doc:      jObj,
 		}
+		partIndexes[id] = append(partIndexes[id], i)
 		return nil
 	})
 
@@ -290,6 +337,10 @@ func (e *Elasticsearch) Write(msg types.Message) error {
 		)
 	}
 
+	// Documents that are rejected for reasons that retrying would not resolve,
+	// such as conflicts with the index mapping, are reported once all other
+	// documents are written.
+	var rejected []*elastic.BulkResponseItem
 	for b.NumberOfActions() != 0 {
 		result, err := b.Do(context.Background())
 		if err != nil {
@@ -301,13 +352,18 @@ func (e *Elasticsearch) Write(msg types.Message) error {
 			continue
 		}
 
-		wait := boff.NextBackOff()
+		var retrying []*elastic.BulkResponseItem
 		for i := 0; i < len(failed); i++ {
+			if failed[i].Error != nil && e.dropMappingError(failed[i].Id, failed[i].Error.Type, failed[i].Error.Reason) {
+				continue
+			}
 			if !shouldRetry(failed[i].Status) {
-				e.log.Errorf("Elasticsearch message '%v' rejected with code [%s]: %v\n", failed[i].Id, failed[i].Status, failed[i].Error.Reason)
-				return fmt.Errorf("failed to send %v parts from message: %v", len(failed), failed[0].Error.Reason)
+				e.log.Errorf("Elasticsearch message '%v' rejected with code [%v]: %v\n", failed[i].Id, failed[i].Status, failed[i].Error.Reason)
+				rejected = append(rejected, failed[i])
+				continue
 			}
-			e.log.Errorf("Elasticsearch message '%v' failed with code [%s]: %v\n", failed[i].Id, failed[i].Status, failed[i].Error.Reason)
+			e.log.Errorf("Elasticsearch message '%v' failed with code [%v]: %v\n", failed[i].Id, failed[i].Status, failed[i].Error.Reason)
+			retrying = append(retrying, failed[i])
 			id := failed[i].Id
 			req := requests[id]
 			b.Add(
@@ -319,15 +375,40 @@ func (e *Elasticsearch) Write(msg types.Message) error {
 					Doc(req.Doc),
 			)
 		}
+		if len(retrying) == 0 {
+			continue
+		}
+
+		wait := boff.NextBackOff()
 		if wait == backoff.Stop {
-			return fmt.Errorf("failed to send %v parts from message: %v", len(failed), failed[0].Error.Reason)
+			return failedBulkItemsError(msg, partIndexes, append(rejected, retrying...))
 		}
 		time.Sleep(wait)
 	}
 
+	if len(rejected) > 0 {
+		return failedBulkItemsError(msg, partIndexes, rejected)
+	}
 	return nil
 }
 
+// failedBulkItemsError returns a batch error where only the messages of failed
+// bulk items are flagged, allowing the successfully written messages of a batch
+// to be acknowledged.
+func failedBulkItemsError(msg types.Message, partIndexes map[string][]int, failed []*elastic.BulkResponseItem) error {
+	batchErr := batchInternal.NewError(msg, fmt.Errorf("failed to send %v parts from message: %v", len(failed), failed[0].Error.Reason))
+	for _, item := range failed {
+		for _, i := range partIndexes[item.Id] {
+			if item.Error != nil && isMappingError(item.Error.Type) {
+				batchErr.Failed(i, fmt.Errorf("rejected due to mapping error [%v]: %v", item.Error.Type, item.Error.Reason))
+			} else {
+				batchErr.Failed(i, fmt.Errorf("rejected with code [%v]: %v", item.Status, item.Error.Reason))
+			}
+		}
+	}
+	return batchErr
+}
+
 // CloseAsync shuts down the Elasticsearch writer and stops processing messages.
 func (e *Elasticsearch) CloseAsync() {
 }
@@ -0,0 +1,160 @@
+package writer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	batchInternal "github.com/Jeffail/benthos/v3/internal/batch"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeElasticBulk runs a server that responds to bulk requests with the status
+// returned by statusFn for each document ID.
+func fakeElasticBulk(t *testing.T, statusFn func(id string, attempt int) (int, string)) (string, func(id string) int) {
+	t.Helper()
+
+	var mut sync.Mutex
+	attempts := map[string]int{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/_bulk"), r.URL.Path)
+
+		mut.Lock()
+		defer mut.Unlock()
+
+		var items []interface{}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var action map[string]struct {
+				ID string `json:"_id"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &action))
+			require.True(t, scanner.Scan())
+
+			id := action["index"].ID
+			attempts[id]++
+			status, errType := statusFn(id, attempts[id])
+			item := map[string]interface{}{
+				"_index": "foo",
+				"_id":    id,
+				"status": status,
+			}
+			if errType != "" {
+				item["error"] = map[string]interface{}{
+					"type":   errType,
+					"reason": fmt.Sprintf("%v failed", id),
+				}
+			}
+			items = append(items, map[string]interface{}{"index": item})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"took":   1,
+			"errors": true,
+			"items":  items,
+		}))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts.URL, func(id string) int {
+		mut.Lock()
+		defer mut.Unlock()
+		return attempts[id]
+	}
+}
+
+func newFakeElasticWriter(t *testing.T, url, mappingErrors string) *Elasticsearch {
+	t.Helper()
+
+	conf := NewElasticsearchConfig()
+	conf.URLs = []string{url}
+	conf.Sniff = false
+	conf.Healthcheck = false
+	conf.ID = `${! json("id") }`
+	conf.Index = "foo"
+	conf.MappingErrors = mappingErrors
+	conf.Backoff.InitialInterval = "1ms"
+	conf.Backoff.MaxInterval = "1ms"
+
+	e, err := NewElasticsearch(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, e.Connect())
+	return e
+}
+
+func TestElasticBulkRejectedDocuments(t *testing.T) {
+	url, attempts := fakeElasticBulk(t, func(id string, attempt int) (int, string) {
+		switch id {
+		case "mapping":
+			return 400, "mapper_parsing_exception"
+		case "busy":
+			if attempt < 3 {
+				return 429, "es_rejected_execution_exception"
+			}
+		}
+		return 201, ""
+	})
+
+	e := newFakeElasticWriter(t, url, "fail")
+
+	msg := message.New([][]byte{
+		[]byte(`{"id":"busy"}`), []byte(`{"id":"mapping"}`), []byte(`{"id":"ok"}`),
+	})
+	err := e.Write(msg)
+	require.Error(t, err)
+
+	bErr, ok := err.(*batchInternal.Error)
+	require.True(t, ok, "%T", err)
+
+	var failed []string
+	bErr.WalkParts(func(i int, p types.Part, err error) bool {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", i, err))
+		}
+		return true
+	})
+	assert.Equal(t, []string{
+		"1: rejected due to mapping error [mapper_parsing_exception]: mapping failed",
+	}, failed)
+
+	assert.Equal(t, 3, attempts("busy"))
+	assert.Equal(t, 1, attempts("mapping"))
+	assert.Equal(t, 1, attempts("ok"))
+}
+
+func TestElasticBulkDropMappingErrors(t *testing.T) {
+	url, attempts := fakeElasticBulk(t, func(id string, attempt int) (int, string) {
+		if id == "mapping" {
+			return 400, "strict_dynamic_mapping_exception"
+		}
+		return 201, ""
+	})
+
+	e := newFakeElasticWriter(t, url, "drop")
+
+	require.NoError(t, e.Write(message.New([][]byte{
+		[]byte(`{"id":"mapping"}`), []byte(`{"id":"ok"}`),
+	})))
+	assert.Equal(t, 1, attempts("mapping"))
+	assert.Equal(t, 1, attempts("ok"))
+}
+
+func TestElasticBadMappingErrors(t *testing.T) {
+	conf := NewElasticsearchConfig()
+	conf.MappingErrors = "nope"
+
+	_, err := NewElasticsearch(conf, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "unrecognised mapping_errors option: nope")
+}
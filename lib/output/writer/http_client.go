@@ -2,39 +2,97 @@ package writer
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message/batch"
 	"github.com/Jeffail/benthos/v3/lib/message/roundtrip"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/Jeffail/benthos/v3/lib/util/http/client"
+	"github.com/gofrs/uuid"
 )
 
 //------------------------------------------------------------------------------
 
+// HTTPIdempotencyConfig contains configuration fields for sending
+// idempotency keys with HTTP requests.
+type HTTPIdempotencyConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Header  string `json:"header" yaml:"header"`
+	Key     string `json:"key" yaml:"key"`
+}
+
+// NewHTTPIdempotencyConfig creates a new HTTPIdempotencyConfig with default
+// values.
+func NewHTTPIdempotencyConfig() HTTPIdempotencyConfig {
+	return HTTPIdempotencyConfig{
+		Enabled: false,
+		Header:  "Idempotency-Key",
+		Key:     "",
+	}
+}
+
+// HTTPMultipartConfig contains configuration fields for a part of a
+// multipart/form-data request body.
+type HTTPMultipartConfig struct {
+	Name        string `json:"name" yaml:"name"`
+	Filename    string `json:"filename" yaml:"filename"`
+	ContentType string `json:"content_type" yaml:"content_type"`
+	Content     string `json:"content" yaml:"content"`
+}
+
+// NewHTTPMultipartConfig creates a new HTTPMultipartConfig with default
+// values.
+func NewHTTPMultipartConfig() HTTPMultipartConfig {
+	return HTTPMultipartConfig{
+		Name:        "",
+		Filename:    "",
+		ContentType: "",
+		Content:     "",
+	}
+}
+
 // HTTPClientConfig contains configuration fields for the HTTPClient output
 // type.
 type HTTPClientConfig struct {
 	client.Config     `json:",inline" yaml:",inline"`
-	BatchAsMultipart  bool               `json:"batch_as_multipart" yaml:"batch_as_multipart"`
-	MaxInFlight       int                `json:"max_in_flight" yaml:"max_in_flight"`
-	PropagateResponse bool               `json:"propagate_response" yaml:"propagate_response"`
-	Batching          batch.PolicyConfig `json:"batching" yaml:"batching"`
+	BatchAsMultipart  bool                  `json:"batch_as_multipart" yaml:"batch_as_multipart"`
+	MaxInFlight       int                   `json:"max_in_flight" yaml:"max_in_flight"`
+	PreserveOrder     bool                  `json:"preserve_order" yaml:"preserve_order"`
+	PropagateResponse bool                  `json:"propagate_response" yaml:"propagate_response"`
+	Idempotency       HTTPIdempotencyConfig `json:"idempotency" yaml:"idempotency"`
+	Multipart         []HTTPMultipartConfig `json:"multipart" yaml:"multipart"`
+	Batching          batch.PolicyConfig    `json:"batching" yaml:"batching"`
 }
 
 // NewHTTPClientConfig creates a new HTTPClientConfig with default values.
 func NewHTTPClientConfig() HTTPClientConfig {
+	cConf := client.NewConfig()
+	cConf.PermanentOn = []int{400, 404, 405, 410, 413, 414, 415, 422}
 	return HTTPClientConfig{
-		Config:            client.NewConfig(),
+		Config:            cConf,
 		BatchAsMultipart:  true, // TODO: V4 Set false by default.
 		MaxInFlight:       1,    // TODO: Increase this default?
+		PreserveOrder:     false,
 		PropagateResponse: false,
+		Idempotency:       NewHTTPIdempotencyConfig(),
+		Multipart:         []HTTPMultipartConfig{},
 		Batching:          batch.NewPolicyConfig(),
 	}
 }
 
+// HTTPIdempotencyKeyMeta is the metadata key used for storing the idempotency
+// key of a message, which allows the same key to be reused when the message is
+// retried.
+const HTTPIdempotencyKeyMeta = "http_idempotency_key"
+
 //------------------------------------------------------------------------------
 
 // HTTPClient is an output type that sends messages as HTTP requests to a target
@@ -45,8 +103,9 @@ type HTTPClient struct {
 	stats metrics.Type
 	log   log.Modular
 
-	conf      HTTPClientConfig
-	closeChan chan struct{}
+	conf           HTTPClientConfig
+	idempotencyKey *field.Expression
+	closeChan      chan struct{}
 }
 
 // NewHTTPClient creates a new HTTPClient writer type.
@@ -63,8 +122,56 @@ func NewHTTPClient(
 		closeChan: make(chan struct{}),
 	}
 	var err error
+	if conf.Idempotency.Enabled {
+		header := http.CanonicalHeaderKey(conf.Idempotency.Header)
+		if header == "" {
+			return nil, fmt.Errorf("an idempotency header must be specified")
+		}
+		headers := make(map[string]string, len(conf.Headers)+1)
+		for k, v := range conf.Headers {
+			if http.CanonicalHeaderKey(k) == header {
+				return nil, fmt.Errorf("idempotency header %v must not also be set in headers", k)
+			}
+			headers[k] = v
+		}
+		headers[header] = fmt.Sprintf(`${! meta("%v") }`, HTTPIdempotencyKeyMeta)
+		conf.Headers = headers
+
+		if conf.Idempotency.Key != "" {
+			if h.idempotencyKey, err = bloblang.NewField(conf.Idempotency.Key); err != nil {
+				return nil, fmt.Errorf("failed to parse idempotency key expression: %v", err)
+			}
+		}
+	}
+	var multipartFields []client.MultipartField
+	for i, p := range conf.Multipart {
+		var f client.MultipartField
+		if p.Name == "" {
+			return nil, fmt.Errorf("multipart field %v requires a name", i)
+		}
+		if f.Name, err = bloblang.NewField(p.Name); err != nil {
+			return nil, fmt.Errorf("failed to parse multipart field %v name expression: %v", i, err)
+		}
+		if p.Filename != "" {
+			if f.Filename, err = bloblang.NewField(p.Filename); err != nil {
+				return nil, fmt.Errorf("failed to parse multipart field %v filename expression: %v", i, err)
+			}
+		}
+		if p.ContentType != "" {
+			if f.ContentType, err = bloblang.NewField(p.ContentType); err != nil {
+				return nil, fmt.Errorf("failed to parse multipart field %v content_type expression: %v", i, err)
+			}
+		}
+		if p.Content != "" {
+			if f.Content, err = bloblang.NewField(p.Content); err != nil {
+				return nil, fmt.Errorf("failed to parse multipart field %v content expression: %v", i, err)
+			}
+		}
+		multipartFields = append(multipartFields, f)
+	}
 	if h.client, err = client.New(
 		conf.Config,
+		client.OptSetMultipartFields(multipartFields),
 		client.OptSetCloseChan(h.closeChan),
 		client.OptSetLogger(h.log),
 		client.OptSetManager(mgr),
@@ -98,6 +205,11 @@ func (h *HTTPClient) Write(msg types.Message) error {
 // WriteWithContext attempts to send a message to an HTTP server, this attempt
 // may include retries, and if all retries fail an error is returned.
 func (h *HTTPClient) WriteWithContext(ctx context.Context, msg types.Message) error {
+	if h.conf.Idempotency.Enabled && msg.Len() > 0 {
+		if err := h.setIdempotencyKey(msg); err != nil {
+			return err
+		}
+	}
 	resultMsg, err := h.client.Send(msg)
 	if err == nil && h.conf.PropagateResponse {
 		msgCopy := msg.Copy()
@@ -123,6 +235,50 @@ func (h *HTTPClient) WriteWithContext(ctx context.Context, msg types.Message) er
 	return err
 }
 
+// setIdempotencyKey stores an idempotency key in the metadata of each message
+// of a batch unless one already exists from a previous attempt. Messages are
+// shallow copied before their metadata is modified as they may be shared with
+// other components.
+func (h *HTTPClient) setIdempotencyKey(msg types.Message) error {
+	if msg.Get(0).Metadata().Get(HTTPIdempotencyKeyMeta) != "" {
+		return nil
+	}
+	key, err := h.newIdempotencyKey(msg)
+	if err != nil {
+		return err
+	}
+	parts := make([]types.Part, msg.Len())
+	msg.Iter(func(i int, p types.Part) error {
+		parts[i] = p.Copy()
+		parts[i].Metadata().Set(HTTPIdempotencyKeyMeta, key)
+		return nil
+	})
+	msg.SetAll(parts)
+	return nil
+}
+
+// newIdempotencyKey returns the idempotency key of a request. When a key
+// expression is configured and a batch is sent as a single request the key is
+// a digest of the key of each message of the batch.
+func (h *HTTPClient) newIdempotencyKey(msg types.Message) (string, error) {
+	if h.idempotencyKey == nil {
+		u4, err := uuid.NewV4()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate idempotency key: %w", err)
+		}
+		return u4.String(), nil
+	}
+	if msg.Len() == 1 {
+		return h.idempotencyKey.String(0, msg), nil
+	}
+	hasher := sha256.New()
+	for i := 0; i < msg.Len(); i++ {
+		hasher.Write([]byte(h.idempotencyKey.String(i, msg)))
+		hasher.Write([]byte{0})
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 // CloseAsync shuts down the HTTPClient output and stops processing messages.
 func (h *HTTPClient) CloseAsync() {
 	close(h.closeChan)
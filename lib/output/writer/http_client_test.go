@@ -1,6 +1,8 @@
 package writer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -297,3 +299,188 @@ func TestHTTPClientMultipart(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestHTTPClientMultipartFields(t *testing.T) {
+	type formField struct {
+		name, filename, contentType, content string
+	}
+
+	var reqCount uint32
+	resultChan := make(chan []formField, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt in order to ensure that the body is rebuilt
+		// for retries.
+		if atomic.AddUint32(&reqCount, 1) == 1 {
+			http.Error(w, "test error", http.StatusInternalServerError)
+			return
+		}
+
+		assert.Equal(t, "bar", r.Header.Get("foo"))
+		assert.Empty(t, r.TransferEncoding)
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(body)), r.ContentLength)
+
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/form-data", mediaType)
+
+		var fields []formField
+		mr := multipart.NewReader(strings.NewReader(string(body)), params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			b, err := ioutil.ReadAll(p)
+			require.NoError(t, err)
+			fields = append(fields, formField{
+				name:        p.FormName(),
+				filename:    p.FileName(),
+				contentType: p.Header.Get("Content-Type"),
+				content:     string(b),
+			})
+		}
+		resultChan <- fields
+	}))
+	defer ts.Close()
+
+	conf := NewHTTPClientConfig()
+	conf.URL = ts.URL + "/upload"
+	conf.Retry = "1ms"
+	conf.Headers = map[string]string{
+		"Content-Type": "application/json",
+		"foo":          "bar",
+	}
+
+	fileField := NewHTTPMultipartConfig()
+	fileField.Name = "file"
+	fileField.Filename = `${! meta("filename") }`
+	conf.Multipart = append(conf.Multipart, fileField)
+
+	descField := NewHTTPMultipartConfig()
+	descField.Name = `${! meta("field") }`
+	descField.ContentType = "text/plain"
+	descField.Content = `${! content().uppercase() }`
+	conf.Multipart = append(conf.Multipart, descField)
+
+	h, err := NewHTTPClient(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msg := message.New([][]byte{
+		[]byte(`first "file"`),
+		[]byte(`second file`),
+	})
+	msg.Get(0).Metadata().Set("filename", `a "quoted" name.txt`).Set("field", "desc_a")
+	msg.Get(1).Metadata().Set("filename", "b.csv").Set("field", "desc_b")
+
+	require.NoError(t, h.Write(msg))
+
+	select {
+	case fields := <-resultChan:
+		assert.Equal(t, []formField{
+			{name: "file", filename: `a "quoted" name.txt`, contentType: "application/octet-stream", content: `first "file"`},
+			{name: "desc_a", contentType: "text/plain", content: `FIRST "FILE"`},
+			{name: "file", filename: "b.csv", contentType: "application/octet-stream", content: "second file"},
+			{name: "desc_b", contentType: "text/plain", content: "SECOND FILE"},
+		}, fields)
+	case <-time.After(time.Second):
+		t.Fatal("Action timed out")
+	}
+	assert.Equal(t, uint32(2), atomic.LoadUint32(&reqCount))
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second))
+}
+
+func TestHTTPClientMultipartFieldsBadConfig(t *testing.T) {
+	conf := NewHTTPClientConfig()
+	conf.Multipart = append(conf.Multipart, NewHTTPMultipartConfig())
+
+	_, err := NewHTTPClient(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "multipart field 0 requires a name")
+}
+
+func TestHTTPClientIdempotencyKey(t *testing.T) {
+	var reqCount uint32
+	keys := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("Idempotency-Key")
+		if atomic.AddUint32(&reqCount, 1)%2 == 1 {
+			http.Error(w, "test error", http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	conf := NewHTTPClientConfig()
+	conf.URL = ts.URL + "/testpost"
+	conf.Retry = "1ms"
+	conf.NumRetries = 3
+	conf.Idempotency.Enabled = true
+
+	h, err := NewHTTPClient(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msg := message.New([][]byte{[]byte("test")})
+	origPart := msg.Get(0)
+	require.NoError(t, h.Write(msg))
+
+	first, second := <-keys, <-keys
+	assert.NotEmpty(t, first)
+	assert.Equal(t, first, second)
+	assert.Equal(t, first, msg.Get(0).Metadata().Get(HTTPIdempotencyKeyMeta))
+	assert.Empty(t, origPart.Metadata().Get(HTTPIdempotencyKeyMeta))
+
+	// Writing the same message again, as an output level retry would, reuses
+	// the key.
+	require.NoError(t, h.Write(msg))
+	assert.Equal(t, first, <-keys)
+	assert.Equal(t, first, <-keys)
+
+	require.NoError(t, h.Write(message.New([][]byte{[]byte("test")})))
+	assert.NotEqual(t, first, <-keys)
+	<-keys
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second))
+
+	conf.Idempotency.Key = `${! json("id") }`
+	conf.Idempotency.Header = "X-Request-Id"
+	conf.Headers["x-request-id"] = "nope"
+	_, err = NewHTTPClient(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "idempotency header x-request-id must not also be set in headers")
+}
+
+func TestHTTPClientIdempotencyKeyMapping(t *testing.T) {
+	keys := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("X-Request-Id")
+	}))
+	defer ts.Close()
+
+	conf := NewHTTPClientConfig()
+	conf.URL = ts.URL + "/testpost"
+	conf.Idempotency.Enabled = true
+	conf.Idempotency.Header = "X-Request-Id"
+	conf.Idempotency.Key = `${! json("id") }`
+
+	h, err := NewHTTPClient(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	require.NoError(t, h.Write(message.New([][]byte{[]byte(`{"id":"foo"}`)})))
+	assert.Equal(t, "foo", <-keys)
+
+	// Batches sent as a single request use a digest of the key of each message
+	batchMsg := message.New([][]byte{[]byte(`{"id":"foo"}`), []byte(`{"id":"bar"}`)})
+	require.NoError(t, h.Write(batchMsg))
+
+	digest := sha256.Sum256([]byte("foo\x00bar\x00"))
+	assert.Equal(t, hex.EncodeToString(digest[:]), <-keys)
+	assert.Equal(t, hex.EncodeToString(digest[:]), batchMsg.Get(1).Metadata().Get(HTTPIdempotencyKeyMeta))
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second))
+}
@@ -3,9 +3,11 @@ package writer
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	batchInternal "github.com/Jeffail/benthos/v3/internal/batch"
@@ -36,11 +38,13 @@ type KafkaConfig struct {
 	Compression      string      `json:"compression" yaml:"compression"`
 	MaxMsgBytes      int         `json:"max_msg_bytes" yaml:"max_msg_bytes"`
 	Timeout          string      `json:"timeout" yaml:"timeout"`
+	ShutdownTimeout  string      `json:"shutdown_timeout" yaml:"shutdown_timeout"`
 	AckReplicas      bool        `json:"ack_replicas" yaml:"ack_replicas"`
 	TargetVersion    string      `json:"target_version" yaml:"target_version"`
 	TLS              btls.Config `json:"tls" yaml:"tls"`
 	SASL             sasl.Config `json:"sasl" yaml:"sasl"`
 	MaxInFlight      int         `json:"max_in_flight" yaml:"max_in_flight"`
+	PreserveOrder    bool        `json:"preserve_order" yaml:"preserve_order"`
 	retries.Config   `json:",inline" yaml:",inline"`
 	RetryAsBatch     bool               `json:"retry_as_batch" yaml:"retry_as_batch"`
 	Batching         batch.PolicyConfig `json:"batching" yaml:"batching"`
@@ -69,6 +73,7 @@ func NewKafkaConfig() KafkaConfig {
 		Compression:          "none",
 		MaxMsgBytes:          1000000,
 		Timeout:              "5s",
+		ShutdownTimeout:      "10s",
 		AckReplicas:          false,
 		TargetVersion:        sarama.V1_0_0_0.String(),
 		StaticHeaders:        map[string]string{},
@@ -76,6 +81,7 @@ func NewKafkaConfig() KafkaConfig {
 		TLS:                  btls.NewConfig(),
 		SASL:                 sasl.NewConfig(),
 		MaxInFlight:          1,
+		PreserveOrder:        false,
 		Config:               rConf,
 		RetryAsBatch:         false,
 		Batching:             batch.NewPolicyConfig(),
@@ -92,8 +98,9 @@ type Kafka struct {
 
 	backoffCtor func() backoff.BackOff
 
-	tlsConf *tls.Config
-	timeout time.Duration
+	tlsConf         *tls.Config
+	timeout         time.Duration
+	shutdownTimeout time.Duration
 
 	addresses []string
 	version   sarama.KafkaVersion
@@ -102,6 +109,7 @@ type Kafka struct {
 	key   *field.Expression
 	topic *field.Expression
 
+	client      sarama.Client
 	producer    sarama.SyncProducer
 	compression sarama.CompressionCodec
 	partitioner sarama.PartitionerConstructor
@@ -109,6 +117,9 @@ type Kafka struct {
 	staticHeaders map[string]string
 	metaFilter    *output.MetadataFilter
 
+	mShutdownFailed metrics.StatCounter
+	shutdownFailed  int64
+
 	connMut sync.RWMutex
 }
 
@@ -138,6 +149,8 @@ func NewKafka(conf KafkaConfig, mgr types.Manager, log log.Modular, stats metric
 		compression:   compression,
 		partitioner:   partitioner,
 		staticHeaders: conf.StaticHeaders,
+
+		mShutdownFailed: stats.GetCounter("shutdown.flush_failed"),
 	}
 
 	if k.metaFilter, err = conf.Metadata.Filter(); err != nil {
@@ -160,6 +173,12 @@ func NewKafka(conf KafkaConfig, mgr types.Manager, log log.Modular, stats metric
 			return nil, fmt.Errorf("failed to parse timeout string: %v", err)
 		}
 	}
+	if tout := conf.ShutdownTimeout; len(tout) > 0 {
+		var err error
+		if k.shutdownTimeout, err = time.ParseDuration(tout); err != nil {
+			return nil, fmt.Errorf("failed to parse shutdown timeout string: %v", err)
+		}
+	}
 
 	if conf.TLS.Enabled {
 		var err error
@@ -299,13 +318,78 @@ func (k *Kafka) Connect() error {
 		config.Producer.RequiredAcks = sarama.WaitForLocal
 	}
 
-	var err error
-	k.producer, err = sarama.NewSyncProducer(k.addresses, config)
+	client, err := sarama.NewClient(k.addresses, config)
+	if err != nil {
+		return err
+	}
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		client.Close()
+		return err
+	}
 
-	if err == nil {
-		k.log.Infof("Sending Kafka messages to addresses: %s\n", k.addresses)
+	k.client, k.producer = client, producer
+	k.log.Infof("Sending Kafka messages to addresses: %s\n", k.addresses)
+	return nil
+}
+
+// HealthCheck verifies that the Kafka brokers are still reachable by
+// refreshing the cluster metadata.
+func (k *Kafka) HealthCheck(ctx context.Context) error {
+	k.connMut.RLock()
+	client := k.client
+	k.connMut.RUnlock()
+
+	if client == nil {
+		return types.ErrNotConnected
 	}
-	return err
+	return client.RefreshMetadata()
+}
+
+// errShutdownFlush is returned for messages that were not acknowledged before
+// the shutdown timeout elapsed.
+var errShutdownFlush = errors.New("message was not acknowledged within the shutdown timeout")
+
+// sendMessages sends messages to Kafka and waits for acknowledgement. Once the
+// context is cancelled, which indicates that the output is shutting down, the
+// acknowledgement is only waited upon until the shutdown timeout elapses, at
+// which point the messages are considered to have failed.
+func (k *Kafka) sendMessages(ctx context.Context, producer sarama.SyncProducer, msg types.Message, msgs []*sarama.ProducerMessage) error {
+	if k.shutdownTimeout <= 0 {
+		return producer.SendMessages(msgs)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- producer.SendMessages(msgs)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(k.shutdownTimeout):
+	}
+
+	batchErr := batchInternal.NewError(msg, errShutdownFlush)
+	for _, m := range msgs {
+		if mIndex, ok := m.Metadata.(int); ok {
+			batchErr.Failed(mIndex, errShutdownFlush)
+		}
+	}
+	return batchErr
+}
+
+// recordShutdownFailed records messages that failed to be flushed after the
+// output began shutting down.
+func (k *Kafka) recordShutdownFailed(n int) {
+	k.mShutdownFailed.Incr(int64(n))
+	atomic.AddInt64(&k.shutdownFailed, int64(n))
 }
 
 // Write will attempt to write a message to Kafka, wait for acknowledgement, and
@@ -344,7 +428,7 @@ func (k *Kafka) WriteWithContext(ctx context.Context, msg types.Message) error {
 		return nil
 	})
 
-	err := producer.SendMessages(msgs)
+	err := k.sendMessages(ctx, producer, msg, msgs)
 	for err != nil {
 		if pErrs, ok := err.(sarama.ProducerErrors); !k.conf.RetryAsBatch && ok {
 			if len(pErrs) == 0 {
@@ -371,12 +455,20 @@ func (k *Kafka) WriteWithContext(ctx context.Context, msg types.Message) error {
 			k.log.Errorf("Failed to send messages: %v\n", err)
 		}
 
+		// Retries are abandoned once shutting down so that the messages are
+		// rejected and can be redelivered by the input.
+		if ctx.Err() != nil {
+			k.recordShutdownFailed(len(msgs))
+			return err
+		}
+
 		tNext := boff.NextBackOff()
 		if tNext == backoff.Stop {
 			return err
 		}
 		select {
 		case <-ctx.Done():
+			k.recordShutdownFailed(len(msgs))
 			return err
 		case <-time.After(tNext):
 		}
@@ -389,7 +481,7 @@ func (k *Kafka) WriteWithContext(ctx context.Context, msg types.Message) error {
 		if producer == nil {
 			return types.ErrNotConnected
 		}
-		err = producer.SendMessages(msgs)
+		err = k.sendMessages(ctx, producer, msg, msgs)
 	}
 
 	return nil
@@ -398,10 +490,17 @@ func (k *Kafka) WriteWithContext(ctx context.Context, msg types.Message) error {
 // CloseAsync shuts down the Kafka writer and stops processing messages.
 func (k *Kafka) CloseAsync() {
 	go func() {
+		if n := atomic.LoadInt64(&k.shutdownFailed); n > 0 {
+			k.log.Errorf("Failed to flush %v messages to Kafka during shutdown, these messages were rejected\n", n)
+		} else {
+			k.log.Infoln("Flushed all messages to Kafka during shutdown")
+		}
+
 		k.connMut.Lock()
 		if k.producer != nil {
 			k.producer.Close()
-			k.producer = nil
+			k.client.Close()
+			k.producer, k.client = nil, nil
 		}
 		k.connMut.Unlock()
 	}()
@@ -0,0 +1,148 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/batch"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type blockingProducer struct {
+	release chan struct{}
+}
+
+func (b *blockingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	return 0, 0, b.SendMessages([]*sarama.ProducerMessage{msg})
+}
+
+func (b *blockingProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	<-b.release
+	return nil
+}
+
+func (b *blockingProducer) Close() error {
+	return nil
+}
+
+type failingProducer struct {
+	calls int
+}
+
+func (f *failingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	return 0, 0, f.SendMessages([]*sarama.ProducerMessage{msg})
+}
+
+func (f *failingProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	f.calls++
+	return sarama.ProducerErrors{
+		{Msg: msgs[len(msgs)-1], Err: errors.New("nope")},
+	}
+}
+
+func (f *failingProducer) Close() error {
+	return nil
+}
+
+func TestKafkaShutdownTimeout(t *testing.T) {
+	conf := NewKafkaConfig()
+	conf.ShutdownTimeout = "10ms"
+
+	stats := metrics.NewLocal()
+	k, err := NewKafka(conf, types.NoopMgr(), log.Noop(), stats)
+	require.NoError(t, err)
+
+	producer := &blockingProducer{release: make(chan struct{})}
+	defer close(producer.release)
+	k.producer = producer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- k.WriteWithContext(ctx, message.New([][]byte{
+			[]byte("foo"), []byte("bar"),
+		}))
+	}()
+
+	select {
+	case err := <-errChan:
+		t.Fatalf("Unexpected write result before shutdown: %v", err)
+	case <-time.After(time.Millisecond * 50):
+	}
+	cancel()
+
+	select {
+	case err = <-errChan:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for write to be abandoned")
+	}
+
+	var bErr *batch.Error
+	require.True(t, errors.As(err, &bErr), err)
+	assert.Equal(t, 2, bErr.IndexedErrors())
+
+	assert.Equal(t, int64(2), stats.GetCounters()["shutdown.flush_failed"])
+}
+
+func TestKafkaShutdownStopsRetries(t *testing.T) {
+	conf := NewKafkaConfig()
+	conf.Backoff.InitialInterval = "1ms"
+	conf.Backoff.MaxInterval = "1ms"
+
+	stats := metrics.NewLocal()
+	k, err := NewKafka(conf, types.NoopMgr(), log.Noop(), stats)
+	require.NoError(t, err)
+
+	producer := &failingProducer{}
+	k.producer = producer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = k.WriteWithContext(ctx, message.New([][]byte{
+		[]byte("foo"), []byte("bar"), []byte("baz"),
+	}))
+
+	var bErr *batch.Error
+	require.True(t, errors.As(err, &bErr), err)
+	assert.Equal(t, 1, bErr.IndexedErrors())
+
+	assert.Equal(t, 1, producer.calls)
+	assert.Equal(t, int64(1), stats.GetCounters()["shutdown.flush_failed"])
+}
+
+func TestKafkaHealthCheck(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()),
+	})
+
+	conf := NewKafkaConfig()
+	conf.Addresses = []string{broker.Addr()}
+	conf.Topic = "foo"
+
+	k, err := NewKafka(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	assert.Equal(t, types.ErrNotConnected, k.HealthCheck(context.Background()))
+
+	require.NoError(t, k.Connect())
+	defer func() {
+		k.CloseAsync()
+		assert.NoError(t, k.WaitForClose(time.Second))
+	}()
+
+	assert.NoError(t, k.HealthCheck(context.Background()))
+
+	broker.Close()
+	assert.Error(t, k.HealthCheck(context.Background()))
+}
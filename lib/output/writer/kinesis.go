@@ -39,6 +39,7 @@ type KinesisConfig struct {
 	HashKey        string `json:"hash_key" yaml:"hash_key"`
 	PartitionKey   string `json:"partition_key" yaml:"partition_key"`
 	MaxInFlight    int    `json:"max_in_flight" yaml:"max_in_flight"`
+	PreserveOrder  bool   `json:"preserve_order" yaml:"preserve_order"`
 	retries.Config `json:",inline" yaml:",inline"`
 	Batching       batch.PolicyConfig `json:"batching" yaml:"batching"`
 }
@@ -53,12 +54,13 @@ func NewKinesisConfig() KinesisConfig {
 		sessionConfig: sessionConfig{
 			Config: sess.NewConfig(),
 		},
-		Stream:       "",
-		HashKey:      "",
-		PartitionKey: "",
-		MaxInFlight:  1,
-		Config:       rConf,
-		Batching:     batch.NewPolicyConfig(),
+		Stream:        "",
+		HashKey:       "",
+		PartitionKey:  "",
+		MaxInFlight:   1,
+		PreserveOrder: false,
+		Config:        rConf,
+		Batching:      batch.NewPolicyConfig(),
 	}
 }
 
@@ -22,10 +22,11 @@ import (
 
 // NATSConfig contains configuration fields for the NATS output type.
 type NATSConfig struct {
-	URLs        []string    `json:"urls" yaml:"urls"`
-	Subject     string      `json:"subject" yaml:"subject"`
-	MaxInFlight int         `json:"max_in_flight" yaml:"max_in_flight"`
-	TLS         btls.Config `json:"tls" yaml:"tls"`
+	URLs        []string          `json:"urls" yaml:"urls"`
+	Subject     string            `json:"subject" yaml:"subject"`
+	Headers     map[string]string `json:"headers" yaml:"headers"`
+	MaxInFlight int               `json:"max_in_flight" yaml:"max_in_flight"`
+	TLS         btls.Config       `json:"tls" yaml:"tls"`
 }
 
 // NewNATSConfig creates a new NATSConfig with default values.
@@ -33,6 +34,7 @@ func NewNATSConfig() NATSConfig {
 	return NATSConfig{
 		URLs:        []string{nats.DefaultURL},
 		Subject:     "benthos_messages",
+		Headers:     map[string]string{},
 		MaxInFlight: 1,
 		TLS:         btls.NewConfig(),
 	}
@@ -50,19 +52,26 @@ type NATS struct {
 	urls       string
 	conf       NATSConfig
 	subjectStr *field.Expression
+	headers    map[string]*field.Expression
 	tlsConf    *tls.Config
 }
 
 // NewNATS creates a new NATS output type.
 func NewNATS(conf NATSConfig, log log.Modular, stats metrics.Type) (*NATS, error) {
 	n := NATS{
-		log:  log,
-		conf: conf,
+		log:     log,
+		conf:    conf,
+		headers: map[string]*field.Expression{},
 	}
 	var err error
 	if n.subjectStr, err = bloblang.NewField(conf.Subject); err != nil {
 		return nil, fmt.Errorf("failed to parse subject expression: %v", err)
 	}
+	for k, v := range conf.Headers {
+		if n.headers[k], err = bloblang.NewField(v); err != nil {
+			return nil, fmt.Errorf("failed to parse header '%v' expression: %v", k, err)
+		}
+	}
 	n.urls = strings.Join(conf.URLs, ",")
 
 	if conf.TLS.Enabled {
@@ -125,7 +134,7 @@ func (n *NATS) Write(msg types.Message) error {
 	return IterateBatchedSend(msg, func(i int, p types.Part) error {
 		subject := n.subjectStr.String(i, msg)
 		n.log.Debugf("Writing NATS message to topic %s", subject)
-		err := conn.Publish(subject, p.Get())
+		err := conn.PublishMsg(NATSMsgWithHeaders(subject, i, msg, n.headers))
 		if err == nats.ErrConnectionClosed {
 			conn.Close()
 			n.connMut.Lock()
@@ -137,6 +146,24 @@ func (n *NATS) Write(msg types.Message) error {
 	})
 }
 
+// NATSMsgWithHeaders creates a NATS message for a message part with headers
+// resolved from a map of interpolated expressions. The header of the message is
+// left empty when there are no headers, as otherwise the message would be
+// rejected by servers that do not support headers.
+func NATSMsgWithHeaders(subject string, index int, msg types.Message, headers map[string]*field.Expression) *nats.Msg {
+	nmsg := &nats.Msg{
+		Subject: subject,
+		Data:    msg.Get(index).Get(),
+	}
+	if len(headers) > 0 {
+		nmsg.Header = nats.Header{}
+		for k, v := range headers {
+			nmsg.Header.Set(k, v.String(index, msg))
+		}
+	}
+	return nmsg
+}
+
 // CloseAsync shuts down the MQTT output and stops processing messages.
 func (n *NATS) CloseAsync() {
 	go func() {
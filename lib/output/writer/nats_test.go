@@ -0,0 +1,40 @@
+package writer
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATSMsgWithHeaders(t *testing.T) {
+	headers := map[string]*field.Expression{}
+	for k, v := range map[string]string{
+		"Content-Type": "application/json",
+		"traceparent":  `${! meta("traceparent") }`,
+	} {
+		var err error
+		headers[k], err = bloblang.NewField(v)
+		require.NoError(t, err)
+	}
+
+	msg := message.New([][]byte{[]byte("first"), []byte("second")})
+	msg.Get(0).Metadata().Set("traceparent", "foo")
+	msg.Get(1).Metadata().Set("traceparent", "bar")
+
+	nmsg := NATSMsgWithHeaders("subject", 1, msg, headers)
+	assert.Equal(t, "subject", nmsg.Subject)
+	assert.Equal(t, "second", string(nmsg.Data))
+	assert.Equal(t, nats.Header{
+		"Content-Type": []string{"application/json"},
+		"traceparent":  []string{"bar"},
+	}, nmsg.Header)
+
+	nmsg = NATSMsgWithHeaders("subject", 0, msg, nil)
+	assert.Equal(t, "first", string(nmsg.Data))
+	assert.Nil(t, nmsg.Header)
+}
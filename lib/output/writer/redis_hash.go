@@ -194,6 +194,18 @@ func (r *RedisHash) disconnect() error {
 	return nil
 }
 
+// HealthCheck verifies that the Redis server is still reachable.
+func (r *RedisHash) HealthCheck(ctx context.Context) error {
+	r.connMut.RLock()
+	client := r.client
+	r.connMut.RUnlock()
+
+	if client == nil {
+		return types.ErrNotConnected
+	}
+	return client.Ping().Err()
+}
+
 // CloseAsync shuts down the RedisHash output and stops processing messages.
 func (r *RedisHash) CloseAsync() {
 	r.disconnect()
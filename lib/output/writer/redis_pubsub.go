@@ -136,6 +136,18 @@ func (r *RedisPubSub) disconnect() error {
 	return nil
 }
 
+// HealthCheck verifies that the Redis server is still reachable.
+func (r *RedisPubSub) HealthCheck(ctx context.Context) error {
+	r.connMut.RLock()
+	client := r.client
+	r.connMut.RUnlock()
+
+	if client == nil {
+		return types.ErrNotConnected
+	}
+	return client.Ping().Err()
+}
+
 // CloseAsync shuts down the RedisPubSub output and stops processing messages.
 func (r *RedisPubSub) CloseAsync() {
 	r.disconnect()
@@ -152,6 +152,18 @@ func (r *RedisStreams) disconnect() error {
 	return nil
 }
 
+// HealthCheck verifies that the Redis server is still reachable.
+func (r *RedisStreams) HealthCheck(ctx context.Context) error {
+	r.connMut.RLock()
+	client := r.client
+	r.connMut.RUnlock()
+
+	if client == nil {
+		return types.ErrNotConnected
+	}
+	return client.Ping().Err()
+}
+
 // CloseAsync shuts down the RedisStreams output and stops processing messages.
 func (r *RedisStreams) CloseAsync() {
 	r.disconnect()
@@ -3,7 +3,11 @@ package writer
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"sort"
 	"strings"
@@ -12,14 +16,19 @@ import (
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
 	"github.com/Jeffail/benthos/v3/internal/component/output"
+	"github.com/Jeffail/benthos/v3/internal/interop"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message/batch"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
 	sess "github.com/Jeffail/benthos/v3/lib/util/aws/session"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
 )
 
 //------------------------------------------------------------------------------
@@ -27,18 +36,37 @@ import (
 // AmazonS3Config contains configuration fields for the AmazonS3 output type.
 type AmazonS3Config struct {
 	sess.Config        `json:",inline" yaml:",inline"`
-	Bucket             string             `json:"bucket" yaml:"bucket"`
-	ForcePathStyleURLs bool               `json:"force_path_style_urls" yaml:"force_path_style_urls"`
-	Path               string             `json:"path" yaml:"path"`
-	Tags               map[string]string  `json:"tags" yaml:"tags"`
-	ContentType        string             `json:"content_type" yaml:"content_type"`
-	ContentEncoding    string             `json:"content_encoding" yaml:"content_encoding"`
-	Metadata           output.Metadata    `json:"metadata" yaml:"metadata"`
-	StorageClass       string             `json:"storage_class" yaml:"storage_class"`
-	Timeout            string             `json:"timeout" yaml:"timeout"`
-	KMSKeyID           string             `json:"kms_key_id" yaml:"kms_key_id"`
-	MaxInFlight        int                `json:"max_in_flight" yaml:"max_in_flight"`
-	Batching           batch.PolicyConfig `json:"batching" yaml:"batching"`
+	Bucket             string              `json:"bucket" yaml:"bucket"`
+	ForcePathStyleURLs bool                `json:"force_path_style_urls" yaml:"force_path_style_urls"`
+	Path               string              `json:"path" yaml:"path"`
+	Tags               map[string]string   `json:"tags" yaml:"tags"`
+	ContentType        string              `json:"content_type" yaml:"content_type"`
+	ContentEncoding    string              `json:"content_encoding" yaml:"content_encoding"`
+	Metadata           output.Metadata     `json:"metadata" yaml:"metadata"`
+	StorageClass       string              `json:"storage_class" yaml:"storage_class"`
+	Timeout            string              `json:"timeout" yaml:"timeout"`
+	KMSKeyID           string              `json:"kms_key_id" yaml:"kms_key_id"`
+	MaxInFlight        int                 `json:"max_in_flight" yaml:"max_in_flight"`
+	Batching           batch.PolicyConfig  `json:"batching" yaml:"batching"`
+	Deduplicate        S3DeduplicateConfig `json:"deduplicate" yaml:"deduplicate"`
+}
+
+// S3DeduplicateConfig contains configuration fields for skipping the upload of
+// objects whose content has already been written to the bucket.
+type S3DeduplicateConfig struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled"`
+	Cache    string `json:"cache" yaml:"cache"`
+	Paranoid bool   `json:"paranoid" yaml:"paranoid"`
+}
+
+// NewS3DeduplicateConfig creates a new S3DeduplicateConfig with default
+// values.
+func NewS3DeduplicateConfig() S3DeduplicateConfig {
+	return S3DeduplicateConfig{
+		Enabled:  false,
+		Cache:    "",
+		Paranoid: false,
+	}
 }
 
 // NewAmazonS3Config creates a new Config with default values.
@@ -57,6 +85,7 @@ func NewAmazonS3Config() AmazonS3Config {
 		KMSKeyID:           "",
 		MaxInFlight:        1,
 		Batching:           batch.NewPolicyConfig(),
+		Deduplicate:        NewS3DeduplicateConfig(),
 	}
 }
 
@@ -80,18 +109,35 @@ type AmazonS3 struct {
 	metaFilter      *output.MetadataFilter
 
 	session  *session.Session
-	uploader *s3manager.Uploader
+	uploader s3manageriface.UploaderAPI
+	s3       s3iface.S3API
 	timeout  time.Duration
 
+	mDeduped metrics.StatCounter
+
+	mgr   types.Manager
 	log   log.Modular
 	stats metrics.Type
 }
 
 // NewAmazonS3 creates a new Amazon S3 bucket writer.Type.
+//
+// Deprecated: use NewAmazonS3V2 instead.
 func NewAmazonS3(
 	conf AmazonS3Config,
 	log log.Modular,
 	stats metrics.Type,
+) (*AmazonS3, error) {
+	return NewAmazonS3V2(conf, types.NoopMgr(), log, stats)
+}
+
+// NewAmazonS3V2 creates a new Amazon S3 bucket writer.Type with access to
+// resources.
+func NewAmazonS3V2(
+	conf AmazonS3Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
 ) (*AmazonS3, error) {
 	var timeout time.Duration
 	if tout := conf.Timeout; len(tout) > 0 {
@@ -101,10 +147,17 @@ func NewAmazonS3(
 		}
 	}
 	a := &AmazonS3{
-		conf:    conf,
-		log:     log,
-		stats:   stats,
-		timeout: timeout,
+		conf:     conf,
+		mDeduped: stats.GetCounter("deduplicated"),
+		mgr:      mgr,
+		log:      log,
+		stats:    stats,
+		timeout:  timeout,
+	}
+	if conf.Deduplicate.Enabled && conf.Deduplicate.Cache != "" {
+		if err := interop.ProbeCache(context.Background(), mgr, conf.Deduplicate.Cache); err != nil {
+			return nil, err
+		}
 	}
 	var err error
 	if a.path, err = bloblang.NewField(conf.Path); err != nil {
@@ -162,6 +215,7 @@ func (a *AmazonS3) Connect() error {
 
 	a.session = sess
 	a.uploader = s3manager.NewUploader(sess)
+	a.s3 = s3.New(sess)
 
 	a.log.Infof("Uploading message parts as objects to Amazon S3 bucket: %v\n", a.conf.Bucket)
 	return nil
@@ -175,7 +229,7 @@ func (a *AmazonS3) Write(msg types.Message) error {
 // WriteWithContext attempts to write message contents to a target S3 bucket as
 // files.
 func (a *AmazonS3) WriteWithContext(wctx context.Context, msg types.Message) error {
-	if a.session == nil {
+	if a.uploader == nil {
 		return types.ErrNotConnected
 	}
 
@@ -185,46 +239,168 @@ func (a *AmazonS3) WriteWithContext(wctx context.Context, msg types.Message) err
 	defer cancel()
 
 	return IterateBatchedSend(msg, func(i int, p types.Part) error {
+		key := a.path.String(i, msg)
+
 		metadata := map[string]*string{}
 		a.metaFilter.Iter(p.Metadata(), func(k, v string) error {
 			metadata[k] = aws.String(v)
 			return nil
 		})
 
-		var contentEncoding *string
-		if ce := a.contentEncoding.String(i, msg); len(ce) > 0 {
-			contentEncoding = aws.String(ce)
+		var contentHash string
+		if a.conf.Deduplicate.Enabled {
+			hashBytes := sha256.Sum256(p.Get())
+			contentHash = hex.EncodeToString(hashBytes[:])
+
+			existingKey, err := a.findDuplicate(ctx, key, contentHash, p.Get())
+			if err != nil {
+				return err
+			}
+			if existingKey == key {
+				a.log.Tracef("Skipping upload of object '%v' as its content already exists\n", key)
+				a.mDeduped.Incr(1)
+				return nil
+			}
+			metadata[s3DedupeHashMetaKey] = aws.String(contentHash)
+			if existingKey != "" {
+				// Upload an empty object that references the existing object
+				// rather than uploading the same content again.
+				a.log.Tracef("Uploading object '%v' as a reference to existing object '%v'\n", key, existingKey)
+				a.mDeduped.Incr(1)
+				metadata[s3DedupeRefMetaKey] = aws.String(existingKey)
+				return a.upload(ctx, i, msg, key, nil, metadata)
+			}
+		}
+
+		if err := a.upload(ctx, i, msg, key, p.Get(), metadata); err != nil {
+			return err
+		}
+		if contentHash != "" && a.conf.Deduplicate.Cache != "" {
+			return a.recordHash(ctx, contentHash, key)
 		}
+		return nil
+	})
+}
+
+func (a *AmazonS3) upload(ctx context.Context, i int, msg types.Message, key string, body []byte, metadata map[string]*string) error {
+	var contentEncoding *string
+	if ce := a.contentEncoding.String(i, msg); len(ce) > 0 {
+		contentEncoding = aws.String(ce)
+	}
+
+	uploadInput := &s3manager.UploadInput{
+		Bucket:          &a.conf.Bucket,
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(body),
+		ContentType:     aws.String(a.contentType.String(i, msg)),
+		ContentEncoding: contentEncoding,
+		StorageClass:    aws.String(a.storageClass.String(i, msg)),
+		Metadata:        metadata,
+	}
 
-		uploadInput := &s3manager.UploadInput{
-			Bucket:          &a.conf.Bucket,
-			Key:             aws.String(a.path.String(i, msg)),
-			Body:            bytes.NewReader(p.Get()),
-			ContentType:     aws.String(a.contentType.String(i, msg)),
-			ContentEncoding: contentEncoding,
-			StorageClass:    aws.String(a.storageClass.String(i, msg)),
-			Metadata:        metadata,
+	// Prepare tags, escaping keys and values to ensure they're valid query string parameters.
+	if len(a.tags) > 0 {
+		tags := make([]string, len(a.tags))
+		for j, pair := range a.tags {
+			tags[j] = url.QueryEscape(pair.key) + "=" + url.QueryEscape(pair.value.String(i, msg))
 		}
+		uploadInput.Tagging = aws.String(strings.Join(tags, "&"))
+	}
+
+	if a.conf.KMSKeyID != "" {
+		uploadInput.ServerSideEncryption = aws.String("aws:kms")
+		uploadInput.SSEKMSKeyId = &a.conf.KMSKeyID
+	}
 
-		// Prepare tags, escaping keys and values to ensure they're valid query string parameters.
-		if len(a.tags) > 0 {
-			tags := make([]string, len(a.tags))
-			for j, pair := range a.tags {
-				tags[j] = url.QueryEscape(pair.key) + "=" + url.QueryEscape(pair.value.String(i, msg))
+	_, err := a.uploader.UploadWithContext(ctx, uploadInput)
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	s3DedupeHashMetaKey = "benthos_content_sha256"
+	s3DedupeRefMetaKey  = "benthos_content_ref"
+)
+
+// findDuplicate returns the key of an existing object with the same content
+// hash, or an empty string if there isn't one. When a cache is configured it is
+// used as the index of content hashes, otherwise the target key is checked
+// with a HEAD request.
+func (a *AmazonS3) findDuplicate(ctx context.Context, key, contentHash string, content []byte) (string, error) {
+	var existingKey string
+	if a.conf.Deduplicate.Cache != "" {
+		var err error
+		if cerr := interop.AccessCache(ctx, a.mgr, a.conf.Deduplicate.Cache, func(cache types.Cache) {
+			var v []byte
+			if v, err = cache.Get(contentHash); err == nil {
+				existingKey = string(v)
+			} else if errors.Is(err, types.ErrKeyNotFound) {
+				err = nil
 			}
-			uploadInput.Tagging = aws.String(strings.Join(tags, "&"))
+		}); cerr != nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to query deduplicate cache: %w", err)
 		}
+	} else {
+		head, err := a.s3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket: &a.conf.Bucket,
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.RequestFailure); ok && aerr.StatusCode() == 404 {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to check for existing object: %w", err)
+		}
+		for k, v := range head.Metadata {
+			if strings.EqualFold(k, s3DedupeHashMetaKey) && v != nil && *v == contentHash {
+				existingKey = key
+			}
+		}
+	}
+	if existingKey == "" || !a.conf.Deduplicate.Paranoid {
+		return existingKey, nil
+	}
 
-		if a.conf.KMSKeyID != "" {
-			uploadInput.ServerSideEncryption = aws.String("aws:kms")
-			uploadInput.SSEKMSKeyId = &a.conf.KMSKeyID
+	obj, err := a.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: &a.conf.Bucket,
+		Key:    aws.String(existingKey),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.RequestFailure); ok && aerr.StatusCode() == 404 {
+			return "", nil
 		}
+		return "", fmt.Errorf("failed to download existing object for comparison: %w", err)
+	}
+	defer obj.Body.Close()
 
-		if _, err := a.uploader.UploadWithContext(ctx, uploadInput); err != nil {
-			return err
+	existing, err := ioutil.ReadAll(obj.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to download existing object for comparison: %w", err)
+	}
+	if !bytes.Equal(existing, content) {
+		a.log.Warnf("Content hash collision detected between object '%v' and existing object '%v'\n", key, existingKey)
+		return "", nil
+	}
+	return existingKey, nil
+}
+
+func (a *AmazonS3) recordHash(ctx context.Context, contentHash, key string) error {
+	var err error
+	if cerr := interop.AccessCache(ctx, a.mgr, a.conf.Deduplicate.Cache, func(cache types.Cache) {
+		if err = cache.Add(contentHash, []byte(key)); errors.Is(err, types.ErrKeyAlreadyExists) {
+			err = nil
 		}
-		return nil
-	})
+	}); cerr != nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record content hash in deduplicate cache: %w", err)
+	}
+	return nil
 }
 
 // CloseAsync begins cleaning up resources used by this reader asynchronously.
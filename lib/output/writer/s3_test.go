@@ -0,0 +1,217 @@
+package writer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockS3Object struct {
+	body     []byte
+	metadata map[string]*string
+}
+
+type mockS3 struct {
+	s3iface.S3API
+	s3manageriface.UploaderAPI
+
+	objects map[string]mockS3Object
+	gets    int
+}
+
+func (m *mockS3) UploadWithContext(ctx aws.Context, input *s3manager.UploadInput, opts ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	body, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.objects[*input.Key] = mockS3Object{
+		body:     body,
+		metadata: input.Metadata,
+	}
+	return &s3manager.UploadOutput{}, nil
+}
+
+func (m *mockS3) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	obj, exists := m.objects[*input.Key]
+	if !exists {
+		return nil, awserr.NewRequestFailure(awserr.New("NotFound", "not found", nil), http.StatusNotFound, "")
+	}
+	return &s3.HeadObjectOutput{Metadata: obj.metadata}, nil
+}
+
+func (m *mockS3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	m.gets++
+	obj, exists := m.objects[*input.Key]
+	if !exists {
+		return nil, awserr.NewRequestFailure(awserr.New("NoSuchKey", "not found", nil), http.StatusNotFound, "")
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(obj.body))}, nil
+}
+
+type mapCache struct {
+	values map[string][]byte
+}
+
+func (m *mapCache) Get(key string) ([]byte, error) {
+	if v, exists := m.values[key]; exists {
+		return v, nil
+	}
+	return nil, types.ErrKeyNotFound
+}
+
+func (m *mapCache) Set(key string, value []byte) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *mapCache) SetMulti(items map[string][]byte) error {
+	for k, v := range items {
+		m.values[k] = v
+	}
+	return nil
+}
+
+func (m *mapCache) Add(key string, value []byte) error {
+	if _, exists := m.values[key]; exists {
+		return types.ErrKeyAlreadyExists
+	}
+	m.values[key] = value
+	return nil
+}
+
+func (m *mapCache) Delete(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+func (m *mapCache) CloseAsync() {}
+
+func (m *mapCache) WaitForClose(time.Duration) error {
+	return nil
+}
+
+func newMockedS3(t *testing.T, conf AmazonS3Config, mgr types.Manager) (*AmazonS3, *mockS3) {
+	t.Helper()
+
+	w, err := NewAmazonS3V2(conf, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	m := &mockS3{objects: map[string]mockS3Object{}}
+	w.uploader = m
+	w.s3 = m
+	return w, m
+}
+
+func TestS3DeduplicateHead(t *testing.T) {
+	conf := NewAmazonS3Config()
+	conf.Path = `${! content().hash("sha256").encode("hex") }`
+	conf.Deduplicate.Enabled = true
+
+	w, m := newMockedS3(t, conf, types.NoopMgr())
+
+	require.NoError(t, w.Write(message.New([][]byte{[]byte("foo")})))
+	require.Len(t, m.objects, 1)
+
+	for k, obj := range m.objects {
+		assert.Equal(t, "foo", string(obj.body))
+		assert.Equal(t, k, *obj.metadata[s3DedupeHashMetaKey])
+		m.objects[k] = mockS3Object{
+			body:     []byte("replaced"),
+			metadata: obj.metadata,
+		}
+	}
+
+	require.NoError(t, w.Write(message.New([][]byte{[]byte("foo")})))
+	require.Len(t, m.objects, 1)
+	for _, obj := range m.objects {
+		assert.Equal(t, "replaced", string(obj.body))
+	}
+	assert.Equal(t, 0, m.gets)
+}
+
+func TestS3DeduplicateCache(t *testing.T) {
+	c := &mapCache{values: map[string][]byte{}}
+	mgr := &fakeMgr{
+		caches: map[string]types.Cache{
+			"foocache": c,
+		},
+	}
+
+	conf := NewAmazonS3Config()
+	conf.Path = `${! meta("key") }`
+	conf.Deduplicate.Enabled = true
+	conf.Deduplicate.Cache = "foocache"
+
+	w, m := newMockedS3(t, conf, mgr)
+
+	writeWithKey := func(key, content string) {
+		t.Helper()
+		msg := message.New([][]byte{[]byte(content)})
+		msg.Get(0).Metadata().Set("key", key)
+		require.NoError(t, w.Write(msg))
+	}
+
+	writeWithKey("a.txt", "foo")
+	writeWithKey("b.txt", "foo")
+	writeWithKey("c.txt", "bar")
+
+	require.Len(t, m.objects, 3)
+	assert.Equal(t, "foo", string(m.objects["a.txt"].body))
+	assert.Equal(t, "", string(m.objects["b.txt"].body))
+	assert.Equal(t, "a.txt", *m.objects["b.txt"].metadata[s3DedupeRefMetaKey])
+	assert.Equal(t, "bar", string(m.objects["c.txt"].body))
+	assert.Len(t, c.values, 2)
+	assert.Equal(t, 0, m.gets)
+}
+
+func TestS3DeduplicateParanoid(t *testing.T) {
+	c := &mapCache{values: map[string][]byte{}}
+	mgr := &fakeMgr{
+		caches: map[string]types.Cache{
+			"foocache": c,
+		},
+	}
+
+	conf := NewAmazonS3Config()
+	conf.Path = `${! meta("key") }`
+	conf.Deduplicate.Enabled = true
+	conf.Deduplicate.Cache = "foocache"
+	conf.Deduplicate.Paranoid = true
+
+	w, m := newMockedS3(t, conf, mgr)
+
+	writeWithKey := func(key, content string) {
+		t.Helper()
+		msg := message.New([][]byte{[]byte(content)})
+		msg.Get(0).Metadata().Set("key", key)
+		require.NoError(t, w.Write(msg))
+	}
+
+	writeWithKey("a.txt", "foo")
+
+	// Simulate a hash collision by mutating the stored object.
+	m.objects["a.txt"] = mockS3Object{body: []byte("not foo")}
+
+	writeWithKey("b.txt", "foo")
+
+	require.Len(t, m.objects, 2)
+	assert.Equal(t, "foo", string(m.objects["b.txt"].body))
+	assert.Nil(t, m.objects["b.txt"].metadata[s3DedupeRefMetaKey])
+	assert.Equal(t, 1, m.gets)
+}
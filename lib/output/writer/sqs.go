@@ -41,6 +41,7 @@ type AmazonSQSConfig struct {
 	MessageDeduplicationID string          `json:"message_deduplication_id" yaml:"message_deduplication_id"`
 	Metadata               output.Metadata `json:"metadata" yaml:"metadata"`
 	MaxInFlight            int             `json:"max_in_flight" yaml:"max_in_flight"`
+	PreserveOrder          bool            `json:"preserve_order" yaml:"preserve_order"`
 	retries.Config         `json:",inline" yaml:",inline"`
 	Batching               batch.PolicyConfig `json:"batching" yaml:"batching"`
 }
@@ -61,6 +62,7 @@ func NewAmazonSQSConfig() AmazonSQSConfig {
 		MessageDeduplicationID: "",
 		Metadata:               output.NewMetadata(),
 		MaxInFlight:            1,
+		PreserveOrder:          false,
 		Config:                 rConf,
 		Batching:               batch.NewPolicyConfig(),
 	}
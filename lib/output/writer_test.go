@@ -206,10 +206,11 @@ func TestWriterClosesOnReconn(t *testing.T) {
 	t.Parallel()
 
 	writerImpl := newMockWriter()
+	stats := metrics.NewLocal()
 
 	w, err := NewWriter(
 		"foo", writerImpl,
-		log.Noop(), metrics.Noop(),
+		log.Noop(), stats,
 	)
 	if err != nil {
 		t.Error(err)
@@ -251,6 +252,10 @@ func TestWriterClosesOnReconn(t *testing.T) {
 	if err = w.WaitForClose(time.Second * 5); err != nil {
 		t.Error(err)
 	}
+
+	if act := stats.GetCounters()["batch.in_flight"]; act != 0 {
+		t.Errorf("Wrong in flight count: %v != 0", act)
+	}
 }
 
 func TestWriterClosesOnResend(t *testing.T) {
@@ -449,10 +454,11 @@ func TestWriterHappyPath(t *testing.T) {
 	expErr := error(nil)
 
 	writerImpl.resToSnd = expErr
+	stats := metrics.NewLocal()
 
 	w, err := NewWriter(
 		"foo", writerImpl,
-		log.Noop(), metrics.Noop(),
+		log.Noop(), stats,
 	)
 	if err != nil {
 		t.Error(err)
@@ -506,6 +512,9 @@ func TestWriterHappyPath(t *testing.T) {
 	if act := message.GetAllBytes(writerImpl.msgRcvd); !reflect.DeepEqual(exp, act) {
 		t.Errorf("Wrong message sent: %v != %v", act, exp)
 	}
+	if act := stats.GetTimings()["message_size"]; act != 3 {
+		t.Errorf("Wrong message size: %v != 3", act)
+	}
 }
 
 func TestWriterSadPath(t *testing.T) {
@@ -23,6 +23,8 @@ import (
 type Config struct {
 	Threads    int                `json:"threads" yaml:"threads"`
 	Processors []processor.Config `json:"processors" yaml:"processors"`
+	Lanes      []LaneConfig       `json:"lanes,omitempty" yaml:"lanes,omitempty"`
+	Init       []processor.Config `json:"init,omitempty" yaml:"init,omitempty"`
 }
 
 // NewConfig returns a configuration struct fully populated with default values.
@@ -30,6 +32,8 @@ func NewConfig() Config {
 	return Config{
 		Threads:    1,
 		Processors: []processor.Config{},
+		Lanes:      []LaneConfig{},
+		Init:       []processor.Config{},
 	}
 }
 
@@ -50,26 +54,63 @@ func (conf Config) Sanitised(removeDeprecated bool) (interface{}, error) {
 			return nil, err
 		}
 	}
-	return map[string]interface{}{
+	sanit := map[string]interface{}{
 		"threads":    conf.Threads,
 		"processors": procConfs,
-	}, nil
+	}
+	if len(conf.Lanes) > 0 {
+		laneConfs := make([]interface{}, len(conf.Lanes))
+		for i, lConf := range conf.Lanes {
+			var err error
+			if laneConfs[i], err = lConf.Sanitised(removeDeprecated); err != nil {
+				return nil, err
+			}
+		}
+		sanit["lanes"] = laneConfs
+	}
+	if len(conf.Init) > 0 {
+		initConfs := make([]interface{}, len(conf.Init))
+		for i, pConf := range conf.Init {
+			var err error
+			if initConfs[i], err = pConf.Sanitised(removeDeprecated); err != nil {
+				return nil, err
+			}
+		}
+		sanit["init"] = initConfs
+	}
+	return sanit, nil
 }
 
 //------------------------------------------------------------------------------
 
-// New creates an input type based on an input configuration.
+// New creates a pipeline based on a pipeline configuration.
 func New(
 	conf Config,
 	mgr types.Manager,
 	log log.Modular,
 	stats metrics.Type,
 	processorCtors ...types.ProcessorConstructorFunc,
+) (Type, error) {
+	if len(conf.Lanes) > 0 {
+		return newLanes(conf, mgr, log, stats, processorCtors...)
+	}
+	return newThreaded(conf.Threads, conf.Processors, mgr, log, stats, processorCtors...)
+}
+
+// newThreaded creates a pipeline that executes a list of processors across a
+// number of parallel threads.
+func newThreaded(
+	threads int,
+	procConfs []processor.Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	processorCtors ...types.ProcessorConstructorFunc,
 ) (Type, error) {
 	procs := 0
 	procCtor := func(i *int) (types.Pipeline, error) {
-		processors := make([]types.Processor, len(conf.Processors)+len(processorCtors))
-		for j, procConf := range conf.Processors {
+		processors := make([]types.Processor, len(procConfs)+len(processorCtors))
+		for j, procConf := range procConfs {
 			pMgr, pLog, pMetrics := interop.LabelChild(fmt.Sprintf("processor.%v", *i), mgr, log, stats)
 			var err error
 			processors[j], err = processor.New(procConf, pMgr, pLog, pMetrics)
@@ -80,17 +121,17 @@ func New(
 		}
 		for j, procCtor := range processorCtors {
 			var err error
-			processors[j+len(conf.Processors)], err = procCtor()
+			processors[j+len(procConfs)], err = procCtor()
 			if err != nil {
 				return nil, fmt.Errorf("failed to create processor: %v", err)
 			}
 		}
 		return NewProcessor(log, stats, processors...), nil
 	}
-	if conf.Threads == 1 {
+	if threads == 1 {
 		return procCtor(&procs)
 	}
-	return NewPool(procCtor, conf.Threads, log, stats)
+	return NewPool(procCtor, threads, log, stats)
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// RunInit executes the init processors of a pipeline configuration once on a
+// single empty message, which allows resources such as caches to be populated
+// before a stream begins consuming data. Returns an error if any of the
+// processors fail to be created or fail to process the message.
+func RunInit(conf Config, mgr types.Manager, log log.Modular, stats metrics.Type) error {
+	if len(conf.Init) == 0 {
+		return nil
+	}
+
+	procs := make([]types.Processor, len(conf.Init))
+	for i, procConf := range conf.Init {
+		pMgr, pLog, pMetrics := interop.LabelChild(fmt.Sprintf("init.%v", i), mgr, log, stats)
+		var err error
+		if procs[i], err = processor.New(procConf, pMgr, pLog, pMetrics); err != nil {
+			return fmt.Errorf("failed to create init processor '%v': %v", procConf.Type, err)
+		}
+	}
+	defer func() {
+		for _, p := range procs {
+			p.CloseAsync()
+		}
+		for _, p := range procs {
+			if err := p.WaitForClose(time.Second); err != nil {
+				log.Warnf("Failed to cleanly close init processor: %v\n", err)
+			}
+		}
+	}()
+
+	log.Infoln("Executing pipeline init processors.")
+
+	msgs, res := processor.ExecuteAll(procs, message.New([][]byte{nil}))
+	if res != nil && res.Error() != nil {
+		return fmt.Errorf("init processors failed: %w", res.Error())
+	}
+	for _, msg := range msgs {
+		var failErr error
+		_ = msg.Iter(func(i int, p types.Part) error {
+			if failErr == nil && processor.HasFailed(p) {
+				failErr = errors.New(processor.GetFail(p))
+			}
+			return nil
+		})
+		if failErr != nil {
+			return fmt.Errorf("init processors failed: %w", failErr)
+		}
+	}
+	return nil
+}
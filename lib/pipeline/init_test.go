@@ -0,0 +1,61 @@
+package pipeline_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/cache"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/pipeline"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRunInitPopulatesCache(t *testing.T) {
+	cConf := cache.NewConfig()
+	cConf.Type = cache.TypeMemory
+
+	mConf := manager.NewConfig()
+	mConf.Caches["foo"] = cConf
+
+	mgr, err := manager.New(mConf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := pipeline.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+init:
+  - bloblang: 'root = "bar"'
+  - cache:
+      resource: foo
+      operator: set
+      key: warm
+      value: ${! content() }
+`), &conf))
+
+	require.NoError(t, pipeline.RunInit(conf, mgr, log.Noop(), metrics.Noop()))
+
+	var value []byte
+	require.NoError(t, interop.AccessCache(context.Background(), mgr, "foo", func(c types.Cache) {
+		value, err = c.Get("warm")
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(value))
+}
+
+func TestRunInitFailure(t *testing.T) {
+	conf := pipeline.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+init:
+  - bloblang: 'root = throw("nope")'
+`), &conf))
+
+	err := pipeline.RunInit(conf, types.NoopMgr(), log.Noop(), metrics.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "init processors failed")
+	assert.Contains(t, err.Error(), "nope")
+}
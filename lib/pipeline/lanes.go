@@ -0,0 +1,360 @@
+package pipeline
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"gopkg.in/yaml.v3"
+)
+
+//------------------------------------------------------------------------------
+
+// LaneConfig is a configuration struct for a processing lane, which is a
+// dedicated set of processing threads for messages that pass a check.
+type LaneConfig struct {
+	Check      string             `json:"check" yaml:"check"`
+	Threads    int                `json:"threads" yaml:"threads"`
+	Processors []processor.Config `json:"processors" yaml:"processors"`
+}
+
+// NewLaneConfig returns a lane configuration struct fully populated with
+// default values.
+func NewLaneConfig() LaneConfig {
+	return LaneConfig{
+		Check:      "",
+		Threads:    1,
+		Processors: []processor.Config{},
+	}
+}
+
+// Sanitised returns a sanitised version of the config, meaning sections that
+// aren't relevant to behaviour are removed. Also optionally removes deprecated
+// fields.
+func (conf LaneConfig) Sanitised(removeDeprecated bool) (interface{}, error) {
+	procConfs := make([]interface{}, len(conf.Processors))
+	for i, pConf := range conf.Processors {
+		var err error
+		if procConfs[i], err = pConf.Sanitised(removeDeprecated); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{
+		"check":      conf.Check,
+		"threads":    conf.Threads,
+		"processors": procConfs,
+	}, nil
+}
+
+// UnmarshalYAML ensures that when parsing configs that are in a slice the
+// default values are still applied.
+func (conf *LaneConfig) UnmarshalYAML(value *yaml.Node) error {
+	type confAlias LaneConfig
+	aliased := confAlias(NewLaneConfig())
+	if err := value.Decode(&aliased); err != nil {
+		return fmt.Errorf("line %v: %v", value.Line, err)
+	}
+	*conf = LaneConfig(aliased)
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Lanes is a pipeline that routes messages into isolated processing lanes, each
+// with their own threads and processors, based on the first lane check that
+// each message passes. Messages that pass no checks are routed to a default
+// lane. The outputs of all lanes are merged into a single transaction channel.
+//
+// Each lane has its own buffered queue of transactions, which is dispatched to
+// the lane independently of the other lanes, and therefore a saturated lane
+// only blocks the routing of messages once its queue is full.
+type Lanes struct {
+	checks []*mapping.Executor
+
+	// The final lane is the default lane.
+	lanes      []types.Pipeline
+	laneQueues []chan types.Transaction
+	laneChans  []chan types.Transaction
+
+	log log.Modular
+
+	mRouted []metrics.StatCounter
+	mErr    metrics.StatCounter
+
+	messagesOut chan types.Transaction
+	pendingAcks sync.WaitGroup
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+	closed    chan struct{}
+}
+
+func newLanes(
+	conf Config,
+	mgr types.Manager,
+	log log.Modular,
+	stats metrics.Type,
+	processorCtors ...types.ProcessorConstructorFunc,
+) (*Lanes, error) {
+	l := &Lanes{
+		log:         log,
+		mErr:        stats.GetCounter("lanes.check.error"),
+		messagesOut: make(chan types.Transaction),
+		closeChan:   make(chan struct{}),
+		closed:      make(chan struct{}),
+	}
+
+	addLane := func(label string, threads int, procConfs []processor.Config) error {
+		lMgr, lLog, lStats := interop.LabelChild(label, mgr, log, stats)
+		lane, err := newThreaded(threads, procConfs, lMgr, lLog, lStats, processorCtors...)
+		if err != nil {
+			return err
+		}
+		l.lanes = append(l.lanes, lane)
+		l.laneQueues = append(l.laneQueues, make(chan types.Transaction, laneQueueSize(threads)))
+		l.laneChans = append(l.laneChans, make(chan types.Transaction))
+		l.mRouted = append(l.mRouted, stats.GetCounter(label+".routed"))
+		return nil
+	}
+
+	for i, lConf := range conf.Lanes {
+		var exe *mapping.Executor
+		if lConf.Check != "" {
+			var err error
+			if exe, err = bloblang.NewMapping("", lConf.Check); err != nil {
+				return nil, fmt.Errorf("failed to parse lane '%v' check mapping: %v", i, err)
+			}
+		}
+		l.checks = append(l.checks, exe)
+		if err := addLane(fmt.Sprintf("lane.%v", i), lConf.Threads, lConf.Processors); err != nil {
+			return nil, fmt.Errorf("failed to create lane '%v': %v", i, err)
+		}
+	}
+	if err := addLane("lane.default", conf.Threads, conf.Processors); err != nil {
+		return nil, fmt.Errorf("failed to create default lane: %v", err)
+	}
+	return l, nil
+}
+
+// laneQueueSize returns the number of transactions that can be queued for a
+// lane with a number of threads before routing is blocked.
+func laneQueueSize(threads int) int {
+	if threads <= 0 {
+		return runtime.NumCPU()
+	}
+	return threads
+}
+
+//------------------------------------------------------------------------------
+
+// route returns the index of the lane that a message part should be processed
+// within.
+func (l *Lanes) route(i int, msg types.Message) int {
+	for j, exe := range l.checks {
+		if exe == nil {
+			return j
+		}
+		test, err := exe.QueryPart(i, msg)
+		if err != nil {
+			l.mErr.Incr(1)
+			l.log.Errorf("Failed to test lane %v: %v\n", j, err)
+			continue
+		}
+		if test {
+			return j
+		}
+	}
+	return len(l.checks)
+}
+
+// dispatch queues a message split into lanes, and returns the response
+// channels of each resulting transaction, or nil if the pipeline was closed
+// before all transactions were queued.
+func (l *Lanes) dispatch(targets [][]types.Part) []chan types.Response {
+	var resChans []chan types.Response
+	for i, parts := range targets {
+		if len(parts) == 0 {
+			continue
+		}
+		msg := message.New(nil)
+		msg.SetAll(parts)
+
+		resChan := make(chan types.Response)
+		select {
+		case l.laneQueues[i] <- types.NewTransaction(msg, resChan):
+		case <-l.closeChan:
+			return nil
+		}
+		resChans = append(resChans, resChan)
+	}
+	return resChans
+}
+
+func (l *Lanes) loop(msgs <-chan types.Transaction) {
+	defer func() {
+		l.pendingAcks.Wait()
+		for _, c := range l.laneQueues {
+			close(c)
+		}
+	}()
+
+	for {
+		var tran types.Transaction
+		var open bool
+		select {
+		case tran, open = <-msgs:
+			if !open {
+				return
+			}
+		case <-l.closeChan:
+			return
+		}
+
+		targets := make([][]types.Part, len(l.lanes))
+		lanesHit := 0
+		_ = tran.Payload.Iter(func(i int, p types.Part) error {
+			j := l.route(i, tran.Payload)
+			if len(targets[j]) == 0 {
+				lanesHit++
+			}
+			targets[j] = append(targets[j], p)
+			l.mRouted[j].Incr(1)
+			return nil
+		})
+
+		// When the whole batch is routed to a single lane we forward it
+		// untouched along with the original response channel.
+		if lanesHit <= 1 {
+			j := len(l.checks)
+			for i, parts := range targets {
+				if len(parts) > 0 {
+					j = i
+				}
+			}
+			select {
+			case l.laneQueues[j] <- tran:
+			case <-l.closeChan:
+				return
+			}
+			continue
+		}
+
+		resChans := l.dispatch(targets)
+		if resChans == nil {
+			return
+		}
+
+		l.pendingAcks.Add(1)
+		go func(resChanUpstream chan<- types.Response) {
+			defer l.pendingAcks.Done()
+
+			var resErr error
+			for _, resChan := range resChans {
+				select {
+				case res := <-resChan:
+					if res.Error() != nil && resErr == nil {
+						resErr = res.Error()
+					}
+				case <-l.closeChan:
+					return
+				}
+			}
+
+			var res types.Response = response.NewAck()
+			if resErr != nil {
+				res = response.NewError(resErr)
+			}
+			select {
+			case resChanUpstream <- res:
+			case <-l.closeChan:
+			}
+		}(tran.ResponseChan)
+	}
+}
+
+// dispatchLane feeds the queued transactions of a lane to the lane until the
+// queue is closed.
+func (l *Lanes) dispatchLane(i int) {
+	defer close(l.laneChans[i])
+	for tran := range l.laneQueues[i] {
+		select {
+		case l.laneChans[i] <- tran:
+		case <-l.closeChan:
+			return
+		}
+	}
+}
+
+// Consume assigns a messages channel for the pipeline to read.
+func (l *Lanes) Consume(msgs <-chan types.Transaction) error {
+	var wg sync.WaitGroup
+	for i, lane := range l.lanes {
+		if err := lane.Consume(l.laneChans[i]); err != nil {
+			return err
+		}
+		go l.dispatchLane(i)
+
+		wg.Add(1)
+		go func(tChan <-chan types.Transaction) {
+			defer wg.Done()
+			for {
+				tran, open := <-tChan
+				if !open {
+					return
+				}
+				select {
+				case l.messagesOut <- tran:
+				case <-l.closeChan:
+					return
+				}
+			}
+		}(lane.TransactionChan())
+	}
+
+	go func() {
+		wg.Wait()
+		close(l.messagesOut)
+		close(l.closed)
+	}()
+
+	go l.loop(msgs)
+	return nil
+}
+
+// TransactionChan returns the channel used for consuming messages from this
+// pipeline.
+func (l *Lanes) TransactionChan() <-chan types.Transaction {
+	return l.messagesOut
+}
+
+// CloseAsync shuts down the pipeline and stops processing messages.
+func (l *Lanes) CloseAsync() {
+	l.closeOnce.Do(func() {
+		close(l.closeChan)
+		for _, lane := range l.lanes {
+			lane.CloseAsync()
+		}
+	})
+}
+
+// WaitForClose blocks until the pipeline has closed down.
+func (l *Lanes) WaitForClose(timeout time.Duration) error {
+	select {
+	case <-l.closed:
+	case <-time.After(timeout):
+		return types.ErrTimeout
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,194 @@
+package pipeline_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/pipeline"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func laneTestPipeline(t *testing.T) pipeline.Type {
+	t.Helper()
+
+	conf := pipeline.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+threads: 2
+processors:
+  - bloblang: 'root = "low: " + content()'
+lanes:
+  - check: 'meta("priority") == "high"'
+    processors:
+      - bloblang: 'root = "high: " + content()'
+  - check: 'meta("priority") == "medium"'
+    threads: 3
+    processors:
+      - bloblang: 'root = "medium: " + content()'
+`), &conf))
+
+	require.Len(t, conf.Lanes, 2)
+	assert.Equal(t, 1, conf.Lanes[0].Threads)
+	assert.Equal(t, 3, conf.Lanes[1].Threads)
+
+	pipe, err := pipeline.New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	return pipe
+}
+
+func laneTestMsg(contents ...string) types.Message {
+	msg := message.New(nil)
+	for _, c := range contents {
+		p := message.NewPart([]byte(c))
+		p.Metadata().Set("priority", c)
+		msg.Append(p)
+	}
+	return msg
+}
+
+func TestLanesRouting(t *testing.T) {
+	pipe := laneTestPipeline(t)
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, pipe.Consume(tChan))
+
+	sendAndAck := func(msg types.Message, errFn func(types.Message) error) ([]string, error) {
+		t.Helper()
+
+		resChan := make(chan types.Response)
+		select {
+		case tChan <- types.NewTransaction(msg, resChan):
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+
+		var contents []string
+		for {
+			select {
+			case tran := <-pipe.TransactionChan():
+				for _, b := range message.GetAllBytes(tran.Payload) {
+					contents = append(contents, string(b))
+				}
+				go func() {
+					if err := errFn(tran.Payload); err != nil {
+						tran.ResponseChan <- response.NewError(err)
+					} else {
+						tran.ResponseChan <- response.NewAck()
+					}
+				}()
+			case res := <-resChan:
+				sort.Strings(contents)
+				return contents, res.Error()
+			case <-time.After(time.Second):
+				t.Fatal("timed out")
+			}
+		}
+	}
+
+	ackAll := func(types.Message) error { return nil }
+
+	contents, err := sendAndAck(laneTestMsg("high", "low", "medium", "high", "nope"), ackAll)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"high: high", "high: high", "low: low", "low: nope", "medium: medium"}, contents)
+
+	contents, err = sendAndAck(laneTestMsg("medium", "medium"), ackAll)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"medium: medium", "medium: medium"}, contents)
+
+	contents, err = sendAndAck(laneTestMsg("high", "low"), func(msg types.Message) error {
+		if string(msg.Get(0).Get()) == "low: low" {
+			return errors.New("nope")
+		}
+		return nil
+	})
+	require.EqualError(t, err, "nope")
+	assert.Equal(t, []string{"high: high", "low: low"}, contents)
+
+	close(tChan)
+	require.NoError(t, pipe.WaitForClose(time.Second))
+}
+
+func TestLanesIndependentBackpressure(t *testing.T) {
+	conf := pipeline.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+lanes:
+  - check: 'meta("priority") == "slow"'
+    processors:
+      - sleep:
+          duration: 500ms
+`), &conf))
+
+	pipe, err := pipeline.New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	tChan := make(chan types.Transaction)
+	require.NoError(t, pipe.Consume(tChan))
+
+	resChan := make(chan types.Response)
+	send := func(msg types.Message) {
+		t.Helper()
+		select {
+		case tChan <- types.NewTransaction(msg, resChan):
+		case <-time.After(time.Millisecond * 100):
+			t.Fatal("timed out")
+		}
+	}
+
+	// The slow lane has a single thread, and is therefore saturated by the
+	// first message, whilst the following messages are queued.
+	send(laneTestMsg("slow"))
+	send(laneTestMsg("slow"))
+	send(laneTestMsg("slow"))
+	send(laneTestMsg("fast"))
+
+	select {
+	case tran := <-pipe.TransactionChan():
+		assert.Equal(t, "fast", string(tran.Payload.Get(0).Get()))
+		go func() {
+			tran.ResponseChan <- response.NewAck()
+		}()
+	case <-time.After(time.Millisecond * 250):
+		t.Fatal("timed out")
+	}
+
+	pipe.CloseAsync()
+	require.NoError(t, pipe.WaitForClose(time.Second*5))
+}
+
+func TestLanesClose(t *testing.T) {
+	pipe := laneTestPipeline(t)
+	require.NoError(t, pipe.Consume(make(chan types.Transaction)))
+
+	pipe.CloseAsync()
+	require.NoError(t, pipe.WaitForClose(time.Second))
+}
+
+func TestLanesBadCheck(t *testing.T) {
+	lConf := pipeline.NewLaneConfig()
+	lConf.Check = `this.foo ==`
+
+	conf := pipeline.NewConfig()
+	conf.Lanes = append(conf.Lanes, lConf)
+
+	_, err := pipeline.New(conf, nil, log.Noop(), metrics.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse lane '0' check mapping")
+
+	lConf.Check = `true`
+	lConf.Processors = append(lConf.Processors, processor.NewConfig())
+	lConf.Processors[0].Type = "nope"
+	conf.Lanes[0] = lConf
+
+	_, err = pipeline.New(conf, nil, log.Noop(), metrics.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create lane '0'")
+}
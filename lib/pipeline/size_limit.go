@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// NewSizeLimit returns a pipeline that rejects batches containing a message
+// larger than a maximum number of bytes. Rejected batches are not propagated
+// and instead an error is returned to their source, resulting in a nack.
+func NewSizeLimit(maxBytes int, log log.Modular, stats metrics.Type) *Processor {
+	return NewProcessor(log, stats, &sizeLimitProc{
+		maxBytes:  maxBytes,
+		log:       log,
+		mRejected: stats.GetCounter("message_size.rejected"),
+	})
+}
+
+type sizeLimitProc struct {
+	maxBytes int
+
+	log       log.Modular
+	mRejected metrics.StatCounter
+}
+
+func (s *sizeLimitProc) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	var err error
+	msg.Iter(func(i int, p types.Part) error {
+		if size := len(p.Get()); size > s.maxBytes {
+			err = fmt.Errorf("message size %v exceeds the limit of %v bytes", size, s.maxBytes)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Debugf("Rejecting batch: %v\n", err)
+		s.mRejected.Incr(1)
+		return nil, response.NewError(err)
+	}
+	return []types.Message{msg}, nil
+}
+
+func (s *sizeLimitProc) CloseAsync() {
+}
+
+func (s *sizeLimitProc) WaitForClose(timeout time.Duration) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
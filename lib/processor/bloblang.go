@@ -1,12 +1,21 @@
 package processor
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message"
@@ -16,6 +25,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/opentracing/opentracing-go"
 	olog "github.com/opentracing/opentracing-go/log"
+	"gopkg.in/yaml.v3"
 )
 
 //------------------------------------------------------------------------------
@@ -27,13 +37,27 @@ func init() {
 			CategoryMapping,
 			CategoryParsing,
 		},
-		config: docs.FieldComponent().HasType(docs.FieldTypeString).Linter(docs.LintBloblangMapping).HasDefault(""),
+		config: docs.FieldComponent().HasType(docs.FieldTypeUnknown).Linter(lintBloblangConfig).HasDefault(""),
 		Summary: `
 Executes a [Bloblang](/docs/guides/bloblang/about) mapping on messages.`,
 		Description: `
 Bloblang is a powerful language that enables a wide range of mapping, transformation and filtering tasks. For more information [check out the docs](/docs/guides/bloblang/about).
 
-If your mapping is large and you'd prefer for it to live in a separate file then you can execute a mapping directly from a file with the expression ` + "`from \"<path>\"`" + `, where the path must be absolute, or relative from the location that Benthos is executed from.`,
+If your mapping is large and you'd prefer for it to live in a separate file then you can execute a mapping directly from a file with the expression ` + "`from \"<path>\"`" + `, where the path must be absolute, or relative from the location that Benthos is executed from.
+
+### Watching Mapping Files
+
+When the mapping consists solely of a ` + "`from \"<path>\"`" + ` expression the file can be watched for changes by setting the processor config to an object with the fields ` + "`mapping`" + ` and ` + "`watch`" + `:
+
+` + "```yaml" + `
+pipeline:
+  processors:
+    - bloblang:
+        mapping: 'from "./mapping.blobl"'
+        watch: true
+` + "```" + `
+
+The file is then checked for changes once per second, and when it is modified the mapping is reparsed and swapped in without restarting the stream. Messages that are already being processed finish with the previous version of the mapping, and if the updated mapping fails to parse the error is logged and the previous version continues to be used. A single watcher is shared by all processors that watch the same file, including the processors of each pipeline thread. Files imported from within the mapping file itself are not watched.`,
 		Footnotes: `
 ## Error Handling
 
@@ -43,7 +67,15 @@ are logged, and the message is flagged as having failed, allowing you to use
 
 However, Bloblang itself also provides powerful ways of ensuring your mappings
 do not fail by specifying desired fallback behaviour, which you can read about
-[in this section](/docs/guides/bloblang/about#error-handling).`,
+[in this section](/docs/guides/bloblang/about#error-handling).
+
+Along with the standard ` + "`error`" + ` metric each failed mapping increments
+the counter ` + "`mapping.error`" + `, which has a label ` + "`category`" + `
+describing the cause of the error. The category is ` + "`type`" + ` when a value
+was of the wrong type, ` + "`context`" + ` when a context that doesn't exist
+was referenced, ` + "`arithmetic`" + ` for a division by zero, and ` + "`other`" + ` for all
+remaining errors. Since processor metrics are namespaced by the label of the
+processor this allows dashboards to show the failure rate of each mapping.`,
 		Examples: []docs.AnnotatedExample{
 			{
 				Title: "Mapping",
@@ -124,67 +156,308 @@ pipeline:
 
 //------------------------------------------------------------------------------
 
-// BloblangConfig contains configuration fields for the Bloblang processor.
-type BloblangConfig string
+// BloblangConfig contains configuration fields for the Bloblang processor. The
+// config is either a mapping string or, in order to set other fields, an object
+// containing the mapping.
+type BloblangConfig struct {
+	Mapping string `json:"mapping" yaml:"mapping"`
+	Watch   bool   `json:"watch" yaml:"watch"`
+}
 
 // NewBloblangConfig returns a BloblangConfig with default values.
 func NewBloblangConfig() BloblangConfig {
-	return ""
+	return BloblangConfig{
+		Mapping: "",
+		Watch:   false,
+	}
+}
+
+// MarshalJSON prints the mapping string when no other fields are set.
+func (b BloblangConfig) MarshalJSON() ([]byte, error) {
+	if !b.Watch {
+		return json.Marshal(b.Mapping)
+	}
+	type confAlias BloblangConfig
+	return json.Marshal(confAlias(b))
+}
+
+// MarshalYAML prints the mapping string when no other fields are set.
+func (b BloblangConfig) MarshalYAML() (interface{}, error) {
+	if !b.Watch {
+		return b.Mapping, nil
+	}
+	type confAlias BloblangConfig
+	return confAlias(b), nil
+}
+
+// UnmarshalJSON parses either a mapping string or an object.
+func (b *BloblangConfig) UnmarshalJSON(data []byte) error {
+	var mapping string
+	if err := json.Unmarshal(data, &mapping); err == nil {
+		*b = NewBloblangConfig()
+		b.Mapping = mapping
+		return nil
+	}
+	type confAlias BloblangConfig
+	aliased := confAlias(NewBloblangConfig())
+	if err := json.Unmarshal(data, &aliased); err != nil {
+		return err
+	}
+	*b = BloblangConfig(aliased)
+	return nil
+}
+
+// UnmarshalYAML parses either a mapping string or an object.
+func (b *BloblangConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*b = NewBloblangConfig()
+		return value.Decode(&b.Mapping)
+	}
+	type confAlias BloblangConfig
+	aliased := confAlias(NewBloblangConfig())
+	if err := value.Decode(&aliased); err != nil {
+		return err
+	}
+	*b = BloblangConfig(aliased)
+	return nil
+}
+
+// lintBloblangConfig lints either a mapping string or an object containing a
+// mapping.
+func lintBloblangConfig(ctx docs.LintContext, line, col int, v interface{}) []docs.Lint {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return docs.LintBloblangMapping(ctx, line, col, v)
+	}
+	var lints []docs.Lint
+	for k, v := range obj {
+		switch k {
+		case "mapping":
+			lints = append(lints, docs.LintBloblangMapping(ctx, line, col, v)...)
+		case "watch":
+			if _, ok := v.(bool); !ok {
+				lints = append(lints, docs.NewLintError(line, "expected bool value for field watch"))
+			}
+		default:
+			lints = append(lints, docs.NewLintError(line, fmt.Sprintf("field %v not recognised", k)))
+		}
+	}
+	return lints
 }
 
 //------------------------------------------------------------------------------
 
 // Bloblang is a processor that performs a Bloblang mapping.
 type Bloblang struct {
-	exec *mapping.Executor
+	exec atomic.Value
+
+	expr      string
+	unwatchFn func()
 
 	log   log.Modular
 	stats metrics.Type
 
-	mCount     metrics.StatCounter
-	mErr       metrics.StatCounter
-	mSent      metrics.StatCounter
-	mBatchSent metrics.StatCounter
-	mDropped   metrics.StatCounter
+	mCount       metrics.StatCounter
+	mErr         metrics.StatCounter
+	mErrCategory metrics.StatCounterVec
+	mSent        metrics.StatCounter
+	mBatchSent   metrics.StatCounter
+	mDropped     metrics.StatCounter
+}
+
+// bloblangFilePath returns the path of a mapping that consists solely of a
+// single root import statement, e.g. `from "./foo.blobl"`.
+func bloblangFilePath(mapping string) (string, bool) {
+	mapping = strings.TrimSpace(mapping)
+	if !strings.HasPrefix(mapping, "from") {
+		return "", false
+	}
+	rest := strings.TrimPrefix(mapping, "from")
+	if len(rest) == 0 || !strings.ContainsAny(rest[:1], " \t\r\n") {
+		return "", false
+	}
+	fpath, err := strconv.Unquote(strings.TrimSpace(rest))
+	if err != nil {
+		return "", false
+	}
+	return fpath, true
 }
 
 // NewBloblang returns a Bloblang processor.
 func NewBloblang(
 	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
 ) (Type, error) {
-	exec, err := bloblang.NewMapping("", string(conf.Bloblang))
+	expr := conf.Bloblang.Mapping
+
+	var fpath string
+	if conf.Bloblang.Watch {
+		var isFile bool
+		if fpath, isFile = bloblangFilePath(expr); !isFile {
+			return nil, errors.New("watch can only be enabled when the mapping consists solely of a from statement")
+		}
+	}
+
+	exec, err := bloblang.NewMapping("", expr)
 	if err != nil {
 		if perr, ok := err.(*parser.Error); ok {
-			return nil, fmt.Errorf("%v", perr.ErrorAtPosition([]rune(conf.Bloblang)))
+			return nil, fmt.Errorf("%v", perr.ErrorAtPosition([]rune(expr)))
 		}
 		return nil, err
 	}
-	return NewBloblangFromExecutor(exec, log, stats), nil
+	b := newBloblang(exec, log, stats)
+	if conf.Bloblang.Watch {
+		b.expr = expr
+		b.unwatchFn = watchBloblangFile(fpath, b)
+	}
+	return b, nil
 }
 
 // NewBloblangFromExecutor returns a Bloblang processor.
 func NewBloblangFromExecutor(exec *mapping.Executor, log log.Modular, stats metrics.Type) Type {
-	return &Bloblang{
-		exec: exec,
+	return newBloblang(exec, log, stats)
+}
 
+func newBloblang(exec *mapping.Executor, log log.Modular, stats metrics.Type) *Bloblang {
+	b := &Bloblang{
 		log:   log,
 		stats: stats,
 
-		mCount:     stats.GetCounter("count"),
-		mErr:       stats.GetCounter("error"),
-		mSent:      stats.GetCounter("sent"),
-		mBatchSent: stats.GetCounter("batch.sent"),
-		mDropped:   stats.GetCounter("dropped"),
+		mCount:       stats.GetCounter("count"),
+		mErr:         stats.GetCounter("error"),
+		mErrCategory: stats.GetCounterVec("mapping.error", []string{"category"}),
+		mSent:        stats.GetCounter("sent"),
+		mBatchSent:   stats.GetCounter("batch.sent"),
+		mDropped:     stats.GetCounter("dropped"),
+	}
+	b.exec.Store(exec)
+	return b
+}
+
+// reload parses the mapping again and swaps to the new executor, the previous
+// executor continues to be used when parsing fails.
+func (b *Bloblang) reload(fpath string) {
+	exec, err := bloblang.NewMapping("", b.expr)
+	if err != nil {
+		if perr, ok := err.(*parser.Error); ok {
+			err = fmt.Errorf("%v", perr.ErrorAtPosition([]rune(b.expr)))
+		}
+		b.log.Errorf("Failed to reload mapping file '%v', continuing with the previous mapping: %v\n", fpath, err)
+		return
+	}
+	b.exec.Store(exec)
+	b.log.Infof("Reloaded mapping file '%v'\n", fpath)
+}
+
+//------------------------------------------------------------------------------
+
+// bloblangWatchPeriod is the period between checks for changes to a watched
+// mapping file.
+var bloblangWatchPeriod = time.Second
+
+// bloblangFileWatcher polls the contents of a mapping file and reloads the
+// mappings of all processors watching the file each time it changes. A single
+// watcher is shared by all processors, and processor threads, that watch the
+// same file.
+type bloblangFileWatcher struct {
+	fpath string
+
+	procs        map[*Bloblang]struct{}
+	lastContents []byte
+
+	closeChan chan struct{}
+}
+
+var (
+	bloblangWatchersMut sync.Mutex
+	bloblangWatchers    = map[string]*bloblangFileWatcher{}
+)
+
+// watchBloblangFile registers a processor with the watcher of a mapping file,
+// creating the watcher if it does not yet exist, and returns a func that
+// unregisters it. The watcher is stopped once no processors are registered.
+func watchBloblangFile(fpath string, b *Bloblang) func() {
+	bloblangWatchersMut.Lock()
+	defer bloblangWatchersMut.Unlock()
+
+	w, exists := bloblangWatchers[fpath]
+	if !exists {
+		// The processor has only just parsed the mapping, and therefore the
+		// current contents are those of the mapping it is using.
+		contents, _ := ioutil.ReadFile(fpath)
+		w = &bloblangFileWatcher{
+			fpath:        fpath,
+			procs:        map[*Bloblang]struct{}{},
+			lastContents: contents,
+			closeChan:    make(chan struct{}),
+		}
+		bloblangWatchers[fpath] = w
+		go w.loop()
+	}
+	w.procs[b] = struct{}{}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			bloblangWatchersMut.Lock()
+			defer bloblangWatchersMut.Unlock()
+
+			delete(w.procs, b)
+			if len(w.procs) == 0 {
+				delete(bloblangWatchers, fpath)
+				close(w.closeChan)
+			}
+		})
+	}
+}
+
+func (w *bloblangFileWatcher) loop() {
+	for {
+		select {
+		case <-time.After(bloblangWatchPeriod):
+		case <-w.closeChan:
+			return
+		}
+
+		contents, err := ioutil.ReadFile(w.fpath)
+		if err != nil {
+			continue
+		}
+
+		bloblangWatchersMut.Lock()
+		if !bytes.Equal(contents, w.lastContents) {
+			w.lastContents = contents
+			for b := range w.procs {
+				b.reload(w.fpath)
+			}
+		}
+		bloblangWatchersMut.Unlock()
 	}
 }
 
 //------------------------------------------------------------------------------
 
+// bloblangErrorCategory returns a broad category describing the cause of a
+// mapping error, which is used as a metric label.
+func bloblangErrorCategory(err error) string {
+	var tErr *query.TypeError
+	var mErr *query.TypeMismatch
+	switch {
+	case errors.As(err, &tErr), errors.As(err, &mErr):
+		return "type"
+	case errors.Is(err, query.ErrNoContext):
+		return "context"
+	case errors.Is(err, query.ErrDivideByZero):
+		return "arithmetic"
+	}
+	return "other"
+}
+
 // ProcessMessage applies the processor to a message, either creating >0
 // resulting messages or a response to be sent back to the message source.
 func (b *Bloblang) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
 	b.mCount.Incr(1)
 
+	exec := b.exec.Load().(*mapping.Executor)
 	newParts := make([]types.Part, 0, msg.Len())
 
 	msg.Iter(func(i int, part types.Part) error {
@@ -198,10 +471,11 @@ func (b *Bloblang) ProcessMessage(msg types.Message) ([]types.Message, types.Res
 			)
 		}
 
-		p, err := b.exec.MapPart(i, msg)
+		p, err := exec.MapPart(i, msg)
 		if err != nil {
 			p = part.Copy()
 			b.mErr.Incr(1)
+			b.mErrCategory.With(bloblangErrorCategory(err)).Incr(1)
 			b.log.Errorf("%v\n", err)
 			FlagErr(p, err)
 			span.SetTag("error", true)
@@ -234,6 +508,9 @@ func (b *Bloblang) ProcessMessage(msg types.Message) ([]types.Message, types.Res
 
 // CloseAsync shuts down the processor and stops processing requests.
 func (b *Bloblang) CloseAsync() {
+	if b.unwatchFn != nil {
+		b.unwatchFn()
+	}
 }
 
 // WaitForClose blocks until the processor has closed down.
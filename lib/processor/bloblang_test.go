@@ -2,8 +2,14 @@ package processor
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
@@ -12,6 +18,7 @@ import (
 	"github.com/Jeffail/gabs/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestBloblangCrossfire(t *testing.T) {
@@ -31,7 +38,7 @@ func TestBloblangCrossfire(t *testing.T) {
 	}
 
 	conf := NewConfig()
-	conf.Bloblang = `
+	conf.Bloblang.Mapping = `
 	foo = json("foo").from(0)
 	foo.bar_new = "this is swapped now"
 	foo.bar.baz = "and this changed"
@@ -89,7 +96,7 @@ func TestBloblangContext(t *testing.T) {
 	msg.Append(message.WithContext(context.WithValue(context.Background(), key, val), part))
 
 	conf := NewConfig()
-	conf.Bloblang = `result = foo.bar.baz.uppercase()`
+	conf.Bloblang.Mapping = `result = foo.bar.baz.uppercase()`
 	proc, err := NewBloblang(conf, nil, log.Noop(), metrics.Noop())
 	if err != nil {
 		t.Fatal(err)
@@ -125,7 +132,7 @@ func TestBloblangCustomObject(t *testing.T) {
 	msg.Append(part)
 
 	conf := NewConfig()
-	conf.Bloblang = `root.foos = this.foos`
+	conf.Bloblang.Mapping = `root.foos = this.foos`
 	proc, err := NewBloblang(conf, nil, log.Noop(), metrics.Noop())
 	require.NoError(t, err)
 
@@ -147,7 +154,7 @@ func TestBloblangFiltering(t *testing.T) {
 	})
 
 	conf := NewConfig()
-	conf.Bloblang = `
+	conf.Bloblang.Mapping = `
 	root = match {
 		(foo | bar).delete.or(false) => deleted(),
 	}
@@ -176,7 +183,7 @@ func TestBloblangFilterAll(t *testing.T) {
 	})
 
 	conf := NewConfig()
-	conf.Bloblang = `root = deleted()`
+	conf.Bloblang.Mapping = `root = deleted()`
 	proc, err := NewBloblang(conf, nil, log.Noop(), metrics.Noop())
 	if err != nil {
 		t.Fatal(err)
@@ -193,7 +200,7 @@ func TestBloblangJSONError(t *testing.T) {
 	})
 
 	conf := NewConfig()
-	conf.Bloblang = `
+	conf.Bloblang.Mapping = `
 	foo = json().bar
 `
 	proc, err := NewBloblang(conf, nil, log.Noop(), metrics.Noop())
@@ -210,3 +217,238 @@ func TestBloblangJSONError(t *testing.T) {
 	assert.Equal(t, `this is not valid json`, string(resPart.Get()))
 	assert.Equal(t, `failed assignment (line 2): invalid character 'h' in literal true (expecting 'r')`, resPart.Metadata().Get(types.FailFlagKey))
 }
+
+func TestBloblangErrorCategory(t *testing.T) {
+	tests := map[string]struct {
+		mapping  string
+		input    string
+		category string
+	}{
+		"type error": {
+			mapping:  `root = this.foo.uppercase()`,
+			input:    `{"foo":5}`,
+			category: "type",
+		},
+		"type mismatch": {
+			mapping:  `root = this.foo + "bar"`,
+			input:    `{"foo":5}`,
+			category: "type",
+		},
+		"unstructured message": {
+			mapping:  `root = this`,
+			input:    `not structured`,
+			category: "other",
+		},
+		"divide by zero": {
+			mapping:  `root = this.foo / 0`,
+			input:    `{"foo":5}`,
+			category: "arithmetic",
+		},
+		"thrown": {
+			mapping:  `root = throw("nope")`,
+			input:    `{}`,
+			category: "other",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			exec, err := bloblang.NewMapping("", test.mapping)
+			require.NoError(t, err)
+
+			_, err = exec.MapPart(0, message.New([][]byte{[]byte(test.input)}))
+			require.Error(t, err)
+			assert.Equal(t, test.category, bloblangErrorCategory(err))
+		})
+	}
+}
+
+func TestBloblangErrorMetrics(t *testing.T) {
+	conf := NewConfig()
+	conf.Bloblang.Mapping = `root = this.foo.uppercase()`
+
+	stats := metrics.NewLocal()
+	proc, err := NewBloblang(conf, nil, log.Noop(), stats)
+	require.NoError(t, err)
+
+	_, res := proc.ProcessMessage(message.New([][]byte{
+		[]byte(`{"foo":"bar"}`),
+		[]byte(`{"foo":5}`),
+		[]byte(`{"foo":6}`),
+	}))
+	require.Nil(t, res)
+
+	assert.Equal(t, int64(2), stats.GetCounters()["mapping.error"])
+	assert.Equal(t, int64(2), stats.GetCounters()["error"])
+	errStat := stats.GetCountersWithLabels()["mapping.error"]
+	assert.True(t, errStat.HasLabelWithValue("category", "type"))
+}
+
+func TestBloblangFileReload(t *testing.T) {
+	defer func(period time.Duration) {
+		bloblangWatchPeriod = period
+	}(bloblangWatchPeriod)
+	bloblangWatchPeriod = time.Millisecond * 10
+
+	fpath := filepath.Join(t.TempDir(), "mapping.blobl")
+	writeMapping := func(mapping string) {
+		t.Helper()
+		require.NoError(t, ioutil.WriteFile(fpath, []byte(mapping), 0644))
+	}
+
+	writeMapping(`root = "foo"`)
+
+	conf := NewConfig()
+	conf.Bloblang.Mapping = fmt.Sprintf("from %q", fpath)
+	conf.Bloblang.Watch = true
+
+	procs := make([]Type, 2)
+	for i := range procs {
+		var err error
+		procs[i], err = NewBloblang(conf, nil, log.Noop(), metrics.Noop())
+		require.NoError(t, err)
+	}
+
+	bloblangWatchersMut.Lock()
+	assert.Len(t, bloblangWatchers, 1)
+	bloblangWatchersMut.Unlock()
+
+	getResult := func(proc Type) string {
+		outMsgs, res := proc.ProcessMessage(message.New([][]byte{[]byte(`{}`)}))
+		require.Nil(t, res)
+		require.Len(t, outMsgs, 1)
+		return string(outMsgs[0].Get(0).Get())
+	}
+	for _, proc := range procs {
+		assert.Equal(t, "foo", getResult(proc))
+	}
+
+	writeMapping(`root = "bar"`)
+	for _, proc := range procs {
+		proc := proc
+		assert.Eventually(t, func() bool {
+			return getResult(proc) == "bar"
+		}, time.Second*5, time.Millisecond*10)
+	}
+
+	writeMapping(`root = ^^^ not valid`)
+	<-time.After(bloblangWatchPeriod * 5)
+	for _, proc := range procs {
+		assert.Equal(t, "bar", getResult(proc))
+	}
+
+	for _, proc := range procs {
+		proc.CloseAsync()
+		require.NoError(t, proc.WaitForClose(time.Second))
+	}
+
+	bloblangWatchersMut.Lock()
+	assert.Len(t, bloblangWatchers, 0)
+	bloblangWatchersMut.Unlock()
+}
+
+func TestBloblangFileNoWatch(t *testing.T) {
+	defer func(period time.Duration) {
+		bloblangWatchPeriod = period
+	}(bloblangWatchPeriod)
+	bloblangWatchPeriod = time.Millisecond * 10
+
+	fpath := filepath.Join(t.TempDir(), "mapping.blobl")
+	require.NoError(t, ioutil.WriteFile(fpath, []byte(`root = "foo"`), 0644))
+
+	conf := NewConfig()
+	conf.Bloblang.Mapping = fmt.Sprintf("from %q", fpath)
+	proc, err := NewBloblang(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	bloblangWatchersMut.Lock()
+	assert.Len(t, bloblangWatchers, 0)
+	bloblangWatchersMut.Unlock()
+
+	require.NoError(t, ioutil.WriteFile(fpath, []byte(`root = "bar"`), 0644))
+	<-time.After(bloblangWatchPeriod * 5)
+
+	outMsgs, res := proc.ProcessMessage(message.New([][]byte{[]byte(`{}`)}))
+	require.Nil(t, res)
+	require.Len(t, outMsgs, 1)
+	assert.Equal(t, "foo", string(outMsgs[0].Get(0).Get()))
+}
+
+func TestBloblangWatchNotFile(t *testing.T) {
+	conf := NewConfig()
+	conf.Bloblang.Mapping = `root = "foo"`
+	conf.Bloblang.Watch = true
+
+	_, err := NewBloblang(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "watch can only be enabled when the mapping consists solely of a from statement")
+}
+
+func TestBloblangConfigFormats(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		expected BloblangConfig
+		output   string
+	}{
+		"string": {
+			input:    `bloblang: root = "foo"`,
+			expected: BloblangConfig{Mapping: `root = "foo"`},
+			output:   "bloblang: root = \"foo\"\n",
+		},
+		"object without watch": {
+			input:    "bloblang:\n  mapping: root = \"foo\"\n",
+			expected: BloblangConfig{Mapping: `root = "foo"`},
+			output:   "bloblang: root = \"foo\"\n",
+		},
+		"object with watch": {
+			input:    "bloblang:\n  mapping: from \"./foo.blobl\"\n  watch: true\n",
+			expected: BloblangConfig{Mapping: `from "./foo.blobl"`, Watch: true},
+			output:   "bloblang:\n    mapping: from \"./foo.blobl\"\n    watch: true\n",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var conf struct {
+				Bloblang BloblangConfig `json:"bloblang" yaml:"bloblang"`
+			}
+			require.NoError(t, yaml.Unmarshal([]byte(test.input), &conf))
+			assert.Equal(t, test.expected, conf.Bloblang)
+
+			out, err := yaml.Marshal(conf)
+			require.NoError(t, err)
+			assert.Equal(t, test.output, string(out))
+
+			jBytes, err := json.Marshal(conf)
+			require.NoError(t, err)
+
+			conf.Bloblang = NewBloblangConfig()
+			require.NoError(t, json.Unmarshal(jBytes, &conf))
+			assert.Equal(t, test.expected, conf.Bloblang)
+		})
+	}
+}
+
+func TestBloblangFilePath(t *testing.T) {
+	tests := map[string]struct {
+		mapping string
+		path    string
+		ok      bool
+	}{
+		"basic":          {mapping: `from "./foo.blobl"`, path: "./foo.blobl", ok: true},
+		"whitespace":     {mapping: "\n  from\t\"./foo bar.blobl\"  \n", path: "./foo bar.blobl", ok: true},
+		"not an import":  {mapping: `root = "from"`},
+		"no whitespace":  {mapping: `from"./foo.blobl"`},
+		"extra mappings": {mapping: "from \"./foo.blobl\"\nroot = this"},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			path, ok := bloblangFilePath(test.mapping)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.path, path)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Jeffail/benthos/v3/internal/docs"
@@ -21,11 +22,35 @@ func init() {
 		},
 		Summary: `
 Removes messages (and batches) that do not fit within certain size boundaries.`,
+		Description: `
+When ` + "`reject`" + ` is set to ` + "`true`" + ` a batch that does not fit
+within the boundaries is rejected rather than dropped, meaning it is nacked and
+the error propagated back to the source of the messages. Placing this processor
+within the ` + "`processors`" + ` of an input therefore prevents oversized
+messages from entering the pipeline, and placing it within the
+` + "`processors`" + ` of an output prevents them from being sent.`,
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Reject Oversized Messages",
+				Summary: "Messages larger than 1MB are rejected at the input level before they reach the pipeline.",
+				Config: `
+input:
+  http_server:
+    path: /post
+  processors:
+    - bounds_check:
+        max_part_size: 1048576
+        min_part_size: 0
+        reject: true
+`,
+			},
+		},
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon("max_part_size", "The maximum size of a message to allow (in bytes)"),
 			docs.FieldCommon("min_part_size", "The minimum size of a message to allow (in bytes)"),
 			docs.FieldAdvanced("max_parts", "The maximum size of message batches to allow (in message count)"),
 			docs.FieldAdvanced("min_parts", "The minimum size of message batches to allow (in message count)"),
+			docs.FieldAdvanced("reject", "Whether batches that do not fit within the boundaries should be rejected with an error rather than dropped.").AtVersion("3.51.0"),
 		},
 	}
 }
@@ -35,10 +60,11 @@ Removes messages (and batches) that do not fit within certain size boundaries.`,
 // BoundsCheckConfig contains configuration fields for the BoundsCheck
 // processor.
 type BoundsCheckConfig struct {
-	MaxParts    int `json:"max_parts" yaml:"max_parts"`
-	MinParts    int `json:"min_parts" yaml:"min_parts"`
-	MaxPartSize int `json:"max_part_size" yaml:"max_part_size"`
-	MinPartSize int `json:"min_part_size" yaml:"min_part_size"`
+	MaxParts    int  `json:"max_parts" yaml:"max_parts"`
+	MinParts    int  `json:"min_parts" yaml:"min_parts"`
+	MaxPartSize int  `json:"max_part_size" yaml:"max_part_size"`
+	MinPartSize int  `json:"min_part_size" yaml:"min_part_size"`
+	Reject      bool `json:"reject" yaml:"reject"`
 }
 
 // NewBoundsCheckConfig returns a BoundsCheckConfig with default values.
@@ -48,6 +74,7 @@ func NewBoundsCheckConfig() BoundsCheckConfig {
 		MinParts:    1,
 		MaxPartSize: 1 * 1024 * 1024 * 1024, // 1GB
 		MinPartSize: 1,
+		Reject:      false,
 	}
 }
 
@@ -65,6 +92,7 @@ type BoundsCheck struct {
 	mDroppedEmpty    metrics.StatCounter
 	mDroppedNumParts metrics.StatCounter
 	mDroppedPartSize metrics.StatCounter
+	mRejected        metrics.StatCounter
 	mSent            metrics.StatCounter
 	mBatchSent       metrics.StatCounter
 }
@@ -83,6 +111,7 @@ func NewBoundsCheck(
 		mDroppedEmpty:    stats.GetCounter("dropped_empty"),
 		mDroppedNumParts: stats.GetCounter("dropped_num_parts"),
 		mDroppedPartSize: stats.GetCounter("dropped_part_size"),
+		mRejected:        stats.GetCounter("rejected"),
 		mSent:            stats.GetCounter("sent"),
 		mBatchSent:       stats.GetCounter("batch.sent"),
 	}, nil
@@ -101,20 +130,18 @@ func (m *BoundsCheck) ProcessMessage(msg types.Message) ([]types.Message, types.
 			"Rejecting message due to message parts below minimum (%v): %v\n",
 			m.conf.BoundsCheck.MinParts, lParts,
 		)
-		m.mDropped.Incr(1)
 		m.mDroppedEmpty.Incr(1)
-		return nil, response.NewAck()
+		return m.drop(fmt.Errorf("message batch size below minimum (%v): %v", m.conf.BoundsCheck.MinParts, lParts))
 	} else if lParts > m.conf.BoundsCheck.MaxParts {
 		m.log.Debugf(
 			"Rejecting message due to message parts exceeding limit (%v): %v\n",
 			m.conf.BoundsCheck.MaxParts, lParts,
 		)
-		m.mDropped.Incr(1)
 		m.mDroppedNumParts.Incr(1)
-		return nil, response.NewAck()
+		return m.drop(fmt.Errorf("message batch size exceeds limit (%v): %v", m.conf.BoundsCheck.MaxParts, lParts))
 	}
 
-	var reject bool
+	var rejectErr error
 	msg.Iter(func(i int, p types.Part) error {
 		if size := len(p.Get()); size > m.conf.BoundsCheck.MaxPartSize ||
 			size < m.conf.BoundsCheck.MinPartSize {
@@ -124,16 +151,20 @@ func (m *BoundsCheck) ProcessMessage(msg types.Message) ([]types.Message, types.
 				m.conf.BoundsCheck.MaxPartSize,
 				size,
 			)
-			reject = true
+			rejectErr = fmt.Errorf(
+				"message part size outside of bounds (%v -> %v): %v",
+				m.conf.BoundsCheck.MinPartSize,
+				m.conf.BoundsCheck.MaxPartSize,
+				size,
+			)
 			return errors.New("exit")
 		}
 		return nil
 	})
 
-	if reject {
-		m.mDropped.Incr(1)
+	if rejectErr != nil {
 		m.mDroppedPartSize.Incr(1)
-		return nil, response.NewAck()
+		return m.drop(rejectErr)
 	}
 
 	m.mBatchSent.Incr(1)
@@ -142,6 +173,17 @@ func (m *BoundsCheck) ProcessMessage(msg types.Message) ([]types.Message, types.
 	return msgs[:], nil
 }
 
+// drop removes a batch that is out of bounds, either by acknowledging it or, if
+// configured to do so, by rejecting it with an error.
+func (m *BoundsCheck) drop(err error) ([]types.Message, types.Response) {
+	if m.conf.BoundsCheck.Reject {
+		m.mRejected.Incr(1)
+		return nil, response.NewError(err)
+	}
+	m.mDropped.Incr(1)
+	return nil, response.NewAck()
+}
+
 // CloseAsync shuts down the processor and stops processing requests.
 func (m *BoundsCheck) CloseAsync() {
 }
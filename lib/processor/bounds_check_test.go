@@ -85,3 +85,55 @@ func TestBoundsCheck(t *testing.T) {
 		}
 	}
 }
+
+func TestBoundsCheckReject(t *testing.T) {
+	conf := NewConfig()
+	conf.BoundsCheck.MinParts = 1
+	conf.BoundsCheck.MaxParts = 2
+	conf.BoundsCheck.MaxPartSize = 10
+	conf.BoundsCheck.MinPartSize = 1
+	conf.BoundsCheck.Reject = true
+
+	proc, err := NewBoundsCheck(conf, nil, log.Noop(), metrics.Noop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		parts  [][]byte
+		errStr string
+	}{
+		{
+			parts:  [][]byte{[]byte("hello world")},
+			errStr: "message part size outside of bounds (1 -> 10): 11",
+		},
+		{
+			parts:  [][]byte{[]byte("a"), []byte("b"), []byte("c")},
+			errStr: "message batch size exceeds limit (2): 3",
+		},
+		{
+			parts:  [][]byte{},
+			errStr: "message batch size below minimum (1): 0",
+		},
+	}
+
+	for _, test := range tests {
+		msgs, res := proc.ProcessMessage(message.New(test.parts))
+		if len(msgs) > 0 {
+			t.Errorf("Bounds check didnt fail on: %s", test.parts)
+		}
+		if res == nil || res.Error() == nil {
+			t.Errorf("Expected error response from bad message: %s", test.parts)
+		} else if exp, act := test.errStr, res.Error().Error(); exp != act {
+			t.Errorf("Wrong error: %v != %v", act, exp)
+		}
+	}
+
+	msgs, res := proc.ProcessMessage(message.New([][]byte{[]byte("hello")}))
+	if len(msgs) != 1 {
+		t.Error("Expected message to pass bounds check")
+	}
+	if res != nil {
+		t.Errorf("Unexpected response: %v", res)
+	}
+}
@@ -272,7 +272,7 @@ func TestBranchBasic(t *testing.T) {
 
 			procConf := NewConfig()
 			procConf.Type = TypeBloblang
-			procConf.Bloblang = BloblangConfig(test.processorMap)
+			procConf.Bloblang.Mapping = test.processorMap
 
 			conf := NewConfig()
 			conf.Type = TypeBranch
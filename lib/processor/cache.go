@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
@@ -27,17 +28,31 @@ func init() {
 		Summary: `
 Performs operations against a [cache resource](/docs/components/caches/about) for each message, allowing you to store or retrieve data within message payloads.`,
 		Description: `
-This processor will interpolate functions within the ` + "`key` and `value`" + ` fields individually for each message. This allows you to specify dynamic keys and values based on the contents of the message payloads and metadata. You can find a list of functions [here](/docs/configuration/interpolation#bloblang-queries).`,
+This processor will interpolate functions within the ` + "`key`, `value` and `old_value`" + ` fields individually for each message. This allows you to specify dynamic keys and values based on the contents of the message payloads and metadata. You can find a list of functions [here](/docs/configuration/interpolation#bloblang-queries).`,
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon("resource", "The [`cache` resource](/docs/components/caches/about) to target with this processor."),
-			docs.FieldDeprecated("cache"),
-			docs.FieldCommon("operator", "The [operation](#operators) to perform with the cache.").HasOptions("set", "add", "get", "delete"),
+			docs.FieldDeprecated("cache").ReplacedBy("resource"),
+			docs.FieldCommon("operator", "The [operation](#operators) to perform with the cache.").HasOptions("set", "add", "get", "delete", "compare_and_swap"),
 			docs.FieldCommon("key", "A key to use with the cache.").IsInterpolated(),
 			docs.FieldCommon("value", "A value to use with the cache (when applicable).").IsInterpolated(),
+			docs.FieldAdvanced("old_value", "The value that a key is expected to currently hold when using the `compare_and_swap` operator. When empty the key is expected to not exist.").IsInterpolated().AtVersion("3.51.0"),
 			docs.FieldAdvanced(
 				"ttl", "The TTL of each individual item as a duration string. After this period an item will be eligible for removal during the next compaction. Not all caches support per-key TTLs, and those that do not will fall back to their generally configured TTL setting.",
 				"60s", "5m", "36h",
 			).IsInterpolated().AtVersion("3.33.0"),
+			docs.FieldBool("refresh_ttl", "Whether the `get` operator should reset the expiry of keys that are found, resulting in a sliding expiration where keys expire only after a period without being read. When a `ttl` is set it is used as the new TTL of the key, otherwise the TTL of the cache is used.").HasDefault(false).Advanced().AtVersion("3.51.0").LinterWithParent(func(ctx docs.LintContext, line, col int, value, parent interface{}) []docs.Lint {
+				if refresh, _ := value.(bool); !refresh {
+					return nil
+				}
+				if pMap, ok := parent.(map[string]interface{}); ok {
+					if op, _ := pMap["operator"].(string); op != "get" {
+						return []docs.Lint{
+							docs.NewTypedLintWarning(line, docs.LintShouldOmit, "field refresh_ttl has no effect unless the operator is get"),
+						}
+					}
+				}
+				return nil
+			}),
 			PartsFieldSpec,
 		},
 		Examples: []docs.AnnotatedExample{
@@ -111,10 +126,31 @@ Retrieve the contents of a cached key and replace the original message payload
 with the result. If the key does not exist the action fails with an error, which
 can be detected with [processor error handling](/docs/configuration/error_handling).
 
+When ` + "`refresh_ttl`" + ` is ` + "`true`" + ` the expiry of a key that is
+found is reset, which keeps frequently read keys such as active sessions alive
+whilst idle keys expire. Only the ` + "`memory`, `memcached` and `redis`" + `
+caches currently support refreshing TTLs, other caches log a warning and the
+expiry of their keys is left unchanged.
+
 ### ` + "`delete`" + `
 
 Delete a key and its contents from the cache.  If the key does not exist the
-action is a no-op and will not fail with an error.`,
+action is a no-op and will not fail with an error.
+
+### ` + "`compare_and_swap`" + `
+
+Atomically set a key in the cache to a value only if it currently holds the
+value of the field ` + "`old_value`" + `, or if it does not exist when
+` + "`old_value`" + ` is empty. If the current value does not match the action
+fails with an error, which can be detected with
+[processor error handling](/docs/configuration/error_handling). This can be used
+to implement optimistic concurrency or coordinate distributed locks across
+multiple instances of Benthos sharing a cache. The ` + "`ttl`" + ` field is not
+used with this operator.
+
+Only the ` + "`memcached`, `memory`, `nats_kv`" + ` and ` + "`redis`" + ` caches
+currently support this operator, other caches fail with an 'operation not
+supported' error.`,
 	}
 }
 
@@ -122,25 +158,29 @@ action is a no-op and will not fail with an error.`,
 
 // CacheConfig contains configuration fields for the Cache processor.
 type CacheConfig struct {
-	Cache    string `json:"cache" yaml:"cache"`
-	Resource string `json:"resource" yaml:"resource"`
-	Parts    []int  `json:"parts" yaml:"parts"`
-	Operator string `json:"operator" yaml:"operator"`
-	Key      string `json:"key" yaml:"key"`
-	Value    string `json:"value" yaml:"value"`
-	TTL      string `json:"ttl" yaml:"ttl"`
+	Cache      string `json:"cache" yaml:"cache"`
+	Resource   string `json:"resource" yaml:"resource"`
+	Parts      []int  `json:"parts" yaml:"parts"`
+	Operator   string `json:"operator" yaml:"operator"`
+	Key        string `json:"key" yaml:"key"`
+	Value      string `json:"value" yaml:"value"`
+	OldValue   string `json:"old_value" yaml:"old_value"`
+	TTL        string `json:"ttl" yaml:"ttl"`
+	RefreshTTL bool   `json:"refresh_ttl" yaml:"refresh_ttl"`
 }
 
 // NewCacheConfig returns a CacheConfig with default values.
 func NewCacheConfig() CacheConfig {
 	return CacheConfig{
-		Cache:    "",
-		Resource: "",
-		Parts:    []int{},
-		Operator: "set",
-		Key:      "",
-		Value:    "",
-		TTL:      "",
+		Cache:      "",
+		Resource:   "",
+		Parts:      []int{},
+		Operator:   "set",
+		Key:        "",
+		Value:      "",
+		OldValue:   "",
+		TTL:        "",
+		RefreshTTL: false,
 	}
 }
 
@@ -155,9 +195,10 @@ type Cache struct {
 
 	parts []int
 
-	key   *field.Expression
-	value *field.Expression
-	ttl   *field.Expression
+	key      *field.Expression
+	value    *field.Expression
+	oldValue *field.Expression
+	ttl      *field.Expression
 
 	mgr       types.Manager
 	cacheName string
@@ -166,6 +207,7 @@ type Cache struct {
 	mCount            metrics.StatCounter
 	mErr              metrics.StatCounter
 	mKeyAlreadyExists metrics.StatCounter
+	mValueMismatch    metrics.StatCounter
 	mSent             metrics.StatCounter
 	mBatchSent        metrics.StatCounter
 }
@@ -182,7 +224,7 @@ func NewCache(
 		return nil, errors.New("cache name must be specified")
 	}
 
-	op, err := cacheOperatorFromString(conf.Cache.Operator)
+	op, err := cacheOperatorFromString(conf.Cache.Operator, conf.Cache.RefreshTTL, log)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +239,11 @@ func NewCache(
 		return nil, fmt.Errorf("failed to parse value expression: %v", err)
 	}
 
+	oldValue, err := bloblang.NewField(conf.Cache.OldValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse old_value expression: %v", err)
+	}
+
 	ttl, err := bloblang.NewField(conf.Cache.TTL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ttl expression: %v", err)
@@ -213,9 +260,10 @@ func NewCache(
 
 		parts: conf.Cache.Parts,
 
-		key:   key,
-		value: value,
-		ttl:   ttl,
+		key:      key,
+		value:    value,
+		oldValue: oldValue,
+		ttl:      ttl,
 
 		mgr:       mgr,
 		cacheName: cacheName,
@@ -224,6 +272,7 @@ func NewCache(
 		mCount:            stats.GetCounter("count"),
 		mErr:              stats.GetCounter("error"),
 		mKeyAlreadyExists: stats.GetCounter("key_already_exists"),
+		mValueMismatch:    stats.GetCounter("value_mismatch"),
 		mSent:             stats.GetCounter("sent"),
 		mBatchSent:        stats.GetCounter("batch.sent"),
 	}, nil
@@ -231,10 +280,10 @@ func NewCache(
 
 //------------------------------------------------------------------------------
 
-type cacheOperator func(cache types.Cache, key string, value []byte, ttl *time.Duration) ([]byte, bool, error)
+type cacheOperator func(cache types.Cache, key string, value, oldValue []byte, ttl *time.Duration) ([]byte, bool, error)
 
 func newCacheSetOperator() cacheOperator {
-	return func(cache types.Cache, key string, value []byte, ttl *time.Duration) ([]byte, bool, error) {
+	return func(cache types.Cache, key string, value, _ []byte, ttl *time.Duration) ([]byte, bool, error) {
 		var err error
 		if cttl, ok := cache.(types.CacheWithTTL); ok {
 			err = cttl.SetWithTTL(key, value, ttl)
@@ -246,7 +295,7 @@ func newCacheSetOperator() cacheOperator {
 }
 
 func newCacheAddOperator() cacheOperator {
-	return func(cache types.Cache, key string, value []byte, ttl *time.Duration) ([]byte, bool, error) {
+	return func(cache types.Cache, key string, value, _ []byte, ttl *time.Duration) ([]byte, bool, error) {
 		var err error
 		if cttl, ok := cache.(types.CacheWithTTL); ok {
 			err = cttl.AddWithTTL(key, value, ttl)
@@ -257,30 +306,70 @@ func newCacheAddOperator() cacheOperator {
 	}
 }
 
-func newCacheGetOperator() cacheOperator {
-	return func(cache types.Cache, key string, _ []byte, _ *time.Duration) ([]byte, bool, error) {
+func newCacheGetOperator(refreshTTL bool, log log.Modular) cacheOperator {
+	var warnOnce sync.Once
+	return func(cache types.Cache, key string, _, _ []byte, ttl *time.Duration) ([]byte, bool, error) {
 		result, err := cache.Get(key)
+		if err != nil || !refreshTTL {
+			return result, true, err
+		}
+
+		err = types.ErrNotSupported
+		if ctouch, ok := cache.(types.CacheWithTouch); ok {
+			err = ctouch.Touch(key, ttl)
+		}
+		switch {
+		case errors.Is(err, types.ErrNotSupported):
+			warnOnce.Do(func() {
+				log.Warnln("The target cache does not support refreshing TTLs, keys will expire regardless of reads")
+			})
+			err = nil
+		case errors.Is(err, types.ErrKeyNotFound):
+			// The key expired after it was read.
+			err = nil
+		}
 		return result, true, err
 	}
 }
 
 func newCacheDeleteOperator() cacheOperator {
-	return func(cache types.Cache, key string, _ []byte, ttl *time.Duration) ([]byte, bool, error) {
+	return func(cache types.Cache, key string, _, _ []byte, ttl *time.Duration) ([]byte, bool, error) {
 		err := cache.Delete(key)
 		return nil, false, err
 	}
 }
 
-func cacheOperatorFromString(operator string) (cacheOperator, error) {
+var errCacheValueMismatch = errors.New("current value of key does not match")
+
+func newCacheCompareAndSwapOperator() cacheOperator {
+	return func(cache types.Cache, key string, value, oldValue []byte, _ *time.Duration) ([]byte, bool, error) {
+		ccas, ok := cache.(types.CacheWithCAS)
+		if !ok {
+			return nil, false, types.ErrNotSupported
+		}
+		if len(oldValue) == 0 {
+			oldValue = nil
+		}
+		swapped, err := ccas.CompareAndSwap(key, oldValue, value)
+		if err == nil && !swapped {
+			err = errCacheValueMismatch
+		}
+		return nil, false, err
+	}
+}
+
+func cacheOperatorFromString(operator string, refreshTTL bool, log log.Modular) (cacheOperator, error) {
 	switch operator {
 	case "set":
 		return newCacheSetOperator(), nil
 	case "add":
 		return newCacheAddOperator(), nil
 	case "get":
-		return newCacheGetOperator(), nil
+		return newCacheGetOperator(refreshTTL, log), nil
 	case "delete":
 		return newCacheDeleteOperator(), nil
+	case "compare_and_swap":
+		return newCacheCompareAndSwapOperator(), nil
 	}
 	return nil, fmt.Errorf("operator not recognised: %v", operator)
 }
@@ -296,6 +385,7 @@ func (c *Cache) ProcessMessage(msg types.Message) ([]types.Message, types.Respon
 	proc := func(index int, span opentracing.Span, part types.Part) error {
 		key := c.key.String(index, msg)
 		value := c.value.Bytes(index, msg)
+		oldValue := c.oldValue.Bytes(index, msg)
 
 		var ttl *time.Duration
 		if ttls := c.ttl.String(index, msg); ttls != "" {
@@ -312,17 +402,21 @@ func (c *Cache) ProcessMessage(msg types.Message) ([]types.Message, types.Respon
 		var useResult bool
 		var err error
 		if cerr := interop.AccessCache(context.Background(), c.mgr, c.cacheName, func(cache types.Cache) {
-			result, useResult, err = c.operator(cache, key, value, ttl)
+			result, useResult, err = c.operator(cache, key, value, oldValue, ttl)
 		}); cerr != nil {
 			err = cerr
 		}
 		if err != nil {
-			if err != types.ErrKeyAlreadyExists {
-				c.mErr.Incr(1)
-				c.log.Debugf("Operator failed for key '%s': %v\n", key, err)
-			} else {
+			switch err {
+			case types.ErrKeyAlreadyExists:
 				c.mKeyAlreadyExists.Incr(1)
 				c.log.Debugf("Key already exists: %v\n", key)
+			case errCacheValueMismatch:
+				c.mValueMismatch.Incr(1)
+				c.log.Debugf("Value of key does not match: %v\n", key)
+			default:
+				c.mErr.Incr(1)
+				c.log.Debugf("Operator failed for key '%s': %v\n", key, err)
 			}
 			return err
 		}
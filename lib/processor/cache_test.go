@@ -3,12 +3,15 @@ package processor
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/cache"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCacheSetDeprecated(t *testing.T) {
@@ -251,6 +254,52 @@ func TestCacheAdd(t *testing.T) {
 	}
 }
 
+func TestCacheCompareAndSwap(t *testing.T) {
+	memCache, err := cache.NewMemory(cache.NewConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, memCache.Set("1", []byte("foo 1")))
+
+	mgr := &fakeMgr{
+		caches: map[string]types.Cache{
+			"foocache": memCache,
+		},
+	}
+
+	conf := NewConfig()
+	conf.Cache.Key = "${!json(\"key\")}"
+	conf.Cache.Value = "${!json(\"value\")}"
+	conf.Cache.OldValue = "${!json(\"old\")}"
+	conf.Cache.Resource = "foocache"
+	conf.Cache.Operator = "compare_and_swap"
+	proc, err := NewCache(conf, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	input := message.New([][]byte{
+		[]byte(`{"key":"1","value":"foo 2","old":"foo 1"}`),
+		[]byte(`{"key":"1","value":"foo 3","old":"foo 1"}`),
+		[]byte(`{"key":"2","value":"bar 1","old":""}`),
+		[]byte(`{"key":"2","value":"bar 2","old":""}`),
+	})
+
+	output, res := proc.ProcessMessage(input)
+	require.Nil(t, res)
+	require.Len(t, output, 1)
+
+	assert.Equal(t, message.GetAllBytes(input), message.GetAllBytes(output[0]))
+	assert.False(t, HasFailed(output[0].Get(0)))
+	assert.True(t, HasFailed(output[0].Get(1)))
+	assert.False(t, HasFailed(output[0].Get(2)))
+	assert.True(t, HasFailed(output[0].Get(3)))
+
+	actBytes, err := memCache.Get("1")
+	require.NoError(t, err)
+	assert.Equal(t, "foo 2", string(actBytes))
+
+	actBytes, err = memCache.Get("2")
+	require.NoError(t, err)
+	assert.Equal(t, "bar 1", string(actBytes))
+}
+
 func TestCacheGet(t *testing.T) {
 	memCache, err := cache.NewMemory(cache.NewConfig(), nil, log.Noop(), metrics.Noop())
 	if err != nil {
@@ -310,6 +359,69 @@ func TestCacheGet(t *testing.T) {
 	}
 }
 
+type touchRecordingCache struct {
+	types.Cache
+	touched map[string]*time.Duration
+}
+
+func (c *touchRecordingCache) Touch(key string, ttl *time.Duration) error {
+	c.touched[key] = ttl
+	return nil
+}
+
+type noTouchCache struct {
+	types.Cache
+}
+
+func TestCacheGetRefreshTTL(t *testing.T) {
+	memCache, err := cache.NewMemory(cache.NewConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, memCache.Set("1", []byte("foo 1")))
+
+	touchCache := &touchRecordingCache{Cache: memCache, touched: map[string]*time.Duration{}}
+	mgr := &fakeMgr{
+		caches: map[string]types.Cache{
+			"touchcache":   touchCache,
+			"notouchcache": &noTouchCache{Cache: memCache},
+		},
+	}
+
+	conf := NewConfig()
+	conf.Cache.Key = "${!json(\"key\")}"
+	conf.Cache.TTL = "10m"
+	conf.Cache.Resource = "touchcache"
+	conf.Cache.Operator = "get"
+	conf.Cache.RefreshTTL = true
+	proc, err := NewCache(conf, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	output, res := proc.ProcessMessage(message.New([][]byte{
+		[]byte(`{"key":"1"}`),
+		[]byte(`{"key":"2"}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, output, 1)
+
+	assert.Equal(t, "foo 1", string(output[0].Get(0).Get()))
+	assert.False(t, HasFailed(output[0].Get(0)))
+	assert.True(t, HasFailed(output[0].Get(1)))
+
+	ttl := time.Minute * 10
+	assert.Equal(t, map[string]*time.Duration{"1": &ttl}, touchCache.touched)
+
+	conf.Cache.Resource = "notouchcache"
+	proc, err = NewCache(conf, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	output, res = proc.ProcessMessage(message.New([][]byte{
+		[]byte(`{"key":"1"}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, output, 1)
+	assert.Equal(t, "foo 1", string(output[0].Get(0).Get()))
+	assert.False(t, HasFailed(output[0].Get(0)))
+}
+
 func TestCacheDelete(t *testing.T) {
 	memCache, err := cache.NewMemory(cache.NewConfig(), nil, log.Noop(), metrics.Noop())
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"compress/flate"
 	"compress/gzip"
 	"compress/zlib"
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/response"
 	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pierrec/lz4/v4"
 )
@@ -30,9 +32,10 @@ func init() {
 		},
 		Summary: `
 Decompresses messages according to the selected algorithm. Supported
-decompression types are: gzip, zlib, bzip2, flate, snappy, lz4.`,
+decompression types are: gzip, zlib, bzip2, flate, snappy, lz4, zstd.`,
 		FieldSpecs: docs.FieldSpecs{
-			docs.FieldCommon("algorithm", "The decompression algorithm to use.").HasOptions("gzip", "zlib", "bzip2", "flate", "snappy", "lz4"),
+			docs.FieldCommon("algorithm", "The decompression algorithm to use.").HasOptions("gzip", "zlib", "bzip2", "flate", "snappy", "lz4", "zstd"),
+			docs.FieldAdvanced("multipart", "Whether to decompress all members of a `gzip` stream, or all frames of a `zstd` stream, and concatenate the results, matching the behaviour of `gzip -dc`. Skippable `zstd` frames are always ignored. When set to `false` only the first member or frame is decompressed and any data following it is discarded. This field has no effect on other algorithms.").AtVersion("3.51.0"),
 			PartsFieldSpec,
 		},
 	}
@@ -43,6 +46,7 @@ decompression types are: gzip, zlib, bzip2, flate, snappy, lz4.`,
 // DecompressConfig contains configuration fields for the Decompress processor.
 type DecompressConfig struct {
 	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	Multipart bool   `json:"multipart" yaml:"multipart"`
 	Parts     []int  `json:"parts" yaml:"parts"`
 }
 
@@ -50,6 +54,7 @@ type DecompressConfig struct {
 func NewDecompressConfig() DecompressConfig {
 	return DecompressConfig{
 		Algorithm: "gzip",
+		Multipart: true,
 		Parts:     []int{},
 	}
 }
@@ -59,10 +64,19 @@ func NewDecompressConfig() DecompressConfig {
 type decompressFunc func(bytes []byte) ([]byte, error)
 
 func gzipDecompress(b []byte) ([]byte, error) {
+	return gzipDecompressMembers(b, true)
+}
+
+func gzipDecompressFirst(b []byte) ([]byte, error) {
+	return gzipDecompressMembers(b, false)
+}
+
+func gzipDecompressMembers(b []byte, multistream bool) ([]byte, error) {
 	r, err := gzip.NewReader(bytes.NewBuffer(b))
 	if err != nil {
 		return nil, err
 	}
+	r.Multistream(multistream)
 
 	outBuf := bytes.Buffer{}
 	if _, err = io.Copy(&outBuf, r); err != nil {
@@ -126,9 +140,73 @@ func lz4Decompress(b []byte) ([]byte, error) {
 	return outBuf.Bytes(), nil
 }
 
-func strToDecompressor(str string) (decompressFunc, error) {
+// zstdFirstFrame returns the first zstd frame of a stream that isn't a
+// skippable frame.
+func zstdFirstFrame(b []byte) ([]byte, error) {
+	for {
+		var h zstd.Header
+		if err := h.Decode(b); err != nil {
+			return nil, err
+		}
+		if h.Skippable {
+			skip := h.HeaderSize + int(h.SkippableSize)
+			if skip > len(b) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			b = b[skip:]
+			continue
+		}
+
+		// Walk the block headers in order to find the end of the frame.
+		end := h.HeaderSize
+		for last := false; !last; {
+			if end+3 > len(b) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			header := uint32(b[end]) | uint32(b[end+1])<<8 | uint32(b[end+2])<<16
+			last = header&1 == 1
+			size := int(header >> 3)
+			switch (header >> 1) & 3 {
+			case 1:
+				// RLE blocks contain a single byte regardless of their size.
+				size = 1
+			case 3:
+				return nil, errors.New("reserved zstd block type")
+			}
+			end += 3 + size
+		}
+		if h.HasCheckSum {
+			end += 4
+		}
+		if end > len(b) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return b[:end], nil
+	}
+}
+
+func newZstdDecompress(multipart bool) (decompressFunc, error) {
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	return func(b []byte) ([]byte, error) {
+		if !multipart {
+			var err error
+			if b, err = zstdFirstFrame(b); err != nil {
+				return nil, err
+			}
+		}
+		return dec.DecodeAll(b, nil)
+	}, nil
+}
+
+func strToDecompressor(str string, multipart bool) (decompressFunc, error) {
 	switch str {
 	case "gzip":
+		if !multipart {
+			return gzipDecompressFirst, nil
+		}
 		return gzipDecompress, nil
 	case "zlib":
 		return zlibDecompress, nil
@@ -140,6 +218,8 @@ func strToDecompressor(str string) (decompressFunc, error) {
 		return snappyDecompress, nil
 	case "lz4":
 		return lz4Decompress, nil
+	case "zstd":
+		return newZstdDecompress(multipart)
 	}
 	return nil, fmt.Errorf("decompression type not recognised: %v", str)
 }
@@ -165,7 +245,7 @@ type Decompress struct {
 func NewDecompress(
 	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
 ) (Type, error) {
-	dcor, err := strToDecompressor(conf.Decompress.Algorithm)
+	dcor, err := strToDecompressor(conf.Decompress.Algorithm, conf.Decompress.Multipart)
 	if err != nil {
 		return nil, err
 	}
@@ -6,13 +6,17 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDecompressBadAlgo(t *testing.T) {
@@ -353,3 +357,89 @@ func TestDecompressIndexBounds(t *testing.T) {
 		}
 	}
 }
+
+func TestDecompressMultipart(t *testing.T) {
+	gzipMembers := func(members ...string) []byte {
+		var buf bytes.Buffer
+		for _, m := range members {
+			zw := gzip.NewWriter(&buf)
+			zw.Write([]byte(m))
+			zw.Close()
+		}
+		return buf.Bytes()
+	}
+
+	zstdFrames := func(frames ...string) []byte {
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderCRC(true))
+		require.NoError(t, err)
+
+		// A skippable frame with a four byte payload.
+		b := []byte{0x50, 0x2a, 0x4d, 0x18, 0x04, 0x00, 0x00, 0x00, 'n', 'o', 'p', 'e'}
+		for _, f := range frames {
+			b = enc.EncodeAll([]byte(f), b)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name      string
+		algorithm string
+		multipart bool
+		input     []byte
+		output    string
+	}{
+		{
+			name:      "gzip multiple members",
+			algorithm: "gzip",
+			multipart: true,
+			input:     gzipMembers("hello ", "world", "!"),
+			output:    "hello world!",
+		},
+		{
+			name:      "gzip first member",
+			algorithm: "gzip",
+			multipart: false,
+			input:     gzipMembers("hello ", "world", "!"),
+			output:    "hello ",
+		},
+		{
+			name:      "zstd multiple frames",
+			algorithm: "zstd",
+			multipart: true,
+			input:     zstdFrames("hello ", "world", "!"),
+			output:    "hello world!",
+		},
+		{
+			name:      "zstd first frame",
+			algorithm: "zstd",
+			multipart: false,
+			input:     zstdFrames("hello ", "world", "!"),
+			output:    "hello ",
+		},
+		{
+			name:      "zstd rle first frame",
+			algorithm: "zstd",
+			multipart: false,
+			input:     zstdFrames(strings.Repeat("a", 1000), "b"),
+			output:    strings.Repeat("a", 1000),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := NewConfig()
+			conf.Decompress.Algorithm = test.algorithm
+			conf.Decompress.Multipart = test.multipart
+
+			proc, err := NewDecompress(conf, nil, log.Noop(), metrics.Noop())
+			require.NoError(t, err)
+
+			msgs, res := proc.ProcessMessage(message.New([][]byte{test.input}))
+			require.Nil(t, res)
+			require.Len(t, msgs, 1)
+			assert.Equal(t, test.output, string(msgs[0].Get(0).Get()))
+			assert.False(t, HasFailed(msgs[0].Get(0)))
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package processor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	Constructors[TypeDedupeBatch] = TypeSpec{
+		constructor: NewDedupeBatch,
+		Categories: []Category{
+			CategoryUtility,
+		},
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Summary: `
+Removes duplicate messages within each batch, keeping either the first or the
+last occurrence of each key.`,
+		Description: `
+Unlike the ` + "[`dedupe`](/docs/components/processors/dedupe)" + ` processor
+no cache is used and therefore no state is kept between batches, which makes
+this processor a cheap way of collapsing duplicates that arrive within the same
+batch or window, such as the messages of a single Kafka fetch.
+
+By default messages are compared by their entire contents, but the ` + "`key`" + `
+field can be populated with a function interpolated string in order to compare
+on dynamic fields within a message instead, such as its metadata, JSON fields,
+etc. A full list of interpolation functions can be found
+[here](/docs/configuration/interpolation#bloblang-queries).
+
+The remaining messages of a batch retain their original ordering.`,
+		FieldSpecs: docs.FieldSpecs{
+			docs.FieldCommon("key", "An optional key to use for deduplication (instead of the entire message contents).", `${! meta("kafka_key") }`, `${! json("id") }`).IsInterpolated(),
+			docs.FieldCommon("keep", "Which occurrence of a duplicated key to keep.").HasOptions("first", "last"),
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Latest State per Key",
+				Summary: "Collapse windowed batches of change events so that only the latest event of each document is written.",
+				Config: `
+input:
+  kafka:
+    addresses: [ TODO ]
+    topics: [ document_changes ]
+    consumer_group: benthos_group
+    batching:
+      count: 1000
+      period: 1s
+  processors:
+    - dedupe_batch:
+        key: ${! json("document.id") }
+        keep: last
+`,
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// DedupeBatchConfig contains configuration fields for the DedupeBatch
+// processor.
+type DedupeBatchConfig struct {
+	Key  string `json:"key" yaml:"key"`
+	Keep string `json:"keep" yaml:"keep"`
+}
+
+// NewDedupeBatchConfig returns a DedupeBatchConfig with default values.
+func NewDedupeBatchConfig() DedupeBatchConfig {
+	return DedupeBatchConfig{
+		Key:  "",
+		Keep: "first",
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// DedupeBatch is a processor that removes messages of a batch that share a key
+// with another message of the same batch.
+type DedupeBatch struct {
+	log   log.Modular
+	stats metrics.Type
+
+	key      *field.Expression
+	keepLast bool
+
+	mCount     metrics.StatCounter
+	mDropped   metrics.StatCounter
+	mSent      metrics.StatCounter
+	mBatchSent metrics.StatCounter
+}
+
+// NewDedupeBatch returns a DedupeBatch processor.
+func NewDedupeBatch(
+	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
+) (Type, error) {
+	d := &DedupeBatch{
+		log:   log,
+		stats: stats,
+
+		mCount:     stats.GetCounter("count"),
+		mDropped:   stats.GetCounter("dropped"),
+		mSent:      stats.GetCounter("sent"),
+		mBatchSent: stats.GetCounter("batch.sent"),
+	}
+
+	switch conf.DedupeBatch.Keep {
+	case "first":
+	case "last":
+		d.keepLast = true
+	default:
+		return nil, fmt.Errorf("keep option not recognised: %v", conf.DedupeBatch.Keep)
+	}
+
+	if conf.DedupeBatch.Key != "" {
+		var err error
+		if d.key, err = bloblang.NewField(conf.DedupeBatch.Key); err != nil {
+			return nil, fmt.Errorf("failed to parse key expression: %v", err)
+		}
+	}
+	return d, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ProcessMessage applies the processor to a message, either creating >0
+// resulting messages or a response to be sent back to the message source.
+func (d *DedupeBatch) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	d.mCount.Incr(1)
+
+	if msg.Len() == 0 {
+		return nil, response.NewAck()
+	}
+
+	keys := make([]string, msg.Len())
+	keepIndexes := make(map[string]int, msg.Len())
+	for i := range keys {
+		if d.key != nil {
+			keys[i] = d.key.String(i, msg)
+		} else {
+			keys[i] = string(msg.Get(i).Get())
+		}
+		if _, exists := keepIndexes[keys[i]]; !exists || d.keepLast {
+			keepIndexes[keys[i]] = i
+		}
+	}
+
+	newMsg := message.New(nil)
+	for i, k := range keys {
+		if keepIndexes[k] == i {
+			newMsg.Append(msg.Get(i).Copy())
+		} else {
+			d.mDropped.Incr(1)
+		}
+	}
+
+	d.mBatchSent.Incr(1)
+	d.mSent.Incr(int64(newMsg.Len()))
+	msgs := [1]types.Message{newMsg}
+	return msgs[:], nil
+}
+
+// CloseAsync shuts down the processor and stops processing requests.
+func (d *DedupeBatch) CloseAsync() {
+}
+
+// WaitForClose blocks until the processor has closed down.
+func (d *DedupeBatch) WaitForClose(timeout time.Duration) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupeBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		keep   string
+		input  []string
+		output []string
+	}{
+		{
+			name:   "contents keep first",
+			keep:   "first",
+			input:  []string{"foo", "bar", "foo", "baz", "bar"},
+			output: []string{"foo", "bar", "baz"},
+		},
+		{
+			name:   "contents keep last",
+			keep:   "last",
+			input:  []string{"foo", "bar", "foo", "baz", "bar"},
+			output: []string{"foo", "baz", "bar"},
+		},
+		{
+			name: "key keep first",
+			key:  `${! json("id") }`,
+			keep: "first",
+			input: []string{
+				`{"id":1,"v":"a"}`, `{"id":2,"v":"b"}`, `{"id":1,"v":"c"}`,
+			},
+			output: []string{
+				`{"id":1,"v":"a"}`, `{"id":2,"v":"b"}`,
+			},
+		},
+		{
+			name: "key keep last",
+			key:  `${! json("id") }`,
+			keep: "last",
+			input: []string{
+				`{"id":1,"v":"a"}`, `{"id":2,"v":"b"}`, `{"id":1,"v":"c"}`,
+			},
+			output: []string{
+				`{"id":2,"v":"b"}`, `{"id":1,"v":"c"}`,
+			},
+		},
+		{
+			name:   "no duplicates",
+			keep:   "first",
+			input:  []string{"foo", "bar"},
+			output: []string{"foo", "bar"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := NewConfig()
+			conf.Type = TypeDedupeBatch
+			conf.DedupeBatch.Key = test.key
+			conf.DedupeBatch.Keep = test.keep
+
+			proc, err := New(conf, nil, log.Noop(), metrics.Noop())
+			require.NoError(t, err)
+
+			var input [][]byte
+			for _, s := range test.input {
+				input = append(input, []byte(s))
+			}
+
+			msgs, res := proc.ProcessMessage(message.New(input))
+			require.Nil(t, res)
+			require.Len(t, msgs, 1)
+
+			var output []string
+			for _, b := range message.GetAllBytes(msgs[0]) {
+				output = append(output, string(b))
+			}
+			assert.Equal(t, test.output, output)
+		})
+	}
+}
+
+func TestDedupeBatchBadKeep(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = TypeDedupeBatch
+	conf.DedupeBatch.Keep = "middle"
+
+	_, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "keep option not recognised: middle")
+}
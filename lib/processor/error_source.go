@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// ErrorSource describes the processor from which a processing error of a
+// message part originated, along with the metadata that the message part
+// carried at the point that it failed.
+type ErrorSource struct {
+	Label    string
+	Path     string
+	Type     string
+	Metadata map[string]string
+}
+
+// GetErrorSource returns the source of the latest processing error of a message
+// part, and a boolean indicating whether the part has failed with a known
+// source.
+func GetErrorSource(part types.Part) (ErrorSource, bool) {
+	if !HasFailed(part) {
+		return ErrorSource{}, false
+	}
+	meta := part.Metadata()
+	src := ErrorSource{
+		Label: meta.Get(types.ErrorSourceLabelKey),
+		Path:  meta.Get(types.ErrorSourcePathKey),
+		Type:  meta.Get(types.ErrorSourceTypeKey),
+	}
+	if src.Type == "" {
+		return src, false
+	}
+	if metaStr := meta.Get(types.ErrorSourceMetadataKey); metaStr != "" {
+		_ = json.Unmarshal([]byte(metaStr), &src.Metadata)
+	}
+	return src, true
+}
+
+func (e ErrorSource) flag(part types.Part) {
+	metaSubset := map[string]string{}
+	_ = part.Metadata().Iter(func(k, v string) error {
+		if !strings.HasPrefix(k, "benthos_") {
+			metaSubset[k] = v
+		}
+		return nil
+	})
+	metaBytes, _ := json.Marshal(metaSubset)
+
+	part.Metadata().
+		Set(types.ErrorSourceLabelKey, e.Label).
+		Set(types.ErrorSourcePathKey, e.Path).
+		Set(types.ErrorSourceTypeKey, e.Type).
+		Set(types.ErrorSourceMetadataKey, string(metaBytes))
+}
+
+func clearErrorSource(part types.Part) {
+	meta := part.Metadata()
+	if meta.Get(types.ErrorSourceTypeKey) == "" && meta.Get(types.ErrorStackKey) == "" {
+		return
+	}
+	meta.Delete(types.ErrorSourceLabelKey).
+		Delete(types.ErrorSourcePathKey).
+		Delete(types.ErrorSourceTypeKey).
+		Delete(types.ErrorSourceMetadataKey).
+		Delete(types.ErrorStackKey)
+}
+
+//------------------------------------------------------------------------------
+
+type errorSourceProc struct {
+	src   ErrorSource
+	child types.Processor
+}
+
+// WithErrorSource wraps a processor so that message parts it flags as having
+// failed are annotated with metadata describing the processor, which can be
+// obtained with GetErrorSource. Errors flagged by nested processors retain
+// their original source.
+func WithErrorSource(src ErrorSource, p types.Processor) types.Processor {
+	return &errorSourceProc{src: src, child: p}
+}
+
+// Unwrap returns the processor wrapped by the error source annotation.
+func (e *errorSourceProc) Unwrap() types.Processor {
+	return e.child
+}
+
+// ProcessMessage applies the child processor to a message and annotates any
+// newly failed message parts with the error source. Flagging a part as failed
+// removes any previous error source, and therefore only failed parts without a
+// source need to be annotated.
+func (e *errorSourceProc) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	msgs, res := e.child.ProcessMessage(msg)
+	for _, m := range msgs {
+		_ = m.Iter(func(i int, p types.Part) error {
+			if HasFailed(p) && p.Metadata().Get(types.ErrorSourceTypeKey) == "" {
+				e.src.flag(p)
+			}
+			return nil
+		})
+	}
+	return msgs, res
+}
+
+// CloseAsync shuts down the processor and stops processing requests.
+func (e *errorSourceProc) CloseAsync() {
+	e.child.CloseAsync()
+}
+
+// WaitForClose blocks until the processor has closed down.
+func (e *errorSourceProc) WaitForClose(timeout time.Duration) error {
+	return e.child.WaitForClose(timeout)
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newErrorSourceTestProc(t *testing.T, mapping string) types.Processor {
+	t.Helper()
+	conf := NewConfig()
+	conf.Type = TypeBloblang
+	conf.Bloblang.Mapping = mapping
+	p, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	return p
+}
+
+func TestErrorSource(t *testing.T) {
+	failA := WithErrorSource(ErrorSource{
+		Label: "a",
+		Path:  "pipeline.processor.0",
+		Type:  TypeBloblang,
+	}, newErrorSourceTestProc(t, `root = if this.fail == "a" { throw("a failed") } else { this }`))
+
+	failB := WithErrorSource(ErrorSource{
+		Path: "pipeline.processor.1",
+		Type: TypeBloblang,
+	}, newErrorSourceTestProc(t, `root = if this.fail == "b" { throw("b failed") } else { this }`))
+
+	msg := message.New([][]byte{
+		[]byte(`{"fail":"a"}`),
+		[]byte(`{"fail":"b"}`),
+		[]byte(`{"fail":"none"}`),
+	})
+	msg.Get(0).Metadata().Set("kafka_topic", "foo")
+
+	msgs, res := ExecuteAll([]types.Processor{failA, failB}, msg)
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	src, ok := GetErrorSource(msgs[0].Get(0))
+	require.True(t, ok)
+	assert.Equal(t, ErrorSource{
+		Label:    "a",
+		Path:     "pipeline.processor.0",
+		Type:     TypeBloblang,
+		Metadata: map[string]string{"kafka_topic": "foo"},
+	}, src)
+
+	src, ok = GetErrorSource(msgs[0].Get(1))
+	require.True(t, ok)
+	assert.Equal(t, ErrorSource{
+		Path:     "pipeline.processor.1",
+		Type:     TypeBloblang,
+		Metadata: map[string]string{},
+	}, src)
+
+	_, ok = GetErrorSource(msgs[0].Get(2))
+	assert.False(t, ok)
+
+	// Clearing the error also clears the source.
+	ClearFail(msgs[0].Get(0))
+	msgs, res = ExecuteAll([]types.Processor{failB}, msgs[0])
+	require.Nil(t, res)
+	_, ok = GetErrorSource(msgs[0].Get(0))
+	assert.False(t, ok)
+	assert.Empty(t, msgs[0].Get(0).Metadata().Get(types.ErrorSourceTypeKey))
+	assert.Empty(t, msgs[0].Get(0).Metadata().Get(types.ErrorSourceMetadataKey))
+
+	// A later error replaces the source.
+	msgs, res = ExecuteAll([]types.Processor{failA}, message.New([][]byte{[]byte(`{"fail":"a"}`)}))
+	require.Nil(t, res)
+	msgs, res = ExecuteAll([]types.Processor{WithErrorSource(ErrorSource{
+		Path: "pipeline.processor.2",
+		Type: TypeBloblang,
+	}, newErrorSourceTestProc(t, `root = throw("c failed")`))}, msgs[0])
+	require.Nil(t, res)
+	src, ok = GetErrorSource(msgs[0].Get(0))
+	require.True(t, ok)
+	assert.Equal(t, "pipeline.processor.2", src.Path)
+}
+
+func TestErrorSourceNested(t *testing.T) {
+	child := WithErrorSource(ErrorSource{
+		Path: "pipeline.processor.0.try.0",
+		Type: TypeBloblang,
+	}, newErrorSourceTestProc(t, `root = throw("nope")`))
+
+	parent := WithErrorSource(ErrorSource{
+		Path: "pipeline.processor.0",
+		Type: TypeTry,
+	}, &Try{
+		children:   []types.Processor{child},
+		log:        log.Noop(),
+		mCount:     metrics.Noop().GetCounter("count"),
+		mErr:       metrics.Noop().GetCounter("error"),
+		mSent:      metrics.Noop().GetCounter("sent"),
+		mBatchSent: metrics.Noop().GetCounter("batch.sent"),
+	})
+
+	msgs, res := parent.ProcessMessage(message.New([][]byte{[]byte(`{}`)}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	src, ok := GetErrorSource(msgs[0].Get(0))
+	require.True(t, ok)
+	assert.Equal(t, "pipeline.processor.0.try.0", src.Path)
+	assert.Equal(t, TypeBloblang, src.Type)
+}
@@ -31,11 +31,11 @@ func TestGroupBy(t *testing.T) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = content().uppercase()`
+	procConf.Bloblang.Mapping = `root = content().uppercase()`
 
 	procConf2 := NewConfig()
 	procConf2.Type = TypeBloblang
-	procConf2.Bloblang = `root = content().trim()`
+	procConf2.Bloblang.Mapping = `root = content().trim()`
 
 	conf.GroupBy = append(conf.GroupBy, GroupByElement{
 		Check: `content().contains("bar")`,
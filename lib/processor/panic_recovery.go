@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+type panicRecoveryProc struct {
+	child types.Processor
+
+	log        log.Modular
+	mRecovered metrics.StatCounter
+}
+
+// WithPanicRecovery wraps a processor so that a panic during the processing of
+// a message is recovered. Instead of crashing the pipeline all parts of the
+// message are flagged as having failed with an error describing the panic, and
+// the stack trace of the panic is stored in the metadata key ErrorStackKey. The
+// message then continues through the pipeline where it can be handled with
+// regular error handling patterns.
+func WithPanicRecovery(p types.Processor, log log.Modular, stats metrics.Type) types.Processor {
+	return &panicRecoveryProc{
+		child:      p,
+		log:        log,
+		mRecovered: stats.GetCounter("panic.recovered"),
+	}
+}
+
+// Unwrap returns the processor wrapped by the panic recovery.
+func (p *panicRecoveryProc) Unwrap() types.Processor {
+	return p.child
+}
+
+// ProcessMessage applies the child processor to a message, recovering from any
+// panic that occurs.
+func (p *panicRecoveryProc) ProcessMessage(msg types.Message) (msgs []types.Message, res types.Response) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		p.mRecovered.Incr(1)
+
+		err := fmt.Errorf("processor panicked: %v", r)
+		stack := string(debug.Stack())
+		p.log.Errorf("Recovered from a panic whilst processing a message: %v\n%s", r, stack)
+
+		_ = msg.Iter(func(i int, part types.Part) error {
+			FlagErr(part, err)
+			part.Metadata().Set(types.ErrorStackKey, stack)
+			return nil
+		})
+		msgs, res = []types.Message{msg}, nil
+	}()
+	return p.child.ProcessMessage(msg)
+}
+
+// CloseAsync shuts down the processor and stops processing requests.
+func (p *panicRecoveryProc) CloseAsync() {
+	p.child.CloseAsync()
+}
+
+// WaitForClose blocks until the processor has closed down.
+func (p *panicRecoveryProc) WaitForClose(timeout time.Duration) error {
+	return p.child.WaitForClose(timeout)
+}
+
+//------------------------------------------------------------------------------
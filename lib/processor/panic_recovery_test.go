@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panickyProc struct{}
+
+func (p panickyProc) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	if string(msg.Get(0).Get()) == "panic" {
+		panic("oh no")
+	}
+	return []types.Message{msg}, nil
+}
+
+func (p panickyProc) CloseAsync() {}
+
+func (p panickyProc) WaitForClose(time.Duration) error {
+	return nil
+}
+
+func TestPanicRecovery(t *testing.T) {
+	stats := metrics.NewLocal()
+	proc := WithErrorSource(ErrorSource{
+		Label: "panicky",
+		Path:  "pipeline.processor.0",
+		Type:  "plugin",
+	}, WithPanicRecovery(panickyProc{}, log.Noop(), stats))
+	after := newErrorSourceTestProc(t, `root = content().uppercase()`)
+
+	msgs, res := ExecuteAll([]types.Processor{proc, after},
+		message.New([][]byte{[]byte("panic")}),
+		message.New([][]byte{[]byte("hello world")}),
+	)
+	require.Nil(t, res)
+	require.Len(t, msgs, 2)
+
+	failed := msgs[0].Get(0)
+	assert.Equal(t, "processor panicked: oh no", GetFail(failed))
+	assert.Contains(t, failed.Metadata().Get(types.ErrorStackKey), "panickyProc")
+	src, ok := GetErrorSource(failed)
+	require.True(t, ok)
+	assert.Equal(t, "panicky", src.Label)
+
+	assert.Equal(t, "HELLO WORLD", string(msgs[1].Get(0).Get()))
+	assert.False(t, HasFailed(msgs[1].Get(0)))
+
+	assert.Equal(t, int64(1), stats.GetCounters()["panic.recovered"])
+
+	// Clearing the error also clears the stack.
+	ClearFail(failed)
+	msgs, res = ExecuteAll([]types.Processor{WithErrorSource(ErrorSource{Type: TypeBloblang}, after)}, msgs[0])
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "", msgs[0].Get(0).Metadata().Get(types.ErrorStackKey))
+}
@@ -0,0 +1,439 @@
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/text/encoding/charmap"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	Constructors[TypeParseFixedWidth] = TypeSpec{
+		constructor: NewParseFixedWidth,
+		Categories: []Category{
+			CategoryParsing,
+		},
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Summary: `
+Parses fixed width records into structured objects, or formats structured
+objects into fixed width records.`,
+		Description: `
+Each message is treated as a single record, where each of the ` + "`fields`" + `
+occupies a range of bytes within the record described by its ` + "`start`" + `
+offset, counted from zero, and its ` + "`length`" + `. Files containing a record
+on each line can be consumed with the ` + "`lines`" + ` codec of inputs such as
+` + "[`file`](/docs/components/inputs/file)" + `.
+
+With the operator ` + "`parse`" + ` the length of each record must match the
+` + "`record_length`" + `, which defaults to the end of the last field, and
+records that do not match are flagged as having failed and can be handled using
+[error handling](/docs/configuration/error_handling) patterns. A trailing
+carriage return is ignored.
+
+With the operator ` + "`format`" + ` messages must be objects and the value of
+each field is written into its range of the record, with strings padded with
+trailing spaces and numbers padded with leading zeros. Values that do not fit
+within the length of their field result in an error. Any bytes not covered by a
+field are filled with spaces.
+
+### Encodings
+
+Records exported from mainframes are commonly encoded with EBCDIC, which can be
+converted to and from UTF-8 by setting ` + "`encoding`" + ` to
+` + "`ebcdic`" + ` (code page 037). Offsets and lengths always refer to the
+encoded record.`,
+		FieldSpecs: docs.FieldSpecs{
+			docs.FieldCommon("operator", "Whether to parse fixed width records into objects or format objects into fixed width records.").HasOptions("parse", "format"),
+			docs.FieldCommon("fields", "The fields of each record.").Array().WithChildren(
+				docs.FieldString("name", "The name of the field within the structured object.").HasDefault(""),
+				docs.FieldInt("start", "The offset in bytes of the first byte of the field within a record, counted from zero.").HasDefault(0),
+				docs.FieldInt("length", "The length of the field in bytes.").HasDefault(0),
+				docs.FieldString("type", "The type of the field value.").HasOptions("string", "int", "float").HasDefault("string"),
+				docs.FieldBool("trim", "Whether to remove leading and trailing spaces from string values when parsing.").HasDefault(true),
+			),
+			docs.FieldAdvanced("record_length", "The expected length of each record in bytes. When zero the length is the end of the last field."),
+			docs.FieldAdvanced("encoding", "The character encoding of records.").HasOptions("ascii", "ebcdic"),
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Parse Customer Records",
+				Summary: "Lines of a fixed width export are parsed into JSON documents, and records of an unexpected length are logged and dropped.",
+				Config: `
+input:
+  file:
+    paths: [ ./customers.dat ]
+    codec: lines
+  processors:
+    - parse_fixed_width:
+        fields:
+          - { name: id, start: 0, length: 8, type: int }
+          - { name: name, start: 8, length: 20 }
+          - { name: balance, start: 28, length: 10, type: float }
+    - catch:
+      - log:
+          message: "Bad record: ${! error() }"
+      - bloblang: root = deleted()
+`,
+			},
+			{
+				Title:   "Format Customer Records",
+				Summary: "JSON documents are formatted into EBCDIC encoded fixed width records.",
+				Config: `
+pipeline:
+  processors:
+    - parse_fixed_width:
+        operator: format
+        encoding: ebcdic
+        fields:
+          - { name: id, start: 0, length: 8, type: int }
+          - { name: name, start: 8, length: 20 }
+          - { name: balance, start: 28, length: 10, type: float }
+`,
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// FixedWidthFieldConfig describes a single field of a fixed width record.
+type FixedWidthFieldConfig struct {
+	Name   string `json:"name" yaml:"name"`
+	Start  int    `json:"start" yaml:"start"`
+	Length int    `json:"length" yaml:"length"`
+	Type   string `json:"type" yaml:"type"`
+	Trim   bool   `json:"trim" yaml:"trim"`
+}
+
+// NewFixedWidthFieldConfig returns a FixedWidthFieldConfig with default
+// values.
+func NewFixedWidthFieldConfig() FixedWidthFieldConfig {
+	return FixedWidthFieldConfig{
+		Name:   "",
+		Start:  0,
+		Length: 0,
+		Type:   "string",
+		Trim:   true,
+	}
+}
+
+// UnmarshalYAML ensures that when parsing configs that are in a slice the
+// default values are still applied.
+func (f *FixedWidthFieldConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type confAlias FixedWidthFieldConfig
+	aliased := confAlias(NewFixedWidthFieldConfig())
+
+	if err := unmarshal(&aliased); err != nil {
+		return err
+	}
+
+	*f = FixedWidthFieldConfig(aliased)
+	return nil
+}
+
+// ParseFixedWidthConfig contains configuration fields for the ParseFixedWidth
+// processor.
+type ParseFixedWidthConfig struct {
+	Operator     string                  `json:"operator" yaml:"operator"`
+	Fields       []FixedWidthFieldConfig `json:"fields" yaml:"fields"`
+	RecordLength int                     `json:"record_length" yaml:"record_length"`
+	Encoding     string                  `json:"encoding" yaml:"encoding"`
+}
+
+// NewParseFixedWidthConfig returns a ParseFixedWidthConfig with default
+// values.
+func NewParseFixedWidthConfig() ParseFixedWidthConfig {
+	return ParseFixedWidthConfig{
+		Operator:     "parse",
+		Fields:       []FixedWidthFieldConfig{},
+		RecordLength: 0,
+		Encoding:     "ascii",
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ParseFixedWidth is a processor that converts between fixed width records and
+// structured objects.
+type ParseFixedWidth struct {
+	conf         ParseFixedWidthConfig
+	recordLength int
+	ebcdic       bool
+	log          log.Modular
+	stats        metrics.Type
+
+	mCount     metrics.StatCounter
+	mErr       metrics.StatCounter
+	mSent      metrics.StatCounter
+	mBatchSent metrics.StatCounter
+}
+
+// NewParseFixedWidth returns a ParseFixedWidth processor.
+func NewParseFixedWidth(
+	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
+) (Type, error) {
+	pConf := conf.ParseFixedWidth
+	if len(pConf.Fields) == 0 {
+		return nil, errors.New("at least one field must be specified")
+	}
+
+	p := &ParseFixedWidth{
+		conf:  pConf,
+		log:   log,
+		stats: stats,
+
+		mCount:     stats.GetCounter("count"),
+		mErr:       stats.GetCounter("error"),
+		mSent:      stats.GetCounter("sent"),
+		mBatchSent: stats.GetCounter("batch.sent"),
+	}
+
+	switch pConf.Operator {
+	case "parse", "format":
+	default:
+		return nil, fmt.Errorf("operator not recognised: %v", pConf.Operator)
+	}
+
+	switch pConf.Encoding {
+	case "ascii":
+	case "ebcdic":
+		p.ebcdic = true
+	default:
+		return nil, fmt.Errorf("encoding not recognised: %v", pConf.Encoding)
+	}
+
+	for i, f := range pConf.Fields {
+		if f.Name == "" {
+			return nil, fmt.Errorf("field %v: a name must be specified", i)
+		}
+		if f.Start < 0 || f.Length <= 0 {
+			return nil, fmt.Errorf("field %v: start must not be negative and length must be greater than zero", f.Name)
+		}
+		switch f.Type {
+		case "string", "int", "float":
+		default:
+			return nil, fmt.Errorf("field %v: type not recognised: %v", f.Name, f.Type)
+		}
+		if end := f.Start + f.Length; end > p.recordLength {
+			p.recordLength = end
+		}
+	}
+	if pConf.RecordLength > 0 {
+		if pConf.RecordLength < p.recordLength {
+			return nil, fmt.Errorf("record_length %v is shorter than the end of the last field %v", pConf.RecordLength, p.recordLength)
+		}
+		p.recordLength = pConf.RecordLength
+	}
+	return p, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (p *ParseFixedWidth) parse(record []byte) (map[string]interface{}, error) {
+	if l := len(record); l > 0 && record[l-1] == '\r' {
+		record = record[:l-1]
+	}
+	if len(record) != p.recordLength {
+		return nil, fmt.Errorf("expected record of length %v, got %v", p.recordLength, len(record))
+	}
+
+	obj := make(map[string]interface{}, len(p.conf.Fields))
+	for _, f := range p.conf.Fields {
+		raw := record[f.Start : f.Start+f.Length]
+		if p.ebcdic {
+			var err error
+			if raw, err = charmap.CodePage037.NewDecoder().Bytes(raw); err != nil {
+				return nil, fmt.Errorf("field %v: %w", f.Name, err)
+			}
+		}
+
+		str := string(raw)
+		if f.Trim || f.Type != "string" {
+			str = strings.TrimSpace(str)
+		}
+
+		switch f.Type {
+		case "string":
+			obj[f.Name] = str
+		case "int":
+			if str == "" {
+				obj[f.Name] = nil
+				continue
+			}
+			v, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", f.Name, err)
+			}
+			obj[f.Name] = v
+		case "float":
+			if str == "" {
+				obj[f.Name] = nil
+				continue
+			}
+			v, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", f.Name, err)
+			}
+			obj[f.Name] = v
+		}
+	}
+	return obj, nil
+}
+
+func formatFixedWidthValue(f FixedWidthFieldConfig, v interface{}) (string, error) {
+	if v == nil {
+		return strings.Repeat(" ", f.Length), nil
+	}
+
+	var str string
+	switch f.Type {
+	case "string":
+		switch t := v.(type) {
+		case string:
+			str = t
+		default:
+			str = fmt.Sprintf("%v", t)
+		}
+		if l := len(str); l < f.Length {
+			str += strings.Repeat(" ", f.Length-l)
+		}
+	case "int", "float":
+		var num float64
+		switch t := v.(type) {
+		case float64:
+			num = t
+		case int64:
+			num = float64(t)
+		case json.Number:
+			var err error
+			if num, err = t.Float64(); err != nil {
+				return "", err
+			}
+		case string:
+			var err error
+			if num, err = strconv.ParseFloat(strings.TrimSpace(t), 64); err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("expected number value, got %T", v)
+		}
+		if f.Type == "int" {
+			if num != float64(int64(num)) {
+				return "", fmt.Errorf("expected integer value, got %v", num)
+			}
+			str = strconv.FormatInt(int64(num), 10)
+		} else {
+			str = strconv.FormatFloat(num, 'f', -1, 64)
+		}
+		sign := ""
+		if strings.HasPrefix(str, "-") {
+			sign, str = "-", str[1:]
+		}
+		if l := len(str) + len(sign); l < f.Length {
+			str = strings.Repeat("0", f.Length-l) + str
+		}
+		str = sign + str
+	}
+	if len(str) > f.Length {
+		return "", fmt.Errorf("value of length %v exceeds field length %v", len(str), f.Length)
+	}
+	return str, nil
+}
+
+func (p *ParseFixedWidth) format(v interface{}) ([]byte, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected object value, got %T", v)
+	}
+
+	record := make([]byte, p.recordLength)
+	for i := range record {
+		record[i] = ' '
+	}
+	for _, f := range p.conf.Fields {
+		str, err := formatFixedWidthValue(f, obj[f.Name])
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", f.Name, err)
+		}
+		copy(record[f.Start:f.Start+f.Length], str)
+	}
+
+	if p.ebcdic {
+		return charmap.CodePage037.NewEncoder().Bytes(record)
+	}
+	return record, nil
+}
+
+// ProcessMessage applies the processor to a message, either creating >0
+// resulting messages or a response to be sent back to the message source.
+func (p *ParseFixedWidth) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	p.mCount.Incr(1)
+
+	if msg.Len() == 0 {
+		return nil, response.NewAck()
+	}
+
+	newMsg := msg.Copy()
+
+	proc := func(index int, span opentracing.Span, part types.Part) error {
+		if p.conf.Operator == "parse" {
+			obj, err := p.parse(part.Get())
+			if err != nil {
+				p.log.Debugf("Failed to parse fixed width record: %v\n", err)
+				p.mErr.Incr(1)
+				return err
+			}
+			if err = part.SetJSON(obj); err != nil {
+				p.mErr.Incr(1)
+				return err
+			}
+			return nil
+		}
+
+		v, err := part.JSON()
+		if err != nil {
+			p.log.Debugf("Failed to parse message as JSON: %v\n", err)
+			p.mErr.Incr(1)
+			return err
+		}
+		record, err := p.format(v)
+		if err != nil {
+			p.log.Debugf("Failed to format fixed width record: %v\n", err)
+			p.mErr.Incr(1)
+			return err
+		}
+		part.Set(record)
+		return nil
+	}
+
+	IteratePartsWithSpan(TypeParseFixedWidth, nil, newMsg, proc)
+
+	p.mBatchSent.Incr(1)
+	p.mSent.Incr(int64(newMsg.Len()))
+	msgs := [1]types.Message{newMsg}
+	return msgs[:], nil
+}
+
+// CloseAsync shuts down the processor and stops processing requests.
+func (p *ParseFixedWidth) CloseAsync() {
+}
+
+// WaitForClose blocks until the processor has closed down.
+func (p *ParseFixedWidth) WaitForClose(timeout time.Duration) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,116 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func fixedWidthTestConf() Config {
+	conf := NewConfig()
+	conf.Type = TypeParseFixedWidth
+
+	id := NewFixedWidthFieldConfig()
+	id.Name, id.Start, id.Length, id.Type = "id", 0, 4, "int"
+	name := NewFixedWidthFieldConfig()
+	name.Name, name.Start, name.Length = "name", 4, 6
+	bal := NewFixedWidthFieldConfig()
+	bal.Name, bal.Start, bal.Length, bal.Type = "balance", 11, 6, "float"
+
+	conf.ParseFixedWidth.Fields = []FixedWidthFieldConfig{id, name, bal}
+	return conf
+}
+
+func TestParseFixedWidthParse(t *testing.T) {
+	proc, err := New(fixedWidthTestConf(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msgs, res := proc.ProcessMessage(message.New([][]byte{
+		[]byte("0012foo    012.50"),
+		[]byte("0013barbaz  -00.5\r"),
+		[]byte("0014short"),
+		[]byte("00x5foo    012.50"),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	assert.Equal(t, `{"balance":12.5,"id":12,"name":"foo"}`, string(msgs[0].Get(0).Get()))
+	assert.Equal(t, `{"balance":-0.5,"id":13,"name":"barbaz"}`, string(msgs[0].Get(1).Get()))
+	assert.Equal(t, "", GetFail(msgs[0].Get(0)))
+	assert.Equal(t, "", GetFail(msgs[0].Get(1)))
+	assert.Equal(t, "expected record of length 17, got 9", GetFail(msgs[0].Get(2)))
+	assert.Equal(t, "0014short", string(msgs[0].Get(2).Get()))
+	assert.Equal(t, `field id: strconv.ParseInt: parsing "00x5": invalid syntax`, GetFail(msgs[0].Get(3)))
+}
+
+func TestParseFixedWidthFormat(t *testing.T) {
+	conf := fixedWidthTestConf()
+	conf.ParseFixedWidth.Operator = "format"
+
+	proc, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msgs, res := proc.ProcessMessage(message.New([][]byte{
+		[]byte(`{"id":12,"name":"foo","balance":12.5}`),
+		[]byte(`{"id":-3,"name":"barbaz"}`),
+		[]byte(`{"id":12,"name":"toolongname","balance":1}`),
+		[]byte(`{"id":1.5}`),
+		[]byte(`not json`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	assert.Equal(t, "0012foo    0012.5", string(msgs[0].Get(0).Get()))
+	assert.Equal(t, "-003barbaz       ", string(msgs[0].Get(1).Get()))
+	assert.Equal(t, "", GetFail(msgs[0].Get(0)))
+	assert.Equal(t, "", GetFail(msgs[0].Get(1)))
+	assert.Equal(t, "field name: value of length 11 exceeds field length 6", GetFail(msgs[0].Get(2)))
+	assert.Equal(t, "field id: expected integer value, got 1.5", GetFail(msgs[0].Get(3)))
+	assert.NotEqual(t, "", GetFail(msgs[0].Get(4)))
+}
+
+func TestParseFixedWidthEBCDIC(t *testing.T) {
+	conf := fixedWidthTestConf()
+	conf.ParseFixedWidth.Operator = "format"
+	conf.ParseFixedWidth.Encoding = "ebcdic"
+
+	formatter, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msgs, res := formatter.ProcessMessage(message.New([][]byte{
+		[]byte(`{"id":12,"name":"foo","balance":12.5}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	require.Equal(t, "", GetFail(msgs[0].Get(0)))
+
+	exp, err := charmap.CodePage037.NewEncoder().Bytes([]byte("0012foo    0012.5"))
+	require.NoError(t, err)
+	assert.Equal(t, exp, msgs[0].Get(0).Get())
+
+	conf.ParseFixedWidth.Operator = "parse"
+	parser, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msgs, res = parser.ProcessMessage(msgs[0])
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, `{"balance":12.5,"id":12,"name":"foo"}`, string(msgs[0].Get(0).Get()))
+}
+
+func TestParseFixedWidthBadConfig(t *testing.T) {
+	conf := fixedWidthTestConf()
+	conf.ParseFixedWidth.RecordLength = 10
+	_, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "record_length 10 is shorter than the end of the last field 17")
+
+	conf = fixedWidthTestConf()
+	conf.ParseFixedWidth.Fields[1].Type = "date"
+	_, err = New(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "field name: type not recognised: date")
+}
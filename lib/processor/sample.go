@@ -1,15 +1,21 @@
 package processor
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/response"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/OneOfOne/xxhash"
 )
 
 //------------------------------------------------------------------------------
@@ -17,15 +23,42 @@ import (
 func init() {
 	Constructors[TypeSample] = TypeSpec{
 		constructor: NewSample,
-		Status:      docs.StatusDeprecated,
-		Footnotes: `
-## Alternatives
-
-All functionality of this processor has been superseded by the
-[bloblang](/docs/components/processors/bloblang) processor.`,
+		Categories: []Category{
+			CategoryUtility,
+		},
+		Summary: `
+Retains a sample of messages and drops the rest, either at random or
+consistently based on a key.`,
+		Description: `
+The ` + "`rate`" + ` field sets the fraction of messages to retain. Dropped messages
+are acknowledged and therefore do not block the input.
+
+When the ` + "`key`" + ` field is set each message of a batch is sampled
+individually based on a hash of its key, and therefore messages sharing a key
+are either always retained or always dropped. The hash is stable, which means
+the same keys are retained across restarts and by every instance configured
+with the same rate, allowing the samples of multiple pipelines to be
+correlated.
+
+Without a key each batch is retained or dropped as a whole at random.`,
 		FieldSpecs: docs.FieldSpecs{
-			docs.FieldCommon("retain", "The percentage of messages to keep."),
-			docs.FieldCommon("seed", "A seed for pseudo-random sampling."),
+			docs.FieldFloat("rate", "The fraction of messages to retain, between 0 and 1.").AtVersion("3.51.0"),
+			docs.FieldString("key", "An optional key to sample messages by, when empty messages are sampled at random.", `${! meta("kafka_key") }`, `${! json("trace_id") }`).IsInterpolated().AtVersion("3.51.0"),
+			docs.FieldAdvanced("seed", "A seed for pseudo-random sampling, which only applies when a key is not set."),
+			docs.FieldDeprecated("retain"),
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Sampling Traces",
+				Summary: "Retain a consistent tenth of traces, where every event of a retained trace is kept in every stage of the pipeline.",
+				Config: `
+pipeline:
+  processors:
+    - sample:
+        rate: 0.1
+        key: ${! json("trace_id") }
+`,
+			},
 		},
 	}
 }
@@ -34,6 +67,8 @@ All functionality of this processor has been superseded by the
 
 // SampleConfig contains configuration fields for the Sample processor.
 type SampleConfig struct {
+	Rate       float64 `json:"rate" yaml:"rate"`
+	Key        string  `json:"key" yaml:"key"`
 	Retain     float64 `json:"retain" yaml:"retain"`
 	RandomSeed int64   `json:"seed" yaml:"seed"`
 }
@@ -41,6 +76,8 @@ type SampleConfig struct {
 // NewSampleConfig returns a SampleConfig with default values.
 func NewSampleConfig() SampleConfig {
 	return SampleConfig{
+		Rate:       0.1,
+		Key:        "",
 		Retain:     10.0, // 10%
 		RandomSeed: 0,
 	}
@@ -54,9 +91,10 @@ type Sample struct {
 	log   log.Modular
 	stats metrics.Type
 
-	retain float64
-	gen    *rand.Rand
-	mut    sync.Mutex
+	rate float64
+	key  *field.Expression
+	gen  *rand.Rand
+	mut  sync.Mutex
 
 	mCount     metrics.StatCounter
 	mDropped   metrics.StatCounter
@@ -68,13 +106,31 @@ type Sample struct {
 func NewSample(
 	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
 ) (Type, error) {
+	rate := conf.Sample.Rate
+	if conf.Sample.Retain != NewSampleConfig().Retain {
+		// TODO: V4 Remove the retain field.
+		rate = conf.Sample.Retain / 100.0
+	}
+	if rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("rate must be between 0 and 1, received %v", rate)
+	}
+
+	var key *field.Expression
+	if conf.Sample.Key != "" {
+		var err error
+		if key, err = bloblang.NewField(conf.Sample.Key); err != nil {
+			return nil, fmt.Errorf("failed to parse key expression: %v", err)
+		}
+	}
+
 	gen := rand.New(rand.NewSource(conf.Sample.RandomSeed))
 	return &Sample{
-		conf:   conf,
-		log:    log,
-		stats:  stats,
-		retain: conf.Sample.Retain / 100.0,
-		gen:    gen,
+		conf:  conf,
+		log:   log,
+		stats: stats,
+		rate:  rate,
+		key:   key,
+		gen:   gen,
 
 		mCount:     stats.GetCounter("count"),
 		mDropped:   stats.GetCounter("dropped"),
@@ -85,13 +141,40 @@ func NewSample(
 
 //------------------------------------------------------------------------------
 
+// keyedSamplingNorm is the constant factor to normalise a uint64 into the
+// [0.0, 1.0] range.
+const keyedSamplingNorm = 1.0 / float64(math.MaxUint64)
+
+func (s *Sample) retainKey(key string) bool {
+	return float64(xxhash.ChecksumString64(key))*keyedSamplingNorm < s.rate
+}
+
 // ProcessMessage applies the processor to a message, either creating >0
 // resulting messages or a response to be sent back to the message source.
 func (s *Sample) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
 	s.mCount.Incr(1)
+
+	if s.key != nil {
+		newMsg := message.New(nil)
+		for i := 0; i < msg.Len(); i++ {
+			if s.retainKey(s.key.String(i, msg)) {
+				newMsg.Append(msg.Get(i).Copy())
+			} else {
+				s.mDropped.Incr(1)
+			}
+		}
+		if newMsg.Len() == 0 {
+			return nil, response.NewAck()
+		}
+		s.mBatchSent.Incr(1)
+		s.mSent.Incr(int64(newMsg.Len()))
+		msgs := [1]types.Message{newMsg}
+		return msgs[:], nil
+	}
+
 	s.mut.Lock()
 	defer s.mut.Unlock()
-	if s.gen.Float64() > s.retain {
+	if s.gen.Float64() >= s.rate {
 		s.mDropped.Incr(1)
 		return nil, response.NewAck()
 	}
@@ -1,12 +1,16 @@
 package processor
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSample10Percent(t *testing.T) {
@@ -82,3 +86,76 @@ func TestSample24Percent(t *testing.T) {
 		t.Errorf("Sample error greater than margin: %v != %v", act, exp)
 	}
 }
+
+func TestSampleRate(t *testing.T) {
+	conf := NewConfig()
+	conf.Sample.Rate = 0.3
+
+	proc, err := NewSample(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	total := 100000
+	totalSampled := 0
+	for i := 0; i < total; i++ {
+		msgs, res := proc.ProcessMessage(message.New([][]byte{[]byte("foo")}))
+		if len(msgs) > 0 {
+			totalSampled++
+		} else {
+			require.NotNil(t, res)
+			assert.NoError(t, res.Error())
+		}
+	}
+	assert.InDelta(t, 0.3, float64(totalSampled)/float64(total), 0.01)
+}
+
+func TestSampleKeyed(t *testing.T) {
+	conf := NewConfig()
+	conf.Sample.Rate = 0.5
+	conf.Sample.Key = `${! json("id") }`
+
+	proc, err := NewSample(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	retained := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("id%v", i)
+		msgs, res := proc.ProcessMessage(message.New([][]byte{
+			[]byte(fmt.Sprintf(`{"id":%q}`, id)),
+		}))
+		if len(msgs) > 0 {
+			retained[id] = true
+		} else {
+			require.NotNil(t, res)
+			assert.NoError(t, res.Error())
+		}
+	}
+	assert.InDelta(t, 500, len(retained), 50)
+
+	// A new instance must make the same decisions, including for messages of
+	// a batch.
+	proc, err = NewSample(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	var batch [][]byte
+	for i := 0; i < 1000; i++ {
+		batch = append(batch, []byte(fmt.Sprintf(`{"id":"id%v"}`, i)))
+	}
+	msgs, res := proc.ProcessMessage(message.New(batch))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	require.Equal(t, len(retained), msgs[0].Len())
+	_ = msgs[0].Iter(func(i int, p types.Part) error {
+		v, err := p.JSON()
+		require.NoError(t, err)
+		assert.True(t, retained[v.(map[string]interface{})["id"].(string)])
+		return nil
+	})
+}
+
+func TestSampleBadRate(t *testing.T) {
+	conf := NewConfig()
+	conf.Sample.Rate = 1.5
+
+	_, err := NewSample(conf, nil, log.Noop(), metrics.Noop())
+	require.EqualError(t, err, "rate must be between 0 and 1, received 1.5")
+}
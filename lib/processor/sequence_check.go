@@ -0,0 +1,240 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/opentracing/opentracing-go"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	Constructors[TypeSequenceCheck] = TypeSpec{
+		constructor: NewSequenceCheck,
+		Categories: []Category{
+			CategoryUtility,
+		},
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Summary: `
+Checks that an integer sequence field of messages increases monotonically per
+key, flagging messages that arrive out of order or that skip values.`,
+		Description: `
+The last sequence value seen for each key is tracked and each message is checked
+against it. A message is flagged as having failed when its sequence value is not
+greater than the last value seen for its key, or when ` + "`check_gaps`" + ` is
+enabled and its value is greater than the next expected value. Failed messages
+can be routed or dropped using [error handling](/docs/configuration/error_handling)
+patterns, and the ` + "`error`" + ` function describes the violation.
+
+Messages that are out of order do not update the last value seen for their key,
+whereas a gap moves the expected value past the skipped values so that only the
+first message after a gap is flagged.
+
+The number of keys tracked is bounded by ` + "`max_keys`" + `, and when the
+limit is reached the least recently seen key is forgotten. A forgotten key is
+treated as new when it is seen again, and therefore its next message is always
+accepted.
+
+State is held in memory by each processor instance, and so in order to check a
+single ordered stream this processor should be placed within the
+` + "`processors`" + ` of an input, or ` + "`pipeline.threads`" + ` should be
+set to one.`,
+		FieldSpecs: docs.FieldSpecs{
+			docs.FieldCommon("sequence", "An interpolated string that resolves to the integer sequence value of a message.", `${! json("seq") }`, `${! meta("kafka_offset") }`).IsInterpolated(),
+			docs.FieldCommon("key", "An optional interpolated string that resolves to the key of the sequence a message belongs to. When empty all messages belong to a single sequence.", `${! meta("kafka_partition") }`).IsInterpolated(),
+			docs.FieldCommon("check_gaps", "Whether to flag messages that skip values of the sequence."),
+			docs.FieldAdvanced("max_keys", "The maximum number of keys to track the last sequence value of."),
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title:   "Detect Reordering",
+				Summary: "Events carry a per device sequence number, and events that arrive out of order or after gaps are logged and dropped.",
+				Config: `
+input:
+  kafka:
+    addresses: [ TODO ]
+    topics: [ device_events ]
+    consumer_group: benthos_group
+  processors:
+    - sequence_check:
+        sequence: ${! json("seq") }
+        key: ${! json("device_id") }
+    - catch:
+      - log:
+          message: "Sequence violation: ${! error() }"
+      - bloblang: root = deleted()
+`,
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// SequenceCheckConfig contains configuration fields for the SequenceCheck
+// processor.
+type SequenceCheckConfig struct {
+	Sequence  string `json:"sequence" yaml:"sequence"`
+	Key       string `json:"key" yaml:"key"`
+	CheckGaps bool   `json:"check_gaps" yaml:"check_gaps"`
+	MaxKeys   int    `json:"max_keys" yaml:"max_keys"`
+}
+
+// NewSequenceCheckConfig returns a SequenceCheckConfig with default values.
+func NewSequenceCheckConfig() SequenceCheckConfig {
+	return SequenceCheckConfig{
+		Sequence:  "",
+		Key:       "",
+		CheckGaps: true,
+		MaxKeys:   1000,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// SequenceCheck is a processor that flags messages that break the order of a
+// monotonically increasing sequence.
+type SequenceCheck struct {
+	conf  SequenceCheckConfig
+	log   log.Modular
+	stats metrics.Type
+
+	sequence *field.Expression
+	key      *field.Expression
+
+	lastMut sync.Mutex
+	last    *simplelru.LRU
+
+	mCount      metrics.StatCounter
+	mErr        metrics.StatCounter
+	mOutOfOrder metrics.StatCounter
+	mGap        metrics.StatCounter
+	mSent       metrics.StatCounter
+	mBatchSent  metrics.StatCounter
+}
+
+// NewSequenceCheck returns a SequenceCheck processor.
+func NewSequenceCheck(
+	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
+) (Type, error) {
+	if conf.SequenceCheck.Sequence == "" {
+		return nil, errors.New("a sequence expression must be specified")
+	}
+	if conf.SequenceCheck.MaxKeys <= 0 {
+		return nil, errors.New("max_keys must be greater than zero")
+	}
+
+	s := &SequenceCheck{
+		conf:  conf.SequenceCheck,
+		log:   log,
+		stats: stats,
+
+		mCount:      stats.GetCounter("count"),
+		mErr:        stats.GetCounter("error"),
+		mOutOfOrder: stats.GetCounter("out_of_order"),
+		mGap:        stats.GetCounter("gap"),
+		mSent:       stats.GetCounter("sent"),
+		mBatchSent:  stats.GetCounter("batch.sent"),
+	}
+
+	var err error
+	if s.sequence, err = bloblang.NewField(conf.SequenceCheck.Sequence); err != nil {
+		return nil, fmt.Errorf("failed to parse sequence expression: %v", err)
+	}
+	if conf.SequenceCheck.Key != "" {
+		if s.key, err = bloblang.NewField(conf.SequenceCheck.Key); err != nil {
+			return nil, fmt.Errorf("failed to parse key expression: %v", err)
+		}
+	}
+	if s.last, err = simplelru.NewLRU(conf.SequenceCheck.MaxKeys, nil); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s *SequenceCheck) check(key string, seq int64) error {
+	s.lastMut.Lock()
+	defer s.lastMut.Unlock()
+
+	if v, exists := s.last.Get(key); exists {
+		last := v.(int64)
+		if seq <= last {
+			s.mOutOfOrder.Incr(1)
+			return fmt.Errorf("sequence value %v of key '%v' is out of order, last seen value was %v", seq, key, last)
+		}
+		if s.conf.CheckGaps && seq > last+1 {
+			s.last.Add(key, seq)
+			s.mGap.Incr(1)
+			return fmt.Errorf("sequence gap detected for key '%v', expected %v but got %v", key, last+1, seq)
+		}
+	}
+	s.last.Add(key, seq)
+	return nil
+}
+
+// ProcessMessage applies the processor to a message, either creating >0
+// resulting messages or a response to be sent back to the message source.
+func (s *SequenceCheck) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	s.mCount.Incr(1)
+
+	if msg.Len() == 0 {
+		return nil, response.NewAck()
+	}
+
+	newMsg := msg.Copy()
+
+	proc := func(index int, span opentracing.Span, part types.Part) error {
+		seqStr := strings.TrimSpace(s.sequence.String(index, newMsg))
+		seq, err := strconv.ParseInt(seqStr, 10, 64)
+		if err != nil {
+			s.log.Debugf("Failed to parse sequence value: %v\n", err)
+			s.mErr.Incr(1)
+			return fmt.Errorf("failed to parse sequence value: %w", err)
+		}
+
+		var key string
+		if s.key != nil {
+			key = s.key.String(index, newMsg)
+		}
+		if err = s.check(key, seq); err != nil {
+			s.log.Debugf("Sequence check failed: %v\n", err)
+			s.mErr.Incr(1)
+			return err
+		}
+		return nil
+	}
+
+	IteratePartsWithSpan(TypeSequenceCheck, nil, newMsg, proc)
+
+	s.mBatchSent.Incr(1)
+	s.mSent.Incr(int64(newMsg.Len()))
+	msgs := [1]types.Message{newMsg}
+	return msgs[:], nil
+}
+
+// CloseAsync shuts down the processor and stops processing requests.
+func (s *SequenceCheck) CloseAsync() {
+}
+
+// WaitForClose blocks until the processor has closed down.
+func (s *SequenceCheck) WaitForClose(timeout time.Duration) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
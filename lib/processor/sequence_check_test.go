@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequenceCheck(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = TypeSequenceCheck
+	conf.SequenceCheck.Sequence = `${! json("seq") }`
+	conf.SequenceCheck.Key = `${! json("key") }`
+
+	proc, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msgs, res := proc.ProcessMessage(message.New([][]byte{
+		[]byte(`{"key":"a","seq":1}`),
+		[]byte(`{"key":"b","seq":10}`),
+		[]byte(`{"key":"a","seq":2}`),
+		[]byte(`{"key":"a","seq":2}`),
+		[]byte(`{"key":"b","seq":13}`),
+		[]byte(`{"key":"b","seq":14}`),
+		[]byte(`{"key":"a","seq":"nope"}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	errs := []string{}
+	for i := 0; i < msgs[0].Len(); i++ {
+		errs = append(errs, GetFail(msgs[0].Get(i)))
+	}
+	assert.Equal(t, []string{
+		"",
+		"",
+		"",
+		"sequence value 2 of key 'a' is out of order, last seen value was 2",
+		"sequence gap detected for key 'b', expected 11 but got 13",
+		"",
+		`failed to parse sequence value: strconv.ParseInt: parsing "nope": invalid syntax`,
+	}, errs)
+
+	msgs, res = proc.ProcessMessage(message.New([][]byte{
+		[]byte(`{"key":"a","seq":1}`),
+		[]byte(`{"key":"a","seq":3}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "sequence value 1 of key 'a' is out of order, last seen value was 2", GetFail(msgs[0].Get(0)))
+	assert.Equal(t, "", GetFail(msgs[0].Get(1)))
+}
+
+func TestSequenceCheckMaxKeys(t *testing.T) {
+	conf := NewConfig()
+	conf.Type = TypeSequenceCheck
+	conf.SequenceCheck.Sequence = `${! json("seq") }`
+	conf.SequenceCheck.Key = `${! json("key") }`
+	conf.SequenceCheck.CheckGaps = false
+	conf.SequenceCheck.MaxKeys = 1
+
+	proc, err := New(conf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	msgs, res := proc.ProcessMessage(message.New([][]byte{
+		[]byte(`{"key":"a","seq":5}`),
+		[]byte(`{"key":"a","seq":9}`),
+		[]byte(`{"key":"b","seq":1}`),
+		[]byte(`{"key":"a","seq":1}`),
+		[]byte(`{"key":"a","seq":1}`),
+	}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	errs := []string{}
+	for i := 0; i < msgs[0].Len(); i++ {
+		errs = append(errs, GetFail(msgs[0].Get(i)))
+	}
+	assert.Equal(t, []string{
+		"",
+		"",
+		"",
+		"",
+		"sequence value 1 of key 'a' is out of order, last seen value was 1",
+	}, errs)
+}
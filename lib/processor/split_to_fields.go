@@ -0,0 +1,343 @@
+package processor
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/opentracing/opentracing-go"
+)
+
+//------------------------------------------------------------------------------
+
+func init() {
+	Constructors[TypeSplitToFields] = TypeSpec{
+		constructor: NewSplitToFields,
+		Categories: []Category{
+			CategoryParsing,
+		},
+		Status:  docs.StatusExperimental,
+		Version: "3.51.0",
+		Summary: `
+Parses messages consisting of delimited key/value pairs, such as
+` + "`level=info user=\"Jane Doe\" took=12`" + `, into structured objects.`,
+		Description: `
+Each message is split into fragments by the ` + "`pair_delimiter`" + `, and each
+fragment is split into a key and a value by the first occurrence of the
+` + "`kv_delimiter`" + `. Empty fragments, such as those produced by consecutive
+delimiters, are ignored, and whitespace surrounding keys and values is removed.
+When a key appears more than once the last value wins.
+
+Keys and values can be wrapped in any of the ` + "`quote_chars`" + `, in which
+case delimiters within them are treated as regular characters. Within quotes a
+backslash escapes the quote character or another backslash. A quote that is
+never closed extends to the end of the message.
+
+Fragments that do not contain the ` + "`kv_delimiter`" + `, or that have an
+empty key, are ignored unless ` + "`unparsed_field`" + ` is set, in which case
+they are added to an array at that key. Messages that contain no key/value pairs
+at all are left unchanged and flagged as having failed, and can be handled using
+[error handling](/docs/configuration/error_handling) patterns.
+
+### Type Inference
+
+By default all values are parsed as strings. When ` + "`infer_types`" + ` is
+` + "`true`" + ` values that are not quoted are parsed as integers, floats or
+booleans (` + "`true` or `false`" + `) where possible, and values that are
+quoted always remain strings.`,
+		FieldSpecs: docs.FieldSpecs{
+			docs.FieldCommon("pair_delimiter", "The delimiter that separates key/value pairs."),
+			docs.FieldCommon("kv_delimiter", "The delimiter that separates a key from its value."),
+			docs.FieldAdvanced("quote_chars", "A string of characters that can be used to quote keys and values, or an empty string in order to disable quoting."),
+			docs.FieldCommon("infer_types", "Whether to parse values that are not quoted as numbers or booleans where possible."),
+			docs.FieldAdvanced("unparsed_field", "An optional field to add fragments that are not key/value pairs to as an array of strings. When empty these fragments are ignored."),
+		},
+		Examples: []docs.AnnotatedExample{
+			{
+				Title: "Parse Access Logs",
+				Summary: `
+Log lines such as ` + "`ts=2021-07-20T10:01:22Z method=GET path=\"/foo bar\" status=200 cached`" + `
+are parsed into JSON documents, where fragments that are not key/value pairs
+are kept within the field ` + "`flags`" + `.`,
+				Config: `
+pipeline:
+  processors:
+    - split_to_fields:
+        infer_types: true
+        unparsed_field: flags
+`,
+			},
+			{
+				Title:   "Comma Separated Pairs",
+				Summary: "Lines such as `host: db-1, region: 'eu west', healthy: false` are parsed with custom delimiters and quotes.",
+				Config: `
+pipeline:
+  processors:
+    - split_to_fields:
+        pair_delimiter: ","
+        kv_delimiter: ":"
+        quote_chars: "\"'"
+        infer_types: true
+`,
+			},
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// SplitToFieldsConfig contains configuration fields for the SplitToFields
+// processor.
+type SplitToFieldsConfig struct {
+	PairDelimiter string `json:"pair_delimiter" yaml:"pair_delimiter"`
+	KVDelimiter   string `json:"kv_delimiter" yaml:"kv_delimiter"`
+	QuoteChars    string `json:"quote_chars" yaml:"quote_chars"`
+	InferTypes    bool   `json:"infer_types" yaml:"infer_types"`
+	UnparsedField string `json:"unparsed_field" yaml:"unparsed_field"`
+}
+
+// NewSplitToFieldsConfig returns a SplitToFieldsConfig with default values.
+func NewSplitToFieldsConfig() SplitToFieldsConfig {
+	return SplitToFieldsConfig{
+		PairDelimiter: " ",
+		KVDelimiter:   "=",
+		QuoteChars:    `"`,
+		InferTypes:    false,
+		UnparsedField: "",
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// SplitToFields is a processor that parses delimited key/value pairs into
+// structured objects.
+type SplitToFields struct {
+	conf  SplitToFieldsConfig
+	log   log.Modular
+	stats metrics.Type
+
+	mCount     metrics.StatCounter
+	mErr       metrics.StatCounter
+	mSent      metrics.StatCounter
+	mBatchSent metrics.StatCounter
+}
+
+// NewSplitToFields returns a SplitToFields processor.
+func NewSplitToFields(
+	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
+) (Type, error) {
+	pConf := conf.SplitToFields
+	if pConf.PairDelimiter == "" {
+		return nil, errors.New("pair_delimiter must not be empty")
+	}
+	if pConf.KVDelimiter == "" {
+		return nil, errors.New("kv_delimiter must not be empty")
+	}
+	if pConf.PairDelimiter == pConf.KVDelimiter {
+		return nil, errors.New("pair_delimiter and kv_delimiter must be different")
+	}
+	for _, c := range pConf.QuoteChars {
+		if c == '\\' || strings.ContainsRune(pConf.PairDelimiter, c) || strings.ContainsRune(pConf.KVDelimiter, c) {
+			return nil, errors.New("quote_chars must not contain a backslash or characters of the delimiters")
+		}
+	}
+	return &SplitToFields{
+		conf:  pConf,
+		log:   log,
+		stats: stats,
+
+		mCount:     stats.GetCounter("count"),
+		mErr:       stats.GetCounter("error"),
+		mSent:      stats.GetCounter("sent"),
+		mBatchSent: stats.GetCounter("batch.sent"),
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+var (
+	splitToFieldsIntRegexp   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	splitToFieldsFloatRegexp = regexp.MustCompile(`^[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`)
+)
+
+func splitToFieldsInfer(v string) interface{} {
+	switch v {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if splitToFieldsIntRegexp.MatchString(v) {
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	}
+	if splitToFieldsFloatRegexp.MatchString(v) {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// splitToFieldsToken accumulates either the key or the value of a fragment,
+// where whitespace outside of quotes is trimmed from both ends.
+type splitToFieldsToken struct {
+	buf    []byte
+	end    int
+	quoted bool
+}
+
+func (t *splitToFieldsToken) writeUnquoted(b byte) {
+	if unicode.IsSpace(rune(b)) {
+		if len(t.buf) > 0 {
+			t.buf = append(t.buf, b)
+		}
+		return
+	}
+	t.buf = append(t.buf, b)
+	t.end = len(t.buf)
+}
+
+func (t *splitToFieldsToken) writeQuoted(b byte) {
+	t.buf = append(t.buf, b)
+	t.end = len(t.buf)
+}
+
+func (t *splitToFieldsToken) String() string {
+	return string(t.buf[:t.end])
+}
+
+func (t *splitToFieldsToken) reset() {
+	t.buf, t.end, t.quoted = t.buf[:0], 0, false
+}
+
+func (s *SplitToFields) parse(line string) (map[string]interface{}, bool) {
+	obj := map[string]interface{}{}
+	var unparsed []interface{}
+	found := false
+
+	var key, value splitToFieldsToken
+	fragStart, seenKV := 0, false
+	var quote byte
+
+	flush := func(fragEnd int) {
+		k := key.String()
+		switch {
+		case seenKV && k != "":
+			v := value.String()
+			if s.conf.InferTypes && !value.quoted {
+				obj[k] = splitToFieldsInfer(v)
+			} else {
+				obj[k] = v
+			}
+			found = true
+		default:
+			if raw := strings.TrimSpace(line[fragStart:fragEnd]); raw != "" {
+				unparsed = append(unparsed, raw)
+			}
+		}
+		key.reset()
+		value.reset()
+		seenKV = false
+	}
+
+	current := func() *splitToFieldsToken {
+		if seenKV {
+			return &value
+		}
+		return &key
+	}
+
+	for i := 0; i < len(line); {
+		c := line[i]
+		if quote != 0 {
+			switch {
+			case c == '\\' && i+1 < len(line) && (line[i+1] == quote || line[i+1] == '\\'):
+				current().writeQuoted(line[i+1])
+				i += 2
+			case c == quote:
+				quote = 0
+				i++
+			default:
+				current().writeQuoted(c)
+				i++
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line[i:], s.conf.PairDelimiter):
+			flush(i)
+			i += len(s.conf.PairDelimiter)
+			fragStart = i
+		case !seenKV && strings.HasPrefix(line[i:], s.conf.KVDelimiter):
+			seenKV = true
+			i += len(s.conf.KVDelimiter)
+		case strings.IndexByte(s.conf.QuoteChars, c) >= 0:
+			quote = c
+			current().quoted = true
+			i++
+		default:
+			current().writeUnquoted(c)
+			i++
+		}
+	}
+	flush(len(line))
+
+	if s.conf.UnparsedField != "" && len(unparsed) > 0 {
+		obj[s.conf.UnparsedField] = unparsed
+	}
+	return obj, found
+}
+
+// ProcessMessage applies the processor to a message, either creating >0
+// resulting messages or a response to be sent back to the message source.
+func (s *SplitToFields) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	s.mCount.Incr(1)
+
+	if msg.Len() == 0 {
+		return nil, response.NewAck()
+	}
+
+	newMsg := msg.Copy()
+
+	proc := func(index int, span opentracing.Span, part types.Part) error {
+		obj, found := s.parse(strings.TrimRight(string(part.Get()), "\r\n"))
+		if !found {
+			s.log.Debugln("Failed to find key/value pairs within message")
+			s.mErr.Incr(1)
+			return errors.New("no key/value pairs found")
+		}
+		if err := part.SetJSON(obj); err != nil {
+			s.mErr.Incr(1)
+			return err
+		}
+		return nil
+	}
+
+	IteratePartsWithSpan(TypeSplitToFields, nil, newMsg, proc)
+
+	s.mBatchSent.Incr(1)
+	s.mSent.Incr(int64(newMsg.Len()))
+	msgs := [1]types.Message{newMsg}
+	return msgs[:], nil
+}
+
+// CloseAsync shuts down the processor and stops processing requests.
+func (s *SplitToFields) CloseAsync() {
+}
+
+// WaitForClose blocks until the processor has closed down.
+func (s *SplitToFields) WaitForClose(timeout time.Duration) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
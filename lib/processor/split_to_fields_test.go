@@ -0,0 +1,153 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitToFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		conf     func(c *SplitToFieldsConfig)
+		input    string
+		output   string
+		errCheck string
+	}{
+		{
+			name:   "basic pairs",
+			input:  `level=info msg=hello took=12`,
+			output: `{"level":"info","msg":"hello","took":"12"}`,
+		},
+		{
+			name:   "quoted values",
+			input:  `msg="hello world"  path="/a=b" quote="say \"hi\"" empty=""`,
+			output: `{"empty":"","msg":"hello world","path":"/a=b","quote":"say \"hi\""}`,
+		},
+		{
+			name:   "quoted keys",
+			input:  `"full name"=foo bar=baz`,
+			output: `{"bar":"baz","full name":"foo"}`,
+		},
+		{
+			name:   "unterminated quote",
+			input:  `a=b msg="hello world`,
+			output: `{"a":"b","msg":"hello world"}`,
+		},
+		{
+			name:   "last value wins",
+			input:  `a=1 a=2`,
+			output: `{"a":"2"}`,
+		},
+		{
+			name:   "value containing kv delimiter",
+			input:  `query=a=b`,
+			output: `{"query":"a=b"}`,
+		},
+		{
+			name: "infer types",
+			conf: func(c *SplitToFieldsConfig) {
+				c.InferTypes = true
+			},
+			input:  `a=12 b=-1.5 c=true d=false e="12" f=1e3 g=nan h=0x10 i=`,
+			output: `{"a":12,"b":-1.5,"c":true,"d":false,"e":"12","f":1000,"g":"nan","h":"0x10","i":""}`,
+		},
+		{
+			name:   "unparsed fragments ignored",
+			input:  `GET a=b =c cached`,
+			output: `{"a":"b"}`,
+		},
+		{
+			name: "unparsed fragments kept",
+			conf: func(c *SplitToFieldsConfig) {
+				c.UnparsedField = "flags"
+			},
+			input:  `GET a=b =c cached`,
+			output: `{"a":"b","flags":["GET","=c","cached"]}`,
+		},
+		{
+			name: "custom delimiters",
+			conf: func(c *SplitToFieldsConfig) {
+				c.PairDelimiter = ","
+				c.KVDelimiter = ":"
+				c.QuoteChars = `"'`
+				c.InferTypes = true
+			},
+			input:  "host: db-1, region: 'eu, west' , healthy: false\r\n",
+			output: `{"healthy":false,"host":"db-1","region":"eu, west"}`,
+		},
+		{
+			name: "multi character delimiters",
+			conf: func(c *SplitToFieldsConfig) {
+				c.PairDelimiter = " | "
+				c.KVDelimiter = "=>"
+			},
+			input:  `a=>b | c => d e`,
+			output: `{"a":"b","c":"d e"}`,
+		},
+		{
+			name: "quotes disabled",
+			conf: func(c *SplitToFieldsConfig) {
+				c.QuoteChars = ""
+			},
+			input:  `a="b c"`,
+			output: `{"a":"\"b"}`,
+		},
+		{
+			name:     "no pairs",
+			input:    `just some text`,
+			output:   `just some text`,
+			errCheck: "no key/value pairs found",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := NewConfig()
+			conf.Type = TypeSplitToFields
+			if test.conf != nil {
+				test.conf(&conf.SplitToFields)
+			}
+
+			proc, err := New(conf, nil, log.Noop(), metrics.Noop())
+			require.NoError(t, err)
+
+			msgs, res := proc.ProcessMessage(message.New([][]byte{[]byte(test.input)}))
+			require.Nil(t, res)
+			require.Len(t, msgs, 1)
+			assert.Equal(t, test.output, string(msgs[0].Get(0).Get()))
+			assert.Equal(t, test.errCheck, GetFail(msgs[0].Get(0)))
+		})
+	}
+}
+
+func TestSplitToFieldsBadConfig(t *testing.T) {
+	tests := map[string]func(c *SplitToFieldsConfig){
+		"empty pair delimiter": func(c *SplitToFieldsConfig) {
+			c.PairDelimiter = ""
+		},
+		"empty kv delimiter": func(c *SplitToFieldsConfig) {
+			c.KVDelimiter = ""
+		},
+		"same delimiters": func(c *SplitToFieldsConfig) {
+			c.KVDelimiter = " "
+		},
+		"quote within delimiter": func(c *SplitToFieldsConfig) {
+			c.QuoteChars = `"=`
+		},
+	}
+
+	for name, fn := range tests {
+		conf := NewConfig()
+		conf.Type = TypeSplitToFields
+		fn(&conf.SplitToFields)
+
+		_, err := New(conf, nil, log.Noop(), metrics.Noop())
+		assert.Error(t, err, name)
+	}
+}
@@ -28,7 +28,7 @@ func TestSwitchCases(t *testing.T) {
 
 	procConf := NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 0: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 0: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -39,7 +39,7 @@ func TestSwitchCases(t *testing.T) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 1: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 1: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -50,7 +50,7 @@ func TestSwitchCases(t *testing.T) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 2: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 2: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -147,7 +147,7 @@ func TestSwitchError(t *testing.T) {
 
 	procConf := NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 0: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 0: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -158,7 +158,7 @@ func TestSwitchError(t *testing.T) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 1: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 1: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -208,7 +208,7 @@ func BenchmarkSwitch10(b *testing.B) {
 
 	procConf := NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 0: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 0: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -219,7 +219,7 @@ func BenchmarkSwitch10(b *testing.B) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 1: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 1: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -230,7 +230,7 @@ func BenchmarkSwitch10(b *testing.B) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 2: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 2: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -287,7 +287,7 @@ func BenchmarkSwitch1(b *testing.B) {
 
 	procConf := NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 0: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 0: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -298,7 +298,7 @@ func BenchmarkSwitch1(b *testing.B) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 1: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 1: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -309,7 +309,7 @@ func BenchmarkSwitch1(b *testing.B) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 2: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 2: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   defaultCaseCond(),
@@ -370,7 +370,7 @@ func BenchmarkSwitchDeprecated1(b *testing.B) {
 
 	procConf := NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 0: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 0: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   condConf,
@@ -384,7 +384,7 @@ func BenchmarkSwitchDeprecated1(b *testing.B) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 1: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 1: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   condConf,
@@ -398,7 +398,7 @@ func BenchmarkSwitchDeprecated1(b *testing.B) {
 
 	procConf = NewConfig()
 	procConf.Type = TypeBloblang
-	procConf.Bloblang = `root = "Hit case 2: " + content().string()`
+	procConf.Bloblang.Mapping = `root = "Hit case 2: " + content().string()`
 
 	conf.Switch = append(conf.Switch, SwitchCaseConfig{
 		Condition:   condConf,
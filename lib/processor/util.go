@@ -125,6 +125,7 @@ var FailFlagKey = types.FailFlagKey
 
 // FlagFail marks a message part as having failed at a processing step.
 func FlagFail(part types.Part) {
+	clearErrorSource(part)
 	part.Metadata().Set(FailFlagKey, "true")
 }
 
@@ -132,6 +133,7 @@ func FlagFail(part types.Part) {
 // error message. If the error is nil the message part remains unchanged.
 func FlagErr(part types.Part, err error) {
 	if err != nil {
+		clearErrorSource(part)
 		part.Metadata().Set(FailFlagKey, err.Error())
 	}
 }
@@ -147,8 +149,10 @@ func HasFailed(part types.Part) bool {
 	return len(part.Metadata().Get(FailFlagKey)) > 0
 }
 
-// ClearFail removes any existing failure flags from a message part.
+// ClearFail removes any existing failure flags from a message part, including
+// the source of the failure.
 func ClearFail(part types.Part) {
+	clearErrorSource(part)
 	part.Metadata().Delete(FailFlagKey)
 }
 
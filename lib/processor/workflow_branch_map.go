@@ -182,6 +182,15 @@ func (r *resourcedBranch) lock() (branch *Branch, unlockFn func()) {
 
 	go func() {
 		_ = interop.AccessProcessor(context.Background(), r.mgr, r.name, func(p types.Processor) {
+			for {
+				u, ok := p.(interface {
+					Unwrap() types.Processor
+				})
+				if !ok {
+					break
+				}
+				p = u.Unwrap()
+			}
 			branch, _ = p.(*Branch)
 			openOnce.Do(func() {
 				close(open)
@@ -172,7 +172,7 @@ func TestWorkflowDeps(t *testing.T) {
 				branchConf.ResultMap = mappings[1]
 				dudProc := NewConfig()
 				dudProc.Type = TypeBloblang
-				dudProc.Bloblang = BloblangConfig("root = this")
+				dudProc.Bloblang.Mapping = "root = this"
 				branchConf.Processors = append(branchConf.Processors, dudProc)
 				conf.Workflow.Branches[strconv.Itoa(j)] = branchConf
 			}
@@ -214,7 +214,7 @@ func quickTestBranches(branches ...[4]string) map[string]Config {
 	for _, b := range branches {
 		blobConf := NewConfig()
 		blobConf.Type = TypeBloblang
-		blobConf.Bloblang = BloblangConfig(b[2])
+		blobConf.Bloblang.Mapping = b[2]
 
 		conf := NewConfig()
 		conf.Type = TypeBranch
@@ -239,7 +239,7 @@ func TestWorkflowMissingResources(t *testing.T) {
 
 	blobConf := NewConfig()
 	blobConf.Type = TypeBloblang
-	blobConf.Bloblang = "root = this"
+	blobConf.Bloblang.Mapping = "root = this"
 
 	branchConf.Branch.Processors = append(branchConf.Branch.Processors, blobConf)
 
@@ -443,7 +443,7 @@ func TestWorkflows(t *testing.T) {
 				branchConf.ResultMap = mappings[2]
 				proc := NewConfig()
 				proc.Type = TypeBloblang
-				proc.Bloblang = BloblangConfig(mappings[1])
+				proc.Bloblang.Mapping = mappings[1]
 				branchConf.Processors = append(branchConf.Processors, proc)
 				conf.Workflow.Branches[strconv.Itoa(j)] = branchConf
 			}
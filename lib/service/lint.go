@@ -2,6 +2,7 @@ package service
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/config"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -19,6 +21,7 @@ import (
 
 var red = color.New(color.FgRed).SprintFunc()
 var yellow = color.New(color.FgYellow).SprintFunc()
+var cyan = color.New(color.FgCyan).SprintFunc()
 
 func resolveLintPath(path string) (string, bool) {
 	recurse := false
@@ -34,15 +37,54 @@ func resolveLintPath(path string) (string, bool) {
 }
 
 type pathLint struct {
-	source string
-	line   int
-	lint   string
-	err    string
+	source  string
+	line    int
+	lint    string
+	err     string
+	warning bool
+
+	// structured is the original lint when the lint was obtained from a
+	// config spec, which is reported as is with the json format.
+	structured *docs.Lint
+}
+
+// jsonPathLint is the structure of lints reported with the json format.
+type jsonPathLint struct {
+	Path        string `json:"path"`
+	SnippetLine int    `json:"snippet_line,omitempty"`
+	docs.Lint
+}
+
+func (p pathLint) toJSON() jsonPathLint {
+	lint := docs.NewLintError(0, p.lint)
+	if len(p.err) > 0 {
+		lint = docs.NewTypedLintError(0, docs.LintFailedRead, p.err)
+	} else if p.structured != nil {
+		lint = *p.structured
+	}
+	return jsonPathLint{
+		Path:        p.source,
+		SnippetLine: p.line,
+		Lint:        lint,
+	}
+}
+
+func lintsToPathLints(source string, line int, lints []docs.Lint) (pathLints []pathLint) {
+	for _, l := range lints {
+		l := l
+		pathLints = append(pathLints, pathLint{
+			source:     source,
+			line:       line,
+			lint:       fmt.Sprintf("line %v: %v", l.Line, l.What),
+			warning:    l.Level == docs.LintWarning,
+			structured: &l,
+		})
+	}
+	return
 }
 
 func lintFile(path string) (pathLints []pathLint) {
-	conf := config.New()
-	lints, err := config.Read(path, true, &conf)
+	configBytes, lints, err := config.ReadWithJSONPointersLinted(path, true)
 	if err != nil {
 		pathLints = append(pathLints, pathLint{
 			source: path,
@@ -56,6 +98,25 @@ func lintFile(path string) (pathLints []pathLint) {
 			lint:   l,
 		})
 	}
+
+	conf := config.New()
+	if err := yaml.Unmarshal(configBytes, &conf); err != nil {
+		pathLints = append(pathLints, pathLint{
+			source: path,
+			err:    err.Error(),
+		})
+		return
+	}
+
+	dLints, err := config.LintWithWarnings(configBytes, conf)
+	if err != nil {
+		pathLints = append(pathLints, pathLint{
+			source: path,
+			err:    err.Error(),
+		})
+		return
+	}
+	pathLints = append(pathLints, lintsToPathLints(path, 0, dLints)...)
 	return
 }
 
@@ -98,7 +159,7 @@ func lintMDSnippets(path string) (pathLints []pathLint) {
 				err:    err.Error(),
 			})
 		} else {
-			lints, err := config.Lint(configBytes, conf)
+			lints, err := config.LintWithWarnings(configBytes, conf)
 			if err != nil {
 				pathLints = append(pathLints, pathLint{
 					source: path,
@@ -106,13 +167,7 @@ func lintMDSnippets(path string) (pathLints []pathLint) {
 					err:    err.Error(),
 				})
 			}
-			for _, l := range lints {
-				pathLints = append(pathLints, pathLint{
-					source: path,
-					line:   snippetLine,
-					lint:   l,
-				})
-			}
+			pathLints = append(pathLints, lintsToPathLints(path, snippetLine, lints)...)
 		}
 
 		if nextSnippet = bytes.Index(rawBytes[endOfSnippet:], []byte("```yaml")); nextSnippet != -1 {
@@ -135,8 +190,34 @@ func lintCliCommand() *cli.Command {
    benthos lint ./configs/...
    
    If a path ends with '...' then Benthos will walk the target and lint any
-   files with the .yaml or .yml extension.`[4:],
+   files with the .yaml or .yml extension.
+
+   Linting warnings, such as fields that are ineffective and can be removed,
+   are also reported but do not result in a non-zero status code. Warnings can
+   be hidden with the --no-warnings flag.
+
+   With --format json the lints are instead written to stdout as a JSON array,
+   where each lint has a machine readable type such as unknown_field or
+   deprecated.`[4:],
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "no-warnings",
+				Value: false,
+				Usage: "Do not report linting warnings.",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "text",
+				Usage: "The format of reported lints, either text or json.",
+			},
+		},
 		Action: func(c *cli.Context) error {
+			format := c.String("format")
+			if format != "text" && format != "json" {
+				fmt.Fprintf(os.Stderr, "Format not recognised: %v\n", format)
+				os.Exit(1)
+			}
+
 			var targets []string
 			for _, p := range c.Args().Slice() {
 				var recurse bool
@@ -195,13 +276,27 @@ func lintCliCommand() *cli.Command {
 				}(i)
 			}
 			wg.Wait()
-			if len(pathLints) == 0 {
-				os.Exit(0)
-			}
+
+			noWarnings := c.Bool("no-warnings")
+			errCount := 0
+			jsonLints := []jsonPathLint{}
 			for _, lint := range pathLints {
+				if lint.warning {
+					if noWarnings {
+						continue
+					}
+				} else {
+					errCount++
+				}
+				if format == "json" {
+					jsonLints = append(jsonLints, lint.toJSON())
+					continue
+				}
 				message := yellow(lint.lint)
 				if len(lint.err) > 0 {
 					message = red(lint.err)
+				} else if lint.warning {
+					message = cyan("warning: " + lint.lint)
 				}
 				if lint.line > 0 {
 					fmt.Fprintf(os.Stderr, "%v: from snippet at line %v: %v\n", lint.source, lint.line, message)
@@ -209,6 +304,13 @@ func lintCliCommand() *cli.Command {
 					fmt.Fprintf(os.Stderr, "%v: %v\n", lint.source, message)
 				}
 			}
+			if format == "json" {
+				jsonBytes, _ := json.Marshal(jsonLints)
+				fmt.Println(string(jsonBytes))
+			}
+			if errCount == 0 {
+				os.Exit(0)
+			}
 			os.Exit(1)
 			return nil
 		},
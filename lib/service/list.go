@@ -128,5 +128,26 @@ func listComponents(c *cli.Context) {
 			panic(err)
 		}
 		fmt.Println(string(jsonBytes))
+	case "jsonschema":
+		provider := docs.NewMappedDocsProvider()
+		for _, specs := range [][]docs.ComponentSpec{
+			schema.Buffers,
+			schema.Caches,
+			schema.Inputs,
+			schema.Outputs,
+			schema.Processors,
+			schema.RateLimits,
+			schema.Metrics,
+			schema.Tracers,
+		} {
+			for _, spec := range specs {
+				provider.RegisterDocs(spec)
+			}
+		}
+		jsonBytes, err := docs.ConfigToJSONSchema(provider, schema.Config)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(jsonBytes))
 	}
 }
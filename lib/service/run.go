@@ -140,6 +140,11 @@ func Run() {
 			Aliases: []string{"r"},
 			Usage:   "pull in extra resources from a file, which can be referenced the same as resources defined in the main config, supports glob patterns (requires quotes)",
 		},
+		&cli.BoolFlag{
+			Name:  "watch-resources",
+			Value: false,
+			Usage: "EXPERIMENTAL: watch resource files for changes and reload the cache and rate limit resources they define without restarting",
+		},
 		&cli.StringSliceFlag{
 			Name:    "templates",
 			Aliases: []string{"t"},
@@ -212,6 +217,7 @@ func Run() {
 			os.Exit(cmdService(
 				c.String("config"),
 				c.StringSlice("resources"),
+				c.Bool("watch-resources"),
 				c.StringSlice("set"),
 				c.String("log.level"),
 				!c.Bool("chilled"),
@@ -276,6 +282,7 @@ func Run() {
 					os.Exit(cmdService(
 						c.String("config"),
 						c.StringSlice("resources"),
+						c.Bool("watch-resources"),
 						c.StringSlice("set"),
 						c.String("log.level"),
 						!c.Bool("chilled"),
@@ -294,12 +301,13 @@ func Run() {
 
    benthos list
    benthos list --format json inputs output
+   benthos list --format jsonschema
    benthos list rate-limits buffers`[4:],
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:  "format",
 						Value: "text",
-						Usage: "Print the component list in a specific format. Options are text or json.",
+						Usage: "Print the component list in a specific format. Options are text, json or jsonschema.",
 					},
 				},
 				Action: func(c *cli.Context) error {
@@ -340,7 +348,7 @@ func Run() {
 		}
 
 		deprecatedExecute(*configPath, testSuffix)
-		os.Exit(cmdService(*configPath, nil, nil, "", false, false, nil))
+		os.Exit(cmdService(*configPath, nil, false, nil, "", false, false, nil))
 		return nil
 	}
 
@@ -123,18 +123,32 @@ func readConfig(path string, resourcesPaths, overrides []string) (lints []string
 func cmdService(
 	confPath string,
 	resourcesPaths []string,
+	watchResources bool,
 	confOverrides []string,
 	overrideLogLevel string,
 	strict bool,
 	streamsMode bool,
 	streamsConfigs []string,
 ) int {
+	resourcesPatterns := resourcesPaths
+
 	var err error
 	if resourcesPaths, err = filepath.Globs(resourcesPaths); err != nil {
 		fmt.Printf("Failed to resolve resource glob pattern: %v\n", err)
 		return 1
 	}
 	lints := readConfig(confPath, resourcesPaths, confOverrides)
+
+	// The watcher reads the resource files immediately so that changes made
+	// after the config was read are not missed.
+	var resourceWatcher *iconfig.ResourceWatcher
+	if watchResources && len(resourcesPatterns) > 0 {
+		if resourceWatcher, err = iconfig.NewResourceWatcher(resourcesPatterns); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to watch resource files: %v\n", err)
+			return 1
+		}
+	}
+
 	if strict && len(lints) > 0 {
 		for _, lint := range lints {
 			fmt.Fprintln(os.Stderr, lint)
@@ -301,6 +315,18 @@ func cmdService(
 		}
 	}
 
+	// Watch resource files for changes until the service is closing.
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	watcherClosedChan := make(chan struct{})
+	if resourceWatcher != nil {
+		go func() {
+			resourceWatcher.Watch(watchCtx, manager, logger.NewModule(".resources"))
+			close(watcherClosedChan)
+		}()
+	} else {
+		close(watcherClosedChan)
+	}
+
 	// Defer clean up.
 	defer func() {
 		go func() {
@@ -326,6 +352,8 @@ func cmdService(
 		if err := dataStream.Stop(exitTimeout); err != nil {
 			os.Exit(1)
 		}
+		stopWatching()
+		<-watcherClosedChan
 		manager.CloseAsync()
 		if err := manager.WaitForClose(time.Until(timesOut)); err != nil {
 			logger.Warnf(
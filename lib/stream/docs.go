@@ -12,6 +12,19 @@ func Spec() docs.FieldSpecs {
 		docs.FieldCommon("pipeline", "Describes optional processing pipelines used for mutating messages.").WithChildren(
 			docs.FieldInt("threads", "The number of threads to execute processing pipelines across.").HasDefault(1),
 			docs.FieldCommon("processors", "A list of processors to apply to messages.").Array().HasType(docs.FieldTypeProcessor),
+			docs.FieldAdvanced(
+				"lanes", "An optional list of [processing lanes](/docs/configuration/processing_pipelines#processing-lanes), each with their own threads and processors, that messages are routed to based on the first lane check they pass. Messages that pass no checks are processed by the `threads` and `processors` of the pipeline itself.",
+			).Array().WithChildren(
+				docs.FieldString(
+					"check", "A [Bloblang query](/docs/guides/bloblang/about/) that should return a boolean value indicating whether a message should be processed within this lane. If left empty the check always passes.",
+					`meta("priority") == "high"`,
+				).HasDefault("").Linter(docs.LintBloblangMapping),
+				docs.FieldInt("threads", "The number of threads to execute the processors of this lane across.").HasDefault(1),
+				docs.FieldCommon("processors", "A list of processors to apply to messages within this lane.").Array().HasType(docs.FieldTypeProcessor),
+			).AtVersion("3.51.0"),
+			docs.FieldAdvanced(
+				"init", "An optional list of processors to execute once on a single empty message before the input is created, which can be used in order to warm up resources such as caches before any data is consumed. If any of these processors fail then the stream fails to start.",
+			).Array().HasType(docs.FieldTypeProcessor).AtVersion("3.51.0"),
 		),
 		docs.FieldCommon("output", "An output to sink messages to.").HasType(docs.FieldTypeOutput),
 	}
@@ -139,6 +139,15 @@ func (t *Type) IsReady() bool {
 }
 
 func (t *Type) start() (err error) {
+	// Init processors are executed before any other components are created
+	// so that inputs do not begin consuming data until they're complete.
+	if len(t.conf.Pipeline.Init) > 0 {
+		pMgr, pLog, pStats := interop.LabelChild("pipeline", t.manager, t.logger, t.stats)
+		if err = pipeline.RunInit(t.conf.Pipeline, pMgr, pLog, pStats); err != nil {
+			return
+		}
+	}
+
 	// Constructors
 	iMgr, iLog, iStats := interop.LabelChild("input", t.manager, t.logger, t.stats)
 	if t.inputLayer, err = input.New(t.conf.Input, iMgr, iLog, iStats); err != nil {
@@ -150,7 +159,7 @@ func (t *Type) start() (err error) {
 			return
 		}
 	}
-	if tLen := len(t.complementaryProcs) + len(t.conf.Pipeline.Processors); tLen > 0 {
+	if tLen := len(t.complementaryProcs) + len(t.conf.Pipeline.Processors) + len(t.conf.Pipeline.Lanes); tLen > 0 {
 		pMgr, pLog, pStats := interop.LabelChild("pipeline", t.manager, t.logger, t.stats)
 		if t.pipelineLayer, err = pipeline.New(t.conf.Pipeline, pMgr, pLog, pStats, t.complementaryProcs...); err != nil {
 			return
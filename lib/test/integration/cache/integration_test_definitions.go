@@ -72,6 +72,80 @@ func integrationTestDoubleAdd() testDefinition {
 	)
 }
 
+func integrationTestCompareAndSwap() testDefinition {
+	return namedTest(
+		"compare and swap only swaps matching values",
+		func(t *testing.T, env *testEnvironment) {
+			t.Parallel()
+
+			cache := initCache(t, env)
+			t.Cleanup(func() {
+				closeCache(t, cache)
+			})
+
+			casCache, ok := cache.(types.CacheWithCAS)
+			require.True(t, ok)
+
+			swapped, err := casCache.CompareAndSwap("caskey", []byte("nope"), []byte("first"))
+			require.NoError(t, err)
+			assert.False(t, swapped)
+
+			_, err = cache.Get("caskey")
+			assert.Equal(t, types.ErrKeyNotFound, err)
+
+			swapped, err = casCache.CompareAndSwap("caskey", nil, []byte("first"))
+			require.NoError(t, err)
+			assert.True(t, swapped)
+
+			swapped, err = casCache.CompareAndSwap("caskey", nil, []byte("second"))
+			require.NoError(t, err)
+			assert.False(t, swapped)
+
+			swapped, err = casCache.CompareAndSwap("caskey", []byte("nope"), []byte("second"))
+			require.NoError(t, err)
+			assert.False(t, swapped)
+
+			swapped, err = casCache.CompareAndSwap("caskey", []byte("first"), []byte("second"))
+			require.NoError(t, err)
+			assert.True(t, swapped)
+
+			res, err := cache.Get("caskey")
+			require.NoError(t, err)
+			assert.Equal(t, "second", string(res))
+		},
+	)
+}
+
+func integrationTestTouch() testDefinition {
+	return namedTest(
+		"touch resets the expiry of existing keys",
+		func(t *testing.T, env *testEnvironment) {
+			t.Parallel()
+
+			cache := initCache(t, env)
+			t.Cleanup(func() {
+				closeCache(t, cache)
+			})
+
+			touchCache, ok := cache.(types.CacheWithTouch)
+			require.True(t, ok)
+
+			assert.Equal(t, types.ErrKeyNotFound, touchCache.Touch("touchkey", nil))
+
+			ttl := time.Second * 2
+			require.NoError(t, cache.(types.CacheWithTTL).SetWithTTL("touchkey", []byte("foo"), &ttl))
+
+			<-time.After(time.Second)
+			require.NoError(t, touchCache.Touch("touchkey", &ttl))
+			<-time.After(time.Millisecond * 1500)
+
+			res, err := cache.Get("touchkey")
+			require.NoError(t, err)
+			assert.Equal(t, "foo", string(res))
+		},
+	)
+}
+
 func integrationTestDelete() testDefinition {
 	return namedTest(
 		"can set and delete keys",
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ = registerIntegrationTest("nats_kv", func(t *testing.T) {
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "nats",
+		Tag:        "latest",
+		Cmd:        []string{"-js"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		natsConn, cErr := nats.Connect(fmt.Sprintf("tcp://localhost:%v", resource.GetPort("4222/tcp")))
+		if cErr != nil {
+			return cErr
+		}
+		defer natsConn.Close()
+
+		jCtx, cErr := natsConn.JetStream()
+		if cErr != nil {
+			return cErr
+		}
+		_, cErr = jCtx.AccountInfo()
+		return cErr
+	}))
+
+	template := `
+cache_resources:
+  - label: testcache
+    nats_kv:
+      urls: [ tcp://localhost:$PORT ]
+      bucket: $ID
+      create_bucket: true
+`
+	suite := integrationTests(
+		integrationTestOpenClose(),
+		integrationTestMissingKey(),
+		integrationTestDoubleAdd(),
+		integrationTestCompareAndSwap(),
+		integrationTestDelete(),
+		integrationTestGetAndSet(50),
+	)
+	suite.Run(
+		t, template,
+		testOptPort(resource.GetPort("4222/tcp")),
+	)
+})
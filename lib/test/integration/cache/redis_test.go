@@ -52,6 +52,8 @@ cache_resources:
 		integrationTestOpenClose(),
 		integrationTestMissingKey(),
 		integrationTestDoubleAdd(),
+		integrationTestCompareAndSwap(),
+		integrationTestTouch(),
 		integrationTestDelete(),
 		integrationTestGetAndSet(50),
 	)
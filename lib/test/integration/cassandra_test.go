@@ -133,4 +133,45 @@ output:
 			}),
 		)
 	})
+
+	t.Run("with args mapping and partition batches", func(t *testing.T) {
+		template := `
+output:
+  cassandra:
+    addresses:
+      - localhost:$PORT
+    query: 'INSERT INTO testspace.table$ID (id, content, created_at) VALUES (?, ?, ?)'
+    args_mapping: 'root = [ this.id, this.content, now() ]'
+    logged_batch: true
+    batch_by_partition: true
+    token_aware: true
+`
+		queryGetFn := func(env *testEnvironment, id string) (string, []string, error) {
+			var resID int
+			var resContent string
+			if err := session.Query(
+				fmt.Sprintf("select id, content from testspace.table%v where id = ?;", env.configVars.id), id,
+			).Scan(&resID, &resContent); err != nil {
+				return "", nil, err
+			}
+			return fmt.Sprintf(`{"id":%v,"content":"%v"}`, resID, resContent), nil, err
+		}
+		suite := integrationTests(
+			integrationTestOutputOnlySendSequential(10, queryGetFn),
+			integrationTestOutputOnlySendBatch(10, queryGetFn),
+		)
+		suite.Run(
+			t, template,
+			testOptPort(resource.GetPort("9042/tcp")),
+			testOptPreTest(func(t *testing.T, env *testEnvironment) {
+				env.configVars.id = strings.ReplaceAll(env.configVars.id, "-", "")
+				require.NoError(t, session.Query(
+					fmt.Sprintf(
+						"CREATE TABLE testspace.table%v (id int primary key, content text, created_at timestamp);",
+						env.configVars.id,
+					),
+				).Exec())
+			}),
+		)
+	})
 })
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ = registerIntegrationTest("clickhouse", func(t *testing.T) {
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pool.MaxWait = time.Second * 30
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository:   "yandex/clickhouse-server",
+		ExposedPorts: []string{"9000/tcp"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pool.Purge(resource))
+	})
+
+	var db *sql.DB
+	t.Cleanup(func() {
+		if db != nil {
+			db.Close()
+		}
+	})
+
+	resource.Expire(900)
+	require.NoError(t, pool.Retry(func() error {
+		var dberr error
+		if db == nil {
+			if db, dberr = sql.Open(
+				"clickhouse",
+				fmt.Sprintf("tcp://localhost:%v", resource.GetPort("9000/tcp")),
+			); dberr != nil {
+				return dberr
+			}
+			db.SetMaxIdleConns(0)
+		}
+		if dberr = db.Ping(); dberr != nil {
+			return dberr
+		}
+		if _, dberr = db.Exec(`create table testtable (
+  id String,
+  content String,
+  count UInt32
+) engine = Memory;`); dberr != nil {
+			return dberr
+		}
+		return nil
+	}))
+
+	template := `
+output:
+  clickhouse:
+    data_source_name: tcp://localhost:$PORT
+    table: testtable
+    columns: [ id, content, count ]
+    args_mapping: '[ "$ID-"+this.id.string(), this.content, this.id ]'
+    compression: lz4
+`
+	queryGetFn := func(env *testEnvironment, id string) (string, []string, error) {
+		key := env.configVars.id + "-" + id
+
+		row := db.QueryRowContext(env.ctx, "SELECT content FROM testtable WHERE id = ?;", key)
+		if row.Err() != nil {
+			return "", nil, row.Err()
+		}
+
+		var content string
+		err := row.Scan(&content)
+		return fmt.Sprintf(`{"id":%v,"content":"%v"}`, id, content), nil, err
+	}
+	suite := integrationTests(
+		integrationTestOutputOnlySendSequential(10, queryGetFn),
+		integrationTestOutputOnlySendBatch(10, queryGetFn),
+	)
+	suite.Run(
+		t, template,
+		testOptPort(resource.GetPort("9000/tcp")),
+	)
+})
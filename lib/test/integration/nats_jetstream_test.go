@@ -43,6 +43,9 @@ output:
   nats_jetstream:
     urls: [ nats://localhost:$PORT ]
     subject: subject-$ID
+    headers:
+      foo: ${! meta("foo") }
+      bar: ${! meta("bar") }
 
 input:
   nats_jetstream:
@@ -52,7 +55,7 @@ input:
 `
 	suite := integrationTests(
 		integrationTestOpenClose(),
-		// integrationTestMetadata(), TODO
+		integrationTestMetadata(),
 		integrationTestSendBatch(10),
 		integrationTestAtLeastOnceDelivery(), // TODO: SubscribeSync doesn't seem to honor durable setting
 		integrationTestStreamParallel(1000),
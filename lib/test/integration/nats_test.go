@@ -40,6 +40,9 @@ output:
     urls: [ tcp://localhost:$PORT ]
     subject: subject-$ID
     max_in_flight: $MAX_IN_FLIGHT
+    headers:
+      foo: ${! meta("foo") }
+      bar: ${! meta("bar") }
 
 input:
   nats:
@@ -50,7 +53,7 @@ input:
 `
 	suite := integrationTests(
 		integrationTestOpenClose(),
-		// integrationTestMetadata(), TODO
+		integrationTestMetadata(),
 		integrationTestSendBatch(10),
 		integrationTestStreamParallel(500),
 		integrationTestStreamParallelLossy(500),
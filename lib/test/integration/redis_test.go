@@ -130,6 +130,31 @@ input:
 				testOptMaxInFlight(10),
 			)
 		})
+		t.Run("with patterns", func(t *testing.T) {
+			t.Parallel()
+			template := `
+output:
+  redis_pubsub:
+    url: tcp://localhost:$PORT
+    channel: channel-$ID-foo
+    max_in_flight: $MAX_IN_FLIGHT
+
+input:
+  redis_pubsub:
+    url: tcp://localhost:$PORT
+    channels: [ channel-$ID-* ]
+    use_patterns: true
+`
+			integrationTests(
+				integrationTestOpenClose(),
+				integrationTestStreamSequential(100),
+			).Run(
+				t, template,
+				testOptSleepAfterInput(500*time.Millisecond),
+				testOptSleepAfterOutput(500*time.Millisecond),
+				testOptPort(resource.GetPort("6379/tcp")),
+			)
+		})
 	})
 
 	t.Run("list", func(t *testing.T) {
@@ -32,6 +32,11 @@ Send spans to a [Jaeger](https://www.jaegertracing.io/) agent.`,
 			),
 			docs.FieldAdvanced("sampler_manager_address", "An optional address of a sampler manager."),
 			docs.FieldFloat("sampler_param", "A parameter to use for sampling. This field is unused for some sampling types.").Advanced(),
+			docs.FieldFloat(
+				"span_sample_rate",
+				"The fraction of messages, between 0 and 1, that are allocated spans. A rate of 1 allocates spans for all messages and a rate of 0 for none of them. Messages that are not sampled skip the creation of spans entirely, which reduces the overhead of tracing in high throughput pipelines. This sampling is applied before, and independently of, the sampler configured with `sampler_type`.",
+				0.1,
+			).Advanced().AtVersion("3.51.0"),
 			docs.FieldString("tags", "A map of tags to add to tracing spans.").Map().Advanced(),
 			docs.FieldCommon("flush_interval", "The period of time between each flush of tracing spans."),
 		},
@@ -48,6 +53,7 @@ type JaegerConfig struct {
 	SamplerType           string            `json:"sampler_type" yaml:"sampler_type"`
 	SamplerManagerAddress string            `json:"sampler_manager_address" yaml:"sampler_manager_address"`
 	SamplerParam          float64           `json:"sampler_param" yaml:"sampler_param"`
+	SpanSampleRate        float64           `json:"span_sample_rate" yaml:"span_sample_rate"`
 	Tags                  map[string]string `json:"tags" yaml:"tags"`
 	FlushInterval         string            `json:"flush_interval" yaml:"flush_interval"`
 }
@@ -60,6 +66,7 @@ func NewJaegerConfig() JaegerConfig {
 		SamplerType:           "const",
 		SamplerManagerAddress: "",
 		SamplerParam:          1.0,
+		SpanSampleRate:        1.0,
 		Tags:                  map[string]string{},
 		FlushInterval:         "",
 	}
@@ -140,6 +147,10 @@ func NewJaeger(config Config, opts ...func(Type)) (Type, error) {
 	if err != nil {
 		return nil, err
 	}
+	if tracer, err = newSampledTracer(tracer, config.Jaeger.SpanSampleRate); err != nil {
+		closer.Close()
+		return nil, err
+	}
 	opentracing.SetGlobalTracer(tracer)
 	j.closer = closer
 
@@ -0,0 +1,99 @@
+package tracer
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+//------------------------------------------------------------------------------
+
+// sampledTracer wraps an opentracing.Tracer and only allocates real root spans
+// for a fraction of requests. Spans that are not sampled, and all descendants
+// of them, are noops, and therefore messages that aren't sampled skip the
+// overhead of span creation entirely.
+type sampledTracer struct {
+	tracer opentracing.Tracer
+	rate   float64
+	randFn func() float64
+}
+
+// newSampledTracer wraps a tracer with a sampling rate, which must be between
+// 0 and 1, where a rate of 0 samples no spans. If the rate is 1 the tracer is
+// returned as is.
+func newSampledTracer(tracer opentracing.Tracer, rate float64) (opentracing.Tracer, error) {
+	if rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("sample rate must be between 0 and 1, got %v", rate)
+	}
+	if rate == 1 {
+		return tracer, nil
+	}
+	var mut sync.Mutex
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return &sampledTracer{
+		tracer: tracer,
+		rate:   rate,
+		randFn: func() float64 {
+			mut.Lock()
+			defer mut.Unlock()
+			return rnd.Float64()
+		},
+	}, nil
+}
+
+func (s *sampledTracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	var sso opentracing.StartSpanOptions
+	for _, o := range opts {
+		o.Apply(&sso)
+	}
+	for _, ref := range sso.References {
+		if _, unsampled := ref.ReferencedContext.(unsampledSpanContext); unsampled {
+			return &unsampledSpan{tracer: s}
+		}
+	}
+	if len(sso.References) == 0 && s.randFn() >= s.rate {
+		return &unsampledSpan{tracer: s}
+	}
+	return s.tracer.StartSpan(operationName, opts...)
+}
+
+func (s *sampledTracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	if _, unsampled := sm.(unsampledSpanContext); unsampled {
+		return nil
+	}
+	return s.tracer.Inject(sm, format, carrier)
+}
+
+func (s *sampledTracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	return s.tracer.Extract(format, carrier)
+}
+
+//------------------------------------------------------------------------------
+
+type unsampledSpanContext struct{}
+
+func (unsampledSpanContext) ForeachBaggageItem(handler func(k, v string) bool) {}
+
+// unsampledSpan is a noop span that identifies itself as not sampled so that
+// child spans are also not sampled.
+type unsampledSpan struct {
+	tracer opentracing.Tracer
+}
+
+func (u *unsampledSpan) Context() opentracing.SpanContext                       { return unsampledSpanContext{} }
+func (u *unsampledSpan) Finish()                                                {}
+func (u *unsampledSpan) FinishWithOptions(opts opentracing.FinishOptions)       {}
+func (u *unsampledSpan) SetOperationName(operationName string) opentracing.Span { return u }
+func (u *unsampledSpan) SetTag(key string, value interface{}) opentracing.Span  { return u }
+func (u *unsampledSpan) LogFields(fields ...log.Field)                          {}
+func (u *unsampledSpan) LogKV(keyVals ...interface{})                           {}
+func (u *unsampledSpan) SetBaggageItem(key, val string) opentracing.Span        { return u }
+func (u *unsampledSpan) BaggageItem(key string) string                          { return "" }
+func (u *unsampledSpan) Tracer() opentracing.Tracer                             { return u.tracer }
+func (u *unsampledSpan) LogEvent(event string)                                  {}
+func (u *unsampledSpan) LogEventWithPayload(event string, payload interface{})  {}
+func (u *unsampledSpan) Log(data opentracing.LogData)                           {}
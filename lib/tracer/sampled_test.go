@@ -0,0 +1,69 @@
+package tracer
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampledTracer(t *testing.T) {
+	mock := mocktracer.New()
+
+	tracer, err := newSampledTracer(mock, 0.5)
+	require.NoError(t, err)
+
+	draws := []float64{0.2, 0.7, 0.4, 0.9}
+	tracer.(*sampledTracer).randFn = func() float64 {
+		d := draws[0]
+		draws = draws[1:]
+		return d
+	}
+
+	for i := 0; i < 4; i++ {
+		root := tracer.StartSpan("root")
+		child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+		grandChild := tracer.StartSpan("grandchild", opentracing.FollowsFrom(child.Context()))
+		grandChild.Finish()
+		child.Finish()
+		root.Finish()
+	}
+
+	var names []string
+	for _, s := range mock.FinishedSpans() {
+		names = append(names, s.OperationName)
+	}
+	assert.Equal(t, []string{
+		"grandchild", "child", "root",
+		"grandchild", "child", "root",
+	}, names)
+}
+
+func TestSampledTracerInject(t *testing.T) {
+	mock := mocktracer.New()
+
+	tracer, err := newSampledTracer(mock, 0)
+	require.NoError(t, err)
+
+	span := tracer.StartSpan("root")
+	headers := http.Header{}
+	require.NoError(t, tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(headers)))
+	assert.Empty(t, headers)
+	span.Finish()
+
+	assert.Empty(t, mock.FinishedSpans())
+}
+
+func TestSampledTracerBadRate(t *testing.T) {
+	mock := mocktracer.New()
+
+	tracer, err := newSampledTracer(mock, 1)
+	require.NoError(t, err)
+	assert.Equal(t, mock, tracer)
+
+	_, err = newSampledTracer(mock, 1.5)
+	require.EqualError(t, err, "sample rate must be between 0 and 1, got 1.5")
+}
@@ -84,16 +84,18 @@ func (b *BatchError) Unwrap() error {
 
 // Manager errors
 var (
-	ErrInputNotFound     = errors.New("input not found")
-	ErrCacheNotFound     = errors.New("cache not found")
-	ErrConditionNotFound = errors.New("condition not found")
-	ErrProcessorNotFound = errors.New("processor not found")
-	ErrRateLimitNotFound = errors.New("rate limit not found")
-	ErrOutputNotFound    = errors.New("output not found")
-	ErrPluginNotFound    = errors.New("plugin not found")
-	ErrKeyAlreadyExists  = errors.New("key already exists")
-	ErrKeyNotFound       = errors.New("key does not exist")
-	ErrPipeNotFound      = errors.New("pipe was not found")
+	ErrInputNotFound       = errors.New("input not found")
+	ErrCacheNotFound       = errors.New("cache not found")
+	ErrConditionNotFound   = errors.New("condition not found")
+	ErrProcessorNotFound   = errors.New("processor not found")
+	ErrRateLimitNotFound   = errors.New("rate limit not found")
+	ErrOutputNotFound      = errors.New("output not found")
+	ErrCredentialsNotFound = errors.New("credentials not found")
+	ErrPluginNotFound      = errors.New("plugin not found")
+	ErrKeyAlreadyExists    = errors.New("key already exists")
+	ErrKeyNotFound         = errors.New("key does not exist")
+	ErrNotSupported        = errors.New("operation not supported")
+	ErrPipeNotFound        = errors.New("pipe was not found")
 )
 
 //------------------------------------------------------------------------------
@@ -118,3 +120,30 @@ func (e ErrUnexpectedHTTPRes) Error() string {
 }
 
 //------------------------------------------------------------------------------
+
+// ErrPermanent wraps an error that is not expected to be resolved by retrying
+// the same operation, such as a rejected request or a constraint violation.
+// Outputs return permanent errors so that retry mechanisms can give up on a
+// message immediately and leave it to error handling.
+type ErrPermanent struct {
+	Err error
+}
+
+// Error returns the Error string.
+func (e ErrPermanent) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e ErrPermanent) Unwrap() error {
+	return e.Err
+}
+
+// IsPermanent returns true if an error, or any error it wraps, is an
+// ErrPermanent.
+func IsPermanent(err error) bool {
+	var pErr ErrPermanent
+	return errors.As(err, &pErr)
+}
+
+//------------------------------------------------------------------------------
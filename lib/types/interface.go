@@ -59,6 +59,32 @@ type CacheWithTTL interface {
 	Cache
 }
 
+// CacheWithCAS is a key/value store that supports atomically swapping the
+// value of a key, enabling optimistic concurrency and coordination between
+// components or instances of Benthos that share a cache.
+type CacheWithCAS interface {
+	// CompareAndSwap attempts to set the value of a key to newValue only if its
+	// current value matches oldValue, where a nil oldValue requires that the
+	// key does not exist. Returns true if the swap occurred and false if the
+	// current value did not match. Caches that are unable to perform the
+	// operation atomically return ErrNotSupported.
+	CompareAndSwap(key string, oldValue, newValue []byte) (bool, error)
+
+	Cache
+}
+
+// CacheWithTouch is a key/value store that supports resetting the expiry of an
+// existing key, allowing items to expire only after a period without access.
+type CacheWithTouch interface {
+	// Touch resets the expiry of an existing key to the provided TTL, or to
+	// the default TTL of the cache when nil. Returns ErrKeyNotFound if the key
+	// does not exist, and caches that are unable to reset expiries return
+	// ErrNotSupported.
+	Touch(key string, ttl *time.Duration) error
+
+	Cache
+}
+
 //------------------------------------------------------------------------------
 
 // RateLimit is a strategy for limiting access to a shared resource, this
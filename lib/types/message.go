@@ -11,6 +11,27 @@ import (
 // be interpretted as having failed a processor step somewhere in the pipeline.
 var FailFlagKey = "benthos_processing_failed"
 
+// ErrorSourceLabelKey, ErrorSourcePathKey and ErrorSourceTypeKey are metadata
+// keys describing the processor that most recently flagged a message part as
+// having failed, these are set alongside FailFlagKey and removed once the
+// failure is cleared.
+var (
+	ErrorSourceLabelKey = "benthos_error_source_label"
+	ErrorSourcePathKey  = "benthos_error_source_path"
+	ErrorSourceTypeKey  = "benthos_error_source_type"
+)
+
+// ErrorSourceMetadataKey is a metadata key containing a JSON object of the
+// metadata (excluding internal benthos_ keys) that a message part carried at
+// the point that it was flagged as having failed, it is set alongside the other
+// error source keys and removed once the failure is cleared.
+var ErrorSourceMetadataKey = "benthos_error_source_metadata"
+
+// ErrorStackKey is a metadata key containing the stack trace of a panic that
+// was recovered whilst processing a message part, it is set alongside
+// FailFlagKey and removed once the failure is cleared.
+var ErrorStackKey = "benthos_error_stack"
+
 //------------------------------------------------------------------------------
 
 // Metadata is an interface representing the metadata of a message part within
@@ -9,7 +9,8 @@ import (
 // FieldSpecs returns a map of field specs for an HTTP type.
 func FieldSpecs() docs.FieldSpecs {
 	httpSpecs := docs.FieldSpecs{
-		docs.FieldCommon("url", "The URL to connect to.").HasType("string").IsInterpolated(),
+		docs.FieldCommon("url", "The URL to connect to. Interpolations are resolved per message, and when any of them fail or resolve to null the request fails unless `url_fallback` is set.").HasType("string").IsInterpolated(),
+		docs.FieldString("url_fallback", "An optional URL to use when the interpolations of `url` fail or resolve to null, such as when a referenced metadata field is missing.", "http://localhost:4195/dead_letters").IsInterpolated().Advanced().AtVersion("3.51.0"),
 		docs.FieldCommon("verb", "A verb to connect with", "POST", "GET", "DELETE").HasType("string"),
 		docs.FieldString("headers", "A map of headers to add to the request.", map[string]interface{}{
 			"Content-Type": "application/octet-stream",
@@ -26,7 +27,8 @@ func FieldSpecs() docs.FieldSpecs {
 		docs.FieldString("max_retry_backoff", "The maximum period to wait between failed requests.").Advanced(),
 		docs.FieldInt("retries", "The maximum number of retry attempts to make.").Advanced(),
 		docs.FieldInt("backoff_on", "A list of status codes whereby the request should be considered to have failed and retries should be attempted, but the period between them should be increased gradually.").Array().Advanced(),
-		docs.FieldInt("drop_on", "A list of status codes whereby the request should be considered to have failed but retries should not be attempted. This is useful for preventing wasted retries for requests that will never succeed. Note that with these status codes the _request_ is dropped, but _message_ that caused the request will not be dropped.").Array().Advanced(),
+		docs.FieldInt("drop_on", "A list of status codes whereby the request should be considered to have failed but retries should not be attempted. This is useful for preventing wasted retries for requests that will never succeed. Note that with these status codes the _request_ is dropped, but _message_ that caused the request will not be dropped, and the error is reported as permanent.").Array().Advanced(),
+		docs.FieldInt("permanent_on", "A list of status codes whereby the request should be considered to have failed permanently, where retries should not be attempted and the error is reported as permanent. Permanent errors are not retried by the [`retry`](/docs/components/outputs/retry) output. Codes within `backoff_on` or `successful_on` take precedence over this field.").Array().Advanced().AtVersion("3.51.0"),
 		docs.FieldInt("successful_on", "A list of status codes whereby the attempt should be considered successful, this is useful for dropping requests that return non-2XX codes indicating that the message has been dealt with, such as a 303 See Other or a 409 Conflict. All 2XX codes are considered successful unless they are present within `backoff_on` or `drop_on`, regardless of this field.").Array().Advanced(),
 		docs.FieldString("proxy_url", "An optional HTTP proxy URL.").Advanced(),
 	)
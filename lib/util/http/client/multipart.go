@@ -0,0 +1,182 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// MultipartField describes a part of a multipart/form-data request body. Each
+// expression is resolved per message, and a nil Content expression results in
+// the raw contents of the message being used.
+type MultipartField struct {
+	Name        *field.Expression
+	Filename    *field.Expression
+	ContentType *field.Expression
+	Content     *field.Expression
+}
+
+// OptSetMultipartFields sets a list of fields from which requests are created
+// as multipart/form-data bodies, overriding the default request body
+// behaviour. For each message of a batch every field is added to the body.
+func OptSetMultipartFields(fields []MultipartField) func(*Type) {
+	return func(t *Type) {
+		t.multipartFields = fields
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type formPart struct {
+	header  textproto.MIMEHeader
+	content []byte
+}
+
+var formQuoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func (h *Type) resolveFormParts(msg types.Message) ([]formPart, error) {
+	parts := make([]formPart, 0, msg.Len()*len(h.multipartFields))
+	for i := 0; i < msg.Len(); i++ {
+		for _, f := range h.multipartFields {
+			name := f.Name.String(i, msg)
+			if name == "" {
+				return nil, errors.New("multipart field name resolved to an empty string")
+			}
+			disposition := fmt.Sprintf(`form-data; name="%s"`, formQuoteEscaper.Replace(name))
+
+			var contentType string
+			if f.ContentType != nil {
+				contentType = f.ContentType.String(i, msg)
+			}
+			if f.Filename != nil {
+				if filename := f.Filename.String(i, msg); filename != "" {
+					disposition += fmt.Sprintf(`; filename="%s"`, formQuoteEscaper.Replace(filename))
+					if contentType == "" {
+						contentType = "application/octet-stream"
+					}
+				}
+			}
+
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", disposition)
+			if contentType != "" {
+				header.Set("Content-Type", contentType)
+			}
+
+			var content []byte
+			if f.Content != nil {
+				content = f.Content.Bytes(i, msg)
+			} else {
+				content = msg.Get(i).Get()
+			}
+			parts = append(parts, formPart{header: header, content: content})
+		}
+	}
+	return parts, nil
+}
+
+func writeFormParts(w io.Writer, boundary string, parts []formPart) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(p.header)
+		if err != nil {
+			return err
+		}
+		if _, err = pw.Write(p.content); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+type countingWriter int64
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	*c += countingWriter(len(p))
+	return len(p), nil
+}
+
+// formBody streams a multipart/form-data body to the reader as it is consumed
+// rather than buffering it. The writing goroutine is only started by the first
+// read, and therefore requests that are never sent do not leak it.
+type formBody struct {
+	boundary string
+	parts    []formPart
+
+	once sync.Once
+	pr   *io.PipeReader
+}
+
+func (f *formBody) start() {
+	var pw *io.PipeWriter
+	f.pr, pw = io.Pipe()
+	go func() {
+		pw.CloseWithError(writeFormParts(pw, f.boundary, f.parts))
+	}()
+}
+
+func (f *formBody) Read(p []byte) (int, error) {
+	f.once.Do(f.start)
+	if f.pr == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return f.pr.Read(p)
+}
+
+func (f *formBody) Close() error {
+	f.once.Do(func() {})
+	if f.pr == nil {
+		return nil
+	}
+	return f.pr.Close()
+}
+
+// createFormRequest creates a request with a multipart/form-data body built
+// from the configured multipart fields.
+func (h *Type) createFormRequest(url string, msg types.Message) (*http.Request, error) {
+	parts, err := h.resolveFormParts(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := multipart.NewWriter(nil).Boundary()
+
+	// The length of the body is calculated up front so that servers which do
+	// not support chunked uploads still accept the request.
+	var length countingWriter
+	if err := writeFormParts(&length, boundary, parts); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(h.conf.Verb, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = &formBody{boundary: boundary, parts: parts}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return &formBody{boundary: boundary, parts: parts}, nil
+	}
+	req.ContentLength = int64(length)
+
+	for k, v := range h.headers {
+		req.Header.Add(k, v.String(0, msg))
+	}
+	if h.host != nil {
+		req.Host = h.host.String(0, msg)
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+	return req, nil
+}
@@ -35,6 +35,7 @@ import (
 // Config is a configuration struct for an HTTP client.
 type Config struct {
 	URL                 string            `json:"url" yaml:"url"`
+	URLFallback         string            `json:"url_fallback" yaml:"url_fallback"`
 	Verb                string            `json:"verb" yaml:"verb"`
 	Headers             map[string]string `json:"headers" yaml:"headers"`
 	CopyResponseHeaders bool              `json:"copy_response_headers" yaml:"copy_response_headers"`
@@ -45,6 +46,7 @@ type Config struct {
 	NumRetries          int               `json:"retries" yaml:"retries"`
 	BackoffOn           []int             `json:"backoff_on" yaml:"backoff_on"`
 	DropOn              []int             `json:"drop_on" yaml:"drop_on"`
+	PermanentOn         []int             `json:"permanent_on" yaml:"permanent_on"`
 	SuccessfulOn        []int             `json:"successful_on" yaml:"successful_on"`
 	TLS                 tls.Config        `json:"tls" yaml:"tls"`
 	ProxyURL            string            `json:"proxy_url" yaml:"proxy_url"`
@@ -55,8 +57,9 @@ type Config struct {
 // NewConfig creates a new Config with default values.
 func NewConfig() Config {
 	return Config{
-		URL:  "http://localhost:4195/post",
-		Verb: "POST",
+		URL:         "http://localhost:4195/post",
+		URLFallback: "",
+		Verb:        "POST",
 		Headers: map[string]string{
 			"Content-Type": "application/octet-stream",
 		},
@@ -68,6 +71,7 @@ func NewConfig() Config {
 		NumRetries:          3,
 		BackoffOn:           []int{429},
 		DropOn:              []int{},
+		PermanentOn:         []int{},
 		SuccessfulOn:        []int{},
 		TLS:                 tls.NewConfig(),
 		Config:              auth.NewConfig(),
@@ -83,11 +87,15 @@ type Type struct {
 
 	backoffOn map[int]struct{}
 	dropOn    map[int]struct{}
+	permOn    map[int]struct{}
 	successOn map[int]struct{}
 
-	url     *field.Expression
-	headers map[string]*field.Expression
-	host    *field.Expression
+	url         *field.Expression
+	urlFallback *field.Expression
+	headers     map[string]*field.Expression
+	host        *field.Expression
+
+	multipartFields []MultipartField
 
 	conf          Config
 	retryThrottle *throttle.Type
@@ -130,10 +138,16 @@ func New(conf Config, opts ...func(*Type)) (*Type, error) {
 		mgr:       types.NoopMgr(),
 		backoffOn: map[int]struct{}{},
 		dropOn:    map[int]struct{}{},
+		permOn:    map[int]struct{}{},
 		successOn: map[int]struct{}{},
 		headers:   map[string]*field.Expression{},
 		host:      nil,
 	}
+	if conf.URLFallback != "" {
+		if h.urlFallback, err = bloblang.NewField(conf.URLFallback); err != nil {
+			return nil, fmt.Errorf("failed to parse URL fallback expression: %v", err)
+		}
+	}
 	h.ctx, h.done = context.WithCancel(context.Background())
 	h.client = conf.OAuth2.Client(h.ctx)
 
@@ -186,6 +200,9 @@ func New(conf Config, opts ...func(*Type)) (*Type, error) {
 	for _, c := range conf.DropOn {
 		h.dropOn[c] = struct{}{}
 	}
+	for _, c := range conf.PermanentOn {
+		h.permOn[c] = struct{}{}
+	}
 	for _, c := range conf.SuccessfulOn {
 		h.successOn[c] = struct{}{}
 	}
@@ -351,9 +368,40 @@ func (h *Type) waitForAccess() bool {
 	}
 }
 
+// resolveURL resolves the URL of a request for a message, falling back to the
+// url_fallback field when the interpolations of the URL fail or resolve to
+// null.
+func (h *Type) resolveURL(msg types.Message) (string, error) {
+	if h.url.NumDynamicExpressions() == 0 {
+		return h.url.String(0, msg), nil
+	}
+	if msg == nil {
+		msg = message.New(nil)
+	}
+	url, err := h.url.TryString(0, msg)
+	if err == nil {
+		return url, nil
+	}
+	if h.urlFallback != nil {
+		h.log.Debugf("Using URL fallback as the URL could not be resolved: %v\n", err)
+		return h.urlFallback.String(0, msg), nil
+	}
+	return "", fmt.Errorf("failed to resolve URL: %w", err)
+}
+
 // CreateRequest creates an HTTP request out of a single message.
 func (h *Type) CreateRequest(msg types.Message) (req *http.Request, err error) {
-	url := h.url.String(0, msg)
+	var url string
+	if url, err = h.resolveURL(msg); err != nil {
+		return nil, err
+	}
+
+	if len(h.multipartFields) > 0 && msg != nil && msg.Len() > 0 {
+		if req, err = h.createFormRequest(url, msg); err == nil {
+			err = h.conf.Config.Sign(req)
+		}
+		return
+	}
 
 	if msg == nil || msg.Len() == 0 {
 		if req, err = http.NewRequest(h.conf.Verb, url, nil); err == nil {
@@ -523,6 +571,9 @@ func (h *Type) checkStatus(code int) (succeeded bool, retStrat retryStrategy) {
 	if _, exists := h.successOn[code]; exists {
 		return true, noRetry
 	}
+	if _, exists := h.permOn[code]; exists {
+		return false, noRetry
+	}
 	if code < 200 || code > 299 {
 		return false, retryLinear
 	}
@@ -588,10 +639,11 @@ func (h *Type) DoWithContext(ctx context.Context, msg types.Message) (res *http.
 		h.incrCode(res.StatusCode)
 		if resolved, retryStrat := h.checkStatus(res.StatusCode); !resolved {
 			rateLimited = retryStrat == retryBackoff
+			err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 			if retryStrat == noRetry {
 				numRetries = 0
+				err = types.ErrPermanent{Err: err}
 			}
-			err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 			if res.Body != nil {
 				res.Body.Close()
 			}
@@ -630,10 +682,11 @@ func (h *Type) DoWithContext(ctx context.Context, msg types.Message) (res *http.
 			h.incrCode(res.StatusCode)
 			if resolved, retryStrat := h.checkStatus(res.StatusCode); !resolved {
 				rateLimited = retryStrat == retryBackoff
+				err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 				if retryStrat == noRetry {
 					j = 0
+					err = types.ErrPermanent{Err: err}
 				}
-				err = types.ErrUnexpectedHTTPRes{Code: res.StatusCode, S: res.Status}
 				if res.Body != nil {
 					res.Body.Close()
 				}
@@ -143,6 +143,50 @@ func TestHTTPClientDropOn(t *testing.T) {
 	}
 }
 
+func TestHTTPClientPermanentOn(t *testing.T) {
+	var reqCount int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqCount, 1)
+		if r.URL.Path == "/permanent" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	conf := NewConfig()
+	conf.URL = ts.URL + "/permanent"
+	conf.PermanentOn = []int{404}
+	conf.Retry = "1ms"
+	conf.NumRetries = 3
+
+	h, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = h.Send(message.New([][]byte{[]byte("foo")})); !types.IsPermanent(err) {
+		t.Errorf("Expected permanent error, received: %v", err)
+	}
+	if exp, act := int32(1), atomic.LoadInt32(&reqCount); exp != act {
+		t.Errorf("Wrong count of requests: %v != %v", act, exp)
+	}
+
+	atomic.StoreInt32(&reqCount, 0)
+	conf.URL = ts.URL + "/transient"
+	if h, err = New(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = h.Send(message.New([][]byte{[]byte("foo")})); err == nil || types.IsPermanent(err) {
+		t.Errorf("Expected non-permanent error, received: %v", err)
+	}
+	if exp, act := int32(4), atomic.LoadInt32(&reqCount); exp != act {
+		t.Errorf("Wrong count of requests: %v != %v", act, exp)
+	}
+}
+
 func TestHTTPClientSuccessfulOn(t *testing.T) {
 	var reqs int32
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -251,6 +295,56 @@ func TestHTTPClientSendInterpolate(t *testing.T) {
 	}
 }
 
+func TestHTTPClientSendURLFallback(t *testing.T) {
+	pathChan := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pathChan <- r.URL.Path
+	}))
+	defer ts.Close()
+
+	conf := NewConfig()
+	conf.URL = ts.URL + `/${! meta("target") }`
+
+	h, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Send(message.New([][]byte{[]byte("test")})); err == nil {
+		t.Error("Expected error from missing metadata")
+	}
+
+	conf.URLFallback = ts.URL + "/fallback"
+	if h, err = New(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		target string
+		path   string
+	}{
+		{target: "foo", path: "/foo"},
+		{target: "", path: "/fallback"},
+		{target: "bar", path: "/bar"},
+	} {
+		msg := message.New([][]byte{[]byte("test")})
+		if test.target != "" {
+			msg.Get(0).Metadata().Set("target", test.target)
+		}
+		if _, err := h.Send(msg); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case path := <-pathChan:
+			if path != test.path {
+				t.Errorf("Wrong path: %v != %v", path, test.path)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Action timed out")
+		}
+	}
+}
+
 func TestHTTPClientSendMultipart(t *testing.T) {
 	nTestLoops := 1000
 
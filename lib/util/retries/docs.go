@@ -10,6 +10,7 @@ func FieldSpecs() docs.FieldSpecs {
 			docs.FieldAdvanced("initial_interval", "The initial period to wait between retry attempts."),
 			docs.FieldAdvanced("max_interval", "The maximum period to wait between retry attempts."),
 			docs.FieldAdvanced("max_elapsed_time", "The maximum period to wait before retry attempts are abandoned. If zero then no limit is used."),
+			docs.FieldAdvanced("jitter", "A jitter strategy to apply to each period between retry attempts, which prevents many clients from retrying in lockstep. The `full` strategy waits a random period between zero and the calculated interval, and the `equal` strategy waits half of the calculated interval plus a random period up to the other half.").HasOptions("none", "full", "equal").AtVersion("3.51.0"),
 		),
 	}
 }
@@ -2,6 +2,7 @@ package retries
 
 import (
 	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -15,6 +16,7 @@ type Backoff struct {
 	InitialInterval string `json:"initial_interval" yaml:"initial_interval"`
 	MaxInterval     string `json:"max_interval" yaml:"max_interval"`
 	MaxElapsedTime  string `json:"max_elapsed_time" yaml:"max_elapsed_time"`
+	Jitter          string `json:"jitter" yaml:"jitter"`
 }
 
 // Config contains configuration params for a retries mechanism.
@@ -31,6 +33,7 @@ func NewConfig() Config {
 			InitialInterval: "500ms",
 			MaxInterval:     "3s",
 			MaxElapsedTime:  "0s",
+			Jitter:          "none",
 		},
 	}
 }
@@ -68,6 +71,17 @@ func (c *Config) GetCtor() (func() backoff.BackOff, error) {
 		}
 	}
 
+	var jitterFn func(time.Duration) time.Duration
+	switch c.Backoff.Jitter {
+	case "", "none":
+	case "full":
+		jitterFn = fullJitter
+	case "equal":
+		jitterFn = equalJitter
+	default:
+		return nil, fmt.Errorf("invalid backoff jitter: %v", c.Backoff.Jitter)
+	}
+
 	return func() backoff.BackOff {
 		boff := backoff.NewExponentialBackOff()
 
@@ -75,11 +89,51 @@ func (c *Config) GetCtor() (func() backoff.BackOff, error) {
 		boff.MaxInterval = maxInterval
 		boff.MaxElapsedTime = maxElapsed
 
+		var b backoff.BackOff = boff
+		if jitterFn != nil {
+			// The jitter replaces the randomisation of the exponential backoff
+			// so that it is applied to the raw exponential interval.
+			boff.RandomizationFactor = 0
+			b = &jitterBackOff{
+				BackOff: boff,
+				jitter:  jitterFn,
+			}
+		}
+
 		if c.MaxRetries > 0 {
-			return backoff.WithMaxRetries(boff, c.MaxRetries)
+			return backoff.WithMaxRetries(b, c.MaxRetries)
 		}
-		return boff
+		return b
 	}, nil
 }
 
 //------------------------------------------------------------------------------
+
+// fullJitter returns a random duration between zero and d.
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// equalJitter returns a random duration between half of d and d.
+func equalJitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + fullJitter(d-half)
+}
+
+type jitterBackOff struct {
+	backoff.BackOff
+	jitter func(time.Duration) time.Duration
+}
+
+func (j *jitterBackOff) NextBackOff() time.Duration {
+	next := j.BackOff.NextBackOff()
+	if next == backoff.Stop {
+		return next
+	}
+	return j.jitter(next)
+}
+
+//------------------------------------------------------------------------------
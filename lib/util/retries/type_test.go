@@ -0,0 +1,74 @@
+package retries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffJitter(t *testing.T) {
+	tests := map[string]struct {
+		jitter string
+		min    func(time.Duration) time.Duration
+	}{
+		"full": {
+			jitter: "full",
+			min:    func(time.Duration) time.Duration { return 0 },
+		},
+		"equal": {
+			jitter: "equal",
+			min:    func(d time.Duration) time.Duration { return d / 2 },
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			conf := NewConfig()
+			conf.MaxRetries = 5
+			conf.Backoff.InitialInterval = "100ms"
+			conf.Backoff.MaxInterval = "1s"
+			conf.Backoff.Jitter = test.jitter
+
+			boff, err := conf.Get()
+			require.NoError(t, err)
+			boff.Reset()
+
+			expected := []time.Duration{
+				100 * time.Millisecond,
+				150 * time.Millisecond,
+				225 * time.Millisecond,
+				337500 * time.Microsecond,
+				506250 * time.Microsecond,
+			}
+			for i, exp := range expected {
+				next := boff.NextBackOff()
+				assert.GreaterOrEqual(t, int64(next), int64(test.min(exp)), i)
+				assert.LessOrEqual(t, int64(next), int64(exp), i)
+			}
+			assert.Equal(t, backoff.Stop, boff.NextBackOff())
+		})
+	}
+}
+
+func TestBackoffJitterNone(t *testing.T) {
+	conf := NewConfig()
+	conf.Backoff.InitialInterval = "100ms"
+
+	boff, err := conf.Get()
+	require.NoError(t, err)
+
+	_, isExp := boff.(*backoff.ExponentialBackOff)
+	assert.True(t, isExp)
+}
+
+func TestBackoffJitterBad(t *testing.T) {
+	conf := NewConfig()
+	conf.Backoff.Jitter = "nope"
+
+	_, err := conf.Get()
+	require.EqualError(t, err, "invalid backoff jitter: nope")
+}
@@ -19,6 +19,10 @@ func FieldSpec() docs.FieldSpec {
 			"enable_renegotiation", "Whether to allow the remote server to repeatedly request renegotiation. Enable this option if you're seeing the error message `local error: tls: no renegotiation`.",
 		).AtVersion("3.45.0").HasType(docs.FieldTypeBool).HasDefault(false),
 
+		docs.FieldAdvanced(
+			"server_name", "An optional server name to use for SNI and server certificate verification, overriding the hostname of the connection. This is useful when connecting via an IP address or a proxy whilst presenting a specific server name.", "example.com",
+		).AtVersion("3.51.0").HasDefault(""),
+
 		docs.FieldString(
 			"root_cas_file", "An optional path of a root certificate authority file to use. This is a file, often with a .pem extension, containing a certificate chain from the parent trusted root certificate, to possible intermediate signing certificates, to the host certificate.", "./root_cas.pem",
 		).HasDefault(""),
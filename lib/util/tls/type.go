@@ -45,6 +45,7 @@ type Config struct {
 	InsecureSkipVerify  bool               `json:"skip_cert_verify" yaml:"skip_cert_verify"`
 	ClientCertificates  []ClientCertConfig `json:"client_certs" yaml:"client_certs"`
 	EnableRenegotiation bool               `json:"enable_renegotiation" yaml:"enable_renegotiation"`
+	ServerName          string             `json:"server_name" yaml:"server_name"`
 }
 
 // NewConfig creates a new Config with default values.
@@ -55,6 +56,7 @@ func NewConfig() Config {
 		InsecureSkipVerify:  false,
 		ClientCertificates:  []ClientCertConfig{},
 		EnableRenegotiation: false,
+		ServerName:          "",
 	}
 }
 
@@ -102,6 +104,11 @@ func (c *Config) Get() (*tls.Config, error) {
 		tlsConf.InsecureSkipVerify = true
 	}
 
+	if c.ServerName != "" {
+		initConf()
+		tlsConf.ServerName = c.ServerName
+	}
+
 	return tlsConf, nil
 }
 
@@ -0,0 +1,28 @@
+package tls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigServerName(t *testing.T) {
+	conf := NewConfig()
+
+	tlsConf, err := conf.Get()
+	require.NoError(t, err)
+	assert.Nil(t, tlsConf)
+
+	conf.ServerName = "example.com"
+	tlsConf, err = conf.Get()
+	require.NoError(t, err)
+	require.NotNil(t, tlsConf)
+	assert.Equal(t, "example.com", tlsConf.ServerName)
+
+	conf.InsecureSkipVerify = true
+	tlsConf, err = conf.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", tlsConf.ServerName)
+	assert.True(t, tlsConf.InsecureSkipVerify)
+}
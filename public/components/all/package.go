@@ -9,9 +9,12 @@ import (
 
 	// Import new service packages.
 	_ "github.com/Jeffail/benthos/v3/internal/impl/aws"
+	_ "github.com/Jeffail/benthos/v3/internal/impl/clickhouse"
 	_ "github.com/Jeffail/benthos/v3/internal/impl/confluent"
 	_ "github.com/Jeffail/benthos/v3/internal/impl/gcp"
+	_ "github.com/Jeffail/benthos/v3/internal/impl/graphql"
 	_ "github.com/Jeffail/benthos/v3/internal/impl/mongodb"
 	_ "github.com/Jeffail/benthos/v3/internal/impl/nats"
 	_ "github.com/Jeffail/benthos/v3/internal/impl/pulsar"
+	_ "github.com/Jeffail/benthos/v3/internal/impl/snowflake"
 )
@@ -11,10 +11,11 @@ import (
 // messages destined for a Batch output. This is returned by constructors of
 // batch outputs.
 type BatchPolicy struct {
-	ByteSize int
-	Count    int
-	Check    string
-	Period   string
+	ByteSize   int
+	Count      int
+	Check      string
+	Period     string
+	IdlePeriod string
 
 	// Only available when using NewBatchPolicyField.
 	procs []processor.Config
@@ -26,6 +27,7 @@ func (b BatchPolicy) toInternal() batch.PolicyConfig {
 	batchConf.Count = b.Count
 	batchConf.Check = b.Check
 	batchConf.Period = b.Period
+	batchConf.IdlePeriod = b.IdlePeriod
 	batchConf.Processors = b.procs
 	return batchConf
 }
@@ -67,6 +69,9 @@ func (p *ParsedConfig) FieldBatchPolicy(path ...string) (conf BatchPolicy, err e
 	if conf.Period, err = p.FieldString(append(path, "period")...); err != nil {
 		return conf, err
 	}
+	if conf.IdlePeriod, err = p.FieldString(append(path, "idle_period")...); err != nil {
+		return conf, err
+	}
 
 	procsNode, exists := p.field(append(path, "processors")...)
 	if !exists {
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigMigration describes a change applied to a component config in order to
+// remove usage of a deprecated field.
+type ConfigMigration struct {
+	// Path is the dot separated path of the deprecated field.
+	Path string
+
+	// NewPath is the dot separated path of the field the value was moved to,
+	// which is empty when the field was removed without a replacement.
+	NewPath string
+
+	// OldValue is the value of the deprecated field.
+	OldValue interface{}
+
+	// Reason describes why the migration was applied.
+	Reason string
+}
+
+// MigrateComponentYAML attempts to remove the usage of deprecated fields from a
+// YAML component config of a given type (input, output, processor, etc),
+// including within nested components. The values of deprecated fields that have
+// a documented replacement are moved to that replacement, and other deprecated
+// fields are removed. Returns the migrated config as YAML along with a list of
+// the migrations that were applied.
+func MigrateComponentYAML(componentType, conf string) (string, []ConfigMigration, error) {
+	cType := docs.Type(componentType)
+	var known bool
+	for _, t := range docs.Types() {
+		if t == cType {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return "", nil, fmt.Errorf("component type %v not recognised", componentType)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(conf), &raw); err != nil {
+		return "", nil, err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	migrations, err := docs.MigrateComponentConfig(cType, raw)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resBytes, err := yaml.Marshal(raw)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var res []ConfigMigration
+	for _, m := range migrations {
+		res = append(res, ConfigMigration{
+			Path:     m.Path,
+			NewPath:  m.NewPath,
+			OldValue: m.OldValue,
+			Reason:   m.Reason,
+		})
+	}
+	return string(resBytes), res, nil
+}
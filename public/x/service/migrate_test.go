@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateComponentYAML(t *testing.T) {
+	res, migrations, err := MigrateComponentYAML("processor", `
+cache:
+  cache: foo
+  operator: get
+  key: bar
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ConfigMigration{
+		{
+			Path:     "cache.cache",
+			NewPath:  "cache.resource",
+			OldValue: "foo",
+			Reason:   "field cache is deprecated and was renamed to resource",
+		},
+	}, migrations)
+	assert.Equal(t, `cache:
+    key: bar
+    operator: get
+    resource: foo
+`, res)
+
+	_, _, err = MigrateComponentYAML("nope", `cache: {}`)
+	require.Error(t, err)
+}
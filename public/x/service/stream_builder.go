@@ -19,6 +19,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/pipeline"
 	"github.com/Jeffail/benthos/v3/lib/processor"
 	"github.com/Jeffail/benthos/v3/lib/ratelimit"
 	"github.com/Jeffail/benthos/v3/lib/response"
@@ -43,6 +44,8 @@ type StreamBuilder struct {
 	inputs     []input.Config
 	buffer     buffer.Config
 	processors []processor.Config
+	lanes      []pipeline.LaneConfig
+	initProcs  []processor.Config
 	outputs    []output.Config
 	resources  manager.ResourceConfig
 	metrics    metrics.Config
@@ -352,6 +355,8 @@ func (s *StreamBuilder) SetYAML(conf string) error {
 	s.buffer = sconf.Buffer
 	s.processors = sconf.Pipeline.Processors
 	s.threads = sconf.Pipeline.Threads
+	s.lanes = sconf.Pipeline.Lanes
+	s.initProcs = sconf.Pipeline.Init
 	s.outputs = []output.Config{sconf.Output}
 	s.resources = sconf.ResourceConfig
 	s.logger = sconf.Logger
@@ -543,6 +548,8 @@ func (s *StreamBuilder) buildConfig() builderConfig {
 
 	conf.Pipeline.Threads = s.threads
 	conf.Pipeline.Processors = s.processors
+	conf.Pipeline.Lanes = s.lanes
+	conf.Pipeline.Init = s.initProcs
 
 	if len(s.outputs) == 1 {
 		conf.Output = s.outputs[0]
@@ -299,6 +299,7 @@ func TestStreamBuilderSetYAMLBrokers(t *testing.T) {
     broker:
         copies: 1
         pattern: fan_out
+        required_acks: 0
         max_in_flight: 1
         outputs:`,
 		`            - label: ""